@@ -0,0 +1,26 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package api holds the API definitions of Shepherd: the CRDs in v1alpha1,
+// the REST API in openapi.yaml and the gRPC API in proto.
+package api
+
+import _ "embed"
+
+// OpenAPISpec is the OpenAPI spec of the REST API, in YAML.
+//
+//go:embed openapi.yaml
+var OpenAPISpec []byte
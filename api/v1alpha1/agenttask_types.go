@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,9 +26,12 @@ import (
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Succeeded")].reason`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Succeeded")].reason`,priority=1
 // +kubebuilder:printcolumn:name="PR",type=string,JSONPath=`.status.result.prURL`,priority=1
 // +kubebuilder:printcolumn:name="Claim",type=string,JSONPath=`.status.sandboxClaimName`
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`,priority=1
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`,priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // AgentTask is the Schema for the agenttasks API.
@@ -47,6 +52,36 @@ type AgentTaskSpec struct {
 	Callback CallbackSpec `json:"callback"`
 	// +optional
 	Runner RunnerSpec `json:"runner,omitzero"`
+
+	// Priority orders tasks waiting for a sandbox when the operator's
+	// concurrency limit is reached. Higher values are admitted first; tasks
+	// with equal priority are admitted in creation order.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// DependsOn lists AgentTasks in the same namespace that must reach
+	// Succeeded before this task gets a sandbox. If a dependency fails,
+	// times out or is cancelled, this task fails without running.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="dependsOn is immutable"
+	// +kubebuilder:validation:MaxItems=16
+	// +listType=set
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Suspend holds the task before it runs: no sandbox is claimed, and a
+	// claimed sandbox the runner has not started on yet is released.
+	// Setting it back to false resumes the task. Tasks that are already
+	// running are not affected.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// TTLAfterFinished deletes the task this long after it succeeded or
+	// failed; zero deletes it as soon as it finished. When unset, the
+	// operator's --ttl-after-finished default applies.
+	// +optional
+	TTLAfterFinished *metav1.Duration `json:"ttlAfterFinished,omitempty"`
 }
 
 type RepoSpec struct {
@@ -72,8 +107,9 @@ type TaskSpec struct {
 	// SourceURL is the origin of the task (e.g., GitHub issue URL). Informational only.
 	SourceURL string `json:"sourceURL,omitempty"`
 
-	// SourceType identifies the trigger type: "issue", "pr", or "fleet".
-	// +kubebuilder:validation:Enum="";issue;pr;fleet
+	// SourceType identifies the trigger type: "issue", "pr", "fleet", or
+	// "verification" for post-merge follow-up tasks.
+	// +kubebuilder:validation:Enum="";issue;pr;fleet;verification
 	SourceType string `json:"sourceType,omitempty"`
 
 	// SourceID identifies the specific trigger instance (e.g., issue number).
@@ -84,26 +120,78 @@ type CallbackSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Pattern=`^https?://`
 	URL string `json:"url"`
+
+	// Format is the format callbacks are sent in: json for Shepherd's own
+	// CallbackPayload, or cloudevents for CloudEvents 1.0 structured JSON.
+	// Empty uses the API server's --callback-format.
+	// +kubebuilder:validation:Enum="";json;cloudevents
+	// +optional
+	Format string `json:"format,omitempty"`
 }
 
+// Callback formats (CallbackSpec.Format).
+const (
+	CallbackFormatJSON        = "json"
+	CallbackFormatCloudEvents = "cloudevents"
+)
+
 type RunnerSpec struct {
 	// SandboxTemplateName references a SandboxTemplate for the runner environment.
 	// +kubebuilder:validation:Required
 	SandboxTemplateName string `json:"sandboxTemplateName"`
 
-	// Timeout is the maximum duration for task execution.
-	// +kubebuilder:default="30m"
+	// Timeout is the maximum duration for task execution. When unset, the
+	// defaulting webhook fills in the namespace's default, and the operator
+	// falls back to DefaultRunnerTimeout.
 	// +optional
 	Timeout metav1.Duration `json:"timeout,omitzero"`
 
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
+	// Model selects the model the runner's agent works with, instead of the
+	// runner image's default.
+	// +kubebuilder:validation:MaxLength=100
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9_.:@/-]*$`
+	// +optional
+	Model string `json:"model,omitempty"`
+
+	// BranchPrefix is prepended to the task's name to name the branch the
+	// runner pushes its changes to; when unset, DefaultBranchPrefix.
+	// +kubebuilder:validation:MaxLength=100
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9_./-]*$`
+	// +optional
+	BranchPrefix string `json:"branchPrefix,omitempty"`
+
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitzero"`
 }
 
+// TaskPhase is a coarse summary of where a task is in its lifecycle. The
+// Succeeded condition's reason has the details, such as why a pending task
+// is waiting.
+// +kubebuilder:validation:Enum=Pending;Provisioning;Running;Succeeded;Failed;TimedOut;Cancelled
+type TaskPhase string
+
+const (
+	// PhasePending covers tasks that have no sandbox yet, including tasks
+	// that are queued, suspended or waiting for a dependency.
+	PhasePending TaskPhase = "Pending"
+	// PhaseProvisioning is a task whose sandbox is claimed but not yet
+	// running the task.
+	PhaseProvisioning TaskPhase = "Provisioning"
+	PhaseRunning      TaskPhase = "Running"
+	PhaseSucceeded    TaskPhase = "Succeeded"
+	PhaseFailed       TaskPhase = "Failed"
+	PhaseTimedOut     TaskPhase = "TimedOut"
+	PhaseCancelled    TaskPhase = "Cancelled"
+)
+
 type AgentTaskStatus struct {
+	// Phase is computed from the Succeeded condition and the sandbox claim
+	// by the operator, for display and simple filtering.
+	// +optional
+	Phase              TaskPhase    `json:"phase,omitempty"`
 	ObservedGeneration int64        `json:"observedGeneration,omitempty"`
 	StartTime          *metav1.Time `json:"startTime,omitempty"`
 	CompletionTime     *metav1.Time `json:"completionTime,omitempty"`
@@ -111,6 +199,16 @@ type AgentTaskStatus struct {
 	// +listMapKey=type
 	Conditions       []metav1.Condition `json:"conditions,omitempty"`
 	SandboxClaimName string             `json:"sandboxClaimName,omitempty"`
+	// SandboxTemplateName is the template the task's sandbox was claimed
+	// from. It differs from spec.runner.sandboxTemplateName when an
+	// operator template route matched the task.
+	// +optional
+	SandboxTemplateName string `json:"sandboxTemplateName,omitempty"`
+	// RunnerProtocolVersion is the task assignment protocol version the
+	// runner reported when it accepted the task. It is unset for runners
+	// that predate protocol versioning.
+	// +optional
+	RunnerProtocolVersion int32 `json:"runnerProtocolVersion,omitempty"`
 	// +optional
 	Result TaskResult `json:"result,omitzero"`
 	// GraceDeadline tracks the deadline for the grace period when a sandbox
@@ -123,11 +221,77 @@ type AgentTaskStatus struct {
 	// Should be reset if task retrigger functionality is implemented in the future.
 	// +optional
 	TokenIssued bool `json:"tokenIssued,omitempty"`
+	// Notes are comments people attached to the task, oldest first.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	Notes []TaskNote `json:"notes,omitempty"`
+	// CallbackHistory records the latest attempts to send the task's
+	// terminal callback to its adapter, oldest first.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	CallbackHistory []CallbackAttempt `json:"callbackHistory,omitempty"`
+	// TimeoutExtensions are the extensions of the runner timeout granted
+	// through the API, oldest first.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	TimeoutExtensions []TimeoutExtension `json:"timeoutExtensions,omitempty"`
+}
+
+// TimeoutExtension is an extension of a task's runner timeout.
+type TimeoutExtension struct {
+	// Duration is how much the timeout was extended by.
+	Duration metav1.Duration `json:"duration"`
+	// Reason is why the timeout was extended, as given by the client.
+	// +optional
+	// +kubebuilder:validation:MaxLength=200
+	Reason    string      `json:"reason,omitempty"`
+	CreatedAt metav1.Time `json:"createdAt"`
+}
+
+// CallbackAttempt is one attempt to send a callback to the task's adapter.
+type CallbackAttempt struct {
+	Time metav1.Time `json:"time"`
+	// Event is the callback event, such as "completed" or "failed".
+	Event string `json:"event"`
+	// StatusCode is the HTTP status the adapter answered with; zero when no
+	// response was received.
+	// +optional
+	StatusCode int32 `json:"statusCode,omitempty"`
+	// Error describes why the attempt failed; empty when it succeeded.
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	Error string `json:"error,omitempty"`
+	// Replay marks attempts requested through the replay endpoint rather
+	// than sent by the API server on its own.
+	// +optional
+	Replay bool `json:"replay,omitempty"`
+}
+
+// TaskNote is a comment attached to a task by a person, such as why it
+// was retried or that its PR was superseded.
+type TaskNote struct {
+	// Author is who wrote the note, as given by the client.
+	// +optional
+	// +kubebuilder:validation:MaxLength=100
+	Author string `json:"author,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=2000
+	Text      string      `json:"text"`
+	CreatedAt metav1.Time `json:"createdAt"`
 }
 
 type TaskResult struct {
 	PRURL string `json:"prURL,omitempty"`
 	Error string `json:"error,omitempty"`
+	// CostUSD is the model cost reported by the runner, as a decimal string
+	// (e.g. "0.4210").
+	// +optional
+	CostUSD string `json:"costUSD,omitempty"`
+	// Summary is the runner's short description of what it changed and
+	// why, used as the PR body and in the completion comment.
+	// +optional
+	// +kubebuilder:validation:MaxLength=4000
+	Summary string `json:"summary,omitempty"`
 }
 
 // IsTerminal returns true if the task has reached a terminal condition.
@@ -140,6 +304,58 @@ func (t *AgentTask) IsTerminal() bool {
 	return cond.Status != metav1.ConditionUnknown
 }
 
+// RunnerTimeout returns how long the runner may work on the task,
+// including the extensions granted since it was created.
+func (t *AgentTask) RunnerTimeout() time.Duration {
+	timeout := t.Spec.Runner.Timeout.Duration
+	if timeout == 0 {
+		timeout = DefaultRunnerTimeout
+	}
+	return timeout + t.TimeoutExtension()
+}
+
+// BranchName returns the branch the runner pushes the task's changes to.
+func (t *AgentTask) BranchName() string {
+	prefix := t.Spec.Runner.BranchPrefix
+	if prefix == "" {
+		prefix = DefaultBranchPrefix
+	}
+	return prefix + t.Name
+}
+
+// TimeoutExtension returns the sum of the task's timeout extensions.
+func (t *AgentTask) TimeoutExtension() time.Duration {
+	var total time.Duration
+	for _, ext := range t.Status.TimeoutExtensions {
+		total += ext.Duration.Duration
+	}
+	return total
+}
+
+// ComputePhase returns the phase the task's status describes.
+func (t *AgentTask) ComputePhase() TaskPhase {
+	cond := meta.FindStatusCondition(t.Status.Conditions, ConditionSucceeded)
+	switch {
+	case cond == nil:
+		return PhasePending
+	case cond.Status == metav1.ConditionTrue:
+		return PhaseSucceeded
+	case cond.Status == metav1.ConditionFalse:
+		switch cond.Reason {
+		case ReasonTimedOut:
+			return PhaseTimedOut
+		case ReasonCancelled:
+			return PhaseCancelled
+		}
+		return PhaseFailed
+	case cond.Reason == ReasonRunning:
+		return PhaseRunning
+	case cond.Reason == ReasonPending && t.Status.SandboxClaimName != "":
+		return PhaseProvisioning
+	}
+	return PhasePending
+}
+
 // +kubebuilder:object:root=true
 
 // AgentTaskList contains a list of AgentTask.
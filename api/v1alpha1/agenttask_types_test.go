@@ -0,0 +1,58 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComputePhase(t *testing.T) {
+	tests := []struct {
+		status metav1.ConditionStatus
+		reason string
+		claim  string
+		want   TaskPhase
+	}{
+		{metav1.ConditionUnknown, ReasonPending, "", PhasePending},
+		{metav1.ConditionUnknown, ReasonQueued, "", PhasePending},
+		{metav1.ConditionUnknown, ReasonWaitingForDependency, "", PhasePending},
+		{metav1.ConditionUnknown, ReasonSuspended, "", PhasePending},
+		{metav1.ConditionUnknown, ReasonPending, "task-1", PhaseProvisioning},
+		{metav1.ConditionUnknown, ReasonRunning, "task-1", PhaseRunning},
+		{metav1.ConditionTrue, ReasonSucceeded, "task-1", PhaseSucceeded},
+		{metav1.ConditionFalse, ReasonFailed, "task-1", PhaseFailed},
+		{metav1.ConditionFalse, ReasonTimedOut, "task-1", PhaseTimedOut},
+		{metav1.ConditionFalse, ReasonCancelled, "", PhaseCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reason+"/"+tt.claim, func(t *testing.T) {
+			task := &AgentTask{Status: AgentTaskStatus{SandboxClaimName: tt.claim}}
+			meta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+				Type:   ConditionSucceeded,
+				Status: tt.status,
+				Reason: tt.reason,
+			})
+			assert.Equal(t, tt.want, task.ComputePhase())
+		})
+	}
+
+	assert.Equal(t, PhasePending, (&AgentTask{}).ComputePhase(), "no condition yet")
+}
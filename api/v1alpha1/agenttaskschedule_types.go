@@ -0,0 +1,146 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy describes how a schedule treats a run that is due while
+// a task from an earlier run is still active.
+// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent starts the new task alongside the active ones.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the run until the active task finishes.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes the active tasks and starts the new one.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ats
+// +kubebuilder:validation:XValidation:rule="self.metadata.name.size() <= 52",message="name must be no more than 52 characters"
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`
+// +kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// AgentTaskSchedule creates AgentTasks from a template on a cron schedule.
+type AgentTaskSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	Spec AgentTaskScheduleSpec `json:"spec,omitzero"`
+	// +optional
+	Status AgentTaskScheduleStatus `json:"status,omitzero"`
+}
+
+type AgentTaskScheduleSpec struct {
+	// Schedule is a five-field cron expression, e.g. "0 6 * * 1" for Mondays
+	// at 06:00. The macros @hourly, @daily, @weekly, @monthly and @yearly are
+	// also accepted.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// TimeZone is the IANA time zone the schedule is evaluated in, e.g.
+	// "Europe/Stockholm". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// StartingDeadlineSeconds is how late a run may start after its
+	// scheduled time, e.g. after operator downtime. Later runs are skipped.
+	// Unset means no deadline.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// ConcurrencyPolicy decides what happens when a run is due while a task
+	// from an earlier run is still active.
+	// +kubebuilder:default=Forbid
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Suspend stops new runs. Tasks that are already active are not affected.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// SuccessfulTasksHistoryLimit is how many succeeded tasks to keep.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=3
+	// +optional
+	SuccessfulTasksHistoryLimit *int32 `json:"successfulTasksHistoryLimit,omitempty"`
+
+	// FailedTasksHistoryLimit is how many failed, timed out or cancelled
+	// tasks to keep.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	FailedTasksHistoryLimit *int32 `json:"failedTasksHistoryLimit,omitempty"`
+
+	// TaskTemplate describes the AgentTask created on each run.
+	TaskTemplate AgentTaskTemplate `json:"taskTemplate"`
+}
+
+// AgentTaskTemplate is the part of an AgentTask that a schedule stamps out.
+// Unlike AgentTaskSpec, repo and task may be edited; changes apply to the
+// next run.
+type AgentTaskTemplate struct {
+	// Labels are added to every created task.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	Repo     RepoSpec     `json:"repo"`
+	Task     TaskSpec     `json:"task"`
+	Callback CallbackSpec `json:"callback"`
+	// +optional
+	Runner RunnerSpec `json:"runner,omitzero"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+type AgentTaskScheduleStatus struct {
+	// Active lists the tasks created by this schedule that have not finished.
+	// +listType=set
+	// +optional
+	Active []string `json:"active,omitempty"`
+	// LastScheduleTime is the scheduled time of the most recent run.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is when the most recent successful task completed.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AgentTaskScheduleList contains a list of AgentTaskSchedule.
+type AgentTaskScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []AgentTaskSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AgentTaskSchedule{}, &AgentTaskScheduleList{})
+}
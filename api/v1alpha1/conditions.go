@@ -21,12 +21,15 @@ const (
 	ConditionSucceeded = "Succeeded"
 
 	// Reasons for ConditionSucceeded
-	ReasonPending   = "Pending"
-	ReasonRunning   = "Running"
-	ReasonSucceeded = "Succeeded"
-	ReasonFailed    = "Failed"
-	ReasonTimedOut  = "TimedOut"
-	ReasonCancelled = "Cancelled"
+	ReasonPending              = "Pending"
+	ReasonWaitingForDependency = "WaitingForDependency" // Status=Unknown: spec.dependsOn not yet satisfied
+	ReasonQueued               = "Queued"               // Status=Unknown: waiting for a free sandbox slot
+	ReasonSuspended            = "Suspended"            // Status=Unknown: spec.suspend is set
+	ReasonRunning              = "Running"
+	ReasonSucceeded            = "Succeeded"
+	ReasonFailed               = "Failed"
+	ReasonTimedOut             = "TimedOut"
+	ReasonCancelled            = "Cancelled"
 
 	// ConditionNotified indicates the adapter callback has been sent for a terminal state.
 	// Managed by the API server, not the operator.
@@ -36,4 +39,13 @@ const (
 	ReasonCallbackPending = "CallbackPending" // Status=Unknown: callback is being sent
 	ReasonCallbackSent    = "CallbackSent"    // Status=True: callback sent successfully
 	ReasonCallbackFailed  = "CallbackFailed"  // Status=True: callback failed but won't retry
+
+	// ConditionTimeoutWarning indicates a running task has used most of its
+	// runner timeout. Set by the operator; the API server changes its reason
+	// once it warned the adapter.
+	ConditionTimeoutWarning = "TimeoutWarning"
+
+	// Reasons for ConditionTimeoutWarning
+	ReasonDeadlineApproaching = "DeadlineApproaching" // Status=True: adapter not warned yet
+	ReasonWarningSent         = "WarningSent"         // Status=True: warning callback sent (or attempted)
 )
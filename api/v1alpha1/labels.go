@@ -0,0 +1,50 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RepoLabel holds the repository of an AgentTask in "owner-repo" form, for
+// filtering tasks by repository.
+const RepoLabel = "shepherd.io/repo"
+
+// DefaultRunnerTimeout applies to tasks without spec.runner.timeout.
+const DefaultRunnerTimeout = 30 * time.Minute
+
+// DefaultBranchPrefix names the branches of tasks without
+// spec.runner.branchPrefix.
+const DefaultBranchPrefix = "shepherd/"
+
+// RepoLabelValue converts a repository URL to the value of RepoLabel, or ""
+// if the result is not a valid label value.
+func RepoLabelValue(repoURL string) string {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return ""
+	}
+	value := strings.ReplaceAll(strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git"), "/", "-")
+	if len(validation.IsValidLabelValue(value)) > 0 {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=fleet
+// +kubebuilder:validation:XValidation:rule="self.metadata.name.size() <= 52",message="name must be no more than 52 characters"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.conditions[?(@.type=="Succeeded")].reason`
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.status.total`
+// +kubebuilder:printcolumn:name="Succeeded",type=integer,JSONPath=`.status.succeeded`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// TaskFleet runs one task in many repositories by creating an AgentTask per
+// repository and aggregating their results.
+type TaskFleet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	Spec TaskFleetSpec `json:"spec,omitzero"`
+	// +optional
+	Status TaskFleetStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.repos) != has(self.repoQuery)",message="exactly one of repos or repoQuery must be set"
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+type TaskFleetSpec struct {
+	// Repos lists the repositories to run the task in.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=500
+	// +listType=atomic
+	// +optional
+	Repos []RepoSpec `json:"repos,omitempty"`
+
+	// RepoQuery is a GitHub repository search query, e.g.
+	// "org:acme topic:terraform archived:false". It is resolved once when the
+	// fleet is first reconciled; the matches are recorded in status.repos.
+	// Requires the operator to be configured with GitHub App credentials.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	RepoQuery string `json:"repoQuery,omitempty"`
+
+	Task     TaskSpec     `json:"task"`
+	Callback CallbackSpec `json:"callback"`
+	// +optional
+	Runner RunnerSpec `json:"runner,omitzero"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+type TaskFleetStatus struct {
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Repos are the repository URLs the fleet runs in, copied from
+	// spec.repos or resolved from spec.repoQuery.
+	// +listType=atomic
+	// +optional
+	Repos []string `json:"repos,omitempty"`
+
+	// Counts of child tasks by state. Pending includes tasks waiting for a
+	// sandbox; Failed includes timed out and cancelled tasks.
+	Total     int32 `json:"total,omitempty"`
+	Pending   int32 `json:"pending,omitempty"`
+	Running   int32 `json:"running,omitempty"`
+	Succeeded int32 `json:"succeeded,omitempty"`
+	Failed    int32 `json:"failed,omitempty"`
+
+	// Tasks summarizes each child task in the order of status.repos.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Tasks []FleetTaskStatus `json:"tasks,omitempty"`
+}
+
+// FleetTaskStatus is the result of one child task of a fleet.
+type FleetTaskStatus struct {
+	Name    string `json:"name"`
+	RepoURL string `json:"repoURL"`
+	// Phase is the reason of the task's Succeeded condition.
+	Phase string `json:"phase"`
+	// +optional
+	PRURL string `json:"prURL,omitempty"`
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TaskFleetList contains a list of TaskFleet.
+type TaskFleetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []TaskFleet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TaskFleet{}, &TaskFleetList{})
+}
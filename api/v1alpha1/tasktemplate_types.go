@@ -0,0 +1,75 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=tt
+// +kubebuilder:printcolumn:name="Sandbox Template",type=string,JSONPath=`.spec.runner.sandboxTemplateName`
+// +kubebuilder:printcolumn:name="Timeout",type=string,JSONPath=`.spec.runner.timeout`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// TaskTemplate is a reusable task definition. Tasks created through the
+// API with a templateRef take their runner settings, labels, priority and
+// boilerplate context from it.
+type TaskTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	Spec TaskTemplateSpec `json:"spec,omitzero"`
+}
+
+type TaskTemplateSpec struct {
+	// Description says what the template is for. It is not copied to tasks.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	Runner RunnerSpec `json:"runner"`
+
+	// Labels are added to every task created from the template. Labels in
+	// the task request take precedence.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Context is prepended to the context of every task created from the
+	// template, e.g. coding conventions or instructions shared by all tasks.
+	// +kubebuilder:validation:MaxLength=65536
+	// +optional
+	Context string `json:"context,omitempty"`
+
+	// Priority is used when the task request does not set one.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TaskTemplateList contains a list of TaskTemplate.
+type TaskTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []TaskTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TaskTemplate{}, &TaskTemplateList{})
+}
@@ -84,6 +84,124 @@ func (in *AgentTaskList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentTaskSchedule) DeepCopyInto(out *AgentTaskSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTaskSchedule.
+func (in *AgentTaskSchedule) DeepCopy() *AgentTaskSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentTaskSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AgentTaskSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentTaskScheduleList) DeepCopyInto(out *AgentTaskScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AgentTaskSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTaskScheduleList.
+func (in *AgentTaskScheduleList) DeepCopy() *AgentTaskScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentTaskScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AgentTaskScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentTaskScheduleSpec) DeepCopyInto(out *AgentTaskScheduleSpec) {
+	*out = *in
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SuccessfulTasksHistoryLimit != nil {
+		in, out := &in.SuccessfulTasksHistoryLimit, &out.SuccessfulTasksHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedTasksHistoryLimit != nil {
+		in, out := &in.FailedTasksHistoryLimit, &out.FailedTasksHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	in.TaskTemplate.DeepCopyInto(&out.TaskTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTaskScheduleSpec.
+func (in *AgentTaskScheduleSpec) DeepCopy() *AgentTaskScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentTaskScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentTaskScheduleStatus) DeepCopyInto(out *AgentTaskScheduleStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTaskScheduleStatus.
+func (in *AgentTaskScheduleStatus) DeepCopy() *AgentTaskScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentTaskScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentTaskSpec) DeepCopyInto(out *AgentTaskSpec) {
 	*out = *in
@@ -91,6 +209,16 @@ func (in *AgentTaskSpec) DeepCopyInto(out *AgentTaskSpec) {
 	out.Task = in.Task
 	out.Callback = in.Callback
 	in.Runner.DeepCopyInto(&out.Runner)
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TTLAfterFinished != nil {
+		in, out := &in.TTLAfterFinished, &out.TTLAfterFinished
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTaskSpec.
@@ -126,6 +254,27 @@ func (in *AgentTaskStatus) DeepCopyInto(out *AgentTaskStatus) {
 		in, out := &in.GraceDeadline, &out.GraceDeadline
 		*out = (*in).DeepCopy()
 	}
+	if in.Notes != nil {
+		in, out := &in.Notes, &out.Notes
+		*out = make([]TaskNote, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CallbackHistory != nil {
+		in, out := &in.CallbackHistory, &out.CallbackHistory
+		*out = make([]CallbackAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TimeoutExtensions != nil {
+		in, out := &in.TimeoutExtensions, &out.TimeoutExtensions
+		*out = make([]TimeoutExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTaskStatus.
@@ -138,6 +287,48 @@ func (in *AgentTaskStatus) DeepCopy() *AgentTaskStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentTaskTemplate) DeepCopyInto(out *AgentTaskTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Repo = in.Repo
+	out.Task = in.Task
+	out.Callback = in.Callback
+	in.Runner.DeepCopyInto(&out.Runner)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTaskTemplate.
+func (in *AgentTaskTemplate) DeepCopy() *AgentTaskTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentTaskTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CallbackAttempt) DeepCopyInto(out *CallbackAttempt) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CallbackAttempt.
+func (in *CallbackAttempt) DeepCopy() *CallbackAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(CallbackAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CallbackSpec) DeepCopyInto(out *CallbackSpec) {
 	*out = *in
@@ -153,6 +344,21 @@ func (in *CallbackSpec) DeepCopy() *CallbackSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetTaskStatus) DeepCopyInto(out *FleetTaskStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetTaskStatus.
+func (in *FleetTaskStatus) DeepCopy() *FleetTaskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetTaskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepoSpec) DeepCopyInto(out *RepoSpec) {
 	*out = *in
@@ -185,6 +391,136 @@ func (in *RunnerSpec) DeepCopy() *RunnerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskFleet) DeepCopyInto(out *TaskFleet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskFleet.
+func (in *TaskFleet) DeepCopy() *TaskFleet {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskFleet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskFleet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskFleetList) DeepCopyInto(out *TaskFleetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TaskFleet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskFleetList.
+func (in *TaskFleetList) DeepCopy() *TaskFleetList {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskFleetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskFleetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskFleetSpec) DeepCopyInto(out *TaskFleetSpec) {
+	*out = *in
+	if in.Repos != nil {
+		in, out := &in.Repos, &out.Repos
+		*out = make([]RepoSpec, len(*in))
+		copy(*out, *in)
+	}
+	out.Task = in.Task
+	out.Callback = in.Callback
+	in.Runner.DeepCopyInto(&out.Runner)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskFleetSpec.
+func (in *TaskFleetSpec) DeepCopy() *TaskFleetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskFleetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskFleetStatus) DeepCopyInto(out *TaskFleetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Repos != nil {
+		in, out := &in.Repos, &out.Repos
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]FleetTaskStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskFleetStatus.
+func (in *TaskFleetStatus) DeepCopy() *TaskFleetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskFleetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskNote) DeepCopyInto(out *TaskNote) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskNote.
+func (in *TaskNote) DeepCopy() *TaskNote {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskNote)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskResult) DeepCopyInto(out *TaskResult) {
 	*out = *in
@@ -214,3 +550,101 @@ func (in *TaskSpec) DeepCopy() *TaskSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskTemplate) DeepCopyInto(out *TaskTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskTemplate.
+func (in *TaskTemplate) DeepCopy() *TaskTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskTemplateList) DeepCopyInto(out *TaskTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TaskTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskTemplateList.
+func (in *TaskTemplateList) DeepCopy() *TaskTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskTemplateSpec) DeepCopyInto(out *TaskTemplateSpec) {
+	*out = *in
+	in.Runner.DeepCopyInto(&out.Runner)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskTemplateSpec.
+func (in *TaskTemplateSpec) DeepCopy() *TaskTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeoutExtension) DeepCopyInto(out *TimeoutExtension) {
+	*out = *in
+	out.Duration = in.Duration
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeoutExtension.
+func (in *TimeoutExtension) DeepCopy() *TimeoutExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeoutExtension)
+	in.DeepCopyInto(out)
+	return out
+}
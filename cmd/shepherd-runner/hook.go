@@ -7,11 +7,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
 	"github.com/NissesSenap/shepherd/pkg/runner"
 )
 
@@ -20,6 +25,14 @@ const (
 	eventCompleted = "completed"
 )
 
+// maxVerificationSummaryLen caps the verdict summary reported to the API,
+// which ends up in the task status and the issue comment.
+const maxVerificationSummaryLen = 4000
+
+// maxSummaryLen caps the task summary reported to the API, the most
+// status.result.summary holds.
+const maxSummaryLen = 4000
+
 // HookInput is the JSON data CC passes to hooks on stdin.
 // Note: CC does NOT pass result data — only metadata. Artifact verification
 // must be done by inspecting git state and PR existence.
@@ -72,10 +85,14 @@ func runHook(
 		return fmt.Errorf("SHEPHERD_API_URL and SHEPHERD_TASK_ID must be set")
 	}
 
-	logger = logger.WithValues("taskID", taskID)
+	logger = logger.WithValues(logging.TaskID, taskID)
+	correlationID := getenv("SHEPHERD_CORRELATION_ID")
+	if correlationID != "" {
+		logger = logger.WithValues(logging.CorrelationID, correlationID)
+	}
 
 	// 4. Verify artifacts
-	client := runner.NewClient(apiURL)
+	client := runner.NewClient(apiURL, runner.WithCorrelationID(correlationID))
 	event, message, details := verifyArtifacts(ctx, logger, exec, input.CWD, taskID, getenv)
 
 	// 5. Report status to API
@@ -102,7 +119,26 @@ func verifyArtifacts(
 	ctx context.Context, logger logr.Logger, exec CommandExecutor,
 	cwd, taskID string, getenv func(string) string,
 ) (event, message string, details map[string]any) {
-	branch := "shepherd/" + taskID
+	// Verification tasks don't produce a PR; the verdict file is the artifact.
+	if getenv("SHEPHERD_SOURCE_TYPE") == api.SourceTypeVerification {
+		return readVerificationResult(logger, filepath.Join(getenv("HOME"), verificationResultFile))
+	}
+
+	// Plans and reviews don't change code; the report is the artifact.
+	if mode := getenv("SHEPHERD_MODE"); mode == api.ModePlan || mode == api.ModeReview {
+		return readReport(logger, mode, filepath.Join(getenv("HOME"), summaryFile))
+	}
+
+	// Tasks of an existing PR push to its branch instead of opening a PR.
+	if getenv("SHEPHERD_SOURCE_TYPE") == api.SourceTypePullRequest {
+		return verifyPush(ctx, logger, exec, cwd, getenv)
+	}
+
+	// Runners that predate branch prefixes do not set SHEPHERD_BRANCH.
+	branch := getenv("SHEPHERD_BRANCH")
+	if branch == "" {
+		branch = toolkitv1alpha1.DefaultBranchPrefix + taskID
+	}
 
 	// 1. Check PR first — most definitive signal of success.
 	// After push, git rev-list --not --remotes returns 0 (commits are on remote),
@@ -119,7 +155,11 @@ func verifyArtifacts(
 	} else {
 		prURL := strings.TrimSpace(string(res.Stdout))
 		if prURL != "" {
-			return eventCompleted, "task completed", map[string]any{"pr_url": prURL}
+			details := map[string]any{"pr_url": prURL}
+			if summary := readSummary(logger, filepath.Join(getenv("HOME"), summaryFile)); summary != "" {
+				details["summary"] = summary
+			}
+			return eventCompleted, "task completed", details
 		}
 	}
 
@@ -144,3 +184,106 @@ func verifyArtifacts(
 
 	return eventFailed, "changes made but no PR created", nil
 }
+
+// verifyPush checks whether a task of an existing PR pushed commits to the
+// PR's branch. The runner records the commit the task started from, and
+// git push updates the remote-tracking branch, so the commits between the
+// two are the ones that reached the PR.
+func verifyPush(
+	ctx context.Context, logger logr.Logger, exec CommandExecutor,
+	cwd string, getenv func(string) string,
+) (event, message string, details map[string]any) {
+	start := getenv("SHEPHERD_START_COMMIT")
+	branch := getenv("SHEPHERD_BASE_REF")
+	if start == "" || branch == "" {
+		return eventFailed, "pull request branch unknown", nil
+	}
+
+	logger.Info("checking for pushed commits", "branch", branch, "start", start)
+	pushed, err := countCommits(ctx, exec, cwd, start+"..origin/"+branch)
+	if err != nil {
+		logger.Error(err, "failed to check pushed commits")
+		return eventFailed, "failed to check git state", nil
+	}
+	if pushed > 0 {
+		details := map[string]any{"pr_url": getenv("SHEPHERD_SOURCE_URL")}
+		if summary := readSummary(logger, filepath.Join(getenv("HOME"), summaryFile)); summary != "" {
+			details["summary"] = summary
+		}
+		return eventCompleted, "task completed", details
+	}
+
+	local, err := countCommits(ctx, exec, cwd, start+"..HEAD")
+	if err != nil {
+		logger.Error(err, "failed to check commit count")
+		return eventFailed, "failed to check git state", nil
+	}
+	if local > 0 {
+		return eventFailed, "changes made but not pushed to the pull request", nil
+	}
+	return eventFailed, "no changes made", nil
+}
+
+// countCommits returns the number of commits in the git revision range.
+func countCommits(ctx context.Context, exec CommandExecutor, cwd, revRange string) (int, error) {
+	res, err := exec.Run(ctx, "git", []string{"rev-list", "--count", revRange}, ExecOptions{Dir: cwd})
+	if err != nil {
+		return 0, err
+	}
+	if res.ExitCode != 0 {
+		return 0, fmt.Errorf("git rev-list failed (exit %d): %s", res.ExitCode, string(res.Stderr))
+	}
+	return strconv.Atoi(strings.TrimSpace(string(res.Stdout)))
+}
+
+// readSummary returns the summary the agent wrote to path, or "" if it
+// wrote none.
+func readSummary(logger logr.Logger, path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Error(err, "failed to read task summary")
+		}
+		return ""
+	}
+	return truncate(strings.TrimSpace(string(data)), maxSummaryLen, truncationSuffix)
+}
+
+// readReport returns the outcome of a plan or review task: completed with
+// the report the agent wrote as its summary, or failed if it wrote none.
+func readReport(logger logr.Logger, mode, path string) (event, message string, details map[string]any) {
+	report := readSummary(logger, path)
+	if report == "" {
+		return eventFailed, "no " + mode + " written", nil
+	}
+	return eventCompleted, mode + " written", map[string]any{"summary": report}
+}
+
+// readVerificationResult parses the verdict written by a verification task.
+// The first line is PASS or FAIL; the remainder is a free-form summary.
+func readVerificationResult(logger logr.Logger, path string) (event, message string, details map[string]any) {
+	logger.Info("reading verification result", "path", path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Error(err, "failed to read verification result")
+		return eventFailed, "no verification result written", nil
+	}
+
+	verdict, summary, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+	summary = truncate(strings.TrimSpace(summary), maxVerificationSummaryLen, truncationSuffix)
+
+	switch strings.ToUpper(strings.TrimSpace(verdict)) {
+	case "PASS":
+		if summary == "" {
+			summary = "verification passed"
+		}
+		return eventCompleted, summary, map[string]any{"verification": "passed"}
+	case "FAIL":
+		if summary == "" {
+			summary = "verification failed"
+		}
+		return eventFailed, summary, map[string]any{"verification": "failed"}
+	default:
+		return eventFailed, fmt.Sprintf("invalid verification verdict %q, expected PASS or FAIL", truncate(verdict, 100, truncationSuffix)), nil
+	}
+}
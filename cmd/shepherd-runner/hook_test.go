@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -180,6 +182,70 @@ func TestHookPRCreated(t *testing.T) {
 	assert.Equal(t, "gh", mock.calls[0].Name)
 }
 
+func TestHookBranchPrefix(t *testing.T) {
+	mock := &mockExecutor{
+		results: []*ExecResult{
+			{ExitCode: 0, Stdout: []byte("https://github.com/org/repo/pull/42\n")}, // gh pr list
+		},
+		errs: []error{nil},
+	}
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer apiServer.Close()
+
+	base := makeGetenv(apiServer.URL, "task-1")
+	getenv := func(key string) string {
+		if key == "SHEPHERD_BRANCH" {
+			return "bot/task-1"
+		}
+		return base(key)
+	}
+	require.NoError(t, runHook(context.Background(), logr.Discard(), hookInput(false, "/tmp/repo"), mock, getenv))
+
+	require.Len(t, mock.calls, 1)
+	assert.Contains(t, mock.calls[0].Args, "bot/task-1")
+}
+
+func TestHookPRCreatedWithSummary(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(home, summaryFile),
+		[]byte("\nFixed the login redirect by keeping the return URL.\n"), 0o644))
+	mock := &mockExecutor{
+		results: []*ExecResult{
+			{ExitCode: 0, Stdout: []byte("https://github.com/org/repo/pull/42\n")}, // gh pr list
+		},
+		errs: []error{nil},
+	}
+
+	var reportedDetails map[string]any
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		reportedDetails, _ = req["details"].(map[string]any)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer apiServer.Close()
+
+	getenv := makeGetenv(apiServer.URL, "task-1")
+	err := runHook(
+		context.Background(), logr.Discard(),
+		hookInput(false, "/tmp/repo"), mock,
+		func(key string) string {
+			if key == "HOME" {
+				return home
+			}
+			return getenv(key)
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"pr_url":  "https://github.com/org/repo/pull/42",
+		"summary": "Fixed the login redirect by keeping the return URL.",
+	}, reportedDetails)
+}
+
 func TestHookMissingEnvVars(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -259,3 +325,211 @@ func TestHookAPIHTTPError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "API rejected status report")
 }
+
+func TestHookVerificationResult(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string // empty means no result file is written
+		wantEvent    string
+		wantMessage  string
+		wantVerified string
+	}{
+		{
+			name:         "pass with summary",
+			content:      "PASS\nAll tests pass and the crash no longer reproduces.\n",
+			wantEvent:    "completed",
+			wantMessage:  "All tests pass and the crash no longer reproduces.",
+			wantVerified: "passed",
+		},
+		{
+			name:         "fail with summary",
+			content:      "fail\n\nTestLogin still fails on main.",
+			wantEvent:    "failed",
+			wantMessage:  "TestLogin still fails on main.",
+			wantVerified: "failed",
+		},
+		{
+			name:         "pass without summary",
+			content:      "PASS",
+			wantEvent:    "completed",
+			wantMessage:  "verification passed",
+			wantVerified: "passed",
+		},
+		{
+			name:        "invalid verdict",
+			content:     "Looks good to me\n",
+			wantEvent:   "failed",
+			wantMessage: `invalid verification verdict "Looks good to me", expected PASS or FAIL`,
+		},
+		{
+			name:        "missing result file",
+			wantEvent:   "failed",
+			wantMessage: "no verification result written",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(home, verificationResultFile), []byte(tt.content), 0o644))
+			}
+
+			var reported map[string]any
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&reported)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer apiServer.Close()
+
+			base := makeGetenv(apiServer.URL, "task-1")
+			getenv := func(key string) string {
+				switch key {
+				case "SHEPHERD_SOURCE_TYPE":
+					return "verification"
+				case "HOME":
+					return home
+				default:
+					return base(key)
+				}
+			}
+
+			mock := &mockExecutor{}
+			err := runHook(context.Background(), logr.Discard(), hookInput(false, "/tmp/repo"), mock, getenv)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantEvent, reported["event"])
+			assert.Equal(t, tt.wantMessage, reported["message"])
+			if tt.wantVerified != "" {
+				details, _ := reported["details"].(map[string]any)
+				assert.Equal(t, tt.wantVerified, details["verification"])
+			}
+			// Verification tasks never look for a PR or commits
+			assert.Empty(t, mock.calls)
+		})
+	}
+}
+
+func TestHookPullRequestPush(t *testing.T) {
+	tests := []struct {
+		name        string
+		results     []*ExecResult
+		wantEvent   string
+		wantMessage string
+		wantPRURL   string
+	}{
+		{
+			name: "pushed",
+			results: []*ExecResult{
+				{ExitCode: 0, Stdout: []byte("2\n")}, // commits on origin/feature
+			},
+			wantEvent:   "completed",
+			wantMessage: "task completed",
+			wantPRURL:   "https://github.com/org/repo/pull/7",
+		},
+		{
+			name: "committed but not pushed",
+			results: []*ExecResult{
+				{ExitCode: 0, Stdout: []byte("0\n")},
+				{ExitCode: 0, Stdout: []byte("1\n")},
+			},
+			wantEvent:   "failed",
+			wantMessage: "changes made but not pushed to the pull request",
+		},
+		{
+			name: "no changes",
+			results: []*ExecResult{
+				{ExitCode: 0, Stdout: []byte("0\n")},
+			},
+			wantEvent:   "failed",
+			wantMessage: "no changes made",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported map[string]any
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&reported)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer apiServer.Close()
+
+			base := makeGetenv(apiServer.URL, "task-1")
+			getenv := func(key string) string {
+				switch key {
+				case "SHEPHERD_SOURCE_TYPE":
+					return "pr"
+				case "SHEPHERD_SOURCE_URL":
+					return "https://github.com/org/repo/pull/7"
+				case "SHEPHERD_START_COMMIT":
+					return "abc123"
+				case "SHEPHERD_BASE_REF":
+					return "feature"
+				case "HOME":
+					return t.TempDir()
+				default:
+					return base(key)
+				}
+			}
+
+			mock := &mockExecutor{results: tt.results, errs: make([]error, len(tt.results))}
+			err := runHook(context.Background(), logr.Discard(), hookInput(false, "/tmp/repo"), mock, getenv)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantEvent, reported["event"])
+			assert.Equal(t, tt.wantMessage, reported["message"])
+			details, _ := reported["details"].(map[string]any)
+			prURL, _ := details["pr_url"].(string)
+			assert.Equal(t, tt.wantPRURL, prURL, "the existing PR is reported")
+
+			// A task of an existing PR never looks for a new PR
+			require.NotEmpty(t, mock.calls)
+			assert.Equal(t, []string{"rev-list", "--count", "abc123..origin/feature"}, mock.calls[0].Args)
+			for _, call := range mock.calls {
+				assert.Equal(t, "git", call.Name)
+			}
+		})
+	}
+}
+
+func TestHookReport(t *testing.T) {
+	for _, mode := range []string{"plan", "review"} {
+		t.Run(mode, func(t *testing.T) {
+			home := t.TempDir()
+
+			var reported map[string]any
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&reported)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer apiServer.Close()
+
+			base := makeGetenv(apiServer.URL, "task-1")
+			getenv := func(key string) string {
+				switch key {
+				case "SHEPHERD_MODE":
+					return mode
+				case "HOME":
+					return home
+				default:
+					return base(key)
+				}
+			}
+
+			mock := &mockExecutor{}
+			require.NoError(t, runHook(context.Background(), logr.Discard(), hookInput(false, "/tmp/repo"), mock, getenv))
+			assert.Equal(t, "failed", reported["event"])
+			assert.Equal(t, "no "+mode+" written", reported["message"])
+
+			require.NoError(t, os.WriteFile(filepath.Join(home, summaryFile), []byte("1. Rename the helper\n"), 0o644))
+			require.NoError(t, runHook(context.Background(), logr.Discard(), hookInput(false, "/tmp/repo"), mock, getenv))
+			assert.Equal(t, "completed", reported["event"])
+			details, _ := reported["details"].(map[string]any)
+			assert.Equal(t, "1. Rename the helper", details["summary"])
+
+			// Plans and reviews never look for a PR or commits
+			assert.Empty(t, mock.calls)
+		})
+	}
+}
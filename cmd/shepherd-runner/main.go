@@ -1,15 +1,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/alecthomas/kong"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
 )
 
 type CLI struct {
 	Serve ServeCmd `cmd:"" default:"1" help:"Run the runner HTTP server (default)"`
 	Hook  HookCmd  `cmd:"" help:"Handle Claude Code Stop hook"`
+
+	LogLevel  int    `help:"Log level (0=info, 1=debug)" default:"0" env:"SHEPHERD_LOG_LEVEL"`
+	LogFormat string `help:"Log format: text or json" default:"text" enum:"text,json" env:"SHEPHERD_LOG_FORMAT"`
+
+	TracingEndpoint    string  `help:"OTLP/HTTP endpoint traces are exported to, e.g. http://otel-collector:4318 (empty = no export)" env:"SHEPHERD_TRACING_ENDPOINT"`
+	TracingSampleRatio float64 `help:"Fraction of new traces that are sampled (0-1)" default:"1" env:"SHEPHERD_TRACING_SAMPLE_RATIO"`
 }
 
 func main() {
@@ -18,8 +30,27 @@ func main() {
 		kong.Name("shepherd-runner"),
 		kong.Description("Shepherd runner for coding tasks"),
 	)
-	if err := ctx.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+
+	// Logs go to stderr; the hook's stdout is read by Claude Code.
+	logger, err := logging.New(logging.Options{Format: cli.LogFormat, Level: cli.LogLevel})
+	ctx.FatalIfErrorf(err)
+	log.SetLogger(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: "shepherd-runner",
+		Endpoint:    cli.TracingEndpoint,
+		SampleRatio: cli.TracingSampleRatio,
+	})
+	ctx.FatalIfErrorf(err)
+
+	runErr := ctx.Run()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error(err, "failed to flush traces")
+	}
+	if runErr != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", runErr)
 		os.Exit(1)
 	}
 }
@@ -3,11 +3,31 @@ package main
 import (
 	"fmt"
 
+	"github.com/NissesSenap/shepherd/pkg/api"
 	"github.com/NissesSenap/shepherd/pkg/runner"
 )
 
+// verificationResultFile is where verification tasks write their verdict,
+// relative to the home directory (outside the repository).
+const verificationResultFile = "verification-result.md"
+
+// summaryFile is where the agent writes the summary of its change, relative
+// to the home directory. It becomes the PR body and status.result.summary.
+const summaryFile = "task-summary.md"
+
 // buildPrompt constructs the v1 prompt for Claude Code from task data.
 func buildPrompt(task runner.TaskData) string {
+	switch {
+	case task.SourceType == api.SourceTypeVerification:
+		return buildVerificationPrompt(task)
+	case task.Mode == api.ModePlan:
+		return buildPlanPrompt(task)
+	case task.Mode == api.ModeReview:
+		return buildReviewPrompt(task)
+	case task.SourceType == api.SourceTypePullRequest:
+		return buildPullRequestPrompt(task)
+	}
+
 	prompt := fmt.Sprintf(`You have been assigned a coding task. Please implement the requested changes.
 
 ## Task Description
@@ -28,11 +48,161 @@ Additional context has been written to ~/task-context.md (outside the repository
 2. Implement the changes described in the task description
 3. Run existing tests to verify your changes don't break anything
 4. Commit your changes with a clear commit message
-5. Create a pull request with a description of what you changed and why
-6. Stay focused on the assigned task — do not make unrelated changes`,
+5. Write a concise summary of what you changed and why, a few sentences in
+   plain language, to ~/%s (outside the repository)
+6. Create a pull request whose body is exactly that summary, for example
+   with: gh pr create --body-file ~/%s
+7. Stay focused on the assigned task — do not make unrelated changes`,
 		task.Description,
 		task.SourceURL,
+		summaryFile,
+		summaryFile,
 	)
 
 	return prompt
 }
+
+// buildPullRequestPrompt constructs the prompt for a task requested on an
+// existing pull request. The PR's branch is checked out, and the agent
+// pushes its commits to it instead of opening a new PR.
+func buildPullRequestPrompt(task runner.TaskData) string {
+	return fmt.Sprintf(`You have been assigned a coding task on an existing pull request. Please
+implement the requested changes on the pull request's branch.
+
+## Task Description
+
+%s
+
+## Source
+
+The pull request is: %s
+
+## Additional Context
+
+The pull request's description, the review discussion that requested this task and
+the pull request's diff have been written to ~/task-context.md (outside the repository).
+
+## Instructions
+
+1. Read the context and the existing codebase before making changes
+2. Implement the changes described in the task description
+3. Run existing tests to verify your changes don't break anything
+4. Commit your changes with a clear commit message
+5. Push the commits to the checked-out branch %s with: git push origin HEAD
+   If the push is rejected because the branch moved, rebase onto it and push again
+6. Do NOT create a new branch or a new pull request, and do not force-push
+7. Write a concise summary of what you changed and why, a few sentences in
+   plain language, to ~/%s (outside the repository)
+8. Stay focused on the assigned task — do not make unrelated changes`,
+		task.Description,
+		task.SourceURL,
+		task.RepoRef,
+		summaryFile,
+	)
+}
+
+// buildPlanPrompt constructs the prompt for a plan task. The agent writes
+// an implementation plan to the summary file, which is posted where the
+// task was requested, and changes no code.
+func buildPlanPrompt(task runner.TaskData) string {
+	return fmt.Sprintf(`You have been assigned a planning task. Work out how the requested change
+should be implemented, without implementing it.
+
+## Task Description
+
+%s
+
+## Source
+
+This task was created from: %s
+
+## Additional Context
+
+Additional context has been written to ~/task-context.md (outside the repository).
+
+## Instructions
+
+1. Read and understand the existing codebase and the context
+2. Work out the changes the task needs: the files and functions involved,
+   the approach, the tests to add, and any open questions or risks
+3. Do NOT modify code, commit, push branches, or create pull requests
+4. Write the plan as concise Markdown, at most about 3000 characters, to
+   ~/%s (outside the repository). It is posted as a comment where the
+   task was requested`,
+		task.Description,
+		task.SourceURL,
+		summaryFile,
+	)
+}
+
+// buildReviewPrompt constructs the prompt for a review task. The agent
+// reviews the task's PR, or the code the task names, writes its findings
+// to the summary file and changes no code.
+func buildReviewPrompt(task runner.TaskData) string {
+	subject := "the code the task description refers to"
+	if task.SourceType == api.SourceTypePullRequest {
+		subject = "the pull request's changes; its diff is in the context, and its branch is checked out"
+	}
+	return fmt.Sprintf(`You have been assigned a code review task. Review %s.
+
+## Task Description
+
+%s
+
+## Source
+
+This task was created from: %s
+
+## Additional Context
+
+Additional context has been written to ~/task-context.md (outside the repository).
+
+## Instructions
+
+1. Read the context and the code under review, and the code around it
+2. Look for bugs, missing tests, unclear code, and anything the task
+   description asks about; you may run the tests
+3. Do NOT modify code, commit, push branches, or create pull requests
+4. Write your findings as concise Markdown, most important first and
+   naming files and lines, at most about 3000 characters, to ~/%s
+   (outside the repository). It is posted as a comment where the task
+   was requested`,
+		subject,
+		task.Description,
+		task.SourceURL,
+		summaryFile,
+	)
+}
+
+// buildVerificationPrompt constructs the prompt for a post-merge verification
+// task. The agent checks the merged change instead of producing a new PR and
+// records its verdict in ~/verification-result.md for the Stop hook.
+func buildVerificationPrompt(task runner.TaskData) string {
+	return fmt.Sprintf(`You have been assigned a post-merge verification task. A pull request made to
+resolve an issue has been merged. Confirm that the merged change actually fixed it.
+
+## Task Description
+
+%s
+
+## Source
+
+The original issue is: %s
+
+## Additional Context
+
+Additional context has been written to ~/task-context.md (outside the repository).
+
+## Instructions
+
+1. Read the original issue and the merged pull request described in the context
+2. Run the project's test suite on the checked-out branch
+3. Reproduce the scenario from the original issue and confirm it no longer occurs
+4. Do NOT modify code, push branches, or create pull requests
+5. Write your verdict to ~/%s. The first line must be exactly PASS or FAIL,
+   followed by a short summary of what you checked and what you found`,
+		task.Description,
+		task.SourceURL,
+		verificationResultFile,
+	)
+}
@@ -16,6 +16,12 @@ type ServeCmd struct {
 	Addr      string `help:"Listen address" default:":8888" env:"SHEPHERD_RUNNER_ADDR"`
 	WorkDir   string `help:"Working directory for cloning repos" default:"/workspace" env:"SHEPHERD_WORK_DIR"`
 	ConfigDir string `help:"Directory with baked-in CC config" default:"/etc/shepherd" env:"SHEPHERD_CONFIG_DIR"`
+
+	MaxThinkingLen    int    `help:"Characters of the agent's text kept in thinking events" default:"200" env:"SHEPHERD_MAX_THINKING_LEN"`
+	MaxBashInputLen   int    `help:"Characters of a Bash command kept in tool call events" default:"500" env:"SHEPHERD_MAX_BASH_INPUT_LEN"`
+	MaxResultLen      int    `help:"Characters of a tool's output kept in tool result events" default:"200" env:"SHEPHERD_MAX_RESULT_LEN"`
+	TruncationSuffix  string `help:"Suffix marking truncated event text" default:"... (truncated)" env:"SHEPHERD_TRUNCATION_SUFFIX"`
+	AttachFullContent bool   `help:"Attach the untruncated text to truncated events as metadata.fullContent" env:"SHEPHERD_ATTACH_FULL_CONTENT"`
 }
 
 func (c *ServeCmd) Run() error {
@@ -32,6 +38,13 @@ func (c *ServeCmd) Run() error {
 		configDir: c.ConfigDir,
 		logger:    logger,
 		execCmd:   &osExecutor{},
+		limits: TruncationLimits{
+			Thinking:          c.MaxThinkingLen,
+			BashInput:         c.MaxBashInputLen,
+			Result:            c.MaxResultLen,
+			Suffix:            c.TruncationSuffix,
+			AttachFullContent: c.AttachFullContent,
+		},
 	}
 
 	srv := runner.NewServer(taskRunner, runner.WithAddr(c.Addr), runner.WithLogger(logger))
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"cmp"
 	"encoding/json"
 	"time"
 
@@ -13,19 +14,48 @@ const (
 	maxResultLen      = 200
 	truncationSuffix  = "... (truncated)"
 	maxEditSummaryLen = 200
+
+	// fullContentKey is the event metadata key holding the untruncated
+	// content when TruncationLimits.AttachFullContent is set.
+	fullContentKey = "fullContent"
 )
 
+// TruncationLimits bounds the text the stream parser copies into events, in
+// runes. Zero fields use the defaults above.
+type TruncationLimits struct {
+	Thinking    int
+	BashInput   int
+	Result      int
+	EditSummary int
+	Suffix      string
+	// AttachFullContent keeps the untruncated text of a truncated thinking
+	// block, command or tool result in the event's metadata.
+	AttachFullContent bool
+}
+
+// withDefaults returns l with zero fields set to the defaults.
+func (l TruncationLimits) withDefaults() TruncationLimits {
+	l.Thinking = cmp.Or(l.Thinking, maxThinkingLen)
+	l.BashInput = cmp.Or(l.BashInput, maxBashInputLen)
+	l.Result = cmp.Or(l.Result, maxResultLen)
+	l.EditSummary = cmp.Or(l.EditSummary, maxEditSummaryLen)
+	l.Suffix = cmp.Or(l.Suffix, truncationSuffix)
+	return l
+}
+
 // StreamParser translates Claude Code stream-json NDJSON lines into TaskEvents.
 type StreamParser struct {
 	toolMap    map[string]string // tool_use_id → tool_name
 	sequence   int64
 	lastResult *ResultMetrics
+	limits     TruncationLimits
 }
 
 // NewStreamParser creates a new stream-json parser.
-func NewStreamParser() *StreamParser {
+func NewStreamParser(limits TruncationLimits) *StreamParser {
 	return &StreamParser{
 		toolMap: make(map[string]string),
+		limits:  limits.withDefaults(),
 	}
 }
 
@@ -111,12 +141,12 @@ func (p *StreamParser) parseAssistant(msg *ccMessage) []api.TaskEvent {
 				continue
 			}
 			p.sequence++
-			events = append(events, api.TaskEvent{
+			events = append(events, p.attachFull(api.TaskEvent{
 				Sequence:  p.sequence,
 				Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
 				Type:      api.EventTypeThinking,
-				Summary:   truncate(content.Text, maxThinkingLen),
-			})
+				Summary:   p.truncate(content.Text, p.limits.Thinking),
+			}, content.Text))
 
 		case "tool_use":
 			if content.ID != "" && content.Name != "" {
@@ -127,11 +157,14 @@ func (p *StreamParser) parseAssistant(msg *ccMessage) []api.TaskEvent {
 				Sequence:  p.sequence,
 				Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
 				Type:      api.EventTypeToolCall,
-				Summary:   toolCallSummary(content.Name, content.Input),
+				Summary:   p.toolCallSummary(content.Name, content.Input),
 				Tool:      content.Name,
 			}
 			if content.Input != nil {
-				event.Input = condensedInput(content.Name, content.Input)
+				event.Input = p.condensedInput(content.Name, content.Input)
+			}
+			if command, ok := bashCommand(content.Name, content.Input); ok {
+				event = p.attachFull(event, command)
 			}
 			events = append(events, event)
 		}
@@ -155,17 +188,17 @@ func (p *StreamParser) parseUser(msg *ccMessage) []api.TaskEvent {
 
 		resultText := extractToolResultText(content.Content)
 
-		events = append(events, api.TaskEvent{
+		events = append(events, p.attachFull(api.TaskEvent{
 			Sequence:  p.sequence,
 			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
 			Type:      api.EventTypeToolResult,
-			Summary:   truncate(resultText, maxResultLen),
+			Summary:   p.truncate(resultText, p.limits.Result),
 			Tool:      toolName,
 			Output: &api.TaskEventOutput{
 				Success: !content.IsError,
-				Summary: truncate(resultText, maxResultLen),
+				Summary: p.truncate(resultText, p.limits.Result),
 			},
-		})
+		}, resultText))
 	}
 	return events
 }
@@ -190,8 +223,31 @@ func (p *StreamParser) errorEvent(message string) []api.TaskEvent {
 	}}
 }
 
+// attachFull adds full to the metadata of e if the limits ask for it and
+// e's summary is a truncated copy of it.
+func (p *StreamParser) attachFull(e api.TaskEvent, full string) api.TaskEvent {
+	if !p.limits.AttachFullContent || e.Summary == full {
+		return e
+	}
+	if e.Metadata == nil {
+		e.Metadata = map[string]any{}
+	}
+	e.Metadata[fullContentKey] = full
+	return e
+}
+
+// bashCommand returns the command of a Bash tool call.
+func bashCommand(toolName string, input any) (string, bool) {
+	inputMap, ok := toStringMap(input)
+	if !ok || toolName != "Bash" {
+		return "", false
+	}
+	command, ok := inputMap["command"].(string)
+	return command, ok
+}
+
 // toolCallSummary generates a human-readable one-liner for a tool call.
-func toolCallSummary(toolName string, input any) string {
+func (p *StreamParser) toolCallSummary(toolName string, input any) string {
 	inputMap, ok := toStringMap(input)
 	if !ok {
 		return toolName
@@ -212,7 +268,7 @@ func toolCallSummary(toolName string, input any) string {
 		}
 	case "Bash":
 		if cmd, ok := inputMap["command"].(string); ok {
-			return truncate(cmd, maxBashInputLen)
+			return p.truncate(cmd, p.limits.BashInput)
 		}
 	case "Glob":
 		if pattern, ok := inputMap["pattern"].(string); ok {
@@ -227,7 +283,7 @@ func toolCallSummary(toolName string, input any) string {
 }
 
 // condensedInput returns a truncated representation of tool input for the event.
-func condensedInput(toolName string, input any) map[string]any {
+func (p *StreamParser) condensedInput(toolName string, input any) map[string]any {
 	inputMap, ok := toStringMap(input)
 	if !ok {
 		return nil
@@ -237,7 +293,7 @@ func condensedInput(toolName string, input any) map[string]any {
 	case "Bash":
 		result := make(map[string]any)
 		if cmd, ok := inputMap["command"].(string); ok {
-			result["command"] = truncate(cmd, maxBashInputLen)
+			result["command"] = p.truncate(cmd, p.limits.BashInput)
 		}
 		return result
 	case "Read", "Write", "Glob", "Grep":
@@ -245,7 +301,7 @@ func condensedInput(toolName string, input any) map[string]any {
 		result := make(map[string]any)
 		for k, v := range inputMap {
 			if s, ok := v.(string); ok {
-				result[k] = truncate(s, maxResultLen)
+				result[k] = p.truncate(s, p.limits.Result)
 			} else {
 				result[k] = v
 			}
@@ -268,7 +324,7 @@ func condensedInput(toolName string, input any) map[string]any {
 		result := make(map[string]any)
 		for k, v := range inputMap {
 			if s, ok := v.(string); ok {
-				result[k] = truncate(s, maxEditSummaryLen)
+				result[k] = p.truncate(s, p.limits.EditSummary)
 			} else {
 				result[k] = v
 			}
@@ -301,16 +357,21 @@ func extractToolResultText(content any) string {
 	return ""
 }
 
-func truncate(s string, maxLen int) string {
+// truncate shortens s to maxLen runes with the parser's suffix.
+func (p *StreamParser) truncate(s string, maxLen int) string {
+	return truncate(s, maxLen, p.limits.Suffix)
+}
+
+func truncate(s string, maxLen int, suffix string) string {
 	runes := []rune(s)
 	if len(runes) <= maxLen {
 		return s
 	}
-	suffixLen := len([]rune(truncationSuffix))
+	suffixLen := len([]rune(suffix))
 	if maxLen <= suffixLen {
 		return string(runes[:maxLen])
 	}
-	return string(runes[:maxLen-suffixLen]) + truncationSuffix
+	return string(runes[:maxLen-suffixLen]) + suffix
 }
 
 func toStringMap(v any) (map[string]any, bool) {
@@ -12,7 +12,7 @@ import (
 )
 
 func TestParseAssistantThinking(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	line := mustJSON(t, map[string]any{
 		"type": "assistant",
 		"message": map[string]any{
@@ -31,7 +31,7 @@ func TestParseAssistantThinking(t *testing.T) {
 }
 
 func TestParseAssistantToolUse(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	line := mustJSON(t, map[string]any{
 		"type": "assistant",
 		"message": map[string]any{
@@ -56,7 +56,7 @@ func TestParseAssistantToolUse(t *testing.T) {
 }
 
 func TestParseAssistantMixedContent(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	line := mustJSON(t, map[string]any{
 		"type": "assistant",
 		"message": map[string]any{
@@ -81,7 +81,7 @@ func TestParseAssistantMixedContent(t *testing.T) {
 }
 
 func TestParseUserToolResult(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 
 	// First, register the tool call so the result can be correlated
 	p.ParseLine(mustJSON(t, map[string]any{
@@ -121,7 +121,7 @@ func TestParseUserToolResult(t *testing.T) {
 }
 
 func TestParseUserToolResultError(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 
 	p.ParseLine(mustJSON(t, map[string]any{
 		"type": "assistant",
@@ -158,7 +158,7 @@ func TestParseUserToolResultError(t *testing.T) {
 }
 
 func TestParseResultMessage(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	line := mustJSON(t, map[string]any{
 		"type":           "result",
 		"subtype":        "success",
@@ -181,7 +181,7 @@ func TestParseResultMessage(t *testing.T) {
 }
 
 func TestParseSystemMessage(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	line := mustJSON(t, map[string]any{
 		"type":    "system",
 		"subtype": "init",
@@ -192,7 +192,7 @@ func TestParseSystemMessage(t *testing.T) {
 }
 
 func TestSequenceMonotonicallyIncreases(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 
 	for range 5 {
 		p.ParseLine(mustJSON(t, map[string]any{
@@ -219,7 +219,7 @@ func TestSequenceMonotonicallyIncreases(t *testing.T) {
 }
 
 func TestParseMalformedJSON(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	events := p.ParseLine([]byte(`{this is not valid json`))
 	require.Len(t, events, 1)
 	assert.Equal(t, api.EventTypeError, events[0].Type)
@@ -227,13 +227,13 @@ func TestParseMalformedJSON(t *testing.T) {
 }
 
 func TestParseEmptyLine(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	events := p.ParseLine([]byte(""))
 	assert.Empty(t, events)
 }
 
 func TestParseNilMessage(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	line := mustJSON(t, map[string]any{
 		"type": "assistant",
 		// no message field
@@ -295,7 +295,7 @@ func TestToolCallSummaries(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := toolCallSummary(tt.toolName, tt.input)
+			got := NewStreamParser(TruncationLimits{}).toolCallSummary(tt.toolName, tt.input)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -303,7 +303,7 @@ func TestToolCallSummaries(t *testing.T) {
 
 func TestTruncation(t *testing.T) {
 	t.Run("short string unchanged", func(t *testing.T) {
-		assert.Equal(t, "hello", truncate("hello", 200))
+		assert.Equal(t, "hello", truncate("hello", 200, truncationSuffix))
 	})
 
 	t.Run("long string truncated", func(t *testing.T) {
@@ -311,7 +311,7 @@ func TestTruncation(t *testing.T) {
 		for i := range long {
 			long[i] = 'a'
 		}
-		result := truncate(string(long), 200)
+		result := truncate(string(long), 200, truncationSuffix)
 		assert.Len(t, result, 200)
 		assert.Contains(t, result, truncationSuffix)
 	})
@@ -324,7 +324,7 @@ func TestTruncation(t *testing.T) {
 			s.WriteString(emoji)
 		}
 		// Truncate to 20 runes — must not split any multi-byte character.
-		result := truncate(s.String(), 20)
+		result := truncate(s.String(), 20, truncationSuffix)
 		runes := []rune(result)
 		assert.LessOrEqual(t, len(runes), 20)
 		assert.Contains(t, result, truncationSuffix)
@@ -340,7 +340,7 @@ func TestCondensedInputEdit(t *testing.T) {
 		"new_string": "func new() { return nil }",
 	}
 
-	result := condensedInput("Edit", input)
+	result := NewStreamParser(TruncationLimits{}).condensedInput("Edit", input)
 	assert.Equal(t, "src/main.go", result["file_path"])
 	assert.Equal(t, len("func old() {}"), result["old_string_length"])
 	assert.Equal(t, len("func new() { return nil }"), result["new_string_length"])
@@ -358,14 +358,14 @@ func TestCondensedInputBash(t *testing.T) {
 		"command": string(longCmd),
 	}
 
-	result := condensedInput("Bash", input)
+	result := NewStreamParser(TruncationLimits{}).condensedInput("Bash", input)
 	cmd, ok := result["command"].(string)
 	require.True(t, ok)
 	assert.LessOrEqual(t, len(cmd), maxBashInputLen)
 }
 
 func TestToolResultCorrelation(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 
 	// Register two tool calls
 	p.ParseLine(mustJSON(t, map[string]any{
@@ -414,7 +414,7 @@ func TestExtractToolResultTextStructured(t *testing.T) {
 }
 
 func TestThinkingTruncation(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	longText := make([]byte, 500)
 	for i := range longText {
 		longText[i] = 'a'
@@ -435,8 +435,53 @@ func TestThinkingTruncation(t *testing.T) {
 	assert.Contains(t, events[0].Summary, truncationSuffix)
 }
 
+func TestTruncationLimits(t *testing.T) {
+	long := strings.Repeat("a", 50)
+	lines := [][]byte{
+		mustJSON(t, map[string]any{
+			"type":    "assistant",
+			"message": map[string]any{"content": []any{map[string]any{"type": "text", "text": long}}},
+		}),
+		mustJSON(t, map[string]any{
+			"type": "assistant",
+			"message": map[string]any{"content": []any{map[string]any{
+				"type": "tool_use", "id": "toolu_1", "name": "Bash", "input": map[string]any{"command": "ls"},
+			}}},
+		}),
+		mustJSON(t, map[string]any{
+			"type": "user",
+			"message": map[string]any{"content": []any{map[string]any{
+				"type": "tool_result", "tool_use_id": "toolu_1", "content": long,
+			}}},
+		}),
+	}
+	parse := func(limits TruncationLimits) []api.TaskEvent {
+		p := NewStreamParser(limits)
+		var events []api.TaskEvent
+		for _, line := range lines {
+			events = append(events, p.ParseLine(line)...)
+		}
+		require.Len(t, events, 3)
+		return events
+	}
+
+	t.Run("custom limits and suffix", func(t *testing.T) {
+		events := parse(TruncationLimits{Thinking: 10, Result: 20, Suffix: "…"})
+		assert.Equal(t, "aaaaaaaaa…", events[0].Summary)
+		assert.Len(t, []rune(events[2].Summary), 20)
+		assert.Nil(t, events[0].Metadata, "full content is not attached by default")
+	})
+
+	t.Run("attach full content", func(t *testing.T) {
+		events := parse(TruncationLimits{Thinking: 10, Result: 20, AttachFullContent: true})
+		assert.Equal(t, long, events[0].Metadata[fullContentKey])
+		assert.Nil(t, events[1].Metadata, "untruncated command needs no attachment")
+		assert.Equal(t, long, events[2].Metadata[fullContentKey])
+	})
+}
+
 func TestEmptyTextSkipped(t *testing.T) {
-	p := NewStreamParser()
+	p := NewStreamParser(TruncationLimits{})
 	line := mustJSON(t, map[string]any{
 		"type": "assistant",
 		"message": map[string]any{
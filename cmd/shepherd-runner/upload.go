@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+const (
+	// statusQueueSize and outputQueueSize bound the batches waiting in each
+	// upload lane.
+	statusQueueSize = 256
+	outputQueueSize = 32
+	// maxUploadBatch caps the events a lane merges into one POST.
+	maxUploadBatch = 100
+	// uploadFlushTimeout bounds how long a finished run waits for queued
+	// events to be posted.
+	uploadFlushTimeout = 10 * time.Second
+)
+
+// eventUploader posts a task's events in the background on two independent
+// lanes, so a burst of large tool outputs cannot delay the small status
+// events the UI and the operator act on. Tool results go to the output
+// lane; everything else goes to the status lane.
+//
+// Each lane is a bounded queue drained by its own worker, which merges
+// queued batches into one POST. When the status lane is full, Enqueue waits
+// for room, slowing the stream parser down rather than losing a status
+// event. When the output lane is full, its events are dropped and counted.
+// The API orders events by sequence, so lanes may deliver out of order.
+type eventUploader struct {
+	status *uploadLane
+	output *uploadLane
+}
+
+type uploadLane struct {
+	name    string
+	queue   chan []api.TaskEvent
+	block   bool
+	dropped atomic.Int64
+	done    chan struct{}
+}
+
+// newEventUploader starts the lane workers. They post with ctx until Close
+// is called.
+func newEventUploader(ctx context.Context, poster EventPoster, taskID string, log logr.Logger) *eventUploader {
+	u := &eventUploader{
+		status: &uploadLane{name: "status", queue: make(chan []api.TaskEvent, statusQueueSize), block: true},
+		output: &uploadLane{name: "output", queue: make(chan []api.TaskEvent, outputQueueSize)},
+	}
+	for _, lane := range []*uploadLane{u.status, u.output} {
+		lane.done = make(chan struct{})
+		go lane.run(ctx, poster, taskID, log)
+	}
+	return u
+}
+
+// Enqueue queues events for upload, splitting them between the lanes.
+func (u *eventUploader) Enqueue(ctx context.Context, events []api.TaskEvent) {
+	var status, output []api.TaskEvent
+	for _, e := range events {
+		if e.Type == api.EventTypeToolResult {
+			output = append(output, e)
+		} else {
+			status = append(status, e)
+		}
+	}
+	u.status.enqueue(ctx, status)
+	u.output.enqueue(ctx, output)
+}
+
+// Close stops accepting events and waits up to uploadFlushTimeout for the
+// queued ones to be posted. It logs how many events were dropped.
+func (u *eventUploader) Close(log logr.Logger) {
+	timeout := time.After(uploadFlushTimeout)
+	for _, lane := range []*uploadLane{u.status, u.output} {
+		close(lane.queue)
+	}
+	for _, lane := range []*uploadLane{u.status, u.output} {
+		select {
+		case <-lane.done:
+		case <-timeout:
+			log.Info("gave up waiting for events to be posted", "lane", lane.name)
+			return
+		}
+		if n := lane.dropped.Load(); n > 0 {
+			log.Info("dropped events while the upload queue was full", "lane", lane.name, "events", n)
+		}
+	}
+}
+
+func (l *uploadLane) enqueue(ctx context.Context, events []api.TaskEvent) {
+	if len(events) == 0 {
+		return
+	}
+	if l.block {
+		select {
+		case l.queue <- events:
+		case <-ctx.Done():
+			l.dropped.Add(int64(len(events)))
+		}
+		return
+	}
+	select {
+	case l.queue <- events:
+	default:
+		l.dropped.Add(int64(len(events)))
+	}
+}
+
+// run posts the lane's batches until its queue is closed and drained.
+func (l *uploadLane) run(ctx context.Context, poster EventPoster, taskID string, log logr.Logger) {
+	defer close(l.done)
+	for batch := range l.queue {
+		batch = l.merge(batch)
+		if err := poster.PostEvents(ctx, taskID, batch); err != nil {
+			log.Info("failed to post events", "lane", l.name, "events", len(batch), "error", err)
+		}
+	}
+}
+
+// merge appends batches already waiting in the queue to batch, up to
+// maxUploadBatch events.
+func (l *uploadLane) merge(batch []api.TaskEvent) []api.TaskEvent {
+	for len(batch) < maxUploadBatch {
+		select {
+		case more, ok := <-l.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, more...)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
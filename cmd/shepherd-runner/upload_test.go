@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// blockingPoster holds tool result posts until release is closed.
+type blockingPoster struct {
+	release chan struct{}
+	mu      sync.Mutex
+	posted  []api.TaskEvent
+}
+
+func (p *blockingPoster) PostEvents(ctx context.Context, _ string, events []api.TaskEvent) error {
+	if events[0].Type == api.EventTypeToolResult {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	p.mu.Lock()
+	p.posted = append(p.posted, events...)
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *blockingPoster) count(typ api.TaskEventType) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, e := range p.posted {
+		if e.Type == typ {
+			n++
+		}
+	}
+	return n
+}
+
+func TestEventUploader_StatusNotBlockedByOutput(t *testing.T) {
+	ctx := context.Background()
+	poster := &blockingPoster{release: make(chan struct{})}
+	u := newEventUploader(ctx, poster, "task-1", logr.Discard())
+
+	// Fill the output lane well past its capacity while its POST hangs
+	for range outputQueueSize * 2 {
+		u.Enqueue(ctx, []api.TaskEvent{{Type: api.EventTypeToolResult}})
+	}
+	u.Enqueue(ctx, []api.TaskEvent{{Type: api.EventTypeThinking}, {Type: api.EventTypeToolCall}})
+
+	require.Eventually(t, func() bool {
+		return poster.count(api.EventTypeThinking) == 1 && poster.count(api.EventTypeToolCall) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Positive(t, u.output.dropped.Load())
+	assert.Zero(t, u.status.dropped.Load())
+
+	close(poster.release)
+	u.Close(logr.Discard())
+	assert.Equal(t, int64(outputQueueSize*2), int64(poster.count(api.EventTypeToolResult))+u.output.dropped.Load())
+}
+
+func TestEventUploader_CloseFlushes(t *testing.T) {
+	ctx := context.Background()
+	poster := &mockEventPoster{}
+	u := newEventUploader(ctx, poster, "task-1", logr.Discard())
+	for range maxUploadBatch + 10 {
+		u.Enqueue(ctx, []api.TaskEvent{{Type: api.EventTypeThinking}})
+	}
+	u.Close(logr.Discard())
+
+	poster.mu.Lock()
+	defer poster.mu.Unlock()
+	total := 0
+	for _, call := range poster.calls {
+		assert.LessOrEqual(t, len(call), maxUploadBatch)
+		total += len(call)
+	}
+	assert.Equal(t, maxUploadBatch+10, total)
+}
@@ -17,36 +17,246 @@ limitations under the License.
 package main
 
 import (
+	"crypto/ed25519"
 	"fmt"
+	"net/url"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/archive"
+	"github.com/NissesSenap/shepherd/pkg/forge"
+	"github.com/NissesSenap/shepherd/pkg/policy"
+	"github.com/NissesSenap/shepherd/pkg/validate"
 )
 
 type APICmd struct {
-	ListenAddr           string `help:"Public API listen address" default:":8080" env:"SHEPHERD_API_ADDR"`
-	InternalListenAddr   string `help:"Internal (runner) API listen address" default:":8081" env:"SHEPHERD_INTERNAL_API_ADDR"`
-	CallbackSecret       string `help:"HMAC secret for adapter callbacks" env:"SHEPHERD_CALLBACK_SECRET"`
-	Namespace            string `help:"Namespace for task creation" default:"shepherd" env:"SHEPHERD_NAMESPACE"`
-	GithubAppID          int64  `help:"GitHub Runner App ID" env:"SHEPHERD_GITHUB_APP_ID"`
-	GithubInstallationID int64  `help:"GitHub Installation ID" env:"SHEPHERD_GITHUB_INSTALLATION_ID"`
-	GithubPrivateKeyPath string `help:"Path to Runner App private key" env:"SHEPHERD_GITHUB_PRIVATE_KEY_PATH"`
+	ListenAddr            string `help:"Public API listen address: host:port, unix:/path/to.sock or systemd:[name]" default:":8080" env:"SHEPHERD_API_ADDR"`
+	InternalListenAddr    string `help:"Internal (runner) API listen address: host:port, unix:/path/to.sock or systemd:[name]" default:":8081" env:"SHEPHERD_INTERNAL_API_ADDR"`
+	MetricsAddr           string `help:"Prometheus metrics listen address: host:port, unix:/path/to.sock or systemd:[name] (empty = disabled)" default:":9090" env:"SHEPHERD_METRICS_ADDR"`
+	DebugEndpoints        bool   `help:"Serve pprof and expvar endpoints on --debug-addr" env:"SHEPHERD_DEBUG_ENDPOINTS"`
+	DebugAddr             string `help:"Debug endpoints listen address: host:port, unix:/path/to.sock or systemd:[name]" default:"${debugAddr}" env:"SHEPHERD_DEBUG_ADDR"`
+	CallbackSecret        string `help:"HMAC secret for adapter callbacks" env:"SHEPHERD_CALLBACK_SECRET"`
+	CallbackFormat        string `help:"Format of callbacks for tasks that do not choose one: json or cloudevents" default:"json" enum:"json,cloudevents" env:"SHEPHERD_CALLBACK_FORMAT"`
+	Namespace             string `help:"Namespace for task creation" default:"shepherd" env:"SHEPHERD_NAMESPACE"`
+	GithubAppID           int64  `help:"GitHub Runner App ID" env:"SHEPHERD_GITHUB_APP_ID"`
+	GithubInstallationID  int64  `help:"GitHub Installation ID (0 = look up the installation of each task's repository)" env:"SHEPHERD_GITHUB_INSTALLATION_ID"`
+	GithubPrivateKeyPath  string `help:"Path to Runner App private key" env:"SHEPHERD_GITHUB_PRIVATE_KEY_PATH"`
+	GithubURL             string `help:"GitHub Enterprise Server URL, e.g. https://ghe.example.com (empty = github.com)" env:"SHEPHERD_GITHUB_URL"`
+	MaxActiveTasksPerRepo int    `help:"Maximum active tasks per repository (0 = unlimited)" default:"0" env:"SHEPHERD_MAX_ACTIVE_TASKS_PER_REPO"`
+	MaxActiveTasksPerOrg  int    `help:"Maximum active tasks per repository owner (0 = unlimited)" default:"0" env:"SHEPHERD_MAX_ACTIVE_TASKS_PER_ORG"`
+	MaxActiveTasks        int    `help:"Maximum active tasks in the namespace (0 = unlimited)" default:"0" env:"SHEPHERD_MAX_ACTIVE_TASKS"`
+	BasePath              string `help:"Path prefix to serve the public API under, e.g. /shepherd" env:"SHEPHERD_API_BASE_PATH"`
+	AdminAPI              bool   `name:"admin-api" help:"Serve the bulk cancel and delete endpoints under /api/v1/admin" env:"SHEPHERD_ADMIN_API"`
+	GRPC                  bool   `name:"grpc" help:"Also serve the gRPC API on the public and internal ports" env:"SHEPHERD_API_GRPC"`
+	TaskIndexSize         int    `help:"Task sources, such as issues, whose latest task is remembered for active task lookups (0 = no index)" default:"1024" env:"SHEPHERD_TASK_INDEX_SIZE"`
+
+	GiteaURL       string `help:"Gitea or Forgejo URL, e.g. https://gitea.example.com; tasks for repositories on its host get the token from --gitea-token-file" env:"SHEPHERD_GITEA_URL"`
+	GiteaTokenFile string `help:"File holding the Gitea or Forgejo access token handed to runners" type:"existingfile" env:"SHEPHERD_GITEA_TOKEN_FILE"`
+
+	MaxTimeoutExtension time.Duration `help:"Total time the timeout of a task may be extended by through the API (0 = no extensions)" default:"1h" env:"SHEPHERD_MAX_TIMEOUT_EXTENSION"`
+
+	DashboardURL string `help:"Base URL of the web frontend, to link to tasks from callbacks, e.g. https://shepherd.example.com" env:"SHEPHERD_DASHBOARD_URL"`
+	LogsURL      string `help:"URL of a task's logs in your log viewer, with {taskID} where the task ID goes; linked to from callbacks" env:"SHEPHERD_LOGS_URL"`
+
+	CallbackSecretSecondary string `help:"Second HMAC secret that also signs adapter callbacks, for rotating the callback secret" env:"SHEPHERD_CALLBACK_SECRET_SECONDARY"`
+	CallbackSigningKey      string `help:"Ed25519 private key (PKCS #8 PEM) that also signs adapter callbacks; adapters verify them with its public key" type:"existingfile" env:"SHEPHERD_CALLBACK_SIGNING_KEY"`
+
+	CallbackAllowedHosts  []string `help:"Host patterns callbacks may be sent to, e.g. *.svc.cluster.local,*.mycorp.com (empty = any)" env:"SHEPHERD_CALLBACK_ALLOWED_HOSTS"`
+	CallbackDeniedHosts   []string `help:"Host patterns callbacks are never sent to" default:"169.254.169.254,localhost,127.0.0.1,::1,0.0.0.0" env:"SHEPHERD_CALLBACK_DENIED_HOSTS"`
+	CallbackInternalHosts []string `help:"Host patterns and CIDR ranges callbacks may reach although they resolve to internal addresses, e.g. shepherd-github.shepherd,10.96.0.0/12" env:"SHEPHERD_CALLBACK_INTERNAL_HOSTS"`
+
+	AllowedSandboxTemplates []string `help:"Sandbox templates tasks may use (empty = any)" env:"SHEPHERD_ALLOWED_SANDBOX_TEMPLATES"`
+
+	RepoSizeLimits  map[string]string `help:"Largest repository a sandbox template can clone, as template=size pairs (e.g. default=2Gi,large=20Gi); needs the GitHub App" mapsep:"," env:"SHEPHERD_REPO_SIZE_LIMITS"`
+	RepoSizeUpgrade bool              `help:"Move tasks for repositories over their template's limit to the smallest template that fits, instead of rejecting them" env:"SHEPHERD_REPO_SIZE_UPGRADE"`
+
+	EventPrivacy        map[string]string `help:"Event privacy level of repositories, as owner/repo=level or owner=level pairs (e.g. acme/payments=minimal,secret-org=summaries-only)" mapsep:"," env:"SHEPHERD_EVENT_PRIVACY"`
+	DefaultEventPrivacy string            `help:"Event privacy level of repositories without one: full, summaries-only or minimal" default:"full" enum:"full,summaries-only,minimal" env:"SHEPHERD_DEFAULT_EVENT_PRIVACY"`
+
+	ArchiveBucket          string `help:"S3-compatible bucket to archive finished tasks to (empty = no archive)" env:"SHEPHERD_ARCHIVE_BUCKET"`
+	ArchiveEndpoint        string `help:"S3 API endpoint of the archive, e.g. https://s3.eu-west-1.amazonaws.com or https://storage.googleapis.com" default:"https://s3.amazonaws.com" env:"SHEPHERD_ARCHIVE_ENDPOINT"`
+	ArchiveRegion          string `help:"Signing region of the archive bucket" default:"us-east-1" env:"SHEPHERD_ARCHIVE_REGION"`
+	ArchivePrefix          string `help:"Key prefix for archived tasks, e.g. shepherd/" env:"SHEPHERD_ARCHIVE_PREFIX"`
+	ArchivePathStyle       bool   `help:"Address the bucket in the URL path instead of the host name (needed by most MinIO setups)" env:"SHEPHERD_ARCHIVE_PATH_STYLE"`
+	ArchiveAccessKeyID     string `help:"Access key ID for the archive bucket" env:"SHEPHERD_ARCHIVE_ACCESS_KEY_ID"`
+	ArchiveSecretAccessKey string `help:"Secret access key for the archive bucket" env:"SHEPHERD_ARCHIVE_SECRET_ACCESS_KEY"`
+
+	PolicyFile     string `help:"File of CEL policies new tasks must pass" type:"existingfile" env:"SHEPHERD_POLICY_FILE"`
+	PolicyOPAURL   string `name:"policy-opa-url" help:"OPA Data API URL of the task admission decision, e.g. http://opa:8181/v1/data/shepherd/admission" env:"SHEPHERD_POLICY_OPA_URL"`
+	PolicyFailOpen bool   `help:"Create tasks when a policy cannot be evaluated, instead of rejecting them" env:"SHEPHERD_POLICY_FAIL_OPEN"`
 }
 
 func (c *APICmd) Run(_ *CLI) error {
+	// Without github-installation-id, each repository's installation of the
+	// App is looked up.
 	githubFlagsSet := c.GithubAppID != 0 || c.GithubInstallationID != 0 || c.GithubPrivateKeyPath != ""
 	if githubFlagsSet {
-		if c.GithubAppID == 0 || c.GithubInstallationID == 0 || c.GithubPrivateKeyPath == "" {
-			return fmt.Errorf("github-app-id, github-installation-id, and github-private-key-path must all be set together")
+		if c.GithubAppID == 0 || c.GithubPrivateKeyPath == "" {
+			return fmt.Errorf("github-app-id and github-private-key-path must be set together")
+		}
+	}
+	if c.GithubURL != "" {
+		if err := forge.ValidateURL(c.GithubURL); err != nil {
+			return fmt.Errorf("--github-url: %w", err)
 		}
 	}
 
+	if (c.GiteaURL == "") != (c.GiteaTokenFile == "") {
+		return fmt.Errorf("--gitea-url and --gitea-token-file must be set together")
+	}
+	if c.GiteaURL != "" {
+		if err := forge.ValidateURL(c.GiteaURL); err != nil {
+			return fmt.Errorf("--gitea-url: %w", err)
+		}
+	}
+
+	if c.MaxActiveTasksPerRepo < 0 {
+		return fmt.Errorf("--max-active-tasks-per-repo must not be negative, got %d", c.MaxActiveTasksPerRepo)
+	}
+	if c.MaxActiveTasksPerOrg < 0 {
+		return fmt.Errorf("--max-active-tasks-per-org must not be negative, got %d", c.MaxActiveTasksPerOrg)
+	}
+	if c.TaskIndexSize < 0 {
+		return fmt.Errorf("--task-index-size must not be negative, got %d", c.TaskIndexSize)
+	}
+	if c.MaxActiveTasks < 0 {
+		return fmt.Errorf("--max-active-tasks must not be negative, got %d", c.MaxActiveTasks)
+	}
+	if c.MaxTimeoutExtension < 0 {
+		return fmt.Errorf("--max-timeout-extension must not be negative, got %s", c.MaxTimeoutExtension)
+	}
+
+	repoSizeBudgets := make(map[string]int64, len(c.RepoSizeLimits))
+	for template, limit := range c.RepoSizeLimits {
+		q, err := resource.ParseQuantity(limit)
+		if err != nil || q.Sign() <= 0 {
+			return fmt.Errorf("--repo-size-limits: invalid size %q for template %s", limit, template)
+		}
+		repoSizeBudgets[template] = q.Value()
+	}
+	if len(repoSizeBudgets) > 0 && !githubFlagsSet {
+		return fmt.Errorf("--repo-size-limits needs the GitHub App flags to look up repository sizes")
+	}
+
+	for repo, level := range c.EventPrivacy {
+		if !api.ValidEventPrivacy(level) {
+			return fmt.Errorf("--event-privacy: invalid level %q for %s, must be full, summaries-only or minimal", level, repo)
+		}
+	}
+
+	for flag, value := range map[string]string{"dashboard-url": c.DashboardURL, "logs-url": c.LogsURL} {
+		if value == "" {
+			continue
+		}
+		if err := checkHTTPURL(flag, value); err != nil {
+			return err
+		}
+	}
+
+	var signingKey ed25519.PrivateKey
+	if c.CallbackSigningKey != "" {
+		var err error
+		if signingKey, err = api.LoadCallbackSigningKey(c.CallbackSigningKey); err != nil {
+			return err
+		}
+	}
+
+	var store archive.Archiver
+	if c.ArchiveBucket != "" {
+		s3, err := archive.NewS3(archive.S3Options{
+			Endpoint:        c.ArchiveEndpoint,
+			Region:          c.ArchiveRegion,
+			Bucket:          c.ArchiveBucket,
+			Prefix:          c.ArchivePrefix,
+			PathStyle:       c.ArchivePathStyle,
+			AccessKeyID:     c.ArchiveAccessKeyID,
+			SecretAccessKey: c.ArchiveSecretAccessKey,
+		})
+		if err != nil {
+			return fmt.Errorf("configuring archive: %w", err)
+		}
+		store = s3
+	}
+
+	var policies policy.Chain
+	if c.PolicyFile != "" {
+		cel, err := policy.LoadCEL(c.PolicyFile)
+		if err != nil {
+			return fmt.Errorf("loading policy file: %w", err)
+		}
+		policies = append(policies, cel)
+	}
+	if c.PolicyOPAURL != "" {
+		if err := checkHTTPURL("policy-opa-url", c.PolicyOPAURL); err != nil {
+			return err
+		}
+		policies = append(policies, policy.NewOPA(c.PolicyOPAURL))
+	}
+	var evaluator policy.Evaluator
+	if len(policies) > 0 {
+		evaluator = policies
+	}
+
 	return api.Run(api.Options{
 		ListenAddr:           c.ListenAddr,
 		InternalListenAddr:   c.InternalListenAddr,
+		MetricsListenAddr:    c.MetricsAddr,
+		DebugListenAddr:      debugAddr(c.DebugEndpoints, c.DebugAddr),
 		CallbackSecret:       c.CallbackSecret,
+		CallbackFormat:       c.CallbackFormat,
 		Namespace:            c.Namespace,
 		GithubAppID:          c.GithubAppID,
 		GithubInstallationID: c.GithubInstallationID,
 		GithubPrivateKeyPath: c.GithubPrivateKeyPath,
+		GithubURL:            c.GithubURL,
+		GiteaURL:             c.GiteaURL,
+		GiteaTokenPath:       c.GiteaTokenFile,
+		BasePath:             c.BasePath,
+		AdminAPI:             c.AdminAPI,
+		GRPC:                 c.GRPC,
+		TaskIndexSize:        c.TaskIndexSize,
+		MaxTimeoutExtension:  c.MaxTimeoutExtension,
+		Archive:              store,
+		Policy:               evaluator,
+		PolicyFailOpen:       c.PolicyFailOpen,
+		Validation: validate.Options{
+			AllowedSandboxTemplates: c.AllowedSandboxTemplates,
+		},
+		Links: api.LinkOptions{
+			DashboardURL: c.DashboardURL,
+			LogsURL:      c.LogsURL,
+		},
+		CallbackSecondarySecret: c.CallbackSecretSecondary,
+		CallbackSigningKey:      signingKey,
+		CallbackTargets: validate.CallbackTargetOptions{
+			Allowed:  c.CallbackAllowedHosts,
+			Denied:   c.CallbackDeniedHosts,
+			Internal: c.CallbackInternalHosts,
+		},
+		RepoSize: api.RepoSizeLimits{
+			Budgets: repoSizeBudgets,
+			Upgrade: c.RepoSizeUpgrade,
+		},
+		EventPrivacy: api.EventPrivacyOptions{
+			Default: c.DefaultEventPrivacy,
+			Repos:   c.EventPrivacy,
+		},
+		Quota: api.TaskQuota{
+			PerRepo:      c.MaxActiveTasksPerRepo,
+			PerOrg:       c.MaxActiveTasksPerOrg,
+			PerNamespace: c.MaxActiveTasks,
+		},
 	})
 }
+
+// checkHTTPURL returns an error naming flag unless value is an absolute
+// http(s) URL.
+func checkHTTPURL(flag, value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid --%s %q: must be an http(s) URL", flag, value)
+	}
+	return nil
+}
@@ -17,36 +17,121 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/alecthomas/kong"
-	zapraw "go.uber.org/zap/zapcore"
 	"sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"github.com/NissesSenap/shepherd/pkg/adapters/gitea"
 	"github.com/NissesSenap/shepherd/pkg/adapters/github"
+	"github.com/NissesSenap/shepherd/pkg/adapters/linear"
+	"github.com/NissesSenap/shepherd/pkg/adapters/slack"
+	"github.com/NissesSenap/shepherd/pkg/debug"
+	"github.com/NissesSenap/shepherd/pkg/forge"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
 )
 
 type CLI struct {
 	API      APICmd      `cmd:"" help:"Run API server"`
 	Operator OperatorCmd `cmd:"" help:"Run K8s operator"`
 	GitHub   GitHubCmd   `cmd:"" name:"github" help:"Run GitHub adapter"`
+	Gitea    GiteaCmd    `cmd:"" name:"gitea" help:"Run Gitea/Forgejo adapter"`
+	Slack    SlackCmd    `cmd:"" name:"slack" help:"Run Slack adapter"`
+	Linear   LinearCmd   `cmd:"" name:"linear" help:"Run Linear adapter"`
+	Replay   ReplayCmd   `cmd:"" help:"Replay recorded sandbox status transitions through the task reconciler"`
+	Seed     SeedCmd     `cmd:"" help:"Create a demo SandboxTemplate and task and follow the task to completion"`
 
-	LogLevel int  `help:"Log level (0=info, 1=debug)" default:"0"`
-	DevMode  bool `help:"Enable development mode logging" default:"false"`
+	LogLevel  int    `help:"Log level (0=info, 1=debug)" default:"0"`
+	LogFormat string `help:"Log format: text or json" default:"text" enum:"text,json" env:"SHEPHERD_LOG_FORMAT"`
+	DevMode   bool   `help:"Enable development mode logging" default:"false"`
+
+	TracingEndpoint    string  `help:"OTLP/HTTP endpoint traces are exported to, e.g. http://otel-collector:4318 (empty = no export)" env:"SHEPHERD_TRACING_ENDPOINT"`
+	TracingSampleRatio float64 `help:"Fraction of new traces that are sampled (0-1)" default:"1" env:"SHEPHERD_TRACING_SAMPLE_RATIO"`
 }
 
 type GitHubCmd struct {
-	ListenAddr             string `help:"GitHub adapter listen address" default:":8082" env:"SHEPHERD_GITHUB_ADDR"`
-	WebhookSecret          string `help:"GitHub webhook secret" env:"SHEPHERD_GITHUB_WEBHOOK_SECRET"`
-	GithubAppID            int64  `help:"GitHub App ID" env:"SHEPHERD_GITHUB_APP_ID"`
-	GithubInstallationID   int64  `help:"GitHub Installation ID" env:"SHEPHERD_GITHUB_INSTALLATION_ID"`
-	GithubPrivateKeyPath   string `help:"Path to GitHub App private key" env:"SHEPHERD_GITHUB_PRIVATE_KEY_PATH"`
-	APIURL                 string `help:"Shepherd API URL" required:"" env:"SHEPHERD_API_URL"`
-	CallbackSecret         string `help:"Shared secret for callback verification" env:"SHEPHERD_CALLBACK_SECRET"`
-	CallbackURL            string `help:"Callback URL for API to call back" env:"SHEPHERD_CALLBACK_URL"`
-	DefaultSandboxTemplate string `help:"Default sandbox template" default:"default"`
+	ListenAddr             string        `help:"GitHub adapter listen address: host:port, unix:/path/to.sock or systemd:[name]" default:":8082" env:"SHEPHERD_GITHUB_ADDR"`
+	MetricsAddr            string        `help:"Prometheus metrics listen address: host:port, unix:/path/to.sock or systemd:[name] (empty = disabled)" default:":9090" env:"SHEPHERD_METRICS_ADDR"`
+	DebugEndpoints         bool          `help:"Serve pprof and expvar endpoints on --debug-addr" env:"SHEPHERD_DEBUG_ENDPOINTS"`
+	DebugAddr              string        `help:"Debug endpoints listen address: host:port, unix:/path/to.sock or systemd:[name]" default:"${debugAddr}" env:"SHEPHERD_DEBUG_ADDR"`
+	WebhookSecret          string        `help:"GitHub webhook secret" env:"SHEPHERD_GITHUB_WEBHOOK_SECRET"`
+	GithubAppID            int64         `help:"GitHub App ID" env:"SHEPHERD_GITHUB_APP_ID"`
+	GithubInstallationID   int64         `help:"GitHub Installation ID (0 = look up the installation of each repository)" env:"SHEPHERD_GITHUB_INSTALLATION_ID"`
+	GithubPrivateKeyPath   string        `help:"Path to GitHub App private key" env:"SHEPHERD_GITHUB_PRIVATE_KEY_PATH"`
+	APIURL                 string        `help:"Shepherd API URL" required:"" env:"SHEPHERD_API_URL"`
+	CallbackSecret         string        `help:"Shared secret for callback verification" env:"SHEPHERD_CALLBACK_SECRET"`
+	CallbackURL            string        `help:"Callback URL for API to call back" env:"SHEPHERD_CALLBACK_URL"`
+	DefaultSandboxTemplate string        `help:"Default sandbox template" default:"default"`
+	PRLabels               []string      `help:"Labels to apply to PRs opened by shepherd" env:"SHEPHERD_GITHUB_PR_LABELS"`
+	PRReviewers            []string      `help:"GitHub users to request PR reviews from" env:"SHEPHERD_GITHUB_PR_REVIEWERS"`
+	PRTeamReviewers        []string      `help:"Team slugs to request PR reviews from" env:"SHEPHERD_GITHUB_PR_TEAM_REVIEWERS"`
+	PRCodeowners           bool          `help:"Request PR reviews from CODEOWNERS" env:"SHEPHERD_GITHUB_PR_CODEOWNERS"`
+	PRAutoMerge            bool          `help:"Enable auto-merge on PRs once required checks pass" env:"SHEPHERD_GITHUB_PR_AUTO_MERGE"`
+	PRMergeMethod          string        `help:"Auto-merge method (merge, squash, rebase)" default:"squash" enum:"merge,squash,rebase" env:"SHEPHERD_GITHUB_PR_MERGE_METHOD"`
+	VerifyAfterMerge       bool          `help:"Run a verification task after a shepherd PR is merged" env:"SHEPHERD_GITHUB_VERIFY_AFTER_MERGE"`
+	RepoCacheTTL           time.Duration `help:"How long repository metadata such as the default branch is cached" default:"10m" env:"SHEPHERD_GITHUB_REPO_CACHE_TTL"`
+	IssueContextCacheSize  int           `help:"Number of issues whose task context is kept for later triggers (0 = no cache)" default:"100" env:"SHEPHERD_GITHUB_ISSUE_CONTEXT_CACHE_SIZE"`
+	Digest                 bool          `help:"Post a weekly activity digest to each repository" env:"SHEPHERD_GITHUB_DIGEST"`
+	DigestWeekday          string        `help:"Day of the week the digest is posted" default:"monday" enum:"sunday,monday,tuesday,wednesday,thursday,friday,saturday" env:"SHEPHERD_GITHUB_DIGEST_WEEKDAY"`
+	DigestHour             int           `help:"Hour of day (UTC) the digest is posted" default:"9" env:"SHEPHERD_GITHUB_DIGEST_HOUR"`
+	EventTimeout           time.Duration `help:"How long handling a webhook event or callback may take" default:"2m" env:"SHEPHERD_GITHUB_EVENT_TIMEOUT"`
+	MaxConcurrentEvents    int           `help:"Webhook events, and separately callbacks, handled at once; more are rejected with 503" default:"32" env:"SHEPHERD_GITHUB_MAX_CONCURRENT_EVENTS"`
+	TimeoutWarnings        bool          `help:"Comment on the issue when a task is about to time out" env:"SHEPHERD_GITHUB_TIMEOUT_WARNINGS"`
+	ReconcileWindow        time.Duration `help:"On startup, post the result comments of tasks that finished this long ago at most while the adapter was down (0 = off)" default:"24h" env:"SHEPHERD_GITHUB_RECONCILE_WINDOW"`
+	MentionHandle          string        `help:"Handle whose @mention triggers a task, typically the GitHub App's slug" default:"shepherd" env:"SHEPHERD_GITHUB_MENTION_HANDLE"`
+	MinPermission          string        `help:"Repository permission a commenter needs to trigger a task (none = anyone who can comment)" default:"write" enum:"none,read,triage,write,maintain,admin" env:"SHEPHERD_GITHUB_MIN_PERMISSION"`
+	AllowedRepos           []string      `help:"owner/repo patterns of repositories that may trigger tasks, with globs such as myorg/* (empty = all)" env:"SHEPHERD_GITHUB_ALLOWED_REPOS"`
+	AllowedReposFile       string        `help:"File with more --allowed-repos patterns, one per line" type:"existingfile" env:"SHEPHERD_GITHUB_ALLOWED_REPOS_FILE"`
+	AckReactions           bool          `help:"Acknowledge mentions with a reaction instead of a comment, and react with the outcome when the task finishes" env:"SHEPHERD_GITHUB_ACK_REACTIONS"`
+	LiveProgress           bool          `help:"Edit the acknowledgment comment with the task's progress, and replace it with the result when the task finishes" env:"SHEPHERD_GITHUB_LIVE_PROGRESS"`
+	TriggerLabel           string        `help:"Label that creates a task when applied to an issue, with the issue's body as the description (empty = off)" env:"SHEPHERD_GITHUB_TRIGGER_LABEL"`
+	Assignee               string        `help:"GitHub user, typically a bot account, whose assignment to an issue creates a task and whose unassignment cancels it (empty = off)" env:"SHEPHERD_GITHUB_ASSIGNEE"`
+	RepoConfig             string        `help:"Path of the repository configuration file on the default branch, whose settings replace the adapter defaults (empty = off)" default:".github/shepherd.yaml" env:"SHEPHERD_GITHUB_REPO_CONFIG"`
+	TaskStore              string        `help:"Where task metadata is kept: memory, lost on restart, or configmap, shared by all replicas" default:"memory" enum:"memory,configmap" env:"SHEPHERD_GITHUB_TASK_STORE"`
+	TaskStoreNamespace     string        `help:"Namespace of the configmap task store" default:"shepherd" env:"SHEPHERD_GITHUB_TASK_STORE_NAMESPACE"`
+	TaskStoreConfigMap     string        `help:"Name of the configmap task store's ConfigMap" default:"shepherd-github-tasks" env:"SHEPHERD_GITHUB_TASK_STORE_CONFIGMAP"`
+	DeliveryCacheSize      int           `help:"Number of webhook delivery IDs remembered to skip GitHub's redeliveries (0 = off)" default:"1000" env:"SHEPHERD_GITHUB_DELIVERY_CACHE_SIZE"`
+	DeliveryStore          string        `help:"Where delivery IDs are also kept: memory only, or configmap, which survives restarts and is shared by all replicas" default:"memory" enum:"memory,configmap" env:"SHEPHERD_GITHUB_DELIVERY_STORE"`
+	DeliveryStoreConfigMap string        `help:"Name of the configmap delivery store's ConfigMap, in --task-store-namespace" default:"shepherd-github-deliveries" env:"SHEPHERD_GITHUB_DELIVERY_STORE_CONFIGMAP"`
+
+	CallbackSecretSecondary string `help:"Second secret callbacks may be signed with, for rotating the callback secret" env:"SHEPHERD_CALLBACK_SECRET_SECONDARY"`
+	CallbackPublicKey       string `help:"Ed25519 public keys (PEM) callbacks may be signed with instead of the callback secret, as served by the API at /api/v1/callback-signing-key" type:"existingfile" env:"SHEPHERD_CALLBACK_PUBLIC_KEY"`
+
+	GithubURL       string `help:"GitHub Enterprise Server URL, e.g. https://ghe.example.com (empty = github.com)" env:"SHEPHERD_GITHUB_URL"`
+	GithubUploadURL string `help:"GitHub Enterprise Server upload URL (empty = same as --github-url)" env:"SHEPHERD_GITHUB_UPLOAD_URL"`
+}
+
+// debugAddr returns the listen address of the debug endpoints, or "" when
+// they are disabled.
+func debugAddr(enabled bool, addr string) string {
+	if !enabled {
+		return ""
+	}
+	return addr
+}
+
+// minPermission returns the --min-permission level, or "" to accept
+// mentions from anyone.
+func minPermission(level string) string {
+	if level == "none" {
+		return ""
+	}
+	return level
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
 }
 
 func (c *GitHubCmd) Run(_ *CLI) error {
@@ -56,18 +141,56 @@ func (c *GitHubCmd) Run(_ *CLI) error {
 	if c.GithubAppID == 0 {
 		return fmt.Errorf("github-app-id is required")
 	}
-	if c.GithubInstallationID == 0 {
-		return fmt.Errorf("github-installation-id is required")
-	}
 	if c.GithubPrivateKeyPath == "" {
 		return fmt.Errorf("github-private-key-path is required")
 	}
 	if c.CallbackURL == "" {
 		return fmt.Errorf("callback-url is required")
 	}
+	if c.DigestHour < 0 || c.DigestHour > 23 {
+		return fmt.Errorf("digest-hour must be between 0 and 23, got %d", c.DigestHour)
+	}
+	if c.RepoCacheTTL <= 0 {
+		return fmt.Errorf("repo-cache-ttl must be positive, got %s", c.RepoCacheTTL)
+	}
+	if c.EventTimeout <= 0 {
+		return fmt.Errorf("event-timeout must be positive, got %s", c.EventTimeout)
+	}
+	if c.MaxConcurrentEvents < 1 {
+		return fmt.Errorf("max-concurrent-events must be at least 1, got %d", c.MaxConcurrentEvents)
+	}
+	if c.IssueContextCacheSize < 0 {
+		return fmt.Errorf("issue-context-cache-size must not be negative, got %d", c.IssueContextCacheSize)
+	}
+	if c.DeliveryCacheSize < 0 {
+		return fmt.Errorf("delivery-cache-size must not be negative, got %d", c.DeliveryCacheSize)
+	}
+	if c.DeliveryStore == github.TaskStoreConfigMap && c.DeliveryCacheSize == 0 {
+		return fmt.Errorf("delivery-store=configmap needs a positive delivery-cache-size")
+	}
+	if c.ReconcileWindow < 0 {
+		return fmt.Errorf("reconcile-window must not be negative, got %s", c.ReconcileWindow)
+	}
+	mentionHandle := strings.TrimPrefix(c.MentionHandle, "@")
+	if !github.ValidMentionHandle(mentionHandle) {
+		return fmt.Errorf("mention-handle %q must be a GitHub user name or App slug", c.MentionHandle)
+	}
+	if c.GithubUploadURL != "" && c.GithubURL == "" {
+		return fmt.Errorf("github-upload-url requires github-url")
+	}
+	for flag, raw := range map[string]string{"github-url": c.GithubURL, "github-upload-url": c.GithubUploadURL} {
+		if raw == "" {
+			continue
+		}
+		if err := forge.ValidateURL(raw); err != nil {
+			return fmt.Errorf("%s: %w", flag, err)
+		}
+	}
 
 	return github.Run(github.Options{
 		ListenAddr:             c.ListenAddr,
+		MetricsListenAddr:      c.MetricsAddr,
+		DebugListenAddr:        debugAddr(c.DebugEndpoints, c.DebugAddr),
 		WebhookSecret:          c.WebhookSecret,
 		AppID:                  c.GithubAppID,
 		InstallationID:         c.GithubInstallationID,
@@ -76,6 +199,174 @@ func (c *GitHubCmd) Run(_ *CLI) error {
 		CallbackSecret:         c.CallbackSecret,
 		CallbackURL:            c.CallbackURL,
 		DefaultSandboxTemplate: c.DefaultSandboxTemplate,
+		PR: github.PRConfig{
+			Labels:        c.PRLabels,
+			Reviewers:     c.PRReviewers,
+			TeamReviewers: c.PRTeamReviewers,
+			UseCodeowners: c.PRCodeowners,
+			AutoMerge:     c.PRAutoMerge,
+			MergeMethod:   c.PRMergeMethod,
+		},
+		VerifyAfterMerge:       c.VerifyAfterMerge,
+		RepoCacheTTL:           c.RepoCacheTTL,
+		IssueContextCacheSize:  c.IssueContextCacheSize,
+		EventTimeout:           c.EventTimeout,
+		MaxConcurrentEvents:    c.MaxConcurrentEvents,
+		TimeoutWarnings:        c.TimeoutWarnings,
+		ReconcileWindow:        c.ReconcileWindow,
+		MentionHandle:          mentionHandle,
+		MinPermission:          minPermission(c.MinPermission),
+		AllowedRepos:           c.AllowedRepos,
+		AllowedReposFile:       c.AllowedReposFile,
+		AckReactions:           c.AckReactions,
+		LiveProgress:           c.LiveProgress,
+		TriggerLabel:           c.TriggerLabel,
+		Assignee:               c.Assignee,
+		RepoConfigPath:         c.RepoConfig,
+		TaskStore:              c.TaskStore,
+		TaskStoreNamespace:     c.TaskStoreNamespace,
+		TaskStoreConfigMap:     c.TaskStoreConfigMap,
+		DeliveryCacheSize:      c.DeliveryCacheSize,
+		DeliveryStore:          c.DeliveryStore,
+		DeliveryStoreConfigMap: c.DeliveryStoreConfigMap,
+		Digest: github.DigestConfig{
+			Enabled: c.Digest,
+			Weekday: weekdays[c.DigestWeekday],
+			Hour:    c.DigestHour,
+		},
+		CallbackSecondarySecret: c.CallbackSecretSecondary,
+		CallbackPublicKeyPath:   c.CallbackPublicKey,
+		GithubURL:               c.GithubURL,
+		GithubUploadURL:         c.GithubUploadURL,
+	})
+}
+
+type GiteaCmd struct {
+	ListenAddr             string        `help:"Gitea adapter listen address: host:port, unix:/path/to.sock or systemd:[name]" default:":8083" env:"SHEPHERD_GITEA_ADDR"`
+	GiteaURL               string        `help:"Gitea or Forgejo URL, e.g. https://gitea.example.com" required:"" env:"SHEPHERD_GITEA_URL"`
+	GiteaTokenFile         string        `help:"File holding the access token the adapter comments with" required:"" type:"existingfile" env:"SHEPHERD_GITEA_TOKEN_FILE"`
+	WebhookSecret          string        `help:"Gitea webhook secret" env:"SHEPHERD_GITEA_WEBHOOK_SECRET"`
+	APIURL                 string        `help:"Shepherd API URL" required:"" env:"SHEPHERD_API_URL"`
+	CallbackSecret         string        `help:"Shared secret for callback verification" env:"SHEPHERD_CALLBACK_SECRET"`
+	CallbackURL            string        `help:"Callback URL for API to call back" required:"" env:"SHEPHERD_CALLBACK_URL"`
+	DefaultSandboxTemplate string        `help:"Default sandbox template" default:"default"`
+	EventTimeout           time.Duration `help:"How long handling a webhook event or callback may take" default:"2m" env:"SHEPHERD_GITEA_EVENT_TIMEOUT"`
+
+	CallbackSecretSecondary string `help:"Second secret callbacks may be signed with, for rotating the callback secret" env:"SHEPHERD_CALLBACK_SECRET_SECONDARY"`
+	CallbackPublicKey       string `help:"Ed25519 public keys (PEM) callbacks may be signed with instead of the callback secret, as served by the API at /api/v1/callback-signing-key" type:"existingfile" env:"SHEPHERD_CALLBACK_PUBLIC_KEY"`
+}
+
+func (c *GiteaCmd) Run(_ *CLI) error {
+	if c.WebhookSecret == "" {
+		return fmt.Errorf("webhook-secret is required")
+	}
+	if err := forge.ValidateURL(c.GiteaURL); err != nil {
+		return fmt.Errorf("gitea-url: %w", err)
+	}
+	if c.EventTimeout <= 0 {
+		return fmt.Errorf("event-timeout must be positive, got %s", c.EventTimeout)
+	}
+
+	return gitea.Run(gitea.Options{
+		ListenAddr:              c.ListenAddr,
+		WebhookSecret:           c.WebhookSecret,
+		GiteaURL:                c.GiteaURL,
+		TokenPath:               c.GiteaTokenFile,
+		APIURL:                  c.APIURL,
+		CallbackSecret:          c.CallbackSecret,
+		CallbackURL:             c.CallbackURL,
+		DefaultSandboxTemplate:  c.DefaultSandboxTemplate,
+		EventTimeout:            c.EventTimeout,
+		CallbackSecondarySecret: c.CallbackSecretSecondary,
+		CallbackPublicKeyPath:   c.CallbackPublicKey,
+	})
+}
+
+type SlackCmd struct {
+	ListenAddr             string        `help:"Slack adapter listen address: host:port, unix:/path/to.sock or systemd:[name]" default:":8084" env:"SHEPHERD_SLACK_ADDR"`
+	SigningSecret          string        `help:"Signing secret of the Slack app" env:"SHEPHERD_SLACK_SIGNING_SECRET"`
+	SlackBotTokenFile      string        `help:"File holding the bot token the adapter posts with" required:"" type:"existingfile" env:"SHEPHERD_SLACK_BOT_TOKEN_FILE"`
+	RepoBaseURL            string        `help:"Forge that repositories given as owner/repo are on" default:"https://github.com" env:"SHEPHERD_SLACK_REPO_BASE_URL"`
+	APIURL                 string        `help:"Shepherd API URL" required:"" env:"SHEPHERD_API_URL"`
+	CallbackSecret         string        `help:"Shared secret for callback verification" env:"SHEPHERD_CALLBACK_SECRET"`
+	CallbackURL            string        `help:"Callback URL for API to call back" required:"" env:"SHEPHERD_CALLBACK_URL"`
+	DefaultSandboxTemplate string        `help:"Default sandbox template" default:"default"`
+	EventTimeout           time.Duration `help:"How long handling a command, event or callback may take" default:"2m" env:"SHEPHERD_SLACK_EVENT_TIMEOUT"`
+
+	CallbackSecretSecondary string `help:"Second secret callbacks may be signed with, for rotating the callback secret" env:"SHEPHERD_CALLBACK_SECRET_SECONDARY"`
+	CallbackPublicKey       string `help:"Ed25519 public keys (PEM) callbacks may be signed with instead of the callback secret, as served by the API at /api/v1/callback-signing-key" type:"existingfile" env:"SHEPHERD_CALLBACK_PUBLIC_KEY"`
+}
+
+func (c *SlackCmd) Run(_ *CLI) error {
+	if c.SigningSecret == "" {
+		return fmt.Errorf("signing-secret is required")
+	}
+	if err := forge.ValidateURL(c.RepoBaseURL); err != nil {
+		return fmt.Errorf("repo-base-url: %w", err)
+	}
+	if c.EventTimeout <= 0 {
+		return fmt.Errorf("event-timeout must be positive, got %s", c.EventTimeout)
+	}
+
+	return slack.Run(slack.Options{
+		ListenAddr:              c.ListenAddr,
+		SigningSecret:           c.SigningSecret,
+		BotTokenPath:            c.SlackBotTokenFile,
+		RepoBaseURL:             c.RepoBaseURL,
+		APIURL:                  c.APIURL,
+		CallbackSecret:          c.CallbackSecret,
+		CallbackURL:             c.CallbackURL,
+		DefaultSandboxTemplate:  c.DefaultSandboxTemplate,
+		EventTimeout:            c.EventTimeout,
+		CallbackSecondarySecret: c.CallbackSecretSecondary,
+		CallbackPublicKeyPath:   c.CallbackPublicKey,
+	})
+}
+
+type LinearCmd struct {
+	ListenAddr             string            `help:"Linear adapter listen address: host:port, unix:/path/to.sock or systemd:[name]" default:":8085" env:"SHEPHERD_LINEAR_ADDR"`
+	LinearAPIKeyFile       string            `help:"File holding the API key the adapter comments with" required:"" type:"existingfile" env:"SHEPHERD_LINEAR_API_KEY_FILE"`
+	WebhookSecret          string            `help:"Signing secret of the Linear webhook" env:"SHEPHERD_LINEAR_WEBHOOK_SECRET"`
+	TriggerLabel           string            `help:"Label that creates a task when added to an issue (empty = only @shepherd comments)" default:"shepherd" env:"SHEPHERD_LINEAR_TRIGGER_LABEL"`
+	TeamRepos              map[string]string `help:"Repository of each team's tasks, e.g. ENG=org/app;OPS=https://gitea.example.com/org/ops" env:"SHEPHERD_LINEAR_TEAM_REPOS"`
+	RepoBaseURL            string            `help:"Forge that repositories given as owner/repo are on" default:"https://github.com" env:"SHEPHERD_LINEAR_REPO_BASE_URL"`
+	APIURL                 string            `help:"Shepherd API URL" required:"" env:"SHEPHERD_API_URL"`
+	CallbackSecret         string            `help:"Shared secret for callback verification" env:"SHEPHERD_CALLBACK_SECRET"`
+	CallbackURL            string            `help:"Callback URL for API to call back" required:"" env:"SHEPHERD_CALLBACK_URL"`
+	DefaultSandboxTemplate string            `help:"Default sandbox template" default:"default"`
+	EventTimeout           time.Duration     `help:"How long handling a webhook event or callback may take" default:"2m" env:"SHEPHERD_LINEAR_EVENT_TIMEOUT"`
+
+	CallbackSecretSecondary string `help:"Second secret callbacks may be signed with, for rotating the callback secret" env:"SHEPHERD_CALLBACK_SECRET_SECONDARY"`
+	CallbackPublicKey       string `help:"Ed25519 public keys (PEM) callbacks may be signed with instead of the callback secret, as served by the API at /api/v1/callback-signing-key" type:"existingfile" env:"SHEPHERD_CALLBACK_PUBLIC_KEY"`
+}
+
+func (c *LinearCmd) Run(_ *CLI) error {
+	if c.WebhookSecret == "" {
+		return fmt.Errorf("webhook-secret is required")
+	}
+	if err := forge.ValidateURL(c.RepoBaseURL); err != nil {
+		return fmt.Errorf("repo-base-url: %w", err)
+	}
+	if c.EventTimeout <= 0 {
+		return fmt.Errorf("event-timeout must be positive, got %s", c.EventTimeout)
+	}
+	triggers := linear.Triggers{Label: c.TriggerLabel, TeamRepos: c.TeamRepos, RepoBaseURL: c.RepoBaseURL}
+	if err := triggers.Validate(); err != nil {
+		return fmt.Errorf("team-repos: %w", err)
+	}
+
+	return linear.Run(linear.Options{
+		ListenAddr:              c.ListenAddr,
+		WebhookSecret:           c.WebhookSecret,
+		APIKeyPath:              c.LinearAPIKeyFile,
+		Triggers:                triggers,
+		APIURL:                  c.APIURL,
+		CallbackSecret:          c.CallbackSecret,
+		CallbackURL:             c.CallbackURL,
+		DefaultSandboxTemplate:  c.DefaultSandboxTemplate,
+		EventTimeout:            c.EventTimeout,
+		CallbackSecondarySecret: c.CallbackSecretSecondary,
+		CallbackPublicKeyPath:   c.CallbackPublicKey,
 	})
 }
 
@@ -84,18 +375,33 @@ func main() {
 	ctx := kong.Parse(&cli,
 		kong.Name("shepherd"),
 		kong.Description("Background coding agent orchestrator"),
+		kong.Vars{"debugAddr": debug.DefaultAddr},
 	)
 
 	// Configure logging
-	logger := zap.New(
-		zap.UseDevMode(cli.DevMode),
-		zap.Level(zapraw.Level(-cli.LogLevel)),
-	)
+	logger, err := logging.New(logging.Options{
+		Format:      cli.LogFormat,
+		Level:       cli.LogLevel,
+		Development: cli.DevMode,
+	})
+	ctx.FatalIfErrorf(err)
 	log.SetLogger(logger)
 
-	err := ctx.Run(&cli)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: "shepherd-" + strings.Fields(ctx.Command())[0],
+		Endpoint:    cli.TracingEndpoint,
+		SampleRatio: cli.TracingSampleRatio,
+	})
+	ctx.FatalIfErrorf(err)
+
+	runErr := ctx.Run(&cli)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error(err, "failed to flush traces")
+	}
+	if runErr != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", runErr)
 		os.Exit(1)
 	}
 }
@@ -19,8 +19,13 @@ package main
 import (
 	"fmt"
 	"net/url"
+	"slices"
+	"time"
 
+	"github.com/NissesSenap/shepherd/internal/controller"
+	"github.com/NissesSenap/shepherd/pkg/forge"
 	"github.com/NissesSenap/shepherd/pkg/operator"
+	"github.com/NissesSenap/shepherd/pkg/validate"
 )
 
 type OperatorCmd struct {
@@ -28,6 +33,41 @@ type OperatorCmd struct {
 	HealthAddr     string `help:"Health probe address" default:":8082" env:"SHEPHERD_HEALTH_ADDR"`
 	LeaderElection bool   `help:"Enable leader election" default:"false" env:"SHEPHERD_LEADER_ELECTION"`
 	APIURL         string `help:"Internal API server URL" required:"" env:"SHEPHERD_API_URL"`
+
+	MaxConcurrentTasks            int            `help:"Maximum number of tasks holding a sandbox at once (0 = unlimited)" default:"0" env:"SHEPHERD_MAX_CONCURRENT_TASKS"`
+	MaxConcurrentTasksPerTemplate map[string]int `help:"Maximum number of tasks holding a sandbox of a given template at once, as template=limit pairs (e.g. gpu=2,large=5)" mapsep:"," env:"SHEPHERD_MAX_CONCURRENT_TASKS_PER_TEMPLATE"`
+	QueueOrder                    string         `help:"Order in which waiting tasks are admitted: priority (highest spec.priority first) or fifo (oldest first)" default:"priority" enum:"priority,fifo" env:"SHEPHERD_QUEUE_ORDER"`
+
+	SandboxTemplateRoutes []string `help:"Routes sending tasks to a sandbox template by their labels, as template:selector rules separated by ';' (e.g. python:shepherd.io/language=python); the first matching rule wins" sep:";" env:"SHEPHERD_SANDBOX_TEMPLATE_ROUTES"`
+
+	TTLAfterFinished time.Duration `help:"Delete finished tasks this long after they succeeded or failed, unless a task sets spec.ttlAfterFinished (0 = keep)" default:"0" env:"SHEPHERD_TTL_AFTER_FINISHED"`
+
+	TimeoutWarningPercent int `help:"Warn the adapter once a running task has used this percentage of its timeout (0 = no warning)" default:"80" env:"SHEPHERD_TIMEOUT_WARNING_PERCENT"`
+
+	MaxConcurrentReconciles int           `help:"Number of objects of each kind reconciled in parallel" default:"1" env:"SHEPHERD_MAX_CONCURRENT_RECONCILES"`
+	RetryBaseDelay          time.Duration `help:"Initial backoff after a failed task reconcile" default:"5ms" env:"SHEPHERD_RETRY_BASE_DELAY"`
+	RetryMaxDelay           time.Duration `help:"Maximum backoff after repeated failed task reconciles" default:"1000s" env:"SHEPHERD_RETRY_MAX_DELAY"`
+	RetryQPS                float64       `help:"Retries of failed task reconciles per second, across all tasks" default:"10" env:"SHEPHERD_RETRY_QPS"`
+	RetryBurst              int           `help:"Burst of retries of failed task reconciles, across all tasks" default:"100" env:"SHEPHERD_RETRY_BURST"`
+	TaskReconcileQPS        float64       `help:"Reconciles per second allowed for a single task (0 = unlimited)" default:"2" env:"SHEPHERD_TASK_RECONCILE_QPS"`
+	TaskReconcileBurst      int           `help:"Burst of reconciles allowed for a single task" default:"10" env:"SHEPHERD_TASK_RECONCILE_BURST"`
+
+	Namespace string `help:"Namespace the operator runs in, where it keeps the image pre-pull DaemonSet" default:"shepherd" env:"SHEPHERD_NAMESPACE"`
+
+	PrePullImages           []string          `help:"Runner images to keep pulled on every node with a DaemonSet, so sandboxes on fresh nodes start without pulling them (empty = no DaemonSet)" env:"SHEPHERD_PREPULL_IMAGES"`
+	PrePullPauseImage       string            `help:"Image the pre-pull pods idle in once their images are pulled" default:"registry.k8s.io/pause:3.10" env:"SHEPHERD_PREPULL_PAUSE_IMAGE"`
+	PrePullImagePullSecrets []string          `help:"Image pull secrets, in the operator's namespace, for pulling the pre-pull images" env:"SHEPHERD_PREPULL_IMAGE_PULL_SECRETS"`
+	PrePullNodeSelector     map[string]string `help:"Node selector for the pre-pull DaemonSet, as key=value pairs (e.g. pool=sandboxes)" mapsep:"," env:"SHEPHERD_PREPULL_NODE_SELECTOR"`
+
+	Webhooks                bool     `help:"Serve the AgentTask defaulting and validating admission webhooks" env:"SHEPHERD_WEBHOOKS"`
+	WebhookPort             int      `help:"Admission webhook port" default:"9443" env:"SHEPHERD_WEBHOOK_PORT"`
+	WebhookCertDir          string   `help:"Directory holding the webhook serving certificate (tls.crt, tls.key)" default:"/tmp/k8s-webhook-server/serving-certs" env:"SHEPHERD_WEBHOOK_CERT_DIR"`
+	AllowedSandboxTemplates []string `help:"Sandbox templates tasks may use (empty = any), enforced by the admission webhook" env:"SHEPHERD_ALLOWED_SANDBOX_TEMPLATES"`
+
+	GithubAppID          int64  `help:"GitHub App ID, used to resolve TaskFleet repo queries" env:"SHEPHERD_GITHUB_APP_ID"`
+	GithubInstallationID int64  `help:"GitHub Installation ID" env:"SHEPHERD_GITHUB_INSTALLATION_ID"`
+	GithubPrivateKeyPath string `help:"Path to GitHub App private key" env:"SHEPHERD_GITHUB_PRIVATE_KEY_PATH"`
+	GithubURL            string `help:"GitHub Enterprise Server URL, e.g. https://ghe.example.com (empty = github.com)" env:"SHEPHERD_GITHUB_URL"`
 }
 
 func (c *OperatorCmd) Run(_ *CLI) error {
@@ -35,11 +75,93 @@ func (c *OperatorCmd) Run(_ *CLI) error {
 	if err != nil || u.Scheme == "" || u.Host == "" {
 		return fmt.Errorf("invalid SHEPHERD_API_URL %q: must be a valid URL with scheme and host", c.APIURL)
 	}
+	if c.MaxConcurrentTasks < 0 {
+		return fmt.Errorf("--max-concurrent-tasks must not be negative, got %d", c.MaxConcurrentTasks)
+	}
+	for tmpl, limit := range c.MaxConcurrentTasksPerTemplate {
+		if limit < 1 {
+			return fmt.Errorf("--max-concurrent-tasks-per-template limit for %q must be at least 1, got %d", tmpl, limit)
+		}
+	}
+	routes := make([]controller.TemplateRoute, 0, len(c.SandboxTemplateRoutes))
+	for _, s := range c.SandboxTemplateRoutes {
+		route, err := controller.ParseTemplateRoute(s)
+		if err != nil {
+			return fmt.Errorf("--sandbox-template-routes: %w", err)
+		}
+		if len(c.AllowedSandboxTemplates) > 0 && !slices.Contains(c.AllowedSandboxTemplates, route.Template) {
+			return fmt.Errorf("--sandbox-template-routes: template %q is not in --allowed-sandbox-templates", route.Template)
+		}
+		routes = append(routes, route)
+	}
+	if c.TTLAfterFinished < 0 {
+		return fmt.Errorf("--ttl-after-finished must not be negative, got %s", c.TTLAfterFinished)
+	}
+	if c.TimeoutWarningPercent < 0 || c.TimeoutWarningPercent >= 100 {
+		return fmt.Errorf("--timeout-warning-percent must be between 0 and 99, got %d", c.TimeoutWarningPercent)
+	}
+	if c.MaxConcurrentReconciles < 1 {
+		return fmt.Errorf("--max-concurrent-reconciles must be at least 1, got %d", c.MaxConcurrentReconciles)
+	}
+	if c.RetryBaseDelay <= 0 || c.RetryMaxDelay < c.RetryBaseDelay {
+		return fmt.Errorf("--retry-base-delay must be positive and not above --retry-max-delay")
+	}
+	if c.RetryQPS <= 0 || c.RetryBurst < 1 {
+		return fmt.Errorf("--retry-qps must be positive and --retry-burst at least 1")
+	}
+	if c.TaskReconcileQPS < 0 || c.TaskReconcileBurst < 1 {
+		return fmt.Errorf("--task-reconcile-qps must not be negative and --task-reconcile-burst must be at least 1")
+	}
+	if c.GithubAppID != 0 && (c.GithubInstallationID == 0 || c.GithubPrivateKeyPath == "") {
+		return fmt.Errorf("github-installation-id and github-private-key-path are required when github-app-id is set")
+	}
+	if c.GithubURL != "" {
+		if err := forge.ValidateURL(c.GithubURL); err != nil {
+			return fmt.Errorf("--github-url: %w", err)
+		}
+	}
 
 	return operator.Run(operator.Options{
 		MetricsAddr:    c.MetricsAddr,
 		HealthAddr:     c.HealthAddr,
 		LeaderElection: c.LeaderElection,
 		APIURL:         c.APIURL,
+
+		MaxConcurrentTasks:            c.MaxConcurrentTasks,
+		MaxConcurrentTasksPerTemplate: c.MaxConcurrentTasksPerTemplate,
+		QueueOrder:                    c.QueueOrder,
+
+		TemplateRoutes: routes,
+
+		TTLAfterFinished: c.TTLAfterFinished,
+
+		TimeoutWarningPercent: c.TimeoutWarningPercent,
+
+		MaxConcurrentReconciles: c.MaxConcurrentReconciles,
+		RetryBaseDelay:          c.RetryBaseDelay,
+		RetryMaxDelay:           c.RetryMaxDelay,
+		RetryQPS:                c.RetryQPS,
+		RetryBurst:              c.RetryBurst,
+		TaskReconcileQPS:        c.TaskReconcileQPS,
+		TaskReconcileBurst:      c.TaskReconcileBurst,
+
+		Namespace: c.Namespace,
+
+		PrePullImages:           c.PrePullImages,
+		PrePullPauseImage:       c.PrePullPauseImage,
+		PrePullImagePullSecrets: c.PrePullImagePullSecrets,
+		PrePullNodeSelector:     c.PrePullNodeSelector,
+
+		Webhooks:       c.Webhooks,
+		WebhookPort:    c.WebhookPort,
+		WebhookCertDir: c.WebhookCertDir,
+		Validation: validate.Options{
+			AllowedSandboxTemplates: c.AllowedSandboxTemplates,
+		},
+
+		GithubAppID:          c.GithubAppID,
+		GithubInstallationID: c.GithubInstallationID,
+		GithubPrivateKeyPath: c.GithubPrivateKeyPath,
+		GithubURL:            c.GithubURL,
 	})
 }
@@ -0,0 +1,81 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/NissesSenap/shepherd/internal/controller"
+)
+
+type ReplayCmd struct {
+	Fixtures []string `arg:"" type:"existingfile" help:"Fixture files with recorded SandboxClaim and Sandbox status transitions"`
+}
+
+func (c *ReplayCmd) Run(_ *CLI) error {
+	failed := 0
+	for _, path := range c.Fixtures {
+		f, err := controller.LoadReplayFixture(path)
+		if err != nil {
+			return err
+		}
+		results, err := controller.Replay(context.Background(), f)
+		printReplay(os.Stdout, path, f, results)
+		if err != nil {
+			return fmt.Errorf("replaying %s: %w", path, err)
+		}
+		for _, res := range results {
+			if len(res.Mismatches) > 0 {
+				failed++
+				break
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d fixtures did not replay as expected", failed, len(c.Fixtures))
+	}
+	return nil
+}
+
+// printReplay writes the outcome of every replayed step of a fixture.
+func printReplay(w io.Writer, path string, f *controller.ReplayFixture, results []controller.ReplayResult) {
+	name := f.Name
+	if name == "" {
+		name = f.Task.Name
+	}
+	_, _ = fmt.Fprintf(w, "%s (%s)\n", name, path)
+	for _, res := range results {
+		line := fmt.Sprintf("  %-8s %-10s requeue=%s claim=%t", res.At, res.Reason, res.RequeueAfter, res.Claim)
+		if res.GraceDeadline != nil {
+			line += " graceDeadline=" + res.GraceDeadline.UTC().Format(time.RFC3339)
+		}
+		if res.Err != nil {
+			line += fmt.Sprintf(" error=%q", res.Err)
+		}
+		_, _ = fmt.Fprintln(w, line)
+		for _, e := range res.Events {
+			_, _ = fmt.Fprintf(w, "           event: %s\n", e)
+		}
+		for _, m := range res.Mismatches {
+			_, _ = fmt.Fprintf(w, "           MISMATCH %s\n", m)
+		}
+	}
+}
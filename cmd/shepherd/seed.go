@@ -0,0 +1,72 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	shepherdclient "github.com/NissesSenap/shepherd/pkg/client"
+	"github.com/NissesSenap/shepherd/pkg/seed"
+)
+
+type SeedCmd struct {
+	APIURL            string        `help:"Public Shepherd API URL" default:"http://localhost:30080" env:"SHEPHERD_API_URL"`
+	Namespace         string        `help:"Namespace the API server creates tasks in; the demo SandboxTemplate is created there" default:"shepherd-system" env:"SHEPHERD_NAMESPACE"`
+	Template          string        `help:"Name of the demo SandboxTemplate; an existing template of that name is used as is" default:"shepherd-demo"`
+	RunnerImage       string        `help:"Runner image of the demo SandboxTemplate" default:"ghcr.io/nissessenap/shepherd-runner:latest"`
+	CredentialsSecret string        `help:"Secret in the namespace holding the runner's Anthropic API key under api-key" default:"anthropic-credentials"`
+	Repo              string        `help:"Public repository the demo task runs against" default:"https://github.com/NissesSenap/shepherd"`
+	Description       string        `help:"What the demo task asks the agent to do" default:"Say hello world"`
+	CallbackURL       string        `help:"Callback URL of the demo task; seed follows the task itself and does not need the callbacks" default:"https://example.com/callback"`
+	Wait              time.Duration `help:"How long to follow the task until it finishes (0 = don't wait)" default:"30m"`
+}
+
+func (c *SeedCmd) Run(_ *CLI) error {
+	if c.Wait < 0 {
+		return fmt.Errorf("--wait must not be negative, got %s", c.Wait)
+	}
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("getting kubeconfig: %w", err)
+	}
+	kube, err := client.New(cfg, client.Options{Scheme: seed.Scheme()})
+	if err != nil {
+		return fmt.Errorf("creating kubernetes client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return seed.Run(ctx, kube, shepherdclient.New(c.APIURL), seed.Options{
+		Namespace:         c.Namespace,
+		TemplateName:      c.Template,
+		RunnerImage:       c.RunnerImage,
+		CredentialsSecret: c.CredentialsSecret,
+		RepoURL:           c.Repo,
+		Description:       c.Description,
+		CallbackURL:       c.CallbackURL,
+		Wait:              c.Wait,
+		PollInterval:      2 * time.Second,
+		Out:               os.Stdout,
+	})
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command shepherdctl creates, inspects and cancels Shepherd tasks through
+// the public API, for scripts and terminals.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	shepherdclient "github.com/NissesSenap/shepherd/pkg/client"
+)
+
+type CLI struct {
+	Task TaskCmd `cmd:"" help:"Manage tasks"`
+
+	APIURL     string `help:"Public Shepherd API URL" default:"http://localhost:30080" env:"SHEPHERD_API_URL"`
+	APIKey     string `help:"API key sent as a bearer token, for a gateway that authenticates API requests" env:"SHEPHERD_API_KEY" xor:"api-key"`
+	APIKeyFile string `help:"File holding the API key" type:"existingfile" env:"SHEPHERD_API_KEY_FILE" xor:"api-key"`
+	Output     string `help:"Output format: table or json" short:"o" default:"table" enum:"table,json"`
+
+	out io.Writer
+}
+
+// client returns a client for the configured API, authenticating with the
+// API key if one is set.
+func (c *CLI) client() (*shepherdclient.Client, error) {
+	key := c.APIKey
+	if c.APIKeyFile != "" {
+		data, err := os.ReadFile(c.APIKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading API key: %w", err)
+		}
+		key = strings.TrimSpace(string(data))
+		if key == "" {
+			return nil, fmt.Errorf("API key file %s is empty", c.APIKeyFile)
+		}
+	}
+	var opts []shepherdclient.Option
+	if key != "" {
+		opts = append(opts, shepherdclient.WithHeader("Authorization", "Bearer "+key))
+	}
+	return shepherdclient.New(c.APIURL, opts...), nil
+}
+
+func main() {
+	cli := CLI{out: os.Stdout}
+	kctx := kong.Parse(&cli,
+		kong.Name("shepherdctl"),
+		kong.Description("Command-line client of the Shepherd API"),
+		kong.UsageOnError(),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	kctx.BindTo(ctx, (*context.Context)(nil))
+	if err := kctx.Run(&cli); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
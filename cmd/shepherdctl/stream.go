@@ -0,0 +1,163 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	shepherdclient "github.com/NissesSenap/shepherd/pkg/client"
+)
+
+// reconnectDelay is how long a followed stream waits before reconnecting
+// after it broke off.
+var reconnectDelay = 2 * time.Second
+
+type TaskLogsCmd struct {
+	ID     string `arg:"" help:"Task ID"`
+	Follow bool   `help:"Keep printing new activity until the task finishes" short:"f"`
+}
+
+func (c *TaskLogsCmd) Run(cli *CLI, ctx context.Context) error {
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+	return followEvents(ctx, client, c.ID, c.Follow, func(msg shepherdclient.StreamMessage) error {
+		switch {
+		case msg.Event != nil:
+			_, err := fmt.Fprintln(cli.out, formatEvent(msg.Event))
+			return err
+		case msg.Complete != nil:
+			_, err := fmt.Fprintln(cli.out, formatComplete(msg.Complete))
+			return err
+		}
+		return nil
+	})
+}
+
+type TaskEventsCmd struct {
+	ID     string `arg:"" help:"Task ID"`
+	Follow bool   `help:"Keep printing new events until the task finishes" short:"f"`
+}
+
+func (c *TaskEventsCmd) Run(cli *CLI, ctx context.Context) error {
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(cli.out)
+	return followEvents(ctx, client, c.ID, c.Follow, func(msg shepherdclient.StreamMessage) error {
+		if msg.Event == nil {
+			return nil
+		}
+		return enc.Encode(msg.Event)
+	})
+}
+
+// followEvents passes the messages of a task's event stream to handle.
+// Without follow it stops after the buffered events; with follow it stops
+// once the task finished, reconnecting if the stream breaks off before.
+func followEvents(ctx context.Context, client *shepherdclient.Client, taskID string, follow bool,
+	handle func(shepherdclient.StreamMessage) error) error {
+	var after int64
+	for {
+		stream, err := client.StreamEvents(ctx, taskID, after)
+		if err != nil {
+			return fmt.Errorf("streaming events: %w", err)
+		}
+		err = func() error {
+			defer func() { _ = stream.Close() }()
+			for {
+				msg, err := stream.Next(ctx)
+				if err != nil {
+					return err
+				}
+				if msg.Event != nil {
+					if msg.Event.Sequence <= after {
+						continue
+					}
+					after = msg.Event.Sequence
+				}
+				if msg.Type == api.StreamEventsSynced && !follow {
+					return io.EOF
+				}
+				if err := handle(msg); err != nil {
+					return err
+				}
+			}
+		}()
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case !follow:
+			return fmt.Errorf("streaming events: %w", err)
+		}
+		select {
+		case <-time.After(reconnectDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// formatEvent renders an agent event as a log line.
+func formatEvent(e *api.TaskEvent) string {
+	var b strings.Builder
+	if t, err := time.Parse(time.RFC3339Nano, e.Timestamp); err == nil {
+		b.WriteString(t.Local().Format(time.TimeOnly))
+	} else {
+		b.WriteString(e.Timestamp)
+	}
+	b.WriteString(" ")
+	switch e.Type {
+	case api.EventTypeToolCall:
+		fmt.Fprintf(&b, "%-8s %s", e.Tool, e.Summary)
+	case api.EventTypeToolResult:
+		status := "ok"
+		if e.Output != nil && !e.Output.Success {
+			status = "failed"
+		}
+		fmt.Fprintf(&b, "%-8s %s", status, e.Summary)
+		if e.Output != nil && e.Output.Summary != "" {
+			b.WriteString(": " + e.Output.Summary)
+		}
+	default:
+		fmt.Fprintf(&b, "%-8s %s", e.Type, e.Summary)
+	}
+	return b.String()
+}
+
+// formatComplete renders the end of a task's stream.
+func formatComplete(c *api.TaskCompleteData) string {
+	line := "Task " + c.Status
+	switch {
+	case c.PRURL != "":
+		line += ": " + c.PRURL
+	case c.Error != "":
+		line += ": " + c.Error
+	}
+	return line
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+var testEvents = []api.TaskEvent{
+	{Sequence: 1, Timestamp: "2026-03-01T12:00:00Z", Type: api.EventTypeThinking, Summary: "Looking at the test"},
+	{Sequence: 2, Timestamp: "2026-03-01T12:00:01Z", Type: api.EventTypeToolCall, Tool: "Bash", Summary: "go test ./..."},
+	{Sequence: 3, Timestamp: "2026-03-01T12:00:05Z", Type: api.EventTypeToolResult, Summary: "go test ./...",
+		Output: &api.TaskEventOutput{Success: false, Summary: "1 test failed"}},
+}
+
+// eventServer serves the event stream of eventHandler.
+func eventServer(t *testing.T, live bool) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(eventHandler(t, live))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// eventHandler serves the event stream of task-abc: the first two events,
+// events_synced, and then, with live, the third event and task_complete.
+// The first live connection breaks off after the synced message, so
+// followers have to reconnect.
+func eventHandler(t *testing.T, live bool) http.Handler {
+	t.Helper()
+	var connections atomic.Int32
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after, _ := strconv.ParseInt(r.URL.Query().Get("after"), 10, 64)
+		conn, err := websocket.Accept(w, r, nil)
+		require.NoError(t, err)
+		write := func(msg api.WSMessage) {
+			data, _ := json.Marshal(msg)
+			_ = conn.Write(r.Context(), websocket.MessageText, data)
+		}
+		for _, e := range testEvents[:2] {
+			if e.Sequence > after {
+				write(api.WSMessage{Type: "task_event", Data: e})
+			}
+		}
+		write(api.WSMessage{Type: api.StreamEventsSynced})
+		if !live {
+			time.Sleep(time.Second) // Still running
+			_ = conn.CloseNow()
+			return
+		}
+		if connections.Add(1) == 1 {
+			_ = conn.Close(websocket.StatusPolicyViolation, "slow consumer evicted")
+			return
+		}
+		write(api.WSMessage{Type: "task_event", Data: testEvents[2]})
+		write(api.WSMessage{Type: "task_complete", Data: api.TaskCompleteData{TaskID: "task-abc", Status: "Failed", Error: "tests fail"}})
+		_ = conn.Close(websocket.StatusNormalClosure, "task complete")
+	})
+}
+
+func TestTaskEvents(t *testing.T) {
+	reconnectDelay = 10 * time.Millisecond
+
+	out, err := run(t, eventServer(t, false).URL, "task", "events", "task-abc")
+	require.NoError(t, err)
+	assert.Equal(t, `{"sequence":1,"timestamp":"2026-03-01T12:00:00Z","type":"thinking","summary":"Looking at the test"}`+"\n"+
+		`{"sequence":2,"timestamp":"2026-03-01T12:00:01Z","type":"tool_call","summary":"go test ./...","tool":"Bash"}`+"\n",
+		out, "without -f only the buffered events")
+
+	out, err = run(t, eventServer(t, true).URL, "task", "events", "-f", "task-abc")
+	require.NoError(t, err)
+	var sequences []int64
+	dec := json.NewDecoder(strings.NewReader(out))
+	for dec.More() {
+		var e api.TaskEvent
+		require.NoError(t, dec.Decode(&e))
+		sequences = append(sequences, e.Sequence)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, sequences, "resumes after a broken stream without repeating events")
+}
+
+func TestTaskLogs(t *testing.T) {
+	reconnectDelay = 10 * time.Millisecond
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	out, err := run(t, eventServer(t, true).URL, "task", "logs", "-f", "task-abc")
+	require.NoError(t, err)
+	assert.Equal(t, ""+
+		"12:00:00 thinking Looking at the test\n"+
+		"12:00:01 Bash     go test ./...\n"+
+		"12:00:05 failed   go test ./...: 1 test failed\n"+
+		"Task Failed: tests fail\n", out)
+}
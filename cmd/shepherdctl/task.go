@@ -0,0 +1,287 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	shepherdclient "github.com/NissesSenap/shepherd/pkg/client"
+)
+
+type TaskCmd struct {
+	Create TaskCreateCmd `cmd:"" help:"Create a task"`
+	List   TaskListCmd   `cmd:"" help:"List tasks"`
+	Get    TaskGetCmd    `cmd:"" help:"Show a task"`
+	Cancel TaskCancelCmd `cmd:"" help:"Cancel an unfinished task"`
+	Logs   TaskLogsCmd   `cmd:"" help:"Print a task's agent activity as log lines"`
+	Events TaskEventsCmd `cmd:"" help:"Print a task's agent events as JSON lines"`
+	Watch  TaskWatchCmd  `cmd:"" help:"Show a live view of a task's phase and agent activity"`
+}
+
+type TaskCreateCmd struct {
+	Description     string            `arg:"" help:"What the agent should do"`
+	Repo            string            `help:"Repository URL, or owner/repo for a GitHub repository" required:""`
+	Ref             string            `help:"Branch, tag or commit to check out (default: the default branch)"`
+	ContextFile     string            `help:"File with additional context for the agent, or - for stdin" type:"path"`
+	CallbackURL     string            `help:"URL the API posts the task's result to" required:"" env:"SHEPHERD_CALLBACK_URL"`
+	SandboxTemplate string            `help:"Sandbox template the task runs in (default: the API server's)"`
+	TaskTemplate    string            `help:"TaskTemplate supplying defaults for the task"`
+	Timeout         time.Duration     `help:"Runner timeout (default: the API server's)"`
+	Label           map[string]string `help:"Label of the task, as key=value; may be repeated"`
+	Priority        int32             `help:"Priority of the task; higher runs first"`
+	DependsOn       []string          `help:"IDs of tasks that must succeed first"`
+}
+
+func (c *TaskCreateCmd) Run(cli *CLI, ctx context.Context) error {
+	req := api.CreateTaskRequest{
+		Repo:        api.RepoRequest{URL: repoURL(c.Repo), Ref: c.Ref},
+		Task:        api.TaskRequest{Description: c.Description},
+		Callback:    c.CallbackURL,
+		Labels:      c.Label,
+		Priority:    c.Priority,
+		DependsOn:   c.DependsOn,
+		TemplateRef: c.TaskTemplate,
+	}
+	if c.ContextFile != "" {
+		taskContext, err := readContext(c.ContextFile)
+		if err != nil {
+			return err
+		}
+		req.Task.Context = taskContext
+	}
+	if c.SandboxTemplate != "" || c.Timeout > 0 {
+		req.Runner = &api.RunnerConfig{SandboxTemplateName: c.SandboxTemplate}
+		if c.Timeout > 0 {
+			req.Runner.Timeout = c.Timeout.String()
+		}
+	}
+
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+	task, err := client.CreateTask(ctx, nil, req)
+	if err != nil {
+		return fmt.Errorf("creating task: %w", err)
+	}
+	if cli.Output == "json" {
+		return writeJSON(cli.out, task)
+	}
+	_, err = fmt.Fprintln(cli.out, task.ID)
+	return err
+}
+
+// repoURL expands owner/repo to the URL of a GitHub repository and leaves
+// anything else as is.
+func repoURL(repo string) string {
+	if strings.Contains(repo, "://") || strings.Count(repo, "/") != 1 {
+		return repo
+	}
+	return "https://github.com/" + strings.TrimSuffix(repo, ".git") + ".git"
+}
+
+// readContext reads the task context from path, or from stdin for "-".
+func readContext(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading context: %w", err)
+	}
+	return string(data), nil
+}
+
+type TaskListCmd struct {
+	Active bool     `help:"Only list unfinished tasks"`
+	Repo   string   `help:"Only list tasks of this repository: its URL, owner/repo or owner-repo"`
+	Issue  string   `help:"Only list tasks of this issue: its number or URL"`
+	Fleet  string   `help:"Only list tasks of this fleet"`
+	Phase  []string `help:"Only list tasks in these phases, e.g. Failed,TimedOut"`
+	Sort   string   `help:"Sort by createdAt, completionTime or phase" default:"createdAt" enum:"createdAt,completionTime,phase"`
+	Order  string   `help:"Sort order: asc or desc" default:"desc" enum:"asc,desc"`
+}
+
+func (c *TaskListCmd) Run(cli *CLI, ctx context.Context) error {
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+	params := &shepherdclient.ListTasksParams{
+		Repo:  c.Repo,
+		Issue: c.Issue,
+		Fleet: c.Fleet,
+		Phase: strings.Join(c.Phase, ","),
+		Sort:  c.Sort,
+		Order: c.Order,
+	}
+	if c.Active {
+		params.Active = "true"
+	}
+	tasks, err := client.ListTasks(ctx, params)
+	if err != nil {
+		return fmt.Errorf("listing tasks: %w", err)
+	}
+	if cli.Output == "json" {
+		return writeJSON(cli.out, tasks)
+	}
+
+	tw := tabwriter.NewWriter(cli.out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tPHASE\tREPOSITORY\tAGE\tDESCRIPTION")
+	now := time.Now()
+	for i := range tasks {
+		task := &tasks[i]
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", task.ID, task.Status.Phase, repoName(task.Repo.URL),
+			age(task.CreatedAt, now), truncate(firstLine(task.Task.Description), 60))
+	}
+	return tw.Flush()
+}
+
+type TaskGetCmd struct {
+	ID string `arg:"" help:"Task ID"`
+}
+
+func (c *TaskGetCmd) Run(cli *CLI, ctx context.Context) error {
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+	task, err := client.GetTask(ctx, c.ID, nil)
+	if err != nil {
+		return fmt.Errorf("getting task: %w", err)
+	}
+	if cli.Output == "json" {
+		return writeJSON(cli.out, task)
+	}
+	return writeTask(cli.out, task)
+}
+
+type TaskCancelCmd struct {
+	ID     string `arg:"" help:"Task ID"`
+	Reason string `help:"Why the task is cancelled; added to its message"`
+}
+
+func (c *TaskCancelCmd) Run(cli *CLI, ctx context.Context) error {
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+	task, err := client.CancelTask(ctx, c.ID, &api.CancelTaskRequest{Reason: c.Reason})
+	if err != nil {
+		return fmt.Errorf("cancelling task: %w", err)
+	}
+	if cli.Output == "json" {
+		return writeJSON(cli.out, task)
+	}
+	_, err = fmt.Fprintf(cli.out, "%s cancelled\n", task.ID)
+	return err
+}
+
+// writeTask prints the fields of a task that are set, one per line.
+func writeTask(w io.Writer, task *api.TaskResponse) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	field := func(name, value string) {
+		if value != "" {
+			_, _ = fmt.Fprintf(tw, "%s:\t%s\n", name, value)
+		}
+	}
+	field("ID", task.ID)
+	field("Phase", task.Status.Phase)
+	field("Message", task.Status.Message)
+	field("Repository", task.Repo.URL)
+	field("Ref", task.Repo.Ref)
+	field("Source", task.Task.SourceURL)
+	field("Requested by", task.RequestedBy)
+	field("Created", task.CreatedAt)
+	if task.CompletionTime != nil {
+		field("Completed", *task.CompletionTime)
+	}
+	field("Deadline", task.Status.Deadline)
+	field("Pull request", task.Status.PRURL)
+	field("Error", task.Status.Error)
+	if task.Status.CostUSD > 0 {
+		field("Cost", fmt.Sprintf("$%.2f", task.Status.CostUSD))
+	}
+	field("Description", firstLine(task.Task.Description))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if task.Status.Summary != "" {
+		_, err := fmt.Fprintf(w, "\n%s\n", strings.TrimSpace(task.Status.Summary))
+		return err
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// repoName shortens a repository URL to its path, e.g. org/repo.
+func repoName(url string) string {
+	if _, rest, ok := strings.Cut(url, "://"); ok {
+		if _, path, ok := strings.Cut(rest, "/"); ok {
+			url = path
+		}
+	}
+	return strings.TrimSuffix(url, ".git")
+}
+
+// age formats the time since the RFC 3339 timestamp ts like kubectl does,
+// e.g. 5m or 3h, or returns ts if it cannot be parsed.
+func age(ts string, now time.Time) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return ts
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
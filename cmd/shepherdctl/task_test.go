@@ -0,0 +1,159 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// run parses args as shepherdctl would and runs the selected command
+// against the API at apiURL, returning what it printed.
+func run(t *testing.T, apiURL string, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cli := CLI{out: &out}
+	parser, err := kong.New(&cli, kong.Name("shepherdctl"), kong.Exit(func(int) { t.Fatal("exit") }))
+	require.NoError(t, err)
+	kctx, err := parser.Parse(append([]string{"--apiurl", apiURL}, args...))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	kctx.BindTo(ctx, (*context.Context)(nil))
+	err = kctx.Run(&cli)
+	return out.String(), err
+}
+
+func TestTaskCreate(t *testing.T) {
+	var got api.CreateTaskRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/tasks", r.URL.Path)
+		assert.Equal(t, "Bearer s3cret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(api.TaskResponse{ID: "task-abc", Status: api.TaskStatusSummary{Phase: "Pending"}})
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("s3cret\n"), 0o600))
+	contextFile := filepath.Join(dir, "context.md")
+	require.NoError(t, os.WriteFile(contextFile, []byte("Stack trace: ..."), 0o600))
+
+	out, err := run(t, srv.URL, "--api-key-file", keyFile, "task", "create", "Fix the flaky test",
+		"--repo", "org/repo", "--callback-url", "https://example.com/cb", "--context-file", contextFile,
+		"--timeout", "45m", "--label", "team=payments", "--depends-on", "task-a,task-b")
+	require.NoError(t, err)
+	assert.Equal(t, "task-abc\n", out)
+	assert.Equal(t, api.CreateTaskRequest{
+		Repo:      api.RepoRequest{URL: "https://github.com/org/repo.git"},
+		Task:      api.TaskRequest{Description: "Fix the flaky test", Context: "Stack trace: ..."},
+		Callback:  "https://example.com/cb",
+		Runner:    &api.RunnerConfig{Timeout: "45m0s"},
+		Labels:    map[string]string{"team": "payments"},
+		DependsOn: []string{"task-a", "task-b"},
+	}, got)
+}
+
+func TestTaskList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/tasks", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("active"))
+		assert.Equal(t, "org/repo", r.URL.Query().Get("repo"))
+		assert.Equal(t, "Pending,Running", r.URL.Query().Get("phase"))
+		assert.Equal(t, "desc", r.URL.Query().Get("order"))
+		_ = json.NewEncoder(w).Encode([]api.TaskResponse{{
+			ID:        "task-abc",
+			Repo:      api.RepoRequest{URL: "https://github.com/org/repo.git"},
+			Task:      api.TaskRequest{Description: "Fix the flaky test\n\nIt fails on CI."},
+			Status:    api.TaskStatusSummary{Phase: "Running"},
+			CreatedAt: time.Now().Add(-5 * time.Minute).UTC().Format(time.RFC3339),
+		}})
+	}))
+	defer srv.Close()
+
+	out, err := run(t, srv.URL, "task", "list", "--active", "--repo", "org/repo", "--phase", "Pending,Running")
+	require.NoError(t, err)
+	assert.Equal(t, "ID        PHASE    REPOSITORY  AGE  DESCRIPTION\n"+
+		"task-abc  Running  org/repo    5m   Fix the flaky test\n", out)
+
+	out, err = run(t, srv.URL, "-o", "json", "task", "list", "--active", "--repo", "org/repo", "--phase", "Pending,Running")
+	require.NoError(t, err)
+	var tasks []api.TaskResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &tasks))
+	assert.Equal(t, "task-abc", tasks[0].ID)
+}
+
+func TestTaskGetAndCancel(t *testing.T) {
+	task := api.TaskResponse{
+		ID:     "task-abc",
+		Repo:   api.RepoRequest{URL: "https://github.com/org/repo.git"},
+		Task:   api.TaskRequest{Description: "Fix the flaky test"},
+		Status: api.TaskStatusSummary{Phase: "Succeeded", PRURL: "https://github.com/org/repo/pull/7", Summary: "Added a retry."},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/tasks/task-abc":
+			_ = json.NewEncoder(w).Encode(task)
+		case "/api/v1/tasks/task-abc/cancel":
+			var req api.CancelTaskRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "wrong repository", req.Reason)
+			_ = json.NewEncoder(w).Encode(task)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"task not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	out, err := run(t, srv.URL, "task", "get", "task-abc")
+	require.NoError(t, err)
+	assert.Equal(t, "ID:           task-abc\n"+
+		"Phase:        Succeeded\n"+
+		"Repository:   https://github.com/org/repo.git\n"+
+		"Pull request: https://github.com/org/repo/pull/7\n"+
+		"Description:  Fix the flaky test\n"+
+		"\nAdded a retry.\n", out)
+
+	out, err = run(t, srv.URL, "task", "cancel", "task-abc", "--reason", "wrong repository")
+	require.NoError(t, err)
+	assert.Equal(t, "task-abc cancelled\n", out)
+
+	_, err = run(t, srv.URL, "task", "get", "task-missing")
+	assert.ErrorContains(t, err, "API error 404: task not found")
+}
+
+func TestRepoURL(t *testing.T) {
+	assert.Equal(t, "https://github.com/org/repo.git", repoURL("org/repo"))
+	assert.Equal(t, "https://github.com/org/repo.git", repoURL("org/repo.git"))
+	assert.Equal(t, "https://gitea.example.com/org/repo.git", repoURL("https://gitea.example.com/org/repo.git"))
+}
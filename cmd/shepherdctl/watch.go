@@ -0,0 +1,314 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	shepherdclient "github.com/NissesSenap/shepherd/pkg/client"
+)
+
+type TaskWatchCmd struct {
+	ID       string        `arg:"" help:"Task ID"`
+	Interval time.Duration `help:"How often to refresh the task's phase" default:"2s"`
+}
+
+// phaseChange is a phase of the task and when it was first seen.
+type phaseChange struct {
+	Phase string
+	At    time.Time
+}
+
+// taskWatch is what task watch knows about the task it follows.
+type taskWatch struct {
+	task     *api.TaskResponse
+	phases   []phaseChange
+	events   []api.TaskEvent
+	complete *api.TaskCompleteData
+}
+
+// setTask records the latest state of the task and reports whether its
+// phase changed.
+func (w *taskWatch) setTask(task *api.TaskResponse, now time.Time) bool {
+	w.task = task
+	if n := len(w.phases); n > 0 && w.phases[n-1].Phase == task.Status.Phase {
+		return false
+	}
+	at := now
+	if len(w.phases) == 0 && task.Status.Phase == "Pending" {
+		if created, err := time.Parse(time.RFC3339, task.CreatedAt); err == nil {
+			at = created
+		}
+	}
+	w.phases = append(w.phases, phaseChange{Phase: task.Status.Phase, At: at})
+	return true
+}
+
+func (c *TaskWatchCmd) Run(cli *CLI, ctx context.Context) error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("--interval must be positive, got %s", c.Interval)
+	}
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+	task, err := client.GetTask(ctx, c.ID, nil)
+	if err != nil {
+		return fmt.Errorf("getting task: %w", err)
+	}
+	w := &taskWatch{}
+	w.setTask(task, time.Now())
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	messages := make(chan shepherdclient.StreamMessage)
+	streamDone := make(chan error, 1)
+	go func() {
+		streamDone <- followEvents(ctx, client, c.ID, true, func(msg shepherdclient.StreamMessage) error {
+			select {
+			case messages <- msg:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	var view watchView = &lineView{out: cli.out}
+	if f, ok := cli.out.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		screen, err := newScreenView(f, cancel)
+		if err != nil {
+			return err
+		}
+		defer screen.close()
+		view = screen
+	}
+	view.update(w, nil)
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for w.complete == nil {
+		select {
+		case msg := <-messages:
+			switch {
+			case msg.Event != nil:
+				w.events = append(w.events, *msg.Event)
+				view.update(w, msg.Event)
+			case msg.Complete != nil:
+				w.complete = msg.Complete
+				// Show the final state, such as the PR and cost.
+				if task, err := client.GetTask(ctx, c.ID, nil); err == nil {
+					w.setTask(task, time.Now())
+				}
+				view.update(w, nil)
+			}
+		case <-ticker.C:
+			task, err := client.GetTask(ctx, c.ID, nil)
+			if err != nil {
+				continue
+			}
+			// Redraw even without a phase change, for the timers.
+			w.setTask(task, time.Now())
+			view.update(w, nil)
+		case err := <-streamDone:
+			if err != nil && ctx.Err() == nil {
+				return err
+			}
+			streamDone = nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+// watchView shows a taskWatch. update is called whenever the watch
+// changed, with the event that was added if that was the change.
+type watchView interface {
+	update(w *taskWatch, event *api.TaskEvent)
+}
+
+// lineView prints phase changes and events as log lines, for output that
+// is not a terminal.
+type lineView struct {
+	out    io.Writer
+	phases int
+}
+
+func (v *lineView) update(w *taskWatch, event *api.TaskEvent) {
+	for _, p := range w.phases[v.phases:] {
+		_, _ = fmt.Fprintf(v.out, "%s %-8s %s\n", p.At.Local().Format(time.TimeOnly), "phase", p.Phase)
+	}
+	v.phases = len(w.phases)
+	if event != nil {
+		_, _ = fmt.Fprintln(v.out, formatEvent(event))
+	}
+	if w.complete != nil {
+		_, _ = fmt.Fprintln(v.out, formatComplete(w.complete))
+	}
+}
+
+// screenView redraws a live view of the task on a terminal, and quits when
+// q or Ctrl-C is pressed.
+type screenView struct {
+	out      *os.File
+	restore  func()
+	hasInput bool
+}
+
+func newScreenView(out *os.File, quit func()) (*screenView, error) {
+	v := &screenView{out: out, restore: func() {}}
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, fmt.Errorf("setting up terminal: %w", err)
+		}
+		v.restore = func() { _ = term.Restore(fd, state) }
+		v.hasInput = true
+		go func() {
+			buf := make([]byte, 1)
+			for {
+				if _, err := os.Stdin.Read(buf); err != nil || buf[0] == 'q' || buf[0] == 3 {
+					quit()
+					return
+				}
+			}
+		}()
+	}
+	_, _ = io.WriteString(out, "\x1b[?25l") // Hide the cursor
+	return v, nil
+}
+
+func (v *screenView) update(w *taskWatch, _ *api.TaskEvent) {
+	width, height, err := term.GetSize(int(v.out.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+	lines := w.render(width, height, time.Now(), true)
+	if v.hasInput && w.complete == nil {
+		lines[0] = padRight(lines[0], width-len("q: quit")) + "q: quit"
+	}
+	_, _ = io.WriteString(v.out, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n")+"\r\n")
+}
+
+func (v *screenView) close() {
+	v.restore()
+	_, _ = io.WriteString(v.out, "\x1b[?25h")
+}
+
+// ANSI colors of the phases.
+var phaseColors = map[string]string{
+	"Pending":      "\x1b[2m",
+	"Provisioning": "\x1b[36m",
+	"Running":      "\x1b[33m",
+	"Succeeded":    "\x1b[32m",
+	"Failed":       "\x1b[31m",
+	"TimedOut":     "\x1b[31m",
+	"Cancelled":    "\x1b[35m",
+}
+
+// render lays the watch out on a screen of the given size: a header with
+// the task and its phases, then as many of the latest events as fit.
+func (w *taskWatch) render(width, height int, now time.Time, color bool) []string {
+	task := w.task
+	phase := func(p string) string {
+		if c, ok := phaseColors[p]; ok && color {
+			return c + p + "\x1b[0m"
+		}
+		return p
+	}
+
+	header := []string{
+		fmt.Sprintf("Task %s  %s  %s", task.ID, phase(task.Status.Phase), repoName(task.Repo.URL)),
+		truncate(firstLine(task.Task.Description), width),
+	}
+	timing := fmt.Sprintf("queued %s · running %s", formatSeconds(task.QueuedSeconds), formatSeconds(task.RunningSeconds))
+	if task.Status.RemainingSeconds != nil && w.complete == nil {
+		timing += " · " + formatSeconds(*task.Status.RemainingSeconds) + " left"
+	}
+	if task.Status.CostUSD > 0 {
+		timing += fmt.Sprintf(" · $%.2f", task.Status.CostUSD)
+	}
+	header = append(header, truncate(timing, width))
+	changes := make([]string, len(w.phases))
+	for i, p := range w.phases {
+		changes[i] = phase(p.Phase) + " " + p.At.Local().Format(time.TimeOnly)
+	}
+	header = append(header, strings.Join(changes, " → "))
+	if task.Status.PRURL != "" {
+		header = append(header, "Pull request: "+task.Status.PRURL)
+	}
+	if task.Status.Error != "" {
+		header = append(header, "Error: "+truncate(task.Status.Error, width-len("Error: ")))
+	}
+	header = append(header, strings.Repeat("─", max(width, 1)))
+
+	room := max(height-len(header)-1, 1)
+	events := w.events
+	if len(events) > room {
+		events = events[len(events)-room:]
+	}
+	lines := header
+	for i := range events {
+		lines = append(lines, truncate(formatEvent(&events[i]), width))
+	}
+	if len(w.events) == 0 {
+		lines = append(lines, "Waiting for agent events…")
+	}
+	return lines
+}
+
+// formatSeconds formats a duration in whole seconds, e.g. 3m05s.
+func formatSeconds(s int64) string {
+	d := time.Duration(s) * time.Second
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func padRight(s string, n int) string {
+	if pad := n - len([]rune(stripANSI(s))); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s + " "
+}
+
+// stripANSI removes color escape sequences, to measure the width of text.
+func stripANSI(s string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "\x1b[")
+		if i < 0 {
+			return b.String() + s
+		}
+		b.WriteString(s[:i])
+		j := strings.IndexByte(s[i:], 'm')
+		if j < 0 {
+			return b.String()
+		}
+		s = s[i+j+1:]
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func TestTaskWatch(t *testing.T) {
+	reconnectDelay = 10 * time.Millisecond
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	// The task runs until its events are complete.
+	var polls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/tasks/task-abc", func(w http.ResponseWriter, _ *http.Request) {
+		task := api.TaskResponse{ID: "task-abc", Status: api.TaskStatusSummary{Phase: "Running"}}
+		if polls.Add(1) > 1 {
+			task.Status = api.TaskStatusSummary{Phase: "Failed", Error: "tests fail"}
+		}
+		_ = json.NewEncoder(w).Encode(task)
+	})
+	mux.Handle("/api/v1/tasks/task-abc/events", eventHandler(t, true))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	out, err := run(t, srv.URL, "task", "watch", "task-abc", "--interval", "1h")
+	require.NoError(t, err)
+	lines := splitLines(out)
+	require.Len(t, lines, 6)
+	assert.Regexp(t, `^\d\d:\d\d:\d\d phase    Running$`, lines[0])
+	assert.Equal(t, []string{
+		"12:00:00 thinking Looking at the test",
+		"12:00:01 Bash     go test ./...",
+		"12:00:05 failed   go test ./...: 1 test failed",
+	}, lines[1:4])
+	assert.Regexp(t, `^\d\d:\d\d:\d\d phase    Failed$`, lines[4], "the final phase is fetched on completion")
+	assert.Equal(t, "Task Failed: tests fail", lines[5])
+
+	_, err = run(t, srv.URL, "task", "watch", "task-missing")
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestTaskWatch_Render(t *testing.T) {
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	start := time.Date(2026, 3, 1, 11, 59, 50, 0, time.UTC)
+	remaining := int64(1500)
+	w := &taskWatch{}
+	w.setTask(&api.TaskResponse{
+		ID:        "task-abc",
+		Repo:      api.RepoRequest{URL: "https://github.com/org/repo.git"},
+		Task:      api.TaskRequest{Description: "Fix the flaky test\n\nIt fails on CI."},
+		Status:    api.TaskStatusSummary{Phase: "Pending"},
+		CreatedAt: start.Format(time.RFC3339),
+	}, start.Add(time.Second))
+	assert.False(t, w.setTask(&api.TaskResponse{ID: "task-abc", Status: api.TaskStatusSummary{Phase: "Pending"}}, start),
+		"an unchanged phase is no change")
+	w.setTask(&api.TaskResponse{
+		ID:             "task-abc",
+		Repo:           api.RepoRequest{URL: "https://github.com/org/repo.git"},
+		Task:           api.TaskRequest{Description: "Fix the flaky test\n\nIt fails on CI."},
+		Status:         api.TaskStatusSummary{Phase: "Running", RemainingSeconds: &remaining, CostUSD: 0.42},
+		QueuedSeconds:  12,
+		RunningSeconds: 185,
+	}, start.Add(12*time.Second))
+	w.events = testEvents
+
+	assert.Equal(t, []string{
+		"Task task-abc  Running  org/repo",
+		"Fix the flaky test",
+		"queued 0m12s · running 3m05s · 25m00s left …",
+		"Pending 11:59:50 → Running 12:00:02",
+		"────────────────────────────────────────────",
+		"12:00:01 Bash     go test ./...",
+		"12:00:05 failed   go test ./...: 1 test fai…",
+	}, w.render(44, 8, start, false), "only the latest events that fit, cut to the width")
+
+	colored := w.render(44, 8, start, true)
+	assert.Equal(t, "Task task-abc  \x1b[33mRunning\x1b[0m  org/repo", colored[0])
+	assert.Equal(t, "Task task-abc  Running  org/repo   ", stripANSI(padRight(colored[0], 35)),
+		"colors do not count towards the width")
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	for line := range strings.Lines(s) {
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	return lines
+}
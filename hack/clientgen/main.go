@@ -0,0 +1,427 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command clientgen generates the operations of pkg/client from the OpenAPI
+// spec. Request and response schemas map to the types of the same name in
+// pkg/api, so the spec and the server types stay the single source of
+// truth; the generator only writes the HTTP plumbing between them.
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// schemaTypes maps the spec schemas that are not served from pkg/api.
+var schemaTypes = map[string]string{
+	"ReadinessResponse": "readiness.Result",
+	"ReadinessCheck":    "readiness.CheckResult",
+}
+
+func main() {
+	specPath := flag.String("spec", "api/openapi.yaml", "Path to the OpenAPI spec.")
+	out := flag.String("out", "pkg/client/zz_generated.client.go", "Path of the generated Go file.")
+	flag.Parse()
+
+	doc, err := openapi3.NewLoader().LoadFromFile(*specPath)
+	if err != nil {
+		log.Fatalf("loading spec: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		log.Fatalf("validating spec: %v", err)
+	}
+	src, err := generate(doc)
+	if err != nil {
+		log.Fatalf("generating client: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatalf("writing client: %v", err)
+	}
+}
+
+// operation is a spec operation as the template needs it.
+type operation struct {
+	name         string
+	method       string
+	path         string
+	summary      string
+	pathParams   []*openapi3.Parameter
+	queryParams  []*openapi3.Parameter
+	headerParams []*openapi3.Parameter
+	body         string // Go type of the JSON request body, "" for none
+	bodyOpt      bool   // whether the request body may be omitted
+	status       int    // success status code
+	result       string // Go type of the JSON response, "" for none
+}
+
+func generate(doc *openapi3.T) ([]byte, error) {
+	var ops []operation
+	var skipped []string
+	paths := doc.Paths.InMatchingOrder()
+	slices.Sort(paths)
+	for _, path := range paths {
+		item := doc.Paths.Value(path)
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			o := item.GetOperation(method)
+			if o == nil {
+				continue
+			}
+			op, ok, err := newOperation(method, path, item, o)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			if !ok {
+				skipped = append(skipped, o.OperationID)
+				continue
+			}
+			ops = append(ops, op)
+		}
+	}
+	slices.SortFunc(ops, func(a, b operation) int { return strings.Compare(a.name, b.name) })
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by hack/clientgen from api/openapi.yaml. DO NOT EDIT.\n\npackage client\n\n")
+	b.WriteString("import (\n\"context\"\n\"net/http\"\n")
+	if usesQuery(ops) || usesPath(ops) {
+		b.WriteString("\"net/url\"\n")
+	}
+	if usesStrconv(ops) {
+		b.WriteString("\"strconv\"\n")
+	}
+	b.WriteString("\n\"github.com/NissesSenap/shepherd/pkg/api\"\n")
+	if usesReadiness(ops) {
+		b.WriteString("\"github.com/NissesSenap/shepherd/pkg/readiness\"\n")
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, "// SpecVersion is the version of the API spec the client was generated from.\nconst SpecVersion = %q\n\n", doc.Info.Version)
+	if len(skipped) > 0 {
+		slices.Sort(skipped)
+		fmt.Fprintf(&b, "// Operations without a JSON or empty success response have no method: %s.\n\n",
+			strings.Join(skipped, ", "))
+	}
+	for _, op := range ops {
+		writeOperation(&b, op)
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting: %w\n%s", err, b.Bytes())
+	}
+	return src, nil
+}
+
+func newOperation(method, path string, item *openapi3.PathItem, o *openapi3.Operation) (operation, bool, error) {
+	op := operation{
+		name:    exported(o.OperationID),
+		method:  method,
+		path:    path,
+		summary: strings.TrimSpace(o.Summary),
+	}
+	for _, ref := range append(slices.Clone(item.Parameters), o.Parameters...) {
+		p := ref.Value
+		switch p.In {
+		case openapi3.ParameterInPath:
+			op.pathParams = append(op.pathParams, p)
+		case openapi3.ParameterInQuery:
+			op.queryParams = append(op.queryParams, p)
+		case openapi3.ParameterInHeader:
+			op.headerParams = append(op.headerParams, p)
+		}
+	}
+
+	if rb := o.RequestBody; rb != nil {
+		mt := rb.Value.Content.Get("application/json")
+		if mt == nil {
+			return op, false, fmt.Errorf("request body is not JSON")
+		}
+		t, err := goType(mt.Schema)
+		if err != nil {
+			return op, false, fmt.Errorf("request body: %w", err)
+		}
+		op.body = t
+		op.bodyOpt = !rb.Value.Required
+	}
+
+	for code := 200; code < 300; code++ {
+		resp := o.Responses.Status(code)
+		if resp == nil {
+			continue
+		}
+		op.status = code
+		if mt := resp.Value.Content.Get("application/json"); mt != nil {
+			t, err := goType(mt.Schema)
+			if err != nil {
+				return op, false, fmt.Errorf("%d response: %w", code, err)
+			}
+			op.result = t
+		}
+		break
+	}
+	return op, op.status != 0, nil
+}
+
+// goType returns the Go type of a schema, which must be a component schema,
+// an array of them or a free-form object.
+func goType(ref *openapi3.SchemaRef) (string, error) {
+	if ref.Ref != "" {
+		name := ref.Ref[strings.LastIndex(ref.Ref, "/")+1:]
+		if t, ok := schemaTypes[name]; ok {
+			return t, nil
+		}
+		return "api." + name, nil
+	}
+	if ref.Value.Type.Is(openapi3.TypeArray) {
+		t, err := goType(ref.Value.Items)
+		return "[]" + t, err
+	}
+	if ref.Value.Type.Is(openapi3.TypeObject) && len(ref.Value.Properties) == 0 {
+		return "map[string]any", nil
+	}
+	return "", fmt.Errorf("inline schemas are not supported, use a component schema")
+}
+
+func writeOperation(b *bytes.Buffer, op operation) {
+	params := slices.Concat(op.queryParams, op.headerParams)
+	if len(params) > 0 {
+		fmt.Fprintf(b, "// %sParams are the query and header parameters of %s.\ntype %sParams struct {\n", op.name, op.name, op.name)
+		for _, p := range params {
+			for _, line := range wrap(p.Description, 74) {
+				fmt.Fprintf(b, "// %s\n", line)
+			}
+			fmt.Fprintf(b, "%s %s\n", fieldName(p), paramType(p))
+		}
+		b.WriteString("}\n\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, p := range op.pathParams {
+		args = append(args, p.Name+" string")
+	}
+	if len(params) > 0 {
+		args = append(args, "params *"+op.name+"Params")
+	}
+	if op.body != "" {
+		if op.bodyOpt {
+			args = append(args, "body *"+op.body)
+		} else {
+			args = append(args, "body "+op.body)
+		}
+	}
+	results := "error"
+	if op.result != "" {
+		results = "(" + resultType(op.result) + ", error)"
+	}
+
+	fmt.Fprintf(b, "// %s calls %s %s", op.name, op.method, op.path)
+	if op.summary != "" {
+		fmt.Fprintf(b, ": %s", strings.TrimSuffix(op.summary, "."))
+	}
+	b.WriteString(".\n")
+	fmt.Fprintf(b, "func (c *Client) %s(%s) %s {\n", op.name, strings.Join(args, ", "), results)
+
+	fmt.Fprintf(b, "req := request{method: %s, path: %s, status: %s}\n",
+		methodConst(op.method), pathExpr(op.path), statusConst(op.status))
+	if len(params) > 0 {
+		b.WriteString("if params != nil {\n")
+		if len(op.queryParams) > 0 {
+			b.WriteString("req.query = url.Values{}\n")
+		}
+		if len(op.headerParams) > 0 {
+			b.WriteString("req.header = http.Header{}\n")
+		}
+		for _, p := range params {
+			field := "params." + fieldName(p)
+			target := "req.query"
+			if p.In == openapi3.ParameterInHeader {
+				target = "req.header"
+			}
+			fmt.Fprintf(b, "if %s {\n%s.Set(%q, %s)\n}\n", isSet(p, field), target, p.Name, formatValue(p, field))
+		}
+		b.WriteString("}\n")
+	}
+	if op.body != "" {
+		if op.bodyOpt {
+			b.WriteString("if body != nil {\nreq.body = body\n}\n")
+		} else {
+			b.WriteString("req.body = body\n")
+		}
+	}
+	if op.result == "" {
+		b.WriteString("return c.do(ctx, req, nil)\n}\n\n")
+		return
+	}
+	fmt.Fprintf(b, "var out %s\nif err := c.do(ctx, req, &out); err != nil {\nreturn nil, err\n}\n", op.result)
+	if byValue(op.result) {
+		b.WriteString("return out, nil\n}\n\n")
+	} else {
+		b.WriteString("return &out, nil\n}\n\n")
+	}
+}
+
+// resultType returns the Go type an operation returns its result as:
+// slices and maps by value, structs by pointer.
+func resultType(t string) string {
+	if byValue(t) {
+		return t
+	}
+	return "*" + t
+}
+
+func paramType(p *openapi3.Parameter) string {
+	switch {
+	case p.Schema.Value.Type.Is(openapi3.TypeInteger):
+		return "int"
+	case p.Schema.Value.Type.Is(openapi3.TypeBoolean):
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// isSet returns the Go condition under which a parameter is sent.
+func isSet(p *openapi3.Parameter, field string) string {
+	switch paramType(p) {
+	case "int":
+		return field + " != 0"
+	case "bool":
+		return field
+	default:
+		return field + ` != ""`
+	}
+}
+
+func formatValue(p *openapi3.Parameter, field string) string {
+	switch paramType(p) {
+	case "int":
+		return "strconv.Itoa(" + field + ")"
+	case "bool":
+		return "strconv.FormatBool(" + field + ")"
+	default:
+		return field
+	}
+}
+
+// fieldName returns the Go field of a parameter. Header parameters drop
+// their "X-" prefix and dashes.
+func fieldName(p *openapi3.Parameter) string {
+	name := p.Name
+	if p.In == openapi3.ParameterInHeader {
+		name = strings.TrimPrefix(name, "X-")
+		name = strings.ReplaceAll(name, "-", "")
+	}
+	return exported(name)
+}
+
+func exported(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func byValue(t string) bool {
+	return strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[")
+}
+
+// pathExpr returns the Go expression of a path, with its parameters
+// escaped.
+func pathExpr(path string) string {
+	var parts []string
+	for path != "" {
+		i := strings.IndexByte(path, '{')
+		if i < 0 {
+			parts = append(parts, fmt.Sprintf("%q", path))
+			break
+		}
+		j := strings.IndexByte(path, '}')
+		if i > 0 {
+			parts = append(parts, fmt.Sprintf("%q", path[:i]))
+		}
+		parts = append(parts, "url.PathEscape("+path[i+1:j]+")")
+		path = path[j+1:]
+	}
+	return strings.Join(parts, " + ")
+}
+
+func methodConst(method string) string {
+	return "http.Method" + method[:1] + strings.ToLower(method[1:])
+}
+
+func statusConst(code int) string {
+	switch code {
+	case http.StatusOK:
+		return "http.StatusOK"
+	case http.StatusCreated:
+		return "http.StatusCreated"
+	case http.StatusAccepted:
+		return "http.StatusAccepted"
+	case http.StatusNoContent:
+		return "http.StatusNoContent"
+	default:
+		return fmt.Sprint(code)
+	}
+}
+
+func usesQuery(ops []operation) bool {
+	return slices.ContainsFunc(ops, func(op operation) bool { return len(op.queryParams) > 0 })
+}
+
+func usesPath(ops []operation) bool {
+	return slices.ContainsFunc(ops, func(op operation) bool { return len(op.pathParams) > 0 })
+}
+
+func usesStrconv(ops []operation) bool {
+	return slices.ContainsFunc(ops, func(op operation) bool {
+		return slices.ContainsFunc(slices.Concat(op.queryParams, op.headerParams), func(p *openapi3.Parameter) bool {
+			return paramType(p) != "string"
+		})
+	})
+}
+
+func usesReadiness(ops []operation) bool {
+	return slices.ContainsFunc(ops, func(op operation) bool { return strings.Contains(op.result, "readiness.") })
+}
+
+// wrap splits text into lines of at most width characters.
+func wrap(text string, width int) []string {
+	var lines []string
+	var line string
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// queuedRequeueInterval is how often a task waiting for capacity re-checks
+// whether it can be admitted.
+const queuedRequeueInterval = 10 * time.Second
+
+// Queue orders for tasks waiting for capacity.
+const (
+	QueueOrderPriority = "priority" // highest spec.priority first, then oldest
+	QueueOrderFIFO     = "fifo"     // oldest first
+)
+
+// admission is the outcome of an admission check for a task without a
+// SandboxClaim.
+type admission struct {
+	Admitted bool
+	Position int // 1-based position in the queue when not admitted
+	// Template is set when the task is held back by the limit of its
+	// sandbox template rather than the controller-wide one; Position is
+	// then its place among tasks waiting for that template.
+	Template string
+}
+
+// admit decides whether a task may get a SandboxClaim under the
+// MaxConcurrentTasks and MaxConcurrentTasksPerTemplate limits. Tasks holding
+// a claim count against the limits. Waiting tasks are admitted in queue
+// order as long as both their template and the controller have a free
+// slot; a task held back by its template's limit does not block tasks of
+// other templates behind it.
+//
+// The check reads from the informer cache, so a claim created moments ago
+// may not be counted yet. The limits are therefore soft ones that can be
+// briefly exceeded, never a reason to fail a task.
+func (r *AgentTaskReconciler) admit(ctx context.Context, task *toolkitv1alpha1.AgentTask) (admission, error) {
+	if r.MaxConcurrentTasks <= 0 && len(r.MaxConcurrentTasksPerTemplate) == 0 {
+		return admission{Admitted: true}, nil
+	}
+
+	var tasks toolkitv1alpha1.AgentTaskList
+	if err := r.List(ctx, &tasks); err != nil {
+		return admission{}, fmt.Errorf("listing tasks for admission: %w", err)
+	}
+
+	active := 0
+	activeByTemplate := map[string]int{}
+	var waiting []*toolkitv1alpha1.AgentTask
+	for i := range tasks.Items {
+		t := &tasks.Items[i]
+		if t.IsTerminal() {
+			continue
+		}
+		if t.Status.SandboxClaimName != "" {
+			active++
+			activeByTemplate[r.sandboxTemplate(t)]++
+			continue
+		}
+		if (isWaitingForDependency(t) || t.Spec.Suspend) && !sameTask(t, task) {
+			// Blocked tasks must not hold a queue position ahead of runnable ones
+			continue
+		}
+		waiting = append(waiting, t)
+	}
+	if !slices.ContainsFunc(waiting, func(t *toolkitv1alpha1.AgentTask) bool { return sameTask(t, task) }) {
+		// Not in the cache yet; queue it behind everything we know about
+		waiting = append(waiting, task)
+	}
+	compare := compareQueueOrder
+	if r.QueueOrder == QueueOrderFIFO {
+		compare = compareFIFOOrder
+	}
+	slices.SortFunc(waiting, compare)
+
+	free := math.MaxInt
+	if r.MaxConcurrentTasks > 0 {
+		free = r.MaxConcurrentTasks - active
+	}
+	freeByTemplate := map[string]int{}
+	for tmpl, limit := range r.MaxConcurrentTasksPerTemplate {
+		freeByTemplate[tmpl] = limit - activeByTemplate[tmpl]
+	}
+
+	// Walk the queue, handing out slots, until we reach the task.
+	position := 0
+	positionInTemplate := map[string]int{}
+	for _, t := range waiting {
+		tmpl := r.sandboxTemplate(t)
+		tmplFree, tmplLimited := freeByTemplate[tmpl]
+		if tmplLimited && tmplFree <= 0 {
+			positionInTemplate[tmpl]++
+			if sameTask(t, task) {
+				return admission{Position: positionInTemplate[tmpl], Template: tmpl}, nil
+			}
+			continue
+		}
+		position++
+		if free <= 0 {
+			if sameTask(t, task) {
+				return admission{Position: position}, nil
+			}
+			continue
+		}
+		if sameTask(t, task) {
+			return admission{Admitted: true}, nil
+		}
+		free--
+		if tmplLimited {
+			freeByTemplate[tmpl]--
+		}
+	}
+	return admission{Admitted: true}, nil
+}
+
+func sameTask(a, b *toolkitv1alpha1.AgentTask) bool {
+	return a.Namespace == b.Namespace && a.Name == b.Name
+}
+
+// compareQueueOrder orders waiting tasks by priority (descending), then
+// creation time and name so the queue order is stable.
+func compareQueueOrder(a, b *toolkitv1alpha1.AgentTask) int {
+	return cmp.Or(
+		cmp.Compare(b.Spec.Priority, a.Spec.Priority),
+		compareFIFOOrder(a, b),
+	)
+}
+
+// compareFIFOOrder orders waiting tasks by creation time, ignoring priority.
+func compareFIFOOrder(a, b *toolkitv1alpha1.AgentTask) int {
+	return cmp.Or(
+		a.CreationTimestamp.Compare(b.CreationTimestamp.Time),
+		cmp.Compare(a.Namespace, b.Namespace),
+		cmp.Compare(a.Name, b.Name),
+	)
+}
+
+// isWaitingForDependency reports whether the task was last seen blocked on
+// spec.dependsOn.
+func isWaitingForDependency(t *toolkitv1alpha1.AgentTask) bool {
+	cond := meta.FindStatusCondition(t.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	return cond != nil && cond.Reason == toolkitv1alpha1.ReasonWaitingForDependency
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+var admissionBase = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func queuedTask(name string, priority int32, createdMinute int) *toolkitv1alpha1.AgentTask {
+	return &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(admissionBase.Add(time.Duration(createdMinute) * time.Minute)),
+		},
+		Spec: toolkitv1alpha1.AgentTaskSpec{Priority: priority},
+	}
+}
+
+func activeTask(name string) *toolkitv1alpha1.AgentTask {
+	t := queuedTask(name, 0, 0)
+	t.Status.SandboxClaimName = name
+	return t
+}
+
+func newAdmissionReconciler(t *testing.T, limit int, objs ...client.Object) *AgentTaskReconciler {
+	t.Helper()
+	s := runtime.NewScheme()
+	require.NoError(t, toolkitv1alpha1.AddToScheme(s))
+	return &AgentTaskReconciler{
+		Client:             fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(),
+		Scheme:             s,
+		MaxConcurrentTasks: limit,
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	low := queuedTask("task-low", 0, 1)
+	high := queuedTask("task-high", 100, 3)
+	lowLater := queuedTask("task-low-later", 0, 2)
+	done := taskWithCondition(metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded)
+	done.Name, done.Namespace, done.Status.SandboxClaimName = "task-done", "default", "task-done"
+	blocked := queuedTask("task-blocked", 500, 0)
+	blocked.Status.Conditions = taskWithCondition(metav1.ConditionUnknown, toolkitv1alpha1.ReasonWaitingForDependency).Status.Conditions
+
+	tests := []struct {
+		name  string
+		limit int
+		objs  []client.Object
+		task  *toolkitv1alpha1.AgentTask
+		want  admission
+	}{
+		{
+			name: "unlimited admits everything",
+			objs: []client.Object{activeTask("task-a"), low},
+			task: low,
+			want: admission{Admitted: true},
+		},
+		{
+			name:  "free slot goes to highest priority",
+			limit: 2,
+			objs:  []client.Object{activeTask("task-a"), low, high},
+			task:  high,
+			want:  admission{Admitted: true},
+		},
+		{
+			name:  "lower priority waits behind higher priority",
+			limit: 2,
+			objs:  []client.Object{activeTask("task-a"), low, high},
+			task:  low,
+			want:  admission{Position: 2},
+		},
+		{
+			name:  "equal priority is FIFO",
+			limit: 2,
+			objs:  []client.Object{activeTask("task-a"), lowLater, low},
+			task:  lowLater,
+			want:  admission{Position: 2},
+		},
+		{
+			name:  "full capacity queues all",
+			limit: 1,
+			objs:  []client.Object{activeTask("task-a"), high},
+			task:  high,
+			want:  admission{Position: 1},
+		},
+		{
+			name:  "tasks waiting for a dependency do not hold a queue position",
+			limit: 2,
+			objs:  []client.Object{activeTask("task-a"), blocked, low},
+			task:  low,
+			want:  admission{Admitted: true},
+		},
+		{
+			name:  "terminal tasks do not hold a slot",
+			limit: 1,
+			objs:  []client.Object{done, low},
+			task:  low,
+			want:  admission{Admitted: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newAdmissionReconciler(t, tt.limit, tt.objs...)
+
+			got, err := r.admit(context.Background(), tt.task)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func templateTask(name, template string, priority int32, createdMinute int) *toolkitv1alpha1.AgentTask {
+	t := queuedTask(name, priority, createdMinute)
+	t.Spec.Runner.SandboxTemplateName = template
+	return t
+}
+
+func TestAdmit_TemplateLimits(t *testing.T) {
+	gpuActive := templateTask("task-gpu-active", "gpu", 0, 0)
+	gpuActive.Status.SandboxClaimName = gpuActive.Name
+	gpuWaiting := templateTask("task-gpu-waiting", "gpu", 100, 1)
+	gpuLater := templateTask("task-gpu-later", "gpu", 100, 2)
+	small := templateTask("task-small", "small", 0, 3)
+
+	tests := []struct {
+		name  string
+		limit int
+		task  *toolkitv1alpha1.AgentTask
+		want  admission
+	}{
+		{"template limit reached", 0, gpuWaiting, admission{Position: 1, Template: "gpu"}},
+		{"position counts within the template", 0, gpuLater, admission{Position: 2, Template: "gpu"}},
+		{"other templates are not blocked", 2, small, admission{Admitted: true}},
+		{"global limit still applies", 1, small, admission{Position: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newAdmissionReconciler(t, tt.limit, gpuActive, gpuWaiting, gpuLater, small)
+			r.MaxConcurrentTasksPerTemplate = map[string]int{"gpu": 1}
+
+			got, err := r.admit(context.Background(), tt.task)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAdmit_FIFO(t *testing.T) {
+	old := queuedTask("task-old", 0, 1)
+	urgent := queuedTask("task-urgent", 1000, 2)
+
+	r := newAdmissionReconciler(t, 1, old, urgent)
+	r.QueueOrder = QueueOrderFIFO
+
+	got, err := r.admit(context.Background(), old)
+	require.NoError(t, err)
+	assert.Equal(t, admission{Admitted: true}, got)
+
+	got, err = r.admit(context.Background(), urgent)
+	require.NoError(t, err)
+	assert.Equal(t, admission{Position: 2}, got)
+}
+
+func TestReconcile_Queued(t *testing.T) {
+	task := templateTask("task-queued", "gpu", 10, 1)
+	task.Status.Conditions = taskWithCondition(metav1.ConditionUnknown, toolkitv1alpha1.ReasonPending).Status.Conditions
+	active := templateTask("task-active", "gpu", 0, 0)
+	active.Status.SandboxClaimName = active.Name
+
+	r := newDependencyReconciler(t, task, active)
+	r.MaxConcurrentTasksPerTemplate = map[string]int{"gpu": 1}
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(task)})
+	require.NoError(t, err)
+	assert.Equal(t, queuedRequeueInterval, result.RequeueAfter)
+
+	var got toolkitv1alpha1.AgentTask
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(task), &got))
+	cond := meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionUnknown, cond.Status)
+	assert.Equal(t, toolkitv1alpha1.ReasonQueued, cond.Reason)
+	assert.Equal(t, `Waiting for capacity of sandbox template "gpu", position 1 in queue (priority 10)`, cond.Message)
+}
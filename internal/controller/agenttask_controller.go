@@ -20,12 +20,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
-	"k8s.io/apimachinery/pkg/api/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -33,10 +37,15 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
 	sandboxv1alpha1 "sigs.k8s.io/agent-sandbox/api/v1alpha1"
 	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
 )
@@ -48,58 +57,137 @@ type AgentTaskReconciler struct {
 	Recorder   events.EventRecorder
 	APIURL     string       // Internal API URL for runner task assignment
 	HTTPClient *http.Client // Injectable for testing; defaults to http.DefaultClient
+	// MaxConcurrentTasks caps how many tasks hold a SandboxClaim at once.
+	// Zero means unlimited.
+	MaxConcurrentTasks int
+	// MaxConcurrentTasksPerTemplate caps how many tasks using a sandbox
+	// template hold a SandboxClaim at once, keyed by template name.
+	// Templates without an entry are only bound by MaxConcurrentTasks.
+	MaxConcurrentTasksPerTemplate map[string]int
+	// QueueOrder is the order waiting tasks are admitted in, QueueOrderFIFO
+	// or QueueOrderPriority (the default).
+	QueueOrder string
+	// TemplateRoutes pick the sandbox template of a task from its labels
+	// when its claim is built; the first matching route wins.
+	TemplateRoutes []TemplateRoute
+	// TTLAfterFinished deletes Succeeded and Failed tasks this long after
+	// they finished, unless a task sets spec.ttlAfterFinished. Zero keeps
+	// them.
+	TTLAfterFinished time.Duration
+	// TimeoutWarningPercent is the share of its runner timeout, in percent,
+	// after which a running task gets the TimeoutWarning condition. Zero
+	// disables the warning.
+	TimeoutWarningPercent int
+	// Clock returns the current time; defaults to time.Now.
+	Clock func() time.Time
+	// RateLimit tunes retries of failed reconciles and how often a single
+	// task may be reconciled.
+	RateLimit RateLimitOptions
+
+	taskLimiter *taskRateLimiter
 }
 
+// RunnerProtocolVersion is the version of the task assignment protocol the
+// operator speaks. Runners built for another version refuse assignments, and
+// the task fails with a message naming both versions. It must match
+// runner.ProtocolVersion.
+const RunnerProtocolVersion = 1
+
 // TaskAssignment is the payload POSTed to the runner's /task endpoint.
 type TaskAssignment struct {
-	TaskID string `json:"taskID"`
-	APIURL string `json:"apiURL"`
+	TaskID          string `json:"taskID"`
+	APIURL          string `json:"apiURL"`
+	ProtocolVersion int    `json:"protocolVersion"`
+	// CorrelationID is the task's correlation ID, for the runner's logs
+	// and its requests to the API.
+	CorrelationID string `json:"correlationID,omitempty"`
+	// TraceParent is the W3C traceparent of the reconcile that assigned
+	// the task, so the runner's work joins the task's trace.
+	TraceParent string `json:"traceparent,omitempty"`
 }
 
-// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=agenttasks,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=agenttasks,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=agenttasks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=agenttasks/finalizers,verbs=update
-// +kubebuilder:rbac:groups=extensions.agents.x-k8s.io,resources=sandboxclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=extensions.agents.x-k8s.io,resources=sandboxclaims,verbs=get;list;watch;create;patch;delete
 // +kubebuilder:rbac:groups=agents.x-k8s.io,resources=sandboxes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
 
 func (r *AgentTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := logf.FromContext(ctx)
+	log := logf.FromContext(ctx).WithValues(logging.TaskID, req.Name)
+
+	if r.taskLimiter != nil {
+		if delay := r.taskLimiter.delay(req.NamespacedName, r.now()); delay > 0 {
+			log.V(1).Info("task reconciled too often, delaying", "delay", delay)
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+	}
 
 	// 1. Fetch the AgentTask
 	var task toolkitv1alpha1.AgentTask
 	if err := r.Get(ctx, req.NamespacedName, &task); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	// Reconciles are part of the trace of the request that created the task.
+	ctx, span := tracing.Tracer().Start(tracing.FromAnnotations(ctx, task.Annotations), "reconcile AgentTask",
+		trace.WithAttributes(attribute.String(logging.TaskID, task.Name)))
+	defer span.End()
+
+	log = log.WithValues(logging.Repo, task.Spec.Repo.URL)
+	if cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded); cond != nil {
+		log = log.WithValues(logging.Phase, cond.Reason)
+	}
+	log = logging.ForTask(log, task.Annotations)
+	ctx = logf.IntoContext(ctx, log)
+	log.V(1).Info("reconciling task", "generation", task.Generation, "sandboxClaim", task.Status.SandboxClaimName)
+
+	// 1a. Catch status.phase up with conditions the API server set, such
+	// as a runner reporting completion
+	if task.Status.Phase != task.ComputePhase() {
+		if err := r.patchStatus(ctx, &task, task.DeepCopy()); err != nil {
+			return ctrl.Result{}, fmt.Errorf("updating phase: %w", err)
+		}
+	}
+
+	// 1b. Deleted → release the sandbox and notify the adapter before the task goes away
+	if !task.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &task)
+	}
 
-	// 2. If terminal → clean up SandboxClaim if still exists, then return
+	// 2. If terminal → clean up SandboxClaim if still exists, then delete the task once its TTL passed
 	if task.IsTerminal() {
-		log.V(1).Info("task is terminal, checking for SandboxClaim cleanup", "task", req.NamespacedName)
+		log.V(1).Info("task is terminal, checking for SandboxClaim cleanup")
 		if err := r.cleanupSandboxClaim(ctx, &task); err != nil {
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, nil
+		return r.expireFinished(ctx, &task)
+	}
+
+	// 2a. Hold deletion of unfinished tasks until cleanup has run
+	if err := r.addFinalizer(ctx, &task); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// 3. Initialize condition if not set → set Pending, requeue
 	if !hasCondition(&task, toolkitv1alpha1.ConditionSucceeded) {
+		base := task.DeepCopy()
 		setCondition(&task, metav1.Condition{
 			Type:               toolkitv1alpha1.ConditionSucceeded,
 			Status:             metav1.ConditionUnknown,
 			Reason:             toolkitv1alpha1.ReasonPending,
-			Message:            "Waiting for sandbox to start",
+			Message:            pendingMessage,
 			ObservedGeneration: task.Generation,
 		})
 		task.Status.ObservedGeneration = task.Generation
 
-		if err := r.Status().Update(ctx, &task); err != nil {
+		if err := r.patchStatus(ctx, &task, base); err != nil {
 			return ctrl.Result{}, fmt.Errorf("updating initial status: %w", err)
 		}
 		// events.EventRecorder uses (regarding, related, type, reason, action, note) signature
 		r.Recorder.Eventf(&task, nil, "Normal", "Pending", "Reconcile", "Task accepted, waiting for sandbox creation")
-		log.Info("initialized task status", "task", req.NamespacedName)
+		log.Info("initialized task status")
 		// Use RequeueAfter instead of deprecated Requeue: true (controller-runtime v0.23+ PR #3107)
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
@@ -112,23 +200,66 @@ func (r *AgentTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if client.IgnoreNotFound(err) != nil {
 		return ctrl.Result{}, fmt.Errorf("getting sandbox claim: %w", err)
 	}
+	var existing *sandboxextv1alpha1.SandboxClaim
+	if err == nil {
+		existing = &claim
+	}
+
+	// 4a. Suspended before the runner started → release the sandbox and wait
+	if task.Spec.Suspend && !hasReason(&task, toolkitv1alpha1.ReasonRunning) {
+		return r.suspend(ctx, &task, existing)
+	}
+	if hasReason(&task, toolkitv1alpha1.ReasonSuspended) {
+		return r.resume(ctx, &task, existing)
+	}
 
-	// 5. No SandboxClaim → create it
+	// 5. No SandboxClaim → create it once dependencies succeeded and the task is admitted
 	if err != nil {
+		deps, depErr := r.checkDependencies(ctx, &task)
+		if depErr != nil {
+			return ctrl.Result{}, depErr
+		}
+		if deps.Failed {
+			return r.markFailed(ctx, &task, toolkitv1alpha1.ReasonFailed, deps.Message)
+		}
+		if !deps.Satisfied {
+			return r.markWaitingForDependency(ctx, &task, deps)
+		}
+
+		adm, admitErr := r.admit(ctx, &task)
+		if admitErr != nil {
+			return ctrl.Result{}, admitErr
+		}
+		if !adm.Admitted {
+			return r.markQueued(ctx, &task, adm)
+		}
+
 		newClaim, buildErr := buildSandboxClaim(&task, sandboxConfig{
-			Scheme: r.Scheme,
+			Scheme:   r.Scheme,
+			Now:      r.now(),
+			Template: r.sandboxTemplate(&task),
 		})
 		if buildErr != nil {
 			return r.markFailed(ctx, &task, toolkitv1alpha1.ReasonFailed,
 				fmt.Sprintf("failed to build sandbox claim: %v", buildErr))
 		}
-		if createErr := r.Create(ctx, newClaim); createErr != nil {
-			return ctrl.Result{}, fmt.Errorf("creating sandbox claim: %w", createErr)
+		if applyErr := r.apply(ctx, newClaim); applyErr != nil {
+			return ctrl.Result{}, fmt.Errorf("applying sandbox claim: %w", applyErr)
 		}
 
+		base := task.DeepCopy()
 		task.Status.SandboxClaimName = newClaim.Name
+		task.Status.SandboxTemplateName = newClaim.Spec.TemplateRef.Name
+		// Clear a "waiting for capacity" or dependency message
+		setCondition(&task, metav1.Condition{
+			Type:               toolkitv1alpha1.ConditionSucceeded,
+			Status:             metav1.ConditionUnknown,
+			Reason:             toolkitv1alpha1.ReasonPending,
+			Message:            pendingMessage,
+			ObservedGeneration: task.Generation,
+		})
 
-		if statusErr := r.Status().Update(ctx, &task); statusErr != nil {
+		if statusErr := r.patchStatus(ctx, &task, base); statusErr != nil {
 			return ctrl.Result{}, fmt.Errorf("updating status after sandbox claim creation: %w", statusErr)
 		}
 		r.Recorder.Eventf(&task, nil, "Normal", "SandboxClaimCreated", "Reconcile", "Created sandbox claim %s", newClaim.Name)
@@ -138,14 +269,38 @@ func (r *AgentTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	// 5a. Claim exists — backfill SandboxClaimName if empty (e.g., after crash between creation and status update)
 	if task.Status.SandboxClaimName == "" {
+		base := task.DeepCopy()
 		task.Status.SandboxClaimName = claim.Name
-		if statusErr := r.Status().Update(ctx, &task); statusErr != nil {
+		if statusErr := r.patchStatus(ctx, &task, base); statusErr != nil {
 			return ctrl.Result{}, fmt.Errorf("backfilling sandbox claim name: %w", statusErr)
 		}
 		log.Info("backfilled sandbox claim name", "claim", claim.Name)
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
 
+	// 5b. Extend the claim's shutdown time by the task's timeout extensions
+	extended, err := r.extendClaim(ctx, &task, &claim)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if extended {
+		r.Recorder.Eventf(&task, nil, "Normal", "TimeoutExtended", "Reconcile",
+			"Extended sandbox claim %s to a timeout of %s", claim.Name, task.RunnerTimeout())
+		log.Info("extended sandbox claim", "claim", claim.Name, "timeout", task.RunnerTimeout())
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	// 5c. Undo hand edits to the fields the operator manages on the claim
+	corrected, err := r.reconcileClaimDrift(ctx, &task, &claim)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if corrected {
+		r.Recorder.Eventf(&task, nil, "Normal", "SandboxClaimCorrected", "Reconcile", "Reverted changes to sandbox claim %s", claim.Name)
+		log.Info("corrected drift on sandbox claim", "claim", claim.Name)
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
 	// 6. SandboxClaim exists — check Ready condition
 	readyCond := meta.FindStatusCondition(claim.Status.Conditions, string(sandboxv1alpha1.SandboxConditionReady))
 
@@ -156,6 +311,13 @@ func (r *AgentTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if readyCond != nil && readyCond.Status == metav1.ConditionTrue {
 		if isRunning {
 			log.V(1).Info("sandbox ready and task already running", "claim", claim.Name)
+			wait, err := r.warnTimeout(ctx, &task)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if wait > 0 && wait < requeueInterval {
+				return ctrl.Result{RequeueAfter: wait}, nil
+			}
 			return ctrl.Result{RequeueAfter: requeueInterval}, nil
 		}
 
@@ -178,17 +340,40 @@ func (r *AgentTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 		// POST task assignment to the runner
 		assignment := TaskAssignment{
-			TaskID: task.Name,
-			APIURL: r.APIURL,
+			TaskID:          task.Name,
+			APIURL:          r.APIURL,
+			ProtocolVersion: RunnerProtocolVersion,
+			CorrelationID:   task.Annotations[logging.CorrelationIDAnnotation],
+			TraceParent:     tracing.TraceParent(ctx),
 		}
-		if err := r.assignTask(ctx, sandbox.Status.ServiceFQDN, assignment); err != nil {
+		runnerVersion, err := r.assignTask(ctx, sandbox.Status.ServiceFQDN, assignment)
+		if err != nil {
+			taskAssignmentFailures.Inc()
+			var incompatible *incompatibleRunnerError
+			if errors.As(err, &incompatible) {
+				// Retrying cannot help until the runner image or the
+				// operator is upgraded, so fail the task loudly.
+				return r.markFailed(ctx, &task, toolkitv1alpha1.ReasonFailed,
+					fmt.Sprintf("Runner of sandbox template %q is incompatible with the operator: %v", r.sandboxTemplate(&task), err))
+			}
 			log.Error(err, "task assignment failed", "sandbox", sandboxName)
 			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 		}
+		switch runnerVersion {
+		case RunnerProtocolVersion:
+		case 0:
+			r.Recorder.Eventf(&task, nil, "Warning", "RunnerProtocolUnknown", "Reconcile",
+				"Runner did not report a protocol version; upgrade the runner image to one that speaks version %d", RunnerProtocolVersion)
+		default:
+			r.Recorder.Eventf(&task, nil, "Warning", "RunnerProtocolMismatch", "Reconcile",
+				"Runner accepted the task with protocol version %d, operator speaks version %d", runnerVersion, RunnerProtocolVersion)
+		}
 
 		// Assignment succeeded — set Running (this IS the idempotency marker) and record StartTime
-		now := metav1.Now()
+		base := task.DeepCopy()
+		now := metav1.NewTime(r.now())
 		task.Status.StartTime = &now
+		task.Status.RunnerProtocolVersion = int32(runnerVersion)
 		setCondition(&task, metav1.Condition{
 			Type:               toolkitv1alpha1.ConditionSucceeded,
 			Status:             metav1.ConditionUnknown,
@@ -196,9 +381,11 @@ func (r *AgentTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			Message:            "Sandbox is ready, task assigned to runner",
 			ObservedGeneration: task.Generation,
 		})
-		if statusErr := r.Status().Update(ctx, &task); statusErr != nil {
+		if statusErr := r.patchStatus(ctx, &task, base); statusErr != nil {
 			return ctrl.Result{}, fmt.Errorf("updating status to running: %w", statusErr)
 		}
+		sandboxProvisioningSeconds.WithLabelValues(r.sandboxTemplate(&task)).
+			Observe(now.Sub(claim.CreationTimestamp.Time).Seconds())
 		r.Recorder.Eventf(&task, nil, "Normal", "Running", "Reconcile", "Task assigned to sandbox %s", sandboxName)
 		log.Info("task assigned and running", "sandbox", sandboxName, "claim", claim.Name)
 		return ctrl.Result{RequeueAfter: requeueInterval}, nil
@@ -215,10 +402,62 @@ func (r *AgentTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 }
 
-// assignTask POSTs a task assignment to the runner's HTTP endpoint.
-// Returns nil on success (200 OK or 409 Conflict), error otherwise.
+// markQueued records that the task is waiting for capacity and requeues it.
+// The Queued condition message is only updated when the queue position
+// changes, to avoid a status write on every poll.
+func (r *AgentTaskReconciler) markQueued(ctx context.Context, task *toolkitv1alpha1.AgentTask, adm admission) (ctrl.Result, error) {
+	message := fmt.Sprintf("Waiting for capacity, position %d in queue (priority %d)", adm.Position, task.Spec.Priority)
+	if adm.Template != "" {
+		message = fmt.Sprintf("Waiting for capacity of sandbox template %q, position %d in queue (priority %d)",
+			adm.Template, adm.Position, task.Spec.Priority)
+	}
+	cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	if cond != nil && cond.Reason == toolkitv1alpha1.ReasonQueued && cond.Message == message {
+		return ctrl.Result{RequeueAfter: queuedRequeueInterval}, nil
+	}
+
+	base := task.DeepCopy()
+	setCondition(task, metav1.Condition{
+		Type:               toolkitv1alpha1.ConditionSucceeded,
+		Status:             metav1.ConditionUnknown,
+		Reason:             toolkitv1alpha1.ReasonQueued,
+		Message:            message,
+		ObservedGeneration: task.Generation,
+	})
+	if err := r.patchStatus(ctx, task, base); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating queued status: %w", err)
+	}
+	logf.FromContext(ctx).V(1).Info("task queued, concurrency limit reached",
+		"position", adm.Position, "template", adm.Template, "priority", task.Spec.Priority)
+	return ctrl.Result{RequeueAfter: queuedRequeueInterval}, nil
+}
+
+// assignmentResponse is the runner's reply to a task assignment.
+type assignmentResponse struct {
+	Error string `json:"error"`
+	// ProtocolVersion is the runner's protocol version. Runners that
+	// predate versioning do not send it.
+	ProtocolVersion int `json:"protocolVersion"`
+}
+
+// incompatibleRunnerError is returned by assignTask when the runner refused
+// the assignment and speaks another protocol version.
+type incompatibleRunnerError struct {
+	runnerVersion int
+	message       string
+}
+
+func (e *incompatibleRunnerError) Error() string {
+	return fmt.Sprintf("runner speaks protocol version %d, operator speaks version %d: %s",
+		e.runnerVersion, RunnerProtocolVersion, e.message)
+}
+
+// assignTask POSTs a task assignment to the runner's HTTP endpoint and
+// returns the protocol version the runner reported, or 0 if it did not.
+// Returns nil on success (200 OK or 409 Conflict), error otherwise; an
+// *incompatibleRunnerError means retrying will not help.
 // The caller handles retries via controller-runtime's RequeueAfter.
-func (r *AgentTaskReconciler) assignTask(ctx context.Context, sandboxFQDN string, assignment TaskAssignment) error {
+func (r *AgentTaskReconciler) assignTask(ctx context.Context, sandboxFQDN string, assignment TaskAssignment) (int, error) {
 	log := logf.FromContext(ctx)
 	httpClient := r.HTTPClient
 	if httpClient == nil {
@@ -227,32 +466,41 @@ func (r *AgentTaskReconciler) assignTask(ctx context.Context, sandboxFQDN string
 
 	body, err := json.Marshal(assignment)
 	if err != nil {
-		return fmt.Errorf("marshaling assignment: %w", err)
+		return 0, fmt.Errorf("marshaling assignment: %w", err)
 	}
 
 	url := fmt.Sprintf("http://%s:8888/task", sandboxFQDN)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("building request: %w", err)
+		return 0, fmt.Errorf("building request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("posting to runner: %w", err)
+		return 0, fmt.Errorf("posting to runner: %w", err)
 	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
 	_, _ = io.Copy(io.Discard, resp.Body)
 	_ = resp.Body.Close()
 
+	// Runners that predate versioning reply in plain text on errors, so a
+	// body that does not parse just leaves the version unknown.
+	var reply assignmentResponse
+	_ = json.Unmarshal(respBody, &reply)
+
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return nil
+		return reply.ProtocolVersion, nil
 	case http.StatusConflict:
 		// Runner already has this task (idempotent retry after crash)
 		log.V(1).Info("runner already has task (409), treating as success")
-		return nil
+		return reply.ProtocolVersion, nil
 	default:
-		return fmt.Errorf("runner returned %d", resp.StatusCode)
+		if reply.ProtocolVersion != 0 && reply.ProtocolVersion != RunnerProtocolVersion {
+			return reply.ProtocolVersion, &incompatibleRunnerError{runnerVersion: reply.ProtocolVersion, message: reply.Error}
+		}
+		return reply.ProtocolVersion, fmt.Errorf("runner returned %d", resp.StatusCode)
 	}
 }
 
@@ -280,7 +528,8 @@ func (r *AgentTaskReconciler) cleanupSandboxClaim(ctx context.Context, task *too
 }
 
 func (r *AgentTaskReconciler) markFailed(ctx context.Context, task *toolkitv1alpha1.AgentTask, reason, message string) (ctrl.Result, error) {
-	now := metav1.Now()
+	base := task.DeepCopy()
+	now := metav1.NewTime(r.now())
 	task.Status.CompletionTime = &now
 	task.Status.Result.Error = message
 	setCondition(task, metav1.Condition{
@@ -290,7 +539,7 @@ func (r *AgentTaskReconciler) markFailed(ctx context.Context, task *toolkitv1alp
 		Message:            message,
 		ObservedGeneration: task.Generation,
 	})
-	if err := r.Status().Update(ctx, task); err != nil {
+	if err := r.patchStatus(ctx, task, base); err != nil {
 		return ctrl.Result{}, fmt.Errorf("marking failed: %w", err)
 	}
 	r.Recorder.Eventf(task, nil, "Warning", reason, "Reconcile", message)
@@ -311,6 +560,7 @@ func (r *AgentTaskReconciler) handleSandboxTermination(
 	if err := r.Get(ctx, req.NamespacedName, &freshTask); err != nil {
 		return ctrl.Result{}, fmt.Errorf("refetching task: %w", err)
 	}
+	base := freshTask.DeepCopy()
 	if freshTask.IsTerminal() {
 		log.Info("task already terminal after refetch, cleaning up SandboxClaim")
 		if err := r.cleanupSandboxClaim(ctx, &freshTask); err != nil {
@@ -321,11 +571,18 @@ func (r *AgentTaskReconciler) handleSandboxTermination(
 
 	// Grace period: give the API time to process the runner's callback.
 	// Use a status field to track the grace deadline.
-	const graceDuration = 30 * time.Second
+	now := r.now()
+	if freshTask.Status.GraceDeadline != nil && deadlineImplausible(now, freshTask.Status.GraceDeadline.Time, graceDuration) {
+		// Set by a previous leader whose clock ran ahead of ours; restart the
+		// grace period on our clock rather than wait out the skew.
+		log.Info("grace deadline is further in the future than the grace period, resetting it",
+			"graceDeadline", freshTask.Status.GraceDeadline.Time)
+		freshTask.Status.GraceDeadline = nil
+	}
 
 	if freshTask.Status.GraceDeadline != nil {
 		// Grace period was already set — check if it has elapsed
-		if time.Now().After(freshTask.Status.GraceDeadline.Time) {
+		if deadlinePassed(now, freshTask.Status.GraceDeadline.Time) {
 			// Grace period elapsed — refetch claim (it may have changed during grace period),
 			// classify termination reason, then cleanup claim and mark failed
 
@@ -341,14 +598,15 @@ func (r *AgentTaskReconciler) handleSandboxTermination(
 			message := "Sandbox terminated unexpectedly"
 
 			if err := r.Get(ctx, claimKey, &freshClaim); err != nil {
-				if !errors.IsNotFound(err) {
+				if !apierrors.IsNotFound(err) {
 					return ctrl.Result{}, fmt.Errorf("refetching claim: %w", err)
 				}
 				// Claim is gone — use generic message
 				log.V(1).Info("claim not found during grace expiration, using generic failure message")
 			} else {
 				// Classify termination based on claim status
-				reason, message = classifyClaimTermination(&freshClaim)
+				observedAt := freshTask.Status.GraceDeadline.Add(-graceDuration)
+				reason, message = classifyClaimTermination(&freshClaim, observedAt)
 			}
 
 			log.Info("grace period elapsed, cleaning up and marking task terminal", "reason", reason)
@@ -359,9 +617,9 @@ func (r *AgentTaskReconciler) handleSandboxTermination(
 			}
 
 			// Clear GraceDeadline and mark failed in one status update
-			now := metav1.Now()
+			completionTime := metav1.NewTime(now)
 			freshTask.Status.GraceDeadline = nil
-			freshTask.Status.CompletionTime = &now
+			freshTask.Status.CompletionTime = &completionTime
 			freshTask.Status.Result.Error = message
 			setCondition(&freshTask, metav1.Condition{
 				Type:               toolkitv1alpha1.ConditionSucceeded,
@@ -370,26 +628,30 @@ func (r *AgentTaskReconciler) handleSandboxTermination(
 				Message:            message,
 				ObservedGeneration: freshTask.Generation,
 			})
-			if err := r.Status().Update(ctx, &freshTask); err != nil {
+			if err := r.patchStatus(ctx, &freshTask, base); err != nil {
 				return ctrl.Result{}, fmt.Errorf("marking failed: %w", err)
 			}
+			gracePeriodExpirations.WithLabelValues(reason).Inc()
+			if reason == toolkitv1alpha1.ReasonTimedOut {
+				tasksTimedOut.Inc()
+			}
 			r.Recorder.Eventf(&freshTask, nil, "Warning", reason, "Reconcile", message)
 			return ctrl.Result{}, nil
 		}
 		// Still within grace period — requeue until deadline
-		remaining := time.Until(freshTask.Status.GraceDeadline.Time)
+		remaining := untilDeadline(now, freshTask.Status.GraceDeadline.Time)
 		log.V(1).Info("within grace period, requeuing", "remaining", remaining)
 		return ctrl.Result{RequeueAfter: remaining}, nil
 	}
 
 	// First time seeing Ready=False while Running — start grace period
-	graceDeadline := metav1.NewTime(time.Now().Add(graceDuration))
+	graceDeadline := deadlineAfter(now, graceDuration)
 	freshTask.Status.GraceDeadline = &graceDeadline
-	if err := r.Status().Update(ctx, &freshTask); err != nil {
+	if err := r.patchStatus(ctx, &freshTask, base); err != nil {
 		return ctrl.Result{}, fmt.Errorf("setting grace deadline: %w", err)
 	}
 	log.Info("started grace period for sandbox termination")
-	return ctrl.Result{RequeueAfter: graceDuration}, nil
+	return ctrl.Result{RequeueAfter: untilDeadline(now, graceDeadline.Time)}, nil
 }
 
 const (
@@ -398,37 +660,92 @@ const (
 )
 
 // classifyClaimTermination inspects SandboxClaim conditions to determine the
-// failure reason. SandboxExpired and ClaimExpired map to TimedOut; all others
-// map to Failed.
-func classifyClaimTermination(claim *sandboxextv1alpha1.SandboxClaim) (string, string) {
+// failure reason. SandboxExpired and ClaimExpired map to TimedOut, unless the
+// operator observed the termination (at observedAt) before the claim's
+// shutdown time; all others map to Failed.
+func classifyClaimTermination(claim *sandboxextv1alpha1.SandboxClaim, observedAt time.Time) (string, string) {
 	readyCond := meta.FindStatusCondition(claim.Status.Conditions, string(sandboxv1alpha1.SandboxConditionReady))
 	if readyCond == nil {
 		return toolkitv1alpha1.ReasonFailed, "SandboxClaim status unavailable"
 	}
 	if readyCond.Reason == reasonSandboxExpired ||
 		readyCond.Reason == reasonClaimExpired {
+		var shutdownTime *metav1.Time
+		if claim.Spec.Lifecycle != nil {
+			shutdownTime = claim.Spec.Lifecycle.ShutdownTime
+		}
+		if expiredEarly(shutdownTime, observedAt) {
+			return toolkitv1alpha1.ReasonFailed, fmt.Sprintf(
+				"Sandbox expired %s before its shutdown time; check for clock skew between nodes",
+				shutdownTime.Sub(observedAt).Round(time.Second))
+		}
 		return toolkitv1alpha1.ReasonTimedOut, "Sandbox expired"
 	}
 	return toolkitv1alpha1.ReasonFailed, fmt.Sprintf("Sandbox terminated: %s", readyCond.Message)
 }
 
-const defaultTimeout = 30 * time.Minute
+const pendingMessage = "Waiting for sandbox to start"
 
 const requeueInterval = 5 * time.Minute
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AgentTaskReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(),
+		&toolkitv1alpha1.AgentTask{}, dependsOnIndex, indexDependsOn); err != nil {
+		return fmt.Errorf("indexing %s: %w", dependsOnIndex, err)
+	}
+	if err := registerPhaseCollector(mgr.GetClient()); err != nil {
+		return fmt.Errorf("registering metrics: %w", err)
+	}
+	if r.RateLimit.TaskQPS > 0 {
+		r.taskLimiter = newTaskRateLimiter(r.RateLimit.TaskQPS, r.RateLimit.TaskBurst)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&toolkitv1alpha1.AgentTask{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		For(&toolkitv1alpha1.AgentTask{}, builder.WithPredicates(
+			predicate.Or[client.Object](predicate.GenerationChangedPredicate{}, becameTerminal, timeoutExtended))).
+		WithOptions(controller.Options{RateLimiter: r.RateLimit.queueRateLimiter()}).
 		Owns(&sandboxextv1alpha1.SandboxClaim{}).
+		// Status changes of a task do not bump its generation, so dependents
+		// are woken by a separate watch that sees every update.
+		Watches(&toolkitv1alpha1.AgentTask{}, handler.EnqueueRequestsFromMapFunc(r.dependentsOf)).
 		Complete(r)
 }
 
+// becameTerminal passes updates that finished a task without bumping its
+// generation, such as a cancellation through the API server's admin
+// endpoints, so its SandboxClaim is released right away.
+var becameTerminal = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldTask, okOld := e.ObjectOld.(*toolkitv1alpha1.AgentTask)
+		newTask, okNew := e.ObjectNew.(*toolkitv1alpha1.AgentTask)
+		return okOld && okNew && !oldTask.IsTerminal() && newTask.IsTerminal()
+	},
+}
+
 // hasCondition returns true if the named condition exists.
 func hasCondition(task *toolkitv1alpha1.AgentTask, condType string) bool {
 	return meta.FindStatusCondition(task.Status.Conditions, condType) != nil
 }
 
+// patchStatus writes the status fields of task that differ from base as a
+// JSON merge patch, so fields owned by other writers are left alone. A merge
+// patch replaces the conditions list as a whole; when it changed, the patch
+// is rejected with a conflict if the task was modified since base was read,
+// rather than dropping a condition someone else just set. status.phase is
+// recomputed from the result, so it can never disagree with the conditions.
+func (r *AgentTaskReconciler) patchStatus(ctx context.Context, task, base *toolkitv1alpha1.AgentTask) error {
+	task.Status.Phase = task.ComputePhase()
+	patch := client.MergeFrom(base)
+	if !equality.Semantic.DeepEqual(base.Status.Conditions, task.Status.Conditions) {
+		patch = client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})
+	}
+	return r.Status().Patch(ctx, task, patch)
+}
+
 // setCondition sets or updates a condition on the task.
 func setCondition(task *toolkitv1alpha1.AgentTask, condition metav1.Condition) {
 	meta.SetStatusCondition(&task.Status.Conditions, condition)
@@ -35,9 +35,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
 	sandboxv1alpha1 "sigs.k8s.io/agent-sandbox/api/v1alpha1"
 	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
 )
@@ -85,6 +87,10 @@ var _ = Describe("AgentTask Controller", func() {
 		nn := types.NamespacedName{Name: name, Namespace: namespace}
 		resource := &toolkitv1alpha1.AgentTask{}
 		if err := k8sClient.Get(ctx, nn, resource); err == nil {
+			// No reconciler runs during cleanup to release the finalizer
+			if controllerutil.RemoveFinalizer(resource, taskFinalizer) {
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			}
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 		}
 	}
@@ -405,6 +411,13 @@ var _ = Describe("AgentTask Controller", func() {
 			sandboxName := createSandboxForClaim(claimName)
 			setClaimReadyWithSandbox(claimName, sandboxName)
 
+			By("Recording the trace of the request that created the task")
+			const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+			var traced toolkitv1alpha1.AgentTask
+			Expect(k8sClient.Get(ctx, taskNN, &traced)).To(Succeed())
+			traced.Annotations = map[string]string{tracing.TraceParentAnnotation: traceParent}
+			Expect(k8sClient.Update(ctx, &traced)).To(Succeed())
+
 			By("Setting up runner mock to capture the assignment POST")
 			var receivedAssignment TaskAssignment
 			var receivedContentType string
@@ -412,7 +425,7 @@ var _ = Describe("AgentTask Controller", func() {
 				receivedContentType = r.Header.Get("Content-Type")
 				_ = json.NewDecoder(r.Body).Decode(&receivedAssignment)
 				w.WriteHeader(http.StatusOK)
-				_, _ = w.Write([]byte(`{"status":"accepted"}`))
+				_, _ = w.Write([]byte(`{"status":"accepted","protocolVersion":1}`))
 			})
 			defer server.Close()
 
@@ -428,6 +441,10 @@ var _ = Describe("AgentTask Controller", func() {
 			Expect(receivedAssignment.TaskID).To(Equal(task.Name))
 			Expect(receivedAssignment.APIURL).To(Equal("http://shepherd-api.shepherd.svc.cluster.local:8081"))
 			Expect(receivedContentType).To(Equal("application/json"))
+			Expect(receivedAssignment.TraceParent).To(HavePrefix("00-4bf92f3577b34da6a3ce929d0e0e4736-"),
+				"assignment should continue the task's trace")
+			Expect(receivedAssignment.ProtocolVersion).To(Equal(RunnerProtocolVersion))
+			Expect(task.Status.RunnerProtocolVersion).To(BeEquivalentTo(RunnerProtocolVersion))
 
 			By("Verifying Running condition")
 			cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
@@ -547,6 +564,35 @@ var _ = Describe("AgentTask Controller", func() {
 			Expect(cond.Reason).NotTo(Equal(toolkitv1alpha1.ReasonRunning))
 		})
 
+		It("should fail the task when the runner speaks another protocol version", func() {
+			createAgentTask(taskName, resourceNamespace)
+			reconcileToPending()
+			claimName := reconcileToClaimed()
+
+			sandboxName := createSandboxForClaim(claimName)
+			setClaimReadyWithSandbox(claimName, sandboxName)
+
+			By("Setting up runner mock that refuses the assignment")
+			server, _ := setupRunnerMock(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"unsupported protocol version 1","protocolVersion":2}`))
+			})
+			defer server.Close()
+
+			By("Reconciling — should fail instead of requeuing")
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: taskNN})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			var task toolkitv1alpha1.AgentTask
+			Expect(k8sClient.Get(ctx, taskNN, &task)).To(Succeed())
+			cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(toolkitv1alpha1.ReasonFailed))
+			Expect(cond.Message).To(ContainSubstring("runner speaks protocol version 2, operator speaks version 1"))
+		})
+
 		It("should requeue when SandboxClaim not yet ready", func() {
 			createAgentTask(taskName, resourceNamespace)
 			reconcileToPending()
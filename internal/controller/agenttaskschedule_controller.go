@@ -0,0 +1,362 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+const (
+	// scheduleLabel is set on every AgentTask created by a schedule to the
+	// schedule's name.
+	scheduleLabel = "shepherd.io/schedule"
+	// scheduledAtAnnotation records the run a task was created for.
+	scheduledAtAnnotation = "shepherd.io/scheduled-at"
+
+	// maxMissedRuns is how many missed runs are tolerated before the
+	// schedule reports a warning. Only the most recent missed run is started.
+	maxMissedRuns = 100
+
+	defaultSuccessfulTasksHistoryLimit = 3
+	defaultFailedTasksHistoryLimit     = 1
+)
+
+// AgentTaskScheduleReconciler reconciles an AgentTaskSchedule object
+type AgentTaskScheduleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+	Clock    func() time.Time // Injectable for testing; defaults to time.Now
+}
+
+// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=agenttaskschedules,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=agenttaskschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=agenttaskschedules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=agenttasks,verbs=create;delete
+
+func (r *AgentTaskScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	now := r.now()
+
+	// 1. Fetch the AgentTaskSchedule
+	var sched toolkitv1alpha1.AgentTaskSchedule
+	if err := r.Get(ctx, req.NamespacedName, &sched); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	original := sched.Status.DeepCopy()
+
+	// 2. Collect the tasks this schedule created and refresh status from them
+	active, succeeded, failed, err := r.listOwnedTasks(ctx, &sched)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	sched.Status.Active = taskNames(active)
+	for _, t := range succeeded {
+		if ts := t.Status.CompletionTime; ts != nil &&
+			(sched.Status.LastSuccessfulTime == nil || ts.After(sched.Status.LastSuccessfulTime.Time)) {
+			sched.Status.LastSuccessfulTime = ts.DeepCopy()
+		}
+	}
+
+	// 3. Prune finished tasks beyond the history limits
+	if err := r.pruneHistory(ctx, succeeded, historyLimit(sched.Spec.SuccessfulTasksHistoryLimit, defaultSuccessfulTasksHistoryLimit)); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.pruneHistory(ctx, failed, historyLimit(sched.Spec.FailedTasksHistoryLimit, defaultFailedTasksHistoryLimit)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// 4. Suspended → only keep status current
+	if sched.Spec.Suspend {
+		log.V(1).Info("schedule is suspended", "schedule", req.NamespacedName)
+		return ctrl.Result{}, r.updateStatus(ctx, &sched, original)
+	}
+
+	// 5. Parse the schedule. An invalid schedule is not retried until the
+	// spec changes.
+	cron, loc, err := parseSchedule(&sched)
+	if err != nil {
+		r.Recorder.Eventf(&sched, nil, "Warning", "InvalidSchedule", "Reconcile", "Invalid schedule: %v", err)
+		log.Info("invalid schedule", "schedule", req.NamespacedName, "error", err.Error())
+		return ctrl.Result{}, r.updateStatus(ctx, &sched, original)
+	}
+
+	// 6. Find the most recent run that is due
+	missed, count := r.mostRecentMissedRun(&sched, cron, loc, now)
+	if count > maxMissedRuns {
+		r.Recorder.Eventf(&sched, nil, "Warning", "TooManyMissedRuns", "Reconcile",
+			"Missed %d runs, starting only the most recent; set startingDeadlineSeconds to bound this", count)
+	}
+	result := requeueAtNextRun(cron, loc, now)
+	if missed.IsZero() {
+		return result, r.updateStatus(ctx, &sched, original)
+	}
+
+	if d := sched.Spec.StartingDeadlineSeconds; d != nil && now.Sub(missed) > time.Duration(*d)*time.Second {
+		log.Info("missed starting deadline, skipping run", "schedule", req.NamespacedName, "scheduledAt", missed)
+		r.Recorder.Eventf(&sched, nil, "Warning", "MissedSchedule", "Reconcile",
+			"Missed starting deadline for run at %s", missed.Format(time.RFC3339))
+		sched.Status.LastScheduleTime = &metav1.Time{Time: missed}
+		return result, r.updateStatus(ctx, &sched, original)
+	}
+
+	// 7. Apply the concurrency policy
+	switch sched.Spec.ConcurrencyPolicy {
+	case toolkitv1alpha1.ForbidConcurrent, "":
+		if len(active) > 0 {
+			// The run stays due; finishing the active task triggers a
+			// reconcile through the Owns watch.
+			log.V(1).Info("concurrency policy forbids a new run while tasks are active",
+				"schedule", req.NamespacedName, "active", sched.Status.Active)
+			return result, r.updateStatus(ctx, &sched, original)
+		}
+	case toolkitv1alpha1.ReplaceConcurrent:
+		for _, t := range active {
+			if err := r.Delete(ctx, t, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, fmt.Errorf("deleting active task %s: %w", t.Name, err)
+			}
+			r.Recorder.Eventf(&sched, t, "Normal", "TaskReplaced", "Reconcile", "Deleted active task %s", t.Name)
+		}
+		sched.Status.Active = nil
+	}
+
+	// 8. Create the task for the run
+	task, err := r.buildTask(&sched, missed)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, task); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("creating task: %w", err)
+		}
+		log.V(1).Info("task for run already exists", logging.TaskID, task.Name)
+	} else {
+		r.Recorder.Eventf(&sched, task, "Normal", "TaskCreated", "Reconcile", "Created task %s", task.Name)
+		log.Info("created scheduled task", "schedule", req.NamespacedName, logging.TaskID, task.Name)
+	}
+
+	if !slices.Contains(sched.Status.Active, task.Name) {
+		sched.Status.Active = append(sched.Status.Active, task.Name)
+		slices.Sort(sched.Status.Active)
+	}
+	sched.Status.LastScheduleTime = &metav1.Time{Time: missed}
+	return result, r.updateStatus(ctx, &sched, original)
+}
+
+func (r *AgentTaskScheduleReconciler) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock()
+	}
+	return time.Now()
+}
+
+// listOwnedTasks returns the tasks controlled by the schedule, split into
+// active, succeeded and failed (including timed out and cancelled).
+func (r *AgentTaskScheduleReconciler) listOwnedTasks(ctx context.Context, sched *toolkitv1alpha1.AgentTaskSchedule) (active, succeeded, failed []*toolkitv1alpha1.AgentTask, err error) {
+	var tasks toolkitv1alpha1.AgentTaskList
+	if err := r.List(ctx, &tasks,
+		client.InNamespace(sched.Namespace),
+		client.MatchingLabels{scheduleLabel: sched.Name},
+	); err != nil {
+		return nil, nil, nil, fmt.Errorf("listing scheduled tasks: %w", err)
+	}
+
+	for i := range tasks.Items {
+		t := &tasks.Items[i]
+		if !metav1.IsControlledBy(t, sched) {
+			continue
+		}
+		switch {
+		case !t.IsTerminal():
+			active = append(active, t)
+		case isSucceeded(t):
+			succeeded = append(succeeded, t)
+		default:
+			failed = append(failed, t)
+		}
+	}
+	return active, succeeded, failed, nil
+}
+
+// pruneHistory deletes the oldest finished tasks so that at most limit remain.
+func (r *AgentTaskScheduleReconciler) pruneHistory(ctx context.Context, tasks []*toolkitv1alpha1.AgentTask, limit int) error {
+	if len(tasks) <= limit {
+		return nil
+	}
+	slices.SortFunc(tasks, func(a, b *toolkitv1alpha1.AgentTask) int {
+		return finishedAt(a).Compare(finishedAt(b))
+	})
+	for _, t := range tasks[:len(tasks)-limit] {
+		if err := r.Delete(ctx, t, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("deleting old task %s: %w", t.Name, err)
+		}
+		logf.FromContext(ctx).V(1).Info("pruned scheduled task", logging.TaskID, t.Name)
+	}
+	return nil
+}
+
+// mostRecentMissedRun returns the latest scheduled time in (last run, now]
+// and how many runs fall in that window. The window starts at the previous
+// run, or at creation for a new schedule, and never earlier than the
+// starting deadline allows.
+func (r *AgentTaskScheduleReconciler) mostRecentMissedRun(sched *toolkitv1alpha1.AgentTaskSchedule, cron *cronSchedule, loc *time.Location, now time.Time) (time.Time, int) {
+	earliest := sched.CreationTimestamp.Time
+	if sched.Status.LastScheduleTime != nil {
+		earliest = sched.Status.LastScheduleTime.Time
+	}
+	if d := sched.Spec.StartingDeadlineSeconds; d != nil {
+		if cutoff := now.Add(-time.Duration(*d) * time.Second); cutoff.After(earliest) {
+			earliest = cutoff
+		}
+	}
+
+	var missed time.Time
+	count := 0
+	for t := cron.next(earliest.In(loc)); !t.IsZero() && !t.After(now); t = cron.next(t) {
+		missed = t
+		count++
+	}
+	return missed, count
+}
+
+// buildTask stamps out the AgentTask for the run at scheduledAt. The name is
+// derived from the scheduled time so a retried reconcile cannot create the
+// same run twice.
+func (r *AgentTaskScheduleReconciler) buildTask(sched *toolkitv1alpha1.AgentTaskSchedule, scheduledAt time.Time) (*toolkitv1alpha1.AgentTask, error) {
+	tmpl := sched.Spec.TaskTemplate.DeepCopy()
+
+	labels := make(map[string]string, len(tmpl.Labels)+1)
+	maps.Copy(labels, tmpl.Labels)
+	labels[scheduleLabel] = sched.Name
+
+	task := &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", sched.Name, scheduledAt.Unix()/60),
+			Namespace: sched.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				scheduledAtAnnotation: scheduledAt.UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: toolkitv1alpha1.AgentTaskSpec{
+			Repo:     tmpl.Repo,
+			Task:     tmpl.Task,
+			Callback: tmpl.Callback,
+			Runner:   tmpl.Runner,
+			Priority: tmpl.Priority,
+		},
+	}
+	if err := controllerutil.SetControllerReference(sched, task, r.Scheme); err != nil {
+		return nil, fmt.Errorf("setting owner reference: %w", err)
+	}
+	return task, nil
+}
+
+func (r *AgentTaskScheduleReconciler) updateStatus(ctx context.Context, sched *toolkitv1alpha1.AgentTaskSchedule, original *toolkitv1alpha1.AgentTaskScheduleStatus) error {
+	if equality.Semantic.DeepEqual(&sched.Status, original) {
+		return nil
+	}
+	// The schedule controller is the only writer of its status.
+	base := sched.DeepCopy()
+	base.Status = *original
+	if err := r.Status().Patch(ctx, sched, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("updating schedule status: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AgentTaskScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&toolkitv1alpha1.AgentTaskSchedule{}).
+		Owns(&toolkitv1alpha1.AgentTask{}).
+		Complete(r)
+}
+
+// parseSchedule parses the schedule's cron expression and time zone.
+func parseSchedule(sched *toolkitv1alpha1.AgentTaskSchedule) (*cronSchedule, *time.Location, error) {
+	cron, err := parseCron(sched.Spec.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc := time.UTC
+	if tz := sched.Spec.TimeZone; tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return nil, nil, fmt.Errorf("unknown time zone %q", tz)
+		}
+	}
+	return cron, loc, nil
+}
+
+func requeueAtNextRun(cron *cronSchedule, loc *time.Location, now time.Time) ctrl.Result {
+	next := cron.next(now.In(loc))
+	if next.IsZero() {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: next.Sub(now)}
+}
+
+func historyLimit(limit *int32, def int) int {
+	if limit == nil {
+		return def
+	}
+	return int(*limit)
+}
+
+func isSucceeded(task *toolkitv1alpha1.AgentTask) bool {
+	cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	return cond != nil && cond.Status == metav1.ConditionTrue
+}
+
+// finishedAt orders finished tasks, falling back to creation time for tasks
+// that never recorded a completion time.
+func finishedAt(task *toolkitv1alpha1.AgentTask) time.Time {
+	if task.Status.CompletionTime != nil {
+		return task.Status.CompletionTime.Time
+	}
+	return task.CreationTimestamp.Time
+}
+
+func taskNames(tasks []*toolkitv1alpha1.AgentTask) []string {
+	if len(tasks) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		names = append(names, t.Name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// scheduleNow is the fake clock used by the schedule tests, a Wednesday.
+var scheduleNow = time.Date(2026, 3, 4, 9, 0, 30, 0, time.UTC)
+
+func testSchedule(expr string) *toolkitv1alpha1.AgentTaskSchedule {
+	return &toolkitv1alpha1.AgentTaskSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "nightly",
+			Namespace:         "default",
+			UID:               "schedule-uid",
+			CreationTimestamp: metav1.NewTime(scheduleNow.Add(-time.Hour)),
+		},
+		Spec: toolkitv1alpha1.AgentTaskScheduleSpec{
+			Schedule:          expr,
+			ConcurrencyPolicy: toolkitv1alpha1.ForbidConcurrent,
+			TaskTemplate: toolkitv1alpha1.AgentTaskTemplate{
+				Labels:   map[string]string{"team": "platform"},
+				Repo:     toolkitv1alpha1.RepoSpec{URL: "https://github.com/org/repo"},
+				Task:     toolkitv1alpha1.TaskSpec{Description: "Bump dependencies"},
+				Callback: toolkitv1alpha1.CallbackSpec{URL: "http://adapter/callback"},
+				Runner:   toolkitv1alpha1.RunnerSpec{SandboxTemplateName: "default"},
+				Priority: 10,
+			},
+		},
+	}
+}
+
+// scheduledTask returns a task owned by sched in the given state.
+func scheduledTask(t *testing.T, s *runtime.Scheme, sched *toolkitv1alpha1.AgentTaskSchedule, name string, status metav1.ConditionStatus, reason string, finished time.Time) *toolkitv1alpha1.AgentTask {
+	t.Helper()
+	task := dependencyTask(name, status, reason)
+	task.Labels = map[string]string{scheduleLabel: sched.Name}
+	if !finished.IsZero() {
+		task.Status.CompletionTime = &metav1.Time{Time: finished}
+	}
+	require.NoError(t, controllerutil.SetControllerReference(sched, task, s))
+	return task
+}
+
+func newScheduleScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	s := runtime.NewScheme()
+	require.NoError(t, toolkitv1alpha1.AddToScheme(s))
+	return s
+}
+
+func newScheduleReconciler(s *runtime.Scheme, objs ...client.Object) *AgentTaskScheduleReconciler {
+	return &AgentTaskScheduleReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(objs...).
+			WithStatusSubresource(&toolkitv1alpha1.AgentTaskSchedule{}, &toolkitv1alpha1.AgentTask{}).
+			Build(),
+		Scheme:   s,
+		Recorder: events.NewFakeRecorder(10),
+		Clock:    func() time.Time { return scheduleNow },
+	}
+}
+
+func reconcileSchedule(t *testing.T, r *AgentTaskScheduleReconciler) (ctrl.Result, *toolkitv1alpha1.AgentTaskSchedule) {
+	t.Helper()
+	key := client.ObjectKey{Namespace: "default", Name: "nightly"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var sched toolkitv1alpha1.AgentTaskSchedule
+	require.NoError(t, r.Get(context.Background(), key, &sched))
+	return result, &sched
+}
+
+func listScheduledTasks(t *testing.T, r *AgentTaskScheduleReconciler) []string {
+	t.Helper()
+	var tasks toolkitv1alpha1.AgentTaskList
+	require.NoError(t, r.List(context.Background(), &tasks, client.InNamespace("default")))
+	names := make([]string, 0, len(tasks.Items))
+	for _, task := range tasks.Items {
+		names = append(names, task.Name)
+	}
+	return names
+}
+
+func TestScheduleReconcile_CreatesTaskWhenDue(t *testing.T) {
+	s := newScheduleScheme(t)
+	r := newScheduleReconciler(s, testSchedule("0 9 * * *"))
+
+	result, sched := reconcileSchedule(t, r)
+
+	runAt := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "nightly-29543580"}, &task))
+	assert.Equal(t, "nightly", task.Labels[scheduleLabel])
+	assert.Equal(t, "platform", task.Labels["team"])
+	assert.Equal(t, "2026-03-04T09:00:00Z", task.Annotations[scheduledAtAnnotation])
+	assert.True(t, metav1.IsControlledBy(&task, sched))
+	assert.Equal(t, "Bump dependencies", task.Spec.Task.Description)
+	assert.Equal(t, "https://github.com/org/repo", task.Spec.Repo.URL)
+	assert.Equal(t, int32(10), task.Spec.Priority)
+
+	assert.Equal(t, []string{"nightly-29543580"}, sched.Status.Active)
+	require.NotNil(t, sched.Status.LastScheduleTime)
+	assert.True(t, sched.Status.LastScheduleTime.Equal(&metav1.Time{Time: runAt}))
+	assert.Equal(t, 24*time.Hour-30*time.Second, result.RequeueAfter)
+
+	// A second reconcile for the same run is a no-op
+	_, _ = reconcileSchedule(t, r)
+	assert.Len(t, listScheduledTasks(t, r), 1)
+}
+
+func TestScheduleReconcile_NotDue(t *testing.T) {
+	s := newScheduleScheme(t)
+	r := newScheduleReconciler(s, testSchedule("0 12 * * *"))
+
+	result, sched := reconcileSchedule(t, r)
+
+	assert.Empty(t, listScheduledTasks(t, r))
+	assert.Nil(t, sched.Status.LastScheduleTime)
+	assert.Equal(t, 3*time.Hour-30*time.Second, result.RequeueAfter)
+}
+
+func TestScheduleReconcile_ConcurrencyPolicy(t *testing.T) {
+	t.Run("Forbid waits for the active task", func(t *testing.T) {
+		s := newScheduleScheme(t)
+		sched := testSchedule("0 9 * * *")
+		running := scheduledTask(t, s, sched, "nightly-old", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, time.Time{})
+		r := newScheduleReconciler(s, sched, running)
+
+		_, got := reconcileSchedule(t, r)
+
+		assert.Equal(t, []string{"nightly-old"}, listScheduledTasks(t, r))
+		assert.Equal(t, []string{"nightly-old"}, got.Status.Active)
+		assert.Nil(t, got.Status.LastScheduleTime, "run should stay due")
+	})
+
+	t.Run("Allow runs alongside the active task", func(t *testing.T) {
+		s := newScheduleScheme(t)
+		sched := testSchedule("0 9 * * *")
+		sched.Spec.ConcurrencyPolicy = toolkitv1alpha1.AllowConcurrent
+		running := scheduledTask(t, s, sched, "nightly-old", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, time.Time{})
+		r := newScheduleReconciler(s, sched, running)
+
+		_, got := reconcileSchedule(t, r)
+
+		assert.ElementsMatch(t, []string{"nightly-old", "nightly-29543580"}, listScheduledTasks(t, r))
+		assert.Equal(t, []string{"nightly-29543580", "nightly-old"}, got.Status.Active)
+	})
+
+	t.Run("Replace deletes the active task", func(t *testing.T) {
+		s := newScheduleScheme(t)
+		sched := testSchedule("0 9 * * *")
+		sched.Spec.ConcurrencyPolicy = toolkitv1alpha1.ReplaceConcurrent
+		running := scheduledTask(t, s, sched, "nightly-old", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, time.Time{})
+		r := newScheduleReconciler(s, sched, running)
+
+		_, got := reconcileSchedule(t, r)
+
+		assert.Equal(t, []string{"nightly-29543580"}, listScheduledTasks(t, r))
+		assert.Equal(t, []string{"nightly-29543580"}, got.Status.Active)
+	})
+}
+
+func TestScheduleReconcile_PrunesHistory(t *testing.T) {
+	s := newScheduleScheme(t)
+	sched := testSchedule("0 12 * * *")
+	sched.Spec.SuccessfulTasksHistoryLimit = ptr.To[int32](2)
+	sched.Spec.FailedTasksHistoryLimit = ptr.To[int32](1)
+
+	day := func(n int) time.Time { return scheduleNow.AddDate(0, 0, -n) }
+	unowned := dependencyTask("manual", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded)
+	r := newScheduleReconciler(s, sched, unowned,
+		scheduledTask(t, s, sched, "ok-1", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded, day(1)),
+		scheduledTask(t, s, sched, "ok-2", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded, day(2)),
+		scheduledTask(t, s, sched, "ok-3", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded, day(3)),
+		scheduledTask(t, s, sched, "failed-1", metav1.ConditionFalse, toolkitv1alpha1.ReasonFailed, day(1)),
+		scheduledTask(t, s, sched, "timedout-2", metav1.ConditionFalse, toolkitv1alpha1.ReasonTimedOut, day(2)),
+	)
+
+	_, got := reconcileSchedule(t, r)
+
+	assert.ElementsMatch(t, []string{"manual", "ok-1", "ok-2", "failed-1"}, listScheduledTasks(t, r))
+	require.NotNil(t, got.Status.LastSuccessfulTime)
+	assert.True(t, got.Status.LastSuccessfulTime.Equal(&metav1.Time{Time: day(1)}))
+	assert.Empty(t, got.Status.Active)
+}
+
+func TestScheduleReconcile_Suspended(t *testing.T) {
+	s := newScheduleScheme(t)
+	sched := testSchedule("0 9 * * *")
+	sched.Spec.Suspend = true
+	r := newScheduleReconciler(s, sched)
+
+	result, got := reconcileSchedule(t, r)
+
+	assert.Empty(t, listScheduledTasks(t, r))
+	assert.Nil(t, got.Status.LastScheduleTime)
+	assert.Zero(t, result.RequeueAfter)
+}
+
+func TestScheduleReconcile_InvalidSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		timeZone string
+	}{
+		{"bad expression", "every day", ""},
+		{"unknown time zone", "0 9 * * *", "Mars/Olympus_Mons"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScheduleScheme(t)
+			sched := testSchedule(tt.schedule)
+			sched.Spec.TimeZone = tt.timeZone
+			r := newScheduleReconciler(s, sched)
+
+			result, _ := reconcileSchedule(t, r)
+
+			assert.Empty(t, listScheduledTasks(t, r))
+			assert.Zero(t, result.RequeueAfter)
+			recorder := r.Recorder.(*events.FakeRecorder)
+			require.Len(t, recorder.Events, 1)
+			assert.Contains(t, <-recorder.Events, "InvalidSchedule")
+		})
+	}
+}
+
+func TestScheduleReconcile_TimeZone(t *testing.T) {
+	s := newScheduleScheme(t)
+	// 09:00 UTC is 10:00 in Stockholm in March
+	sched := testSchedule("0 10 * * *")
+	sched.Spec.TimeZone = "Europe/Stockholm"
+	r := newScheduleReconciler(s, sched)
+
+	_, got := reconcileSchedule(t, r)
+
+	assert.Equal(t, []string{"nightly-29543580"}, got.Status.Active)
+}
+
+func TestScheduleReconcile_StartingDeadline(t *testing.T) {
+	t.Run("run within deadline starts", func(t *testing.T) {
+		s := newScheduleScheme(t)
+		sched := testSchedule("0 9 * * *")
+		sched.Spec.StartingDeadlineSeconds = ptr.To[int64](60)
+		r := newScheduleReconciler(s, sched)
+
+		_, got := reconcileSchedule(t, r)
+
+		assert.Equal(t, []string{"nightly-29543580"}, got.Status.Active)
+	})
+
+	t.Run("run past deadline is skipped", func(t *testing.T) {
+		s := newScheduleScheme(t)
+		sched := testSchedule("0 9 * * *")
+		sched.Spec.StartingDeadlineSeconds = ptr.To[int64](10)
+		r := newScheduleReconciler(s, sched)
+
+		result, got := reconcileSchedule(t, r)
+
+		assert.Empty(t, listScheduledTasks(t, r))
+		assert.Nil(t, got.Status.LastScheduleTime)
+		assert.Equal(t, 24*time.Hour-30*time.Second, result.RequeueAfter)
+	})
+}
+
+func TestScheduleReconcile_MissedRuns(t *testing.T) {
+	s := newScheduleScheme(t)
+	sched := testSchedule("*/10 * * * *")
+	sched.Status.LastScheduleTime = &metav1.Time{Time: scheduleNow.Add(-2 * time.Hour)}
+	r := newScheduleReconciler(s, sched)
+
+	_, got := reconcileSchedule(t, r)
+
+	// Only the most recent of the missed runs is started
+	assert.Equal(t, []string{"nightly-29543580"}, listScheduledTasks(t, r))
+	assert.True(t, got.Status.LastScheduleTime.Equal(&metav1.Time{Time: time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)}))
+}
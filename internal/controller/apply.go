@@ -0,0 +1,110 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+// fieldManager is the server-side apply field manager for objects the
+// operator manages. Fields it sets are owned by it; fields added by other
+// managers are left alone.
+const fieldManager = "shepherd-operator"
+
+// apply server-side applies obj as the operator's field manager, taking
+// ownership of any field another manager changed.
+func (r *AgentTaskReconciler) apply(ctx context.Context, obj client.Object) error {
+	return applyObject(ctx, r.Client, r.Scheme, obj)
+}
+
+// applyObject server-side applies obj with c; see AgentTaskReconciler.apply.
+func applyObject(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return fmt.Errorf("getting kind: %w", err)
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("converting %s: %w", gvk.Kind, err)
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	// Only the desired state is applied; an empty status or creation
+	// timestamp would otherwise be claimed as owned fields.
+	delete(u.Object, "status")
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+
+	return c.Apply(ctx, client.ApplyConfigurationFromUnstructured(u),
+		client.FieldOwner(fieldManager), client.ForceOwnership)
+}
+
+// claimDrifted reports whether fields the operator sets on a SandboxClaim
+// were changed by someone else. Labels and annotations added by others are
+// ignored; shutdown times within clockSkewTolerance are treated as equal.
+func claimDrifted(existing, desired *sandboxextv1alpha1.SandboxClaim) bool {
+	for k, v := range desired.Labels {
+		if existing.Labels[k] != v {
+			return true
+		}
+	}
+	if existing.Spec.TemplateRef != desired.Spec.TemplateRef {
+		return true
+	}
+	have, want := existing.Spec.Lifecycle, desired.Spec.Lifecycle
+	if have == nil || want == nil {
+		return have != want
+	}
+	if have.ShutdownPolicy != want.ShutdownPolicy {
+		return true
+	}
+	if have.ShutdownTime == nil || want.ShutdownTime == nil {
+		return have.ShutdownTime != want.ShutdownTime
+	}
+	skew := have.ShutdownTime.Sub(want.ShutdownTime.Time).Abs()
+	return skew > clockSkewTolerance
+}
+
+// reconcileClaimDrift re-applies the desired SandboxClaim when fields the
+// operator owns were edited by hand, and reports whether it did. The
+// shutdown time counts from the claim's creation, so correcting drift never
+// extends how long a task may run.
+func (r *AgentTaskReconciler) reconcileClaimDrift(ctx context.Context, task *toolkitv1alpha1.AgentTask, claim *sandboxextv1alpha1.SandboxClaim) (bool, error) {
+	desired, err := buildSandboxClaim(task, sandboxConfig{
+		Scheme:   r.Scheme,
+		Now:      claim.CreationTimestamp.Time,
+		Template: r.sandboxTemplate(task),
+	})
+	if err != nil {
+		return false, fmt.Errorf("building sandbox claim: %w", err)
+	}
+	if !claimDrifted(claim, desired) {
+		return false, nil
+	}
+	if err := r.apply(ctx, desired); err != nil {
+		return false, fmt.Errorf("applying sandbox claim: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+func TestClaimDrifted(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	desired, err := buildSandboxClaim(dependentTask("task-drift"), sandboxConfig{Scheme: testScheme(), Now: created})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		mutate func(*sandboxextv1alpha1.SandboxClaim)
+		want   bool
+	}{
+		{"unchanged", func(*sandboxextv1alpha1.SandboxClaim) {}, false},
+		{"label added by someone else", func(c *sandboxextv1alpha1.SandboxClaim) { c.Labels["team"] = "platform" }, false},
+		{"operator label changed", func(c *sandboxextv1alpha1.SandboxClaim) { c.Labels["shepherd.io/task"] = "other" }, true},
+		{"template changed", func(c *sandboxextv1alpha1.SandboxClaim) { c.Spec.TemplateRef.Name = "bigger" }, true},
+		{"lifecycle removed", func(c *sandboxextv1alpha1.SandboxClaim) { c.Spec.Lifecycle = nil }, true},
+		{"shutdown policy changed", func(c *sandboxextv1alpha1.SandboxClaim) {
+			c.Spec.Lifecycle.ShutdownPolicy = sandboxextv1alpha1.ShutdownPolicyDelete
+		}, true},
+		{"shutdown time within tolerance", func(c *sandboxextv1alpha1.SandboxClaim) {
+			c.Spec.Lifecycle.ShutdownTime = &metav1.Time{Time: c.Spec.Lifecycle.ShutdownTime.Add(5 * time.Second)}
+		}, false},
+		{"shutdown time extended", func(c *sandboxextv1alpha1.SandboxClaim) {
+			c.Spec.Lifecycle.ShutdownTime = &metav1.Time{Time: c.Spec.Lifecycle.ShutdownTime.Add(time.Hour)}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := desired.DeepCopy()
+			tt.mutate(existing)
+			assert.Equal(t, tt.want, claimDrifted(existing, desired))
+		})
+	}
+}
+
+func TestReconcile_AppliesSandboxClaim(t *testing.T) {
+	task := dependentTask("task-apply")
+	r := newDependencyReconciler(t, task)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(task)}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	var claim sandboxextv1alpha1.SandboxClaim
+	require.NoError(t, r.Get(context.Background(), req.NamespacedName, &claim))
+	assert.Equal(t, "default", claim.Spec.TemplateRef.Name)
+	require.Len(t, claim.OwnerReferences, 1)
+	assert.Equal(t, task.Name, claim.OwnerReferences[0].Name)
+	assert.Equal(t, task.Name, claim.Labels["shepherd.io/task"])
+}
+
+func TestReconcile_CorrectsClaimDrift(t *testing.T) {
+	task := dependentTask("task-edited")
+	task.Status.SandboxClaimName = task.Name
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	claim, err := buildSandboxClaim(task, sandboxConfig{Scheme: testScheme(), Now: created})
+	require.NoError(t, err)
+	claim.CreationTimestamp = metav1.Time{Time: created}
+	// Someone bumped the sandbox size and pushed the shutdown out by a day.
+	claim.Spec.TemplateRef.Name = "bigger"
+	claim.Spec.Lifecycle.ShutdownTime = &metav1.Time{Time: created.Add(24 * time.Hour)}
+	claim.Labels["team"] = "platform"
+
+	r := newDependencyReconciler(t, task, claim)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(task)}
+
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, result.RequeueAfter)
+
+	var got sandboxextv1alpha1.SandboxClaim
+	require.NoError(t, r.Get(context.Background(), req.NamespacedName, &got))
+	assert.Equal(t, "default", got.Spec.TemplateRef.Name)
+	assert.True(t, got.Spec.Lifecycle.ShutdownTime.Time.Equal(created.Add(toolkitv1alpha1.DefaultRunnerTimeout)))
+	assert.Equal(t, "platform", got.Labels["team"], "labels added by others are kept")
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/runner"
+)
+
+func TestRunnerProtocolVersionMatchesRunner(t *testing.T) {
+	assert.Equal(t, runner.ProtocolVersion, RunnerProtocolVersion)
+}
+
+func TestAssignTask_ProtocolVersion(t *testing.T) {
+	tests := []struct {
+		name             string
+		code             int
+		body             string
+		wantVersion      int
+		wantErr          bool
+		wantIncompatible bool
+	}{
+		{"current runner", http.StatusOK, `{"status":"accepted","protocolVersion":1}`, 1, false, false},
+		{"unversioned runner", http.StatusOK, `{"status":"accepted"}`, 0, false, false},
+		{"unversioned runner already assigned", http.StatusConflict, "task already assigned\n", 0, false, false},
+		{"unversioned runner rejects", http.StatusBadRequest, "invalid request\n", 0, true, false},
+		{"newer runner refuses", http.StatusBadRequest, `{"error":"unsupported protocol version 1","protocolVersion":2}`, 2, true, true},
+		{"runner error", http.StatusInternalServerError, `{"error":"boom","protocolVersion":1}`, 1, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TaskAssignment
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+				w.WriteHeader(tt.code)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			r := &AgentTaskReconciler{HTTPClient: &http.Client{
+				Transport: &rewriteTransport{base: http.DefaultTransport, targetURL: srv.URL},
+			}}
+			version, err := r.assignTask(context.Background(), "sandbox.default.svc", TaskAssignment{
+				TaskID: "task-1", APIURL: "http://api:8081", ProtocolVersion: RunnerProtocolVersion,
+			})
+			assert.Equal(t, RunnerProtocolVersion, got.ProtocolVersion)
+			assert.Equal(t, tt.wantVersion, version)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			var incompatible *incompatibleRunnerError
+			assert.Equal(t, tt.wantIncompatible, errors.As(err, &incompatible))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2026, 3, 4, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)},
+		{"30 8-17/2 * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 6 * * mon", time.Date(2026, 3, 9, 6, 0, 0, 0, time.UTC)},
+		{"0 6 * * 7", time.Date(2026, 3, 8, 6, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * JUN *", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches
+		{"0 0 20 * fri", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		// Never matches
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.next(from))
+		})
+	}
+}
+
+func TestCronNext_ExactMinuteIsExcluded(t *testing.T) {
+	s, err := parseCron("0 9 * * *")
+	require.NoError(t, err)
+
+	at := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, at.AddDate(0, 0, 1), s.next(at))
+}
+
+func TestCronNext_TimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Stockholm")
+	require.NoError(t, err)
+	s, err := parseCron("0 9 * * *")
+	require.NoError(t, err)
+
+	// 2026-03-29 is the start of daylight saving time in Europe
+	from := time.Date(2026, 3, 28, 12, 0, 0, 0, loc)
+	next := s.next(from)
+	assert.Equal(t, time.Date(2026, 3, 29, 9, 0, 0, 0, loc), next)
+	assert.Equal(t, time.Date(2026, 3, 29, 7, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every 5m",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := parseCron(expr)
+			assert.Error(t, err)
+		})
+	}
+}
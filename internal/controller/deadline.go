@@ -0,0 +1,80 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Task deadlines (the sandbox shutdown time and the termination grace
+// deadline) are computed by the operator only, from its own clock. They are
+// still read back from status written by a previous leader and enforced by
+// the sandbox controller on other nodes, so every comparison allows for
+// clockSkewTolerance of drift between those clocks.
+const clockSkewTolerance = 10 * time.Second
+
+// graceDuration is how long a task stays Running after its sandbox
+// terminated, giving the API time to process the runner's callback.
+const graceDuration = 30 * time.Second
+
+// now returns the operator's current time, the single authority for
+// deadlines.
+func (r *AgentTaskReconciler) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock()
+	}
+	return time.Now()
+}
+
+// deadlineAfter returns the deadline d from now.
+func deadlineAfter(now time.Time, d time.Duration) metav1.Time {
+	return metav1.NewTime(now.Add(d))
+}
+
+// deadlinePassed reports whether deadline is more than clockSkewTolerance
+// in the past, so a deadline written by a clock that runs slightly behind
+// ours does not expire early.
+func deadlinePassed(now, deadline time.Time) bool {
+	return now.After(deadline.Add(clockSkewTolerance))
+}
+
+// untilDeadline returns how long to wait before deadlinePassed can report
+// true. It is never negative.
+func untilDeadline(now, deadline time.Time) time.Duration {
+	return max(deadline.Add(clockSkewTolerance).Sub(now), 0)
+}
+
+// deadlineImplausible reports whether deadline lies further in the future
+// than a deadline set d ago could, meaning it was written by a clock that
+// runs ahead of ours. Waiting for it would hold the task for the size of
+// the skew.
+func deadlineImplausible(now, deadline time.Time, d time.Duration) bool {
+	return deadline.After(now.Add(d + clockSkewTolerance))
+}
+
+// expiredEarly reports whether a sandbox whose termination the operator
+// first observed at observedAt stopped before its shutdown time by the
+// operator's clock, which happens when the sandbox controller's clock runs
+// ahead.
+func expiredEarly(shutdownTime *metav1.Time, observedAt time.Time) bool {
+	if shutdownTime == nil || observedAt.IsZero() {
+		return false
+	}
+	return observedAt.Add(clockSkewTolerance).Before(shutdownTime.Time)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+var operatorNow = time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+func TestDeadlinePassed(t *testing.T) {
+	tests := []struct {
+		name     string
+		deadline time.Time
+		want     bool
+	}{
+		{"in the future", operatorNow.Add(time.Second), false},
+		{"just passed", operatorNow.Add(-time.Second), false},
+		{"at the tolerance", operatorNow.Add(-clockSkewTolerance), false},
+		{"beyond the tolerance", operatorNow.Add(-clockSkewTolerance - time.Second), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, deadlinePassed(operatorNow, tt.deadline))
+		})
+	}
+}
+
+func TestUntilDeadline(t *testing.T) {
+	assert.Equal(t, 15*time.Second, untilDeadline(operatorNow, operatorNow.Add(5*time.Second)))
+	assert.Equal(t, time.Duration(0), untilDeadline(operatorNow, operatorNow.Add(-time.Minute)))
+}
+
+func TestDeadlineImplausible(t *testing.T) {
+	assert.False(t, deadlineImplausible(operatorNow, operatorNow.Add(graceDuration), graceDuration))
+	assert.False(t, deadlineImplausible(operatorNow, operatorNow.Add(graceDuration+clockSkewTolerance), graceDuration))
+	assert.True(t, deadlineImplausible(operatorNow, operatorNow.Add(5*time.Minute), graceDuration))
+}
+
+// terminatingTask returns a Running task whose grace period ends at
+// graceDeadline, and its claim reporting an expired sandbox with the given
+// shutdown time.
+func terminatingTask(graceDeadline, shutdownTime time.Time) (*toolkitv1alpha1.AgentTask, client.Object) {
+	task := dependencyTask("task-skew", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning)
+	task.Status.SandboxClaimName = task.Name
+	task.Status.GraceDeadline = &metav1.Time{Time: graceDeadline}
+
+	claim := expiringClaim(shutdownTime)
+	claim.Name, claim.Namespace = task.Name, task.Namespace
+	return task, claim
+}
+
+func TestHandleSandboxTermination_ClockSkew(t *testing.T) {
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "task-skew"}}
+
+	t.Run("grace deadline passed within tolerance keeps the task running", func(t *testing.T) {
+		task, claim := terminatingTask(operatorNow.Add(-5*time.Second), operatorNow.Add(-40*time.Second))
+		r := newDependencyReconciler(t, task, claim)
+		r.Clock = func() time.Time { return operatorNow }
+
+		result, err := r.handleSandboxTermination(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, result.RequeueAfter)
+
+		var got toolkitv1alpha1.AgentTask
+		require.NoError(t, r.Get(context.Background(), req.NamespacedName, &got))
+		assert.False(t, got.IsTerminal())
+	})
+
+	t.Run("grace deadline from a clock running ahead is reset", func(t *testing.T) {
+		task, claim := terminatingTask(operatorNow.Add(10*time.Minute), operatorNow.Add(-time.Minute))
+		r := newDependencyReconciler(t, task, claim)
+		r.Clock = func() time.Time { return operatorNow }
+
+		result, err := r.handleSandboxTermination(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, graceDuration+clockSkewTolerance, result.RequeueAfter)
+
+		var got toolkitv1alpha1.AgentTask
+		require.NoError(t, r.Get(context.Background(), req.NamespacedName, &got))
+		require.NotNil(t, got.Status.GraceDeadline)
+		assert.True(t, got.Status.GraceDeadline.Time.Equal(operatorNow.Add(graceDuration)))
+	})
+
+	t.Run("sandbox expiring before its shutdown time is not a timeout", func(t *testing.T) {
+		// Observed at 12:29:00, shutdown time 12:35:00: the sandbox
+		// controller's clock runs six minutes ahead.
+		task, claim := terminatingTask(operatorNow.Add(-time.Minute+graceDuration), operatorNow.Add(5*time.Minute))
+		r := newDependencyReconciler(t, task, claim)
+		r.Clock = func() time.Time { return operatorNow }
+
+		_, err := r.handleSandboxTermination(context.Background(), req)
+		require.NoError(t, err)
+
+		var got toolkitv1alpha1.AgentTask
+		require.NoError(t, r.Get(context.Background(), req.NamespacedName, &got))
+		cond := meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+		require.NotNil(t, cond)
+		assert.Equal(t, toolkitv1alpha1.ReasonFailed, cond.Reason)
+		assert.Contains(t, cond.Message, "clock skew")
+		assert.True(t, got.Status.CompletionTime.Time.Equal(operatorNow))
+	})
+
+	t.Run("sandbox expiring after its shutdown time is a timeout", func(t *testing.T) {
+		task, claim := terminatingTask(operatorNow.Add(-time.Minute+graceDuration), operatorNow.Add(-70*time.Second))
+		r := newDependencyReconciler(t, task, claim)
+		r.Clock = func() time.Time { return operatorNow }
+
+		_, err := r.handleSandboxTermination(context.Background(), req)
+		require.NoError(t, err)
+
+		var got toolkitv1alpha1.AgentTask
+		require.NoError(t, r.Get(context.Background(), req.NamespacedName, &got))
+		cond := meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+		require.NotNil(t, cond)
+		assert.Equal(t, toolkitv1alpha1.ReasonTimedOut, cond.Reason)
+	})
+}
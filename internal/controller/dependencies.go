@@ -0,0 +1,209 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// dependsOnIndex indexes AgentTasks by the names in spec.dependsOn so a
+// finished task can find the tasks waiting on it.
+const dependsOnIndex = "spec.dependsOn"
+
+// missingDependencyTimeout is how long after its creation a task waits for
+// a dependency that does not exist. Clients creating a group of tasks create
+// them well within it; after it, the dependency is taken to be deleted or
+// misspelled.
+const missingDependencyTimeout = 10 * time.Minute
+
+// dependencyCheck is the outcome of checking a task's spec.dependsOn.
+type dependencyCheck struct {
+	Satisfied bool
+	Failed    bool   // A dependency reached a terminal state other than Succeeded
+	Message   string // Why the task is waiting or failed
+	// RequeueAfter, when set, is when a waiting task must be checked again
+	// even if no dependency changes.
+	RequeueAfter time.Duration
+}
+
+// checkDependencies reports whether every task in spec.dependsOn has
+// succeeded. Dependencies are checked in order and the first one that is not
+// satisfied determines the result. A missing dependency is waited for up to
+// missingDependencyTimeout, since a client may create a group of tasks in any
+// order. Tasks that depend on each other fail, as they would wait forever.
+func (r *AgentTaskReconciler) checkDependencies(ctx context.Context, task *toolkitv1alpha1.AgentTask) (dependencyCheck, error) {
+	for _, name := range task.Spec.DependsOn {
+		if name == task.Name {
+			return dependencyCheck{Failed: true, Message: "Task depends on itself"}, nil
+		}
+
+		var dep toolkitv1alpha1.AgentTask
+		if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: name}, &dep); err != nil {
+			if !errors.IsNotFound(err) {
+				return dependencyCheck{}, fmt.Errorf("getting dependency %s: %w", name, err)
+			}
+			waited := r.now().Sub(task.CreationTimestamp.Time)
+			if waited >= missingDependencyTimeout {
+				return dependencyCheck{
+					Failed:  true,
+					Message: fmt.Sprintf("Dependency %s does not exist after %s", name, missingDependencyTimeout),
+				}, nil
+			}
+			return dependencyCheck{
+				Message:      fmt.Sprintf("Waiting for dependency %s to be created", name),
+				RequeueAfter: missingDependencyTimeout - waited,
+			}, nil
+		}
+
+		cond := meta.FindStatusCondition(dep.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+		switch {
+		case cond != nil && cond.Status == metav1.ConditionTrue:
+			continue
+		case dep.IsTerminal():
+			return dependencyCheck{
+				Failed:  true,
+				Message: fmt.Sprintf("Dependency %s did not succeed (%s)", name, cond.Reason),
+			}, nil
+		}
+
+		cycle, err := r.dependencyCycle(ctx, task, &dep)
+		if err != nil {
+			return dependencyCheck{}, err
+		}
+		if cycle != nil {
+			return dependencyCheck{
+				Failed:  true,
+				Message: "Dependency cycle: " + strings.Join(cycle, " -> "),
+			}, nil
+		}
+		return dependencyCheck{Message: fmt.Sprintf("Waiting for dependency %s to succeed", name)}, nil
+	}
+	return dependencyCheck{Satisfied: true}, nil
+}
+
+// dependencyCycle returns the chain of unfinished tasks through which dep,
+// a dependency of task, itself depends on task, starting and ending with
+// task, or nil if there is none.
+func (r *AgentTaskReconciler) dependencyCycle(
+	ctx context.Context, task, dep *toolkitv1alpha1.AgentTask,
+) ([]string, error) {
+	visited := map[string]bool{dep.Name: true}
+	var walk func(t *toolkitv1alpha1.AgentTask, path []string) ([]string, error)
+	walk = func(t *toolkitv1alpha1.AgentTask, path []string) ([]string, error) {
+		for _, name := range t.Spec.DependsOn {
+			if name == task.Name {
+				return append(slices.Clone(path), name), nil
+			}
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+
+			var next toolkitv1alpha1.AgentTask
+			if err := r.Get(ctx, client.ObjectKey{Namespace: task.Namespace, Name: name}, &next); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return nil, fmt.Errorf("getting dependency %s: %w", name, err)
+			}
+			if next.IsTerminal() {
+				continue
+			}
+			cycle, err := walk(&next, append(slices.Clone(path), name))
+			if cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+	return walk(dep, []string{task.Name, dep.Name})
+}
+
+// markWaitingForDependency records that the task is blocked on spec.dependsOn.
+// The task is re-reconciled by the dependency watch when a dependency changes,
+// so the requeue is only a fallback, unless deps needs an earlier one.
+func (r *AgentTaskReconciler) markWaitingForDependency(ctx context.Context, task *toolkitv1alpha1.AgentTask, deps dependencyCheck) (ctrl.Result, error) {
+	requeue := requeueInterval
+	if deps.RequeueAfter > 0 {
+		requeue = min(requeue, deps.RequeueAfter)
+	}
+	message := deps.Message
+	cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	if cond != nil && cond.Reason == toolkitv1alpha1.ReasonWaitingForDependency && cond.Message == message {
+		return ctrl.Result{RequeueAfter: requeue}, nil
+	}
+
+	base := task.DeepCopy()
+	setCondition(task, metav1.Condition{
+		Type:               toolkitv1alpha1.ConditionSucceeded,
+		Status:             metav1.ConditionUnknown,
+		Reason:             toolkitv1alpha1.ReasonWaitingForDependency,
+		Message:            message,
+		ObservedGeneration: task.Generation,
+	})
+	if err := r.patchStatus(ctx, task, base); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating dependency status: %w", err)
+	}
+	logf.FromContext(ctx).V(1).Info("task waiting for dependency", "message", message)
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+// dependentsOf maps an AgentTask to the reconcile requests of the tasks that
+// list it in spec.dependsOn.
+func (r *AgentTaskReconciler) dependentsOf(ctx context.Context, obj client.Object) []reconcile.Request {
+	var dependents toolkitv1alpha1.AgentTaskList
+	if err := r.List(ctx, &dependents,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{dependsOnIndex: obj.GetName()},
+	); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list dependent tasks", logging.TaskID, obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(dependents.Items))
+	for _, t := range dependents.Items {
+		if t.IsTerminal() || t.Status.SandboxClaimName != "" {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&t)})
+	}
+	return requests
+}
+
+// indexDependsOn is the field indexer for dependsOnIndex.
+func indexDependsOn(obj client.Object) []string {
+	task, ok := obj.(*toolkitv1alpha1.AgentTask)
+	if !ok {
+		return nil
+	}
+	return task.Spec.DependsOn
+}
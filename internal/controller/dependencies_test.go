@@ -0,0 +1,233 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+func dependencyTask(name string, status metav1.ConditionStatus, reason string) *toolkitv1alpha1.AgentTask {
+	t := taskWithCondition(status, reason)
+	t.Name, t.Namespace = name, "default"
+	return t
+}
+
+func dependentTask(name string, dependsOn ...string) *toolkitv1alpha1.AgentTask {
+	t := dependencyTask(name, metav1.ConditionUnknown, toolkitv1alpha1.ReasonPending)
+	t.Spec.DependsOn = dependsOn
+	t.Spec.Runner.SandboxTemplateName = "default"
+	return t
+}
+
+func newDependencyReconciler(t *testing.T, objs ...client.Object) *AgentTaskReconciler {
+	t.Helper()
+	s := runtime.NewScheme()
+	require.NoError(t, toolkitv1alpha1.AddToScheme(s))
+	require.NoError(t, sandboxextv1alpha1.AddToScheme(s))
+	return &AgentTaskReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(objs...).
+			WithStatusSubresource(&toolkitv1alpha1.AgentTask{}).
+			WithIndex(&toolkitv1alpha1.AgentTask{}, dependsOnIndex, indexDependsOn).
+			Build(),
+		Scheme:   s,
+		Recorder: events.NewFakeRecorder(10),
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	createdAgo := func(task *toolkitv1alpha1.AgentTask, ago time.Duration) *toolkitv1alpha1.AgentTask {
+		task.CreationTimestamp = metav1.NewTime(now.Add(-ago))
+		return task
+	}
+	succeeded := dependencyTask("dep-ok", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded)
+	running := dependencyTask("dep-running", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning)
+	timedOut := dependencyTask("dep-timeout", metav1.ConditionFalse, toolkitv1alpha1.ReasonTimedOut)
+	// cycle-a and cycle-b wait for each other; chain-b waits for task
+	// through chain-c, unless chain-c is finished.
+	cycleB := dependentTask("cycle-b", "cycle-a")
+	chainB := dependentTask("chain-b", "dep-ok", "chain-c")
+	chainC := dependentTask("chain-c", "task")
+	finishedB := dependentTask("finished-b", "finished-c")
+	finishedC := dependencyTask("finished-c", metav1.ConditionFalse, toolkitv1alpha1.ReasonFailed)
+	finishedC.Spec.DependsOn = []string{"task"}
+
+	tests := []struct {
+		name string
+		task *toolkitv1alpha1.AgentTask
+		want dependencyCheck
+	}{
+		{
+			name: "no dependencies",
+			task: dependentTask("task"),
+			want: dependencyCheck{Satisfied: true},
+		},
+		{
+			name: "all succeeded",
+			task: dependentTask("task", "dep-ok"),
+			want: dependencyCheck{Satisfied: true},
+		},
+		{
+			name: "dependency still running",
+			task: dependentTask("task", "dep-ok", "dep-running"),
+			want: dependencyCheck{Message: "Waiting for dependency dep-running to succeed"},
+		},
+		{
+			name: "dependency not created yet",
+			task: createdAgo(dependentTask("task", "dep-missing"), time.Minute),
+			want: dependencyCheck{
+				Message:      "Waiting for dependency dep-missing to be created",
+				RequeueAfter: missingDependencyTimeout - time.Minute,
+			},
+		},
+		{
+			name: "dependency missing for too long",
+			task: createdAgo(dependentTask("task", "dep-missing"), missingDependencyTimeout),
+			want: dependencyCheck{Failed: true, Message: "Dependency dep-missing does not exist after 10m0s"},
+		},
+		{
+			name: "dependencies wait for each other",
+			task: dependentTask("cycle-a", "cycle-b"),
+			want: dependencyCheck{Failed: true, Message: "Dependency cycle: cycle-a -> cycle-b -> cycle-a"},
+		},
+		{
+			name: "dependency waits for the task through another task",
+			task: dependentTask("task", "chain-b"),
+			want: dependencyCheck{Failed: true, Message: "Dependency cycle: task -> chain-b -> chain-c -> task"},
+		},
+		{
+			name: "cycle through a finished task",
+			task: dependentTask("task", "finished-b"),
+			want: dependencyCheck{Message: "Waiting for dependency finished-b to succeed"},
+		},
+		{
+			name: "dependency timed out",
+			task: dependentTask("task", "dep-timeout"),
+			want: dependencyCheck{Failed: true, Message: "Dependency dep-timeout did not succeed (TimedOut)"},
+		},
+		{
+			name: "depends on itself",
+			task: dependentTask("task", "task"),
+			want: dependencyCheck{Failed: true, Message: "Task depends on itself"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newDependencyReconciler(t, succeeded, running, timedOut, cycleB, chainB, chainC, finishedB, finishedC)
+			r.Clock = func() time.Time { return now }
+
+			got, err := r.checkDependencies(context.Background(), tt.task)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReconcile_Dependencies(t *testing.T) {
+	reconcileTask := func(t *testing.T, r *AgentTaskReconciler, name string) *toolkitv1alpha1.AgentTask {
+		t.Helper()
+		key := client.ObjectKey{Namespace: "default", Name: name}
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+
+		var task toolkitv1alpha1.AgentTask
+		require.NoError(t, r.Get(context.Background(), key, &task))
+		return &task
+	}
+
+	t.Run("waits without creating a claim", func(t *testing.T) {
+		r := newDependencyReconciler(t,
+			dependencyTask("dep", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning),
+			dependentTask("task", "dep"))
+
+		task := reconcileTask(t, r, "task")
+
+		cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionUnknown, cond.Status)
+		assert.Equal(t, toolkitv1alpha1.ReasonWaitingForDependency, cond.Reason)
+		assert.Empty(t, task.Status.SandboxClaimName)
+
+		var claims sandboxextv1alpha1.SandboxClaimList
+		require.NoError(t, r.List(context.Background(), &claims))
+		assert.Empty(t, claims.Items)
+	})
+
+	t.Run("fails when dependency fails", func(t *testing.T) {
+		r := newDependencyReconciler(t,
+			dependencyTask("dep", metav1.ConditionFalse, toolkitv1alpha1.ReasonFailed),
+			dependentTask("task", "dep"))
+
+		task := reconcileTask(t, r, "task")
+
+		assert.True(t, task.IsTerminal())
+		assert.Equal(t, "Dependency dep did not succeed (Failed)", task.Status.Result.Error)
+	})
+
+	t.Run("creates claim once dependency succeeded", func(t *testing.T) {
+		blocked := dependentTask("task", "dep")
+		blocked.Status.Conditions[0].Reason = toolkitv1alpha1.ReasonWaitingForDependency
+		r := newDependencyReconciler(t,
+			dependencyTask("dep", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded),
+			blocked)
+
+		task := reconcileTask(t, r, "task")
+
+		assert.Equal(t, "task", task.Status.SandboxClaimName)
+		cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+		require.NotNil(t, cond)
+		assert.Equal(t, toolkitv1alpha1.ReasonPending, cond.Reason)
+	})
+}
+
+func TestDependentsOf(t *testing.T) {
+	started := dependentTask("task-started", "dep")
+	started.Status.SandboxClaimName = "task-started"
+
+	r := newDependencyReconciler(t,
+		dependencyTask("dep", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded),
+		dependentTask("task-a", "dep"),
+		dependentTask("task-b", "other", "dep"),
+		dependentTask("task-c", "other"),
+		started)
+
+	requests := r.dependentsOf(context.Background(), dependencyTask("dep", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded))
+
+	var names []string
+	for _, req := range requests {
+		names = append(names, req.Name)
+	}
+	assert.ElementsMatch(t, []string{"task-a", "task-b"}, names)
+}
@@ -18,6 +18,7 @@ package controller
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,6 +32,7 @@ func TestClassifyClaimTermination(t *testing.T) {
 	tests := []struct {
 		name           string
 		claim          *sandboxextv1alpha1.SandboxClaim
+		observedAt     time.Time
 		expectedReason string
 		expectedMsg    string
 	}{
@@ -60,6 +62,33 @@ func TestClassifyClaimTermination(t *testing.T) {
 			expectedReason: toolkitv1alpha1.ReasonTimedOut,
 			expectedMsg:    "Sandbox expired",
 		},
+		{
+			name: "expiry observed after shutdown time returns TimedOut",
+			claim: expiringClaim(
+				time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+			),
+			observedAt:     time.Date(2026, 3, 1, 12, 30, 2, 0, time.UTC),
+			expectedReason: toolkitv1alpha1.ReasonTimedOut,
+			expectedMsg:    "Sandbox expired",
+		},
+		{
+			name: "expiry observed within skew tolerance of shutdown time returns TimedOut",
+			claim: expiringClaim(
+				time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+			),
+			observedAt:     time.Date(2026, 3, 1, 12, 29, 55, 0, time.UTC),
+			expectedReason: toolkitv1alpha1.ReasonTimedOut,
+			expectedMsg:    "Sandbox expired",
+		},
+		{
+			name: "expiry observed well before shutdown time returns Failed",
+			claim: expiringClaim(
+				time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+			),
+			observedAt:     time.Date(2026, 3, 1, 12, 28, 0, 0, time.UTC),
+			expectedReason: toolkitv1alpha1.ReasonFailed,
+			expectedMsg:    "Sandbox expired 2m0s before its shutdown time; check for clock skew between nodes",
+		},
 		{
 			name: "other reason returns Failed with message",
 			claim: claimWithReadyCondition(
@@ -74,7 +103,7 @@ func TestClassifyClaimTermination(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			reason, msg := classifyClaimTermination(tt.claim)
+			reason, msg := classifyClaimTermination(tt.claim, tt.observedAt)
 			assert.Equal(t, tt.expectedReason, reason)
 			assert.Equal(t, tt.expectedMsg, msg)
 		})
@@ -96,3 +125,9 @@ func claimWithReadyCondition(status metav1.ConditionStatus, reason, message stri
 		},
 	}
 }
+
+func expiringClaim(shutdownTime time.Time) *sandboxextv1alpha1.SandboxClaim {
+	claim := claimWithReadyCondition(metav1.ConditionFalse, reasonSandboxExpired, "sandbox lifetime exceeded")
+	claim.Spec.Lifecycle = &sandboxextv1alpha1.Lifecycle{ShutdownTime: &metav1.Time{Time: shutdownTime}}
+	return claim
+}
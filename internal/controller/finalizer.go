@@ -0,0 +1,105 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// taskFinalizer keeps a deleted AgentTask around until its sandbox is
+// released and the adapter has been told how the task ended.
+const taskFinalizer = "shepherd.io/cleanup"
+
+// deletedMessage is the failure message of a task deleted before it finished.
+const deletedMessage = "Task was deleted before it finished"
+
+// deletionNotifyTimeout bounds how long a deleted task waits for the API
+// server to send its final callback, so an unavailable API server cannot
+// block deletion forever.
+const deletionNotifyTimeout = 2 * time.Minute
+
+// addFinalizer adds taskFinalizer to a task that does not have it yet.
+func (r *AgentTaskReconciler) addFinalizer(ctx context.Context, task *toolkitv1alpha1.AgentTask) error {
+	if controllerutil.ContainsFinalizer(task, taskFinalizer) {
+		return nil
+	}
+	base := task.DeepCopy()
+	controllerutil.AddFinalizer(task, taskFinalizer)
+	if err := r.Patch(ctx, task, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("adding finalizer: %w", err)
+	}
+	return nil
+}
+
+// finalize runs when a task with taskFinalizer is deleted. It deletes the
+// SandboxClaim, marks an unfinished task Cancelled so the API server's
+// status watcher sends a "cancelled" callback, and removes the finalizer once
+// that callback was attempted or deletionNotifyTimeout has passed.
+func (r *AgentTaskReconciler) finalize(ctx context.Context, task *toolkitv1alpha1.AgentTask) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(task, taskFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.cleanupSandboxClaim(ctx, task); err != nil {
+		return ctrl.Result{}, fmt.Errorf("deleting sandbox claim of deleted task: %w", err)
+	}
+
+	if !task.IsTerminal() {
+		if _, err := r.markFailed(ctx, task, toolkitv1alpha1.ReasonCancelled, deletedMessage); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("cancelled deleted task")
+		return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+	}
+
+	// Status changes do not trigger a reconcile, so poll for the callback.
+	if !callbackAttempted(task) {
+		waited := r.now().Sub(task.DeletionTimestamp.Time)
+		if waited < deletionNotifyTimeout {
+			log.V(1).Info("waiting for final callback before removing finalizer")
+			return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+		}
+		log.Info("final callback not sent in time, removing finalizer anyway", "waited", waited)
+	}
+
+	base := task.DeepCopy()
+	controllerutil.RemoveFinalizer(task, taskFinalizer)
+	if err := r.Patch(ctx, task, client.MergeFrom(base)); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log.Info("removed finalizer from deleted task")
+	return ctrl.Result{}, nil
+}
+
+// callbackAttempted reports whether the API server finished sending the
+// task's terminal callback, successfully or not.
+func callbackAttempted(task *toolkitv1alpha1.AgentTask) bool {
+	cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionNotified)
+	return cond != nil && (cond.Reason == toolkitv1alpha1.ReasonCallbackSent ||
+		cond.Reason == toolkitv1alpha1.ReasonCallbackFailed)
+}
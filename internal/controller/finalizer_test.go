@@ -0,0 +1,108 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+// deletedTask returns a task with taskFinalizer that was deleted at deletedAt.
+func deletedTask(name string, status metav1.ConditionStatus, reason string, deletedAt time.Time) *toolkitv1alpha1.AgentTask {
+	task := dependencyTask(name, status, reason)
+	task.Spec.Runner.SandboxTemplateName = "default"
+	task.Finalizers = []string{taskFinalizer}
+	task.DeletionTimestamp = &metav1.Time{Time: deletedAt}
+	return task
+}
+
+func TestReconcile_AddsFinalizer(t *testing.T) {
+	task := dependentTask("task-new")
+	r := newDependencyReconciler(t, task)
+
+	_, got := reconcileTask(t, r, task)
+	assert.Contains(t, got.Finalizers, taskFinalizer)
+}
+
+func TestReconcile_DeletedRunningTask(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := deletedTask("task-deleted", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, now)
+	task.Status.SandboxClaimName = task.Name
+	claim, err := buildSandboxClaim(task, sandboxConfig{Scheme: testScheme(), Now: now})
+	require.NoError(t, err)
+	r := newDependencyReconciler(t, task, claim)
+	r.Clock = func() time.Time { return now }
+
+	result, got := reconcileTask(t, r, task)
+	assert.Equal(t, 2*time.Second, result.RequeueAfter)
+
+	err = r.Get(context.Background(), client.ObjectKeyFromObject(claim), &sandboxextv1alpha1.SandboxClaim{})
+	assert.True(t, apierrors.IsNotFound(err), "claim is deleted first")
+
+	cond := meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, toolkitv1alpha1.ReasonCancelled, cond.Reason)
+	assert.Contains(t, got.Finalizers, taskFinalizer, "finalizer is kept until the callback was sent")
+
+	// Not yet notified: the task keeps waiting
+	result, got = reconcileTask(t, r, got)
+	assert.Equal(t, 2*time.Second, result.RequeueAfter)
+	assert.Contains(t, got.Finalizers, taskFinalizer)
+}
+
+func TestReconcile_DeletedTaskReleasedAfterCallback(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := deletedTask("task-notified", metav1.ConditionFalse, toolkitv1alpha1.ReasonCancelled, now)
+	setCondition(task, metav1.Condition{
+		Type:   toolkitv1alpha1.ConditionNotified,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonCallbackSent,
+	})
+	r := newDependencyReconciler(t, task)
+	r.Clock = func() time.Time { return now }
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(task)})
+	require.NoError(t, err)
+
+	err = r.Get(context.Background(), client.ObjectKeyFromObject(task), &toolkitv1alpha1.AgentTask{})
+	assert.True(t, apierrors.IsNotFound(err), "task is removed once the finalizer is gone")
+}
+
+func TestReconcile_DeletedTaskReleasedAfterTimeout(t *testing.T) {
+	deletedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := deletedTask("task-unnotified", metav1.ConditionFalse, toolkitv1alpha1.ReasonCancelled, deletedAt)
+	r := newDependencyReconciler(t, task)
+	r.Clock = func() time.Time { return deletedAt.Add(deletionNotifyTimeout) }
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(task)})
+	require.NoError(t, err)
+
+	err = r.Get(context.Background(), client.ObjectKeyFromObject(task), &toolkitv1alpha1.AgentTask{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
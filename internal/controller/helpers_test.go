@@ -17,10 +17,17 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
 )
@@ -127,6 +134,46 @@ func TestSetCondition(t *testing.T) {
 	assert.Equal(t, metav1.ConditionTrue, task.Status.Conditions[0].Status)
 }
 
+func TestPatchStatus(t *testing.T) {
+	ctx := context.Background()
+	task := dependencyTask("task-patch", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning)
+	r := newDependencyReconciler(t, task)
+
+	var stale toolkitv1alpha1.AgentTask
+	require.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(task), &stale))
+
+	// Another writer records a result in the meantime.
+	var current toolkitv1alpha1.AgentTask
+	require.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(task), &current))
+	current.Status.Result.CostUSD = "0.1000"
+	require.NoError(t, r.Status().Update(ctx, &current))
+
+	t.Run("fields outside conditions are patched without a conflict", func(t *testing.T) {
+		task := stale.DeepCopy()
+		base := task.DeepCopy()
+		task.Status.SandboxClaimName = "claim"
+		require.NoError(t, r.patchStatus(ctx, task, base))
+
+		var got toolkitv1alpha1.AgentTask
+		require.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(task), &got))
+		assert.Equal(t, "claim", got.Status.SandboxClaimName)
+		assert.Equal(t, "0.1000", got.Status.Result.CostUSD, "other writer's field is kept")
+		assert.Equal(t, toolkitv1alpha1.PhaseRunning, got.Status.Phase)
+	})
+
+	t.Run("condition changes from a stale read conflict", func(t *testing.T) {
+		task := stale.DeepCopy()
+		base := task.DeepCopy()
+		setCondition(task, metav1.Condition{
+			Type:   toolkitv1alpha1.ConditionSucceeded,
+			Status: metav1.ConditionFalse,
+			Reason: toolkitv1alpha1.ReasonFailed,
+		})
+		err := r.patchStatus(ctx, task, base)
+		assert.True(t, apierrors.IsConflict(err), "got %v", err)
+	})
+}
+
 func taskWithCondition(status metav1.ConditionStatus, reason string) *toolkitv1alpha1.AgentTask {
 	task := &toolkitv1alpha1.AgentTask{}
 	setCondition(task, metav1.Condition{
@@ -136,3 +183,31 @@ func taskWithCondition(status metav1.ConditionStatus, reason string) *toolkitv1a
 	})
 	return task
 }
+
+func TestReconcile_CatchesUpPhase(t *testing.T) {
+	// The API server recorded the runner's result without touching the phase.
+	task := dependencyTask("task-phase", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded)
+	task.Status.Phase = toolkitv1alpha1.PhaseRunning
+	task.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	r := newDependencyReconciler(t, task)
+
+	key := client.ObjectKeyFromObject(task)
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	var got toolkitv1alpha1.AgentTask
+	require.NoError(t, r.Get(context.Background(), key, &got))
+	assert.Equal(t, toolkitv1alpha1.PhaseSucceeded, got.Status.Phase)
+}
+
+func TestBecameTerminal(t *testing.T) {
+	running := taskWithCondition(metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning)
+	cancelled := taskWithCondition(metav1.ConditionFalse, toolkitv1alpha1.ReasonCancelled)
+	succeeded := taskWithCondition(metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded)
+
+	assert.True(t, becameTerminal.Update(event.UpdateEvent{ObjectOld: running, ObjectNew: cancelled}))
+	assert.False(t, becameTerminal.Update(event.UpdateEvent{ObjectOld: running, ObjectNew: running}))
+	assert.False(t, becameTerminal.Update(event.UpdateEvent{ObjectOld: cancelled, ObjectNew: succeeded}),
+		"already finished")
+	assert.False(t, becameTerminal.Create(event.CreateEvent{Object: cancelled}))
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+var (
+	sandboxProvisioningSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "shepherd_sandbox_provisioning_duration_seconds",
+		Help: "Time from creating a task's SandboxClaim to assigning the task to its runner.",
+		// Sandboxes start in seconds from a warm pool and in minutes when
+		// the image has to be pulled.
+		Buckets: []float64{1, 2.5, 5, 10, 20, 30, 60, 120, 300, 600},
+	}, []string{"sandbox_template"})
+
+	taskAssignmentFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "shepherd_task_assignment_failures_total",
+		Help: "Failed attempts to assign a task to the runner in its sandbox.",
+	})
+
+	gracePeriodExpirations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "shepherd_sandbox_grace_period_expirations_total",
+		Help: "Tasks whose sandbox terminated and whose runner did not report a result within the grace period, by the reason the task failed with.",
+	}, []string{"reason"})
+
+	tasksTimedOut = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "shepherd_tasks_timed_out_total",
+		Help: "Tasks that failed because their sandbox reached its shutdown time.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		sandboxProvisioningSeconds,
+		taskAssignmentFailures,
+		gracePeriodExpirations,
+		tasksTimedOut,
+	)
+}
+
+var tasksByPhaseDesc = prometheus.NewDesc(
+	"shepherd_agenttasks",
+	"Number of AgentTasks by phase.",
+	[]string{"namespace", "phase"}, nil,
+)
+
+// phaseCollector reports the number of AgentTasks in each phase. It counts
+// the tasks in the informer cache on every scrape rather than tracking
+// transitions, so the gauge stays right across operator restarts, task
+// deletions and status writes by the API server.
+type phaseCollector struct {
+	reader client.Reader
+}
+
+func (c phaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tasksByPhaseDesc
+}
+
+func (c phaseCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var tasks toolkitv1alpha1.AgentTaskList
+	if err := c.reader.List(ctx, &tasks); err != nil {
+		logf.Log.WithName("metrics").Error(err, "listing AgentTasks")
+		return
+	}
+
+	type key struct {
+		namespace string
+		phase     toolkitv1alpha1.TaskPhase
+	}
+	counts := map[key]int{}
+	for i := range tasks.Items {
+		task := &tasks.Items[i]
+		counts[key{task.Namespace, task.ComputePhase()}]++
+	}
+	for k, n := range counts {
+		ch <- prometheus.MustNewConstMetric(tasksByPhaseDesc, prometheus.GaugeValue, float64(n), k.namespace, string(k.phase))
+	}
+}
+
+// registerPhaseCollector registers the tasks-by-phase gauge. A collector
+// registered by an earlier manager in the same process is kept.
+func registerPhaseCollector(reader client.Reader) error {
+	err := metrics.Registry.Register(phaseCollector{reader: reader})
+	if are := (prometheus.AlreadyRegisteredError{}); errors.As(err, &are) {
+		return nil
+	}
+	return err
+}
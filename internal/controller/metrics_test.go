@@ -0,0 +1,67 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestPhaseCollector(t *testing.T) {
+	r := newDependencyReconciler(t,
+		dependencyTask("task-a", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning),
+		dependencyTask("task-b", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning),
+		dependencyTask("task-c", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded),
+		dependencyTask("task-d", metav1.ConditionUnknown, toolkitv1alpha1.ReasonQueued),
+	)
+
+	expected := `
+# HELP shepherd_agenttasks Number of AgentTasks by phase.
+# TYPE shepherd_agenttasks gauge
+shepherd_agenttasks{namespace="default",phase="Pending"} 1
+shepherd_agenttasks{namespace="default",phase="Running"} 2
+shepherd_agenttasks{namespace="default",phase="Succeeded"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(phaseCollector{reader: r.Client}, strings.NewReader(expected)))
+}
+
+func TestGracePeriodExpirationMetrics(t *testing.T) {
+	timedOut := testutil.ToFloat64(tasksTimedOut)
+	expired := testutil.ToFloat64(gracePeriodExpirations.WithLabelValues(toolkitv1alpha1.ReasonTimedOut))
+
+	task, claim := terminatingTask(operatorNow.Add(-time.Minute+graceDuration), operatorNow.Add(-70*time.Second))
+	r := newDependencyReconciler(t, task, claim)
+	r.Clock = func() time.Time { return operatorNow }
+
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(task)}
+	_, err := r.handleSandboxTermination(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, timedOut+1, testutil.ToFloat64(tasksTimedOut))
+	assert.Equal(t, expired+1, testutil.ToFloat64(gracePeriodExpirations.WithLabelValues(toolkitv1alpha1.ReasonTimedOut)))
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PrePullDaemonSetName is the name of the DaemonSet that keeps runner
+// images pulled on every node.
+const PrePullDaemonSetName = "shepherd-image-prepull"
+
+// DefaultPrePullPauseImage is the container the pre-pull pods keep running
+// once their images are pulled.
+const DefaultPrePullPauseImage = "registry.k8s.io/pause:3.10"
+
+// prePullResyncInterval is how often the pre-pull DaemonSet is re-applied,
+// undoing changes made to it by hand.
+const prePullResyncInterval = 10 * time.Minute
+
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;create;patch;delete
+
+// ImagePrePuller maintains a DaemonSet that pulls runner images onto every
+// node ahead of time, so a sandbox scheduled on a fresh node does not spend
+// minutes pulling its image. Each image is pulled by an init container
+// running "sh -c true", so the images need a shell; the pod then idles in
+// a pause container, which keeps the images from being garbage collected.
+// Without images, a DaemonSet left from an earlier configuration is
+// deleted.
+type ImagePrePuller struct {
+	Client    client.Client
+	Scheme    *runtime.Scheme
+	Namespace string
+	Images    []string
+	// PauseImage defaults to DefaultPrePullPauseImage.
+	PauseImage       string
+	ImagePullSecrets []string
+	// NodeSelector limits the DaemonSet to the nodes sandboxes run on.
+	NodeSelector map[string]string
+}
+
+// Start implements manager.Runnable. It only runs on the leader.
+func (p *ImagePrePuller) Start(ctx context.Context) error {
+	log := ctrl.Log.WithName("image-prepull")
+
+	if len(p.Images) == 0 {
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: PrePullDaemonSetName, Namespace: p.Namespace}}
+		if err := p.Client.Delete(ctx, ds); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "deleting unused image pre-pull DaemonSet")
+		}
+		return nil
+	}
+
+	ticker := time.NewTicker(prePullResyncInterval)
+	defer ticker.Stop()
+	for {
+		if err := applyObject(ctx, p.Client, p.Scheme, p.daemonSet()); err != nil {
+			log.Error(err, "applying image pre-pull DaemonSet")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *ImagePrePuller) daemonSet() *appsv1.DaemonSet {
+	labels := map[string]string{
+		"app.kubernetes.io/name":       PrePullDaemonSetName,
+		"app.kubernetes.io/managed-by": fieldManager,
+	}
+	securityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1m"),
+			corev1.ResourceMemory: resource.MustParse("8Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("32Mi"),
+		},
+	}
+
+	initContainers := make([]corev1.Container, 0, len(p.Images))
+	for i, image := range p.Images {
+		initContainers = append(initContainers, corev1.Container{
+			Name:            fmt.Sprintf("prepull-%d", i),
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"sh", "-c", "true"},
+			Resources:       resources,
+			SecurityContext: securityContext,
+		})
+	}
+	pullSecrets := make([]corev1.LocalObjectReference, 0, len(p.ImagePullSecrets))
+	for _, name := range p.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: name})
+	}
+	pauseImage := p.PauseImage
+	if pauseImage == "" {
+		pauseImage = DefaultPrePullPauseImage
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PrePullDaemonSetName,
+			Namespace: p.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					InitContainers: initContainers,
+					Containers: []corev1.Container{{
+						Name:      "pause",
+						Image:     pauseImage,
+						Resources: resources,
+						SecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: ptr.To(false),
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+							RunAsNonRoot:             ptr.To(true),
+							RunAsUser:                ptr.To[int64](65535),
+							ReadOnlyRootFilesystem:   ptr.To(true),
+						},
+					}},
+					ImagePullSecrets:              pullSecrets,
+					NodeSelector:                  p.NodeSelector,
+					AutomountServiceAccountToken:  ptr.To(false),
+					TerminationGracePeriodSeconds: ptr.To[int64](0),
+				},
+			},
+		},
+	}
+}
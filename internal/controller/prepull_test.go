@@ -0,0 +1,80 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPrePuller(t *testing.T, objs ...client.Object) *ImagePrePuller {
+	t.Helper()
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	return &ImagePrePuller{
+		Client:    fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(),
+		Scheme:    s,
+		Namespace: "shepherd",
+	}
+}
+
+func TestImagePrePuller_AppliesDaemonSet(t *testing.T) {
+	p := newPrePuller(t)
+	p.Images = []string{"ghcr.io/acme/runner-python:1.2", "ghcr.io/acme/runner-go:1.2"}
+	p.ImagePullSecrets = []string{"ghcr"}
+	p.NodeSelector = map[string]string{"pool": "sandboxes"}
+
+	// A cancelled context makes Start return after the first apply.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, p.Start(ctx))
+
+	var ds appsv1.DaemonSet
+	require.NoError(t, p.Client.Get(context.Background(),
+		client.ObjectKey{Namespace: "shepherd", Name: PrePullDaemonSetName}, &ds))
+	spec := ds.Spec.Template.Spec
+	require.Len(t, spec.InitContainers, 2)
+	assert.Equal(t, "ghcr.io/acme/runner-python:1.2", spec.InitContainers[0].Image)
+	assert.Equal(t, []string{"sh", "-c", "true"}, spec.InitContainers[1].Command)
+	require.Len(t, spec.Containers, 1)
+	assert.Equal(t, DefaultPrePullPauseImage, spec.Containers[0].Image)
+	assert.Equal(t, "ghcr", spec.ImagePullSecrets[0].Name)
+	assert.Equal(t, map[string]string{"pool": "sandboxes"}, spec.NodeSelector)
+	assert.Equal(t, ds.Spec.Selector.MatchLabels, ds.Spec.Template.Labels)
+}
+
+func TestImagePrePuller_DeletesUnusedDaemonSet(t *testing.T) {
+	p := newPrePuller(t, &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: PrePullDaemonSetName, Namespace: "shepherd"},
+	})
+
+	require.NoError(t, p.Start(context.Background()))
+
+	err := p.Client.Get(context.Background(),
+		client.ObjectKey{Namespace: "shepherd", Name: PrePullDaemonSetName}, &appsv1.DaemonSet{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
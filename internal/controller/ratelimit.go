@@ -0,0 +1,121 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// RateLimitOptions tunes how the AgentTask work queue retries failed
+// reconciles and how often a single task may be reconciled. Zero values
+// fall back to the controller-runtime defaults.
+type RateLimitOptions struct {
+	// BaseDelay and MaxDelay bound the per-task exponential backoff after a
+	// failed reconcile.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// QPS and Burst limit retries of failed reconciles across all tasks.
+	QPS   float64
+	Burst int
+	// TaskQPS and TaskBurst limit how often one task is reconciled, however
+	// many events it produces. Zero TaskQPS disables the limit.
+	TaskQPS   float64
+	TaskBurst int
+}
+
+// Defaults match workqueue.DefaultTypedControllerRateLimiter.
+const (
+	defaultRateLimitBaseDelay = 5 * time.Millisecond
+	defaultRateLimitMaxDelay  = 1000 * time.Second
+	defaultRateLimitQPS       = 10
+	defaultRateLimitBurst     = 100
+)
+
+// queueRateLimiter returns the rate limiter for failed reconciles: the
+// slower of a per-task exponential backoff and an overall token bucket.
+func (o RateLimitOptions) queueRateLimiter() workqueue.TypedRateLimiter[reconcile.Request] {
+	baseDelay := cmp.Or(o.BaseDelay, defaultRateLimitBaseDelay)
+	maxDelay := cmp.Or(o.MaxDelay, defaultRateLimitMaxDelay)
+	qps := cmp.Or(o.QPS, defaultRateLimitQPS)
+	burst := cmp.Or(o.Burst, defaultRateLimitBurst)
+
+	return workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseDelay, maxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
+
+// taskRateLimiter limits how often each task is reconciled. Watch events do
+// not pass through the work queue's rate limiter, so without it a task whose
+// status churns is reconciled on every change and holds a worker that other
+// tasks are waiting for.
+type taskRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	limiters  map[types.NamespacedName]*rate.Limiter
+	lastPrune time.Time
+}
+
+// taskLimiterPruneInterval is how often limiters of tasks that have been
+// quiet long enough to refill their burst are dropped.
+const taskLimiterPruneInterval = time.Minute
+
+func newTaskRateLimiter(qps float64, burst int) *taskRateLimiter {
+	return &taskRateLimiter{
+		limit:    rate.Limit(qps),
+		burst:    max(burst, 1),
+		limiters: map[types.NamespacedName]*rate.Limiter{},
+	}
+}
+
+// delay returns how long the task must wait before it may be reconciled.
+// Zero means it may be reconciled now, which uses up one token.
+func (l *taskRateLimiter) delay(key types.NamespacedName, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastPrune) >= taskLimiterPruneInterval {
+		for k, lim := range l.limiters {
+			if lim.TokensAt(now) >= float64(l.burst) {
+				delete(l.limiters, k)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	lim, ok := l.limiters[key]
+	if !ok {
+		lim = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[key] = lim
+	}
+	res := lim.ReserveN(now, 1)
+	d := res.DelayFrom(now)
+	if d > 0 {
+		// The reconcile is skipped, so it must not use up a future token.
+		res.CancelAt(now)
+	}
+	return d
+}
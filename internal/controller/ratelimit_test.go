@@ -0,0 +1,94 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestTaskRateLimiter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	flapping := types.NamespacedName{Namespace: "default", Name: "flapping"}
+	quiet := types.NamespacedName{Namespace: "default", Name: "quiet"}
+
+	l := newTaskRateLimiter(1, 2)
+
+	assert.Zero(t, l.delay(flapping, now), "first reconcile uses the burst")
+	assert.Zero(t, l.delay(flapping, now), "second reconcile uses the burst")
+	assert.Equal(t, time.Second, l.delay(flapping, now), "burst exhausted")
+	assert.Equal(t, time.Second, l.delay(flapping, now), "skipped reconciles do not push the delay further out")
+
+	assert.Zero(t, l.delay(quiet, now), "other tasks are not affected")
+
+	assert.Zero(t, l.delay(flapping, now.Add(time.Second)), "token refilled")
+}
+
+func TestTaskRateLimiter_PrunesIdleTasks(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := newTaskRateLimiter(1, 2)
+
+	for i := range 3 {
+		l.delay(types.NamespacedName{Namespace: "default", Name: string(rune('a' + i))}, now)
+	}
+	require.Len(t, l.limiters, 3)
+
+	l.delay(types.NamespacedName{Namespace: "default", Name: "a"}, now.Add(taskLimiterPruneInterval))
+	assert.Len(t, l.limiters, 1, "refilled limiters are dropped")
+}
+
+func TestQueueRateLimiter_Defaults(t *testing.T) {
+	limiter := RateLimitOptions{}.queueRateLimiter()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "task"}}
+
+	assert.Equal(t, defaultRateLimitBaseDelay, limiter.When(req))
+	assert.Equal(t, 2*defaultRateLimitBaseDelay, limiter.When(req))
+	limiter.Forget(req)
+	assert.Equal(t, defaultRateLimitBaseDelay, limiter.When(req))
+}
+
+func TestQueueRateLimiter_MaxDelay(t *testing.T) {
+	limiter := RateLimitOptions{BaseDelay: time.Second, MaxDelay: 3 * time.Second}.queueRateLimiter()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "task"}}
+
+	for range 5 {
+		limiter.When(req)
+	}
+	assert.Equal(t, 3*time.Second, limiter.When(req))
+}
+
+func TestReconcile_TaskRateLimited(t *testing.T) {
+	task := dependentTask("task-churn")
+	r := newDependencyReconciler(t, task)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r.Clock = func() time.Time { return now }
+	r.taskLimiter = newTaskRateLimiter(0.5, 1)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "task-churn"}}
+
+	_, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, result.RequeueAfter)
+}
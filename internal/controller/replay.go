@@ -0,0 +1,352 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxv1alpha1 "sigs.k8s.io/agent-sandbox/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+// ReplayFixture is a recorded sequence of SandboxClaim and Sandbox status
+// transitions of one AgentTask. Replay feeds it through the reconciler on a
+// fake clock and a fake API server, so timing bugs seen in production can be
+// reproduced and kept as regression tests.
+type ReplayFixture struct {
+	// Name describes the scenario.
+	Name string `json:"name,omitempty"`
+	// Start is the time of the first step; defaults to 2026-01-01T00:00:00Z.
+	Start metav1.Time `json:"start,omitempty"`
+	// Task is the AgentTask as it was created. Its namespace defaults to
+	// "default".
+	Task toolkitv1alpha1.AgentTask `json:"task"`
+	// TTLAfterFinished is the operator's --ttl-after-finished setting.
+	TTLAfterFinished metav1.Duration `json:"ttlAfterFinished,omitempty"`
+	Steps            []ReplayStep    `json:"steps"`
+}
+
+// ReplayStep is one observation in a ReplayFixture. The changes it
+// describes are written first, then the task is reconciled once.
+type ReplayStep struct {
+	// At is the time of the step as an offset from the fixture's start.
+	// Steps must be in order.
+	At metav1.Duration `json:"at"`
+	// Claim replaces the status of the task's SandboxClaim, which the
+	// reconciler must have created by then.
+	Claim *sandboxextv1alpha1.SandboxClaimStatus `json:"claim,omitempty"`
+	// Sandbox creates or updates the Sandbox named in the claim's status.
+	Sandbox *sandboxv1alpha1.SandboxStatus `json:"sandbox,omitempty"`
+	// Conditions are set on the task's status, the way the API server
+	// records the runner's final status.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// RunnerStatus is the HTTP status the runner answers a task assignment
+	// with; defaults to 200.
+	RunnerStatus int `json:"runnerStatus,omitempty"`
+	// Expect, when set, is checked against the outcome of the step.
+	Expect *ReplayExpectation `json:"expect,omitempty"`
+}
+
+// ReplayExpectation is the expected outcome of a ReplayStep. Unset fields
+// are not checked.
+type ReplayExpectation struct {
+	// Reason is the reason of the task's Succeeded condition.
+	Reason string `json:"reason,omitempty"`
+	// Message is a substring of the Succeeded condition's message.
+	Message      string           `json:"message,omitempty"`
+	RequeueAfter *metav1.Duration `json:"requeueAfter,omitempty"`
+	// GracePeriod is whether a sandbox termination grace period is running.
+	GracePeriod *bool `json:"gracePeriod,omitempty"`
+	// Claim is whether the task's SandboxClaim exists.
+	Claim *bool `json:"claim,omitempty"`
+	// Error is a substring of the error the reconcile returned. A step
+	// whose reconcile fails must expect the error.
+	Error string `json:"error,omitempty"`
+}
+
+// ReplayResult is the outcome of one ReplayStep.
+type ReplayResult struct {
+	At            time.Duration
+	Reason        string
+	Message       string
+	RequeueAfter  time.Duration
+	GraceDeadline *time.Time
+	Claim         bool
+	Err           error
+	// Events are the Kubernetes events recorded during the step.
+	Events []string
+	// Mismatches lists how the outcome differs from the step's expectation.
+	Mismatches []string
+}
+
+// LoadReplayFixture reads a ReplayFixture from a YAML or JSON file.
+func LoadReplayFixture(path string) (*ReplayFixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f ReplayFixture
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if f.Task.Name == "" {
+		return nil, fmt.Errorf("%s: task.metadata.name is required", path)
+	}
+	return &f, nil
+}
+
+// Replay runs the steps of f through an AgentTaskReconciler and returns the
+// outcome of each. An error means the fixture could not be replayed; a
+// step that did not go as expected is reported in its Mismatches.
+func Replay(ctx context.Context, f *ReplayFixture) ([]ReplayResult, error) {
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		toolkitv1alpha1.AddToScheme, sandboxextv1alpha1.AddToScheme, sandboxv1alpha1.AddToScheme,
+	} {
+		if err := add(s); err != nil {
+			return nil, err
+		}
+	}
+
+	start := f.Start.Time
+	if start.IsZero() {
+		start = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	now := start
+
+	task := f.Task.DeepCopy()
+	if task.Namespace == "" {
+		task.Namespace = "default"
+	}
+	task.CreationTimestamp = metav1.NewTime(start)
+	key := client.ObjectKeyFromObject(task)
+
+	runner := &replayRunner{status: http.StatusOK}
+	recorder := events.NewFakeRecorder(100)
+	r := &AgentTaskReconciler{
+		// The fake client serves the server-side apply of the SandboxClaim
+		// with its default type converters, which deduce the claim's schema.
+		Client: fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(task).
+			WithStatusSubresource(&toolkitv1alpha1.AgentTask{}).
+			WithIndex(&toolkitv1alpha1.AgentTask{}, dependsOnIndex, indexDependsOn).
+			Build(),
+		Scheme:           s,
+		Recorder:         recorder,
+		APIURL:           "http://shepherd-api.replay:8080",
+		HTTPClient:       &http.Client{Transport: runner},
+		TTLAfterFinished: f.TTLAfterFinished.Duration,
+		Clock:            func() time.Time { return now },
+	}
+
+	results := make([]ReplayResult, 0, len(f.Steps))
+	for i, step := range f.Steps {
+		at := start.Add(step.At.Duration)
+		if at.Before(now) {
+			return results, fmt.Errorf("step %d: at %s is before the previous step", i+1, step.At.Duration)
+		}
+		now = at
+		runner.status = http.StatusOK
+		if step.RunnerStatus != 0 {
+			runner.status = step.RunnerStatus
+		}
+		if err := applyReplayStep(ctx, r.Client, key, step, now); err != nil {
+			return results, fmt.Errorf("step %d: %w", i+1, err)
+		}
+
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		res := ReplayResult{At: step.At.Duration, RequeueAfter: result.RequeueAfter, Err: err}
+		if err := observeReplay(ctx, r.Client, key, now, &res); err != nil {
+			return results, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		res.Events = drainEvents(recorder)
+		if step.Expect != nil {
+			res.Mismatches = step.Expect.check(&res)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// applyReplayStep writes the object changes of step.
+func applyReplayStep(ctx context.Context, c client.Client, key client.ObjectKey, step ReplayStep, now time.Time) error {
+	var claim sandboxextv1alpha1.SandboxClaim
+	if step.Claim != nil || step.Sandbox != nil {
+		if err := c.Get(ctx, key, &claim); err != nil {
+			return fmt.Errorf("getting sandbox claim: %w", err)
+		}
+	}
+	if step.Claim != nil {
+		claim.Status = *step.Claim
+		for i := range claim.Status.Conditions {
+			if claim.Status.Conditions[i].LastTransitionTime.IsZero() {
+				claim.Status.Conditions[i].LastTransitionTime = metav1.NewTime(now)
+			}
+		}
+		if err := c.Update(ctx, &claim); err != nil {
+			return fmt.Errorf("updating sandbox claim: %w", err)
+		}
+	}
+	if step.Sandbox != nil {
+		name := claim.Status.SandboxStatus.Name
+		if name == "" {
+			return fmt.Errorf("sandbox given but the claim's status names no sandbox")
+		}
+		sandbox := &sandboxv1alpha1.Sandbox{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: name}, sandbox)
+		if client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("getting sandbox: %w", err)
+		}
+		sandbox.Status = *step.Sandbox
+		if err != nil {
+			sandbox.Name, sandbox.Namespace = name, key.Namespace
+			sandbox.CreationTimestamp = metav1.NewTime(now)
+			err = c.Create(ctx, sandbox)
+		} else {
+			err = c.Update(ctx, sandbox)
+		}
+		if err != nil {
+			return fmt.Errorf("writing sandbox: %w", err)
+		}
+	}
+	if len(step.Conditions) > 0 {
+		var task toolkitv1alpha1.AgentTask
+		if err := c.Get(ctx, key, &task); err != nil {
+			return fmt.Errorf("getting task: %w", err)
+		}
+		for _, cond := range step.Conditions {
+			if cond.LastTransitionTime.IsZero() {
+				cond.LastTransitionTime = metav1.NewTime(now)
+			}
+			meta.SetStatusCondition(&task.Status.Conditions, cond)
+		}
+		if task.IsTerminal() && task.Status.CompletionTime == nil {
+			completion := metav1.NewTime(now)
+			task.Status.CompletionTime = &completion
+		}
+		if err := c.Status().Update(ctx, &task); err != nil {
+			return fmt.Errorf("updating task status: %w", err)
+		}
+	}
+	return nil
+}
+
+// observeReplay records the state of the task and its claim in res. A
+// claim created during the step gets the current time as its creation
+// timestamp, which the fake client leaves empty.
+func observeReplay(ctx context.Context, c client.Client, key client.ObjectKey, now time.Time, res *ReplayResult) error {
+	var task toolkitv1alpha1.AgentTask
+	err := c.Get(ctx, key, &task)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("getting task: %w", err)
+	}
+	if err != nil {
+		res.Reason = "Deleted"
+	} else {
+		if cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded); cond != nil {
+			res.Reason, res.Message = cond.Reason, cond.Message
+		}
+		if task.Status.GraceDeadline != nil {
+			deadline := task.Status.GraceDeadline.Time
+			res.GraceDeadline = &deadline
+		}
+	}
+
+	var claim sandboxextv1alpha1.SandboxClaim
+	err = c.Get(ctx, key, &claim)
+	if client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("getting sandbox claim: %w", err)
+	}
+	res.Claim = err == nil
+	if res.Claim && claim.CreationTimestamp.IsZero() {
+		claim.CreationTimestamp = metav1.NewTime(now)
+		if err := c.Update(ctx, &claim); err != nil {
+			return fmt.Errorf("updating sandbox claim: %w", err)
+		}
+	}
+	return nil
+}
+
+// check returns how res differs from e.
+func (e *ReplayExpectation) check(res *ReplayResult) []string {
+	var mismatches []string
+	if e.Reason != "" && res.Reason != e.Reason {
+		mismatches = append(mismatches, fmt.Sprintf("reason: got %q, want %q", res.Reason, e.Reason))
+	}
+	if e.Message != "" && !strings.Contains(res.Message, e.Message) {
+		mismatches = append(mismatches, fmt.Sprintf("message: got %q, want it to contain %q", res.Message, e.Message))
+	}
+	if e.RequeueAfter != nil && res.RequeueAfter != e.RequeueAfter.Duration {
+		mismatches = append(mismatches, fmt.Sprintf("requeueAfter: got %s, want %s", res.RequeueAfter, e.RequeueAfter.Duration))
+	}
+	if e.GracePeriod != nil && (res.GraceDeadline != nil) != *e.GracePeriod {
+		mismatches = append(mismatches, fmt.Sprintf("gracePeriod: got %t, want %t", res.GraceDeadline != nil, *e.GracePeriod))
+	}
+	if e.Claim != nil && res.Claim != *e.Claim {
+		mismatches = append(mismatches, fmt.Sprintf("claim: got %t, want %t", res.Claim, *e.Claim))
+	}
+	switch {
+	case res.Err == nil && e.Error != "":
+		mismatches = append(mismatches, fmt.Sprintf("error: got none, want %q", e.Error))
+	case res.Err != nil && (e.Error == "" || !strings.Contains(res.Err.Error(), e.Error)):
+		mismatches = append(mismatches, fmt.Sprintf("error: got %q, want %q", res.Err, e.Error))
+	}
+	return mismatches
+}
+
+func drainEvents(recorder *events.FakeRecorder) []string {
+	var recorded []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			recorded = append(recorded, e)
+		default:
+			return recorded
+		}
+	}
+}
+
+// replayRunner answers every task assignment with status.
+type replayRunner struct {
+	status int
+}
+
+func (rr *replayRunner) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: rr.status,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Header:     http.Header{},
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+// TestReplayFixtures replays every fixture in testdata/replay. Add a
+// fixture there to keep a production timing bug from coming back.
+func TestReplayFixtures(t *testing.T) {
+	paths, err := filepath.Glob("testdata/replay/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			f, err := LoadReplayFixture(path)
+			require.NoError(t, err)
+			results, err := Replay(context.Background(), f)
+			require.NoError(t, err)
+			require.Len(t, results, len(f.Steps))
+			for i, res := range results {
+				assert.Empty(t, res.Mismatches, "step %d at %s", i+1, res.At)
+			}
+		})
+	}
+}
+
+func TestReplay_Deterministic(t *testing.T) {
+	f, err := LoadReplayFixture("testdata/replay/grace-period-elapsed.yaml")
+	require.NoError(t, err)
+
+	first, err := Replay(context.Background(), f)
+	require.NoError(t, err)
+	second, err := Replay(context.Background(), f)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	graceStarted := first[3]
+	require.NotNil(t, graceStarted.GraceDeadline)
+	assert.Equal(t, time.Date(2026, 3, 1, 12, 10, 30, 0, time.UTC), graceStarted.GraceDeadline.UTC())
+	assert.Contains(t, first[len(first)-1].Events, "Warning Failed Sandbox terminated: pod was evicted")
+}
+
+func TestReplay_ReportsMismatches(t *testing.T) {
+	f := &ReplayFixture{
+		Task: *dependentTask("task-replay"),
+		Steps: []ReplayStep{{
+			Expect: &ReplayExpectation{
+				Reason:       "Running",
+				RequeueAfter: &metav1.Duration{Duration: time.Minute},
+			},
+		}},
+	}
+	f.Task.Status = toolkitv1alpha1.AgentTaskStatus{}
+
+	results, err := Replay(context.Background(), f)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{
+		`reason: got "Pending", want "Running"`,
+		"requeueAfter: got 1s, want 1m0s",
+	}, results[0].Mismatches)
+}
+
+func TestReplay_InvalidFixture(t *testing.T) {
+	t.Run("steps out of order", func(t *testing.T) {
+		f := &ReplayFixture{
+			Task: *dependentTask("task-replay"),
+			Steps: []ReplayStep{
+				{At: metav1.Duration{Duration: time.Minute}},
+				{At: metav1.Duration{Duration: time.Second}},
+			},
+		}
+		_, err := Replay(context.Background(), f)
+		assert.ErrorContains(t, err, "step 2: at 1s is before the previous step")
+	})
+
+	t.Run("claim status before the claim exists", func(t *testing.T) {
+		f := &ReplayFixture{
+			Task:  *dependentTask("task-replay"),
+			Steps: []ReplayStep{{Claim: &sandboxextv1alpha1.SandboxClaimStatus{}}},
+		}
+		_, err := Replay(context.Background(), f)
+		assert.ErrorContains(t, err, "step 1: getting sandbox claim")
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// TemplateRoute sends tasks whose labels match Selector to the sandbox
+// template Template, whatever template they asked for.
+type TemplateRoute struct {
+	Template string
+	Selector labels.Selector
+}
+
+// ParseTemplateRoute parses a route written as "template:selector", where
+// selector is a Kubernetes label selector such as
+// "shepherd.io/language=python,shepherd.io/source-type=issue".
+func ParseTemplateRoute(s string) (TemplateRoute, error) {
+	template, selector, ok := strings.Cut(s, ":")
+	template, selector = strings.TrimSpace(template), strings.TrimSpace(selector)
+	if !ok || template == "" || selector == "" {
+		return TemplateRoute{}, fmt.Errorf("template route %q must have the form template:selector", s)
+	}
+	if errs := validation.IsDNS1123Subdomain(template); len(errs) > 0 {
+		return TemplateRoute{}, fmt.Errorf("template route %q: invalid template name: %s", s, strings.Join(errs, "; "))
+	}
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return TemplateRoute{}, fmt.Errorf("template route %q: %w", s, err)
+	}
+	return TemplateRoute{Template: template, Selector: sel}, nil
+}
+
+// sandboxTemplate returns the sandbox template task runs in. Once the task
+// has a claim this is the template recorded in its status, so changing the
+// routes never moves a running task. Before that, the first route matching
+// the task's labels wins, and a task no route matches keeps the template
+// it asked for.
+func (r *AgentTaskReconciler) sandboxTemplate(task *toolkitv1alpha1.AgentTask) string {
+	if task.Status.SandboxClaimName != "" {
+		// Tasks claimed before the template was recorded used the one
+		// they asked for.
+		return cmp.Or(task.Status.SandboxTemplateName, task.Spec.Runner.SandboxTemplateName)
+	}
+	set := labels.Set(task.Labels)
+	for _, route := range r.TemplateRoutes {
+		if route.Selector.Matches(set) {
+			return route.Template
+		}
+	}
+	return task.Spec.Runner.SandboxTemplateName
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseRoutes(t *testing.T, specs ...string) []TemplateRoute {
+	t.Helper()
+	routes := make([]TemplateRoute, 0, len(specs))
+	for _, s := range specs {
+		route, err := ParseTemplateRoute(s)
+		require.NoError(t, err)
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+func TestParseTemplateRoute(t *testing.T) {
+	route, err := ParseTemplateRoute(" python : shepherd.io/language=python,shepherd.io/source-type in (issue) ")
+	require.NoError(t, err)
+	assert.Equal(t, "python", route.Template)
+	assert.Equal(t, "shepherd.io/language=python,shepherd.io/source-type in (issue)", route.Selector.String())
+
+	for _, s := range []string{
+		"python",
+		":shepherd.io/language=python",
+		"python:",
+		"Python_Tools:shepherd.io/language=python",
+		"python:shepherd.io/language in python",
+	} {
+		_, err := ParseTemplateRoute(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSandboxTemplate(t *testing.T) {
+	r := &AgentTaskReconciler{TemplateRoutes: mustParseRoutes(t,
+		"python-gpu:shepherd.io/language=python,shepherd.io/repo=acme-ml",
+		"python:shepherd.io/language=python",
+	)}
+
+	task := baseTask()
+	assert.Equal(t, "secure-runner-template", r.sandboxTemplate(task), "no route matches")
+
+	task.Labels = map[string]string{"shepherd.io/language": "python"}
+	assert.Equal(t, "python", r.sandboxTemplate(task))
+
+	task.Labels["shepherd.io/repo"] = "acme-ml"
+	assert.Equal(t, "python-gpu", r.sandboxTemplate(task), "first matching route wins")
+
+	// A claimed task stays on its template when the routes change.
+	task.Status.SandboxClaimName = task.Name
+	task.Status.SandboxTemplateName = "python"
+	assert.Equal(t, "python", r.sandboxTemplate(task))
+	task.Status.SandboxTemplateName = ""
+	assert.Equal(t, "secure-runner-template", r.sandboxTemplate(task), "claimed before the template was recorded")
+}
+
+func TestBuildSandboxClaim_RoutedTemplate(t *testing.T) {
+	cfg := baseSandboxCfg()
+	cfg.Template = "python"
+
+	claim, err := buildSandboxClaim(baseTask(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "python", claim.Spec.TemplateRef.Name)
+}
+
+func TestAdmit_RoutedTemplateLimits(t *testing.T) {
+	// Both tasks ask for "default" but are routed to "gpu", whose only
+	// slot the active one holds.
+	active := templateTask("task-active", "default", 0, 0)
+	active.Labels = map[string]string{"shepherd.io/language": "cuda"}
+	active.Status.SandboxClaimName = active.Name
+	active.Status.SandboxTemplateName = "gpu"
+	waiting := templateTask("task-waiting", "default", 0, 1)
+	waiting.Labels = map[string]string{"shepherd.io/language": "cuda"}
+
+	r := newAdmissionReconciler(t, 0, active, waiting)
+	r.MaxConcurrentTasksPerTemplate = map[string]int{"gpu": 1}
+	r.TemplateRoutes = mustParseRoutes(t, "gpu:shepherd.io/language=cuda")
+
+	got, err := r.admit(context.Background(), waiting)
+	require.NoError(t, err)
+	assert.Equal(t, admission{Position: 1, Template: "gpu"}, got)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package controller
 
 import (
+	"cmp"
 	"fmt"
 	"time"
 
@@ -31,6 +32,11 @@ import (
 // sandboxConfig holds operator-level configuration needed to build SandboxClaims.
 type sandboxConfig struct {
 	Scheme *runtime.Scheme
+	// Now is the time the runner timeout counts from; defaults to time.Now.
+	Now time.Time
+	// Template is the sandbox template to claim; defaults to the one the
+	// task asked for.
+	Template string
 }
 
 func buildSandboxClaim(task *toolkitv1alpha1.AgentTask, cfg sandboxConfig) (*sandboxextv1alpha1.SandboxClaim, error) {
@@ -39,15 +45,17 @@ func buildSandboxClaim(task *toolkitv1alpha1.AgentTask, cfg sandboxConfig) (*san
 		return nil, fmt.Errorf("task name %q exceeds 63-character limit", claimName)
 	}
 
-	if task.Spec.Runner.SandboxTemplateName == "" {
+	template := cmp.Or(cfg.Template, task.Spec.Runner.SandboxTemplateName)
+	if template == "" {
 		return nil, fmt.Errorf("sandboxTemplateName is required")
 	}
 
-	timeout := task.Spec.Runner.Timeout.Duration
-	if timeout == 0 {
-		timeout = defaultTimeout
+	timeout := task.RunnerTimeout()
+	now := cfg.Now
+	if now.IsZero() {
+		now = time.Now()
 	}
-	shutdownTime := metav1.NewTime(time.Now().Add(timeout))
+	shutdownTime := deadlineAfter(now, timeout)
 	shutdownPolicy := sandboxextv1alpha1.ShutdownPolicyRetain
 
 	claim := &sandboxextv1alpha1.SandboxClaim{
@@ -60,7 +68,7 @@ func buildSandboxClaim(task *toolkitv1alpha1.AgentTask, cfg sandboxConfig) (*san
 		},
 		Spec: sandboxextv1alpha1.SandboxClaimSpec{
 			TemplateRef: sandboxextv1alpha1.SandboxTemplateRef{
-				Name: task.Spec.Runner.SandboxTemplateName,
+				Name: template,
 			},
 			Lifecycle: &sandboxextv1alpha1.Lifecycle{
 				ShutdownTime:   &shutdownTime,
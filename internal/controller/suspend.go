@@ -0,0 +1,100 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+const suspendedMessage = "Task is suspended, set spec.suspend to false to resume"
+
+// hasReason reports whether the task's Succeeded condition has the reason.
+func hasReason(task *toolkitv1alpha1.AgentTask, reason string) bool {
+	cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	return cond != nil && cond.Reason == reason
+}
+
+// suspend holds a task that has not started running. An idle SandboxClaim
+// (claim may be nil) is deleted so the sandbox is freed for other tasks; a
+// new one is claimed when the task is resumed. The task is reconciled again
+// when its spec changes, so nothing is requeued.
+func (r *AgentTaskReconciler) suspend(ctx context.Context, task *toolkitv1alpha1.AgentTask, claim *sandboxextv1alpha1.SandboxClaim) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if claim != nil && claim.DeletionTimestamp.IsZero() {
+		if err := r.Delete(ctx, claim); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("deleting idle sandbox claim: %w", err)
+		}
+		log.Info("deleted idle SandboxClaim of suspended task", "claim", claim.Name)
+	}
+
+	if hasReason(task, toolkitv1alpha1.ReasonSuspended) && task.Status.SandboxClaimName == "" {
+		return ctrl.Result{}, nil
+	}
+
+	base := task.DeepCopy()
+	task.Status.SandboxClaimName = ""
+	task.Status.SandboxTemplateName = ""
+	setCondition(task, metav1.Condition{
+		Type:               toolkitv1alpha1.ConditionSucceeded,
+		Status:             metav1.ConditionUnknown,
+		Reason:             toolkitv1alpha1.ReasonSuspended,
+		Message:            suspendedMessage,
+		ObservedGeneration: task.Generation,
+	})
+	if err := r.patchStatus(ctx, task, base); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating suspended status: %w", err)
+	}
+	r.Recorder.Eventf(task, nil, "Normal", "Suspended", "Suspend", "Task suspended")
+	log.Info("task suspended")
+	return ctrl.Result{}, nil
+}
+
+// resume returns a suspended task to Pending once the claim released by
+// suspend is gone, so the normal lifecycle claims a new sandbox.
+func (r *AgentTaskReconciler) resume(ctx context.Context, task *toolkitv1alpha1.AgentTask, claim *sandboxextv1alpha1.SandboxClaim) (ctrl.Result, error) {
+	if claim != nil {
+		logf.FromContext(ctx).V(1).Info("waiting for released SandboxClaim to be deleted", "claim", claim.Name)
+		return ctrl.Result{RequeueAfter: 2 * time.Second}, nil
+	}
+
+	base := task.DeepCopy()
+	setCondition(task, metav1.Condition{
+		Type:               toolkitv1alpha1.ConditionSucceeded,
+		Status:             metav1.ConditionUnknown,
+		Reason:             toolkitv1alpha1.ReasonPending,
+		Message:            pendingMessage,
+		ObservedGeneration: task.Generation,
+	})
+	if err := r.patchStatus(ctx, task, base); err != nil {
+		return ctrl.Result{}, fmt.Errorf("updating resumed status: %w", err)
+	}
+	r.Recorder.Eventf(task, nil, "Normal", "Resumed", "Resume", "Task resumed, waiting for sandbox creation")
+	logf.FromContext(ctx).Info("task resumed")
+	return ctrl.Result{RequeueAfter: time.Second}, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+func reconcileTask(t *testing.T, r *AgentTaskReconciler, task *toolkitv1alpha1.AgentTask) (ctrl.Result, *toolkitv1alpha1.AgentTask) {
+	t.Helper()
+	key := client.ObjectKeyFromObject(task)
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	var got toolkitv1alpha1.AgentTask
+	require.NoError(t, r.Get(context.Background(), key, &got))
+	return result, &got
+}
+
+func TestReconcile_Suspended(t *testing.T) {
+	task := dependentTask("task-suspended")
+	task.Spec.Suspend = true
+	r := newDependencyReconciler(t, task)
+
+	result, got := reconcileTask(t, r, task)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Equal(t, toolkitv1alpha1.ReasonSuspended, meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded).Reason)
+
+	var claim sandboxextv1alpha1.SandboxClaim
+	err := r.Get(context.Background(), client.ObjectKeyFromObject(task), &claim)
+	assert.True(t, apierrors.IsNotFound(err), "no claim is created while suspended")
+}
+
+func TestReconcile_SuspendReleasesIdleClaim(t *testing.T) {
+	task := dependentTask("task-idle")
+	task.Spec.Suspend = true
+	task.Status.SandboxClaimName = task.Name
+	claim, err := buildSandboxClaim(task, sandboxConfig{Scheme: testScheme(), Now: admissionBase})
+	require.NoError(t, err)
+	r := newDependencyReconciler(t, task, claim)
+
+	_, got := reconcileTask(t, r, task)
+	assert.Empty(t, got.Status.SandboxClaimName)
+	assert.Equal(t, toolkitv1alpha1.ReasonSuspended, meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded).Reason)
+
+	err = r.Get(context.Background(), client.ObjectKeyFromObject(claim), &sandboxextv1alpha1.SandboxClaim{})
+	assert.True(t, apierrors.IsNotFound(err), "idle claim is deleted")
+}
+
+func TestReconcile_SuspendKeepsRunningTask(t *testing.T) {
+	task := dependencyTask("task-running", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning)
+	task.Spec.Runner.SandboxTemplateName = "default"
+	task.Spec.Suspend = true
+	task.Status.SandboxClaimName = task.Name
+	claim, err := buildSandboxClaim(task, sandboxConfig{Scheme: testScheme(), Now: admissionBase})
+	require.NoError(t, err)
+	r := newDependencyReconciler(t, task, claim)
+
+	_, got := reconcileTask(t, r, task)
+	assert.Equal(t, task.Name, got.Status.SandboxClaimName)
+	assert.Equal(t, toolkitv1alpha1.ReasonRunning, meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded).Reason)
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(claim), &sandboxextv1alpha1.SandboxClaim{}))
+}
+
+func TestReconcile_Resume(t *testing.T) {
+	task := dependencyTask("task-resumed", metav1.ConditionUnknown, toolkitv1alpha1.ReasonSuspended)
+	task.Spec.Runner.SandboxTemplateName = "default"
+	r := newDependencyReconciler(t, task)
+
+	_, got := reconcileTask(t, r, task)
+	assert.Equal(t, toolkitv1alpha1.ReasonPending, meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded).Reason)
+
+	_, got = reconcileTask(t, r, got)
+	assert.Equal(t, task.Name, got.Status.SandboxClaimName, "a new claim is created after resuming")
+}
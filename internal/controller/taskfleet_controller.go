@@ -0,0 +1,303 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+const (
+	// fleetLabel is set on every AgentTask created by a fleet to the fleet's
+	// name. The API's fleet filter selects on it.
+	fleetLabel = "shepherd.io/fleet"
+
+	// maxFleetRepos caps how many repositories a fleet runs in, matching the
+	// MaxItems of spec.repos.
+	maxFleetRepos = 500
+)
+
+// RepoSearcher resolves a TaskFleet's spec.repoQuery to repository URLs.
+type RepoSearcher interface {
+	SearchRepos(ctx context.Context, query string, limit int) ([]string, error)
+}
+
+// TaskFleetReconciler reconciles a TaskFleet object
+type TaskFleetReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+	// Repos resolves spec.repoQuery. Nil when the operator has no GitHub
+	// credentials, in which case fleets using a query fail.
+	Repos RepoSearcher
+}
+
+// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=taskfleets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=taskfleets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=taskfleets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=toolkit.shepherd.io,resources=agenttasks,verbs=create
+
+func (r *TaskFleetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// 1. Fetch the TaskFleet
+	var fleet toolkitv1alpha1.TaskFleet
+	if err := r.Get(ctx, req.NamespacedName, &fleet); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	original := fleet.Status.DeepCopy()
+	fleet.Status.ObservedGeneration = fleet.Generation
+
+	// 2. Resolve the repositories once. A query is not re-run on later
+	// reconciles so the set of child tasks stays stable.
+	if len(fleet.Status.Repos) == 0 {
+		if isFleetFailed(&fleet) {
+			return ctrl.Result{}, nil
+		}
+		repos, permanent, err := r.resolveRepos(ctx, &fleet)
+		if err != nil {
+			r.Recorder.Eventf(&fleet, nil, "Warning", "RepoQueryFailed", "Reconcile", "Resolving repoQuery: %v", err)
+			if !permanent {
+				// Search errors are usually transient (rate limits,
+				// outages), so retry with backoff.
+				setFleetCondition(&fleet, metav1.ConditionUnknown, toolkitv1alpha1.ReasonPending, fmt.Sprintf("Resolving repoQuery: %v", err))
+				if statusErr := r.updateFleetStatus(ctx, &fleet, original); statusErr != nil {
+					return ctrl.Result{}, statusErr
+				}
+				return ctrl.Result{}, fmt.Errorf("resolving repoQuery: %w", err)
+			}
+			setFleetCondition(&fleet, metav1.ConditionFalse, toolkitv1alpha1.ReasonFailed, fmt.Sprintf("Resolving repoQuery: %v", err))
+			return ctrl.Result{}, r.updateFleetStatus(ctx, &fleet, original)
+		}
+		fleet.Status.Repos = repos
+		log.Info("resolved fleet repositories", "fleet", req.NamespacedName, "count", len(repos))
+	}
+
+	// 3. Create missing child tasks
+	var children toolkitv1alpha1.AgentTaskList
+	if err := r.List(ctx, &children,
+		client.InNamespace(fleet.Namespace),
+		client.MatchingLabels{fleetLabel: fleet.Name},
+	); err != nil {
+		return ctrl.Result{}, fmt.Errorf("listing fleet tasks: %w", err)
+	}
+	byName := make(map[string]*toolkitv1alpha1.AgentTask, len(children.Items))
+	for i := range children.Items {
+		if metav1.IsControlledBy(&children.Items[i], &fleet) {
+			byName[children.Items[i].Name] = &children.Items[i]
+		}
+	}
+
+	created := 0
+	for _, repoURL := range fleet.Status.Repos {
+		name := fleetTaskName(fleet.Name, repoURL)
+		if _, ok := byName[name]; ok {
+			continue
+		}
+		task, err := r.buildFleetTask(&fleet, name, repoURL)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, task); err != nil {
+			if !errors.IsAlreadyExists(err) {
+				return ctrl.Result{}, fmt.Errorf("creating fleet task for %s: %w", repoURL, err)
+			}
+		} else {
+			created++
+		}
+		byName[name] = task
+	}
+	if created > 0 {
+		r.Recorder.Eventf(&fleet, nil, "Normal", "TasksCreated", "Reconcile", "Created %d tasks", created)
+		log.Info("created fleet tasks", "fleet", req.NamespacedName, "count", created)
+	}
+
+	// 4. Aggregate the results of the child tasks
+	aggregateFleetStatus(&fleet, byName)
+	return ctrl.Result{}, r.updateFleetStatus(ctx, &fleet, original)
+}
+
+// resolveRepos returns the repository URLs from spec.repos or by running
+// spec.repoQuery. permanent reports whether a failure will not go away by
+// retrying.
+func (r *TaskFleetReconciler) resolveRepos(ctx context.Context, fleet *toolkitv1alpha1.TaskFleet) (repos []string, permanent bool, err error) {
+	if len(fleet.Spec.Repos) > 0 {
+		seen := make(map[string]bool, len(fleet.Spec.Repos))
+		for _, repo := range fleet.Spec.Repos {
+			if !seen[repo.URL] {
+				seen[repo.URL] = true
+				repos = append(repos, repo.URL)
+			}
+		}
+		return repos, false, nil
+	}
+
+	if r.Repos == nil {
+		return nil, true, fmt.Errorf("operator has no GitHub credentials configured")
+	}
+	repos, err = r.Repos.SearchRepos(ctx, fleet.Spec.RepoQuery, maxFleetRepos)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(repos) == 0 {
+		return nil, true, fmt.Errorf("query %q matched no repositories", fleet.Spec.RepoQuery)
+	}
+	return repos, false, nil
+}
+
+// buildFleetTask creates the child AgentTask for one repository.
+func (r *TaskFleetReconciler) buildFleetTask(fleet *toolkitv1alpha1.TaskFleet, name, repoURL string) (*toolkitv1alpha1.AgentTask, error) {
+	repo := toolkitv1alpha1.RepoSpec{URL: repoURL}
+	for _, s := range fleet.Spec.Repos {
+		if s.URL == repoURL {
+			repo = s
+			break
+		}
+	}
+
+	task := fleet.Spec.Task
+	task.SourceType = "fleet"
+	task.SourceID = fleet.Name
+
+	labels := map[string]string{
+		fleetLabel:                fleet.Name,
+		"shepherd.io/source-type": "fleet",
+		"shepherd.io/source-id":   fleet.Name,
+	}
+	if l := toolkitv1alpha1.RepoLabelValue(repoURL); l != "" {
+		labels[toolkitv1alpha1.RepoLabel] = l
+	}
+
+	child := &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fleet.Namespace,
+			Labels:    labels,
+		},
+		Spec: toolkitv1alpha1.AgentTaskSpec{
+			Repo:     repo,
+			Task:     task,
+			Callback: fleet.Spec.Callback,
+			Runner:   *fleet.Spec.Runner.DeepCopy(),
+			Priority: fleet.Spec.Priority,
+		},
+	}
+	if err := controllerutil.SetControllerReference(fleet, child, r.Scheme); err != nil {
+		return nil, fmt.Errorf("setting owner reference: %w", err)
+	}
+	return child, nil
+}
+
+func (r *TaskFleetReconciler) updateFleetStatus(ctx context.Context, fleet *toolkitv1alpha1.TaskFleet, original *toolkitv1alpha1.TaskFleetStatus) error {
+	if equality.Semantic.DeepEqual(&fleet.Status, original) {
+		return nil
+	}
+	base := fleet.DeepCopy()
+	base.Status = *original
+	if err := r.Status().Patch(ctx, fleet, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("updating fleet status: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TaskFleetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&toolkitv1alpha1.TaskFleet{}).
+		Owns(&toolkitv1alpha1.AgentTask{}).
+		Complete(r)
+}
+
+// aggregateFleetStatus fills in the per-task summaries, the counts and the
+// fleet's Succeeded condition from the child tasks.
+func aggregateFleetStatus(fleet *toolkitv1alpha1.TaskFleet, children map[string]*toolkitv1alpha1.AgentTask) {
+	st := &fleet.Status
+	st.Tasks = make([]toolkitv1alpha1.FleetTaskStatus, 0, len(st.Repos))
+	st.Total, st.Pending, st.Running, st.Succeeded, st.Failed = int32(len(st.Repos)), 0, 0, 0, 0
+
+	for _, repoURL := range st.Repos {
+		name := fleetTaskName(fleet.Name, repoURL)
+		summary := toolkitv1alpha1.FleetTaskStatus{Name: name, RepoURL: repoURL, Phase: toolkitv1alpha1.ReasonPending}
+		if task, ok := children[name]; ok {
+			if cond := meta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded); cond != nil {
+				summary.Phase = cond.Reason
+			}
+			summary.PRURL = task.Status.Result.PRURL
+			summary.Error = task.Status.Result.Error
+		}
+		st.Tasks = append(st.Tasks, summary)
+
+		switch summary.Phase {
+		case toolkitv1alpha1.ReasonRunning:
+			st.Running++
+		case toolkitv1alpha1.ReasonSucceeded:
+			st.Succeeded++
+		case toolkitv1alpha1.ReasonFailed, toolkitv1alpha1.ReasonTimedOut, toolkitv1alpha1.ReasonCancelled:
+			st.Failed++
+		default:
+			st.Pending++
+		}
+	}
+
+	switch {
+	case st.Succeeded+st.Failed < st.Total:
+		setFleetCondition(fleet, metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning,
+			fmt.Sprintf("%d of %d tasks finished", st.Succeeded+st.Failed, st.Total))
+	case st.Failed > 0:
+		setFleetCondition(fleet, metav1.ConditionFalse, toolkitv1alpha1.ReasonFailed,
+			fmt.Sprintf("%d of %d tasks did not succeed", st.Failed, st.Total))
+	default:
+		setFleetCondition(fleet, metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded,
+			fmt.Sprintf("All %d tasks succeeded", st.Total))
+	}
+}
+
+func setFleetCondition(fleet *toolkitv1alpha1.TaskFleet, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&fleet.Status.Conditions, metav1.Condition{
+		Type:               toolkitv1alpha1.ConditionSucceeded,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: fleet.Generation,
+	})
+}
+
+func isFleetFailed(fleet *toolkitv1alpha1.TaskFleet) bool {
+	return meta.IsStatusConditionFalse(fleet.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+}
+
+// fleetTaskName derives a stable child task name from the fleet name and
+// repository URL, so a repeated reconcile finds the task it created before.
+func fleetTaskName(fleetName, repoURL string) string {
+	sum := sha256.Sum256([]byte(repoURL))
+	return fleetName + "-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+type fakeRepoSearcher struct {
+	repos []string
+	err   error
+	calls int
+}
+
+func (f *fakeRepoSearcher) SearchRepos(_ context.Context, _ string, _ int) ([]string, error) {
+	f.calls++
+	return f.repos, f.err
+}
+
+func testFleet(repos ...string) *toolkitv1alpha1.TaskFleet {
+	fleet := &toolkitv1alpha1.TaskFleet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bump-go",
+			Namespace: "default",
+			UID:       "fleet-uid",
+		},
+		Spec: toolkitv1alpha1.TaskFleetSpec{
+			Task:     toolkitv1alpha1.TaskSpec{Description: "Bump Go to 1.26"},
+			Callback: toolkitv1alpha1.CallbackSpec{URL: "http://adapter/callback"},
+			Runner:   toolkitv1alpha1.RunnerSpec{SandboxTemplateName: "default"},
+			Priority: 5,
+		},
+	}
+	for _, r := range repos {
+		fleet.Spec.Repos = append(fleet.Spec.Repos, toolkitv1alpha1.RepoSpec{URL: r, Ref: "main"})
+	}
+	return fleet
+}
+
+func newFleetReconciler(t *testing.T, searcher RepoSearcher, objs ...client.Object) *TaskFleetReconciler {
+	t.Helper()
+	s := runtime.NewScheme()
+	require.NoError(t, toolkitv1alpha1.AddToScheme(s))
+	return &TaskFleetReconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(objs...).
+			WithStatusSubresource(&toolkitv1alpha1.TaskFleet{}, &toolkitv1alpha1.AgentTask{}).
+			Build(),
+		Scheme:   s,
+		Recorder: events.NewFakeRecorder(10),
+		Repos:    searcher,
+	}
+}
+
+func reconcileFleet(t *testing.T, r *TaskFleetReconciler) (*toolkitv1alpha1.TaskFleet, error) {
+	t.Helper()
+	key := client.ObjectKey{Namespace: "default", Name: "bump-go"}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+
+	var fleet toolkitv1alpha1.TaskFleet
+	require.NoError(t, r.Get(context.Background(), key, &fleet))
+	return &fleet, err
+}
+
+// fleetChild returns a child task of fleet for repoURL in the given state.
+func fleetChild(t *testing.T, s *runtime.Scheme, fleet *toolkitv1alpha1.TaskFleet, repoURL string, status metav1.ConditionStatus, reason string) *toolkitv1alpha1.AgentTask {
+	t.Helper()
+	task := dependencyTask(fleetTaskName(fleet.Name, repoURL), status, reason)
+	task.Labels = map[string]string{fleetLabel: fleet.Name}
+	task.Spec.Repo.URL = repoURL
+	require.NoError(t, controllerutil.SetControllerReference(fleet, task, s))
+	return task
+}
+
+func TestFleetReconcile_CreatesChildTasks(t *testing.T) {
+	repos := []string{"https://github.com/acme/api", "https://github.com/acme/web.git"}
+	r := newFleetReconciler(t, nil, testFleet(repos...))
+
+	fleet, err := reconcileFleet(t, r)
+	require.NoError(t, err)
+
+	assert.Equal(t, repos, fleet.Status.Repos)
+	for _, repoURL := range repos {
+		var task toolkitv1alpha1.AgentTask
+		key := client.ObjectKey{Namespace: "default", Name: fleetTaskName("bump-go", repoURL)}
+		require.NoError(t, r.Get(context.Background(), key, &task))
+		assert.True(t, metav1.IsControlledBy(&task, fleet))
+		assert.Equal(t, repoURL, task.Spec.Repo.URL)
+		assert.Equal(t, "main", task.Spec.Repo.Ref)
+		assert.Equal(t, "Bump Go to 1.26", task.Spec.Task.Description)
+		assert.Equal(t, "fleet", task.Spec.Task.SourceType)
+		assert.Equal(t, "bump-go", task.Spec.Task.SourceID)
+		assert.Equal(t, int32(5), task.Spec.Priority)
+		assert.Equal(t, "bump-go", task.Labels[fleetLabel])
+		assert.Equal(t, "fleet", task.Labels["shepherd.io/source-type"])
+	}
+
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, r.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: fleetTaskName("bump-go", repos[1])}, &task))
+	assert.Equal(t, "acme-web", task.Labels["shepherd.io/repo"])
+
+	assert.Equal(t, int32(2), fleet.Status.Total)
+	assert.Equal(t, int32(2), fleet.Status.Pending)
+	cond := meta.FindStatusCondition(fleet.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionUnknown, cond.Status)
+	assert.Equal(t, toolkitv1alpha1.ReasonRunning, cond.Reason)
+
+	// Reconciling again does not create duplicates
+	_, err = reconcileFleet(t, r)
+	require.NoError(t, err)
+	var tasks toolkitv1alpha1.AgentTaskList
+	require.NoError(t, r.List(context.Background(), &tasks))
+	assert.Len(t, tasks.Items, 2)
+}
+
+func TestFleetReconcile_AggregatesResults(t *testing.T) {
+	tests := []struct {
+		name       string
+		reasons    []string
+		wantStatus metav1.ConditionStatus
+		wantReason string
+		wantMsg    string
+	}{
+		{
+			name:       "in progress",
+			reasons:    []string{toolkitv1alpha1.ReasonSucceeded, toolkitv1alpha1.ReasonRunning, toolkitv1alpha1.ReasonPending},
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: toolkitv1alpha1.ReasonRunning,
+			wantMsg:    "1 of 3 tasks finished",
+		},
+		{
+			name:       "all succeeded",
+			reasons:    []string{toolkitv1alpha1.ReasonSucceeded, toolkitv1alpha1.ReasonSucceeded, toolkitv1alpha1.ReasonSucceeded},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: toolkitv1alpha1.ReasonSucceeded,
+			wantMsg:    "All 3 tasks succeeded",
+		},
+		{
+			name:       "some failed",
+			reasons:    []string{toolkitv1alpha1.ReasonSucceeded, toolkitv1alpha1.ReasonTimedOut, toolkitv1alpha1.ReasonFailed},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: toolkitv1alpha1.ReasonFailed,
+			wantMsg:    "2 of 3 tasks did not succeed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var repos []string
+			for i := range tt.reasons {
+				repos = append(repos, fmt.Sprintf("https://github.com/acme/repo-%d", i))
+			}
+			fleet := testFleet(repos...)
+			s := runtime.NewScheme()
+			require.NoError(t, toolkitv1alpha1.AddToScheme(s))
+
+			objs := []client.Object{fleet}
+			for i, reason := range tt.reasons {
+				status := metav1.ConditionUnknown
+				switch reason {
+				case toolkitv1alpha1.ReasonSucceeded:
+					status = metav1.ConditionTrue
+				case toolkitv1alpha1.ReasonFailed, toolkitv1alpha1.ReasonTimedOut:
+					status = metav1.ConditionFalse
+				}
+				child := fleetChild(t, s, fleet, repos[i], status, reason)
+				if reason == toolkitv1alpha1.ReasonSucceeded {
+					child.Status.Result.PRURL = repos[i] + "/pull/1"
+				}
+				objs = append(objs, child)
+			}
+			r := newFleetReconciler(t, nil, objs...)
+
+			got, err := reconcileFleet(t, r)
+			require.NoError(t, err)
+
+			cond := meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.wantStatus, cond.Status)
+			assert.Equal(t, tt.wantReason, cond.Reason)
+			assert.Equal(t, tt.wantMsg, cond.Message)
+
+			require.Len(t, got.Status.Tasks, len(repos))
+			for i, summary := range got.Status.Tasks {
+				assert.Equal(t, repos[i], summary.RepoURL)
+				assert.Equal(t, tt.reasons[i], summary.Phase)
+			}
+			assert.Equal(t, "https://github.com/acme/repo-0/pull/1", got.Status.Tasks[0].PRURL)
+		})
+	}
+}
+
+func TestFleetReconcile_RepoQuery(t *testing.T) {
+	t.Run("resolves once", func(t *testing.T) {
+		fleet := testFleet()
+		fleet.Spec.RepoQuery = "org:acme topic:go"
+		searcher := &fakeRepoSearcher{repos: []string{"https://github.com/acme/a", "https://github.com/acme/b"}}
+		r := newFleetReconciler(t, searcher, fleet)
+
+		got, err := reconcileFleet(t, r)
+		require.NoError(t, err)
+		assert.Equal(t, searcher.repos, got.Status.Repos)
+		assert.Equal(t, int32(2), got.Status.Total)
+
+		_, err = reconcileFleet(t, r)
+		require.NoError(t, err)
+		assert.Equal(t, 1, searcher.calls)
+	})
+
+	t.Run("no matches fails the fleet", func(t *testing.T) {
+		fleet := testFleet()
+		fleet.Spec.RepoQuery = "org:acme topic:cobol"
+		r := newFleetReconciler(t, &fakeRepoSearcher{}, fleet)
+
+		got, err := reconcileFleet(t, r)
+		require.NoError(t, err)
+		cond := meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Contains(t, cond.Message, "matched no repositories")
+	})
+
+	t.Run("no GitHub credentials fails the fleet", func(t *testing.T) {
+		fleet := testFleet()
+		fleet.Spec.RepoQuery = "org:acme"
+		r := newFleetReconciler(t, nil, fleet)
+
+		got, err := reconcileFleet(t, r)
+		require.NoError(t, err)
+		assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded))
+	})
+
+	t.Run("search error is retried", func(t *testing.T) {
+		fleet := testFleet()
+		fleet.Spec.RepoQuery = "org:acme"
+		searcher := &fakeRepoSearcher{err: fmt.Errorf("rate limited")}
+		r := newFleetReconciler(t, searcher, fleet)
+
+		got, err := reconcileFleet(t, r)
+		require.Error(t, err)
+		cond := meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionUnknown, cond.Status)
+		assert.Contains(t, cond.Message, "rate limited")
+
+		searcher.err = nil
+		searcher.repos = []string{"https://github.com/acme/a"}
+		got, err = reconcileFleet(t, r)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), got.Status.Total)
+	})
+}
+
+func TestFleetTaskName(t *testing.T) {
+	a := fleetTaskName("bump-go", "https://github.com/acme/a")
+	assert.Equal(t, a, fleetTaskName("bump-go", "https://github.com/acme/a"))
+	assert.NotEqual(t, a, fleetTaskName("bump-go", "https://github.com/acme/b"))
+	assert.Len(t, a, len("bump-go-")+8)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+// extendClaim moves the shutdown time of a task's SandboxClaim out to cover
+// the timeout extensions granted through the API, and reports whether it
+// did. The shutdown time keeps counting from the claim's creation.
+func (r *AgentTaskReconciler) extendClaim(ctx context.Context, task *toolkitv1alpha1.AgentTask, claim *sandboxextv1alpha1.SandboxClaim) (bool, error) {
+	if len(task.Status.TimeoutExtensions) == 0 || claim.Spec.Lifecycle == nil ||
+		claim.Spec.Lifecycle.ShutdownTime == nil {
+		return false, nil
+	}
+	shutdown := deadlineAfter(claim.CreationTimestamp.Time, task.RunnerTimeout())
+	if shutdown.Sub(claim.Spec.Lifecycle.ShutdownTime.Time) <= clockSkewTolerance {
+		return false, nil
+	}
+
+	desired, err := buildSandboxClaim(task, sandboxConfig{
+		Scheme:   r.Scheme,
+		Now:      claim.CreationTimestamp.Time,
+		Template: r.sandboxTemplate(task),
+	})
+	if err != nil {
+		return false, fmt.Errorf("building sandbox claim: %w", err)
+	}
+	if err := r.apply(ctx, desired); err != nil {
+		return false, fmt.Errorf("extending sandbox claim: %w", err)
+	}
+	return true, nil
+}
+
+// timeoutExtended passes updates that extended a task's timeout, which do
+// not bump its generation, so its SandboxClaim is extended right away.
+var timeoutExtended = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldTask, okOld := e.ObjectOld.(*toolkitv1alpha1.AgentTask)
+		newTask, okNew := e.ObjectNew.(*toolkitv1alpha1.AgentTask)
+		return okOld && okNew &&
+			len(newTask.Status.TimeoutExtensions) > len(oldTask.Status.TimeoutExtensions)
+	},
+}
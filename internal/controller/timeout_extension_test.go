@@ -0,0 +1,76 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+)
+
+func TestReconcile_ExtendsClaim(t *testing.T) {
+	task := dependentTask("task-extended")
+	task.Status.SandboxClaimName = task.Name
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	claim, err := buildSandboxClaim(task, sandboxConfig{Scheme: testScheme(), Now: created})
+	require.NoError(t, err)
+	claim.CreationTimestamp = metav1.Time{Time: created}
+	task.Status.TimeoutExtensions = []toolkitv1alpha1.TimeoutExtension{
+		{Duration: metav1.Duration{Duration: 20 * time.Minute}, CreatedAt: metav1.Time{Time: created.Add(time.Minute)}},
+		{Duration: metav1.Duration{Duration: 10 * time.Minute}, CreatedAt: metav1.Time{Time: created.Add(2 * time.Minute)}},
+	}
+
+	r := newDependencyReconciler(t, task, claim)
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(task)}
+
+	result, err := r.Reconcile(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, result.RequeueAfter)
+
+	var got sandboxextv1alpha1.SandboxClaim
+	require.NoError(t, r.Get(context.Background(), req.NamespacedName, &got))
+	want := created.Add(toolkitv1alpha1.DefaultRunnerTimeout + 30*time.Minute)
+	assert.True(t, got.Spec.Lifecycle.ShutdownTime.Time.Equal(want),
+		"shutdown time %s, want %s", got.Spec.Lifecycle.ShutdownTime, want)
+
+	// Once extended, the claim is left alone.
+	extended, err := r.extendClaim(context.Background(), task, &got)
+	require.NoError(t, err)
+	assert.False(t, extended)
+}
+
+func TestTimeoutExtendedPredicate(t *testing.T) {
+	oldTask := dependencyTask("task-1", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning)
+	newTask := oldTask.DeepCopy()
+	assert.False(t, timeoutExtended.Update(event.UpdateEvent{ObjectOld: oldTask, ObjectNew: newTask}))
+
+	newTask.Status.TimeoutExtensions = append(newTask.Status.TimeoutExtensions, toolkitv1alpha1.TimeoutExtension{
+		Duration: metav1.Duration{Duration: time.Minute},
+	})
+	assert.True(t, timeoutExtended.Update(event.UpdateEvent{ObjectOld: oldTask, ObjectNew: newTask}))
+}
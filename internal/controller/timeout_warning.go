@@ -0,0 +1,75 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// timeoutWarningAt returns when a running task has used
+// TimeoutWarningPercent of its runner timeout, or false if it gets no
+// warning: warnings are disabled, the task has no start time, or it was
+// already warned.
+func (r *AgentTaskReconciler) timeoutWarningAt(task *toolkitv1alpha1.AgentTask) (time.Time, bool) {
+	if r.TimeoutWarningPercent <= 0 || task.Status.StartTime == nil ||
+		hasCondition(task, toolkitv1alpha1.ConditionTimeoutWarning) {
+		return time.Time{}, false
+	}
+	timeout := task.RunnerTimeout()
+	return task.Status.StartTime.Add(timeout * time.Duration(r.TimeoutWarningPercent) / 100), true
+}
+
+// warnTimeout sets the TimeoutWarning condition of a running task that is
+// past its warning time, which the API server turns into a progress
+// callback. Otherwise it returns how long until the warning is due, or zero
+// if none is.
+func (r *AgentTaskReconciler) warnTimeout(ctx context.Context, task *toolkitv1alpha1.AgentTask) (time.Duration, error) {
+	warnAt, ok := r.timeoutWarningAt(task)
+	if !ok {
+		return 0, nil
+	}
+	now := r.now()
+	if wait := warnAt.Sub(now); wait > 0 {
+		return wait, nil
+	}
+
+	timeout := task.RunnerTimeout()
+	deadline := task.Status.StartTime.Add(timeout)
+	base := task.DeepCopy()
+	setCondition(task, metav1.Condition{
+		Type:   toolkitv1alpha1.ConditionTimeoutWarning,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonDeadlineApproaching,
+		Message: fmt.Sprintf("Task has used %d%% of its %s timeout and times out at %s",
+			r.TimeoutWarningPercent, timeout, deadline.UTC().Format(time.RFC3339)),
+		ObservedGeneration: task.Generation,
+	})
+	if err := r.patchStatus(ctx, task, base); err != nil {
+		return 0, fmt.Errorf("setting timeout warning: %w", err)
+	}
+	r.Recorder.Eventf(task, nil, "Warning", "TimeoutApproaching", "Reconcile",
+		"Task times out at %s", deadline.UTC().Format(time.RFC3339))
+	logf.FromContext(ctx).Info("task is about to time out", "deadline", deadline)
+	return 0, nil
+}
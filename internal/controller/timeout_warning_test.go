@@ -0,0 +1,83 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestWarnTimeout(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		percent int
+		started time.Duration // how long ago the 30m task started
+		warned  bool
+		wait    time.Duration
+		warning bool
+	}{
+		{"disabled", 0, 29 * time.Minute, false, 0, false},
+		{"warning not due yet", 80, 10 * time.Minute, false, 14 * time.Minute, false},
+		{"warning due", 80, 24 * time.Minute, false, 0, true},
+		{"already warned", 80, 29 * time.Minute, true, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := dependencyTask("task-running", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning)
+			task.Spec.Runner.Timeout = metav1.Duration{Duration: 30 * time.Minute}
+			task.Status.StartTime = &metav1.Time{Time: now.Add(-tt.started)}
+			if tt.warned {
+				setCondition(task, metav1.Condition{
+					Type:   toolkitv1alpha1.ConditionTimeoutWarning,
+					Status: metav1.ConditionTrue,
+					Reason: toolkitv1alpha1.ReasonWarningSent,
+				})
+			}
+			r := newDependencyReconciler(t, task)
+			r.TimeoutWarningPercent = tt.percent
+			r.Clock = func() time.Time { return now }
+
+			wait, err := r.warnTimeout(context.Background(), task)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wait, wait)
+
+			var got toolkitv1alpha1.AgentTask
+			require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(task), &got))
+			cond := meta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionTimeoutWarning)
+			if !tt.warning {
+				if cond != nil {
+					assert.Equal(t, toolkitv1alpha1.ReasonWarningSent, cond.Reason, "existing warning left alone")
+				}
+				return
+			}
+			require.NotNil(t, cond)
+			assert.Equal(t, toolkitv1alpha1.ReasonDeadlineApproaching, cond.Reason)
+			assert.Equal(t, "Task has used 80% of its 30m0s timeout and times out at 2026-03-01T12:06:00Z", cond.Message)
+		})
+	}
+}
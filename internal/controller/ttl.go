@@ -0,0 +1,57 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// finishedTTL returns how long task is kept after it finished and whether
+// it is deleted at all. spec.ttlAfterFinished overrides the operator default.
+func (r *AgentTaskReconciler) finishedTTL(task *toolkitv1alpha1.AgentTask) (time.Duration, bool) {
+	if task.Spec.TTLAfterFinished != nil {
+		return max(task.Spec.TTLAfterFinished.Duration, 0), true
+	}
+	return r.TTLAfterFinished, r.TTLAfterFinished > 0
+}
+
+// expireFinished deletes a terminal task whose TTL has passed, or requeues
+// it for when it will. Deletion runs the finalizer, so a pending callback is
+// still sent first.
+func (r *AgentTaskReconciler) expireFinished(ctx context.Context, task *toolkitv1alpha1.AgentTask) (ctrl.Result, error) {
+	ttl, ok := r.finishedTTL(task)
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+	if remaining := finishedAt(task).Add(ttl).Sub(r.now()); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	if err := r.Delete(ctx, task); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("deleting finished task: %w", err)
+	}
+	logf.FromContext(ctx).Info("deleted finished task", "ttlAfterFinished", ttl)
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestReconcile_TTLAfterFinished(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ttl := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	tests := []struct {
+		name       string
+		defaultTTL time.Duration
+		specTTL    *metav1.Duration
+		finished   time.Duration // how long ago the task finished
+		requeue    time.Duration
+		deleted    bool
+	}{
+		{"no TTL keeps the task", 0, nil, 24 * time.Hour, 0, false},
+		{"default TTL not reached", time.Hour, nil, 30 * time.Minute, 30 * time.Minute, false},
+		{"default TTL passed", time.Hour, nil, 2 * time.Hour, 0, true},
+		{"spec TTL overrides default", time.Hour, ttl(24 * time.Hour), 2 * time.Hour, 22 * time.Hour, false},
+		{"zero spec TTL deletes at once", 0, ttl(0), 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := dependencyTask("task-done", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded)
+			task.Spec.TTLAfterFinished = tt.specTTL
+			task.Status.CompletionTime = &metav1.Time{Time: now.Add(-tt.finished)}
+			r := newDependencyReconciler(t, task)
+			r.TTLAfterFinished = tt.defaultTTL
+			r.Clock = func() time.Time { return now }
+
+			key := client.ObjectKeyFromObject(task)
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+			require.NoError(t, err)
+			assert.Equal(t, tt.requeue, result.RequeueAfter)
+
+			err = r.Get(context.Background(), key, &toolkitv1alpha1.AgentTask{})
+			assert.Equal(t, tt.deleted, apierrors.IsNotFound(err), "deleted")
+		})
+	}
+}
+
+func TestReconcile_TTLAfterFinishedKeepsFinalizer(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := dependencyTask("task-done", metav1.ConditionFalse, toolkitv1alpha1.ReasonFailed)
+	task.Finalizers = []string{taskFinalizer}
+	task.Status.CompletionTime = &metav1.Time{Time: now.Add(-2 * time.Hour)}
+	r := newDependencyReconciler(t, task)
+	r.TTLAfterFinished = time.Hour
+	r.Clock = func() time.Time { return now }
+
+	_, got := reconcileTask(t, r, task)
+	assert.False(t, got.DeletionTimestamp.IsZero(), "deletion started")
+	assert.Contains(t, got.Finalizers, taskFinalizer, "finalizer still waits for the callback")
+}
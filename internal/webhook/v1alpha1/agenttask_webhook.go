@@ -0,0 +1,148 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/validate"
+)
+
+// Namespace annotations with the defaults for AgentTasks created in the
+// namespace.
+const (
+	DefaultSandboxTemplateAnnotation = "shepherd.io/default-sandbox-template"
+	DefaultTimeoutAnnotation         = "shepherd.io/default-timeout"
+)
+
+// SetupAgentTaskWebhookWithManager registers the AgentTask defaulting and
+// validating webhooks with the manager.
+func SetupAgentTaskWebhookWithManager(mgr ctrl.Manager, opts validate.Options) error {
+	return ctrl.NewWebhookManagedBy(mgr, &toolkitv1alpha1.AgentTask{}).
+		WithDefaulter(&AgentTaskDefaulter{Reader: mgr.GetAPIReader()}).
+		WithValidator(&AgentTaskValidator{Options: opts}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-toolkit-shepherd-io-v1alpha1-agenttask,mutating=true,failurePolicy=fail,sideEffects=None,groups=toolkit.shepherd.io,resources=agenttasks,verbs=create,versions=v1alpha1,name=magenttask-v1alpha1.shepherd.io,admissionReviewVersions=v1
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get
+
+// AgentTaskDefaulter fills in the fields of new AgentTasks that controllers
+// and list filters rely on: the runner timeout and sandbox template, from
+// the annotations of the task's namespace, and the shepherd.io/repo label.
+type AgentTaskDefaulter struct {
+	// Reader reads namespaces. It is uncached, so the operator only needs
+	// to get namespaces, not watch them.
+	Reader client.Reader
+}
+
+var _ admission.Defaulter[*toolkitv1alpha1.AgentTask] = &AgentTaskDefaulter{}
+
+// Default implements admission.Defaulter.
+func (d *AgentTaskDefaulter) Default(ctx context.Context, task *toolkitv1alpha1.AgentTask) error {
+	if l := toolkitv1alpha1.RepoLabelValue(task.Spec.Repo.URL); l != "" {
+		if task.Labels == nil {
+			task.Labels = map[string]string{}
+		}
+		task.Labels[toolkitv1alpha1.RepoLabel] = l
+	}
+
+	runner := &task.Spec.Runner
+	if runner.Timeout.Duration != 0 && runner.SandboxTemplateName != "" {
+		return nil
+	}
+	namespace := task.Namespace
+	if namespace == "" {
+		req, err := admission.RequestFromContext(ctx)
+		if err != nil {
+			return err
+		}
+		namespace = req.Namespace
+	}
+	var ns corev1.Namespace
+	if err := d.Reader.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		return fmt.Errorf("reading namespace %s: %w", namespace, err)
+	}
+
+	if runner.SandboxTemplateName == "" {
+		runner.SandboxTemplateName = ns.Annotations[DefaultSandboxTemplateAnnotation]
+	}
+	if runner.Timeout.Duration == 0 {
+		timeout := toolkitv1alpha1.DefaultRunnerTimeout
+		if s, ok := ns.Annotations[DefaultTimeoutAnnotation]; ok {
+			parsed, err := time.ParseDuration(s)
+			if err == nil {
+				err = validate.Timeout(parsed)
+			}
+			if err != nil {
+				return fmt.Errorf("namespace %s: invalid %s annotation %q: %w", namespace, DefaultTimeoutAnnotation, s, err)
+			}
+			timeout = parsed
+		}
+		runner.Timeout = metav1.Duration{Duration: timeout}
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-toolkit-shepherd-io-v1alpha1-agenttask,mutating=false,failurePolicy=fail,sideEffects=None,groups=toolkit.shepherd.io,resources=agenttasks,verbs=create;update,versions=v1alpha1,name=vagenttask-v1alpha1.shepherd.io,admissionReviewVersions=v1
+
+// AgentTaskValidator rejects AgentTasks that the API server would refuse to
+// create, so tasks applied with kubectl get the same checks.
+type AgentTaskValidator struct {
+	Options validate.Options
+}
+
+var _ admission.Validator[*toolkitv1alpha1.AgentTask] = &AgentTaskValidator{}
+
+// ValidateCreate implements admission.Validator.
+func (v *AgentTaskValidator) ValidateCreate(_ context.Context, task *toolkitv1alpha1.AgentTask) (admission.Warnings, error) {
+	return nil, v.validate(task)
+}
+
+// ValidateUpdate implements admission.Validator. Only spec changes are
+// checked: a task admitted under an older configuration must stay
+// updatable, or the operator could not update its finalizers and labels.
+func (v *AgentTaskValidator) ValidateUpdate(_ context.Context, oldTask, newTask *toolkitv1alpha1.AgentTask) (admission.Warnings, error) {
+	if equality.Semantic.DeepEqual(oldTask.Spec, newTask.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(newTask)
+}
+
+// ValidateDelete implements admission.Validator.
+func (v *AgentTaskValidator) ValidateDelete(context.Context, *toolkitv1alpha1.AgentTask) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *AgentTaskValidator) validate(task *toolkitv1alpha1.AgentTask) error {
+	errs := v.Options.AgentTask(task)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(toolkitv1alpha1.GroupVersion.WithKind("AgentTask").GroupKind(), task.Name, errs)
+}
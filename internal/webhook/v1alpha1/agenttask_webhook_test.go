@@ -0,0 +1,131 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/validate"
+)
+
+func validTask() *toolkitv1alpha1.AgentTask {
+	return &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "task-1", Namespace: "default"},
+		Spec: toolkitv1alpha1.AgentTaskSpec{
+			Repo:     toolkitv1alpha1.RepoSpec{URL: "https://github.com/acme/app"},
+			Task:     toolkitv1alpha1.TaskSpec{Description: "Fix the bug"},
+			Callback: toolkitv1alpha1.CallbackSpec{URL: "http://shepherd-github.shepherd:8080/callback"},
+			Runner:   toolkitv1alpha1.RunnerSpec{SandboxTemplateName: "default"},
+		},
+	}
+}
+
+func TestAgentTaskValidator_ValidateCreate(t *testing.T) {
+	v := &AgentTaskValidator{Options: validate.Options{AllowedSandboxTemplates: []string{"default", "large"}}}
+
+	_, err := v.ValidateCreate(context.Background(), validTask())
+	require.NoError(t, err)
+
+	task := validTask()
+	task.Spec.Callback.URL = "http://169.254.169.254/latest/meta-data"
+	task.Spec.Runner.SandboxTemplateName = "gpu"
+	task.Spec.Runner.Timeout = metav1.Duration{Duration: 48 * time.Hour}
+	_, err = v.ValidateCreate(context.Background(), task)
+	require.Error(t, err)
+	assert.True(t, apierrors.IsInvalid(err))
+	assert.ErrorContains(t, err, "spec.callback.url")
+	assert.ErrorContains(t, err, "spec.runner.sandboxTemplateName")
+	assert.ErrorContains(t, err, "spec.runner.timeout")
+}
+
+func TestAgentTaskValidator_ValidateUpdate(t *testing.T) {
+	v := &AgentTaskValidator{Options: validate.Options{AllowedSandboxTemplates: []string{"large"}}}
+
+	// A task created before "default" left the allowlist keeps working.
+	oldTask := validTask()
+	newTask := oldTask.DeepCopy()
+	newTask.Finalizers = []string{"shepherd.io/cleanup"}
+	_, err := v.ValidateUpdate(context.Background(), oldTask, newTask)
+	require.NoError(t, err)
+
+	newTask.Spec.Callback.URL = "http://localhost:8080/callback"
+	_, err = v.ValidateUpdate(context.Background(), oldTask, newTask)
+	assert.True(t, apierrors.IsInvalid(err))
+}
+
+func defaulterFor(annotations map[string]string) *AgentTaskDefaulter {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: annotations}}
+	return &AgentTaskDefaulter{Reader: fake.NewClientBuilder().WithObjects(ns).Build()}
+}
+
+func TestAgentTaskDefaulter(t *testing.T) {
+	d := defaulterFor(map[string]string{
+		DefaultSandboxTemplateAnnotation: "large",
+		DefaultTimeoutAnnotation:         "2h",
+	})
+
+	task := validTask()
+	task.Spec.Runner = toolkitv1alpha1.RunnerSpec{}
+	require.NoError(t, d.Default(context.Background(), task))
+	assert.Equal(t, "large", task.Spec.Runner.SandboxTemplateName)
+	assert.Equal(t, 2*time.Hour, task.Spec.Runner.Timeout.Duration)
+	assert.Equal(t, "acme-app", task.Labels[toolkitv1alpha1.RepoLabel])
+
+	// Fields set on the task win over the namespace defaults.
+	task = validTask()
+	task.Spec.Runner.Timeout = metav1.Duration{Duration: 10 * time.Minute}
+	require.NoError(t, d.Default(context.Background(), task))
+	assert.Equal(t, "default", task.Spec.Runner.SandboxTemplateName)
+	assert.Equal(t, 10*time.Minute, task.Spec.Runner.Timeout.Duration)
+}
+
+func TestAgentTaskDefaulter_NoAnnotations(t *testing.T) {
+	d := defaulterFor(nil)
+
+	// kubectl create may leave the namespace to the request.
+	task := validTask()
+	task.Namespace = ""
+	task.Spec.Runner.SandboxTemplateName = ""
+	task.Labels = map[string]string{toolkitv1alpha1.RepoLabel: "stale"}
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{Namespace: "default"},
+	})
+	require.NoError(t, d.Default(ctx, task))
+	assert.Empty(t, task.Spec.Runner.SandboxTemplateName, "left for schema validation to reject")
+	assert.Equal(t, toolkitv1alpha1.DefaultRunnerTimeout, task.Spec.Runner.Timeout.Duration)
+	assert.Equal(t, "acme-app", task.Labels[toolkitv1alpha1.RepoLabel])
+}
+
+func TestAgentTaskDefaulter_InvalidTimeoutAnnotation(t *testing.T) {
+	for _, value := range []string{"soon", "72h"} {
+		d := defaulterFor(map[string]string{DefaultTimeoutAnnotation: value})
+		err := d.Default(context.Background(), validTask())
+		assert.ErrorContains(t, err, "invalid shepherd.io/default-timeout annotation", value)
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/forge"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// TaskMetadata stores the Gitea context needed to post comments when a
+// callback arrives for a task.
+type TaskMetadata struct {
+	Owner       string
+	Repo        string
+	IssueNumber int
+}
+
+// CallbackHandler handles callback notifications from the Shepherd API.
+type CallbackHandler struct {
+	secret       string
+	client       *Client
+	apiClient    *APIClient
+	eventTimeout time.Duration
+	log          logr.Logger
+
+	// secondarySecret is also accepted, so the secret can be rotated
+	// without rejecting callbacks signed with the other one.
+	secondarySecret string
+	// publicKeys verify Ed25519 signatures of callbacks; without them
+	// only HMAC signatures are accepted.
+	publicKeys []ed25519.PublicKey
+
+	// In-memory cache for fast lookup; API fallback handles restarts
+	mu    sync.RWMutex
+	tasks map[string]TaskMetadata
+}
+
+// CallbackOption configures optional CallbackHandler behavior.
+type CallbackOption func(*CallbackHandler)
+
+// WithSecondaryCallbackSecret also accepts callbacks signed with secret, for
+// rotating the callback secret.
+func WithSecondaryCallbackSecret(secret string) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.secondarySecret = secret
+	}
+}
+
+// WithCallbackPublicKeys also accepts callbacks with an Ed25519 signature
+// made with the private key of any of keys, so the adapter needs no shared
+// secret.
+func WithCallbackPublicKeys(keys ...ed25519.PublicKey) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.publicKeys = keys
+	}
+}
+
+// NewCallbackHandler creates a new callback handler. Each callback is
+// handled within eventTimeout.
+func NewCallbackHandler(
+	secret string, client *Client, apiClient *APIClient, eventTimeout time.Duration, log logr.Logger,
+	opts ...CallbackOption,
+) *CallbackHandler {
+	h := &CallbackHandler{
+		secret:       secret,
+		client:       client,
+		apiClient:    apiClient,
+		eventTimeout: eventTimeout,
+		log:          log,
+		tasks:        make(map[string]TaskMetadata),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// RegisterTask stores metadata for a task so that callback notifications
+// can be routed back to the correct issue.
+func (h *CallbackHandler) RegisterTask(taskID string, meta TaskMetadata) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tasks[taskID] = meta
+}
+
+// ServeHTTP handles callback requests from the Shepherd API.
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Read body with 1MB limit
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		h.log.Error(err, "failed to read callback body")
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verifySignature(body, r.Header.Values("X-Shepherd-Signature")...) {
+		h.log.Info("callback signature verification failed")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var payload api.CallbackPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		h.log.Error(err, "failed to parse callback payload")
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	h.log.Info("received callback", logging.TaskID, payload.TaskID, logging.CorrelationID, payload.CorrelationID,
+		"event", payload.Event)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), h.eventTimeout)
+	defer cancel()
+	h.handleCallback(ctx, &payload)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySignature verifies the signatures from the API. The API sends one
+// header per secret and key; it passes if any of them was made with either
+// secret or with the key of a public key.
+func (h *CallbackHandler) verifySignature(body []byte, signatures ...string) bool {
+	v := api.CallbackVerifier{Secrets: []string{h.secret, h.secondarySecret}, PublicKeys: h.publicKeys}
+	return v.Verify(body, signatures...)
+}
+
+// resolveTaskMetadata looks up task metadata from cache, falling back to
+// the Shepherd API if not found (e.g., after a restart).
+func (h *CallbackHandler) resolveTaskMetadata(ctx context.Context, taskID string) (TaskMetadata, bool) {
+	h.mu.RLock()
+	meta, ok := h.tasks[taskID]
+	h.mu.RUnlock()
+	if ok {
+		return meta, true
+	}
+
+	task, err := h.apiClient.GetTask(ctx, taskID)
+	if err != nil {
+		h.log.Error(err, "failed to fetch task from API for callback", logging.TaskID, taskID)
+		return TaskMetadata{}, false
+	}
+	meta, err = parseSourceURL(h.client.basePath, task.Task.SourceURL)
+	if err != nil {
+		h.log.Error(err, "failed to parse sourceURL from task", logging.TaskID, taskID, "sourceURL", task.Task.SourceURL)
+		return TaskMetadata{}, false
+	}
+
+	h.RegisterTask(taskID, meta)
+	h.log.Info("recovered task metadata from API",
+		logging.TaskID, taskID, "owner", meta.Owner, "repo", meta.Repo, "issue", meta.IssueNumber)
+	return meta, true
+}
+
+// parseSourceURL extracts owner, repo, and issue number from a Gitea issue
+// or pull request URL: https://gitea.example.com/{owner}/{repo}/issues/{n}
+// or .../pulls/{n}, with basePath between host and owner on instances
+// served below a path.
+func parseSourceURL(basePath, sourceURL string) (TaskMetadata, error) {
+	if sourceURL == "" {
+		return TaskMetadata{}, fmt.Errorf("empty sourceURL")
+	}
+	parts, err := forge.SplitPath(basePath, sourceURL)
+	if err != nil {
+		return TaskMetadata{}, fmt.Errorf("invalid sourceURL: %w", err)
+	}
+	if len(parts) < 4 || (parts[2] != "issues" && parts[2] != "pulls") {
+		return TaskMetadata{}, fmt.Errorf("unexpected sourceURL format: %s", sourceURL)
+	}
+	issueNumber, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return TaskMetadata{}, fmt.Errorf("invalid issue number in sourceURL: %w", err)
+	}
+	return TaskMetadata{
+		Owner:       parts[0],
+		Repo:        parts[1],
+		IssueNumber: issueNumber,
+	}, nil
+}
+
+// handleCallback posts a comment on the task's issue for terminal events.
+func (h *CallbackHandler) handleCallback(ctx context.Context, payload *api.CallbackPayload) {
+	var comment string
+	switch payload.Event {
+	case api.EventCompleted:
+		prURL, _ := payload.Details["pr_url"].(string)
+		summary, _ := payload.Details["summary"].(string)
+		if prURL != "" {
+			comment = formatCompleted(prURL, summary)
+		} else {
+			comment = formatCompletedWithoutPR(summary)
+		}
+	case api.EventFailed:
+		comment = formatFailed(payload.Message)
+	case api.EventCancelled:
+		comment = formatCancelled(payload.Message)
+	case api.EventStarted, api.EventProgress:
+		h.log.V(1).Info("ignoring intermediate event", "event", payload.Event)
+		return
+	default:
+		h.log.Info("unknown callback event type", "event", payload.Event)
+		return
+	}
+
+	meta, ok := h.resolveTaskMetadata(ctx, payload.TaskID)
+	if !ok {
+		h.log.Info("unable to resolve task metadata, cannot post comment", logging.TaskID, payload.TaskID)
+		return
+	}
+	h.mu.Lock()
+	delete(h.tasks, payload.TaskID)
+	h.mu.Unlock()
+
+	comment = withTaskMarker(comment, payload.TaskID, payload.CorrelationID)
+	if err := h.client.PostComment(ctx, meta.Owner, meta.Repo, meta.IssueNumber, comment); err != nil {
+		h.log.Error(err, "failed to post callback comment", logging.TaskID, payload.TaskID, "event", payload.Event)
+	}
+}
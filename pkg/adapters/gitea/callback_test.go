@@ -0,0 +1,193 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func callbackRequest(t *testing.T, secret string, payload api.CallbackPayload) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(body))
+	req.Header.Set("X-Shepherd-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func newTestCallbackHandler(t *testing.T, handler http.Handler, opts ...CallbackOption) *CallbackHandler {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewCallbackHandler("secret", newClient(srv.Client(), srv.URL, "token"), NewAPIClient(srv.URL),
+		time.Minute, ctrl.Log.WithName("test"), opts...)
+}
+
+func TestCallbackHandler_PostsComments(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload api.CallbackPayload
+		want    string
+	}{
+		{
+			name: "completed with PR",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventCompleted,
+				Details: map[string]any{"pr_url": "https://gitea.example.com/org/repo/pulls/7"}},
+			want: "Pull Request: https://gitea.example.com/org/repo/pulls/7",
+		},
+		{
+			name:    "failed",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed, Message: "tests did not pass"},
+			want:    "Error: tests did not pass",
+		},
+		{
+			name:    "cancelled",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventCancelled, Message: "cancelled by alice"},
+			want:    "cancelled by alice",
+		},
+		{
+			name: "task marker",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed, Message: "tests did not pass",
+				CorrelationID: "run-1"},
+			want: "\n\n<!-- shepherd-task:task-1 correlation:run-1 -->",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeForge{}
+			h := newTestCallbackHandler(t, f)
+			h.RegisterTask("task-1", TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 42})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, callbackRequest(t, "secret", tt.payload))
+			assert.Equal(t, http.StatusOK, w.Code)
+			require.Len(t, f.comments, 1)
+			assert.Contains(t, f.comments[0], tt.want)
+			assert.Empty(t, h.tasks, "metadata of a finished task is dropped")
+		})
+	}
+}
+
+func TestCallbackHandler_IgnoresIntermediateEvents(t *testing.T) {
+	f := &fakeForge{}
+	h := newTestCallbackHandler(t, f)
+	h.RegisterTask("task-1", TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 42})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "secret", api.CallbackPayload{TaskID: "task-1", Event: api.EventStarted}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, f.comments)
+}
+
+func TestCallbackHandler_InvalidSignature(t *testing.T) {
+	f := &fakeForge{}
+	h := newTestCallbackHandler(t, f)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "wrong", api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, f.comments)
+}
+
+func TestCallbackHandler_VerifiesRotatedSecretsAndKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	f := &fakeForge{}
+	h := newTestCallbackHandler(t, f, WithSecondaryCallbackSecret("new-secret"), WithCallbackPublicKeys(pub))
+	h.RegisterTask("task-1", TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 42})
+	payload := api.CallbackPayload{TaskID: "task-1", Event: api.EventStarted}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "new-secret", payload))
+	assert.Equal(t, http.StatusOK, w.Code, "secondary secret")
+
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(body))
+	req.Header.Add("X-Shepherd-Signature", "sha256=unknown")
+	req.Header.Add("X-Shepherd-Signature", api.SignCallbackEd25519(priv, body))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "public key")
+}
+
+func TestCallbackHandler_RecoversMetadataFromAPI(t *testing.T) {
+	f := &fakeForge{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/tasks/task-1", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.TaskResponse{
+			ID:   "task-1",
+			Task: api.TaskRequest{SourceURL: "https://gitea.example.com/org/repo/issues/42"},
+		})
+	})
+	mux.Handle("/", f)
+	h := newTestCallbackHandler(t, mux)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "secret", api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, f.comments, 1)
+	assert.Contains(t, f.comments[0], "Error: Unknown error")
+}
+
+func TestParseSourceURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		basePath  string
+		sourceURL string
+		want      TaskMetadata
+		wantErr   bool
+	}{
+		{name: "issue", sourceURL: "https://gitea.example.com/org/repo/issues/42",
+			want: TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 42}},
+		{name: "pull request", sourceURL: "https://gitea.example.com/org/repo/pulls/7",
+			want: TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 7}},
+		{name: "below a path", basePath: "/gitea", sourceURL: "https://example.com/gitea/org/repo/issues/1",
+			want: TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 1}},
+		{name: "empty", wantErr: true},
+		{name: "not an issue", sourceURL: "https://gitea.example.com/org/repo/wiki/42", wantErr: true},
+		{name: "bad number", sourceURL: "https://gitea.example.com/org/repo/issues/abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSourceURL(tt.basePath, tt.sourceURL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
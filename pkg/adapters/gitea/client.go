@@ -0,0 +1,158 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gitea implements the Shepherd adapter for self-hosted Gitea and
+// Forgejo instances. Forgejo is a fork of Gitea and serves the same webhook
+// payloads and REST API, so one adapter handles both.
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/NissesSenap/shepherd/pkg/forge"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+)
+
+// commentsPerPage is the page size used when listing comments. Gitea caps
+// it at the instance's MAX_RESPONSE_ITEMS, 50 by default.
+const commentsPerPage = 50
+
+// User is a Gitea account.
+type User struct {
+	Login string `json:"login"`
+}
+
+// Comment is a comment on a Gitea issue or pull request.
+type Comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	User User   `json:"user"`
+}
+
+// Client talks to the REST API of a Gitea or Forgejo instance with an
+// access token.
+type Client struct {
+	http    *http.Client
+	baseURL string // instance URL without a trailing slash
+	token   string
+	// basePath prefixes repository paths in the instance's web URLs; see
+	// forge.BasePath.
+	basePath string
+}
+
+// NewClient creates a client for the instance at baseURL, such as
+// https://gitea.example.com, authenticated with token.
+func NewClient(baseURL, token string) (*Client, error) {
+	if err := forge.ValidateURL(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid Gitea URL: %w", err)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("gitea token is required")
+	}
+	return newClient(&http.Client{Transport: tracing.Transport(http.DefaultTransport)}, baseURL, token), nil
+}
+
+func newClient(httpClient *http.Client, baseURL, token string) *Client {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return &Client{
+		http:     httpClient,
+		baseURL:  baseURL,
+		token:    token,
+		basePath: forge.BasePath(baseURL),
+	}
+}
+
+// CheckCredentials verifies that the token is accepted by the instance.
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	var user User
+	if err := c.do(ctx, http.MethodGet, "/user", nil, &user); err != nil {
+		return fmt.Errorf("getting authenticated user: %w", err)
+	}
+	return nil
+}
+
+// PostComment posts a comment to an issue or pull request.
+func (c *Client) PostComment(ctx context.Context, owner, repo string, index int, body string) error {
+	req := map[string]string{"body": body}
+	if err := c.do(ctx, http.MethodPost, issuePath(owner, repo, index)+"/comments", req, nil); err != nil {
+		return fmt.Errorf("creating comment: %w", err)
+	}
+	return nil
+}
+
+// ListIssueComments retrieves all comments on an issue, oldest first.
+func (c *Client) ListIssueComments(ctx context.Context, owner, repo string, index int) ([]Comment, error) {
+	var all []Comment
+	for page := 1; ; page++ {
+		var comments []Comment
+		path := fmt.Sprintf("%s/comments?page=%d&limit=%d", issuePath(owner, repo, index), page, commentsPerPage)
+		if err := c.do(ctx, http.MethodGet, path, nil, &comments); err != nil {
+			return nil, fmt.Errorf("listing comments: %w", err)
+		}
+		all = append(all, comments...)
+		if len(comments) < commentsPerPage {
+			return all, nil
+		}
+	}
+}
+
+func issuePath(owner, repo string, index int) string {
+	return fmt.Sprintf("/repos/%s/%s/issues/%d", url.PathEscape(owner), url.PathEscape(repo), index)
+}
+
+// do sends a request to the API below /api/v1 and decodes the JSON
+// response into out, unless out is nil.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/api/v1"+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient creates a Client backed by a test HTTP server.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return newClient(srv.Client(), srv.URL+"/", "test-token")
+}
+
+func TestClient_PostComment(t *testing.T) {
+	var receivedBody map[string]string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/repos/org/repo/issues/42/comments", r.URL.Path)
+		assert.Equal(t, "token test-token", r.Header.Get("Authorization"))
+		_ = json.NewDecoder(r.Body).Decode(&receivedBody)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+
+	require.NoError(t, client.PostComment(context.Background(), "org", "repo", 42, "Hello from Shepherd"))
+	assert.Equal(t, "Hello from Shepherd", receivedBody["body"])
+}
+
+func TestClient_PostComment_Error(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"token is required"}`, http.StatusUnauthorized)
+	}))
+
+	err := client.PostComment(context.Background(), "org", "repo", 42, "body")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	assert.Contains(t, err.Error(), "token is required")
+}
+
+func TestClient_ListIssueComments_Paginates(t *testing.T) {
+	var pages []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		n := commentsPerPage
+		if page == "2" {
+			n = 1
+		}
+		comments := make([]Comment, n)
+		for i := range comments {
+			comments[i] = Comment{ID: int64(i), Body: fmt.Sprintf("comment %s-%d", page, i), User: User{Login: "alice"}}
+		}
+		_ = json.NewEncoder(w).Encode(comments)
+	}))
+
+	comments, err := client.ListIssueComments(context.Background(), "org", "repo", 42)
+	require.NoError(t, err)
+	assert.Len(t, comments, commentsPerPage+1)
+	assert.Equal(t, []string{"1", "2"}, pages)
+	assert.Equal(t, "comment 2-0", comments[commentsPerPage].Body)
+}
+
+func TestNewClient_Validation(t *testing.T) {
+	_, err := NewClient("gitea.example.com", "token")
+	assert.Error(t, err, "URL without scheme")
+
+	_, err = NewClient("https://gitea.example.com", "")
+	assert.Error(t, err, "empty token")
+
+	c, err := NewClient("https://example.com/gitea/", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/gitea", c.baseURL)
+	assert.Equal(t, "/gitea", c.basePath)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/httprate"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/listen"
+	"github.com/NissesSenap/shepherd/pkg/readiness"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+)
+
+// Options configures the Gitea adapter.
+type Options struct {
+	ListenAddr             string        // ":8083"
+	WebhookSecret          string        // Secret configured on the Gitea webhook
+	GiteaURL               string        // Instance URL, e.g. "https://gitea.example.com"
+	TokenPath              string        // File holding the access token the adapter comments with
+	APIURL                 string        // Shepherd API URL (e.g., "http://shepherd-api:8080")
+	CallbackSecret         string        // Shared secret for callback HMAC verification
+	CallbackURL            string        // URL for API to call back (e.g., "http://gitea-adapter:8083/callback")
+	DefaultSandboxTemplate string        // Default sandbox template name
+	EventTimeout           time.Duration // How long a webhook event or callback may take to handle
+	// CallbackSecondarySecret is also accepted while the callback secret
+	// is rotated.
+	CallbackSecondarySecret string
+	// CallbackPublicKeyPath is a PEM file with the Ed25519 public keys
+	// callbacks may be signed with instead of the callback secret.
+	CallbackPublicKeyPath string
+}
+
+// requireJSON validates Content-Type on POST/PUT/PATCH requests.
+func requireJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost || r.Method == http.MethodPut || r.Method == http.MethodPatch {
+			ct := r.Header.Get("Content-Type")
+			if !strings.HasPrefix(ct, "application/json") {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				_, _ = w.Write([]byte(`{"error":"Content-Type must be application/json"}`))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Run starts the Gitea adapter server.
+func Run(opts Options) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	log := ctrl.Log.WithName("gitea-adapter")
+
+	token, err := os.ReadFile(opts.TokenPath)
+	if err != nil {
+		return fmt.Errorf("reading gitea token: %w", err)
+	}
+	client, err := NewClient(opts.GiteaURL, strings.TrimSpace(string(token)))
+	if err != nil {
+		return fmt.Errorf("creating gitea client: %w", err)
+	}
+	apiClient := NewAPIClient(opts.APIURL)
+
+	callbackOpts := []CallbackOption{WithSecondaryCallbackSecret(opts.CallbackSecondarySecret)}
+	if opts.CallbackPublicKeyPath != "" {
+		keys, err := api.LoadCallbackPublicKeys(opts.CallbackPublicKeyPath)
+		if err != nil {
+			return err
+		}
+		callbackOpts = append(callbackOpts, WithCallbackPublicKeys(keys...))
+	}
+	callbackHandler := NewCallbackHandler(
+		opts.CallbackSecret, client, apiClient, opts.EventTimeout, log.WithName("callbacks"), callbackOpts...)
+	webhookHandler := NewWebhookHandler(
+		opts.WebhookSecret,
+		client,
+		apiClient,
+		callbackHandler,
+		opts.CallbackURL,
+		opts.DefaultSandboxTemplate,
+		opts.EventTimeout,
+		log.WithName("webhooks"),
+	)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
+	r.Use(middleware.Recoverer)
+
+	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	// Like the GitHub adapter, an outage of either dependency is only
+	// reported and does not fail readiness.
+	r.Get("/readyz", readiness.New(
+		readiness.Check{Name: "gitea", Interval: 5 * time.Minute, Run: client.CheckCredentials},
+		readiness.Check{Name: "api", Interval: 10 * time.Second, Run: apiClient.Ping},
+	).ServeHTTP)
+
+	r.Route("/webhook", func(r chi.Router) {
+		r.Use(httprate.LimitByIP(100, time.Minute))
+		r.Use(requireJSON)
+		r.Post("/", webhookHandler.ServeHTTP)
+	})
+	r.With(requireJSON).Post("/callback", callbackHandler.ServeHTTP)
+
+	ln, err := listen.Listen(opts.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("listener: %w", err)
+	}
+	srv := &http.Server{
+		Handler:      tracing.Handler(r, "shepherd-gitea"),
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 60 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("starting Gitea adapter", "addr", opts.ListenAddr, "gitea", opts.GiteaURL)
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			errCh <- fmt.Errorf("server error: %w", err)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Info("shutting down Gitea adapter")
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// mentionRegex matches @shepherd mentions but not email-style patterns
+// (e.g., user@shepherd.io). Requires start-of-string or whitespace before the @.
+var mentionRegex = regexp.MustCompile(`(?i)(?:^|\s)@shepherd\b`)
+
+// maxContextSize is the soft limit for context passed to the API, the same
+// as the GitHub adapter's.
+const maxContextSize = 1_000_000 // 1MB
+
+// Forgejo sends its own headers, and Gitea's as well up to Forgejo 7;
+// both are accepted.
+var (
+	eventHeaders     = []string{"X-Gitea-Event", "X-Forgejo-Event"}
+	signatureHeaders = []string{"X-Gitea-Signature", "X-Forgejo-Signature"}
+)
+
+// issueCommentEvent is the part of a Gitea issue_comment webhook payload
+// the adapter uses.
+type issueCommentEvent struct {
+	Action string `json:"action"`
+	Issue  struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+		Body    string `json:"body"`
+	} `json:"issue"`
+	Comment struct {
+		Body string `json:"body"`
+		User User   `json:"user"`
+	} `json:"comment"`
+	Repository struct {
+		Name          string `json:"name"`
+		FullName      string `json:"full_name"`
+		Owner         User   `json:"owner"`
+		CloneURL      string `json:"clone_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+}
+
+// WebhookHandler handles incoming Gitea and Forgejo webhooks.
+type WebhookHandler struct {
+	secret                 string
+	client                 *Client
+	apiClient              *APIClient
+	callbackHandler        *CallbackHandler
+	callbackURL            string
+	defaultSandboxTemplate string
+	eventTimeout           time.Duration
+	log                    logr.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler. Each event is handled
+// within eventTimeout.
+func NewWebhookHandler(
+	secret string,
+	client *Client,
+	apiClient *APIClient,
+	callbackHandler *CallbackHandler,
+	callbackURL string,
+	defaultSandboxTemplate string,
+	eventTimeout time.Duration,
+	log logr.Logger,
+) *WebhookHandler {
+	return &WebhookHandler{
+		secret:                 secret,
+		client:                 client,
+		apiClient:              apiClient,
+		callbackHandler:        callbackHandler,
+		callbackURL:            callbackURL,
+		defaultSandboxTemplate: defaultSandboxTemplate,
+		eventTimeout:           eventTimeout,
+		log:                    log,
+	}
+}
+
+// ServeHTTP handles webhook requests.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Read body with 10MB limit
+	body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		h.log.Error(err, "failed to read webhook body")
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verifySignature(body, firstHeader(r.Header, signatureHeaders)) {
+		h.log.Info("webhook signature verification failed")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	eventType := firstHeader(r.Header, eventHeaders)
+	h.log.V(1).Info("received webhook", "event", eventType)
+
+	switch eventType {
+	case "issue_comment":
+		// Finish the event even if Gitea stops waiting for the response.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), h.eventTimeout)
+		defer cancel()
+		h.handleIssueComment(ctx, body)
+	default:
+		h.log.V(1).Info("ignoring event type", "event", eventType)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// firstHeader returns the first of names that is set in header.
+func firstHeader(header http.Header, names []string) string {
+	for _, name := range names {
+		if v := header.Get(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// verifySignature verifies the webhook signature, the hex-encoded
+// HMAC-SHA256 of the body. Unlike GitHub, Gitea sends no "sha256=" prefix.
+func (h *WebhookHandler) verifySignature(body []byte, signature string) bool {
+	if h.secret == "" {
+		return true // No verification if no secret configured
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// handleIssueComment processes issue_comment events. Gitea sends them for
+// comments on both issues and pull requests.
+func (h *WebhookHandler) handleIssueComment(ctx context.Context, body []byte) {
+	var event issueCommentEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.log.Error(err, "failed to parse issue_comment event")
+		return
+	}
+
+	// Only process new comments (not edits or deletes)
+	if event.Action != "created" {
+		return
+	}
+
+	if !mentionRegex.MatchString(event.Comment.Body) {
+		return
+	}
+
+	description := strings.TrimSpace(mentionRegex.ReplaceAllString(event.Comment.Body, ""))
+	if description == "" {
+		description = "Work on this issue"
+	}
+
+	h.log.Info("processing @shepherd mention",
+		"repo", event.Repository.FullName,
+		"issue", event.Issue.Number,
+		"user", event.Comment.User.Login,
+	)
+
+	h.processTask(ctx, &event, description)
+}
+
+// processTask handles the task creation workflow.
+func (h *WebhookHandler) processTask(ctx context.Context, event *issueCommentEvent, description string) {
+	meta := TaskMetadata{
+		Owner:       event.Repository.Owner.Login,
+		Repo:        event.Repository.Name,
+		IssueNumber: event.Issue.Number,
+	}
+	issueURL := event.Issue.HTMLURL
+	issueLabel := fmt.Sprintf("%d", meta.IssueNumber)
+
+	// Check for an active task of the issue (deduplication)
+	task, err := h.apiClient.GetActiveTask(ctx, issueURL)
+	if err != nil {
+		h.log.Error(err, "failed to check for active tasks")
+		// Continue anyway - better to potentially create duplicate than fail silently
+	}
+	if task != nil {
+		h.log.Info("task already running", logging.TaskID, task.ID, "status", task.Status.Phase)
+		h.postComment(ctx, meta, formatAlreadyRunning(task.ID, task.Status.Phase))
+		return
+	}
+
+	createReq := api.CreateTaskRequest{
+		Repo: api.RepoRequest{
+			URL: event.Repository.CloneURL,
+			// Gitea sends the default branch with every event, so tasks
+			// are pinned to it without an extra request.
+			Ref: event.Repository.DefaultBranch,
+		},
+		Task: api.TaskRequest{
+			Description: description,
+			Context:     h.buildContext(ctx, meta, event.Issue.Body),
+			SourceURL:   issueURL,
+			SourceType:  api.SourceTypeIssue,
+			SourceID:    issueLabel,
+		},
+		Callback: h.callbackURL,
+		Runner: &api.RunnerConfig{
+			SandboxTemplateName: h.defaultSandboxTemplate,
+		},
+		Labels: map[string]string{
+			"shepherd.io/repo":         strings.ReplaceAll(event.Repository.FullName, "/", "-"),
+			"shepherd.io/issue":        issueLabel,
+			"shepherd.io/requested-by": event.Comment.User.Login,
+		},
+	}
+
+	taskResp, err := h.apiClient.CreateTask(ctx, createReq)
+	if err != nil {
+		h.log.Error(err, "failed to create task")
+		h.postComment(ctx, meta, formatFailed("Failed to create task"))
+		return
+	}
+
+	h.log.Info("created task", logging.TaskID, taskResp.ID, logging.CorrelationID, taskResp.CorrelationID)
+
+	h.callbackHandler.RegisterTask(taskResp.ID, meta)
+	h.postComment(ctx, meta, withTaskMarker(formatAcknowledge(taskResp.ID), taskResp.ID, taskResp.CorrelationID))
+}
+
+func (h *WebhookHandler) postComment(ctx context.Context, meta TaskMetadata, body string) {
+	if err := h.client.PostComment(ctx, meta.Owner, meta.Repo, meta.IssueNumber, body); err != nil {
+		h.log.Error(err, "failed to post comment", "repo", meta.Owner+"/"+meta.Repo, "issue", meta.IssueNumber)
+	}
+}
+
+// buildContext assembles the context string from issue body and comments,
+// truncated once it exceeds maxContextSize.
+func (h *WebhookHandler) buildContext(ctx context.Context, meta TaskMetadata, issueBody string) string {
+	var sb strings.Builder
+	sb.WriteString("## Issue Description\n\n")
+	sb.WriteString(issueBody)
+	sb.WriteString("\n\n")
+
+	comments, err := h.client.ListIssueComments(ctx, meta.Owner, meta.Repo, meta.IssueNumber)
+	if err != nil {
+		h.log.Error(err, "failed to fetch issue comments")
+		return sb.String()
+	}
+
+	if len(comments) > 0 {
+		sb.WriteString("## Comments\n\n")
+		for _, c := range comments {
+			entry := fmt.Sprintf("**%s** wrote:\n\n%s\n\n---\n\n", c.User.Login, c.Body)
+			if sb.Len()+len(entry) > maxContextSize {
+				sb.WriteString("\n\n--- Context truncated due to size limit ---\n")
+				h.log.Info("context truncated", "issue", meta.IssueNumber, "size", sb.Len())
+				break
+			}
+			sb.WriteString(entry)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+const testCommentsPath = "/api/v1/repos/org/repo/issues/42/comments"
+
+func signedRequest(t *testing.T, secret string, body []byte, eventHeader, event string) *http.Request {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	req.Header.Set("X-Gitea-Signature", hex.EncodeToString(mac.Sum(nil)))
+	req.Header.Set(eventHeader, event)
+	return req
+}
+
+func issueCommentPayload(t *testing.T, comment string) []byte {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"action": "created",
+		"issue": map[string]any{
+			"number":   42,
+			"html_url": "https://gitea.example.com/org/repo/issues/42",
+			"body":     "The login page is broken",
+		},
+		"comment": map[string]any{
+			"body": comment,
+			"user": map[string]any{"login": "alice"},
+		},
+		"repository": map[string]any{
+			"name":           "repo",
+			"full_name":      "org/repo",
+			"owner":          map[string]any{"login": "org"},
+			"clone_url":      "https://gitea.example.com/org/repo.git",
+			"default_branch": "main",
+		},
+	})
+	require.NoError(t, err)
+	return body
+}
+
+// fakeForge serves the Gitea comments endpoint and the Shepherd API tasks
+// endpoints, recording what was posted.
+type fakeForge struct {
+	mu         sync.Mutex
+	comments   []string
+	created    []api.CreateTaskRequest
+	activeTask *api.TaskResponse
+}
+
+func (f *fakeForge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == testCommentsPath && r.Method == http.MethodGet:
+		_, _ = w.Write([]byte(`[{"id": 1, "body": "I can reproduce this", "user": {"login": "bob"}}]`))
+	case r.URL.Path == testCommentsPath && r.Method == http.MethodPost:
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.comments = append(f.comments, req["body"])
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	case r.URL.Path == "/api/v1/tasks/active":
+		if f.activeTask == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.activeTask)
+	case r.URL.Path == "/api/v1/tasks" && r.Method == http.MethodPost:
+		var req api.CreateTaskRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.created = append(f.created, req)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(api.TaskResponse{ID: "task-abc"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestWebhookHandler(t *testing.T, f *fakeForge) (*WebhookHandler, *CallbackHandler) {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client := newClient(srv.Client(), srv.URL, "token")
+	apiClient := NewAPIClient(srv.URL)
+	log := ctrl.Log.WithName("test")
+	cb := NewCallbackHandler("", client, apiClient, time.Minute, log)
+	return NewWebhookHandler("secret", client, apiClient, cb, "http://adapter/callback", "default", time.Minute, log), cb
+}
+
+func TestWebhookHandler_SignatureVerification(t *testing.T) {
+	h := NewWebhookHandler("secret", nil, nil, nil, "", "default", time.Minute, ctrl.Log.WithName("test"))
+	body := []byte(`{"action":"created"}`)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "secret", body, "X-Gitea-Event", "push"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "wrong", body, "X-Gitea-Event", "push"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := signedRequest(t, "secret", body, "X-Gitea-Event", "push")
+	req.Header.Set("X-Gitea-Signature", "sha256="+req.Header.Get("X-Gitea-Signature"))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "GitHub-style prefixed signature")
+
+	// Forgejo's own signature header is accepted too
+	req = signedRequest(t, "secret", body, "X-Forgejo-Event", "push")
+	req.Header.Set("X-Forgejo-Signature", req.Header.Get("X-Gitea-Signature"))
+	req.Header.Del("X-Gitea-Signature")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestWebhookHandler_CreatesTask(t *testing.T) {
+	for _, header := range []string{"X-Gitea-Event", "X-Forgejo-Event"} {
+		t.Run(header, func(t *testing.T) {
+			f := &fakeForge{}
+			h, cb := newTestWebhookHandler(t, f)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, signedRequest(t, "secret",
+				issueCommentPayload(t, "@shepherd fix the login page"), header, "issue_comment"))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			require.Len(t, f.created, 1)
+			req := f.created[0]
+			assert.Equal(t, "https://gitea.example.com/org/repo.git", req.Repo.URL)
+			assert.Equal(t, "main", req.Repo.Ref)
+			assert.Equal(t, "fix the login page", req.Task.Description)
+			assert.Equal(t, "https://gitea.example.com/org/repo/issues/42", req.Task.SourceURL)
+			assert.Contains(t, req.Task.Context, "The login page is broken")
+			assert.Contains(t, req.Task.Context, "**bob** wrote:\n\nI can reproduce this")
+			assert.Equal(t, "http://adapter/callback", req.Callback)
+			assert.Equal(t, map[string]string{
+				"shepherd.io/repo":         "org-repo",
+				"shepherd.io/issue":        "42",
+				"shepherd.io/requested-by": "alice",
+			}, req.Labels)
+
+			require.Len(t, f.comments, 1)
+			assert.Contains(t, f.comments[0], "Task ID: task-abc")
+			assert.Equal(t, TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 42}, cb.tasks["task-abc"])
+		})
+	}
+}
+
+func TestWebhookHandler_IgnoresComments(t *testing.T) {
+	tests := map[string]string{
+		"no mention":    "looks good to me",
+		"email address": "contact user@shepherd.io",
+	}
+	for name, comment := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &fakeForge{}
+			h, _ := newTestWebhookHandler(t, f)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, signedRequest(t, "secret", issueCommentPayload(t, comment), "X-Gitea-Event", "issue_comment"))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, f.created)
+			assert.Empty(t, f.comments)
+		})
+	}
+}
+
+func TestWebhookHandler_TaskAlreadyRunning(t *testing.T) {
+	f := &fakeForge{activeTask: &api.TaskResponse{ID: "task-old", Status: api.TaskStatusSummary{Phase: "Running"}}}
+	h, _ := newTestWebhookHandler(t, f)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "secret", issueCommentPayload(t, "@shepherd again"), "X-Gitea-Event", "issue_comment"))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.Empty(t, f.created)
+	require.Len(t, f.comments, 1)
+	assert.Contains(t, f.comments[0], "Task ID: task-old")
+	assert.Contains(t, f.comments[0], "Status: Running")
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"time"
+
+	gh "github.com/google/go-github/v75/github"
+
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// ackRetryDelays are the waits before each new attempt to post an
+// acknowledgment comment that could not be posted. Once they are used up,
+// the acknowledgment rides along with the task's next comment instead.
+var ackRetryDelays = []time.Duration{15 * time.Second, time.Minute, 5 * time.Minute}
+
+// ackPostTimeout bounds a single retried attempt to post an acknowledgment.
+const ackPostTimeout = 30 * time.Second
+
+// queueAck retries posting the acknowledgment of taskID in the background
+// after it failed, so the requester is not left without an answer. The
+// acknowledgment stays pending until it is posted, and the task's comments
+// include it while it is.
+func (h *CallbackHandler) queueAck(ctx context.Context, taskID string, meta TaskMetadata) {
+	h.mu.Lock()
+	h.pendingAcks[taskID] = meta
+	h.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for _, delay := range ackRetryDelays {
+			time.Sleep(delay)
+			if h.postPendingAck(ctx, taskID) {
+				return
+			}
+		}
+		h.log.Info("giving up retrying acknowledgment comment, adding it to the task's next comment",
+			logging.TaskID, taskID)
+	}()
+}
+
+// postPendingAck posts the pending acknowledgment of taskID, and reports
+// whether there is nothing left to post.
+func (h *CallbackHandler) postPendingAck(ctx context.Context, taskID string) bool {
+	meta, ok := h.takePendingAck(taskID)
+	if !ok {
+		// Posted already, or included in another comment.
+		return true
+	}
+	ctx, cancel := context.WithTimeout(ctx, ackPostTimeout)
+	defer cancel()
+	ack, err := h.ghClient.CreateComment(ctx, meta.Owner, meta.Repo, meta.IssueNumber,
+		withTaskMarker(formatAcknowledge(taskID, meta.Lineage), taskID, meta.CorrelationID))
+	if err != nil {
+		h.log.Error(err, "failed to post acknowledgment comment", logging.TaskID, taskID)
+		// Pending again before checking the task, so that a result comment
+		// posted meanwhile either includes the acknowledgment or is seen.
+		h.mu.Lock()
+		h.pendingAcks[taskID] = meta
+		h.mu.Unlock()
+		if _, active, err := h.tasks.Get(ctx, taskID); err == nil && !active {
+			// The task finished meanwhile; its result comment is the answer.
+			h.takePendingAck(taskID)
+			return true
+		}
+		return false
+	}
+	h.log.Info("posted delayed acknowledgment comment", logging.TaskID, taskID)
+	h.setAck(ctx, taskID, ack)
+	return true
+}
+
+// setAck records the acknowledgment comment of taskID, if the task has not
+// finished yet.
+func (h *CallbackHandler) setAck(ctx context.Context, taskID string, ack *gh.IssueComment) {
+	_, err := h.tasks.Update(ctx, taskID, func(meta *TaskMetadata) bool {
+		meta.AckURL = ack.GetHTMLURL()
+		meta.AckCommentID = ack.GetID()
+		meta.AckedAt = ack.GetCreatedAt().Time
+		if meta.AckedAt.IsZero() {
+			meta.AckedAt = time.Now()
+		}
+		return true
+	})
+	if err != nil {
+		h.log.Error(err, "failed to store acknowledgment comment", logging.TaskID, taskID)
+	}
+}
+
+// takePendingAck removes the pending acknowledgment of taskID, so that
+// only the caller posts it.
+func (h *CallbackHandler) takePendingAck(taskID string) (TaskMetadata, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	meta, ok := h.pendingAcks[taskID]
+	delete(h.pendingAcks, taskID)
+	return meta, ok
+}
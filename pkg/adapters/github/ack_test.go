@@ -0,0 +1,132 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// commentRecorder is a GitHub server that fails the first failures comment
+// posts and records the rest.
+type commentRecorder struct {
+	mu       sync.Mutex
+	failures int
+	comments []string
+}
+
+func (c *commentRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	var body map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	c.comments = append(c.comments, body["body"])
+	w.WriteHeader(http.StatusCreated)
+	_, _ = w.Write([]byte(`{"id":1}`))
+}
+
+func (c *commentRecorder) posted() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.comments...)
+}
+
+func TestCallbackHandler_QueueAckRetries(t *testing.T) {
+	delays := ackRetryDelays
+	ackRetryDelays = []time.Duration{time.Millisecond, time.Millisecond, time.Millisecond}
+	t.Cleanup(func() { ackRetryDelays = delays })
+
+	gh := &commentRecorder{failures: 1}
+	srv := httptest.NewServer(gh)
+	defer srv.Close()
+
+	meta := TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 7}
+	handler := NewCallbackHandler("", newTestClientFromServer(t, srv), nil, ctrl.Log.WithName("test"))
+	handler.RegisterTask(context.Background(), "task-1", meta)
+	handler.queueAck(context.Background(), "task-1", meta)
+
+	require.Eventually(t, func() bool { return len(gh.posted()) == 1 }, 5*time.Second, 5*time.Millisecond)
+	assert.Contains(t, gh.posted()[0], "working on your request")
+	assert.Contains(t, gh.posted()[0], "task-1")
+	_, pending := handler.takePendingAck("task-1")
+	assert.False(t, pending)
+}
+
+func TestCallbackHandler_PendingAckInCallbackComments(t *testing.T) {
+	meta := TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 7}
+
+	t.Run("posted on started", func(t *testing.T) {
+		gh := &commentRecorder{}
+		srv := httptest.NewServer(gh)
+		defer srv.Close()
+		handler := NewCallbackHandler("", newTestClientFromServer(t, srv), nil, ctrl.Log.WithName("test"))
+		handler.RegisterTask(context.Background(), "task-1", meta)
+		handler.pendingAcks["task-1"] = meta
+
+		handler.handleCallback(context.Background(), &api.CallbackPayload{TaskID: "task-1", Event: api.EventStarted})
+		require.Len(t, gh.posted(), 1)
+		assert.Contains(t, gh.posted()[0], "working on your request")
+
+		// Later progress does not repeat it.
+		handler.handleCallback(context.Background(), &api.CallbackPayload{TaskID: "task-1", Event: api.EventProgress})
+		assert.Len(t, gh.posted(), 1)
+	})
+
+	t.Run("included in result", func(t *testing.T) {
+		gh := &commentRecorder{}
+		srv := httptest.NewServer(gh)
+		defer srv.Close()
+		handler := NewCallbackHandler("", newTestClientFromServer(t, srv), nil, ctrl.Log.WithName("test"))
+		handler.RegisterTask(context.Background(), "task-2", meta)
+		handler.pendingAcks["task-2"] = meta
+
+		handler.handleCallback(context.Background(), &api.CallbackPayload{
+			TaskID: "task-2", Event: api.EventFailed, Message: "tests failed",
+		})
+		require.Len(t, gh.posted(), 1)
+		assert.Contains(t, gh.posted()[0], "picked up your request as task task-2")
+		assert.Contains(t, gh.posted()[0], "tests failed")
+
+		// A retry that fails after the task finished is dropped.
+		handler.pendingAcks["task-2"] = meta
+		gh.mu.Lock()
+		gh.failures = 1
+		gh.mu.Unlock()
+		assert.True(t, handler.postPendingAck(context.Background(), "task-2"))
+		_, pending := handler.takePendingAck("task-2")
+		assert.False(t, pending)
+	})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// RepoAllowlist restricts the repositories whose mentions trigger tasks.
+// A nil RepoAllowlist allows every repository the app is installed on.
+type RepoAllowlist struct {
+	patterns []string
+}
+
+// NewRepoAllowlist returns an allowlist of the repositories matching one of
+// patterns. A pattern is owner/repo, where either part may be a glob, such
+// as myorg/* or myorg/infra-*. Patterns are compared without regard to case.
+func NewRepoAllowlist(patterns []string) (*RepoAllowlist, error) {
+	a := &RepoAllowlist{}
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		owner, repo, ok := strings.Cut(p, "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return nil, fmt.Errorf("pattern %q must have the form owner/repo", p)
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", p, err)
+		}
+		a.patterns = append(a.patterns, p)
+	}
+	return a, nil
+}
+
+// ReadRepoPatterns reads allowlist patterns from the file at name, one per
+// line. Blank lines and lines starting with # are skipped.
+func ReadRepoPatterns(name string) ([]string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading allowed repositories: %w", err)
+	}
+	var patterns []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// Allows reports whether the repository fullName, as owner/repo, matches
+// one of the allowlist's patterns.
+func (a *RepoAllowlist) Allows(fullName string) bool {
+	if a == nil {
+		return true
+	}
+	fullName = strings.ToLower(fullName)
+	for _, p := range a.patterns {
+		if ok, _ := path.Match(p, fullName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRepoAllowlist only creates tasks for mentions in repositories that
+// allowlist allows.
+func WithRepoAllowlist(allowlist *RepoAllowlist) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.allowlist = allowlist
+	}
+}
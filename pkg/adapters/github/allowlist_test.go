@@ -0,0 +1,87 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoAllowlist_Allows(t *testing.T) {
+	allowlist, err := NewRepoAllowlist([]string{"myorg/*", "other/infra-*", " Acme/Docs "})
+	require.NoError(t, err)
+
+	tests := []struct {
+		repo string
+		want bool
+	}{
+		{repo: "myorg/api", want: true},
+		{repo: "MyOrg/API", want: true},
+		{repo: "other/infra-dns", want: true},
+		{repo: "other/app", want: false},
+		{repo: "acme/docs", want: true},
+		{repo: "acme/docs-site", want: false},
+		{repo: "myorgs/api", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, allowlist.Allows(tt.repo), tt.repo)
+	}
+
+	var none *RepoAllowlist
+	assert.True(t, none.Allows("anyone/anything"), "a nil allowlist allows every repository")
+}
+
+func TestNewRepoAllowlist_InvalidPatterns(t *testing.T) {
+	for _, pattern := range []string{"myorg", "myorg/", "/repo", "myorg/team/repo", "myorg/[a"} {
+		_, err := NewRepoAllowlist([]string{pattern})
+		assert.Error(t, err, pattern)
+	}
+}
+
+func TestReadRepoPatterns(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "allowed-repos")
+	require.NoError(t, os.WriteFile(name, []byte("# platform team\nmyorg/infra-*\n\n  acme/docs  \n"), 0o600))
+
+	patterns, err := ReadRepoPatterns(name)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"myorg/infra-*", "acme/docs"}, patterns)
+
+	_, err = ReadRepoPatterns(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestWebhookHandler_RepoNotAllowlisted(t *testing.T) {
+	f := &fakePullRequest{}
+	handler, _ := newPullRequestTestHandler(t, f)
+	allowlist, err := NewRepoAllowlist([]string{"other/*"})
+	require.NoError(t, err)
+	WithRepoAllowlist(allowlist)(handler)
+
+	body, err := json.Marshal(createTestIssueCommentEvent("org", "repo", 7, "@shepherd fix the login"))
+	require.NoError(t, err)
+	handler.handleIssueComment(context.Background(), body)
+
+	assert.Empty(t, f.created)
+	require.Len(t, f.comments, 1)
+	assert.Contains(t, f.comments[0], "Shepherd is not enabled for org/repo")
+}
@@ -17,176 +17,115 @@ limitations under the License.
 package github
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/client"
 )
 
-const unknownErrorMessage = "unknown error"
+// MaintenanceError is returned by CreateTask while Shepherd is under
+// maintenance and rejects new tasks.
+type MaintenanceError struct {
+	// Message is the maintenance message set by the administrator.
+	Message string
+	// Until is when maintenance is expected to end; zero if not announced.
+	Until time.Time
+}
+
+func (e *MaintenanceError) Error() string {
+	return "shepherd is under maintenance: " + e.Message
+}
 
 // APIClient communicates with the Shepherd API.
 type APIClient struct {
-	baseURL    string
-	httpClient *http.Client
+	api *client.Client
 }
 
 // NewAPIClient creates a new API client.
 func NewAPIClient(baseURL string) *APIClient {
-	return &APIClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}
+	return &APIClient{api: client.New(baseURL)}
 }
 
-// GetActiveTasks queries for active tasks matching the given labels.
-func (c *APIClient) GetActiveTasks(ctx context.Context, repoLabel, issueLabel string) ([]api.TaskResponse, error) {
-	u, err := url.Parse(c.baseURL + "/api/v1/tasks")
-	if err != nil {
-		return nil, fmt.Errorf("parsing URL: %w", err)
-	}
-
-	q := u.Query()
-	q.Set("repo", repoLabel)
-	q.Set("issue", issueLabel)
-	q.Set("active", "true")
-	u.RawQuery = q.Encode()
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+// Ping checks that the API server is reachable and serving.
+func (c *APIClient) Ping(ctx context.Context) error {
+	err := c.api.Healthz(ctx)
+	if code := client.StatusCode(err); code != 0 {
+		return fmt.Errorf("API health check returned %d", code)
 	}
+	return err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		var errResp api.ErrorResponse
-		if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error == "" {
-			msg := string(bytes.TrimSpace(body))
-			if len(msg) > 1024 {
-				msg = msg[:1024]
-			}
-			if msg == "" {
-				msg = unknownErrorMessage
-			}
-			return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, msg)
-		}
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, errResp.Error)
+// GetActiveTask returns the active task created for sourceURL, such as an
+// issue URL, or nil if there is none.
+func (c *APIClient) GetActiveTask(ctx context.Context, sourceURL string) (*api.TaskResponse, error) {
+	task, err := c.api.GetActiveTask(ctx, &client.GetActiveTaskParams{SourceURL: sourceURL})
+	if client.StatusCode(err) == http.StatusNotFound {
+		return nil, nil
 	}
+	return task, err
+}
 
-	var tasks []api.TaskResponse
-	if err := json.Unmarshal(body, &tasks); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
-	}
+// ListTasks returns all tasks, oldest first.
+func (c *APIClient) ListTasks(ctx context.Context) ([]api.TaskResponse, error) {
+	return c.api.ListTasks(ctx, &client.ListTasksParams{Sort: "createdAt"})
+}
 
-	return tasks, nil
+// ListIssueTasks returns the tasks of an issue, by the values of their
+// shepherd.io/repo and shepherd.io/issue labels, oldest first.
+func (c *APIClient) ListIssueTasks(ctx context.Context, repoLabel, issueLabel string) ([]api.TaskResponse, error) {
+	return c.api.ListTasks(ctx, &client.ListTasksParams{Repo: repoLabel, Issue: issueLabel, Sort: "createdAt"})
 }
 
 // GetTask fetches a single task by ID. Used by CallbackHandler to resolve
 // task metadata for callbacks received after a restart (stateless recovery).
 func (c *APIClient) GetTask(ctx context.Context, taskID string) (*api.TaskResponse, error) {
-	reqURL := c.baseURL + "/api/v1/tasks/" + url.PathEscape(taskID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errResp api.ErrorResponse
-		if err := json.Unmarshal(body, &errResp); err != nil || errResp.Error == "" {
-			msg := string(bytes.TrimSpace(body))
-			if len(msg) > 1024 {
-				msg = msg[:1024]
-			}
-			if msg == "" {
-				msg = unknownErrorMessage
-			}
-			return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, msg)
-		}
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, errResp.Error)
-	}
+	return c.api.GetTask(ctx, taskID, nil)
+}
 
-	var task api.TaskResponse
-	if err := json.Unmarshal(body, &task); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
+// CancelTask cancels an unfinished task, giving reason. A task that
+// finished meanwhile is left as it is.
+func (c *APIClient) CancelTask(ctx context.Context, taskID, reason string) error {
+	_, err := c.api.CancelTask(ctx, taskID, &api.CancelTaskRequest{Reason: reason})
+	if client.StatusCode(err) == http.StatusGone {
+		return nil
 	}
-
-	return &task, nil
+	return err
 }
 
 // CreateTask creates a new task via the API.
 func (c *APIClient) CreateTask(ctx context.Context, createReq api.CreateTaskRequest) (*api.TaskResponse, error) {
-	body, err := json.Marshal(createReq)
-	if err != nil {
-		return nil, fmt.Errorf("marshaling request: %w", err)
+	task, err := c.api.CreateTask(ctx, nil, createReq)
+	if maintErr := maintenanceError(err); maintErr != nil {
+		return nil, maintErr
 	}
+	return task, err
+}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/tasks", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
-	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+// RetryTask creates a task retrying a failed, timed out or cancelled task,
+// with its spec and labels.
+func (c *APIClient) RetryTask(ctx context.Context, taskID string) (*api.TaskResponse, error) {
+	task, err := c.api.RetryTask(ctx, taskID, nil)
+	if maintErr := maintenanceError(err); maintErr != nil {
+		return nil, maintErr
 	}
+	return task, err
+}
 
-	if resp.StatusCode != http.StatusCreated {
-		var errResp api.ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil || errResp.Error == "" {
-			msg := string(bytes.TrimSpace(respBody))
-			if len(msg) > 1024 {
-				msg = msg[:1024]
-			}
-			if msg == "" {
-				msg = unknownErrorMessage
-			}
-			return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, msg)
-		}
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, errResp.Error)
+// maintenanceError returns the MaintenanceError err reports, or nil if err
+// is not a rejection because of maintenance.
+func maintenanceError(err error) *MaintenanceError {
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable ||
+		apiErr.Header.Get(api.MaintenanceHeader) == "" {
+		return nil
 	}
-
-	var taskResp api.TaskResponse
-	if err := json.Unmarshal(respBody, &taskResp); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
+	maintErr := &MaintenanceError{Message: apiErr.Response.Details}
+	if until, err := http.ParseTime(apiErr.Header.Get("Retry-After")); err == nil {
+		maintErr.Until = until
 	}
-
-	return &taskResp, nil
+	return maintErr
 }
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,40 +30,38 @@ import (
 	"github.com/NissesSenap/shepherd/pkg/api"
 )
 
-func TestAPIClient_GetActiveTasks(t *testing.T) {
-	t.Run("returns active tasks", func(t *testing.T) {
+func TestAPIClient_GetActiveTask(t *testing.T) {
+	t.Run("returns the active task", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			assert.Equal(t, "/api/v1/tasks", r.URL.Path)
-			assert.Equal(t, "org-repo", r.URL.Query().Get("repo"))
-			assert.Equal(t, "123", r.URL.Query().Get("issue"))
-			assert.Equal(t, "true", r.URL.Query().Get("active"))
+			assert.Equal(t, "/api/v1/tasks/active", r.URL.Path)
+			assert.Equal(t, "https://github.com/org/repo/issues/123", r.URL.Query().Get("sourceURL"))
 
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`[{"id":"task-abc","status":{"phase":"Running"}}]`))
+			_, _ = w.Write([]byte(`{"id":"task-abc","status":{"phase":"Running"}}`))
 		}))
 		defer srv.Close()
 
 		client := NewAPIClient(srv.URL)
-		tasks, err := client.GetActiveTasks(context.Background(), "org-repo", "123")
+		task, err := client.GetActiveTask(context.Background(), "https://github.com/org/repo/issues/123")
 		require.NoError(t, err)
-		require.Len(t, tasks, 1)
-		assert.Equal(t, "task-abc", tasks[0].ID)
-		assert.Equal(t, "Running", tasks[0].Status.Phase)
+		require.NotNil(t, task)
+		assert.Equal(t, "task-abc", task.ID)
+		assert.Equal(t, "Running", task.Status.Phase)
 	})
 
-	t.Run("returns empty array", func(t *testing.T) {
+	t.Run("returns nil without an active task", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`[]`))
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"no active task"}`))
 		}))
 		defer srv.Close()
 
 		client := NewAPIClient(srv.URL)
-		tasks, err := client.GetActiveTasks(context.Background(), "org-repo", "123")
+		task, err := client.GetActiveTask(context.Background(), "https://github.com/org/repo/issues/123")
 		require.NoError(t, err)
-		assert.Empty(t, tasks)
+		assert.Nil(t, task)
 	})
 
 	t.Run("handles API error", func(t *testing.T) {
@@ -74,12 +73,27 @@ func TestAPIClient_GetActiveTasks(t *testing.T) {
 		defer srv.Close()
 
 		client := NewAPIClient(srv.URL)
-		_, err := client.GetActiveTasks(context.Background(), "org-repo", "123")
+		_, err := client.GetActiveTask(context.Background(), "https://github.com/org/repo/issues/123")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "internal server error")
 	})
 }
 
+func TestAPIClient_Ping(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/healthz", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	client := NewAPIClient(srv.URL + "/")
+	require.NoError(t, client.Ping(context.Background()))
+
+	status = http.StatusServiceUnavailable
+	assert.EqualError(t, client.Ping(context.Background()), "API health check returned 503")
+}
+
 func TestAPIClient_GetTask(t *testing.T) {
 	t.Run("returns task", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -150,4 +164,23 @@ func TestAPIClient_CreateTask(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "repo.url is required")
 	})
+
+	t.Run("returns MaintenanceError during maintenance", func(t *testing.T) {
+		until := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(api.MaintenanceHeader, "true")
+			w.Header().Set("Retry-After", until.Format(http.TimeFormat))
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":"shepherd is under maintenance","details":"Upgrading"}`))
+		}))
+		defer srv.Close()
+
+		client := NewAPIClient(srv.URL)
+		_, err := client.CreateTask(context.Background(), api.CreateTaskRequest{})
+		var maintErr *MaintenanceError
+		require.ErrorAs(t, err, &maintErr)
+		assert.Equal(t, "Upgrading", maintErr.Message)
+		assert.True(t, until.Equal(maintErr.Until))
+	})
 }
@@ -0,0 +1,99 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"strings"
+
+	gh "github.com/google/go-github/v75/github"
+
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// WithAssignee creates a task for an issue when it is assigned to the
+// GitHub user login, typically a bot account, and cancels the issue's
+// running task when the issue is unassigned from it.
+func WithAssignee(login string) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.assignee = login
+	}
+}
+
+// isAssignee reports whether the assignee of event is the one that
+// triggers tasks. GitHub logins are not case sensitive.
+func (h *WebhookHandler) isAssignee(event *gh.IssuesEvent) bool {
+	return h.assignee != "" && strings.EqualFold(event.GetAssignee().GetLogin(), h.assignee)
+}
+
+// handleAssigned creates a task for the issue of event if it was assigned
+// to the assignee.
+func (h *WebhookHandler) handleAssigned(ctx context.Context, event *gh.IssuesEvent) {
+	if !h.isAssignee(event) {
+		return
+	}
+
+	h.log.Info("processing assignment",
+		"repo", event.GetRepo().GetFullName(),
+		"issue", event.GetIssue().GetNumber(),
+		"user", event.GetSender().GetLogin(),
+	)
+
+	if !h.authorized(ctx, event.GetRepo(), event.GetIssue().GetNumber(), event.GetSender().GetLogin()) {
+		return
+	}
+
+	h.processIssueTask(ctx, issueTrigger{
+		repo:        event.GetRepo(),
+		issue:       event.GetIssue(),
+		requestedBy: event.GetSender().GetLogin(),
+	}, taskCommand{Description: issueDescription(event.GetIssue())})
+}
+
+// handleUnassigned cancels the running task of the issue of event if it
+// was unassigned from the assignee. The task's cancelled callback then
+// tells the issue.
+func (h *WebhookHandler) handleUnassigned(ctx context.Context, event *gh.IssuesEvent) {
+	if !h.isAssignee(event) {
+		return
+	}
+	user := event.GetSender().GetLogin()
+	task, err := h.apiClient.GetActiveTask(ctx, event.GetIssue().GetHTMLURL())
+	if err != nil {
+		h.log.Error(err, "failed to look up the task of an unassigned issue", "issue", event.GetIssue().GetNumber())
+		return
+	}
+	if task == nil {
+		return
+	}
+
+	h.log.Info("cancelling task of unassigned issue", logging.TaskID, task.ID,
+		"repo", event.GetRepo().GetFullName(),
+		"issue", event.GetIssue().GetNumber(),
+		"user", user,
+	)
+	if !h.authorized(ctx, event.GetRepo(), event.GetIssue().GetNumber(), user) {
+		return
+	}
+	if err := h.apiClient.CancelTask(ctx, task.ID, "unassigned by @"+user); err != nil {
+		h.log.Error(err, "failed to cancel task of unassigned issue", logging.TaskID, task.ID)
+		if commentErr := h.ghClient.PostComment(ctx, event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName(),
+			event.GetIssue().GetNumber(), formatCancelFailed(task.ID, " after this issue was unassigned")); commentErr != nil {
+			h.log.Error(commentErr, "failed to post cancel-failed comment")
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	gh "github.com/google/go-github/v75/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// assignmentEvent returns an issues event for issue 7 being assigned to or
+// unassigned from assignee by alice.
+func assignmentEvent(t *testing.T, action, assignee string) []byte {
+	t.Helper()
+	body, err := json.Marshal(gh.IssuesEvent{
+		Action: gh.Ptr(action),
+		Repo:   testRepository(),
+		Issue: &gh.Issue{
+			Number:  gh.Ptr(7),
+			Title:   gh.Ptr("Login fails with SSO"),
+			Body:    gh.Ptr("Redirect back after SSO login."),
+			HTMLURL: gh.Ptr("https://github.com/org/repo/issues/7"),
+		},
+		Assignee: &gh.User{Login: gh.Ptr(assignee)},
+		Sender:   &gh.User{Login: gh.Ptr("alice")},
+	})
+	require.NoError(t, err)
+	return body
+}
+
+func TestWebhookHandler_Assigned(t *testing.T) {
+	f := &fakePullRequest{}
+	handler, _ := newPullRequestTestHandler(t, f)
+	WithAssignee("shepherd-bot")(handler)
+
+	handler.handleIssues(context.Background(), assignmentEvent(t, "assigned", "Shepherd-Bot"))
+
+	require.Len(t, f.created, 1)
+	assert.Equal(t, "Redirect back after SSO login.", f.created[0].Task.Description)
+	assert.Equal(t, "alice", f.created[0].Labels["shepherd.io/requested-by"])
+	require.Len(t, f.comments, 1)
+	assert.True(t, isAcknowledgment(f.comments[0], "task-pr"))
+}
+
+func TestWebhookHandler_AssignedToSomeoneElse(t *testing.T) {
+	f := &fakePullRequest{}
+	handler, _ := newPullRequestTestHandler(t, f)
+	WithAssignee("shepherd-bot")(handler)
+
+	handler.handleIssues(context.Background(), assignmentEvent(t, "assigned", "bob"))
+	handler.handleIssues(context.Background(), assignmentEvent(t, "unassigned", "bob"))
+
+	assert.Empty(t, f.created)
+	assert.Empty(t, f.comments)
+}
+
+func TestWebhookHandler_Unassigned(t *testing.T) {
+	t.Run("cancels the running task", func(t *testing.T) {
+		f := &fakePullRequest{active: &api.TaskResponse{ID: "task-running"}}
+		handler, _ := newPullRequestTestHandler(t, f)
+		WithAssignee("shepherd-bot")(handler)
+
+		handler.handleIssues(context.Background(), assignmentEvent(t, "unassigned", "shepherd-bot"))
+
+		assert.Equal(t, []string{"unassigned by @alice"}, f.cancelled)
+		assert.Empty(t, f.comments, "the cancelled callback tells the issue")
+	})
+
+	t.Run("without a running task", func(t *testing.T) {
+		f := &fakePullRequest{}
+		handler, _ := newPullRequestTestHandler(t, f)
+		WithAssignee("shepherd-bot")(handler)
+
+		handler.handleIssues(context.Background(), assignmentEvent(t, "unassigned", "shepherd-bot"))
+
+		assert.Empty(t, f.cancelled)
+		assert.Empty(t, f.comments)
+	})
+
+	t.Run("reports a failed cancel", func(t *testing.T) {
+		f := &fakePullRequest{active: &api.TaskResponse{ID: "task-running"}, failCancel: true}
+		handler, _ := newPullRequestTestHandler(t, f)
+		WithAssignee("shepherd-bot")(handler)
+
+		handler.handleIssues(context.Background(), assignmentEvent(t, "unassigned", "shepherd-bot"))
+
+		require.Len(t, f.comments, 1)
+		assert.Contains(t, f.comments[0], "could not cancel task task-running")
+	})
+}
@@ -18,29 +18,57 @@ package github
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"strconv"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 
 	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/forge"
+	"github.com/NissesSenap/shepherd/pkg/logging"
 )
 
 // TaskMetadata stores the GitHub context needed to post comments when
-// a callback arrives for a completed task.
+// a callback arrives for a completed task. It is kept in a
+// TaskMetadataStore, which may persist it as JSON.
 type TaskMetadata struct {
-	Owner       string
-	Repo        string
-	IssueNumber int
+	Owner       string `json:"owner"`
+	Repo        string `json:"repo"`
+	IssueNumber int    `json:"issueNumber"`
+	// Verification is true for post-merge verification tasks, which report
+	// a verdict instead of a PR.
+	Verification bool `json:"verification,omitempty"`
+	// PullRequest is true for tasks of an existing PR, numbered
+	// IssueNumber, which push to its branch instead of opening a PR.
+	PullRequest bool `json:"pullRequest,omitempty"`
+	// Mode is the task's mode from the mention's command, "" if none was
+	// given. Plan and review tasks report instead of changing code.
+	Mode string `json:"mode,omitempty"`
+	// ReactTo is the ID of the mention that triggered the task, which is
+	// reacted to instead of acknowledged with a comment; 0 for comments.
+	// ReactToReview is true if it is a review comment on a PR's diff.
+	ReactTo       int64 `json:"reactTo,omitempty"`
+	ReactToReview bool  `json:"reactToReview,omitempty"`
+	// CorrelationID is the task's correlation ID, if known.
+	CorrelationID string `json:"correlationID,omitempty"`
+	// AckURL is the URL of the comment that acknowledged the task, which
+	// the task's later comments link back to.
+	AckURL string `json:"ackURL,omitempty"`
+	// AckCommentID is the ID of the acknowledgment comment, 0 if the task
+	// was not acknowledged with a comment, and AckedAt is when it was
+	// posted. ProgressAt is when live progress last edited it.
+	AckCommentID int64     `json:"ackCommentID,omitempty"`
+	AckedAt      time.Time `json:"ackedAt,omitzero"`
+	ProgressAt   time.Time `json:"progressAt,omitzero"`
+	// Lineage relates the task to an earlier task of the issue, such as
+	// "retries task-abc, which failed".
+	Lineage string `json:"lineage,omitempty"`
 }
 
 // CallbackHandler handles callback notifications from the Shepherd API.
@@ -49,29 +77,123 @@ type CallbackHandler struct {
 	ghClient  *Client
 	apiClient *APIClient
 	log       logr.Logger
+	prConfig  PRConfig
+	guard     *EventGuard // nil handles callbacks without limits
+	// timeoutWarnings posts a comment when a task is about to time out.
+	timeoutWarnings bool
+	// liveProgress edits the acknowledgment comment with the task's
+	// progress, and replaces it with the result.
+	liveProgress bool
+	// handle is the mention that triggers a new task, as comments tell
+	// users.
+	handle string
+
+	// secondarySecret is also accepted, so the secret can be rotated
+	// without rejecting callbacks signed with the other one.
+	secondarySecret string
+	// publicKeys verify Ed25519 signatures of callbacks; without them
+	// only HMAC signatures are accepted.
+	publicKeys []ed25519.PublicKey
+
+	// tasks holds the metadata of the tasks being worked on; the API
+	// fallback recovers part of it for tasks it does not know.
+	tasks TaskMetadataStore
+
+	mu sync.Mutex
+	// pendingAcks holds the tasks whose acknowledgment comment could not
+	// be posted yet.
+	pendingAcks map[string]TaskMetadata
+	// results holds when the result comment of recently finished tasks
+	// was posted; see claimResult.
+	results map[string]time.Time
+}
+
+// CallbackOption configures optional CallbackHandler behavior.
+type CallbackOption func(*CallbackHandler)
+
+// WithPRConfig enables labeling and reviewer assignment on PRs reported
+// by completed tasks.
+func WithPRConfig(cfg PRConfig) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.prConfig = cfg
+	}
+}
 
-	// In-memory cache for fast lookup; API fallback handles restarts
-	mu    sync.RWMutex
-	tasks map[string]TaskMetadata
+// WithSecondaryCallbackSecret also accepts callbacks signed with secret, for
+// rotating the callback secret.
+func WithSecondaryCallbackSecret(secret string) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.secondarySecret = secret
+	}
+}
+
+// WithCallbackPublicKeys also accepts callbacks with an Ed25519 signature
+// made with the private key of any of keys, so the adapter needs no shared
+// secret.
+func WithCallbackPublicKeys(keys ...ed25519.PublicKey) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.publicKeys = keys
+	}
+}
+
+// WithCallbackGuard handles callbacks through guard, which limits their
+// duration and how many run at once.
+func WithCallbackGuard(guard *EventGuard) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.guard = guard
+	}
+}
+
+// WithTimeoutWarningComments posts a comment on the issue when the API
+// warns that a task is about to time out.
+func WithTimeoutWarningComments(enabled bool) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.timeoutWarnings = enabled
+	}
+}
+
+// WithCallbackMentionHandle tells users to mention @handle instead of
+// @shepherd to trigger a new attempt; see WithMentionHandle.
+func WithCallbackMentionHandle(handle string) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.handle = handle
+	}
+}
+
+// WithTaskMetadataStore keeps task metadata in store instead of in
+// memory, so it survives restarts and can be shared between replicas.
+func WithTaskMetadataStore(store TaskMetadataStore) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.tasks = store
+	}
 }
 
 // NewCallbackHandler creates a new callback handler.
-func NewCallbackHandler(secret string, ghClient *Client, apiClient *APIClient, log logr.Logger) *CallbackHandler {
-	return &CallbackHandler{
-		secret:    secret,
-		ghClient:  ghClient,
-		apiClient: apiClient,
-		log:       log,
-		tasks:     make(map[string]TaskMetadata),
+func NewCallbackHandler(
+	secret string, ghClient *Client, apiClient *APIClient, log logr.Logger, opts ...CallbackOption,
+) *CallbackHandler {
+	h := &CallbackHandler{
+		secret:      secret,
+		ghClient:    ghClient,
+		apiClient:   apiClient,
+		log:         log,
+		tasks:       newMemoryTaskStore(),
+		pendingAcks: make(map[string]TaskMetadata),
+		results:     make(map[string]time.Time),
+		handle:      DefaultMentionHandle,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 // RegisterTask stores metadata for a task so that callback notifications
 // can be routed back to the correct GitHub issue.
-func (h *CallbackHandler) RegisterTask(taskID string, meta TaskMetadata) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-	h.tasks[taskID] = meta
+func (h *CallbackHandler) RegisterTask(ctx context.Context, taskID string, meta TaskMetadata) {
+	if err := h.tasks.Put(ctx, taskID, meta); err != nil {
+		h.log.Error(err, "failed to store task metadata", logging.TaskID, taskID)
+	}
 }
 
 // ServeHTTP handles callback requests from the Shepherd API.
@@ -89,9 +211,9 @@ func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify HMAC signature
-	signature := r.Header.Get("X-Shepherd-Signature")
-	if !h.verifySignature(body, signature) {
+	// Verify the signature. The API sends one header per secret and key,
+	// two secrets during a rotation.
+	if !h.verifySignature(body, r.Header.Values("X-Shepherd-Signature")...) {
 		h.log.Info("callback signature verification failed")
 		http.Error(w, "invalid signature", http.StatusUnauthorized)
 		return
@@ -105,81 +227,79 @@ func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.log.Info("received callback", "taskID", payload.TaskID, "event", payload.Event)
+	h.log.Info("received callback", logging.TaskID, payload.TaskID, logging.CorrelationID, payload.CorrelationID,
+		"event", payload.Event)
 
 	// Handle the callback
-	h.handleCallback(r.Context(), &payload)
+	if !h.guard.Run(r.Context(), "callback", func(ctx context.Context) { h.handleCallback(ctx, &payload) }) {
+		// The API retries terminal callbacks that were not accepted.
+		http.Error(w, "too many callbacks in progress", http.StatusServiceUnavailable)
+		return
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
-// verifySignature verifies the HMAC-SHA256 signature from the API.
-func (h *CallbackHandler) verifySignature(body []byte, signature string) bool {
-	if h.secret == "" {
-		return true // No verification if no secret
-	}
-
-	if !strings.HasPrefix(signature, "sha256=") {
-		return false
-	}
-
-	mac := hmac.New(sha256.New, []byte(h.secret))
-	mac.Write(body)
-	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
-
-	return hmac.Equal([]byte(expected), []byte(signature))
+// verifySignature verifies the signatures from the API. It passes if any
+// of them was made with either secret or with the key of a public key.
+func (h *CallbackHandler) verifySignature(body []byte, signatures ...string) bool {
+	v := api.CallbackVerifier{Secrets: []string{h.secret, h.secondarySecret}, PublicKeys: h.publicKeys}
+	return v.Verify(body, signatures...)
 }
 
 // resolveTaskMetadata looks up task metadata from cache, falling back to
 // the Shepherd API if not found (e.g., after a restart).
 func (h *CallbackHandler) resolveTaskMetadata(ctx context.Context, taskID string) (TaskMetadata, bool) {
-	// Check in-memory cache first
-	h.mu.RLock()
-	meta, ok := h.tasks[taskID]
-	h.mu.RUnlock()
-	if ok {
+	meta, ok, err := h.tasks.Get(ctx, taskID)
+	if err != nil {
+		h.log.Error(err, "failed to read task metadata, recovering it from the API", logging.TaskID, taskID)
+	} else if ok {
 		return meta, true
 	}
 
 	// Fallback: query the Shepherd API for task details
 	if h.apiClient == nil {
-		h.log.Info("no API client configured, cannot recover task metadata", "taskID", taskID)
+		h.log.Info("no API client configured, cannot recover task metadata", logging.TaskID, taskID)
 		return TaskMetadata{}, false
 	}
 
 	task, err := h.apiClient.GetTask(ctx, taskID)
 	if err != nil {
-		h.log.Error(err, "failed to fetch task from API for callback", "taskID", taskID)
+		h.log.Error(err, "failed to fetch task from API for callback", logging.TaskID, taskID)
 		return TaskMetadata{}, false
 	}
 
 	// Parse owner/repo/issue from sourceURL (e.g., "https://github.com/org/repo/issues/42")
-	meta, err = parseSourceURL(task.Task.SourceURL)
+	meta, err = parseSourceURL(h.ghClient.pathPrefix(), task.Task.SourceURL)
 	if err != nil {
-		h.log.Error(err, "failed to parse sourceURL from task", "taskID", taskID, "sourceURL", task.Task.SourceURL)
+		h.log.Error(err, "failed to parse sourceURL from task", logging.TaskID, taskID, "sourceURL", task.Task.SourceURL)
 		return TaskMetadata{}, false
 	}
 
-	// Cache for future callbacks on the same task
-	h.RegisterTask(taskID, meta)
+	meta.Verification = task.Task.SourceType == api.SourceTypeVerification
+	meta.PullRequest = task.Task.SourceType == api.SourceTypePullRequest
+
+	// Store for future callbacks on the same task
+	h.RegisterTask(ctx, taskID, meta)
 	h.log.Info("recovered task metadata from API",
-		"taskID", taskID, "owner", meta.Owner, "repo", meta.Repo, "issue", meta.IssueNumber)
+		logging.TaskID, taskID, "owner", meta.Owner, "repo", meta.Repo, "issue", meta.IssueNumber)
 	return meta, true
 }
 
 // parseSourceURL extracts owner, repo, and issue number from a GitHub issue URL.
-// Expected format: https://github.com/{owner}/{repo}/issues/{number}
-func parseSourceURL(sourceURL string) (TaskMetadata, error) {
+// Expected format: https://github.com/{owner}/{repo}/issues/{number}, or
+// .../pull/{number} for tasks of a PR, with basePath between host and owner
+// on instances served below a path.
+func parseSourceURL(basePath, sourceURL string) (TaskMetadata, error) {
 	if sourceURL == "" {
 		return TaskMetadata{}, fmt.Errorf("empty sourceURL")
 	}
-	u, err := url.Parse(sourceURL)
+	// Path: /owner/repo/issues/42
+	parts, err := forge.SplitPath(basePath, sourceURL)
 	if err != nil {
 		return TaskMetadata{}, fmt.Errorf("invalid sourceURL: %w", err)
 	}
-	// Path: /owner/repo/issues/42
-	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
-	if len(parts) < 4 || parts[2] != "issues" {
+	if len(parts) < 4 || (parts[2] != "issues" && parts[2] != "pull") {
 		return TaskMetadata{}, fmt.Errorf("unexpected sourceURL format: %s", sourceURL)
 	}
 	issueNumber, err := strconv.Atoi(parts[3])
@@ -193,26 +313,52 @@ func parseSourceURL(sourceURL string) (TaskMetadata, error) {
 	}, nil
 }
 
+// prURLFromDetails returns the PR URL from callback details. The API sends
+// "pr_url"; "prURL" is still accepted from older senders.
+func prURLFromDetails(details map[string]any) string {
+	for _, key := range []string{"pr_url", "prURL"} {
+		if v, ok := details[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 // handleCallback processes the callback and posts appropriate GitHub comments.
 func (h *CallbackHandler) handleCallback(ctx context.Context, payload *api.CallbackPayload) {
 	// Look up task metadata (cache + API fallback)
 	meta, ok := h.resolveTaskMetadata(ctx, payload.TaskID)
 	if !ok {
-		h.log.Info("unable to resolve task metadata, cannot post comment", "taskID", payload.TaskID)
+		h.log.Info("unable to resolve task metadata, cannot post comment", logging.TaskID, payload.TaskID)
+		return
+	}
+
+	terminal := payload.Event == api.EventCompleted || payload.Event == api.EventFailed ||
+		payload.Event == api.EventCancelled
+	if terminal && !h.claimResult(payload.TaskID, time.Now()) {
+		h.log.Info("result comment already posted", logging.TaskID, payload.TaskID, "event", payload.Event)
 		return
 	}
 
 	var comment string
 	switch payload.Event {
 	case api.EventCompleted:
-		prURL := ""
-		if v, ok := payload.Details["prURL"].(string); ok {
-			prURL = v
-		}
-		if prURL != "" {
-			comment = formatCompleted(prURL)
-		} else {
-			comment = "Shepherd completed the task successfully."
+		prURL := prURLFromDetails(payload.Details)
+		summary, _ := payload.Details["summary"].(string)
+		switch {
+		case meta.Verification:
+			comment = formatVerificationPassed(payload.Message)
+		case meta.Mode == api.ModePlan || meta.Mode == api.ModeReview:
+			comment = formatReport(meta.Mode, summary)
+		case meta.PullRequest && prURL != "":
+			// Plan and review tasks of a PR push nothing, so a task
+			// recovered without its mode must not claim it pushed.
+			comment = formatPushed(summary)
+		case prURL != "":
+			h.decoratePR(ctx, prURL)
+			comment = formatCompleted(prURL, summary)
+		default:
+			comment = formatCompletedWithoutPR(summary)
 		}
 
 	case api.EventFailed:
@@ -222,11 +368,32 @@ func (h *CallbackHandler) handleCallback(ctx context.Context, payload *api.Callb
 		if errorMsg == "" {
 			errorMsg = "Task failed"
 		}
-		comment = formatFailed(errorMsg)
+		if meta.Verification {
+			comment = formatVerificationFailed(errorMsg)
+		} else {
+			comment = formatFailed(errorMsg, h.handle)
+		}
+
+	case api.EventCancelled:
+		comment = formatCancelled(payload.Message, h.handle)
 
-	case api.EventStarted, api.EventProgress:
-		// Don't post comments for intermediate events
+	case api.EventProgress:
+		if warning, _ := payload.Details[api.TimeoutWarningDetail].(bool); warning && h.timeoutWarnings {
+			deadline, _ := payload.Details["deadline"].(string)
+			comment = formatTimeoutWarning(payload.TaskID, deadline)
+			break
+		}
 		h.log.V(1).Info("ignoring intermediate event", "event", payload.Event)
+		h.postPendingAck(ctx, payload.TaskID)
+		h.updateProgress(ctx, payload, time.Now())
+		return
+
+	case api.EventStarted:
+		// Don't post comments for intermediate events, other than an
+		// acknowledgment that could not be posted when the task was created.
+		h.log.V(1).Info("ignoring intermediate event", "event", payload.Event)
+		h.postPendingAck(ctx, payload.TaskID)
+		h.updateProgress(ctx, payload, time.Now())
 		return
 
 	default:
@@ -235,16 +402,74 @@ func (h *CallbackHandler) handleCallback(ctx context.Context, payload *api.Callb
 	}
 
 	// Clean up task metadata for terminal events
-	if payload.Event == api.EventCompleted || payload.Event == api.EventFailed {
-		h.mu.Lock()
-		delete(h.tasks, payload.TaskID)
-		h.mu.Unlock()
+	if terminal {
+		if err := h.tasks.Delete(ctx, payload.TaskID); err != nil {
+			h.log.Error(err, "failed to delete task metadata", logging.TaskID, payload.TaskID)
+		}
+	}
+	if _, pending := h.takePendingAck(payload.TaskID); pending {
+		comment = withAcknowledgment(comment, payload.TaskID)
+	}
+	comment = withLinks(comment, payload.Links)
+	if terminal && h.liveComment(meta) {
+		// The result replaces the acknowledgment, so it has nothing to
+		// link back to.
+		header := meta
+		header.AckURL = ""
+		err := h.ghClient.EditComment(ctx, meta.Owner, meta.Repo, meta.AckCommentID,
+			withTaskMarker(withThreadHeader(comment, payload.TaskID, header), payload.TaskID, payload.CorrelationID))
+		if err == nil {
+			h.reactToResult(ctx, payload.TaskID, meta, payload.Event)
+			return
+		}
+		h.log.Error(err, "failed to replace acknowledgment comment with the result, posting it instead",
+			logging.TaskID, payload.TaskID)
 	}
 
+	comment = withTaskMarker(withThreadHeader(comment, payload.TaskID, meta), payload.TaskID, payload.CorrelationID)
 	if err := h.ghClient.PostComment(ctx, meta.Owner, meta.Repo, meta.IssueNumber, comment); err != nil {
 		h.log.Error(err, "failed to post callback comment",
-			"taskID", payload.TaskID,
+			logging.TaskID, payload.TaskID,
 			"event", payload.Event,
 		)
+		if terminal {
+			// Let a retried callback or the next reconciliation post it.
+			h.releaseResult(payload.TaskID)
+		}
+		return
+	}
+	if terminal {
+		h.reactToResult(ctx, payload.TaskID, meta, payload.Event)
+	}
+}
+
+// resultMemory is how long the adapter remembers posting the result
+// comment of a task. It outlasts the API server's callback retries, so a
+// retry that arrives after the result was posted by reconciliation, or the
+// other way around, is not posted twice.
+const resultMemory = 2 * time.Hour
+
+// claimResult records that the result comment of taskID is posted at now,
+// and reports false if it already was.
+func (h *CallbackHandler) claimResult(taskID string, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id, at := range h.results {
+		if now.Sub(at) > resultMemory {
+			delete(h.results, id)
+		}
+	}
+	if _, posted := h.results[taskID]; posted {
+		return false
 	}
+	h.results[taskID] = now
+	return true
+}
+
+// releaseResult forgets the claim on the result comment of taskID, after
+// posting it failed.
+func (h *CallbackHandler) releaseResult(taskID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.results, taskID)
 }
@@ -19,6 +19,7 @@ package github
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -32,6 +33,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/NissesSenap/shepherd/pkg/api"
+	"strings"
 )
 
 func signedCallbackRequest(t *testing.T, secret string, payload any) *http.Request {
@@ -76,6 +78,39 @@ func TestCallbackHandler_SignatureVerification(t *testing.T) {
 		h := NewCallbackHandler("", nil, nil, ctrl.Log.WithName("test"))
 		assert.True(t, h.verifySignature([]byte(`{}`), ""))
 	})
+
+	t.Run("secondary secret during rotation", func(t *testing.T) {
+		h := NewCallbackHandler("new-secret", nil, nil, ctrl.Log.WithName("test"),
+			WithSecondaryCallbackSecret(secret))
+		body := []byte(`{"taskID":"abc","event":"completed"}`)
+		sign := func(key string) string {
+			mac := hmac.New(sha256.New, []byte(key))
+			mac.Write(body)
+			return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		}
+
+		assert.True(t, h.verifySignature(body, sign(secret)), "old secret")
+		assert.True(t, h.verifySignature(body, sign("new-secret")), "new secret")
+		assert.True(t, h.verifySignature(body, sign("unknown"), sign("new-secret")), "any signature")
+		assert.False(t, h.verifySignature(body, sign("unknown")))
+		assert.False(t, h.verifySignature(body))
+	})
+
+	t.Run("ed25519 public key", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		_, otherPriv, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		h := NewCallbackHandler("", nil, nil, ctrl.Log.WithName("test"), WithCallbackPublicKeys(pub))
+		body := []byte(`{"taskID":"abc","event":"completed"}`)
+
+		assert.True(t, h.verifySignature(body, "sha256=unknown", api.SignCallbackEd25519(priv, body)))
+		assert.False(t, h.verifySignature(body, api.SignCallbackEd25519(otherPriv, body)), "other key")
+		assert.False(t, h.verifySignature([]byte(`{"taskID":"abc","event":"failed"}`), api.SignCallbackEd25519(priv, body)),
+			"tampered body")
+		assert.False(t, h.verifySignature(body), "a key requires a signature")
+	})
 }
 
 func TestCallbackHandler_ServeHTTP(t *testing.T) {
@@ -129,7 +164,7 @@ func TestCallbackHandler_ServeHTTP(t *testing.T) {
 		handler := NewCallbackHandler(secret, ghClient, nil, ctrl.Log.WithName("test"))
 
 		// Register task metadata
-		handler.RegisterTask("task-123", TaskMetadata{
+		handler.RegisterTask(context.Background(), "task-123", TaskMetadata{
 			Owner:       "org",
 			Repo:        "repo",
 			IssueNumber: 42,
@@ -150,9 +185,7 @@ func TestCallbackHandler_ServeHTTP(t *testing.T) {
 		assert.Contains(t, postedComment, "completed")
 
 		// Task metadata should be cleaned up
-		handler.mu.RLock()
-		_, exists := handler.tasks["task-123"]
-		handler.mu.RUnlock()
+		_, exists := storedTask(handler, "task-123")
 		assert.False(t, exists)
 	})
 }
@@ -160,15 +193,13 @@ func TestCallbackHandler_ServeHTTP(t *testing.T) {
 func TestCallbackHandler_TaskMetadata(t *testing.T) {
 	handler := NewCallbackHandler("", nil, nil, ctrl.Log.WithName("test"))
 
-	handler.RegisterTask("task-123", TaskMetadata{
+	handler.RegisterTask(context.Background(), "task-123", TaskMetadata{
 		Owner:       "test-org",
 		Repo:        "test-repo",
 		IssueNumber: 42,
 	})
 
-	handler.mu.RLock()
-	meta, ok := handler.tasks["task-123"]
-	handler.mu.RUnlock()
+	meta, ok := storedTask(handler, "task-123")
 
 	assert.True(t, ok)
 	assert.Equal(t, "test-org", meta.Owner)
@@ -178,7 +209,7 @@ func TestCallbackHandler_TaskMetadata(t *testing.T) {
 
 func TestParseSourceURL(t *testing.T) {
 	t.Run("valid issue URL", func(t *testing.T) {
-		meta, err := parseSourceURL("https://github.com/myorg/myrepo/issues/42")
+		meta, err := parseSourceURL("", "https://github.com/myorg/myrepo/issues/42")
 		require.NoError(t, err)
 		assert.Equal(t, "myorg", meta.Owner)
 		assert.Equal(t, "myrepo", meta.Repo)
@@ -186,25 +217,46 @@ func TestParseSourceURL(t *testing.T) {
 	})
 
 	t.Run("empty URL", func(t *testing.T) {
-		_, err := parseSourceURL("")
+		_, err := parseSourceURL("", "")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "empty sourceURL")
 	})
 
+	t.Run("pull request URL", func(t *testing.T) {
+		meta, err := parseSourceURL("", "https://github.com/myorg/myrepo/pull/7")
+		require.NoError(t, err)
+		assert.Equal(t, TaskMetadata{Owner: "myorg", Repo: "myrepo", IssueNumber: 7}, meta)
+	})
+
 	t.Run("non-issue URL", func(t *testing.T) {
-		_, err := parseSourceURL("https://github.com/myorg/myrepo/pull/42")
+		_, err := parseSourceURL("", "https://github.com/myorg/myrepo/wiki/42")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "unexpected sourceURL format")
 	})
 
 	t.Run("invalid issue number", func(t *testing.T) {
-		_, err := parseSourceURL("https://github.com/myorg/myrepo/issues/abc")
+		_, err := parseSourceURL("", "https://github.com/myorg/myrepo/issues/abc")
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid issue number")
 	})
 
 	t.Run("too few path segments", func(t *testing.T) {
-		_, err := parseSourceURL("https://github.com/myorg")
+		_, err := parseSourceURL("", "https://github.com/myorg")
+		require.Error(t, err)
+	})
+
+	t.Run("enterprise issue URL", func(t *testing.T) {
+		meta, err := parseSourceURL("", "https://ghe.example.com/myorg/myrepo/issues/42")
+		require.NoError(t, err)
+		assert.Equal(t, TaskMetadata{Owner: "myorg", Repo: "myrepo", IssueNumber: 42}, meta)
+	})
+
+	t.Run("enterprise issue URL below a base path", func(t *testing.T) {
+		meta, err := parseSourceURL("/github", "https://proxy.example.com/github/myorg/myrepo/issues/42")
+		require.NoError(t, err)
+		assert.Equal(t, TaskMetadata{Owner: "myorg", Repo: "myrepo", IssueNumber: 42}, meta)
+
+		_, err = parseSourceURL("/github", "https://proxy.example.com/myorg/myrepo/issues/42")
 		require.Error(t, err)
 	})
 }
@@ -226,7 +278,7 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		ghClient := newTestClientFromServer(t, ghServer)
 		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
 
-		handler.RegisterTask("task-1", TaskMetadata{
+		handler.RegisterTask(context.Background(), "task-1", TaskMetadata{
 			Owner: "org", Repo: "repo", IssueNumber: 10,
 		})
 
@@ -240,6 +292,74 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		assert.Contains(t, postedComment, "completed")
 	})
 
+	t.Run("completed task of a PR posts pushed comment", func(t *testing.T) {
+		var postedComment string
+		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/org/repo/issues/7/comments" {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				postedComment = body["body"]
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":1}`))
+				return
+			}
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}))
+		defer ghServer.Close()
+
+		ghClient := newTestClientFromServer(t, ghServer)
+		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"),
+			WithPRConfig(PRConfig{Labels: []string{"shepherd"}}))
+
+		handler.RegisterTask(context.Background(), "task-pr", TaskMetadata{
+			Owner: "org", Repo: "repo", IssueNumber: 7, PullRequest: true,
+		})
+
+		handler.handleCallback(context.Background(), &api.CallbackPayload{
+			TaskID: "task-pr",
+			Event:  api.EventCompleted,
+			Details: map[string]any{
+				"pr_url":  "https://github.com/org/repo/pull/7",
+				"summary": "Renamed the helper as requested.",
+			},
+		})
+
+		// The PR is not labeled again, as PRs shepherd opened are
+		assert.Contains(t, postedComment, "pushed its changes to this pull request")
+		assert.Contains(t, postedComment, "> Renamed the helper as requested.")
+		assert.NotContains(t, postedComment, "Pull Request: ")
+	})
+
+	t.Run("completed review task of a PR posts its report", func(t *testing.T) {
+		var postedComment string
+		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				postedComment = body["body"]
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			}
+		}))
+		defer ghServer.Close()
+
+		ghClient := newTestClientFromServer(t, ghServer)
+		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
+
+		handler.RegisterTask(context.Background(), "task-review", TaskMetadata{
+			Owner: "org", Repo: "repo", IssueNumber: 7, PullRequest: true, Mode: api.ModeReview,
+		})
+
+		handler.handleCallback(context.Background(), &api.CallbackPayload{
+			TaskID:  "task-review",
+			Event:   api.EventCompleted,
+			Details: map[string]any{"summary": "- The login handler ignores errors"},
+		})
+
+		assert.Contains(t, postedComment, "Shepherd has reviewed the code.\n\n- The login handler ignores errors")
+		assert.NotContains(t, postedComment, "pushed")
+	})
+
 	t.Run("completed event without PR URL posts generic success", func(t *testing.T) {
 		var postedComment string
 		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -256,7 +376,7 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		ghClient := newTestClientFromServer(t, ghServer)
 		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
 
-		handler.RegisterTask("task-2", TaskMetadata{
+		handler.RegisterTask(context.Background(), "task-2", TaskMetadata{
 			Owner: "org", Repo: "repo", IssueNumber: 10,
 		})
 
@@ -284,7 +404,7 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		ghClient := newTestClientFromServer(t, ghServer)
 		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
 
-		handler.RegisterTask("task-3", TaskMetadata{
+		handler.RegisterTask(context.Background(), "task-3", TaskMetadata{
 			Owner: "org", Repo: "repo", IssueNumber: 10,
 		})
 
@@ -295,11 +415,80 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		})
 
 		assert.Contains(t, postedComment, "Build failed")
+		assert.NotContains(t, postedComment, "[View task]")
 
 		// Task metadata should be cleaned up
-		handler.mu.RLock()
-		_, exists := handler.tasks["task-3"]
-		handler.mu.RUnlock()
+		_, exists := storedTask(handler, "task-3")
+		assert.False(t, exists)
+	})
+
+	t.Run("links from the API server are appended", func(t *testing.T) {
+		var postedComment string
+		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				postedComment = body["body"]
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			}
+		}))
+		defer ghServer.Close()
+
+		ghClient := newTestClientFromServer(t, ghServer)
+		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
+
+		handler.RegisterTask(context.Background(), "task-links", TaskMetadata{
+			Owner: "org", Repo: "repo", IssueNumber: 10,
+		})
+
+		handler.handleCallback(context.Background(), &api.CallbackPayload{
+			TaskID:        "task-links",
+			Event:         api.EventFailed,
+			Message:       "Build failed",
+			CorrelationID: "run-1",
+			Links: &api.TaskLinks{
+				Dashboard: "https://shepherd.example.com/tasks/task-links",
+				Logs:      "https://logs.example.com/?task=task-links",
+			},
+		})
+
+		assert.True(t, strings.HasSuffix(postedComment,
+			"\n\n[View task](https://shepherd.example.com/tasks/task-links) · [Logs](https://logs.example.com/?task=task-links)"+
+				"\n\n<!-- shepherd-task:task-links correlation:run-1 -->"),
+			postedComment)
+	})
+
+	t.Run("cancelled event posts cancelled comment", func(t *testing.T) {
+		var postedComment string
+		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				postedComment = body["body"]
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			}
+		}))
+		defer ghServer.Close()
+
+		ghClient := newTestClientFromServer(t, ghServer)
+		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
+
+		handler.RegisterTask(context.Background(), "task-cancelled", TaskMetadata{
+			Owner: "org", Repo: "repo", IssueNumber: 10,
+		})
+
+		handler.handleCallback(context.Background(), &api.CallbackPayload{
+			TaskID:  "task-cancelled",
+			Event:   api.EventCancelled,
+			Message: "Task was deleted before it finished",
+		})
+
+		assert.Contains(t, postedComment, "was cancelled")
+		assert.Contains(t, postedComment, "Task was deleted before it finished")
+
+		_, exists := storedTask(handler, "task-cancelled")
 		assert.False(t, exists)
 	})
 
@@ -316,7 +505,7 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		ghClient := newTestClientFromServer(t, ghServer)
 		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
 
-		handler.RegisterTask("task-4", TaskMetadata{
+		handler.RegisterTask(context.Background(), "task-4", TaskMetadata{
 			Owner: "org", Repo: "repo", IssueNumber: 10,
 		})
 
@@ -328,9 +517,7 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		assert.False(t, commentPosted)
 
 		// Task metadata should NOT be cleaned up for intermediate events
-		handler.mu.RLock()
-		_, exists := handler.tasks["task-4"]
-		handler.mu.RUnlock()
+		_, exists := storedTask(handler, "task-4")
 		assert.True(t, exists)
 	})
 
@@ -347,7 +534,7 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		ghClient := newTestClientFromServer(t, ghServer)
 		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
 
-		handler.RegisterTask("task-5", TaskMetadata{
+		handler.RegisterTask(context.Background(), "task-5", TaskMetadata{
 			Owner: "org", Repo: "repo", IssueNumber: 10,
 		})
 
@@ -360,6 +547,39 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		assert.False(t, commentPosted)
 	})
 
+	t.Run("timeout warning posts comment when enabled", func(t *testing.T) {
+		var postedComments []string
+		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				postedComments = append(postedComments, body["body"])
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			}
+		}))
+		defer ghServer.Close()
+
+		ghClient := newTestClientFromServer(t, ghServer)
+		warning := &api.CallbackPayload{
+			TaskID:  "task-warn",
+			Event:   api.EventProgress,
+			Details: map[string]any{api.TimeoutWarningDetail: true, "deadline": "2026-03-01T12:30:00Z"},
+		}
+		meta := TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 10}
+
+		silent := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
+		silent.RegisterTask(context.Background(), "task-warn", meta)
+		silent.handleCallback(context.Background(), warning)
+		assert.Empty(t, postedComments)
+
+		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"), WithTimeoutWarningComments(true))
+		handler.RegisterTask(context.Background(), "task-warn", meta)
+		handler.handleCallback(context.Background(), warning)
+		require.Len(t, postedComments, 1)
+		assert.Contains(t, postedComments[0], "task-warn is about to time out at 2026-03-01 12:30 UTC")
+	})
+
 	t.Run("API fallback resolves task metadata after restart", func(t *testing.T) {
 		var postedComment string
 		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -415,7 +635,7 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		ghClient := newTestClientFromServer(t, ghServer)
 		handler := NewCallbackHandler("", ghClient, nil, ctrl.Log.WithName("test"))
 
-		handler.RegisterTask("task-6", TaskMetadata{
+		handler.RegisterTask(context.Background(), "task-6", TaskMetadata{
 			Owner: "org", Repo: "repo", IssueNumber: 10,
 		})
 
@@ -441,14 +661,14 @@ func TestCallbackHandler_HandleCallback(t *testing.T) {
 		}))
 		defer ghServer.Close()
 
-		// API server returns task with invalid sourceURL (PR instead of issue)
+		// API server returns task with invalid sourceURL (a branch instead of an issue)
 		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			assert.Equal(t, "/api/v1/tasks/task-bad-url", r.URL.Path)
 			w.Header().Set("Content-Type", "application/json")
 			_, _ = w.Write([]byte(`{
 				"id":"task-bad-url",
 				"status":{"phase":"Completed"},
-				"task":{"sourceURL":"https://github.com/org/repo/pull/42"}
+				"task":{"sourceURL":"https://github.com/org/repo/tree/main"}
 			}`))
 		}))
 		defer apiServer.Close()
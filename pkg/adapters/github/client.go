@@ -21,33 +21,110 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
 	gh "github.com/google/go-github/v75/github"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/forge"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
 )
 
 // Client wraps the GitHub API client with app authentication.
 type Client struct {
-	gh             *gh.Client
-	installationID int64
+	gh *gh.Client
+	// checkCredentials verifies the app's credentials; nil for clients
+	// built in tests.
+	checkCredentials func(context.Context) error
+	// installations finds the installation of each repository; nil when
+	// the client is bound to one installation.
+	installations *forge.GitHubInstallations
+	// basePath prefixes repository paths in the instance's web URLs; see
+	// forge.BasePath.
+	basePath string
 }
 
-// NewClient creates a new GitHub client authenticated as a GitHub App installation.
-func NewClient(appID, installationID int64, privateKeyPath string) (*Client, error) {
+// NewClient creates a new GitHub client authenticated as a GitHub App
+// installation. With installationID 0, each request is authenticated as the
+// installation on the repository it is about, so the App can be installed
+// on several organizations. baseURL and uploadURL point it at a GitHub
+// Enterprise Server instance; empty means github.com.
+func NewClient(appID, installationID int64, privateKeyPath, baseURL, uploadURL string) (*Client, error) {
 	keyData, err := os.ReadFile(privateKeyPath)
 	if err != nil {
 		return nil, fmt.Errorf("reading private key: %w", err)
 	}
 
-	transport, err := ghinstallation.New(http.DefaultTransport, appID, installationID, keyData)
+	// Installation tokens and API requests all go through the apps
+	// transport's base, so they all wait out rate limits.
+	limits := forge.NewRateLimitTransport(http.DefaultTransport, "github-adapter", ctrl.Log.WithName("github"))
+	apps, err := ghinstallation.NewAppsTransport(limits, appID, keyData)
+	if err != nil {
+		return nil, fmt.Errorf("creating apps transport: %w", err)
+	}
+	// Resolve the API URL first: installation tokens come from the same API
+	// the client talks to.
+	probe, err := forge.NewGitHubClient(nil, baseURL, uploadURL)
 	if err != nil {
-		return nil, fmt.Errorf("creating installation transport: %w", err)
+		return nil, err
 	}
+	apps.BaseURL = forge.APIURL(probe)
 
-	return &Client{
-		gh:             gh.NewClient(&http.Client{Transport: transport}),
-		installationID: installationID,
-	}, nil
+	c := &Client{basePath: forge.BasePath(baseURL)}
+	var transport http.RoundTripper
+	if installationID != 0 {
+		tokens := ghinstallation.NewFromAppsTransport(apps, installationID)
+		transport = tokens
+		c.checkCredentials = func(ctx context.Context) error {
+			if _, err := tokens.Token(ctx); err != nil {
+				return fmt.Errorf("getting installation token: %w", err)
+			}
+			return nil
+		}
+	} else {
+		c.installations, err = forge.NewGitHubInstallations(apps)
+		if err != nil {
+			return nil, err
+		}
+		transport = c.installations
+		c.checkCredentials = c.installations.CheckCredentials
+	}
+
+	c.gh, err = forge.NewGitHubClient(&http.Client{Transport: tracing.Transport(transport)}, baseURL, uploadURL)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// pathPrefix returns the path repositories are served under, "" on
+// github.com.
+func (c *Client) pathPrefix() string {
+	if c == nil {
+		return ""
+	}
+	return c.basePath
+}
+
+// CheckCredentials reports whether the app can still authenticate: get an
+// installation token, or without a fixed installation, authenticate as the
+// App. The transport reuses its token until it expires, so this rarely
+// reaches GitHub.
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	if c.checkCredentials == nil {
+		return nil
+	}
+	return c.checkCredentials(ctx)
+}
+
+// RememberInstallation records the installation a webhook for owner/repo
+// came from, so requests about the repository need not look it up.
+func (c *Client) RememberInstallation(owner, repo string, id int64) {
+	if c != nil && c.installations != nil {
+		c.installations.Remember(owner, repo, id)
+	}
 }
 
 // newClientFromGH creates a Client from an existing go-github client (for testing).
@@ -57,10 +134,39 @@ func newClientFromGH(ghClient *gh.Client) *Client {
 
 // PostComment posts a comment to an issue or pull request.
 func (c *Client) PostComment(ctx context.Context, owner, repo string, number int, body string) error {
-	comment := &gh.IssueComment{Body: gh.Ptr(body)}
-	_, _, err := c.gh.Issues.CreateComment(ctx, owner, repo, number, comment)
+	_, err := c.CreateComment(ctx, owner, repo, number, body)
+	return err
+}
+
+// CreateComment posts a comment to an issue or pull request and returns
+// the created comment.
+func (c *Client) CreateComment(ctx context.Context, owner, repo string, number int, body string) (*gh.IssueComment, error) {
+	comment, _, err := c.gh.Issues.CreateComment(ctx, owner, repo, number, &gh.IssueComment{Body: gh.Ptr(body)})
+	if err != nil {
+		return nil, fmt.Errorf("creating comment: %w", err)
+	}
+	return comment, nil
+}
+
+// EditComment replaces the body of a comment on an issue or pull request.
+func (c *Client) EditComment(ctx context.Context, owner, repo string, commentID int64, body string) error {
+	if _, _, err := c.gh.Issues.EditComment(ctx, owner, repo, commentID, &gh.IssueComment{Body: gh.Ptr(body)}); err != nil {
+		return fmt.Errorf("editing comment: %w", err)
+	}
+	return nil
+}
+
+// AddCommentReaction reacts to a comment with content, such as "eyes". A
+// review comment on a PR's diff has its own reactions endpoint.
+func (c *Client) AddCommentReaction(ctx context.Context, owner, repo string, commentID int64, review bool, content string) error {
+	var err error
+	if review {
+		_, _, err = c.gh.Reactions.CreatePullRequestCommentReaction(ctx, owner, repo, commentID, content)
+	} else {
+		_, _, err = c.gh.Reactions.CreateIssueCommentReaction(ctx, owner, repo, commentID, content)
+	}
 	if err != nil {
-		return fmt.Errorf("creating comment: %w", err)
+		return fmt.Errorf("adding reaction: %w", err)
 	}
 	return nil
 }
@@ -84,3 +190,236 @@ func (c *Client) ListIssueComments(ctx context.Context, owner, repo string, numb
 	}
 	return allComments, nil
 }
+
+// AddLabels adds labels to an issue or pull request.
+func (c *Client) AddLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	_, _, err := c.gh.Issues.AddLabelsToIssue(ctx, owner, repo, number, labels)
+	if err != nil {
+		return fmt.Errorf("adding labels: %w", err)
+	}
+	return nil
+}
+
+// RequestReviewers requests reviews from users and teams on a pull request.
+// Team reviewers are given as team slugs within the repository's organization.
+func (c *Client) RequestReviewers(ctx context.Context, owner, repo string, number int, users, teams []string) error {
+	req := gh.ReviewersRequest{Reviewers: users, TeamReviewers: teams}
+	_, _, err := c.gh.PullRequests.RequestReviewers(ctx, owner, repo, number, req)
+	if err != nil {
+		return fmt.Errorf("requesting reviewers: %w", err)
+	}
+	return nil
+}
+
+// ListPullRequestFiles returns the paths of all files changed in a pull request.
+func (c *Client) ListPullRequestFiles(ctx context.Context, owner, repo string, number int) ([]string, error) {
+	var files []string
+	opts := &gh.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := c.gh.PullRequests.ListFiles(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing pull request files: %w", err)
+		}
+		for _, f := range page {
+			files = append(files, f.GetFilename())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return files, nil
+}
+
+// GetPullRequest returns a pull request.
+func (c *Client) GetPullRequest(ctx context.Context, owner, repo string, number int) (*gh.PullRequest, error) {
+	pr, _, err := c.gh.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("getting pull request: %w", err)
+	}
+	return pr, nil
+}
+
+// GetPullRequestDiff returns the unified diff of a pull request.
+func (c *Client) GetPullRequestDiff(ctx context.Context, owner, repo string, number int) (string, error) {
+	diff, _, err := c.gh.PullRequests.GetRaw(ctx, owner, repo, number, gh.RawOptions{Type: gh.Diff})
+	if err != nil {
+		return "", fmt.Errorf("getting pull request diff: %w", err)
+	}
+	return diff, nil
+}
+
+// ListReviewComments retrieves all review comments on a pull request, the
+// comments made on lines of its diff.
+func (c *Client) ListReviewComments(ctx context.Context, owner, repo string, number int) ([]*gh.PullRequestComment, error) {
+	var allComments []*gh.PullRequestComment
+	opts := &gh.PullRequestListCommentsOptions{
+		ListOptions: gh.ListOptions{PerPage: 100},
+	}
+	for {
+		comments, resp, err := c.gh.PullRequests.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing review comments: %w", err)
+		}
+		allComments = append(allComments, comments...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return allComments, nil
+}
+
+// GetFileContent returns the decoded content of a file on the repository's
+// default branch. The boolean result is false if the file does not exist.
+func (c *Client) GetFileContent(ctx context.Context, owner, repo, path string) (string, bool, error) {
+	file, _, resp, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("getting %s: %w", path, err)
+	}
+	if file == nil {
+		// Path is a directory
+		return "", false, nil
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return "", false, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return content, true, nil
+}
+
+// GetRepository returns the metadata of a repository.
+func (c *Client) GetRepository(ctx context.Context, owner, repo string) (RepoMetadata, error) {
+	r, _, err := c.gh.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return RepoMetadata{}, fmt.Errorf("getting repository: %w", err)
+	}
+	return RepoMetadata{
+		FullName:      r.GetFullName(),
+		DefaultBranch: r.GetDefaultBranch(),
+		CloneURL:      r.GetCloneURL(),
+		Visibility:    r.GetVisibility(),
+		SizeKB:        r.GetSize(),
+		Archived:      r.GetArchived(),
+	}, nil
+}
+
+// GetPermissionLevel returns the permission a user has on a repository:
+// its role name, such as "maintain" or "triage", if GitHub reports one of
+// the standard roles, and otherwise the base permission "admin", "write",
+// "read" or "none".
+func (c *Client) GetPermissionLevel(ctx context.Context, owner, repo, user string) (string, error) {
+	level, _, err := c.gh.Repositories.GetPermissionLevel(ctx, owner, repo, user)
+	if err != nil {
+		return "", fmt.Errorf("getting permission of %s: %w", user, err)
+	}
+	if slices.Contains(PermissionLevels, level.GetRoleName()) {
+		return level.GetRoleName(), nil
+	}
+	return level.GetPermission(), nil
+}
+
+// RefExists reports whether ref (a branch, tag or commit SHA) exists in a
+// repository.
+func (c *Client) RefExists(ctx context.Context, owner, repo, ref string) (bool, error) {
+	_, resp, err := c.gh.Repositories.GetCommitSHA1(ctx, owner, repo, ref, "")
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity) {
+			return false, nil
+		}
+		return false, fmt.Errorf("resolving ref %s: %w", ref, err)
+	}
+	return true, nil
+}
+
+// enableAutoMergeMutation turns on GitHub's native auto-merge for a pull
+// request. Auto-merge is only exposed through the GraphQL API.
+const enableAutoMergeMutation = `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) {
+    clientMutationId
+  }
+}`
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// EnableAutoMerge enables auto-merge on a pull request so GitHub merges it
+// once all required reviews and status checks pass. mergeMethod is one of
+// "merge", "squash" or "rebase".
+func (c *Client) EnableAutoMerge(ctx context.Context, owner, repo string, number int, mergeMethod string) error {
+	pr, _, err := c.gh.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return fmt.Errorf("getting pull request: %w", err)
+	}
+
+	body := graphQLRequest{
+		Query: enableAutoMergeMutation,
+		Variables: map[string]any{
+			"id":     pr.GetNodeID(),
+			"method": strings.ToUpper(mergeMethod),
+		},
+	}
+	// The GraphQL endpoint is a sibling of the REST base URL:
+	// api.github.com/graphql on github.com, /api/graphql on GHES (/api/v3/).
+	req, err := c.gh.NewRequest(http.MethodPost, "../graphql", body)
+	if err != nil {
+		return fmt.Errorf("building auto-merge request: %w", err)
+	}
+	var resp graphQLResponse
+	// The GraphQL path names no repository to find the installation by.
+	if _, err := c.gh.Do(forge.WithRepository(ctx, owner, repo), req, &resp); err != nil {
+		return fmt.Errorf("enabling auto-merge: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("enabling auto-merge: %s", resp.Errors[0].Message)
+	}
+	return nil
+}
+
+// IsPullRequestMerged reports whether a pull request has been merged.
+func (c *Client) IsPullRequestMerged(ctx context.Context, owner, repo string, number int) (bool, error) {
+	merged, _, err := c.gh.PullRequests.IsMerged(ctx, owner, repo, number)
+	if err != nil {
+		return false, fmt.Errorf("checking pull request merge status: %w", err)
+	}
+	return merged, nil
+}
+
+// FindOrCreateIssue returns the number of the oldest open issue carrying the
+// given label, creating an issue with that title and label if none exists.
+func (c *Client) FindOrCreateIssue(ctx context.Context, owner, repo, title, label, body string) (int, error) {
+	issues, _, err := c.gh.Issues.ListByRepo(ctx, owner, repo, &gh.IssueListByRepoOptions{
+		State:       "open",
+		Labels:      []string{label},
+		Sort:        "created",
+		Direction:   "asc",
+		ListOptions: gh.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("listing issues: %w", err)
+	}
+	if len(issues) > 0 {
+		return issues[0].GetNumber(), nil
+	}
+
+	issue, _, err := c.gh.Issues.Create(ctx, owner, repo, &gh.IssueRequest{
+		Title:  gh.Ptr(title),
+		Body:   gh.Ptr(body),
+		Labels: &[]string{label},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("creating issue: %w", err)
+	}
+	return issue.GetNumber(), nil
+}
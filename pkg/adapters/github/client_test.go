@@ -18,9 +18,16 @@ package github
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	gh "github.com/google/go-github/v75/github"
@@ -73,9 +80,36 @@ func TestClient_ListIssueComments(t *testing.T) {
 	})
 }
 
+func TestClient_EnableAutoMerge(t *testing.T) {
+	t.Run("GraphQL error is returned", func(t *testing.T) {
+		client, srv := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == "/api/graphql" {
+				_, _ = w.Write([]byte(`{"errors":[{"message":"Auto merge is not allowed for this repository"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"number":7,"node_id":"PR_node7"}`))
+		}))
+		defer srv.Close()
+
+		err := client.EnableAutoMerge(context.Background(), "myorg", "myrepo", 7, "squash")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Auto merge is not allowed")
+	})
+
+	t.Run("pull request lookup failure", func(t *testing.T) {
+		client, srv := newTestClient(t, http.NotFoundHandler())
+		defer srv.Close()
+
+		err := client.EnableAutoMerge(context.Background(), "myorg", "myrepo", 7, "squash")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "getting pull request")
+	})
+}
+
 func TestCommentTemplates(t *testing.T) {
 	t.Run("acknowledge", func(t *testing.T) {
-		result := formatAcknowledge("task-abc123")
+		result := formatAcknowledge("task-abc123", "")
 		assert.Contains(t, result, "task-abc123")
 		assert.Contains(t, result, "working on your request")
 	})
@@ -88,18 +122,70 @@ func TestCommentTemplates(t *testing.T) {
 	})
 
 	t.Run("completed", func(t *testing.T) {
-		result := formatCompleted("https://github.com/org/repo/pull/42")
+		result := formatCompleted("https://github.com/org/repo/pull/42", "")
 		assert.Contains(t, result, "https://github.com/org/repo/pull/42")
 		assert.Contains(t, result, "completed")
 	})
 
+	t.Run("completed with summary", func(t *testing.T) {
+		result := formatCompleted("https://github.com/org/repo/pull/42", "Fixed the redirect.\nAdded a test.")
+		assert.Equal(t, "Shepherd has completed the task.\n\nPull Request: https://github.com/org/repo/pull/42\n\n"+
+			"> Fixed the redirect.\n> Added a test.\n\nPlease review the changes.", result)
+	})
+
+	t.Run("completed without PR", func(t *testing.T) {
+		assert.Equal(t, "Shepherd completed the task successfully.", formatCompletedWithoutPR(""))
+		assert.Equal(t, "Shepherd completed the task successfully.\n\n> Nothing to change.",
+			formatCompletedWithoutPR("Nothing to change."))
+	})
+
 	t.Run("failed with message", func(t *testing.T) {
-		result := formatFailed("Build failed")
+		result := formatFailed("Build failed", DefaultMentionHandle)
 		assert.Contains(t, result, "Build failed")
 	})
 
 	t.Run("failed empty message", func(t *testing.T) {
-		result := formatFailed("")
+		result := formatFailed("", DefaultMentionHandle)
 		assert.Contains(t, result, "Unknown error")
 	})
+
+	t.Run("task marker", func(t *testing.T) {
+		assert.Equal(t, "Done.\n\n<!-- shepherd-task:task-abc correlation:run-1 -->",
+			withTaskMarker("Done.", "task-abc", "run-1"))
+		assert.Equal(t, "Done.\n\n<!-- shepherd-task:task-abc -->", withTaskMarker("Done.", "task-abc", ""))
+	})
+}
+
+func TestNewClient_Enterprise(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}), 0o600))
+
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if strings.HasSuffix(r.URL.Path, "/access_tokens") {
+			_, _ = w.Write([]byte(`{"token": "ghs_test", "expires_at": "2099-01-01T00:00:00Z"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(1, 2, keyPath, srv.URL, "")
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/api/uploads/", client.gh.UploadURL.String())
+
+	require.NoError(t, client.PostComment(context.Background(), "myorg", "myrepo", 42, "hi"))
+	// The installation token and the comment both go to the GHES REST API.
+	assert.Equal(t, []string{
+		"/api/v3/app/installations/2/access_tokens",
+		"/api/v3/repos/myorg/myrepo/issues/42/comments",
+	}, paths)
 }
@@ -0,0 +1,92 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileCodeownersPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*", "main.go", true},
+		{"*", "pkg/api/server.go", true},
+		{"*.go", "pkg/api/server.go", true},
+		{"*.go", "README.md", false},
+		{"/docs/", "docs/index.md", true},
+		{"/docs/", "web/docs/index.md", false},
+		{"docs/", "web/docs/index.md", true},
+		{"apps/", "apps/web/main.ts", true},
+		{"docs/*", "docs/index.md", true},
+		{"docs/*", "docs/setup/install.md", false},
+		{"/build/logs", "build/logs/out.log", true},
+		{"/build/logs", "src/build/logs/out.log", false},
+		{"**/logs", "a/b/logs/out.log", true},
+		{"**/logs", "logs/out.log", true},
+		{"api/**/types.go", "api/v1alpha1/types.go", true},
+		{"api/**/types.go", "api/types.go", true},
+		{"Makefile", "sub/Makefile", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"go.mod", "go.mod", true},
+		{"go.mod", "gosmod", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			re, err := compileCodeownersPattern(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.match, re.MatchString(tt.path))
+		})
+	}
+}
+
+func TestParseCodeowners(t *testing.T) {
+	content := `# Default owners
+*       @org/core
+
+# Go code
+*.go    @alice @org/backend  # inline comment
+/docs/  docs@example.com @bob
+/docs/generated/
+`
+	rules := parseCodeowners(content)
+	require.Len(t, rules, 4)
+
+	t.Run("last match wins", func(t *testing.T) {
+		assert.Equal(t, []string{"@alice", "@org/backend"}, rules.ownersFor("pkg/api/server.go"))
+		assert.Equal(t, []string{"docs@example.com", "@bob"}, rules.ownersFor("docs/index.md"))
+	})
+
+	t.Run("falls back to default owners", func(t *testing.T) {
+		assert.Equal(t, []string{"@org/core"}, rules.ownersFor("web/package.json"))
+	})
+
+	t.Run("pattern without owners unsets ownership", func(t *testing.T) {
+		assert.Empty(t, rules.ownersFor("docs/generated/api.md"))
+	})
+
+	t.Run("empty file has no owners", func(t *testing.T) {
+		assert.Empty(t, parseCodeowners("").ownersFor("main.go"))
+	})
+}
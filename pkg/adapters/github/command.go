@@ -0,0 +1,183 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/validate"
+)
+
+// commandUsage describes the grammar parseCommand accepts, after the mention.
+const commandUsage = "[plan|fix|review] [--branch NAME] [--template NAME] [--timeout DURATION] [description]"
+
+// taskCommand is a mention parsed into a task request. A mention reads
+//
+//	@shepherd [plan|fix|review] [--branch NAME] [--template NAME] [--timeout DURATION] [description]
+//
+// Options may be given as --name value or --name=value anywhere on the
+// first line, and "--" ends them. Later lines only add to the description,
+// so text pasted below the request is never read as options.
+type taskCommand struct {
+	// Mode is one of the api task modes, or "" if none was given.
+	Mode string
+	// Branch is the branch the task checks out instead of the default
+	// branch.
+	Branch string
+	// Template is the SandboxTemplate that replaces the default one.
+	Template string
+	// Timeout is the runner timeout, or 0 for the default.
+	Timeout     time.Duration
+	Description string
+}
+
+// command returns the name of the command in a repository
+// configuration's commands: its mode, where no mode is a fix.
+func (c taskCommand) command() string {
+	if c.Mode == "" {
+		return api.ModeFix
+	}
+	return c.Mode
+}
+
+// commandOptions are the options a command accepts.
+var commandOptions = []string{"branch", "template", "timeout"}
+
+// branchNameRegex matches the branch names a command accepts: no spaces or
+// characters git forbids in ref names.
+var branchNameRegex = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+
+// parseCommand parses text, a comment with its mention removed. The error
+// is written for the user who made the mention.
+func parseCommand(text string) (taskCommand, error) {
+	var cmd taskCommand
+	first, rest, _ := strings.Cut(strings.TrimSpace(text), "\n")
+
+	tokens := strings.Fields(first)
+	var modeWord string
+	if len(tokens) > 0 {
+		switch mode := strings.ToLower(tokens[0]); mode {
+		case api.ModePlan, api.ModeFix, api.ModeReview:
+			cmd.Mode = mode
+			modeWord, tokens = tokens[0], tokens[1:]
+		}
+	}
+
+	var words []string
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token == "--" {
+			words = append(words, tokens[i+1:]...)
+			break
+		}
+		name, ok := strings.CutPrefix(token, "--")
+		if !ok {
+			words = append(words, token)
+			continue
+		}
+		name, value, hasValue := strings.Cut(name, "=")
+		if !slices.Contains(commandOptions, name) {
+			return taskCommand{}, fmt.Errorf("unknown option --%s", name)
+		}
+		if !hasValue {
+			if i+1 == len(tokens) {
+				return taskCommand{}, fmt.Errorf("--%s needs a value", name)
+			}
+			i++
+			value = tokens[i]
+		}
+		if err := cmd.setOption(name, value); err != nil {
+			return taskCommand{}, err
+		}
+	}
+
+	// "fix the login bug" is also a description, so the mode stays part
+	// of it unless it stands alone.
+	if len(words) > 0 && modeWord != "" {
+		words = append([]string{modeWord}, words...)
+	}
+	cmd.Description = strings.Join(words, " ")
+	if rest = strings.TrimSpace(rest); rest != "" {
+		cmd.Description = strings.TrimSpace(cmd.Description + "\n" + rest)
+	}
+	return cmd, nil
+}
+
+// setOption sets the option name of a command to value.
+func (c *taskCommand) setOption(name, value string) error {
+	switch name {
+	case "branch":
+		if !branchNameRegex.MatchString(value) || strings.Contains(value, "..") ||
+			strings.HasPrefix(value, "-") || strings.HasPrefix(value, "/") ||
+			strings.HasSuffix(value, "/") || strings.HasSuffix(value, ".lock") {
+			return fmt.Errorf("%q is not a valid branch name", value)
+		}
+		c.Branch = value
+	case "template":
+		if len(validation.IsDNS1123Subdomain(value)) > 0 {
+			return fmt.Errorf("%q is not a valid template name", value)
+		}
+		c.Template = value
+	case "timeout":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a valid timeout, use for example 30m or 2h", value)
+		}
+		if err := validate.Timeout(d); err != nil {
+			return fmt.Errorf("timeout %s: %w", value, err)
+		}
+		c.Timeout = d
+	}
+	return nil
+}
+
+// describe gives a command without a description one, for a mention on
+// subject, such as "issue".
+func (c *taskCommand) describe(subject string) {
+	if c.Description != "" {
+		return
+	}
+	switch c.Mode {
+	case api.ModePlan:
+		c.Description = "Plan the work on this " + subject
+	case api.ModeReview:
+		c.Description = "Review this " + subject
+	default:
+		c.Description = "Work on this " + subject
+	}
+}
+
+// apply sets the command's mode, template and timeout on a task request.
+// The branch is resolved by the caller.
+func (c *taskCommand) apply(req *api.CreateTaskRequest) {
+	if c.Mode != "" {
+		req.Labels[api.ModeLabel] = c.Mode
+	}
+	if c.Template != "" {
+		req.Runner.SandboxTemplateName = c.Template
+	}
+	if c.Timeout > 0 {
+		req.Runner.Timeout = c.Timeout.String()
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v75/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    taskCommand
+		wantErr string
+	}{
+		{name: "description only", text: "make the login work",
+			want: taskCommand{Description: "make the login work"}},
+		{name: "empty", text: "  ", want: taskCommand{}},
+		{name: "mode alone", text: "plan", want: taskCommand{Mode: api.ModePlan}},
+		{name: "mode is part of the description", text: "Fix the login bug",
+			want: taskCommand{Mode: api.ModeFix, Description: "Fix the login bug"}},
+		{name: "options", text: "fix --branch release-1.2 --template=python --timeout 45m",
+			want: taskCommand{Mode: api.ModeFix, Branch: "release-1.2", Template: "python", Timeout: 45 * time.Minute}},
+		{name: "options between words", text: "review --branch=main the auth module",
+			want: taskCommand{Mode: api.ModeReview, Branch: "main", Description: "review the auth module"}},
+		{name: "options only on the first line", text: "fix --timeout 1h\nrun it with --force\nthen test",
+			want: taskCommand{Mode: api.ModeFix, Timeout: time.Hour, Description: "run it with --force\nthen test"}},
+		{name: "double dash", text: "-- --branch is documented wrong",
+			want: taskCommand{Description: "--branch is documented wrong"}},
+		{name: "unknown option", text: "fix --force", wantErr: "unknown option --force"},
+		{name: "missing value", text: "fix --branch", wantErr: "--branch needs a value"},
+		{name: "invalid branch", text: "fix --branch ../main", wantErr: "not a valid branch name"},
+		{name: "invalid template", text: "fix --template Big_Box", wantErr: "not a valid template name"},
+		{name: "invalid timeout", text: "fix --timeout soon", wantErr: "not a valid timeout"},
+		{name: "timeout out of range", text: "fix --timeout 1s", wantErr: "must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCommand(tt.text)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWebhookHandler_IssueCommand(t *testing.T) {
+	f := &fakePullRequest{}
+	handler, callbackHandler := newPullRequestTestHandler(t, f)
+
+	body, err := json.Marshal(createTestIssueCommentEvent("org", "repo", 7, "@shepherd plan --branch release-1.2 --timeout 45m"))
+	require.NoError(t, err)
+	handler.handleIssueComment(context.Background(), body)
+
+	require.Len(t, f.created, 1)
+	req := f.created[0]
+	assert.Equal(t, "release-1.2", req.Repo.Ref)
+	assert.Equal(t, "Plan the work on this issue", req.Task.Description)
+	assert.Equal(t, "45m0s", req.Runner.Timeout)
+	assert.Equal(t, api.ModePlan, req.Labels[api.ModeLabel])
+
+	meta, _ := storedTask(callbackHandler, "task-pr")
+	assert.Equal(t, api.ModePlan, meta.Mode)
+}
+
+func TestWebhookHandler_PullRequestCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		comment     string
+		wantComment string
+	}{
+		{name: "review", comment: "@shepherd review --template=large"},
+		{name: "invalid command", comment: "@shepherd fix --force", wantComment: "unknown option --force.\n\nUsage: @shepherd [plan|fix|review]"},
+		{name: "branch", comment: "@shepherd fix --branch main", wantComment: "--branch cannot be used on a pull request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakePullRequest{headRepo: "org/repo", state: "open"}
+			handler, _ := newPullRequestTestHandler(t, f)
+
+			event := createTestIssueCommentEvent("org", "repo", 7, tt.comment)
+			event.Issue.PullRequestLinks = &gh.PullRequestLinks{URL: gh.Ptr("https://api.github.com/repos/org/repo/pulls/7")}
+			body, err := json.Marshal(event)
+			require.NoError(t, err)
+			handler.handleIssueComment(context.Background(), body)
+
+			if tt.wantComment != "" {
+				assert.Empty(t, f.created)
+				require.Len(t, f.comments, 1)
+				assert.Contains(t, f.comments[0], tt.wantComment)
+				return
+			}
+			require.Len(t, f.created, 1)
+			req := f.created[0]
+			assert.Equal(t, "feature/login", req.Repo.Ref)
+			assert.Equal(t, "Review this pull request", req.Task.Description)
+			assert.Equal(t, "large", req.Runner.SandboxTemplateName)
+			assert.Equal(t, api.ModeReview, req.Labels[api.ModeLabel])
+		})
+	}
+}
@@ -16,7 +16,13 @@ limitations under the License.
 
 package github
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
 
 // Comment templates for different events.
 const (
@@ -24,7 +30,20 @@ const (
 
 Task ID: %s
 
-I'll update this issue when I'm done.`
+%sI'll update this issue when I'm done.`
+
+	// commentLiveProgress keeps the start of commentAcknowledge, so
+	// isAcknowledgment still recognizes the comment it replaces.
+	commentLiveProgress = `Shepherd is working on your request.
+
+Task ID: %s
+
+%s**Step:** %s
+**Elapsed:** %s
+%s
+I'll update this comment when I'm done.`
+
+	progressStarted = "Runner started"
 
 	commentAlreadyRunning = `A Shepherd task is already running for this issue.
 
@@ -37,30 +56,342 @@ Please wait for it to complete before triggering a new one.`
 
 Pull Request: %s
 
-Please review the changes.`
+%sPlease review the changes.`
+
+	commentPushed = `Shepherd has pushed its changes to this pull request.
+
+%sPlease review the changes.`
+
+	commentPullRequestFork = `Shepherd cannot push to this pull request's branch, because it belongs to a fork.
+
+Mention @%s on an issue instead to have Shepherd open a new pull request.`
+
+	commentPullRequestClosed = `Shepherd only works on open pull requests.`
+
+	commentRepoNotEnabled = `Shepherd is not enabled for %s, so it cannot work on this request. Ask your Shepherd administrators to enable the repository.`
+
+	commentPermissionDenied = `Sorry @%s, only users with %s access to this repository can ask Shepherd to work on it.`
+
+	commentPermissionUnknown = `Sorry @%s, Shepherd could not check your access to this repository, so it did not start a task. Please try again later.`
+
+	commentInvalidCommand = `Shepherd could not understand the request: %s.
+
+Usage: @%s %s`
+
+	commentUnknownBranch = `Shepherd could not find the branch %s in this repository.`
+
+	commentBranchOnPullRequest = `Shepherd always works on the pull request's own branch, so --branch cannot be used on a pull request.`
+
+	commentPlan = `Shepherd has written a plan.
+
+%s`
+
+	commentReview = `Shepherd has reviewed the code.
+
+%s`
 
 	commentFailed = `Shepherd was unable to complete the task.
 
 Error: %s
 
-You can trigger a new attempt by commenting with @shepherd again.`
+You can trigger a new attempt by commenting with @%s again.`
+
+	commentMaintenance = `Shepherd is under maintenance and cannot start new tasks right now.
+
+%s
+
+Please try again later by commenting with @%s again.`
+
+	commentCancelled = `The Shepherd task was cancelled.
+
+%s
+
+You can trigger a new attempt by commenting with @%s again.`
+
+	commentCancelFailed = `Shepherd could not cancel task %s%s, so it keeps running until it finishes or times out.`
+
+	commentNothingToCancel = `There is no running Shepherd task to cancel.`
+
+	commentNothingToRetry = `There is no failed Shepherd task to retry. Mention @%s with a request to start a new one.`
+
+	commentRetrySucceeded = `The last Shepherd task, %s, succeeded, so there is nothing to retry. Mention @%s with a request to ask for changes.`
+
+	commentLookupFailed = `Shepherd could not look up the task to %s. Please try again later.`
+
+	commentInvalidRepoConfig = `Shepherd did not start a task because the repository configuration is invalid:
+
+%s
+
+Fix it on the default branch and try again.`
+
+	commentCommandNotAllowed = `The repository configuration in %s does not allow the %s command. Allowed commands: %s.`
+
+	commentTimeoutWarning = `The Shepherd task %s is about to time out%s.
+
+Work that is not pushed by then will be lost.`
+
+	commentVerificationStarted = `%s was merged. Shepherd is verifying that it resolved this issue.
+
+Task ID: %s`
+
+	commentVerificationPassed = `Shepherd's post-merge verification passed.
+
+%s`
+
+	commentVerificationFailed = `Shepherd's post-merge verification failed, so this issue may not be fully resolved.
+
+%s`
 )
 
-func formatAcknowledge(taskID string) string {
-	return fmt.Sprintf(commentAcknowledge, taskID)
+// formatAcknowledge acknowledges a new task, stating how it relates to the
+// issue's earlier tasks if lineage is set.
+func formatAcknowledge(taskID, lineage string) string {
+	if lineage != "" {
+		lineage = "This task " + lineage + ".\n\n"
+	}
+	return fmt.Sprintf(commentAcknowledge, taskID, lineage)
+}
+
+// formatLiveProgress is the acknowledgment of a task edited to show the
+// step it is at, the runner's last action if known, and how long ago it
+// was acknowledged.
+func formatLiveProgress(taskID, lineage, step, action string, elapsed time.Duration) string {
+	if lineage != "" {
+		lineage = "This task " + lineage + ".\n\n"
+	}
+	if step = firstLine(step); step == "" {
+		step = "Working"
+	}
+	if action = firstLine(action); action != "" {
+		action = "**Last action:** " + action + "\n"
+	}
+	return fmt.Sprintf(commentLiveProgress, taskID, lineage, step, elapsed.Round(time.Second), action)
+}
+
+// firstLine returns the first line of s, trimmed, so it fits a line of a
+// comment.
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(s)
+}
+
+// isAcknowledgment reports whether body is the acknowledgment of taskID.
+func isAcknowledgment(body, taskID string) bool {
+	return strings.HasPrefix(body, "Shepherd is working on your request.\n\nTask ID: "+taskID+"\n")
 }
 
 func formatAlreadyRunning(taskID, status string) string {
 	return fmt.Sprintf(commentAlreadyRunning, taskID, status)
 }
 
-func formatCompleted(prURL string) string {
-	return fmt.Sprintf(commentCompleted, prURL)
+// formatCompleted announces the PR of a completed task, with the runner's
+// summary of the change if it sent one.
+func formatCompleted(prURL, summary string) string {
+	if summary != "" {
+		summary = quoteSummary(summary) + "\n\n"
+	}
+	return fmt.Sprintf(commentCompleted, prURL, summary)
+}
+
+// formatPushed announces a completed task of an existing PR, which pushed
+// to the PR's branch instead of opening a PR.
+func formatPushed(summary string) string {
+	if summary != "" {
+		summary = quoteSummary(summary) + "\n\n"
+	}
+	return fmt.Sprintf(commentPushed, summary)
+}
+
+func formatPullRequestFork(handle string) string {
+	return fmt.Sprintf(commentPullRequestFork, handle)
+}
+
+func formatPullRequestClosed() string {
+	return commentPullRequestClosed
 }
 
-func formatFailed(errorMsg string) string {
+func formatRepoNotEnabled(repo string) string {
+	return fmt.Sprintf(commentRepoNotEnabled, repo)
+}
+
+func formatPermissionDenied(user, level string) string {
+	return fmt.Sprintf(commentPermissionDenied, user, level)
+}
+
+func formatPermissionUnknown(user string) string {
+	return fmt.Sprintf(commentPermissionUnknown, user)
+}
+
+func formatInvalidCommand(err error, handle string) string {
+	return fmt.Sprintf(commentInvalidCommand, err, handle, commandUsage)
+}
+
+func formatUnknownBranch(branch string) string {
+	return fmt.Sprintf(commentUnknownBranch, "`"+branch+"`")
+}
+
+func formatBranchOnPullRequest() string {
+	return commentBranchOnPullRequest
+}
+
+// formatReport posts the report of a plan or review task. Unlike other
+// summaries it is not quoted, as the report is the comment's content.
+func formatReport(mode, report string) string {
+	if report == "" {
+		report = "The task did not write a report."
+	}
+	if mode == api.ModePlan {
+		return fmt.Sprintf(commentPlan, strings.TrimSpace(report))
+	}
+	return fmt.Sprintf(commentReview, strings.TrimSpace(report))
+}
+
+// formatCompletedWithoutPR announces a task that completed without a PR.
+func formatCompletedWithoutPR(summary string) string {
+	if summary == "" {
+		return "Shepherd completed the task successfully."
+	}
+	return "Shepherd completed the task successfully.\n\n" + quoteSummary(summary)
+}
+
+// quoteSummary renders the runner's summary as a Markdown block quote, so
+// it stands apart from the comment's own text.
+func quoteSummary(summary string) string {
+	return "> " + strings.ReplaceAll(strings.TrimSpace(summary), "\n", "\n> ")
+}
+
+// withAcknowledgment prefixes a task's result comment with the
+// acknowledgment that could not be posted when the task was created.
+func withAcknowledgment(comment, taskID string) string {
+	return fmt.Sprintf("Shepherd picked up your request as task %s.\n\n%s", taskID, comment)
+}
+
+// withThreadHeader starts a comment about an acknowledged task with a quoted
+// header naming the task, how it relates to earlier tasks, and a link to
+// its acknowledgment. Issues don't have threads, so the header is what
+// tells the comments of several tasks on one issue apart.
+func withThreadHeader(comment, taskID string, meta TaskMetadata) string {
+	header := "> **" + taskID + "**"
+	if meta.Lineage != "" {
+		header += " " + meta.Lineage
+	}
+	if meta.AckURL != "" {
+		header += fmt.Sprintf(" · [started here](%s)", meta.AckURL)
+	}
+	return header + "\n\n" + comment
+}
+
+// withTaskMarker appends a hidden HTML comment naming the task, and its
+// correlation ID if known, to comment. Comments of reruns on one issue
+// can then be told apart, and joined with the logs of their task.
+func withTaskMarker(comment, taskID, correlationID string) string {
+	marker := "<!-- shepherd-task:" + taskID
+	if correlationID != "" {
+		marker += " correlation:" + correlationID
+	}
+	return comment + "\n\n" + marker + " -->"
+}
+
+// withLinks appends the links the API server sent with a callback to
+// comment.
+func withLinks(comment string, links *api.TaskLinks) string {
+	if links == nil {
+		return comment
+	}
+	var parts []string
+	if links.Dashboard != "" {
+		parts = append(parts, fmt.Sprintf("[View task](%s)", links.Dashboard))
+	}
+	if links.Logs != "" {
+		parts = append(parts, fmt.Sprintf("[Logs](%s)", links.Logs))
+	}
+	if len(parts) == 0 {
+		return comment
+	}
+	return comment + "\n\n" + strings.Join(parts, " · ")
+}
+
+func formatFailed(errorMsg, handle string) string {
 	if errorMsg == "" {
 		errorMsg = "Unknown error"
 	}
-	return fmt.Sprintf(commentFailed, errorMsg)
+	return fmt.Sprintf(commentFailed, errorMsg, handle)
+}
+
+// formatMaintenance tells the requester that the task was not started
+// because Shepherd is under maintenance, and until when if announced.
+func formatMaintenance(message string, until time.Time, handle string) string {
+	if message == "" {
+		message = "No details given."
+	}
+	if !until.IsZero() {
+		message += fmt.Sprintf("\n\nMaintenance is expected to end at %s.", until.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	return fmt.Sprintf(commentMaintenance, message, handle)
+}
+
+func formatCancelled(reason, handle string) string {
+	if reason == "" {
+		reason = "No reason given"
+	}
+	return fmt.Sprintf(commentCancelled, reason, handle)
+}
+
+// formatCancelFailed tells that a task could not be cancelled, when
+// describing what asked for it, such as " after this issue was unassigned".
+func formatCancelFailed(taskID, when string) string {
+	return fmt.Sprintf(commentCancelFailed, taskID, when)
+}
+
+// formatNothingToRetry answers a retry on an issue without a task.
+func formatNothingToRetry(handle string) string {
+	return fmt.Sprintf(commentNothingToRetry, handle)
+}
+
+// formatRetrySucceeded answers a retry of a task that succeeded.
+func formatRetrySucceeded(taskID, handle string) string {
+	return fmt.Sprintf(commentRetrySucceeded, taskID, handle)
+}
+
+// formatLookupFailed answers a control command whose task could not be
+// looked up.
+func formatLookupFailed(command string) string {
+	return fmt.Sprintf(commentLookupFailed, command)
+}
+
+// formatInvalidRepoConfig explains why a repository configuration file
+// was rejected.
+func formatInvalidRepoConfig(err error) string {
+	return fmt.Sprintf(commentInvalidRepoConfig, err)
+}
+
+// formatCommandNotAllowed answers a command the repository configuration
+// does not list.
+func formatCommandNotAllowed(command, path string, allowed []string) string {
+	return fmt.Sprintf(commentCommandNotAllowed, path, command, strings.Join(allowed, ", "))
+}
+
+// formatTimeoutWarning warns that a task will time out soon, at deadline
+// (RFC 3339) if known.
+func formatTimeoutWarning(taskID, deadline string) string {
+	var at string
+	if t, err := time.Parse(time.RFC3339, deadline); err == nil {
+		at = " at " + t.UTC().Format("2006-01-02 15:04 MST")
+	}
+	return fmt.Sprintf(commentTimeoutWarning, taskID, at)
+}
+
+func formatVerificationStarted(prURL, taskID string) string {
+	return fmt.Sprintf(commentVerificationStarted, prURL, taskID)
+}
+
+func formatVerificationPassed(summary string) string {
+	return fmt.Sprintf(commentVerificationPassed, summary)
+}
+
+func formatVerificationFailed(summary string) string {
+	if summary == "" {
+		summary = "Unknown error"
+	}
+	return fmt.Sprintf(commentVerificationFailed, summary)
 }
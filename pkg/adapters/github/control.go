@@ -0,0 +1,156 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	gh "github.com/google/go-github/v75/github"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// Control commands act on the task of an issue or PR instead of starting
+// one.
+const (
+	// controlCancel cancels the running task.
+	controlCancel = "cancel"
+	// controlRetry retries the last task if it failed, timed out or was
+	// cancelled.
+	controlRetry = "retry"
+)
+
+// controlCommand returns the control command that text, a comment with
+// its mention removed, gives, or "" if it requests a task. The command
+// must stand alone on the first line, so "cancel the nightly job" is a
+// task.
+func controlCommand(text string) string {
+	first, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	switch command := strings.ToLower(strings.TrimSpace(first)); command {
+	case controlCancel, controlRetry:
+		return command
+	}
+	return ""
+}
+
+// handleControl runs a control command from a comment on an issue or a
+// PR's conversation, replying with its outcome.
+func (h *WebhookHandler) handleControl(ctx context.Context, event *gh.IssueCommentEvent, command string) {
+	if _, ok := h.repoConfig(ctx, event.GetRepo(), event.GetIssue().GetNumber(), command); !ok {
+		return
+	}
+	switch command {
+	case controlCancel:
+		h.cancelByComment(ctx, event)
+	case controlRetry:
+		h.retryByComment(ctx, event)
+	}
+}
+
+// cancelByComment cancels the running task of the issue of event. The
+// task's cancelled callback then tells the issue.
+func (h *WebhookHandler) cancelByComment(ctx context.Context, event *gh.IssueCommentEvent) {
+	owner, repo := event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName()
+	number := event.GetIssue().GetNumber()
+	user := event.GetComment().GetUser().GetLogin()
+
+	task, err := h.apiClient.GetActiveTask(ctx, event.GetIssue().GetHTMLURL())
+	var reply string
+	switch {
+	case err != nil:
+		h.log.Error(err, "failed to look up the task to cancel", "issue", number)
+		reply = formatLookupFailed(controlCancel)
+	case task == nil:
+		reply = commentNothingToCancel
+	default:
+		h.log.Info("cancelling task on request", logging.TaskID, task.ID, "issue", number, "user", user)
+		err := h.apiClient.CancelTask(ctx, task.ID, "cancelled by @"+user)
+		if err == nil {
+			return
+		}
+		h.log.Error(err, "failed to cancel task", logging.TaskID, task.ID)
+		reply = formatCancelFailed(task.ID, "")
+	}
+	if commentErr := h.ghClient.PostComment(ctx, owner, repo, number, reply); commentErr != nil {
+		h.log.Error(commentErr, "failed to post cancel reply")
+	}
+}
+
+// retryByComment retries the last task of the issue of event, which must
+// have failed, timed out or been cancelled. The retry is acknowledged like
+// a new task.
+func (h *WebhookHandler) retryByComment(ctx context.Context, event *gh.IssueCommentEvent) {
+	owner, repo := event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName()
+	number := event.GetIssue().GetNumber()
+	repoLabel := strings.ReplaceAll(event.GetRepo().GetFullName(), "/", "-")
+
+	tasks, err := h.apiClient.ListIssueTasks(ctx, repoLabel, strconv.Itoa(number))
+	var previous *api.TaskResponse
+	for i := range tasks {
+		if tasks[i].Task.SourceType != api.SourceTypeVerification {
+			previous = &tasks[i]
+		}
+	}
+	var reply string
+	switch {
+	case err != nil:
+		h.log.Error(err, "failed to look up the task to retry", "issue", number)
+		reply = formatLookupFailed(controlRetry)
+	case previous == nil:
+		reply = formatNothingToRetry(h.handle)
+	case previous.CompletionTime == nil:
+		reply = formatAlreadyRunning(previous.ID, previous.Status.Phase)
+	case previous.Status.Phase == "Succeeded":
+		reply = formatRetrySucceeded(previous.ID, h.handle)
+	}
+	if reply != "" {
+		if commentErr := h.ghClient.PostComment(ctx, owner, repo, number, reply); commentErr != nil {
+			h.log.Error(commentErr, "failed to post retry reply")
+		}
+		return
+	}
+
+	taskResp, err := h.apiClient.RetryTask(ctx, previous.ID)
+	if err != nil {
+		h.reportCreateFailure(ctx, owner, repo, number, err)
+		return
+	}
+	h.log.Info("retried task", logging.TaskID, taskResp.ID, logging.CorrelationID, taskResp.CorrelationID,
+		"retryOf", previous.ID, "issue", number)
+
+	_, _, lineage := taskLineage([]api.TaskResponse{*previous})
+	meta := TaskMetadata{
+		Owner:         owner,
+		Repo:          repo,
+		IssueNumber:   number,
+		PullRequest:   previous.Task.SourceType == api.SourceTypePullRequest,
+		CorrelationID: taskResp.CorrelationID,
+		Lineage:       lineage,
+	}
+	// The retry keeps the previous task's mode label, which its metadata
+	// knows unless it expired.
+	if prev, ok, err := h.callbackHandler.tasks.Get(ctx, previous.ID); err != nil {
+		h.log.Error(err, "failed to get metadata of the retried task", logging.TaskID, previous.ID)
+	} else if ok {
+		meta.Mode = prev.Mode
+	}
+	h.reactTo(&meta, event.GetComment().GetID(), event.GetComment().GetHTMLURL(), false)
+	h.acknowledge(ctx, taskResp, meta)
+}
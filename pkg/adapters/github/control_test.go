@@ -0,0 +1,139 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func TestControlCommand(t *testing.T) {
+	tests := map[string]string{
+		" cancel ":                   controlCancel,
+		"Retry":                      controlRetry,
+		"retry\n\nThe runner broke.": controlRetry,
+		"cancel the nightly job":     "",
+		"fix the retry logic":        "",
+		"":                           "",
+	}
+	for text, want := range tests {
+		assert.Equal(t, want, controlCommand(text), text)
+	}
+}
+
+// issueTask returns a task of issue 7 in phase, finished unless running.
+func issueTask(id, sourceType, phase string) api.TaskResponse {
+	completed := "2026-10-10T08:00:00Z"
+	tr := api.TaskResponse{ID: id, Task: api.TaskRequest{SourceType: sourceType}}
+	tr.Status.Phase = phase
+	if phase != "Running" {
+		tr.CompletionTime = &completed
+	}
+	return tr
+}
+
+// controlComment sends a comment with text on issue 7 to handler.
+func controlComment(t *testing.T, handler *WebhookHandler, text string) {
+	t.Helper()
+	body, err := json.Marshal(createTestIssueCommentEvent("org", "repo", 7, "@shepherd "+text))
+	require.NoError(t, err)
+	handler.handleIssueComment(context.Background(), body)
+}
+
+func TestWebhookHandler_CancelCommand(t *testing.T) {
+	t.Run("cancels the running task", func(t *testing.T) {
+		f := &fakePullRequest{active: &api.TaskResponse{ID: "task-running"}}
+		handler, _ := newPullRequestTestHandler(t, f)
+
+		controlComment(t, handler, "cancel")
+
+		assert.Equal(t, []string{"cancelled by @testuser"}, f.cancelled)
+		assert.Empty(t, f.created)
+		assert.Empty(t, f.comments, "the cancelled callback tells the issue")
+	})
+
+	t.Run("without a running task", func(t *testing.T) {
+		f := &fakePullRequest{}
+		handler, _ := newPullRequestTestHandler(t, f)
+
+		controlComment(t, handler, "cancel")
+
+		assert.Empty(t, f.cancelled)
+		assert.Equal(t, []string{commentNothingToCancel}, f.comments)
+	})
+
+	t.Run("reports a failed cancel", func(t *testing.T) {
+		f := &fakePullRequest{active: &api.TaskResponse{ID: "task-running"}, failCancel: true}
+		handler, _ := newPullRequestTestHandler(t, f)
+
+		controlComment(t, handler, "cancel")
+
+		assert.Equal(t, []string{formatCancelFailed("task-running", "")}, f.comments)
+	})
+}
+
+func TestWebhookHandler_RetryCommand(t *testing.T) {
+	t.Run("retries the failed task", func(t *testing.T) {
+		f := &fakePullRequest{tasks: []api.TaskResponse{
+			issueTask("task-1", api.SourceTypeIssue, "Failed"),
+			issueTask("task-verify", api.SourceTypeVerification, "Succeeded"),
+		}}
+		handler, callbackHandler := newPullRequestTestHandler(t, f)
+		callbackHandler.RegisterTask(context.Background(), "task-1", TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 7, Mode: api.ModePlan})
+
+		controlComment(t, handler, "retry")
+
+		assert.Equal(t, []string{"task-1"}, f.retried)
+		assert.Empty(t, f.created)
+		require.Len(t, f.comments, 1)
+		assert.True(t, isAcknowledgment(f.comments[0], "task-retry"))
+		assert.Contains(t, f.comments[0], "retries task-1, which failed")
+		meta, ok := storedTask(callbackHandler, "task-retry")
+		require.True(t, ok)
+		assert.Equal(t, api.ModePlan, meta.Mode)
+		assert.Equal(t, "run-2", meta.CorrelationID)
+	})
+
+	tests := []struct {
+		name  string
+		tasks []api.TaskResponse
+		want  string
+	}{
+		{"without a task", nil, formatNothingToRetry("shepherd")},
+		{"while running", []api.TaskResponse{issueTask("task-1", api.SourceTypeIssue, "Running")},
+			formatAlreadyRunning("task-1", "Running")},
+		{"after success", []api.TaskResponse{issueTask("task-1", api.SourceTypeIssue, "Succeeded")},
+			formatRetrySucceeded("task-1", "shepherd")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakePullRequest{tasks: tt.tasks}
+			handler, _ := newPullRequestTestHandler(t, f)
+
+			controlComment(t, handler, "retry")
+
+			assert.Empty(t, f.retried)
+			assert.Equal(t, []string{tt.want}, f.comments)
+		})
+	}
+}
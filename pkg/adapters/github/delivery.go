@@ -0,0 +1,233 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeliveryStore records the IDs of the webhook deliveries the adapter
+// handled. GitHub sends a redelivery, whether automatic or requested in its
+// UI, with the ID of the original delivery.
+type DeliveryStore interface {
+	// Record records id and reports whether it was recorded before.
+	Record(ctx context.Context, id string) (seen bool, err error)
+	// Forget removes id, so a redelivery of it is handled.
+	Forget(ctx context.Context, id string) error
+}
+
+var (
+	_ DeliveryStore = (*memoryDeliveryStore)(nil)
+	_ DeliveryStore = (*configMapDeliveryStore)(nil)
+)
+
+// memoryDeliveryStore keeps the most recently recorded delivery IDs in
+// memory, forgetting the least recently seen one when it is full.
+type memoryDeliveryStore struct {
+	maxEntries int
+
+	mu    sync.Mutex
+	order *list.List // of delivery IDs, most recent first
+	ids   map[string]*list.Element
+}
+
+// newMemoryDeliveryStore returns a store that keeps up to maxEntries IDs.
+func newMemoryDeliveryStore(maxEntries int) *memoryDeliveryStore {
+	return &memoryDeliveryStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		ids:        map[string]*list.Element{},
+	}
+}
+
+func (s *memoryDeliveryStore) Record(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.ids[id]; ok {
+		s.order.MoveToFront(e)
+		return true, nil
+	}
+	s.ids[id] = s.order.PushFront(id)
+	if s.order.Len() > s.maxEntries {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.ids, oldest.Value.(string))
+	}
+	return false, nil
+}
+
+func (s *memoryDeliveryStore) Forget(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.ids[id]; ok {
+		s.order.Remove(e)
+		delete(s.ids, id)
+	}
+	return nil
+}
+
+// DefaultDeliveryStoreConfigMap is the ConfigMap the adapter keeps
+// delivery IDs in with the configmap delivery store.
+const DefaultDeliveryStoreConfigMap = "shepherd-github-deliveries"
+
+// deliveryRetention is how long the configmap delivery store keeps a
+// delivery ID. GitHub only redelivers deliveries of the past three days.
+const deliveryRetention = 72 * time.Hour
+
+// maxStoredDeliveries bounds the IDs in the configmap delivery store, whose
+// ConfigMap holds at most 1 MiB; the oldest are dropped first.
+const maxStoredDeliveries = 10000
+
+// configMapDeliveryStore keeps delivery IDs in a ConfigMap, each mapped to
+// when it was recorded, so they survive restarts and are shared by all
+// replicas of the adapter.
+type configMapDeliveryStore struct {
+	data configMapData
+	now  func() time.Time
+}
+
+// NewConfigMapDeliveryStore returns a store that keeps delivery IDs in the
+// ConfigMap namespace/name, creating it on the first write.
+func NewConfigMapDeliveryStore(c client.Client, namespace, name string) DeliveryStore {
+	return &configMapDeliveryStore{
+		data: configMapData{client: c, key: client.ObjectKey{Namespace: namespace, Name: name}, what: "delivery store"},
+		now:  time.Now,
+	}
+}
+
+// newConfigMapDeliveryStoreFromConfig returns a configmap delivery store
+// using the in-cluster or kubeconfig credentials.
+func newConfigMapDeliveryStoreFromConfig(namespace, name string) (DeliveryStore, error) {
+	c, err := newStoreClient(namespace, "delivery store")
+	if err != nil {
+		return nil, err
+	}
+	return NewConfigMapDeliveryStore(c, namespace, cmp.Or(name, DefaultDeliveryStoreConfigMap)), nil
+}
+
+func (s *configMapDeliveryStore) Record(ctx context.Context, id string) (bool, error) {
+	if len(validation.IsConfigMapKey(id)) > 0 {
+		// Not a delivery ID GitHub sends; it cannot be a key.
+		return false, nil
+	}
+	var seen bool
+	_, err := s.data.modify(ctx, func(data map[string]string) (bool, error) {
+		if _, seen = data[id]; seen {
+			return false, nil
+		}
+		data[id] = s.now().UTC().Format(time.RFC3339)
+		s.prune(data)
+		return true, nil
+	})
+	return seen, err
+}
+
+// prune drops the IDs of data recorded longer than deliveryRetention ago,
+// and the oldest beyond maxStoredDeliveries.
+func (s *configMapDeliveryStore) prune(data map[string]string) {
+	type recorded struct {
+		id string
+		at time.Time
+	}
+	entries := make([]recorded, 0, len(data))
+	for id, value := range data {
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil || s.now().Sub(at) > deliveryRetention {
+			delete(data, id)
+			continue
+		}
+		entries = append(entries, recorded{id: id, at: at})
+	}
+	if len(entries) <= maxStoredDeliveries {
+		return
+	}
+	slices.SortFunc(entries, func(a, b recorded) int { return a.at.Compare(b.at) })
+	for _, e := range entries[:len(entries)-maxStoredDeliveries] {
+		delete(data, e.id)
+	}
+}
+
+func (s *configMapDeliveryStore) Forget(ctx context.Context, id string) error {
+	_, err := s.data.modify(ctx, func(data map[string]string) (bool, error) {
+		_, ok := data[id]
+		delete(data, id)
+		return ok, nil
+	})
+	return err
+}
+
+// DeliveryDeduplicator skips webhook deliveries that were handled before.
+// Recent IDs are kept in memory, and with a persistent store also there,
+// which catches redeliveries after a restart or to another replica.
+type DeliveryDeduplicator struct {
+	recent *memoryDeliveryStore
+	store  DeliveryStore // nil keeps IDs in memory only
+	log    logr.Logger
+}
+
+// NewDeliveryDeduplicator returns a deduplicator that remembers the last
+// cacheSize deliveries in memory and, if store is not nil, all deliveries
+// in store.
+func NewDeliveryDeduplicator(cacheSize int, store DeliveryStore, log logr.Logger) *DeliveryDeduplicator {
+	return &DeliveryDeduplicator{recent: newMemoryDeliveryStore(cacheSize), store: store, log: log}
+}
+
+// seen records the delivery id and reports whether it was handled before.
+// Deliveries without an ID, and any delivery when the persistent store
+// fails, are handled: a duplicate task is better than a lost one. A nil
+// deduplicator sees nothing.
+func (d *DeliveryDeduplicator) seen(ctx context.Context, id string) bool {
+	if d == nil || id == "" {
+		return false
+	}
+	if seen, _ := d.recent.Record(ctx, id); seen {
+		return true
+	}
+	if d.store == nil {
+		return false
+	}
+	seen, err := d.store.Record(ctx, id)
+	if err != nil {
+		d.log.Error(err, "failed to record delivery, handling it", "delivery", id)
+		return false
+	}
+	return seen
+}
+
+// forget drops the delivery id, which was not handled after all, so that
+// its redelivery is.
+func (d *DeliveryDeduplicator) forget(ctx context.Context, id string) {
+	if d == nil || id == "" {
+		return
+	}
+	_ = d.recent.Forget(ctx, id)
+	if d.store == nil {
+		return
+	}
+	if err := d.store.Forget(ctx, id); err != nil {
+		d.log.Error(err, "failed to forget delivery; a redelivery will be skipped", "delivery", id)
+	}
+}
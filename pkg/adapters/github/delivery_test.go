@@ -0,0 +1,153 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMemoryDeliveryStore(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryDeliveryStore(2)
+	record := func(id string) bool {
+		seen, err := store.Record(ctx, id)
+		require.NoError(t, err)
+		return seen
+	}
+
+	assert.False(t, record("a"))
+	assert.False(t, record("b"))
+	assert.True(t, record("a"), "a is now the most recent")
+	assert.False(t, record("c"), "evicts b")
+	assert.True(t, record("a"))
+	assert.False(t, record("b"), "b was evicted")
+
+	require.NoError(t, store.Forget(ctx, "b"))
+	assert.False(t, record("b"), "forgotten")
+}
+
+func TestConfigMapDeliveryStore(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	store := NewConfigMapDeliveryStore(c, "shepherd", DefaultDeliveryStoreConfigMap).(*configMapDeliveryStore)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	seen, err := store.Record(ctx, "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	require.NoError(t, err)
+	assert.False(t, seen)
+
+	// Another replica, or the adapter after a restart, sees the delivery.
+	other := NewConfigMapDeliveryStore(c, "shepherd", DefaultDeliveryStoreConfigMap)
+	seen, err = other.Record(ctx, "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	require.NoError(t, err)
+	assert.True(t, seen)
+
+	require.NoError(t, other.Forget(ctx, "72d3162e-cc78-11e3-81ab-4c9367dc0958"))
+	seen, err = store.Record(ctx, "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	require.NoError(t, err)
+	assert.False(t, seen, "forgotten")
+
+	seen, err = store.Record(ctx, "not a key")
+	require.NoError(t, err)
+	assert.False(t, seen, "IDs that cannot be keys are not stored")
+
+	now = now.Add(deliveryRetention + time.Minute)
+	_, err = store.Record(ctx, "new")
+	require.NoError(t, err)
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "shepherd", Name: DefaultDeliveryStoreConfigMap}, &cm))
+	assert.Equal(t, []string{"new"}, keysOf(cm.Data), "old IDs are pruned")
+}
+
+func keysOf(data map[string]string) []string {
+	var keys []string
+	for k := range data {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// failingDeliveryStore fails to record and forget.
+type failingDeliveryStore struct{}
+
+func (failingDeliveryStore) Record(context.Context, string) (bool, error) {
+	return false, errors.New("unavailable")
+}
+
+func (failingDeliveryStore) Forget(context.Context, string) error { return errors.New("unavailable") }
+
+func TestDeliveryDeduplicator(t *testing.T) {
+	ctx := context.Background()
+	log := ctrl.Log.WithName("test")
+
+	store := NewConfigMapDeliveryStore(fake.NewClientBuilder().Build(), "shepherd", DefaultDeliveryStoreConfigMap)
+	d := NewDeliveryDeduplicator(10, store, log)
+	assert.False(t, d.seen(ctx, "delivery-1"))
+	assert.True(t, d.seen(ctx, "delivery-1"))
+	assert.False(t, d.seen(ctx, ""), "deliveries without an ID are handled")
+	assert.False(t, d.seen(ctx, ""))
+
+	restarted := NewDeliveryDeduplicator(10, store, log)
+	assert.True(t, restarted.seen(ctx, "delivery-1"), "seen in the persistent store")
+	restarted.forget(ctx, "delivery-1")
+	assert.False(t, NewDeliveryDeduplicator(10, store, log).seen(ctx, "delivery-1"), "forgotten in the persistent store")
+
+	failing := NewDeliveryDeduplicator(10, failingDeliveryStore{}, log)
+	assert.False(t, failing.seen(ctx, "delivery-2"), "handled when the store fails")
+	assert.True(t, failing.seen(ctx, "delivery-2"), "still remembered in memory")
+
+	var none *DeliveryDeduplicator
+	assert.False(t, none.seen(ctx, "delivery-1"))
+	none.forget(ctx, "delivery-1")
+}
+
+func TestWebhookHandler_DeliveryDeduplication(t *testing.T) {
+	f := &fakePullRequest{}
+	handler, _ := newPullRequestTestHandler(t, f)
+	WithDeliveryDeduplication(NewDeliveryDeduplicator(10, nil, ctrl.Log.WithName("test")))(handler)
+
+	body, err := json.Marshal(createTestIssueCommentEvent("org", "repo", 7, "@shepherd fix the tests"))
+	require.NoError(t, err)
+	deliver := func(id string) {
+		req := signedRequest(t, "", body, "issue_comment")
+		req.Header.Set("X-GitHub-Delivery", id)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	deliver("delivery-1")
+	deliver("delivery-1")
+	assert.Len(t, f.created, 1, "the redelivery is skipped")
+
+	deliver("delivery-2")
+	assert.Len(t, f.created, 2, "a new delivery of the same comment is handled")
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/forge"
+)
+
+// DigestConfig controls the weekly activity digest.
+type DigestConfig struct {
+	Enabled bool
+	Weekday time.Weekday // Day the digest is posted
+	Hour    int          // Hour of day (UTC) the digest is posted
+}
+
+const (
+	digestIssueTitle = "Shepherd weekly digest"
+	digestLabel      = "shepherd-digest"
+	digestPeriod     = 7 * 24 * time.Hour
+)
+
+// repoDigest summarizes one repository's shepherd activity over a period.
+type repoDigest struct {
+	Owner     string
+	Repo      string
+	Tasks     int
+	Succeeded int
+	Failed    int // Failed, TimedOut and Cancelled
+	Active    int
+	PRs       []digestPR
+	CostUSD   float64
+}
+
+type digestPR struct {
+	URL    string
+	Merged bool
+}
+
+// Digester posts a weekly summary of shepherd activity to a pinned issue in
+// each repository that had tasks during the week.
+type Digester struct {
+	ghClient  *Client
+	apiClient *APIClient
+	cfg       DigestConfig
+	log       logr.Logger
+}
+
+// NewDigester creates a digest generator.
+func NewDigester(ghClient *Client, apiClient *APIClient, cfg DigestConfig, log logr.Logger) *Digester {
+	return &Digester{
+		ghClient:  ghClient,
+		apiClient: apiClient,
+		cfg:       cfg,
+		log:       log.WithName("digest"),
+	}
+}
+
+// Run posts digests on the configured schedule until ctx is cancelled.
+func (d *Digester) Run(ctx context.Context) {
+	for {
+		next := nextDigestTime(time.Now(), d.cfg.Weekday, d.cfg.Hour)
+		d.log.Info("next weekly digest scheduled", "at", next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := d.Publish(ctx, next.Add(-digestPeriod), next); err != nil {
+			d.log.Error(err, "failed to publish weekly digest")
+		}
+	}
+}
+
+// Publish posts the digest for tasks created in [since, until). Repositories
+// that already have a digest for the period are skipped, so a retry or a
+// second adapter replica does not post duplicates.
+func (d *Digester) Publish(ctx context.Context, since, until time.Time) error {
+	tasks, err := d.apiClient.ListTasks(ctx)
+	if err != nil {
+		return fmt.Errorf("listing tasks: %w", err)
+	}
+
+	digests := summarizeTasks(d.ghClient.pathPrefix(), tasks, since, until)
+	for _, rd := range digests {
+		if err := d.publishRepo(ctx, rd, until); err != nil {
+			// Keep going so one inaccessible repository does not block the rest
+			d.log.Error(err, "failed to publish repository digest", "owner", rd.Owner, "repo", rd.Repo)
+		}
+	}
+	d.log.Info("published weekly digest", "repositories", len(digests), "since", since, "until", until)
+	return nil
+}
+
+func (d *Digester) publishRepo(ctx context.Context, rd *repoDigest, until time.Time) error {
+	for i := range rd.PRs {
+		pr, err := parsePRURL(d.ghClient.pathPrefix(), rd.PRs[i].URL)
+		if err != nil {
+			continue
+		}
+		merged, err := d.ghClient.IsPullRequestMerged(ctx, pr.Owner, pr.Repo, pr.Number)
+		if err != nil {
+			d.log.Error(err, "failed to check PR merge status", "prURL", rd.PRs[i].URL)
+			continue
+		}
+		rd.PRs[i].Merged = merged
+	}
+
+	issue, err := d.ghClient.FindOrCreateIssue(ctx, rd.Owner, rd.Repo, digestIssueTitle, digestLabel,
+		"Shepherd posts a summary of its activity in this repository here every week.")
+	if err != nil {
+		return err
+	}
+
+	marker := digestMarker(until)
+	comments, err := d.ghClient.ListIssueComments(ctx, rd.Owner, rd.Repo, issue)
+	if err != nil {
+		return err
+	}
+	for _, c := range comments {
+		if strings.Contains(c.GetBody(), marker) {
+			d.log.V(1).Info("digest already posted", "owner", rd.Owner, "repo", rd.Repo)
+			return nil
+		}
+	}
+
+	return d.ghClient.PostComment(ctx, rd.Owner, rd.Repo, issue, formatDigest(rd, until.Add(-digestPeriod), until))
+}
+
+// nextDigestTime returns the next occurrence of weekday at hour:00 UTC
+// strictly after now.
+func nextDigestTime(now time.Time, weekday time.Weekday, hour int) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	next = next.AddDate(0, 0, (int(weekday)-int(now.Weekday())+7)%7)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+// summarizeTasks groups the tasks created in [since, until) by repository.
+// The result is sorted by owner and repository name.
+func summarizeTasks(basePath string, tasks []api.TaskResponse, since, until time.Time) []*repoDigest {
+	byRepo := make(map[string]*repoDigest)
+	for _, t := range tasks {
+		created, err := time.Parse(time.RFC3339, t.CreatedAt)
+		if err != nil || created.Before(since) || !created.Before(until) {
+			continue
+		}
+		owner, repo, ok := parseRepoURL(basePath, t.Repo.URL)
+		if !ok {
+			continue
+		}
+
+		key := strings.ToLower(owner + "/" + repo)
+		rd := byRepo[key]
+		if rd == nil {
+			rd = &repoDigest{Owner: owner, Repo: repo}
+			byRepo[key] = rd
+		}
+
+		rd.Tasks++
+		switch t.Status.Phase {
+		case "Succeeded":
+			rd.Succeeded++
+		case "Failed", "TimedOut", "Cancelled":
+			rd.Failed++
+		default:
+			rd.Active++
+		}
+		// Tasks of an existing PR report that PR, which they did not open
+		if t.Status.PRURL != "" && t.Task.SourceType != api.SourceTypePullRequest {
+			rd.PRs = append(rd.PRs, digestPR{URL: t.Status.PRURL})
+		}
+		rd.CostUSD += t.Status.CostUSD
+	}
+
+	digests := make([]*repoDigest, 0, len(byRepo))
+	for _, rd := range byRepo {
+		digests = append(digests, rd)
+	}
+	slices.SortFunc(digests, func(a, b *repoDigest) int {
+		return cmp.Or(cmp.Compare(a.Owner, b.Owner), cmp.Compare(a.Repo, b.Repo))
+	})
+	return digests
+}
+
+// parseRepoURL extracts owner and repository from a repository URL such as
+// https://github.com/org/repo.git whose path starts with basePath.
+func parseRepoURL(basePath, repoURL string) (string, string, bool) {
+	parts, err := forge.SplitPath(basePath, repoURL)
+	if err != nil {
+		return "", "", false
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), true
+}
+
+// digestMarker identifies the digest for the period ending at until.
+func digestMarker(until time.Time) string {
+	return "<!-- shepherd-digest:" + until.UTC().Format(time.DateOnly) + " -->"
+}
+
+func formatDigest(rd *repoDigest, since, until time.Time) string {
+	merged := 0
+	for _, pr := range rd.PRs {
+		if pr.Merged {
+			merged++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### Shepherd weekly digest: %s – %s\n\n",
+		since.UTC().Format("Jan 2"), until.Add(-time.Second).UTC().Format("Jan 2, 2006"))
+	sb.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&sb, "| Tasks run | %d |\n", rd.Tasks)
+	fmt.Fprintf(&sb, "| Succeeded | %d |\n", rd.Succeeded)
+	fmt.Fprintf(&sb, "| Failed | %d |\n", rd.Failed)
+	if rd.Active > 0 {
+		fmt.Fprintf(&sb, "| Still running | %d |\n", rd.Active)
+	}
+	fmt.Fprintf(&sb, "| PRs opened | %d |\n", len(rd.PRs))
+	fmt.Fprintf(&sb, "| PRs merged | %d |\n", merged)
+	fmt.Fprintf(&sb, "| Cost | $%.2f |\n", rd.CostUSD)
+
+	if len(rd.PRs) > 0 {
+		sb.WriteString("\n<details><summary>Pull requests</summary>\n\n")
+		for _, pr := range rd.PRs {
+			if pr.Merged {
+				fmt.Fprintf(&sb, "- %s (merged)\n", pr.URL)
+			} else {
+				fmt.Fprintf(&sb, "- %s\n", pr.URL)
+			}
+		}
+		sb.WriteString("\n</details>\n")
+	}
+
+	sb.WriteString("\n" + digestMarker(until) + "\n")
+	return sb.String()
+}
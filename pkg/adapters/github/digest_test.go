@@ -0,0 +1,229 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// Digest period used by the tests: Mon Oct 5 09:00 UTC to Mon Oct 12 09:00 UTC.
+var (
+	testDigestSince = time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC)
+	testDigestUntil = time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+)
+
+func digestTask(id, repoURL, created, phase, prURL string, cost float64) api.TaskResponse {
+	return api.TaskResponse{
+		ID:        id,
+		Repo:      api.RepoRequest{URL: repoURL},
+		CreatedAt: created,
+		Status:    api.TaskStatusSummary{Phase: phase, PRURL: prURL, CostUSD: cost},
+	}
+}
+
+func TestNextDigestTime(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"later the same week", time.Date(2026, 10, 7, 15, 0, 0, 0, time.UTC), testDigestUntil},
+		{"earlier the same day", time.Date(2026, 10, 12, 8, 59, 0, 0, time.UTC), testDigestUntil},
+		{"exactly at the digest time", testDigestUntil, testDigestUntil.AddDate(0, 0, 7)},
+		{"later the same day", time.Date(2026, 10, 12, 10, 0, 0, 0, time.UTC), testDigestUntil.AddDate(0, 0, 7)},
+		{"non-UTC clock", time.Date(2026, 10, 12, 10, 0, 0, 0, time.FixedZone("CEST", 2*3600)), testDigestUntil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextDigestTime(tt.now, time.Monday, 9))
+		})
+	}
+}
+
+func TestParseRepoURL(t *testing.T) {
+	owner, repo, ok := parseRepoURL("", "https://github.com/org/repo.git")
+	require.True(t, ok)
+	assert.Equal(t, "org", owner)
+	assert.Equal(t, "repo", repo)
+
+	_, _, ok = parseRepoURL("", "https://github.com/org")
+	assert.False(t, ok)
+
+	owner, repo, ok = parseRepoURL("/github", "https://proxy.example.com/github/org/repo")
+	require.True(t, ok)
+	assert.Equal(t, "org", owner)
+	assert.Equal(t, "repo", repo)
+}
+
+func TestSummarizeTasks(t *testing.T) {
+	tasks := []api.TaskResponse{
+		digestTask("t1", "https://github.com/org/repo", "2026-10-05T09:00:00Z", "Succeeded", "https://github.com/org/repo/pull/1", 1.5),
+		digestTask("t2", "https://github.com/org/repo.git", "2026-10-06T12:00:00Z", "Failed", "", 0.25),
+		digestTask("t3", "https://github.com/org/repo", "2026-10-11T23:00:00Z", "Running", "", 0),
+		digestTask("t4", "https://github.com/org/other", "2026-10-08T00:00:00Z", "TimedOut", "", 2),
+		// Outside the period
+		digestTask("t5", "https://github.com/org/repo", "2026-10-05T08:59:59Z", "Succeeded", "", 5),
+		digestTask("t6", "https://github.com/org/repo", "2026-10-12T09:00:00Z", "Succeeded", "", 5),
+	}
+
+	digests := summarizeTasks("", tasks, testDigestSince, testDigestUntil)
+	require.Len(t, digests, 2)
+
+	assert.Equal(t, &repoDigest{
+		Owner: "org", Repo: "other", Tasks: 1, Failed: 1, CostUSD: 2,
+	}, digests[0])
+	assert.Equal(t, &repoDigest{
+		Owner: "org", Repo: "repo", Tasks: 3, Succeeded: 1, Failed: 1, Active: 1,
+		PRs:     []digestPR{{URL: "https://github.com/org/repo/pull/1"}},
+		CostUSD: 1.75,
+	}, digests[1])
+}
+
+func TestFormatDigest(t *testing.T) {
+	body := formatDigest(&repoDigest{
+		Owner: "org", Repo: "repo", Tasks: 3, Succeeded: 2, Failed: 1,
+		PRs: []digestPR{
+			{URL: "https://github.com/org/repo/pull/1", Merged: true},
+			{URL: "https://github.com/org/repo/pull/2"},
+		},
+		CostUSD: 1.754,
+	}, testDigestSince, testDigestUntil)
+
+	assert.Contains(t, body, "Oct 5 – Oct 12, 2026")
+	assert.Contains(t, body, "| Tasks run | 3 |")
+	assert.Contains(t, body, "| PRs opened | 2 |")
+	assert.Contains(t, body, "| PRs merged | 1 |")
+	assert.Contains(t, body, "| Cost | $1.75 |")
+	assert.Contains(t, body, "- https://github.com/org/repo/pull/1 (merged)")
+	assert.NotContains(t, body, "Still running")
+	assert.Contains(t, body, "<!-- shepherd-digest:2026-10-12 -->")
+}
+
+// digestRecorder captures the requests received by the fake GitHub API.
+type digestRecorder struct {
+	mu             sync.Mutex
+	createdIssue   map[string]any
+	comment        string
+	existingMarker string
+}
+
+func newDigestServers(t *testing.T, rec *digestRecorder, existingIssue bool) (*httptest.Server, *httptest.Server) {
+	t.Helper()
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "createdAt", r.URL.Query().Get("sort"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]api.TaskResponse{
+			digestTask("t1", "https://github.com/org/repo", "2026-10-06T10:00:00Z", "Succeeded", "https://github.com/org/repo/pull/5", 0.5),
+		})
+	}))
+	t.Cleanup(apiServer.Close)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/org/repo/pulls/5/merge", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /api/v3/repos/org/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, digestLabel, r.URL.Query().Get("labels"))
+		if existingIssue {
+			_, _ = w.Write([]byte(`[{"number":9}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("POST /api/v3/repos/org/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		_ = json.NewDecoder(r.Body).Decode(&rec.createdIssue)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number":9}`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/org/repo/issues/9/comments", func(w http.ResponseWriter, _ *http.Request) {
+		if rec.existingMarker == "" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `[{"id":1,"body":%q}]`, "old digest\n"+rec.existingMarker)
+	})
+	mux.HandleFunc("POST /api/v3/repos/org/repo/issues/9/comments", func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		rec.comment = body["body"]
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":2}`))
+	})
+	ghServer := httptest.NewServer(mux)
+	t.Cleanup(ghServer.Close)
+
+	return apiServer, ghServer
+}
+
+func TestDigester_Publish(t *testing.T) {
+	t.Run("creates digest issue and posts summary", func(t *testing.T) {
+		rec := &digestRecorder{}
+		apiServer, ghServer := newDigestServers(t, rec, false)
+		d := NewDigester(newTestClientFromServer(t, ghServer), NewAPIClient(apiServer.URL),
+			DigestConfig{Enabled: true}, ctrl.Log.WithName("test"))
+
+		require.NoError(t, d.Publish(context.Background(), testDigestSince, testDigestUntil))
+
+		require.NotNil(t, rec.createdIssue)
+		assert.Equal(t, digestIssueTitle, rec.createdIssue["title"])
+		assert.Equal(t, []any{digestLabel}, rec.createdIssue["labels"])
+		assert.Contains(t, rec.comment, "| Tasks run | 1 |")
+		assert.Contains(t, rec.comment, "| PRs merged | 1 |")
+		assert.Contains(t, rec.comment, "| Cost | $0.50 |")
+	})
+
+	t.Run("reuses existing digest issue", func(t *testing.T) {
+		rec := &digestRecorder{}
+		apiServer, ghServer := newDigestServers(t, rec, true)
+		d := NewDigester(newTestClientFromServer(t, ghServer), NewAPIClient(apiServer.URL),
+			DigestConfig{Enabled: true}, ctrl.Log.WithName("test"))
+
+		require.NoError(t, d.Publish(context.Background(), testDigestSince, testDigestUntil))
+
+		assert.Nil(t, rec.createdIssue)
+		assert.NotEmpty(t, rec.comment)
+	})
+
+	t.Run("skips period that was already posted", func(t *testing.T) {
+		rec := &digestRecorder{existingMarker: digestMarker(testDigestUntil)}
+		apiServer, ghServer := newDigestServers(t, rec, true)
+		d := NewDigester(newTestClientFromServer(t, ghServer), NewAPIClient(apiServer.URL),
+			DigestConfig{Enabled: true}, ctrl.Log.WithName("test"))
+
+		require.NoError(t, d.Publish(context.Background(), testDigestSince, testDigestUntil))
+
+		assert.Empty(t, rec.comment)
+	})
+}
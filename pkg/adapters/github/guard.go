@@ -0,0 +1,83 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// EventGuard bounds the work done for webhook events and callbacks, so a
+// hung GitHub or API call, or a panic while handling one event, cannot tie
+// up the adapter: each event gets a deadline, at most a fixed number are
+// handled at once, and panics are recovered.
+type EventGuard struct {
+	sem     chan struct{}
+	timeout time.Duration
+	log     logr.Logger
+}
+
+// NewEventGuard returns a guard running at most maxConcurrent events at
+// once, each for at most timeout.
+func NewEventGuard(maxConcurrent int, timeout time.Duration, log logr.Logger) *EventGuard {
+	return &EventGuard{
+		sem:     make(chan struct{}, maxConcurrent),
+		timeout: timeout,
+		log:     log,
+	}
+}
+
+// Run runs fn for the event named kind, and reports false without running
+// it if the guard is already at capacity. fn's context keeps the values of
+// ctx but not its cancellation: a webhook sender that stops waiting for the
+// response must not abort an event halfway, leaving a task without its
+// comment. A panic in fn is logged and counts as handled. A nil guard runs
+// fn directly.
+func (g *EventGuard) Run(ctx context.Context, kind string, fn func(ctx context.Context)) (ran bool) {
+	if g == nil {
+		fn(ctx)
+		return true
+	}
+	select {
+	case g.sem <- struct{}{}:
+	default:
+		g.log.Info("too many events in progress, rejecting", "kind", kind, "limit", cap(g.sem))
+		return false
+	}
+	defer func() { <-g.sem }()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), g.timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			g.log.Error(fmt.Errorf("panic: %v", r), "recovered from panic while handling event",
+				"kind", kind, "stack", string(debug.Stack()))
+			ran = true
+		}
+	}()
+
+	fn(ctx)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		g.log.Info("event handling ran into its deadline", "kind", kind, "timeout", g.timeout)
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventGuard_Deadline(t *testing.T) {
+	g := NewEventGuard(1, 50*time.Millisecond, logr.Discard())
+
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var err error
+	ran := g.Run(parent, "test", func(ctx context.Context) {
+		// The sender giving up does not cancel the event, its deadline does.
+		require.NoError(t, ctx.Err())
+		<-ctx.Done()
+		err = ctx.Err()
+	})
+	assert.True(t, ran)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestEventGuard_RecoversPanic(t *testing.T) {
+	g := NewEventGuard(1, time.Second, logr.Discard())
+
+	assert.True(t, g.Run(context.Background(), "test", func(context.Context) { panic("boom") }))
+	// The slot was released despite the panic.
+	assert.True(t, g.Run(context.Background(), "test", func(context.Context) {}))
+}
+
+func TestEventGuard_Capacity(t *testing.T) {
+	g := NewEventGuard(1, time.Second, logr.Discard())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan bool)
+	go func() {
+		done <- g.Run(context.Background(), "slow", func(context.Context) {
+			close(started)
+			<-release
+		})
+	}()
+	<-started
+
+	assert.False(t, g.Run(context.Background(), "test", func(context.Context) {
+		t.Error("ran over capacity")
+	}))
+
+	close(release)
+	assert.True(t, <-done)
+	assert.True(t, g.Run(context.Background(), "test", func(context.Context) {}))
+}
+
+func TestEventGuard_Nil(t *testing.T) {
+	var g *EventGuard
+	ran := false
+	assert.True(t, g.Run(context.Background(), "test", func(context.Context) { ran = true }))
+	assert.True(t, ran)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type issueContextEntry struct {
+	updatedAt time.Time
+	context   string
+	storedAt  time.Time
+}
+
+// issueContextCache keeps the task context built for an issue until the
+// issue changes. GitHub bumps an issue's updated_at for edits and new
+// comments, so an entry stored for the same updated_at is still accurate
+// and the comments need not be fetched again.
+type issueContextCache struct {
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]issueContextEntry
+}
+
+func newIssueContextCache(maxEntries int) *issueContextCache {
+	return &issueContextCache{
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    map[string]issueContextEntry{},
+	}
+}
+
+func issueKey(owner, repo string, number int) string {
+	return strings.ToLower(fmt.Sprintf("%s/%s#%d", owner, repo, number))
+}
+
+// get returns the context cached for the issue at updatedAt.
+func (c *issueContextCache) get(owner, repo string, number int, updatedAt time.Time) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[issueKey(owner, repo, number)]
+	if !ok || !entry.updatedAt.Equal(updatedAt) {
+		return "", false
+	}
+	return entry.context, true
+}
+
+// put stores the context built for the issue at updatedAt, evicting the
+// oldest entry when the cache is full.
+func (c *issueContextCache) put(owner, repo string, number int, updatedAt time.Time, context string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := issueKey(owner, repo, number)
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		var oldest string
+		for k, e := range c.entries {
+			if oldest == "" || e.storedAt.Before(c.entries[oldest].storedAt) {
+				oldest = k
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	c.entries[key] = issueContextEntry{updatedAt: updatedAt, context: context, storedAt: c.now()}
+}
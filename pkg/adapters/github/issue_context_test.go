@@ -0,0 +1,88 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v75/github"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestIssueContextCache_Evicts(t *testing.T) {
+	c := newIssueContextCache(2)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	updated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	c.put("org", "repo", 1, updated, "one")
+	now = now.Add(time.Second)
+	c.put("org", "repo", 2, updated, "two")
+	now = now.Add(time.Second)
+	c.put("org", "repo", 3, updated, "three")
+
+	_, ok := c.get("org", "repo", 1, updated)
+	assert.False(t, ok, "oldest entry evicted")
+	got, ok := c.get("Org", "Repo", 3, updated)
+	assert.True(t, ok)
+	assert.Equal(t, "three", got)
+
+	_, ok = c.get("org", "repo", 2, updated.Add(time.Minute))
+	assert.False(t, ok, "issue changed since")
+}
+
+func TestWebhookHandler_IssueContext(t *testing.T) {
+	var fetches atomic.Int32
+	var fail atomic.Bool
+	client, srv := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"user":{"login":"alice"},"body":"Steps to reproduce"}]`))
+	}))
+	defer srv.Close()
+
+	h := NewWebhookHandler("", client, nil, nil, "", "default", ctrl.Log.WithName("test"), WithIssueContextCache(10))
+	ctx := context.Background()
+	issue := &gh.Issue{
+		Number:    gh.Ptr(42),
+		Body:      gh.Ptr("Login crashes"),
+		UpdatedAt: &gh.Timestamp{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	first := h.issueContext(ctx, "org", "repo", issue)
+	assert.Contains(t, first, "Steps to reproduce")
+	assert.Equal(t, first, h.issueContext(ctx, "org", "repo", issue))
+	assert.Equal(t, int32(1), fetches.Load(), "unchanged issue reuses its context")
+
+	issue.UpdatedAt = &gh.Timestamp{Time: issue.UpdatedAt.Add(time.Minute)}
+	fail.Store(true)
+	partial := h.issueContext(ctx, "org", "repo", issue)
+	assert.NotContains(t, partial, "Steps to reproduce")
+
+	fail.Store(false)
+	assert.Contains(t, h.issueContext(ctx, "org", "repo", issue), "Steps to reproduce", "incomplete context is not cached")
+	assert.Equal(t, int32(3), fetches.Load())
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"strings"
+
+	gh "github.com/google/go-github/v75/github"
+)
+
+// WithTriggerLabel creates a task for an issue when label is applied to
+// it, with the issue's body as the task's description.
+func WithTriggerLabel(label string) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.triggerLabel = label
+	}
+}
+
+// handleLabeled creates a task for the issue of event if the label
+// applied to it is the trigger label.
+func (h *WebhookHandler) handleLabeled(ctx context.Context, event *gh.IssuesEvent) {
+	// GitHub compares label names without regard to case too.
+	if h.triggerLabel == "" || !strings.EqualFold(event.GetLabel().GetName(), h.triggerLabel) {
+		return
+	}
+
+	h.log.Info("processing trigger label",
+		"repo", event.GetRepo().GetFullName(),
+		"issue", event.GetIssue().GetNumber(),
+		"user", event.GetSender().GetLogin(),
+	)
+
+	if !h.authorized(ctx, event.GetRepo(), event.GetIssue().GetNumber(), event.GetSender().GetLogin()) {
+		return
+	}
+
+	h.processIssueTask(ctx, issueTrigger{
+		repo:        event.GetRepo(),
+		issue:       event.GetIssue(),
+		requestedBy: event.GetSender().GetLogin(),
+	}, taskCommand{Description: issueDescription(event.GetIssue())})
+}
+
+// issueDescription is the description of a task triggered without a
+// comment, by a label or an assignment: the issue's body, or its title if
+// the body is empty.
+func issueDescription(issue *gh.Issue) string {
+	if body := strings.TrimSpace(issue.GetBody()); body != "" {
+		return body
+	}
+	return issue.GetTitle()
+}
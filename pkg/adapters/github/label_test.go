@@ -0,0 +1,105 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	gh "github.com/google/go-github/v75/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// labeledEvent returns an issues event for label applied to issue 7 by
+// alice.
+func labeledEvent(t *testing.T, action, label, issueBody string) []byte {
+	t.Helper()
+	body, err := json.Marshal(gh.IssuesEvent{
+		Action: gh.Ptr(action),
+		Repo:   testRepository(),
+		Issue: &gh.Issue{
+			Number:  gh.Ptr(7),
+			Title:   gh.Ptr("Login fails with SSO"),
+			Body:    gh.Ptr(issueBody),
+			HTMLURL: gh.Ptr("https://github.com/org/repo/issues/7"),
+		},
+		Label:  &gh.Label{Name: gh.Ptr(label)},
+		Sender: &gh.User{Login: gh.Ptr("alice")},
+	})
+	require.NoError(t, err)
+	return body
+}
+
+func TestWebhookHandler_TriggerLabel(t *testing.T) {
+	f := &fakePullRequest{}
+	handler, callbackHandler := newPullRequestTestHandler(t, f)
+	WithTriggerLabel("shepherd")(handler)
+
+	handler.handleIssues(context.Background(), labeledEvent(t, "labeled", "Shepherd", "Redirect back after SSO login."))
+
+	require.Len(t, f.created, 1)
+	req := f.created[0]
+	assert.Equal(t, "Redirect back after SSO login.", req.Task.Description)
+	assert.Equal(t, "https://github.com/org/repo/issues/7", req.Task.SourceURL)
+	assert.Equal(t, "alice", req.Labels["shepherd.io/requested-by"])
+	assert.Contains(t, req.Task.Context, "Please also update the docs")
+
+	require.Len(t, f.comments, 1)
+	assert.True(t, isAcknowledgment(f.comments[0], "task-pr"))
+	_, ok := storedTask(callbackHandler, "task-pr")
+	assert.True(t, ok)
+}
+
+func TestWebhookHandler_TriggerLabelWithoutBody(t *testing.T) {
+	f := &fakePullRequest{}
+	handler, _ := newPullRequestTestHandler(t, f)
+	WithTriggerLabel("shepherd")(handler)
+
+	handler.handleIssues(context.Background(), labeledEvent(t, "labeled", "shepherd", "  "))
+
+	require.Len(t, f.created, 1)
+	assert.Equal(t, "Login fails with SSO", f.created[0].Task.Description)
+}
+
+func TestWebhookHandler_IgnoredLabels(t *testing.T) {
+	tests := []struct {
+		name         string
+		triggerLabel string
+		action       string
+		label        string
+	}{
+		{name: "other label", triggerLabel: "shepherd", action: "labeled", label: "bug"},
+		{name: "label removed", triggerLabel: "shepherd", action: "unlabeled", label: "shepherd"},
+		{name: "no trigger label", action: "labeled", label: "shepherd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakePullRequest{}
+			handler, _ := newPullRequestTestHandler(t, f)
+			if tt.triggerLabel != "" {
+				WithTriggerLabel(tt.triggerLabel)(handler)
+			}
+
+			handler.handleIssues(context.Background(), labeledEvent(t, tt.action, tt.label, "Fix it"))
+
+			assert.Empty(t, f.created)
+			assert.Empty(t, f.comments)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// Labels that link a task to the earlier task of its issue it follows.
+const (
+	// retryOfLabelKey names the failed, timed out or cancelled task that
+	// a task retries.
+	retryOfLabelKey = api.RetryOfLabel
+	// supersedesLabelKey names the succeeded task whose result a task
+	// replaces.
+	supersedesLabelKey = api.SupersedesLabel
+)
+
+// taskLineage relates a new task of an issue to the issue's most recent
+// finished task, ignoring verification tasks. It returns the label to set
+// on the new task and its description for comments, or empty strings if
+// the issue has no earlier task.
+func taskLineage(tasks []api.TaskResponse) (labelKey, previousID, lineage string) {
+	var previous *api.TaskResponse
+	for i := range tasks {
+		if tasks[i].Task.SourceType != api.SourceTypeVerification && tasks[i].CompletionTime != nil {
+			previous = &tasks[i]
+		}
+	}
+	if previous == nil {
+		return "", "", ""
+	}
+	switch previous.Status.Phase {
+	case "Succeeded":
+		lineage = "supersedes " + previous.ID
+		if previous.Status.PRURL != "" && previous.Task.SourceType != api.SourceTypePullRequest {
+			lineage += ", which opened " + previous.Status.PRURL
+		}
+		return supersedesLabelKey, previous.ID, lineage
+	case "TimedOut":
+		return retryOfLabelKey, previous.ID, "retries " + previous.ID + ", which timed out"
+	case "Cancelled":
+		return retryOfLabelKey, previous.ID, "retries " + previous.ID + ", which was cancelled"
+	default:
+		return retryOfLabelKey, previous.ID, "retries " + previous.ID + ", which failed"
+	}
+}
+
+// issueLineage looks up the earlier tasks of an issue and relates a new
+// task to them; see taskLineage. Lineage is informational, so a failed
+// lookup only leaves it out.
+func (h *WebhookHandler) issueLineage(ctx context.Context, repoLabel, issueLabel string) (labelKey, previousID, lineage string) {
+	tasks, err := h.apiClient.ListIssueTasks(ctx, repoLabel, issueLabel)
+	if err != nil {
+		h.log.Error(err, "failed to list earlier tasks of the issue, leaving out lineage")
+		return "", "", ""
+	}
+	return taskLineage(tasks)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func TestTaskLineage(t *testing.T) {
+	completed := "2026-10-10T08:00:00Z"
+	task := func(id, sourceType, phase string) api.TaskResponse {
+		tr := api.TaskResponse{ID: id, Task: api.TaskRequest{SourceType: sourceType}}
+		tr.Status.Phase = phase
+		if phase != "Running" {
+			tr.CompletionTime = &completed
+		}
+		return tr
+	}
+	succeeded := task("task-2", api.SourceTypeIssue, "Succeeded")
+	succeeded.Status.PRURL = "https://github.com/org/repo/pull/5"
+	pushed := task("task-4", api.SourceTypePullRequest, "Succeeded")
+	pushed.Status.PRURL = "https://github.com/org/repo/pull/5"
+
+	tests := []struct {
+		name        string
+		tasks       []api.TaskResponse
+		wantKey     string
+		wantID      string
+		wantLineage string
+	}{
+		{name: "first task"},
+		{
+			name:    "after a failure",
+			tasks:   []api.TaskResponse{task("task-1", api.SourceTypeIssue, "Failed")},
+			wantKey: retryOfLabelKey, wantID: "task-1", wantLineage: "retries task-1, which failed",
+		},
+		{
+			name:    "after a timeout",
+			tasks:   []api.TaskResponse{task("task-1", api.SourceTypeIssue, "TimedOut")},
+			wantKey: retryOfLabelKey, wantID: "task-1", wantLineage: "retries task-1, which timed out",
+		},
+		{
+			name:    "after a cancellation",
+			tasks:   []api.TaskResponse{task("task-1", api.SourceTypeIssue, "Cancelled")},
+			wantKey: retryOfLabelKey, wantID: "task-1", wantLineage: "retries task-1, which was cancelled",
+		},
+		{
+			name: "after a success, ignoring verification and older tasks",
+			tasks: []api.TaskResponse{
+				task("task-1", api.SourceTypeIssue, "Failed"),
+				succeeded,
+				task("task-3", api.SourceTypeVerification, "Failed"),
+			},
+			wantKey: supersedesLabelKey, wantID: "task-2",
+			wantLineage: "supersedes task-2, which opened https://github.com/org/repo/pull/5",
+		},
+		{
+			name:    "after a task that pushed to the PR",
+			tasks:   []api.TaskResponse{pushed},
+			wantKey: supersedesLabelKey, wantID: "task-4", wantLineage: "supersedes task-4",
+		},
+		{
+			name:  "only unfinished tasks",
+			tasks: []api.TaskResponse{task("task-1", api.SourceTypeIssue, "Running")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, id, lineage := taskLineage(tt.tasks)
+			assert.Equal(t, tt.wantKey, key)
+			assert.Equal(t, tt.wantID, id)
+			assert.Equal(t, tt.wantLineage, lineage)
+		})
+	}
+}
+
+func TestWithThreadHeader(t *testing.T) {
+	assert.Equal(t, "> **task-1**\n\nDone.", withThreadHeader("Done.", "task-1", TaskMetadata{}))
+	assert.Equal(t,
+		"> **task-2** retries task-1, which failed · [started here](https://github.com/c/1)\n\nDone.",
+		withThreadHeader("Done.", "task-2", TaskMetadata{
+			Lineage: "retries task-1, which failed",
+			AckURL:  "https://github.com/c/1",
+		}))
+}
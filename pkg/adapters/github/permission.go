@@ -0,0 +1,82 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"slices"
+
+	gh "github.com/google/go-github/v75/github"
+)
+
+// PermissionLevels are the repository permissions WithMinPermission
+// accepts, from lowest to highest.
+var PermissionLevels = []string{"read", "triage", "write", "maintain", "admin"}
+
+// hasPermission reports whether a user with permission level has at least
+// permission minLevel. Levels that are not in PermissionLevels, such as
+// "none", have no permission.
+func hasPermission(level, minLevel string) bool {
+	return slices.Index(PermissionLevels, level) >= slices.Index(PermissionLevels, minLevel)
+}
+
+// WithMinPermission only creates tasks for mentions by users with at least
+// permission level on the repository, one of PermissionLevels. Without it,
+// anyone who can comment can trigger a task.
+func WithMinPermission(level string) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.minPermission = level
+	}
+}
+
+// authorized reports whether user may trigger tasks in repo: the
+// repository is allowlisted and the user has the required permission. If
+// not, it answers the mention on issue or PR number with a refusal. A
+// permission that cannot be looked up is treated as none.
+func (h *WebhookHandler) authorized(ctx context.Context, repo *gh.Repository, number int, user string) bool {
+	owner := repo.GetOwner().GetLogin()
+	name := repo.GetName()
+	if !h.allowlist.Allows(repo.GetFullName()) {
+		h.log.Info("repository is not allowlisted, not creating task", "repo", repo.GetFullName())
+		if commentErr := h.ghClient.PostComment(ctx, owner, name, number,
+			formatRepoNotEnabled(repo.GetFullName())); commentErr != nil {
+			h.log.Error(commentErr, "failed to post repository comment")
+		}
+		return false
+	}
+	if h.minPermission == "" {
+		return true
+	}
+
+	var comment string
+	level, err := h.ghClient.GetPermissionLevel(ctx, owner, name, user)
+	switch {
+	case err != nil:
+		h.log.Error(err, "failed to look up commenter permission, not creating task", "user", user)
+		comment = formatPermissionUnknown(user)
+	case hasPermission(level, h.minPermission):
+		return true
+	default:
+		h.log.Info("commenter lacks permission, not creating task", "user", user, "permission", level,
+			"required", h.minPermission)
+		comment = formatPermissionDenied(user, h.minPermission)
+	}
+	if commentErr := h.ghClient.PostComment(ctx, owner, name, number, comment); commentErr != nil {
+		h.log.Error(commentErr, "failed to post permission comment")
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestHasPermission(t *testing.T) {
+	assert.True(t, hasPermission("admin", "write"))
+	assert.True(t, hasPermission("write", "write"))
+	assert.True(t, hasPermission("maintain", "triage"))
+	assert.False(t, hasPermission("triage", "write"))
+	assert.False(t, hasPermission("none", "read"))
+	assert.False(t, hasPermission("custom-role", "read"))
+}
+
+func TestWebhookHandler_MinPermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		permission  string // "" makes the lookup fail
+		roleName    string
+		wantCreated bool
+		wantComment string
+	}{
+		{name: "write", permission: "write", roleName: "write", wantCreated: true},
+		{name: "maintain role", permission: "write", roleName: "maintain", wantCreated: true},
+		{name: "triage role", permission: "read", roleName: "triage",
+			wantComment: "Sorry @testuser, only users with write access to this repository can ask Shepherd to work on it."},
+		{name: "no access", permission: "none", wantComment: "only users with write access"},
+		{name: "lookup fails", wantComment: "could not check your access"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comments []string
+			created := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case r.URL.Path == "/api/v3/repos/org/repo/collaborators/testuser/permission":
+					if tt.permission == "" {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					_ = json.NewEncoder(w).Encode(map[string]string{"permission": tt.permission, "role_name": tt.roleName})
+				case r.URL.Path == testGHCommentsPath && r.Method == http.MethodPost:
+					var body map[string]string
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					comments = append(comments, body["body"])
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"id":1}`))
+				case r.URL.Path == testAPITasksPath && r.Method == http.MethodPost:
+					created = true
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"id":"task-1"}`))
+				case r.URL.Path == testAPITasksPath:
+					_, _ = w.Write([]byte(`[]`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`[]`))
+				}
+			}))
+			defer srv.Close()
+
+			ghClient := newTestClientFromServer(t, srv)
+			apiClient := NewAPIClient(srv.URL)
+			callbackHandler := NewCallbackHandler("", ghClient, apiClient, ctrl.Log.WithName("test"))
+			handler := NewWebhookHandler("", ghClient, apiClient, callbackHandler, "http://callback", "default",
+				ctrl.Log.WithName("test"), WithMinPermission("write"))
+
+			body, err := json.Marshal(createTestIssueCommentEvent("org", "repo", 42, "@shepherd fix this"))
+			require.NoError(t, err)
+			handler.handleIssueComment(context.Background(), body)
+
+			assert.Equal(t, tt.wantCreated, created)
+			if tt.wantComment != "" {
+				require.Len(t, comments, 1)
+				assert.Contains(t, comments[0], tt.wantComment)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NissesSenap/shepherd/pkg/forge"
+)
+
+// PRConfig controls how pull requests opened by a runner are prepared for
+// review once the task completes.
+type PRConfig struct {
+	Labels        []string // Labels applied to every PR (e.g., "shepherd", "automated")
+	Reviewers     []string // GitHub users to request reviews from
+	TeamReviewers []string // Team slugs to request reviews from
+	UseCodeowners bool     // Also request reviews from CODEOWNERS of the changed files
+	AutoMerge     bool     // Enable GitHub auto-merge so the PR merges once required checks pass
+	MergeMethod   string   // Auto-merge method: "merge", "squash" (default) or "rebase"
+}
+
+const defaultMergeMethod = "squash"
+
+// enabled reports whether any PR decoration is configured.
+func (c PRConfig) enabled() bool {
+	return len(c.Labels) > 0 || len(c.Reviewers) > 0 || len(c.TeamReviewers) > 0 ||
+		c.UseCodeowners || c.AutoMerge
+}
+
+// pullRequestRef identifies a pull request.
+type pullRequestRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// parsePRURL extracts owner, repo, and PR number from a GitHub pull request URL.
+// Expected format: https://github.com/{owner}/{repo}/pull/{number}, with
+// basePath between host and owner on instances served below a path.
+func parsePRURL(basePath, prURL string) (pullRequestRef, error) {
+	parts, err := forge.SplitPath(basePath, prURL)
+	if err != nil {
+		return pullRequestRef{}, fmt.Errorf("invalid PR URL: %w", err)
+	}
+	if len(parts) < 4 || parts[2] != "pull" {
+		return pullRequestRef{}, fmt.Errorf("unexpected PR URL format: %s", prURL)
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return pullRequestRef{}, fmt.Errorf("invalid PR number in URL: %w", err)
+	}
+	return pullRequestRef{Owner: parts[0], Repo: parts[1], Number: number}, nil
+}
+
+// decoratePR applies the configured labels, review requests and auto-merge
+// setting to a PR. Failures are logged and never block the completion comment.
+func (h *CallbackHandler) decoratePR(ctx context.Context, prURL string) {
+	if !h.prConfig.enabled() {
+		return
+	}
+
+	pr, err := parsePRURL(h.ghClient.pathPrefix(), prURL)
+	if err != nil {
+		h.log.Error(err, "cannot prepare PR for review", "prURL", prURL)
+		return
+	}
+
+	if len(h.prConfig.Labels) > 0 {
+		if err := h.ghClient.AddLabels(ctx, pr.Owner, pr.Repo, pr.Number, h.prConfig.Labels); err != nil {
+			h.log.Error(err, "failed to label PR", "prURL", prURL)
+		}
+	}
+
+	users, teams := h.resolveReviewers(ctx, pr)
+	if len(users) > 0 || len(teams) > 0 {
+		if err := h.ghClient.RequestReviewers(ctx, pr.Owner, pr.Repo, pr.Number, users, teams); err != nil {
+			h.log.Error(err, "failed to request PR reviewers", "prURL", prURL)
+		} else {
+			h.log.Info("requested PR reviewers", "prURL", prURL, "users", users, "teams", teams)
+		}
+	}
+
+	if h.prConfig.AutoMerge {
+		method := h.prConfig.MergeMethod
+		if method == "" {
+			method = defaultMergeMethod
+		}
+		// GitHub refuses auto-merge on PRs that are already mergeable (no
+		// pending required checks); such PRs are left for a human to merge.
+		if err := h.ghClient.EnableAutoMerge(ctx, pr.Owner, pr.Repo, pr.Number, method); err != nil {
+			h.log.Error(err, "failed to enable PR auto-merge", "prURL", prURL)
+		} else {
+			h.log.Info("enabled PR auto-merge", "prURL", prURL, "method", method)
+		}
+	}
+}
+
+// resolveReviewers combines the statically configured reviewers with the
+// CODEOWNERS of the files changed in the PR, if enabled.
+func (h *CallbackHandler) resolveReviewers(ctx context.Context, pr pullRequestRef) (users, teams []string) {
+	users = append(users, h.prConfig.Reviewers...)
+	teams = append(teams, h.prConfig.TeamReviewers...)
+
+	if h.prConfig.UseCodeowners {
+		owners, err := h.codeownersForPR(ctx, pr)
+		if err != nil {
+			h.log.Error(err, "failed to resolve CODEOWNERS, using configured reviewers only",
+				"owner", pr.Owner, "repo", pr.Repo, "pr", pr.Number)
+		}
+		for _, owner := range owners {
+			name, ok := strings.CutPrefix(owner, "@")
+			if !ok {
+				// Email owners cannot be requested as reviewers
+				continue
+			}
+			if org, team, isTeam := strings.Cut(name, "/"); isTeam {
+				// Only teams in the repository's organization can review
+				if strings.EqualFold(org, pr.Owner) {
+					teams = append(teams, team)
+				}
+				continue
+			}
+			users = append(users, name)
+		}
+	}
+
+	return dedupeFold(users), dedupeFold(teams)
+}
+
+// codeownersForPR returns the owners of all files changed in the PR.
+// It returns nil if the repository has no CODEOWNERS file.
+func (h *CallbackHandler) codeownersForPR(ctx context.Context, pr pullRequestRef) ([]string, error) {
+	var content string
+	for _, path := range codeownersPaths {
+		c, found, err := h.ghClient.GetFileContent(ctx, pr.Owner, pr.Repo, path)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			content = c
+			break
+		}
+	}
+	if content == "" {
+		return nil, nil
+	}
+
+	files, err := h.ghClient.ListPullRequestFiles(ctx, pr.Owner, pr.Repo, pr.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := parseCodeowners(content)
+	var owners []string
+	for _, f := range files {
+		owners = append(owners, rules.ownersFor(f)...)
+	}
+	return owners, nil
+}
+
+// dedupeFold removes case-insensitive duplicates while preserving order.
+func dedupeFold(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var out []string
+	for _, v := range values {
+		key := strings.ToLower(v)
+		if v == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,345 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gh "github.com/google/go-github/v75/github"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// pullRequestTrigger is a mention of the adapter's handle on a pull
+// request, either in its conversation or in a review comment on its diff.
+type pullRequestTrigger struct {
+	repo        *gh.Repository
+	pr          *gh.PullRequest
+	requestedBy string
+	// reviewComment is the review comment with the mention, or nil for a
+	// comment in the PR's conversation.
+	reviewComment *gh.PullRequestComment
+	// comment is the conversation comment with the mention, or nil for a
+	// review comment.
+	comment *gh.IssueComment
+}
+
+// handleReviewComment processes pull_request_review_comment events, which
+// GitHub sends for comments on lines of a PR's diff.
+func (h *WebhookHandler) handleReviewComment(ctx context.Context, body []byte) {
+	var event gh.PullRequestReviewCommentEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.log.Error(err, "failed to parse pull_request_review_comment event")
+		return
+	}
+
+	// Only process new comments (not edits or deletes)
+	if event.GetAction() != "created" {
+		return
+	}
+
+	commentBody := event.GetComment().GetBody()
+	if !h.mention.MatchString(commentBody) {
+		return
+	}
+	h.log.Info("processing review comment mention",
+		"repo", event.GetRepo().GetFullName(),
+		"pullRequest", event.GetPullRequest().GetNumber(),
+		"user", event.GetComment().GetUser().GetLogin(),
+	)
+
+	if !h.authorized(ctx, event.GetRepo(), event.GetPullRequest().GetNumber(), event.GetComment().GetUser().GetLogin()) {
+		return
+	}
+
+	cmd, err := parseCommand(h.mention.ReplaceAllString(commentBody, ""))
+	if err != nil {
+		h.rejectCommand(ctx, event.GetRepo(), event.GetPullRequest().GetNumber(), err)
+		return
+	}
+	if cmd.Description == "" && cmd.Mode != api.ModePlan && cmd.Mode != api.ModeReview {
+		cmd.Description = "Address this review comment"
+	}
+	cmd.describe("review comment")
+
+	h.processPullRequestTask(ctx, pullRequestTrigger{
+		repo:          event.GetRepo(),
+		pr:            event.GetPullRequest(),
+		requestedBy:   event.GetComment().GetUser().GetLogin(),
+		reviewComment: event.GetComment(),
+	}, cmd)
+}
+
+// processPullRequestComment handles a mention in a PR's conversation.
+// issue_comment events don't carry the PR's branches, so the PR is looked
+// up first.
+func (h *WebhookHandler) processPullRequestComment(ctx context.Context, event *gh.IssueCommentEvent, cmd taskCommand) {
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repo := event.GetRepo().GetName()
+	number := event.GetIssue().GetNumber()
+
+	pr, err := h.ghClient.GetPullRequest(ctx, owner, repo, number)
+	if err != nil {
+		h.log.Error(err, "failed to look up pull request", "pullRequest", number)
+		if commentErr := h.ghClient.PostComment(ctx, owner, repo, number,
+			formatFailed("Failed to look up the pull request", h.handle)); commentErr != nil {
+			h.log.Error(commentErr, "failed to post error comment")
+		}
+		return
+	}
+
+	h.processPullRequestTask(ctx, pullRequestTrigger{
+		repo:        event.GetRepo(),
+		pr:          pr,
+		requestedBy: event.GetComment().GetUser().GetLogin(),
+		comment:     event.GetComment(),
+	}, cmd)
+}
+
+// processPullRequestTask creates a task that works on the branch of an
+// existing PR and pushes its commits there, instead of opening a new PR.
+func (h *WebhookHandler) processPullRequestTask(ctx context.Context, trigger pullRequestTrigger, cmd taskCommand) {
+	owner := trigger.repo.GetOwner().GetLogin()
+	repo := trigger.repo.GetName()
+	number := trigger.pr.GetNumber()
+	prURL := trigger.pr.GetHTMLURL()
+
+	if cmd.Branch != "" {
+		h.log.Info("not working on pull request", "pullRequest", number, "reason", "branch given")
+		if commentErr := h.ghClient.PostComment(ctx, owner, repo, number, formatBranchOnPullRequest()); commentErr != nil {
+			h.log.Error(commentErr, "failed to post comment")
+		}
+		return
+	}
+	if reason := h.unworkablePullRequest(trigger); reason != "" {
+		h.log.Info("not working on pull request", "pullRequest", number, "reason", reason)
+		if commentErr := h.ghClient.PostComment(ctx, owner, repo, number, reason); commentErr != nil {
+			h.log.Error(commentErr, "failed to post comment")
+		}
+		return
+	}
+
+	cfg, ok := h.repoConfig(ctx, trigger.repo, number, cmd.command())
+	if !ok {
+		return
+	}
+
+	repoLabel := strings.ReplaceAll(trigger.repo.GetFullName(), "/", "-")
+	numberLabel := strconv.Itoa(number)
+
+	task, err := h.apiClient.GetActiveTask(ctx, prURL)
+	if err != nil {
+		h.log.Error(err, "failed to check for active tasks")
+	}
+	if task != nil {
+		h.log.Info("task already running", logging.TaskID, task.ID, "status", task.Status.Phase)
+		if commentErr := h.ghClient.PostComment(ctx, owner, repo, number,
+			formatAlreadyRunning(task.ID, task.Status.Phase)); commentErr != nil {
+			h.log.Error(commentErr, "failed to post already-running comment")
+		}
+		return
+	}
+
+	createReq := api.CreateTaskRequest{
+		Repo: api.RepoRequest{
+			URL: trigger.repo.GetCloneURL(),
+			Ref: trigger.pr.GetHead().GetRef(),
+		},
+		Task: api.TaskRequest{
+			Description: cmd.Description,
+			Context:     h.pullRequestContext(ctx, trigger),
+			SourceURL:   prURL,
+			SourceType:  api.SourceTypePullRequest,
+			SourceID:    numberLabel,
+		},
+		Callback: h.callbackURL,
+		Runner: &api.RunnerConfig{
+			SandboxTemplateName: h.defaultSandboxTemplate,
+		},
+		Labels: map[string]string{
+			"shepherd.io/repo": repoLabel,
+			// PRs and issues share their numbers, so the PR's number
+			// relates the tasks of one PR like those of an issue.
+			"shepherd.io/issue":        numberLabel,
+			"shepherd.io/requested-by": strings.TrimSuffix(trigger.requestedBy, "[bot]"),
+		},
+	}
+	if l := languageLabel(trigger.repo.GetLanguage()); l != "" {
+		createReq.Labels[languageLabelKey] = l
+	}
+	cfg.apply(&createReq)
+	cmd.apply(&createReq)
+	lineageKey, previousID, lineage := h.issueLineage(ctx, repoLabel, numberLabel)
+	if lineageKey != "" {
+		createReq.Labels[lineageKey] = previousID
+	}
+
+	taskResp, err := h.apiClient.CreateTask(ctx, createReq)
+	if err != nil {
+		h.reportCreateFailure(ctx, owner, repo, number, err)
+		return
+	}
+
+	h.log.Info("created pull request task", logging.TaskID, taskResp.ID, logging.CorrelationID, taskResp.CorrelationID,
+		"pullRequest", number, "branch", createReq.Repo.Ref)
+
+	meta := TaskMetadata{
+		Owner:         owner,
+		Repo:          repo,
+		IssueNumber:   number,
+		PullRequest:   true,
+		Mode:          cmd.Mode,
+		CorrelationID: taskResp.CorrelationID,
+		Lineage:       lineage,
+	}
+	if c := trigger.reviewComment; c != nil {
+		h.reactTo(&meta, c.GetID(), c.GetHTMLURL(), true)
+	} else {
+		h.reactTo(&meta, trigger.comment.GetID(), trigger.comment.GetHTMLURL(), false)
+	}
+	h.acknowledge(ctx, taskResp, meta)
+}
+
+// unworkablePullRequest returns the comment explaining why a task cannot
+// push to the PR's branch, or "" if it can. The runner's token only has
+// access to the repository itself, so it cannot push to branches of forks.
+func (h *WebhookHandler) unworkablePullRequest(trigger pullRequestTrigger) string {
+	if trigger.pr.GetState() != "open" {
+		return formatPullRequestClosed()
+	}
+	head := trigger.pr.GetHead().GetRepo()
+	if head == nil || !strings.EqualFold(head.GetFullName(), trigger.repo.GetFullName()) {
+		return formatPullRequestFork(h.handle)
+	}
+	return ""
+}
+
+// maxDiffContextSize caps the part of the context taken up by the PR's
+// diff, so the discussion around it is never crowded out.
+const maxDiffContextSize = maxContextSize / 2
+
+// pullRequestContext assembles the context of a PR task: the PR's
+// description, the review thread or conversation with the mention, and the
+// PR's diff. Like buildContext, it stays within maxContextSize.
+func (h *WebhookHandler) pullRequestContext(ctx context.Context, trigger pullRequestTrigger) string {
+	owner := trigger.repo.GetOwner().GetLogin()
+	repo := trigger.repo.GetName()
+	pr := trigger.pr
+
+	var sb strings.Builder
+	sb.WriteString("## Pull Request\n\n")
+	fmt.Fprintf(&sb, "**%s** (%s into %s)\n\n", pr.GetTitle(), pr.GetHead().GetRef(), pr.GetBase().GetRef())
+	sb.WriteString(pr.GetBody())
+	sb.WriteString("\n\n")
+
+	budget := maxContextSize - maxDiffContextSize
+	if trigger.reviewComment != nil {
+		h.writeReviewThread(ctx, &sb, owner, repo, pr.GetNumber(), trigger.reviewComment, budget)
+	} else {
+		h.writeConversation(ctx, &sb, owner, repo, pr.GetNumber(), budget)
+	}
+
+	diff, err := h.ghClient.GetPullRequestDiff(ctx, owner, repo, pr.GetNumber())
+	if err != nil {
+		h.log.Error(err, "failed to fetch pull request diff")
+		return sb.String()
+	}
+	if limit := maxContextSize - sb.Len(); len(diff) > limit {
+		// Cut at a line boundary, so the last hunk is not garbled
+		diff = diff[:strings.LastIndexByte(diff[:max(limit, 0)], '\n')+1] + "\n--- Diff truncated due to size limit ---\n"
+		h.log.Info("pull request diff truncated", "pullRequest", pr.GetNumber())
+	}
+	sb.WriteString("## Diff\n\n```diff\n")
+	sb.WriteString(diff)
+	sb.WriteString("\n```\n")
+	return sb.String()
+}
+
+// writeReviewThread writes the diff hunk and the comments of the review
+// thread that comment belongs to, falling back to comment alone if the
+// thread cannot be fetched.
+func (h *WebhookHandler) writeReviewThread(ctx context.Context, sb *strings.Builder, owner, repo string, number int,
+	comment *gh.PullRequestComment, budget int) {
+	sb.WriteString("## Review Thread\n\n")
+	fmt.Fprintf(sb, "On `%s`", comment.GetPath())
+	if line := comment.GetLine(); line != 0 {
+		fmt.Fprintf(sb, ", line %d", line)
+	}
+	sb.WriteString(":\n\n```diff\n")
+	sb.WriteString(comment.GetDiffHunk())
+	sb.WriteString("\n```\n\n")
+
+	root := comment.GetInReplyTo()
+	if root == 0 {
+		root = comment.GetID()
+	}
+	thread := []*gh.PullRequestComment{comment}
+	comments, err := h.ghClient.ListReviewComments(ctx, owner, repo, number)
+	if err != nil {
+		h.log.Error(err, "failed to fetch review comments")
+	} else {
+		thread = thread[:0]
+		for _, c := range comments {
+			if c.GetID() == root || c.GetInReplyTo() == root {
+				thread = append(thread, c)
+			}
+		}
+	}
+	for _, c := range thread {
+		if !writeComment(sb, c.GetUser().GetLogin(), c.GetBody(), budget) {
+			h.log.Info("review thread truncated", "pullRequest", number, "size", sb.Len())
+			break
+		}
+	}
+}
+
+// writeConversation writes the comments of a PR's conversation.
+func (h *WebhookHandler) writeConversation(ctx context.Context, sb *strings.Builder, owner, repo string, number, budget int) {
+	comments, err := h.ghClient.ListIssueComments(ctx, owner, repo, number)
+	if err != nil {
+		h.log.Error(err, "failed to fetch pull request comments")
+		return
+	}
+	if len(comments) == 0 {
+		return
+	}
+	sb.WriteString("## Comments\n\n")
+	for _, c := range comments {
+		if !writeComment(sb, c.GetUser().GetLogin(), c.GetBody(), budget) {
+			h.log.Info("pull request conversation truncated", "pullRequest", number, "size", sb.Len())
+			break
+		}
+	}
+}
+
+// writeComment appends a comment in the form buildContext uses, or a
+// truncation note if it would take sb past budget. It reports whether the
+// comment was written.
+func writeComment(sb *strings.Builder, login, body string, budget int) bool {
+	entry := fmt.Sprintf("**%s** wrote:\n\n%s\n\n---\n\n", login, body)
+	if sb.Len()+len(entry) > budget {
+		sb.WriteString("\n\n--- Context truncated due to size limit ---\n")
+		return false
+	}
+	sb.WriteString(entry)
+	return true
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v75/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+const (
+	testPRPath         = "/api/v3/repos/org/repo/pulls/7"
+	testPRCommentsPath = "/api/v3/repos/org/repo/issues/7/comments"
+	testPRDiff         = "diff --git a/login.go b/login.go\n--- a/login.go\n+++ b/login.go\n@@ -1 +1 @@\n-old\n+new\n"
+)
+
+// fakePullRequest serves the GitHub API of pull request 7 and the Shepherd
+// API's task endpoints, recording created tasks and posted comments.
+type fakePullRequest struct {
+	mu       sync.Mutex
+	headRepo string
+	state    string
+	created  []api.CreateTaskRequest
+	comments []string
+	// reactions are "<path> <content>" of each reaction added; with
+	// failReactions set, adding one fails.
+	reactions     []string
+	failReactions bool
+	// edits are the bodies comments were edited to; with failEdits set,
+	// editing fails.
+	edits     []string
+	failEdits bool
+	// active is the issue's active task, if any, and cancelled the
+	// reasons given for cancelling it; with failCancel set, cancelling
+	// fails.
+	active     *api.TaskResponse
+	cancelled  []string
+	failCancel bool
+	// tasks are the issue's tasks, oldest first, and retried the IDs of
+	// the tasks retried.
+	tasks   []api.TaskResponse
+	retried []string
+	// repoConfig is the content of the repository's configuration file,
+	// which does not exist if empty.
+	repoConfig string
+}
+
+func (f *fakePullRequest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == testPRPath && strings.Contains(r.Header.Get("Accept"), "diff"):
+		_, _ = w.Write([]byte(testPRDiff))
+	case r.URL.Path == testPRPath:
+		_ = json.NewEncoder(w).Encode(testPullRequest(f.headRepo, f.state))
+	case r.URL.Path == testPRPath+"/comments":
+		_, _ = w.Write([]byte(`[
+			{"id":1,"user":{"login":"alice"},"body":"This name is unclear"},
+			{"id":2,"user":{"login":"bob"},"body":"Unrelated thread"},
+			{"id":3,"in_reply_to_id":1,"user":{"login":"carol"},"body":"@shepherd rename it to loginUser"}
+		]`))
+	case r.URL.Path == testPRCommentsPath && r.Method == http.MethodPost:
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.comments = append(f.comments, body["body"])
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1,"created_at":"2026-01-02T03:04:05Z"}`))
+	case strings.HasSuffix(r.URL.Path, "/reactions") && r.Method == http.MethodPost:
+		if f.failReactions {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.reactions = append(f.reactions, strings.TrimPrefix(r.URL.Path, "/api/v3/repos/org/repo/")+" "+body["content"])
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	case strings.HasPrefix(r.URL.Path, "/api/v3/repos/org/repo/issues/comments/") && r.Method == http.MethodPatch:
+		if f.failEdits {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.edits = append(f.edits, body["body"])
+		_, _ = w.Write([]byte(`{"id":1}`))
+	case r.URL.Path == testPRCommentsPath:
+		_, _ = w.Write([]byte(`[{"user":{"login":"alice"},"body":"Please also update the docs"}]`))
+	case r.URL.Path == testGHRepoPath+"/contents/"+DefaultRepoConfigPath && f.repoConfig != "":
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(f.repoConfig)),
+		})
+	case r.URL.Path == testAPITasksPath+"/active" && f.active != nil:
+		_ = json.NewEncoder(w).Encode(f.active)
+	case r.URL.Path == testAPITasksPath+"/active":
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"not found"}`))
+	case f.active != nil && r.URL.Path == testAPITasksPath+"/"+f.active.ID+"/cancel" && r.Method == http.MethodPost:
+		if f.failCancel {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var req api.CancelTaskRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.cancelled = append(f.cancelled, req.Reason)
+		_ = json.NewEncoder(w).Encode(f.active)
+	case strings.HasSuffix(r.URL.Path, "/retry") && r.Method == http.MethodPost:
+		f.retried = append(f.retried, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, testAPITasksPath+"/"), "/retry"))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"task-retry","correlationID":"run-2"}`))
+	case r.URL.Path == testAPITasksPath && r.Method == http.MethodGet:
+		tasks := f.tasks
+		if tasks == nil {
+			tasks = []api.TaskResponse{}
+		}
+		_ = json.NewEncoder(w).Encode(tasks)
+	case r.URL.Path == testAPITasksPath && r.Method == http.MethodPost:
+		var req api.CreateTaskRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.created = append(f.created, req)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"task-pr","correlationID":"run-1"}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func testPullRequest(headRepo, state string) *gh.PullRequest {
+	return &gh.PullRequest{
+		Number:  gh.Ptr(7),
+		State:   gh.Ptr(state),
+		Title:   gh.Ptr("Add login"),
+		Body:    gh.Ptr("Adds the login form"),
+		HTMLURL: gh.Ptr("https://github.com/org/repo/pull/7"),
+		Head: &gh.PullRequestBranch{
+			Ref:  gh.Ptr("feature/login"),
+			Repo: &gh.Repository{FullName: gh.Ptr(headRepo)},
+		},
+		Base: &gh.PullRequestBranch{Ref: gh.Ptr("main")},
+	}
+}
+
+func testRepository() *gh.Repository {
+	return &gh.Repository{
+		Owner:    &gh.User{Login: gh.Ptr("org")},
+		Name:     gh.Ptr("repo"),
+		FullName: gh.Ptr("org/repo"),
+		CloneURL: gh.Ptr("https://github.com/org/repo.git"),
+	}
+}
+
+func newPullRequestTestHandler(t *testing.T, f *fakePullRequest) (*WebhookHandler, *CallbackHandler) {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	ghClient := newTestClientFromServer(t, srv)
+	apiClient := NewAPIClient(srv.URL)
+	callbackHandler := NewCallbackHandler("", ghClient, apiClient, ctrl.Log.WithName("test"))
+	return NewWebhookHandler("", ghClient, apiClient, callbackHandler, "http://callback", "default",
+		ctrl.Log.WithName("test")), callbackHandler
+}
+
+func TestWebhookHandler_ReviewComment(t *testing.T) {
+	f := &fakePullRequest{headRepo: "org/repo", state: "open"}
+	handler, callbackHandler := newPullRequestTestHandler(t, f)
+
+	body, err := json.Marshal(gh.PullRequestReviewCommentEvent{
+		Action:      gh.Ptr("created"),
+		Repo:        testRepository(),
+		PullRequest: testPullRequest("org/repo", "open"),
+		Comment: &gh.PullRequestComment{
+			ID:        gh.Ptr(int64(3)),
+			InReplyTo: gh.Ptr(int64(1)),
+			Body:      gh.Ptr("@shepherd rename it to loginUser"),
+			Path:      gh.Ptr("login.go"),
+			Line:      gh.Ptr(12),
+			DiffHunk:  gh.Ptr("@@ -10,3 +10,3 @@\n+func doLogin() {"),
+			User:      &gh.User{Login: gh.Ptr("carol")},
+		},
+	})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(t, "", body, "pull_request_review_comment"))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Len(t, f.created, 1)
+	req := f.created[0]
+	assert.Equal(t, api.RepoRequest{URL: "https://github.com/org/repo.git", Ref: "feature/login"}, req.Repo,
+		"works on the PR's branch")
+	assert.Equal(t, "rename it to loginUser", req.Task.Description)
+	assert.Equal(t, api.SourceTypePullRequest, req.Task.SourceType)
+	assert.Equal(t, "https://github.com/org/repo/pull/7", req.Task.SourceURL)
+	assert.Equal(t, "7", req.Labels["shepherd.io/issue"])
+	assert.Equal(t, "carol", req.Labels["shepherd.io/requested-by"])
+
+	taskContext := req.Task.Context
+	assert.Contains(t, taskContext, "**Add login** (feature/login into main)")
+	assert.Contains(t, taskContext, "On `login.go`, line 12:\n\n```diff\n@@ -10,3 +10,3 @@\n+func doLogin() {\n```")
+	assert.Contains(t, taskContext, "**alice** wrote:\n\nThis name is unclear")
+	assert.Contains(t, taskContext, "**carol** wrote:\n\n@shepherd rename it to loginUser")
+	assert.NotContains(t, taskContext, "Unrelated thread", "only the mention's thread is included")
+	assert.Contains(t, taskContext, "## Diff\n\n```diff\n"+testPRDiff)
+
+	require.Len(t, f.comments, 1)
+	assert.Contains(t, f.comments[0], "Task ID: task-pr")
+	meta, _ := storedTask(callbackHandler, "task-pr")
+	assert.Equal(t, TaskMetadata{
+		Owner: "org", Repo: "repo", IssueNumber: 7, PullRequest: true, CorrelationID: "run-1",
+		AckCommentID: 1, AckedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, meta)
+}
+
+func TestWebhookHandler_PullRequestConversationComment(t *testing.T) {
+	f := &fakePullRequest{headRepo: "org/repo", state: "open"}
+	handler, _ := newPullRequestTestHandler(t, f)
+
+	event := createTestIssueCommentEvent("org", "repo", 7, "@shepherd")
+	event.Issue.PullRequestLinks = &gh.PullRequestLinks{URL: gh.Ptr("https://api.github.com/repos/org/repo/pulls/7")}
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+	handler.handleIssueComment(context.Background(), body)
+
+	require.Len(t, f.created, 1)
+	req := f.created[0]
+	assert.Equal(t, "feature/login", req.Repo.Ref)
+	assert.Equal(t, "Work on this pull request", req.Task.Description)
+	assert.Equal(t, api.SourceTypePullRequest, req.Task.SourceType)
+	assert.Contains(t, req.Task.Context, "**alice** wrote:\n\nPlease also update the docs")
+	assert.NotContains(t, req.Task.Context, "## Review Thread")
+}
+
+func TestWebhookHandler_UnworkablePullRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		headRepo string
+		state    string
+		want     string
+	}{
+		{name: "fork", headRepo: "someone/repo", state: "open", want: "belongs to a fork"},
+		{name: "closed", headRepo: "org/repo", state: "closed", want: "only works on open pull requests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakePullRequest{headRepo: tt.headRepo, state: tt.state}
+			handler, _ := newPullRequestTestHandler(t, f)
+
+			event := createTestIssueCommentEvent("org", "repo", 7, "@shepherd fix the tests")
+			event.Issue.PullRequestLinks = &gh.PullRequestLinks{URL: gh.Ptr("https://api.github.com/repos/org/repo/pulls/7")}
+			body, err := json.Marshal(event)
+			require.NoError(t, err)
+			handler.handleIssueComment(context.Background(), body)
+
+			assert.Empty(t, f.created)
+			require.Len(t, f.comments, 1)
+			assert.Contains(t, f.comments[0], tt.want)
+		})
+	}
+}
+
+func TestWebhookHandler_PullRequestContextTruncatesDiff(t *testing.T) {
+	diff := strings.Repeat("+"+strings.Repeat("a", 99)+"\n", maxContextSize/50)
+	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == testPRPath {
+			_, _ = w.Write([]byte(diff))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer ghServer.Close()
+
+	handler := NewWebhookHandler("", newTestClientFromServer(t, ghServer), nil, nil, "", "default", ctrl.Log.WithName("test"))
+	taskContext := handler.pullRequestContext(context.Background(), pullRequestTrigger{
+		repo: testRepository(),
+		pr:   testPullRequest("org/repo", "open"),
+	})
+
+	assert.LessOrEqual(t, len(taskContext), maxContextSize+100)
+	assert.Contains(t, taskContext, "\n--- Diff truncated due to size limit ---\n")
+	assert.Contains(t, taskContext, strings.Repeat("a", 99)+"\n\n--- Diff truncated", "cut at a line boundary")
+}
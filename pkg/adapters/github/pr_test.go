@@ -0,0 +1,227 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+const (
+	testGHLabelsPath    = "/api/v3/repos/org/repo/issues/5/labels"
+	testGHReviewersPath = "/api/v3/repos/org/repo/pulls/5/requested_reviewers"
+	testGHPRFilesPath   = "/api/v3/repos/org/repo/pulls/5/files"
+	testPRURL           = "https://github.com/org/repo/pull/5"
+)
+
+func TestParsePRURL(t *testing.T) {
+	t.Run("valid PR URL", func(t *testing.T) {
+		pr, err := parsePRURL("", "https://github.com/myorg/myrepo/pull/42")
+		require.NoError(t, err)
+		assert.Equal(t, pullRequestRef{Owner: "myorg", Repo: "myrepo", Number: 42}, pr)
+	})
+
+	t.Run("issue URL", func(t *testing.T) {
+		_, err := parsePRURL("", "https://github.com/myorg/myrepo/issues/42")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unexpected PR URL format")
+	})
+
+	t.Run("invalid PR number", func(t *testing.T) {
+		_, err := parsePRURL("", "https://github.com/myorg/myrepo/pull/abc")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid PR number")
+	})
+
+	t.Run("enterprise PR URL below a base path", func(t *testing.T) {
+		pr, err := parsePRURL("/github", "https://proxy.example.com/github/myorg/myrepo/pull/42")
+		require.NoError(t, err)
+		assert.Equal(t, pullRequestRef{Owner: "myorg", Repo: "myrepo", Number: 42}, pr)
+	})
+}
+
+// prRecorder captures the PR-related requests received by the fake GitHub API.
+type prRecorder struct {
+	mu        sync.Mutex
+	labels    []string
+	reviewers map[string][]string
+	comment   string
+	graphQL   graphQLRequest
+}
+
+func newPRServer(t *testing.T, rec *prRecorder, codeowners string, files []string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+testGHLabelsPath, func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		_ = json.NewDecoder(r.Body).Decode(&rec.labels)
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("POST "+testGHReviewersPath, func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		_ = json.NewDecoder(r.Body).Decode(&rec.reviewers)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number":5}`))
+	})
+	mux.HandleFunc("GET /api/v3/repos/org/repo/contents/.github/CODEOWNERS", func(w http.ResponseWriter, _ *http.Request) {
+		if codeowners == "" {
+			http.NotFound(w, nil)
+			return
+		}
+		encoded := base64.StdEncoding.EncodeToString([]byte(codeowners))
+		_, _ = fmt.Fprintf(w, `{"type":"file","encoding":"base64","content":%q}`, encoded)
+	})
+	mux.HandleFunc("GET "+testGHPRFilesPath, func(w http.ResponseWriter, _ *http.Request) {
+		var out []map[string]string
+		for _, f := range files {
+			out = append(out, map[string]string{"filename": f})
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	})
+	mux.HandleFunc("GET /api/v3/repos/org/repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"number":5,"node_id":"PR_node5"}`))
+	})
+	mux.HandleFunc("POST /api/graphql", func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		_ = json.NewDecoder(r.Body).Decode(&rec.graphQL)
+		_, _ = w.Write([]byte(`{"data":{"enablePullRequestAutoMerge":{"clientMutationId":null}}}`))
+	})
+	mux.HandleFunc("POST "+testGHCommentsPath, func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		rec.comment = body["body"]
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+	// Any other path (e.g. fallback CODEOWNERS locations) is not found
+	mux.HandleFunc("/", http.NotFound)
+	return httptest.NewServer(mux)
+}
+
+func TestCallbackHandler_DecoratePR(t *testing.T) {
+	t.Run("applies labels and static reviewers", func(t *testing.T) {
+		rec := &prRecorder{}
+		srv := newPRServer(t, rec, "", nil)
+		defer srv.Close()
+
+		handler := NewCallbackHandler("", newTestClientFromServer(t, srv), nil, ctrl.Log.WithName("test"),
+			WithPRConfig(PRConfig{
+				Labels:        []string{"shepherd", "automated"},
+				Reviewers:     []string{"alice"},
+				TeamReviewers: []string{"backend"},
+			}))
+
+		handler.decoratePR(context.Background(), testPRURL)
+
+		assert.Equal(t, []string{"shepherd", "automated"}, rec.labels)
+		assert.Equal(t, []string{"alice"}, rec.reviewers["reviewers"])
+		assert.Equal(t, []string{"backend"}, rec.reviewers["team_reviewers"])
+	})
+
+	t.Run("resolves reviewers from CODEOWNERS", func(t *testing.T) {
+		rec := &prRecorder{}
+		codeowners := "*.go @Alice @org/backend @other-org/team\n/docs/ @bob docs@example.com\n"
+		srv := newPRServer(t, rec, codeowners, []string{"pkg/api/server.go", "docs/index.md", "README.md"})
+		defer srv.Close()
+
+		handler := NewCallbackHandler("", newTestClientFromServer(t, srv), nil, ctrl.Log.WithName("test"),
+			WithPRConfig(PRConfig{Reviewers: []string{"alice"}, UseCodeowners: true}))
+
+		handler.decoratePR(context.Background(), testPRURL)
+
+		assert.Nil(t, rec.labels, "no labels configured")
+		assert.Equal(t, []string{"alice", "bob"}, rec.reviewers["reviewers"])
+		assert.Equal(t, []string{"backend"}, rec.reviewers["team_reviewers"])
+	})
+
+	t.Run("missing CODEOWNERS requests no reviewers", func(t *testing.T) {
+		rec := &prRecorder{}
+		srv := newPRServer(t, rec, "", []string{"main.go"})
+		defer srv.Close()
+
+		handler := NewCallbackHandler("", newTestClientFromServer(t, srv), nil, ctrl.Log.WithName("test"),
+			WithPRConfig(PRConfig{UseCodeowners: true}))
+
+		handler.decoratePR(context.Background(), testPRURL)
+
+		assert.Nil(t, rec.reviewers)
+	})
+
+	t.Run("enables auto-merge with configured method", func(t *testing.T) {
+		rec := &prRecorder{}
+		srv := newPRServer(t, rec, "", nil)
+		defer srv.Close()
+
+		handler := NewCallbackHandler("", newTestClientFromServer(t, srv), nil, ctrl.Log.WithName("test"),
+			WithPRConfig(PRConfig{AutoMerge: true, MergeMethod: "rebase"}))
+
+		handler.decoratePR(context.Background(), testPRURL)
+
+		assert.Contains(t, rec.graphQL.Query, "enablePullRequestAutoMerge")
+		assert.Equal(t, "PR_node5", rec.graphQL.Variables["id"])
+		assert.Equal(t, "REBASE", rec.graphQL.Variables["method"])
+	})
+
+	t.Run("auto-merge defaults to squash", func(t *testing.T) {
+		rec := &prRecorder{}
+		srv := newPRServer(t, rec, "", nil)
+		defer srv.Close()
+
+		handler := NewCallbackHandler("", newTestClientFromServer(t, srv), nil, ctrl.Log.WithName("test"),
+			WithPRConfig(PRConfig{AutoMerge: true}))
+
+		handler.decoratePR(context.Background(), testPRURL)
+
+		assert.Equal(t, "SQUASH", rec.graphQL.Variables["method"])
+	})
+
+	t.Run("completed callback decorates PR before commenting", func(t *testing.T) {
+		rec := &prRecorder{}
+		srv := newPRServer(t, rec, "", nil)
+		defer srv.Close()
+
+		handler := NewCallbackHandler("", newTestClientFromServer(t, srv), nil, ctrl.Log.WithName("test"),
+			WithPRConfig(PRConfig{Labels: []string{"shepherd"}}))
+		handler.RegisterTask(context.Background(), "task-1", TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 42})
+
+		handler.handleCallback(context.Background(), &api.CallbackPayload{
+			TaskID:  "task-1",
+			Event:   api.EventCompleted,
+			Details: map[string]any{"pr_url": testPRURL},
+		})
+
+		assert.Equal(t, []string{"shepherd"}, rec.labels)
+		assert.Contains(t, rec.comment, testPRURL)
+	})
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// liveProgressInterval is how often live progress edits the acknowledgment
+// comment at most; progress reported in between is skipped.
+const liveProgressInterval = 10 * time.Second
+
+// WithLiveProgress edits the acknowledgment comment of a task on its
+// started and progress callbacks to show the task's current step, elapsed
+// time and last action, and replaces it with the task's result.
+func WithLiveProgress(enabled bool) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.liveProgress = enabled
+	}
+}
+
+// liveComment reports whether the result of a task is to replace its
+// acknowledgment comment, meta.AckCommentID. Verification tasks announce
+// themselves with their own comment, which is left as it is.
+func (h *CallbackHandler) liveComment(meta TaskMetadata) bool {
+	return h.liveProgress && meta.AckCommentID != 0 && !meta.Verification
+}
+
+// updateProgress edits the acknowledgment comment of the task of payload,
+// a started or progress callback, with the progress it reports.
+func (h *CallbackHandler) updateProgress(ctx context.Context, payload *api.CallbackPayload, now time.Time) {
+	if !h.liveProgress {
+		return
+	}
+	step := payload.Message
+	if payload.Event == api.EventStarted {
+		step = progressStarted
+	}
+	action, _ := payload.Details[api.ProgressActionDetail].(string)
+	if step == "" && action == "" {
+		return
+	}
+
+	// Claiming the edit in the store keeps replicas that receive the
+	// task's callbacks from editing more often between them.
+	var meta TaskMetadata
+	claimed, err := h.tasks.Update(ctx, payload.TaskID, func(m *TaskMetadata) bool {
+		if !h.liveComment(*m) || now.Sub(m.ProgressAt) < liveProgressInterval {
+			return false
+		}
+		m.ProgressAt = now
+		meta = *m
+		return true
+	})
+	if err != nil {
+		h.log.Error(err, "failed to read task metadata for progress", logging.TaskID, payload.TaskID)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	body := withTaskMarker(formatLiveProgress(payload.TaskID, meta.Lineage, step, action, now.Sub(meta.AckedAt)),
+		payload.TaskID, meta.CorrelationID)
+	if err := h.ghClient.EditComment(ctx, meta.Owner, meta.Repo, meta.AckCommentID, body); err != nil {
+		h.log.Error(err, "failed to edit acknowledgment comment with progress", logging.TaskID, payload.TaskID)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// acknowledgeWithLiveProgress creates a task from a mention on issue 7 with
+// live progress enabled.
+func acknowledgeWithLiveProgress(t *testing.T, f *fakePullRequest) *CallbackHandler {
+	t.Helper()
+	handler, callbackHandler := newPullRequestTestHandler(t, f)
+	WithLiveProgress(true)(callbackHandler)
+
+	body, err := json.Marshal(createTestIssueCommentEvent("org", "repo", 7, "@shepherd fix the login"))
+	require.NoError(t, err)
+	handler.handleIssueComment(context.Background(), body)
+	require.Len(t, f.comments, 1, "acknowledgment comment")
+	return callbackHandler
+}
+
+func TestCallbackHandler_LiveProgress(t *testing.T) {
+	f := &fakePullRequest{}
+	callbackHandler := acknowledgeWithLiveProgress(t, f)
+	ctx := context.Background()
+
+	callbackHandler.handleCallback(ctx, &api.CallbackPayload{TaskID: "task-pr", Event: api.EventStarted})
+	require.Len(t, f.edits, 1)
+	assert.True(t, isAcknowledgment(f.edits[0], "task-pr"), "still recognized as the acknowledgment")
+	assert.Contains(t, f.edits[0], "**Step:** Runner started")
+
+	payload := &api.CallbackPayload{
+		TaskID: "task-pr", Event: api.EventProgress, Message: "Running tests",
+		Details: map[string]any{api.ProgressActionDetail: "go test ./..."},
+	}
+	callbackHandler.updateProgress(ctx, payload, time.Now())
+	assert.Len(t, f.edits, 1, "progress right after the last edit is skipped")
+
+	callbackHandler.updateProgress(ctx, payload, time.Now().Add(liveProgressInterval))
+	require.Len(t, f.edits, 2)
+	assert.Contains(t, f.edits[1], "**Step:** Running tests\n")
+	assert.Contains(t, f.edits[1], "**Last action:** go test ./...\n")
+	assert.Contains(t, f.edits[1], "<!-- shepherd-task:task-pr correlation:run-1 -->")
+
+	callbackHandler.handleCallback(ctx, &api.CallbackPayload{
+		TaskID: "task-pr", Event: api.EventCompleted,
+		Details: map[string]any{"pr_url": "https://github.com/org/repo/pull/8"},
+	})
+	assert.Len(t, f.comments, 1, "the result replaces the acknowledgment")
+	require.Len(t, f.edits, 3)
+	assert.Contains(t, f.edits[2], "https://github.com/org/repo/pull/8")
+	assert.NotContains(t, f.edits[2], "started here")
+	assert.False(t, resultMissing([]string{f.edits[2]}, "task-pr"))
+}
+
+func TestCallbackHandler_LiveProgressResultPostedIfEditFails(t *testing.T) {
+	f := &fakePullRequest{failEdits: true}
+	callbackHandler := acknowledgeWithLiveProgress(t, f)
+
+	callbackHandler.handleCallback(context.Background(), &api.CallbackPayload{
+		TaskID: "task-pr", Event: api.EventFailed, Message: "tests did not pass",
+	})
+	require.Len(t, f.comments, 2)
+	assert.Contains(t, f.comments[1], "tests did not pass")
+}
+
+func TestCallbackHandler_ProgressWithoutLiveProgress(t *testing.T) {
+	f := &fakePullRequest{}
+	handler, callbackHandler := newPullRequestTestHandler(t, f)
+	body, err := json.Marshal(createTestIssueCommentEvent("org", "repo", 7, "@shepherd fix the login"))
+	require.NoError(t, err)
+	handler.handleIssueComment(context.Background(), body)
+
+	callbackHandler.handleCallback(context.Background(), &api.CallbackPayload{
+		TaskID: "task-pr", Event: api.EventProgress, Message: "Running tests",
+	})
+	assert.Empty(t, f.edits)
+	assert.Len(t, f.comments, 1)
+}
+
+func TestFormatLiveProgress(t *testing.T) {
+	got := formatLiveProgress("task-1", "retries task-0, which failed", "Editing\nlogin.go", "", 90*time.Second+300*time.Millisecond)
+	assert.Equal(t, `Shepherd is working on your request.
+
+Task ID: task-1
+
+This task retries task-0, which failed.
+
+**Step:** Editing
+**Elapsed:** 1m30s
+
+I'll update this comment when I'm done.`, got)
+}
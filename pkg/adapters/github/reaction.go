@@ -0,0 +1,67 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// Reactions to the mention that triggered a task. GitHub only offers eight
+// reactions, so the outcome is shown with the closest of them.
+const (
+	reactionStarted   = "eyes"
+	reactionSucceeded = "rocket"
+	reactionFailed    = "confused"
+)
+
+// WithReactionAcknowledgment acknowledges a mention by reacting to it
+// instead of posting a comment, and reacts again when the task finishes.
+func WithReactionAcknowledgment() WebhookOption {
+	return func(h *WebhookHandler) {
+		h.ackReactions = true
+	}
+}
+
+// reactTo makes the comment commentID, at url, the one meta's task reacts
+// to, if mentions are acknowledged with reactions. review is true for a
+// review comment on a PR's diff.
+func (h *WebhookHandler) reactTo(meta *TaskMetadata, commentID int64, url string, review bool) {
+	if !h.ackReactions {
+		return
+	}
+	meta.ReactTo = commentID
+	meta.ReactToReview = review
+	// Later comments of the task link to the mention instead.
+	meta.AckURL = url
+}
+
+// reactToResult reacts to the mention of a finished task with its outcome.
+func (h *CallbackHandler) reactToResult(ctx context.Context, taskID string, meta TaskMetadata, event string) {
+	if meta.ReactTo == 0 {
+		return
+	}
+	reaction := reactionFailed
+	if event == api.EventCompleted {
+		reaction = reactionSucceeded
+	}
+	if err := h.ghClient.AddCommentReaction(ctx, meta.Owner, meta.Repo, meta.ReactTo, meta.ReactToReview, reaction); err != nil {
+		h.log.Error(err, "failed to react to mention", logging.TaskID, taskID)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	gh "github.com/google/go-github/v75/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func TestWebhookHandler_ReactionAcknowledgment(t *testing.T) {
+	tests := []struct {
+		name          string
+		finishedEvent string
+		wantStarted   string
+		wantFinished  string
+	}{
+		{name: "completed", finishedEvent: api.EventCompleted,
+			wantStarted: "issues/comments/99/reactions eyes", wantFinished: "issues/comments/99/reactions rocket"},
+		{name: "failed", finishedEvent: api.EventFailed,
+			wantStarted: "issues/comments/99/reactions eyes", wantFinished: "issues/comments/99/reactions confused"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakePullRequest{}
+			handler, callbackHandler := newPullRequestTestHandler(t, f)
+			WithReactionAcknowledgment()(handler)
+
+			event := createTestIssueCommentEvent("org", "repo", 7, "@shepherd fix the login")
+			event.Comment.ID = gh.Ptr(int64(99))
+			event.Comment.HTMLURL = gh.Ptr("https://github.com/org/repo/issues/7#issuecomment-99")
+			body, err := json.Marshal(event)
+			require.NoError(t, err)
+			handler.handleIssueComment(context.Background(), body)
+
+			require.Len(t, f.created, 1)
+			assert.Empty(t, f.comments, "no acknowledgment comment")
+			assert.Equal(t, []string{tt.wantStarted}, f.reactions)
+
+			callbackHandler.handleCallback(context.Background(), &api.CallbackPayload{
+				TaskID: "task-pr", Event: tt.finishedEvent, Message: "tests did not pass",
+			})
+			require.Len(t, f.comments, 1, "the result is still posted")
+			assert.Contains(t, f.comments[0], "[started here](https://github.com/org/repo/issues/7#issuecomment-99)",
+				"the result links to the mention")
+			assert.Equal(t, []string{tt.wantStarted, tt.wantFinished}, f.reactions)
+		})
+	}
+}
+
+func TestWebhookHandler_ReactionAcknowledgmentOfReviewComment(t *testing.T) {
+	f := &fakePullRequest{headRepo: "org/repo", state: "open"}
+	handler, _ := newPullRequestTestHandler(t, f)
+	WithReactionAcknowledgment()(handler)
+
+	body, err := json.Marshal(gh.PullRequestReviewCommentEvent{
+		Action:      gh.Ptr("created"),
+		Repo:        testRepository(),
+		PullRequest: testPullRequest("org/repo", "open"),
+		Comment: &gh.PullRequestComment{
+			ID:   gh.Ptr(int64(3)),
+			Body: gh.Ptr("@shepherd rename it"),
+			User: &gh.User{Login: gh.Ptr("carol")},
+		},
+	})
+	require.NoError(t, err)
+	handler.handleReviewComment(context.Background(), body)
+
+	require.Len(t, f.created, 1)
+	assert.Empty(t, f.comments)
+	assert.Equal(t, []string{"pulls/comments/3/reactions eyes"}, f.reactions)
+}
+
+func TestWebhookHandler_ReactionAcknowledgmentFallsBackToComment(t *testing.T) {
+	f := &fakePullRequest{failReactions: true}
+	handler, _ := newPullRequestTestHandler(t, f)
+	WithReactionAcknowledgment()(handler)
+
+	event := createTestIssueCommentEvent("org", "repo", 7, "@shepherd fix the login")
+	event.Comment.ID = gh.Ptr(int64(99))
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+	handler.handleIssueComment(context.Background(), body)
+
+	require.Len(t, f.comments, 1)
+	assert.Contains(t, f.comments[0], "Task ID: task-pr")
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// Reconciler posts the result comments of tasks that finished while the
+// adapter was down. The API server retries a terminal callback for about an
+// hour; after a longer outage, or a callback that arrived but could not be
+// acted on, the issue is left with only the acknowledgment.
+type Reconciler struct {
+	ghClient        *Client
+	apiClient       *APIClient
+	callbackHandler *CallbackHandler
+	callbackURL     string
+	window          time.Duration
+	log             logr.Logger
+}
+
+// NewReconciler creates a reconciler for the tasks that call back to
+// callbackURL and finished within window.
+func NewReconciler(
+	ghClient *Client, apiClient *APIClient, callbackHandler *CallbackHandler,
+	callbackURL string, window time.Duration, log logr.Logger,
+) *Reconciler {
+	return &Reconciler{
+		ghClient:        ghClient,
+		apiClient:       apiClient,
+		callbackHandler: callbackHandler,
+		callbackURL:     callbackURL,
+		window:          window,
+		log:             log.WithName("reconcile"),
+	}
+}
+
+// Run reconciles once, as the adapter starts.
+func (r *Reconciler) Run(ctx context.Context) {
+	reported, err := r.Reconcile(ctx, time.Now())
+	if err != nil {
+		r.log.Error(err, "failed to reconcile result comments")
+		return
+	}
+	r.log.Info("reconciled result comments", "reported", reported, "window", r.window)
+}
+
+// Reconcile posts the missing result comments of tasks that finished after
+// now minus the window, and returns how many tasks it reported. A result
+// is missing if the issue has the task's acknowledgment comment but no
+// other comment of the task. Verification tasks are not reconciled.
+func (r *Reconciler) Reconcile(ctx context.Context, now time.Time) (int, error) {
+	tasks, err := r.apiClient.ListTasks(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("listing tasks: %w", err)
+	}
+
+	// Group by issue, so each issue's comments are listed once
+	type issueKey struct {
+		owner, repo string
+		number      int
+	}
+	var issues []issueKey
+	byIssue := make(map[issueKey][]api.TaskResponse)
+	for _, t := range tasks {
+		reported := t.Task.SourceType == api.SourceTypeIssue || t.Task.SourceType == api.SourceTypePullRequest
+		if t.CallbackURL != r.callbackURL || !reported || t.CompletionTime == nil {
+			continue
+		}
+		completed, err := time.Parse(time.RFC3339, *t.CompletionTime)
+		if err != nil || now.Sub(completed) > r.window {
+			continue
+		}
+		meta, err := parseSourceURL(r.ghClient.pathPrefix(), t.Task.SourceURL)
+		if err != nil {
+			continue
+		}
+		key := issueKey{meta.Owner, meta.Repo, meta.IssueNumber}
+		if _, ok := byIssue[key]; !ok {
+			issues = append(issues, key)
+		}
+		byIssue[key] = append(byIssue[key], t)
+	}
+
+	reported := 0
+	for _, key := range issues {
+		comments, err := r.ghClient.ListIssueComments(ctx, key.owner, key.repo, key.number)
+		if err != nil {
+			// Keep going so one inaccessible repository does not block the rest
+			r.log.Error(err, "failed to list issue comments", "owner", key.owner, "repo", key.repo, "issue", key.number)
+			continue
+		}
+		bodies := make([]string, len(comments))
+		for i, c := range comments {
+			bodies[i] = c.GetBody()
+		}
+
+		for _, t := range byIssue[key] {
+			if !resultMissing(bodies, t.ID) {
+				continue
+			}
+			r.log.Info("posting missed result comment", logging.TaskID, t.ID, "phase", t.Status.Phase)
+			meta := TaskMetadata{
+				Owner:         key.owner,
+				Repo:          key.repo,
+				IssueNumber:   key.number,
+				PullRequest:   t.Task.SourceType == api.SourceTypePullRequest,
+				CorrelationID: t.CorrelationID,
+			}
+			for _, c := range comments {
+				if isAcknowledgment(c.GetBody(), t.ID) {
+					meta.AckURL = c.GetHTMLURL()
+					meta.AckCommentID = c.GetID()
+					meta.AckedAt = c.GetCreatedAt().Time
+				}
+			}
+			r.callbackHandler.RegisterTask(ctx, t.ID, meta)
+			payload := resultPayload(t)
+			r.callbackHandler.handleCallback(ctx, &payload)
+			reported++
+		}
+	}
+	return reported, nil
+}
+
+// resultMissing reports whether comments include the acknowledgment of
+// taskID but no comment with its result.
+func resultMissing(comments []string, taskID string) bool {
+	marker := "<!-- shepherd-task:" + taskID + " "
+	// The timeout warning from commentTimeoutWarning, after its thread header.
+	timeoutWarning := fmt.Sprintf("The Shepherd task %s is about to time out", taskID)
+	acknowledged := false
+	for _, body := range comments {
+		if !strings.Contains(body, marker) {
+			continue
+		}
+		switch {
+		case isAcknowledgment(body, taskID):
+			acknowledged = true
+		case strings.Contains(body, timeoutWarning):
+		default:
+			return false
+		}
+	}
+	return acknowledged
+}
+
+// resultPayload rebuilds the terminal callback of a finished task from its
+// status, the way the API server sends it.
+func resultPayload(t api.TaskResponse) api.CallbackPayload {
+	event := api.EventFailed
+	switch t.Status.Phase {
+	case "Succeeded":
+		event = api.EventCompleted
+	case "Cancelled":
+		event = api.EventCancelled
+	}
+	payload := api.CallbackPayload{
+		TaskID:        t.ID,
+		Event:         event,
+		Message:       t.Status.Message,
+		Details:       map[string]any{},
+		CorrelationID: t.CorrelationID,
+	}
+	if t.Status.PRURL != "" {
+		payload.Details["pr_url"] = t.Status.PRURL
+	}
+	if t.Status.Error != "" {
+		payload.Details["error"] = t.Status.Error
+	}
+	if t.Status.Summary != "" {
+		payload.Details["summary"] = t.Status.Summary
+	}
+	return payload
+}
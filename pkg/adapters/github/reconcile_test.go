@@ -0,0 +1,160 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+var testReconcileNow = time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+
+func reconcileTask(id string, issue, completed, phase string) api.TaskResponse {
+	t := api.TaskResponse{
+		ID:            id,
+		CallbackURL:   "http://adapter/callback",
+		CorrelationID: "run-" + id,
+		Task: api.TaskRequest{
+			SourceURL:  "https://github.com/org/repo/issues/" + issue,
+			SourceType: api.SourceTypeIssue,
+		},
+		Status: api.TaskStatusSummary{Phase: phase},
+	}
+	if completed != "" {
+		t.CompletionTime = &completed
+	}
+	return t
+}
+
+func TestResultMissing(t *testing.T) {
+	ack := withTaskMarker(formatAcknowledge("task-1", ""), "task-1", "run-1")
+	tests := []struct {
+		name     string
+		comments []string
+		want     bool
+	}{
+		{name: "only the acknowledgment", comments: []string{"@shepherd fix it", ack}, want: true},
+		{name: "acknowledgment and timeout warning",
+			comments: []string{ack, withTaskMarker(formatTimeoutWarning("task-1", ""), "task-1", "run-1")}, want: true},
+		{name: "result posted", comments: []string{ack, withTaskMarker(formatFailed("boom", DefaultMentionHandle), "task-1", "run-1")}},
+		{name: "no acknowledgment", comments: []string{"@shepherd fix it"}},
+		{name: "another task's acknowledgment",
+			comments: []string{withTaskMarker(formatAcknowledge("task-10", ""), "task-10", "run-10")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resultMissing(tt.comments, "task-1"))
+		})
+	}
+}
+
+func TestReconciler_PostsMissedResults(t *testing.T) {
+	tasks := []api.TaskResponse{
+		reconcileTask("task-done", "1", "2026-10-12T08:00:00Z", "Succeeded"),
+		reconcileTask("task-reported", "1", "2026-10-12T08:30:00Z", "Failed"),
+		reconcileTask("task-old", "2", "2026-10-10T08:00:00Z", "Failed"),
+		reconcileTask("task-running", "2", "", "Running"),
+	}
+	tasks[0].Status.PRURL = "https://github.com/org/repo/pull/9"
+	other := reconcileTask("task-other", "2", "2026-10-12T08:00:00Z", "Failed")
+	other.CallbackURL = "http://other-adapter/callback"
+	tasks = append(tasks, other)
+
+	comments := map[string][]string{
+		"/api/v3/repos/org/repo/issues/1/comments": {
+			withTaskMarker(formatAcknowledge("task-done", ""), "task-done", "run-task-done"),
+			withTaskMarker(formatAcknowledge("task-reported", ""), "task-reported", "run-task-reported"),
+			withTaskMarker(formatFailed("boom", DefaultMentionHandle), "task-reported", "run-task-reported"),
+		},
+		"/api/v3/repos/org/repo/issues/2/comments": {
+			withTaskMarker(formatAcknowledge("task-old", ""), "task-old", "run-task-old"),
+			withTaskMarker(formatAcknowledge("task-other", ""), "task-other", "run-task-other"),
+		},
+	}
+
+	var mu sync.Mutex
+	var posted []string
+	var listed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/api/v1/tasks":
+			_ = json.NewEncoder(w).Encode(tasks)
+		case r.Method == http.MethodGet:
+			listed = append(listed, r.URL.Path)
+			var out []map[string]string
+			for i, body := range comments[r.URL.Path] {
+				out = append(out, map[string]string{"body": body, "html_url": fmt.Sprintf("https://github.com/c/%d", i)})
+			}
+			_ = json.NewEncoder(w).Encode(out)
+		case r.Method == http.MethodPost:
+			var req map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			posted = append(posted, r.URL.Path+": "+req["body"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	ghClient := newTestClientFromServer(t, srv)
+	apiClient := NewAPIClient(srv.URL)
+	cb := NewCallbackHandler("", ghClient, apiClient, ctrl.Log.WithName("test"))
+	r := NewReconciler(ghClient, apiClient, cb, "http://adapter/callback", 24*time.Hour, ctrl.Log.WithName("test"))
+
+	reported, err := r.Reconcile(context.Background(), testReconcileNow)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reported)
+	assert.Equal(t, []string{"/api/v3/repos/org/repo/issues/1/comments"}, listed,
+		"issues without recently finished tasks of this adapter are not read")
+	require.Len(t, posted, 1)
+	assert.Contains(t, posted[0], "/api/v3/repos/org/repo/issues/1/comments: > **task-done** · "+
+		"[started here](https://github.com/c/0)\n\nShepherd has completed the task.")
+	assert.Contains(t, posted[0], "Pull Request: https://github.com/org/repo/pull/9")
+	assert.Contains(t, posted[0], "<!-- shepherd-task:task-done correlation:run-task-done -->")
+
+	// A late callback retry for the same task is not posted again
+	cb.handleCallback(context.Background(), &api.CallbackPayload{TaskID: "task-done", Event: api.EventCompleted})
+	assert.Len(t, posted, 1)
+}
+
+func TestCallbackHandler_ClaimResult(t *testing.T) {
+	h := NewCallbackHandler("", nil, nil, ctrl.Log.WithName("test"))
+	now := testReconcileNow
+
+	assert.True(t, h.claimResult("task-1", now))
+	assert.False(t, h.claimResult("task-1", now.Add(time.Minute)))
+
+	h.releaseResult("task-1")
+	assert.True(t, h.claimResult("task-1", now.Add(time.Minute)), "claim released after a failed post")
+
+	assert.True(t, h.claimResult("task-2", now.Add(resultMemory+2*time.Minute)))
+	assert.True(t, h.claimResult("task-1", now.Add(resultMemory+2*time.Minute)), "old claims are forgotten")
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v75/github"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/validate"
+)
+
+// DefaultRepoConfigPath is where a repository keeps its Shepherd
+// configuration, on its default branch.
+const DefaultRepoConfigPath = ".github/shepherd.yaml"
+
+// repoCommands are the commands RepoConfig.Commands may list: the task
+// modes and the control commands.
+var repoCommands = []string{api.ModeFix, api.ModePlan, api.ModeReview, controlCancel, controlRetry}
+
+// RepoConfig is a repository's configuration file, such as
+//
+//	sandboxTemplate: python-large
+//	timeout: 45m
+//	model: claude-opus-4-1
+//	commands: [plan, review, cancel]
+//	branchPrefix: bot/shepherd-
+//
+// Its values replace the adapter's defaults for the repository's tasks, and
+// the options of a mention replace them in turn.
+type RepoConfig struct {
+	// SandboxTemplate replaces the adapter's default SandboxTemplate.
+	SandboxTemplate string `json:"sandboxTemplate,omitempty"`
+	// Timeout is the runner timeout, as a Go duration.
+	Timeout string `json:"timeout,omitempty"`
+	// Model is the model the runner's agent works with.
+	Model string `json:"model,omitempty"`
+	// Commands lists the commands mentions may give; empty allows all. A
+	// mention without a mode, the trigger label and assignment are fix
+	// commands.
+	Commands []string `json:"commands,omitempty"`
+	// BranchPrefix names the branches of the repository's tasks, followed
+	// by the task ID, instead of "shepherd/".
+	BranchPrefix string `json:"branchPrefix,omitempty"`
+}
+
+// parseRepoConfig parses and checks the content of a configuration file.
+// The error is written for the repository's users.
+func parseRepoConfig(content string) (RepoConfig, error) {
+	var cfg RepoConfig
+	if err := yaml.UnmarshalStrict([]byte(content), &cfg); err != nil {
+		return RepoConfig{}, err
+	}
+	if cfg.SandboxTemplate != "" && len(validation.IsDNS1123Subdomain(cfg.SandboxTemplate)) > 0 {
+		return RepoConfig{}, fmt.Errorf("sandboxTemplate %q is not a valid template name", cfg.SandboxTemplate)
+	}
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return RepoConfig{}, fmt.Errorf("timeout %q is not a valid duration, use for example 30m or 2h", cfg.Timeout)
+		}
+		if err := validate.Timeout(d); err != nil {
+			return RepoConfig{}, fmt.Errorf("timeout %s: %w", cfg.Timeout, err)
+		}
+		cfg.Timeout = d.String()
+	}
+	if err := validate.Model(cfg.Model); err != nil {
+		return RepoConfig{}, fmt.Errorf("model: %w", err)
+	}
+	for i, command := range cfg.Commands {
+		cfg.Commands[i] = strings.ToLower(command)
+		if !slices.Contains(repoCommands, cfg.Commands[i]) {
+			return RepoConfig{}, fmt.Errorf("unknown command %q in commands, must be one of %s",
+				command, strings.Join(repoCommands, ", "))
+		}
+	}
+	if err := validate.BranchPrefix(cfg.BranchPrefix); err != nil {
+		return RepoConfig{}, fmt.Errorf("branchPrefix: %w", err)
+	}
+	return cfg, nil
+}
+
+// allows reports whether mentions may give command.
+func (c RepoConfig) allows(command string) bool {
+	return len(c.Commands) == 0 || slices.Contains(c.Commands, command)
+}
+
+// branchPrefix returns the prefix of the branches of the repository's
+// tasks.
+func (c RepoConfig) branchPrefix() string {
+	if c.BranchPrefix == "" {
+		return toolkitv1alpha1.DefaultBranchPrefix
+	}
+	return c.BranchPrefix
+}
+
+// apply sets the configured runner settings on a task request, before the
+// mention's options are applied.
+func (c RepoConfig) apply(req *api.CreateTaskRequest) {
+	if c.SandboxTemplate != "" {
+		req.Runner.SandboxTemplateName = c.SandboxTemplate
+	}
+	if c.Timeout != "" {
+		req.Runner.Timeout = c.Timeout
+	}
+	req.Runner.Model = c.Model
+	req.Runner.BranchPrefix = c.BranchPrefix
+}
+
+// loadRepoConfig returns the configuration file of owner/repo, or an empty
+// configuration if it has none or the adapter reads none.
+func (h *WebhookHandler) loadRepoConfig(ctx context.Context, owner, repo string) (RepoConfig, error) {
+	if h.repoConfigPath == "" || h.repos == nil {
+		return RepoConfig{}, nil
+	}
+	content, err := h.repos.ConfigFile(ctx, owner, repo, h.repoConfigPath)
+	if err != nil {
+		// An unavailable GitHub API should not stop tasks, like an
+		// unresolved branch.
+		h.log.Error(err, "failed to get repository configuration, using the defaults", "repo", owner+"/"+repo)
+		return RepoConfig{}, nil
+	}
+	cfg, err := parseRepoConfig(content)
+	if err != nil {
+		return RepoConfig{}, fmt.Errorf("%s: %w", h.repoConfigPath, err)
+	}
+	return cfg, nil
+}
+
+// repoConfig returns the configuration of repo for a command on issue or
+// PR number. If the configuration is invalid or does not allow command,
+// it tells the issue and returns false.
+func (h *WebhookHandler) repoConfig(ctx context.Context, repo *gh.Repository, number int, command string) (RepoConfig, bool) {
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	cfg, err := h.loadRepoConfig(ctx, owner, name)
+	var reply string
+	switch {
+	case err != nil:
+		h.log.Info("invalid repository configuration", "repo", repo.GetFullName(), "error", err.Error())
+		reply = formatInvalidRepoConfig(err)
+	case !cfg.allows(command):
+		h.log.Info("command not allowed by repository configuration", "repo", repo.GetFullName(), "command", command)
+		reply = formatCommandNotAllowed(command, h.repoConfigPath, cfg.Commands)
+	default:
+		return cfg, true
+	}
+	if commentErr := h.ghClient.PostComment(ctx, owner, name, number, reply); commentErr != nil {
+		h.log.Error(commentErr, "failed to post repository configuration comment")
+	}
+	return RepoConfig{}, false
+}
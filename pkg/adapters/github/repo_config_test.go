@@ -0,0 +1,162 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func TestParseRepoConfig(t *testing.T) {
+	cfg, err := parseRepoConfig(`
+sandboxTemplate: python-large
+timeout: 90m
+model: claude-opus-4-1
+commands: [Plan, review, cancel]
+branchPrefix: bot/shepherd-
+`)
+	require.NoError(t, err)
+	assert.Equal(t, RepoConfig{
+		SandboxTemplate: "python-large",
+		Timeout:         "1h30m0s",
+		Model:           "claude-opus-4-1",
+		Commands:        []string{"plan", "review", "cancel"},
+		BranchPrefix:    "bot/shepherd-",
+	}, cfg)
+	assert.True(t, cfg.allows(api.ModePlan))
+	assert.False(t, cfg.allows(api.ModeFix))
+	assert.Equal(t, "bot/shepherd-", cfg.branchPrefix())
+
+	cfg, err = parseRepoConfig("")
+	require.NoError(t, err)
+	assert.True(t, cfg.allows(controlRetry), "no commands allows all")
+	assert.Equal(t, "shepherd/", cfg.branchPrefix())
+
+	invalid := map[string]string{
+		"unknown field":    "template: big",
+		"not yaml":         "timeout: [",
+		"template":         "sandboxTemplate: Big_Template",
+		"timeout":          "timeout: soon",
+		"timeout too long": "timeout: 48h",
+		"model":            "model: -latest",
+		"command":          "commands: [deploy]",
+		"branch prefix":    "branchPrefix: bot..x/",
+	}
+	for name, content := range invalid {
+		_, err := parseRepoConfig(content)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRepoCache_ConfigFile(t *testing.T) {
+	var fetches atomic.Int32
+	f := &fakePullRequest{repoConfig: "model: claude-opus-4-1\n"}
+	client, srv := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		f.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	cache := NewRepoCache(client, time.Hour)
+	ctx := context.Background()
+
+	content, err := cache.ConfigFile(ctx, "org", "repo", DefaultRepoConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, "model: claude-opus-4-1\n", content)
+	_, err = cache.ConfigFile(ctx, "Org", "Repo", DefaultRepoConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fetches.Load(), "served from cache within the TTL")
+
+	content, err = cache.ConfigFile(ctx, "org", "repo", ".shepherd.yaml")
+	require.NoError(t, err)
+	assert.Empty(t, content, "a missing file is empty")
+
+	cache.Invalidate("org", "repo")
+	_, err = cache.ConfigFile(ctx, "org", "repo", DefaultRepoConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), fetches.Load(), "refreshed after invalidation")
+}
+
+func TestWebhookHandler_RepoConfig(t *testing.T) {
+	const config = `sandboxTemplate: python-large
+timeout: 45m
+model: claude-opus-4-1
+commands: [fix, plan]
+branchPrefix: bot/
+`
+	newHandler := func(t *testing.T, config string) (*WebhookHandler, *fakePullRequest) {
+		f := &fakePullRequest{repoConfig: config}
+		handler, _ := newPullRequestTestHandler(t, f)
+		handler.repos = NewRepoCache(handler.ghClient, time.Minute)
+		return handler, f
+	}
+
+	t.Run("replaces the adapter defaults", func(t *testing.T) {
+		handler, f := newHandler(t, config)
+
+		controlComment(t, handler, "--template gpu fix the tests")
+
+		require.Len(t, f.created, 1)
+		assert.Equal(t, &api.RunnerConfig{
+			SandboxTemplateName: "gpu",
+			Timeout:             "45m0s",
+			Model:               "claude-opus-4-1",
+			BranchPrefix:        "bot/",
+		}, f.created[0].Runner, "the mention's options win")
+	})
+
+	t.Run("rejects commands it does not list", func(t *testing.T) {
+		handler, f := newHandler(t, config)
+
+		controlComment(t, handler, "review")
+		controlComment(t, handler, "cancel")
+
+		assert.Empty(t, f.created)
+		assert.Empty(t, f.cancelled)
+		assert.Equal(t, []string{
+			formatCommandNotAllowed(api.ModeReview, DefaultRepoConfigPath, []string{"fix", "plan"}),
+			formatCommandNotAllowed(controlCancel, DefaultRepoConfigPath, []string{"fix", "plan"}),
+		}, f.comments)
+	})
+
+	t.Run("reports an invalid configuration", func(t *testing.T) {
+		handler, f := newHandler(t, "timeout: soon\n")
+
+		controlComment(t, handler, "fix the tests")
+
+		assert.Empty(t, f.created)
+		require.Len(t, f.comments, 1)
+		assert.Contains(t, f.comments[0], DefaultRepoConfigPath+`: timeout "soon" is not a valid duration`)
+	})
+
+	t.Run("without a configuration", func(t *testing.T) {
+		handler, f := newHandler(t, "")
+
+		controlComment(t, handler, "fix the tests")
+
+		require.Len(t, f.created, 1)
+		assert.Equal(t, &api.RunnerConfig{SandboxTemplateName: "default"}, f.created[0].Runner)
+	})
+}
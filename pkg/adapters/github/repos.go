@@ -0,0 +1,168 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errUnknownRef is returned by RepoCache.ResolveRef for a ref that does not
+// exist in the repository.
+var errUnknownRef = errors.New("unknown ref")
+
+// RepoMetadata is what the adapter knows about a repository beyond the
+// webhook payload.
+type RepoMetadata struct {
+	FullName      string
+	DefaultBranch string
+	CloneURL      string
+	// Visibility is "public", "private" or "internal".
+	Visibility string
+	// SizeKB is the size of the repository as reported by GitHub.
+	SizeKB int
+	// Archived repositories cannot be pushed to.
+	Archived bool
+}
+
+type repoCacheEntry struct {
+	meta      RepoMetadata
+	fetchedAt time.Time
+}
+
+type configCacheEntry struct {
+	content   string
+	fetchedAt time.Time
+}
+
+// RepoCache caches repository metadata fetched from the GitHub API, so a
+// busy repository costs one API call per TTL rather than one per event.
+// Entries are also dropped when GitHub reports a change to the repository.
+type RepoCache struct {
+	client *Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]repoCacheEntry
+	configs map[string]configCacheEntry
+}
+
+// NewRepoCache creates a cache whose entries are refreshed after ttl.
+func NewRepoCache(client *Client, ttl time.Duration) *RepoCache {
+	return &RepoCache{
+		client:  client,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]repoCacheEntry{},
+		configs: map[string]configCacheEntry{},
+	}
+}
+
+func repoKey(owner, repo string) string {
+	return strings.ToLower(owner + "/" + repo)
+}
+
+// Get returns the metadata of owner/repo, fetching it if it is not cached
+// or older than the TTL.
+func (c *RepoCache) Get(ctx context.Context, owner, repo string) (RepoMetadata, error) {
+	key := repoKey(owner, repo)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.fetchedAt) < c.ttl {
+		return entry.meta, nil
+	}
+
+	meta, err := c.client.GetRepository(ctx, owner, repo)
+	if err != nil {
+		if ok {
+			// Serve stale metadata rather than failing while GitHub is
+			// unavailable; it is refreshed on the next call.
+			return entry.meta, nil
+		}
+		return RepoMetadata{}, err
+	}
+	c.mu.Lock()
+	c.entries[key] = repoCacheEntry{meta: meta, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return meta, nil
+}
+
+// ConfigFile returns the content of the file at path on the default branch
+// of owner/repo, or "" if it does not exist, fetching it if it is not
+// cached or older than the TTL.
+func (c *RepoCache) ConfigFile(ctx context.Context, owner, repo, path string) (string, error) {
+	key := repoKey(owner, repo) + ":" + path
+	c.mu.Lock()
+	entry, ok := c.configs[key]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.fetchedAt) < c.ttl {
+		return entry.content, nil
+	}
+
+	content, _, err := c.client.GetFileContent(ctx, owner, repo, path)
+	if err != nil {
+		if ok {
+			return entry.content, nil
+		}
+		return "", err
+	}
+	c.mu.Lock()
+	c.configs[key] = configCacheEntry{content: content, fetchedAt: c.now()}
+	c.mu.Unlock()
+	return content, nil
+}
+
+// Invalidate drops the cached metadata and configuration files of
+// owner/repo.
+func (c *RepoCache) Invalidate(owner, repo string) {
+	key := repoKey(owner, repo)
+	c.mu.Lock()
+	delete(c.entries, key)
+	for k := range c.configs {
+		if strings.HasPrefix(k, key+":") {
+			delete(c.configs, k)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// ResolveRef returns the ref a task for owner/repo should check out: ref
+// itself if it exists in the repository, or the default branch if ref is
+// empty. A ref that does not exist returns errUnknownRef.
+func (c *RepoCache) ResolveRef(ctx context.Context, owner, repo, ref string) (string, error) {
+	if ref == "" {
+		meta, err := c.Get(ctx, owner, repo)
+		if err != nil {
+			return "", err
+		}
+		return meta.DefaultBranch, nil
+	}
+	ok, err := c.client.RefExists(ctx, owner, repo, ref)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%w %q in %s/%s", errUnknownRef, ref, owner, repo)
+	}
+	return ref, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v75/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const testGHRepoPath = "/api/v3/repos/org/repo"
+
+// repoServer serves org/repo with the given default branch and counts the
+// repository lookups. Commits exist for main and v1.0 only.
+func repoServer(defaultBranch string, fail *atomic.Bool, lookups *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case testGHRepoPath:
+			lookups.Add(1)
+			if fail.Load() {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"full_name":"org/repo","default_branch":"` + defaultBranch +
+				`","clone_url":"https://github.com/org/repo.git","visibility":"private","size":2048}`))
+		case testGHRepoPath + "/commits/main", testGHRepoPath + "/commits/v1.0":
+			_, _ = w.Write([]byte("0123456789abcdef0123456789abcdef01234567"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestRepoCache_Get(t *testing.T) {
+	var fail atomic.Bool
+	var lookups atomic.Int32
+	client, srv := newTestClient(t, repoServer("main", &fail, &lookups))
+	defer srv.Close()
+
+	now := time.Now()
+	cache := NewRepoCache(client, time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	meta, err := cache.Get(ctx, "org", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, RepoMetadata{
+		FullName:      "org/repo",
+		DefaultBranch: "main",
+		CloneURL:      "https://github.com/org/repo.git",
+		Visibility:    "private",
+		SizeKB:        2048,
+	}, meta)
+
+	_, err = cache.Get(ctx, "Org", "Repo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), lookups.Load(), "served from cache within the TTL")
+
+	now = now.Add(2 * time.Minute)
+	_, err = cache.Get(ctx, "org", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), lookups.Load(), "refreshed after the TTL")
+
+	cache.Invalidate("org", "repo")
+	_, err = cache.Get(ctx, "org", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), lookups.Load(), "refreshed after invalidation")
+}
+
+func TestRepoCache_GetServesStaleOnError(t *testing.T) {
+	var fail atomic.Bool
+	var lookups atomic.Int32
+	client, srv := newTestClient(t, repoServer("main", &fail, &lookups))
+	defer srv.Close()
+
+	now := time.Now()
+	cache := NewRepoCache(client, time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := cache.Get(ctx, "org", "repo")
+	require.NoError(t, err)
+
+	fail.Store(true)
+	now = now.Add(2 * time.Minute)
+	meta, err := cache.Get(ctx, "org", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, "main", meta.DefaultBranch)
+
+	cache.Invalidate("org", "repo")
+	_, err = cache.Get(ctx, "org", "repo")
+	assert.Error(t, err, "nothing cached to fall back to")
+}
+
+func TestRepoCache_ResolveRef(t *testing.T) {
+	var fail atomic.Bool
+	var lookups atomic.Int32
+	client, srv := newTestClient(t, repoServer("trunk", &fail, &lookups))
+	defer srv.Close()
+
+	cache := NewRepoCache(client, time.Minute)
+	ctx := context.Background()
+
+	ref, err := cache.ResolveRef(ctx, "org", "repo", "")
+	require.NoError(t, err)
+	assert.Equal(t, "trunk", ref, "defaults to the default branch")
+
+	ref, err = cache.ResolveRef(ctx, "org", "repo", "v1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0", ref)
+
+	_, err = cache.ResolveRef(ctx, "org", "repo", "feature/gone")
+	assert.ErrorIs(t, err, errUnknownRef)
+}
+
+func TestWebhookHandler_VerificationRef(t *testing.T) {
+	var fail atomic.Bool
+	var lookups atomic.Int32
+	client, srv := newTestClient(t, repoServer("main", &fail, &lookups))
+	defer srv.Close()
+
+	h := &WebhookHandler{repos: NewRepoCache(client, time.Minute), log: ctrl.Log.WithName("test")}
+	ctx := context.Background()
+	event := func(base string) *gh.PullRequestEvent {
+		return &gh.PullRequestEvent{
+			PullRequest: &gh.PullRequest{Base: &gh.PullRequestBranch{Ref: gh.Ptr(base)}},
+			Repo:        &gh.Repository{Name: gh.Ptr("repo"), Owner: &gh.User{Login: gh.Ptr("org")}},
+		}
+	}
+
+	assert.Equal(t, "v1.0", h.verificationRef(ctx, event("v1.0")))
+	assert.Equal(t, "main", h.verificationRef(ctx, event("release/deleted")), "falls back to the default branch")
+
+	fail.Store(true)
+	h.repos = NewRepoCache(client, time.Minute)
+	assert.Equal(t, "release/deleted", h.verificationRef(ctx, event("release/deleted")),
+		"keeps the base branch when GitHub is unavailable")
+}
+
+func TestWebhookHandler_HandleRepository(t *testing.T) {
+	var fail atomic.Bool
+	var lookups atomic.Int32
+	client, srv := newTestClient(t, repoServer("main", &fail, &lookups))
+	defer srv.Close()
+
+	h := &WebhookHandler{repos: NewRepoCache(client, time.Hour), log: ctrl.Log.WithName("test")}
+	ctx := context.Background()
+	_, err := h.repos.Get(ctx, "org", "repo")
+	require.NoError(t, err)
+
+	body, _ := json.Marshal(gh.RepositoryEvent{
+		Action: gh.Ptr("edited"),
+		Repo:   &gh.Repository{Name: gh.Ptr("repo"), FullName: gh.Ptr("org/repo"), Owner: &gh.User{Login: gh.Ptr("org")}},
+	})
+	h.handleRepository(body)
+
+	_, err = h.repos.Get(ctx, "org", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), lookups.Load(), "edited repository is fetched again")
+}
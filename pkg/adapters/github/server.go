@@ -18,12 +18,13 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strings"
-	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -31,19 +32,69 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httprate"
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/debug"
+	"github.com/NissesSenap/shepherd/pkg/listen"
+	"github.com/NissesSenap/shepherd/pkg/readiness"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
 )
 
 // Options configures the GitHub adapter.
 type Options struct {
 	ListenAddr             string // ":8082"
+	MetricsListenAddr      string // Prometheus metrics; empty disables them
+	DebugListenAddr        string // pprof and expvar; empty disables them
 	WebhookSecret          string // GitHub webhook secret
 	AppID                  int64  // GitHub App ID
-	InstallationID         int64  // GitHub Installation ID
+	InstallationID         int64  // GitHub Installation ID; 0 looks up each repository's installation
 	PrivateKeyPath         string // Path to private key PEM file
 	APIURL                 string // Shepherd API URL (e.g., "http://shepherd-api:8080")
 	CallbackSecret         string // Shared secret for callback HMAC verification
 	CallbackURL            string // URL for API to call back (e.g., "http://github-adapter:8082/callback")
 	DefaultSandboxTemplate string // Default sandbox template name
+	PR                     PRConfig
+	VerifyAfterMerge       bool          // Schedule a verification task when a shepherd PR is merged
+	RepoCacheTTL           time.Duration // How long repository metadata is cached
+	IssueContextCacheSize  int           // Issues whose task context is cached; 0 disables the cache
+	Digest                 DigestConfig
+	EventTimeout           time.Duration // How long a webhook event or callback may take to handle
+	MaxConcurrentEvents    int           // Webhook events, and separately callbacks, handled at once
+	TimeoutWarnings        bool          // Comment when a task is about to time out
+	ReconcileWindow        time.Duration // How far back startup reconciliation looks; 0 disables it
+	MentionHandle          string        // Mentioned to trigger a task, without the @; empty means "shepherd"
+	MinPermission          string        // Repository permission a commenter needs to trigger a task; empty accepts anyone
+	AllowedRepos           []string      // owner/repo glob patterns of repositories that may trigger tasks; empty allows all
+	AllowedReposFile       string        // File with more AllowedRepos patterns, one per line
+	AckReactions           bool          // Acknowledge mentions with reactions instead of comments
+	LiveProgress           bool          // Edit the acknowledgment comment with the task's progress
+	TriggerLabel           string        // Label that creates a task when applied to an issue; empty disables it
+	Assignee               string        // User whose assignment to an issue creates a task; empty disables it
+	RepoConfigPath         string        // Repository configuration file on the default branch; empty disables it
+	// TaskStore is where task metadata is kept: "memory" or "configmap",
+	// the ConfigMap TaskStoreConfigMap in TaskStoreNamespace. Empty means
+	// memory.
+	TaskStore          string
+	TaskStoreNamespace string
+	TaskStoreConfigMap string
+	// DeliveryCacheSize is how many webhook deliveries are remembered in
+	// memory to skip redeliveries; 0 handles every delivery.
+	DeliveryCacheSize int
+	// DeliveryStore also keeps delivery IDs in the ConfigMap
+	// DeliveryStoreConfigMap in TaskStoreNamespace when "configmap".
+	// Empty means memory only.
+	DeliveryStore          string
+	DeliveryStoreConfigMap string
+	// CallbackSecondarySecret is also accepted while the callback secret
+	// is rotated.
+	CallbackSecondarySecret string
+	// CallbackPublicKeyPath is a PEM file with the Ed25519 public keys
+	// callbacks may be signed with instead of the callback secret.
+	CallbackPublicKeyPath string
+	// GithubURL and GithubUploadURL point the adapter at a GitHub
+	// Enterprise Server instance; empty means github.com.
+	GithubURL       string
+	GithubUploadURL string
 }
 
 // requireJSON validates Content-Type on POST/PUT/PATCH requests.
@@ -70,7 +121,7 @@ func Run(opts Options) error {
 	log := ctrl.Log.WithName("github-adapter")
 
 	// Create GitHub client
-	ghClient, err := NewClient(opts.AppID, opts.InstallationID, opts.PrivateKeyPath)
+	ghClient, err := NewClient(opts.AppID, opts.InstallationID, opts.PrivateKeyPath, opts.GithubURL, opts.GithubUploadURL)
 	if err != nil {
 		return fmt.Errorf("creating github client: %w", err)
 	}
@@ -78,12 +129,38 @@ func Run(opts Options) error {
 	// Create API client
 	apiClient := NewAPIClient(opts.APIURL)
 
-	// Create callback handler (Phase 5 adds callback endpoint)
-	callbackHandler := NewCallbackHandler(opts.CallbackSecret, ghClient, apiClient, log)
+	callbackOpts := []CallbackOption{
+		WithPRConfig(opts.PR),
+		WithSecondaryCallbackSecret(opts.CallbackSecondarySecret),
+		WithCallbackGuard(NewEventGuard(opts.MaxConcurrentEvents, opts.EventTimeout, log.WithName("callbacks"))),
+		WithTimeoutWarningComments(opts.TimeoutWarnings),
+		WithLiveProgress(opts.LiveProgress),
+	}
+	if opts.MentionHandle != "" {
+		callbackOpts = append(callbackOpts, WithCallbackMentionHandle(opts.MentionHandle))
+	}
+	if opts.CallbackPublicKeyPath != "" {
+		keys, err := api.LoadCallbackPublicKeys(opts.CallbackPublicKeyPath)
+		if err != nil {
+			return err
+		}
+		callbackOpts = append(callbackOpts, WithCallbackPublicKeys(keys...))
+	}
 
-	// Health tracking
-	var healthy atomic.Bool
-	healthy.Store(true)
+	if opts.TaskStore == TaskStoreConfigMap {
+		store, err := newConfigMapTaskStoreFromConfig(opts.TaskStoreNamespace, opts.TaskStoreConfigMap)
+		if err != nil {
+			return err
+		}
+		callbackOpts = append(callbackOpts, WithTaskMetadataStore(store))
+		log.Info("keeping task metadata in a ConfigMap",
+			"namespace", opts.TaskStoreNamespace, "configMap", opts.TaskStoreConfigMap)
+	}
+
+	// Create callback handler (Phase 5 adds callback endpoint)
+	// Webhooks and callbacks get a guard each, so a flood of webhooks
+	// cannot hold up the comments of finished tasks.
+	callbackHandler := NewCallbackHandler(opts.CallbackSecret, ghClient, apiClient, log, callbackOpts...)
 
 	// Build router
 	r := chi.NewRouter()
@@ -96,17 +173,70 @@ func Run(opts Options) error {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
-	r.Get("/readyz", func(w http.ResponseWriter, _ *http.Request) {
-		if !healthy.Load() {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			_, _ = w.Write([]byte("unhealthy"))
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
-	})
+	// Neither dependency fails readiness: GitHub does not redeliver
+	// webhooks sent while no replica is ready, so an outage is only
+	// reported.
+	r.Get("/readyz", readiness.New(
+		readiness.Check{Name: "github", Interval: 5 * time.Minute, Run: ghClient.CheckCredentials},
+		readiness.Check{Name: "api", Interval: 10 * time.Second, Run: apiClient.Ping},
+	).ServeHTTP)
 
 	// Webhook handler
+	webhookOpts := []WebhookOption{
+		WithRepoCache(NewRepoCache(ghClient, opts.RepoCacheTTL)),
+		WithRepoConfigPath(opts.RepoConfigPath),
+		WithEventGuard(NewEventGuard(opts.MaxConcurrentEvents, opts.EventTimeout, log.WithName("webhooks"))),
+	}
+	if opts.IssueContextCacheSize > 0 {
+		webhookOpts = append(webhookOpts, WithIssueContextCache(opts.IssueContextCacheSize))
+	}
+	if opts.VerifyAfterMerge {
+		webhookOpts = append(webhookOpts, WithPostMergeVerification())
+	}
+	if opts.MentionHandle != "" {
+		webhookOpts = append(webhookOpts, WithMentionHandle(opts.MentionHandle))
+	}
+	if opts.MinPermission != "" {
+		webhookOpts = append(webhookOpts, WithMinPermission(opts.MinPermission))
+	}
+	if opts.AckReactions {
+		webhookOpts = append(webhookOpts, WithReactionAcknowledgment())
+	}
+	if opts.TriggerLabel != "" {
+		webhookOpts = append(webhookOpts, WithTriggerLabel(opts.TriggerLabel))
+	}
+	if opts.Assignee != "" {
+		webhookOpts = append(webhookOpts, WithAssignee(opts.Assignee))
+	}
+	if opts.DeliveryCacheSize > 0 {
+		var store DeliveryStore
+		if opts.DeliveryStore == TaskStoreConfigMap {
+			var err error
+			store, err = newConfigMapDeliveryStoreFromConfig(opts.TaskStoreNamespace, opts.DeliveryStoreConfigMap)
+			if err != nil {
+				return err
+			}
+			log.Info("keeping webhook delivery IDs in a ConfigMap",
+				"namespace", opts.TaskStoreNamespace, "configMap", opts.DeliveryStoreConfigMap)
+		}
+		webhookOpts = append(webhookOpts,
+			WithDeliveryDeduplication(NewDeliveryDeduplicator(opts.DeliveryCacheSize, store, log.WithName("deliveries"))))
+	}
+	if len(opts.AllowedRepos) > 0 || opts.AllowedReposFile != "" {
+		patterns := opts.AllowedRepos
+		if opts.AllowedReposFile != "" {
+			filePatterns, err := ReadRepoPatterns(opts.AllowedReposFile)
+			if err != nil {
+				return err
+			}
+			patterns = append(patterns, filePatterns...)
+		}
+		allowlist, err := NewRepoAllowlist(patterns)
+		if err != nil {
+			return fmt.Errorf("allowed repositories: %w", err)
+		}
+		webhookOpts = append(webhookOpts, WithRepoAllowlist(allowlist))
+	}
 	webhookHandler := NewWebhookHandler(
 		opts.WebhookSecret,
 		ghClient,
@@ -115,6 +245,7 @@ func Run(opts Options) error {
 		opts.CallbackURL,
 		opts.DefaultSandboxTemplate,
 		log,
+		webhookOpts...,
 	)
 
 	// Webhook endpoint with rate limiting and content-type validation
@@ -127,28 +258,84 @@ func Run(opts Options) error {
 	// Callback endpoint with content-type validation
 	r.With(requireJSON).Post("/callback", callbackHandler.ServeHTTP)
 
+	ln, err := listen.Listen(opts.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("listener: %w", err)
+	}
 	srv := &http.Server{
-		Addr:         opts.ListenAddr,
-		Handler:      r,
+		Handler:      tracing.Handler(r, "shepherd-github"),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 60 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}
+	var metricsLn net.Listener
+	if opts.MetricsListenAddr != "" {
+		metricsLn, err = listen.Listen(opts.MetricsListenAddr)
+		if err != nil {
+			_ = ln.Close()
+			return fmt.Errorf("metrics listener: %w", err)
+		}
+	}
+	var debugLn net.Listener
+	if opts.DebugListenAddr != "" {
+		debugLn, err = listen.Listen(opts.DebugListenAddr)
+		if err != nil {
+			_ = ln.Close()
+			if metricsLn != nil {
+				_ = metricsLn.Close()
+			}
+			return fmt.Errorf("debug listener: %w", err)
+		}
+	}
+	// Metrics server, on its own listener so scrapes do not reach the
+	// webhook port
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metricsHandler())
+	metricsSrv := &http.Server{
+		Handler:      metricsMux,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 60 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+	debugSrv := debug.NewServer()
 
-	errCh := make(chan error, 1)
+	if opts.Digest.Enabled {
+		go NewDigester(ghClient, apiClient, opts.Digest, log).Run(ctx)
+	}
+	if opts.ReconcileWindow > 0 {
+		go NewReconciler(ghClient, apiClient, callbackHandler, opts.CallbackURL, opts.ReconcileWindow, log).Run(ctx)
+	}
+
+	errCh := make(chan error, 3)
 	go func() {
 		log.Info("starting GitHub adapter", "addr", opts.ListenAddr)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
 			errCh <- fmt.Errorf("server error: %w", err)
 		}
 	}()
+	if metricsLn != nil {
+		go func() {
+			log.Info("starting metrics server", "addr", opts.MetricsListenAddr)
+			if err := metricsSrv.Serve(metricsLn); err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("metrics server: %w", err)
+			}
+		}()
+	}
+	if debugLn != nil {
+		go func() {
+			log.Info("starting debug server", "addr", opts.DebugListenAddr)
+			if err := debugSrv.Serve(debugLn); err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("debug server: %w", err)
+			}
+		}()
+	}
 
 	select {
 	case <-ctx.Done():
 		log.Info("shutting down GitHub adapter")
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer shutdownCancel()
-		return srv.Shutdown(shutdownCtx)
+		return errors.Join(srv.Shutdown(shutdownCtx), metricsSrv.Shutdown(shutdownCtx), debugSrv.Shutdown(shutdownCtx))
 	case err := <-errCh:
 		return err
 	}
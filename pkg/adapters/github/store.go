@@ -0,0 +1,303 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TaskMetadataStore keeps the metadata of the tasks the adapter created
+// until they finish, so their callbacks can be routed back to GitHub. The
+// API fallback in resolveTaskMetadata only recovers the issue of a task,
+// not its acknowledgment comment or mode.
+type TaskMetadataStore interface {
+	// Get returns the metadata of taskID; ok is false if it is unknown.
+	Get(ctx context.Context, taskID string) (meta TaskMetadata, ok bool, err error)
+	// Put stores the metadata of taskID, replacing any earlier metadata.
+	Put(ctx context.Context, taskID string, meta TaskMetadata) error
+	// Update calls mutate with the metadata of taskID and stores the
+	// result if mutate returns true, without losing concurrent updates. It
+	// reports whether it stored it; unknown tasks are left alone.
+	Update(ctx context.Context, taskID string, mutate func(*TaskMetadata) bool) (bool, error)
+	// Delete removes the metadata of taskID.
+	Delete(ctx context.Context, taskID string) error
+}
+
+var (
+	_ TaskMetadataStore = (*memoryTaskStore)(nil)
+	_ TaskMetadataStore = (*configMapTaskStore)(nil)
+)
+
+// memoryTaskStore keeps task metadata in memory, so it is lost when the
+// adapter restarts and is not shared between replicas.
+type memoryTaskStore struct {
+	mu    sync.Mutex
+	tasks map[string]TaskMetadata
+}
+
+// newMemoryTaskStore returns an empty in-memory store.
+func newMemoryTaskStore() *memoryTaskStore {
+	return &memoryTaskStore{tasks: make(map[string]TaskMetadata)}
+}
+
+func (s *memoryTaskStore) Get(_ context.Context, taskID string) (TaskMetadata, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	meta, ok := s.tasks[taskID]
+	return meta, ok, nil
+}
+
+func (s *memoryTaskStore) Put(_ context.Context, taskID string, meta TaskMetadata) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[taskID] = meta
+	return nil
+}
+
+func (s *memoryTaskStore) Update(_ context.Context, taskID string, mutate func(*TaskMetadata) bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	meta, ok := s.tasks[taskID]
+	if !ok || !mutate(&meta) {
+		return false, nil
+	}
+	s.tasks[taskID] = meta
+	return true, nil
+}
+
+func (s *memoryTaskStore) Delete(_ context.Context, taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tasks, taskID)
+	return nil
+}
+
+// Stores selectable with Options.TaskStore and Options.DeliveryStore.
+const (
+	TaskStoreMemory    = "memory"
+	TaskStoreConfigMap = "configmap"
+)
+
+// DefaultTaskStoreConfigMap is the ConfigMap the adapter keeps task
+// metadata in with the configmap task store.
+const DefaultTaskStoreConfigMap = "shepherd-github-tasks"
+
+// taskMetadataRetention is how long the configmap task store keeps the
+// metadata of a task whose final callback never arrived. A ConfigMap holds
+// at most 1 MiB, so such entries must not pile up.
+const taskMetadataRetention = 7 * 24 * time.Hour
+
+// configMapEntry is the value a task's ID maps to in the ConfigMap.
+type configMapEntry struct {
+	Meta   TaskMetadata `json:"meta"`
+	Stored time.Time    `json:"stored"`
+}
+
+// configMapData reads and writes the data of a ConfigMap the adapter keeps
+// state in, creating it on the first write.
+type configMapData struct {
+	client client.Client
+	key    client.ObjectKey
+	// what names the state in errors, such as "task store".
+	what string
+}
+
+// read returns the ConfigMap, or a new one not yet created if it does not
+// exist.
+func (d configMapData) read(ctx context.Context) (*corev1.ConfigMap, error) {
+	var cm corev1.ConfigMap
+	err := d.client.Get(ctx, d.key, &cm)
+	if apierrors.IsNotFound(err) {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: d.key.Namespace, Name: d.key.Name}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", d.what, err)
+	}
+	return &cm, nil
+}
+
+// modify applies mutate to the ConfigMap's data and writes it if mutate
+// returns true, starting over if another replica wrote it meanwhile.
+func (d configMapData) modify(ctx context.Context, mutate func(data map[string]string) (bool, error)) (bool, error) {
+	var written bool
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		written = false
+		cm, err := d.read(ctx)
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		changed, err := mutate(cm.Data)
+		if err != nil || !changed {
+			return err
+		}
+		if cm.ResourceVersion == "" {
+			err = d.client.Create(ctx, cm)
+		} else {
+			err = d.client.Update(ctx, cm)
+		}
+		written = err == nil
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("writing %s: %w", d.what, err)
+	}
+	return written, nil
+}
+
+// configMapTaskStore keeps task metadata in a ConfigMap, one key per task,
+// so it survives restarts and is shared by all replicas of the adapter.
+// Writes conflicting with another replica's are retried.
+type configMapTaskStore struct {
+	data configMapData
+	now  func() time.Time
+}
+
+// NewConfigMapTaskStore returns a store that keeps task metadata in the
+// ConfigMap namespace/name, creating it on the first write.
+func NewConfigMapTaskStore(c client.Client, namespace, name string) TaskMetadataStore {
+	return &configMapTaskStore{
+		data: configMapData{client: c, key: client.ObjectKey{Namespace: namespace, Name: name}, what: "task store"},
+		now:  time.Now,
+	}
+}
+
+// newConfigMapTaskStoreFromConfig returns a configmap task store using the
+// in-cluster or kubeconfig credentials.
+func newConfigMapTaskStoreFromConfig(namespace, name string) (TaskMetadataStore, error) {
+	c, err := newStoreClient(namespace, "task store")
+	if err != nil {
+		return nil, err
+	}
+	return NewConfigMapTaskStore(c, namespace, cmp.Or(name, DefaultTaskStoreConfigMap)), nil
+}
+
+// newStoreClient returns a Kubernetes client for the ConfigMap store what
+// in namespace, using the in-cluster or kubeconfig credentials.
+func newStoreClient(namespace, what string) (client.Client, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("the configmap %s needs a namespace", what)
+	}
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("getting kubernetes config for the %s: %w", what, err)
+	}
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("creating kubernetes client for the %s: %w", what, err)
+	}
+	return c, nil
+}
+
+// modify applies mutate like configMapData.modify. Entries past
+// taskMetadataRetention are dropped with the write.
+func (s *configMapTaskStore) modify(ctx context.Context, mutate func(data map[string]string) (bool, error)) (bool, error) {
+	return s.data.modify(ctx, func(data map[string]string) (bool, error) {
+		changed, err := mutate(data)
+		if err != nil || !changed {
+			return false, err
+		}
+		s.prune(data)
+		return true, nil
+	})
+}
+
+// prune drops the entries of data stored longer than
+// taskMetadataRetention ago, and entries it cannot read.
+func (s *configMapTaskStore) prune(data map[string]string) {
+	for taskID, value := range data {
+		var entry configMapEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil || s.now().Sub(entry.Stored) > taskMetadataRetention {
+			delete(data, taskID)
+		}
+	}
+}
+
+func (s *configMapTaskStore) Get(ctx context.Context, taskID string) (TaskMetadata, bool, error) {
+	cm, err := s.data.read(ctx)
+	if err != nil {
+		return TaskMetadata{}, false, err
+	}
+	value, ok := cm.Data[taskID]
+	if !ok {
+		return TaskMetadata{}, false, nil
+	}
+	var entry configMapEntry
+	if err := json.Unmarshal([]byte(value), &entry); err != nil {
+		return TaskMetadata{}, false, fmt.Errorf("decoding metadata of task %s: %w", taskID, err)
+	}
+	return entry.Meta, true, nil
+}
+
+func (s *configMapTaskStore) Put(ctx context.Context, taskID string, meta TaskMetadata) error {
+	value, err := json.Marshal(configMapEntry{Meta: meta, Stored: s.now()})
+	if err != nil {
+		return fmt.Errorf("encoding metadata of task %s: %w", taskID, err)
+	}
+	_, err = s.modify(ctx, func(data map[string]string) (bool, error) {
+		data[taskID] = string(value)
+		return true, nil
+	})
+	return err
+}
+
+func (s *configMapTaskStore) Update(ctx context.Context, taskID string, mutate func(*TaskMetadata) bool) (bool, error) {
+	return s.modify(ctx, func(data map[string]string) (bool, error) {
+		value, ok := data[taskID]
+		if !ok {
+			return false, nil
+		}
+		var entry configMapEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			return false, fmt.Errorf("decoding metadata of task %s: %w", taskID, err)
+		}
+		if !mutate(&entry.Meta) {
+			return false, nil
+		}
+		updated, err := json.Marshal(entry)
+		if err != nil {
+			return false, fmt.Errorf("encoding metadata of task %s: %w", taskID, err)
+		}
+		data[taskID] = string(updated)
+		return true, nil
+	})
+}
+
+func (s *configMapTaskStore) Delete(ctx context.Context, taskID string) error {
+	_, err := s.modify(ctx, func(data map[string]string) (bool, error) {
+		_, ok := data[taskID]
+		delete(data, taskID)
+		return ok, nil
+	})
+	return err
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// storedTask returns the metadata h keeps for taskID.
+func storedTask(h *CallbackHandler, taskID string) (TaskMetadata, bool) {
+	meta, ok, _ := h.tasks.Get(context.Background(), taskID)
+	return meta, ok
+}
+
+func TestConfigMapTaskStore(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	store := NewConfigMapTaskStore(c, "shepherd", DefaultTaskStoreConfigMap)
+
+	_, ok, err := store.Get(ctx, "task-1")
+	require.NoError(t, err)
+	assert.False(t, ok, "unknown before the ConfigMap exists")
+
+	meta := TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 42, Mode: "plan", CorrelationID: "run-1"}
+	require.NoError(t, store.Put(ctx, "task-1", meta))
+
+	// Another replica sees the metadata.
+	other := NewConfigMapTaskStore(c, "shepherd", DefaultTaskStoreConfigMap)
+	got, ok, err := other.Get(ctx, "task-1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, meta, got)
+
+	ackedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	updated, err := other.Update(ctx, "task-1", func(m *TaskMetadata) bool {
+		m.AckCommentID, m.AckedAt = 7, ackedAt
+		return true
+	})
+	require.NoError(t, err)
+	assert.True(t, updated)
+	got, _, err = store.Get(ctx, "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), got.AckCommentID)
+	assert.True(t, ackedAt.Equal(got.AckedAt))
+
+	updated, err = store.Update(ctx, "task-2", func(*TaskMetadata) bool { return true })
+	require.NoError(t, err)
+	assert.False(t, updated, "unknown tasks are left alone")
+
+	require.NoError(t, store.Delete(ctx, "task-1"))
+	_, ok, err = other.Get(ctx, "task-1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestConfigMapTaskStore_PrunesStaleEntries(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	store := NewConfigMapTaskStore(c, "shepherd", DefaultTaskStoreConfigMap).(*configMapTaskStore)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Put(ctx, "task-old", TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 1}))
+	now = now.Add(taskMetadataRetention + time.Minute)
+	require.NoError(t, store.Put(ctx, "task-new", TaskMetadata{Owner: "org", Repo: "repo", IssueNumber: 2}))
+
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "shepherd", Name: DefaultTaskStoreConfigMap}, &cm))
+	assert.NotContains(t, cm.Data, "task-old")
+	assert.Contains(t, cm.Data, "task-new")
+}
+
+func TestCallbackHandler_TaskMetadataStoreSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+	f := &fakePullRequest{}
+	handler, callbackHandler := newPullRequestTestHandler(t, f)
+	WithTaskMetadataStore(NewConfigMapTaskStore(c, "shepherd", DefaultTaskStoreConfigMap))(callbackHandler)
+	WithLiveProgress(true)(callbackHandler)
+
+	body, err := json.Marshal(createTestIssueCommentEvent("org", "repo", 7, "@shepherd plan the login"))
+	require.NoError(t, err)
+	handler.handleIssueComment(ctx, body)
+	require.Len(t, f.comments, 1)
+
+	// A new handler, as after a restart or on another replica, still
+	// knows the task's mode and acknowledgment comment.
+	_, restarted := newPullRequestTestHandler(t, f)
+	WithTaskMetadataStore(NewConfigMapTaskStore(c, "shepherd", DefaultTaskStoreConfigMap))(restarted)
+	WithLiveProgress(true)(restarted)
+	restarted.handleCallback(ctx, &api.CallbackPayload{
+		TaskID: "task-pr", Event: api.EventCompleted, Details: map[string]any{"summary": "Split the handler"},
+	})
+	assert.Len(t, f.comments, 1, "the result replaces the acknowledgment")
+	require.Len(t, f.edits, 1)
+	assert.Contains(t, f.edits[0], "Split the handler")
+
+	_, ok := storedTask(restarted, "task-pr")
+	assert.False(t, ok, "finished tasks are removed")
+}
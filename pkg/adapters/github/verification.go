@@ -0,0 +1,175 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gh "github.com/google/go-github/v75/github"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// handlePullRequest processes pull_request events. A merged PR from a
+// shepherd branch triggers a post-merge verification task when enabled.
+func (h *WebhookHandler) handlePullRequest(ctx context.Context, body []byte) {
+	if !h.verifyAfterMerge {
+		return
+	}
+
+	var event gh.PullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.log.Error(err, "failed to parse pull_request event")
+		return
+	}
+
+	pr := event.GetPullRequest()
+	if event.GetAction() != "closed" || !pr.GetMerged() {
+		return
+	}
+	if !h.allowlist.Allows(event.GetRepo().GetFullName()) {
+		h.log.V(1).Info("repository is not allowlisted, skipping verification", "repo", event.GetRepo().GetFullName())
+		return
+	}
+	// Branches created by the runner are named {branchPrefix}{taskID}. An
+	// invalid configuration leaves the default prefix; it is reported when
+	// a task is requested.
+	cfg, err := h.loadRepoConfig(ctx, event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName())
+	if err != nil {
+		h.log.V(1).Info("invalid repository configuration, using the default branch prefix", "error", err.Error())
+	}
+	taskID, ok := strings.CutPrefix(pr.GetHead().GetRef(), cfg.branchPrefix())
+	if !ok || taskID == "" {
+		return
+	}
+
+	h.scheduleVerification(ctx, &event, taskID, cfg)
+}
+
+// scheduleVerification creates a verification task for the issue that the
+// merged PR's originating task was created from. The outcome is reported on
+// that issue through the regular callback flow.
+func (h *WebhookHandler) scheduleVerification(ctx context.Context, event *gh.PullRequestEvent, taskID string, cfg RepoConfig) {
+	pr := event.GetPullRequest()
+	log := h.log.WithValues(logging.TaskID, taskID, "prURL", pr.GetHTMLURL())
+
+	original, err := h.apiClient.GetTask(ctx, taskID)
+	if err != nil {
+		log.Error(err, "failed to fetch originating task, skipping verification")
+		return
+	}
+	if original.Task.SourceType != api.SourceTypeIssue {
+		log.V(1).Info("originating task was not created from an issue, skipping verification",
+			"sourceType", original.Task.SourceType)
+		return
+	}
+	meta, err := parseSourceURL(h.ghClient.pathPrefix(), original.Task.SourceURL)
+	if err != nil {
+		log.Error(err, "failed to parse originating task sourceURL, skipping verification")
+		return
+	}
+
+	repoLabel := strings.ReplaceAll(event.GetRepo().GetFullName(), "/", "-")
+	issueLabel := strconv.Itoa(meta.IssueNumber)
+	ref := h.verificationRef(ctx, event)
+
+	createReq := api.CreateTaskRequest{
+		Repo: api.RepoRequest{
+			URL: original.Repo.URL,
+			Ref: ref,
+		},
+		Task: api.TaskRequest{
+			Description: fmt.Sprintf("Verify that %s resolved %s", pr.GetHTMLURL(), original.Task.SourceURL),
+			Context:     buildVerificationContext(original, pr),
+			SourceURL:   original.Task.SourceURL,
+			SourceType:  api.SourceTypeVerification,
+			SourceID:    issueLabel,
+		},
+		Callback: h.callbackURL,
+		Runner: &api.RunnerConfig{
+			SandboxTemplateName: h.defaultSandboxTemplate,
+		},
+		Labels: map[string]string{
+			"shepherd.io/repo":     repoLabel,
+			"shepherd.io/issue":    issueLabel,
+			"shepherd.io/verifies": taskID,
+		},
+	}
+	if l := languageLabel(event.GetRepo().GetLanguage()); l != "" {
+		createReq.Labels[languageLabelKey] = l
+	}
+	cfg.apply(&createReq)
+
+	taskResp, err := h.apiClient.CreateTask(ctx, createReq)
+	if err != nil {
+		log.Error(err, "failed to create verification task")
+		return
+	}
+	log.Info("created verification task", "verificationTaskID", taskResp.ID, logging.CorrelationID, taskResp.CorrelationID)
+
+	meta.Verification = true
+	meta.CorrelationID = taskResp.CorrelationID
+	meta.Lineage = "verifies " + taskID
+	h.callbackHandler.RegisterTask(ctx, taskResp.ID, meta)
+
+	ack, err := h.ghClient.CreateComment(ctx, meta.Owner, meta.Repo, meta.IssueNumber,
+		withTaskMarker(formatVerificationStarted(pr.GetHTMLURL(), taskResp.ID), taskResp.ID, taskResp.CorrelationID))
+	if err != nil {
+		log.Error(err, "failed to post verification comment")
+		return
+	}
+	h.callbackHandler.setAck(ctx, taskResp.ID, ack)
+}
+
+// verificationRef returns the branch a verification task checks out: the
+// merged PR's base branch, or the default branch if the base branch has
+// been deleted since.
+func (h *WebhookHandler) verificationRef(ctx context.Context, event *gh.PullRequestEvent) string {
+	base := event.GetPullRequest().GetBase().GetRef()
+	if h.repos == nil {
+		return base
+	}
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repo := event.GetRepo().GetName()
+	ref, err := h.repos.ResolveRef(ctx, owner, repo, base)
+	if errors.Is(err, errUnknownRef) {
+		h.log.Info("base branch no longer exists, verifying the default branch", "ref", base)
+		ref, err = h.repos.ResolveRef(ctx, owner, repo, "")
+	}
+	if err != nil {
+		h.log.Error(err, "failed to resolve verification ref, using the base branch", "ref", base)
+		return base
+	}
+	return ref
+}
+
+// buildVerificationContext describes the original task and the merged PR
+// for the verification runner.
+func buildVerificationContext(original *api.TaskResponse, pr *gh.PullRequest) string {
+	var sb strings.Builder
+	sb.WriteString("## Original Task\n\n")
+	fmt.Fprintf(&sb, "Issue: %s\n\n%s\n\n", original.Task.SourceURL, original.Task.Description)
+	sb.WriteString("## Merged Pull Request\n\n")
+	fmt.Fprintf(&sb, "%s\n\n**%s**\n\n%s\n", pr.GetHTMLURL(), pr.GetTitle(), pr.GetBody())
+	return sb.String()
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gh "github.com/google/go-github/v75/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+const testMergedPRURL = "https://github.com/org/repo/pull/7"
+
+func mergedPullRequestEvent(action string, merged bool, headRef string) []byte {
+	event := gh.PullRequestEvent{
+		Action: gh.Ptr(action),
+		PullRequest: &gh.PullRequest{
+			Number:  gh.Ptr(7),
+			Merged:  gh.Ptr(merged),
+			HTMLURL: gh.Ptr(testMergedPRURL),
+			Title:   gh.Ptr("Fix login crash"),
+			Body:    gh.Ptr("Handles nil sessions."),
+			Head:    &gh.PullRequestBranch{Ref: gh.Ptr(headRef)},
+			Base:    &gh.PullRequestBranch{Ref: gh.Ptr("main")},
+		},
+		Repo: &gh.Repository{
+			Name:     gh.Ptr("repo"),
+			FullName: gh.Ptr("org/repo"),
+			Owner:    &gh.User{Login: gh.Ptr("org")},
+		},
+	}
+	body, _ := json.Marshal(event)
+	return body
+}
+
+// verificationFixture wires a webhook handler to fake API and GitHub servers.
+type verificationFixture struct {
+	handler       *WebhookHandler
+	callback      *CallbackHandler
+	createdTask   *api.CreateTaskRequest
+	postedComment string
+}
+
+func newVerificationFixture(t *testing.T, originalSourceType string, opts ...WebhookOption) *verificationFixture {
+	t.Helper()
+	f := &verificationFixture{}
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == testAPITasksPath+"/task-orig":
+			_ = json.NewEncoder(w).Encode(api.TaskResponse{
+				ID:   "task-orig",
+				Repo: api.RepoRequest{URL: "https://github.com/org/repo.git"},
+				Task: api.TaskRequest{
+					Description: "fix the login crash",
+					SourceURL:   "https://github.com/org/repo/issues/42",
+					SourceType:  originalSourceType,
+					SourceID:    "42",
+				},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == testAPITasksPath:
+			f.createdTask = &api.CreateTaskRequest{}
+			_ = json.NewDecoder(r.Body).Decode(f.createdTask)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"task-verify","status":{"phase":"Pending"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(apiServer.Close)
+
+	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == testGHCommentsPath {
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			f.postedComment = body["body"]
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1}`))
+		}
+	}))
+	t.Cleanup(ghServer.Close)
+
+	ghClient := newTestClientFromServer(t, ghServer)
+	apiClient := NewAPIClient(apiServer.URL)
+	f.callback = NewCallbackHandler("", ghClient, apiClient, ctrl.Log.WithName("test"))
+	f.handler = NewWebhookHandler("", ghClient, apiClient, f.callback,
+		"http://callback", "default", ctrl.Log.WithName("test"), opts...)
+	return f
+}
+
+func TestWebhookHandler_PostMergeVerification(t *testing.T) {
+	t.Run("merged shepherd PR creates verification task", func(t *testing.T) {
+		f := newVerificationFixture(t, api.SourceTypeIssue, WithPostMergeVerification())
+
+		f.handler.handlePullRequest(context.Background(), mergedPullRequestEvent("closed", true, "shepherd/task-orig"))
+
+		require.NotNil(t, f.createdTask)
+		assert.Equal(t, api.SourceTypeVerification, f.createdTask.Task.SourceType)
+		assert.Equal(t, "https://github.com/org/repo/issues/42", f.createdTask.Task.SourceURL)
+		assert.Equal(t, "42", f.createdTask.Task.SourceID)
+		assert.Contains(t, f.createdTask.Task.Description, testMergedPRURL)
+		assert.Contains(t, f.createdTask.Task.Context, "fix the login crash")
+		assert.Contains(t, f.createdTask.Task.Context, "Fix login crash")
+		assert.Equal(t, "https://github.com/org/repo.git", f.createdTask.Repo.URL)
+		assert.Equal(t, "main", f.createdTask.Repo.Ref)
+		assert.Equal(t, map[string]string{
+			"shepherd.io/repo":     "org-repo",
+			"shepherd.io/issue":    "42",
+			"shepherd.io/verifies": "task-orig",
+		}, f.createdTask.Labels)
+
+		assert.Contains(t, f.postedComment, testMergedPRURL)
+		assert.Contains(t, f.postedComment, "task-verify")
+
+		meta, _ := storedTask(f.callback, "task-verify")
+		assert.True(t, meta.Verification)
+		assert.Equal(t, 42, meta.IssueNumber)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		f := newVerificationFixture(t, api.SourceTypeIssue)
+
+		f.handler.handlePullRequest(context.Background(), mergedPullRequestEvent("closed", true, "shepherd/task-orig"))
+
+		assert.Nil(t, f.createdTask)
+	})
+
+	t.Run("ignores closed without merge", func(t *testing.T) {
+		f := newVerificationFixture(t, api.SourceTypeIssue, WithPostMergeVerification())
+
+		f.handler.handlePullRequest(context.Background(), mergedPullRequestEvent("closed", false, "shepherd/task-orig"))
+
+		assert.Nil(t, f.createdTask)
+	})
+
+	t.Run("ignores non-shepherd branches", func(t *testing.T) {
+		f := newVerificationFixture(t, api.SourceTypeIssue, WithPostMergeVerification())
+
+		f.handler.handlePullRequest(context.Background(), mergedPullRequestEvent("closed", true, "feature/login"))
+
+		assert.Nil(t, f.createdTask)
+	})
+
+	t.Run("does not verify verification tasks", func(t *testing.T) {
+		f := newVerificationFixture(t, api.SourceTypeVerification, WithPostMergeVerification())
+
+		f.handler.handlePullRequest(context.Background(), mergedPullRequestEvent("closed", true, "shepherd/task-orig"))
+
+		assert.Nil(t, f.createdTask)
+	})
+}
+
+func TestCallbackHandler_VerificationOutcome(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		want  string
+	}{
+		{"passed", api.EventCompleted, "verification passed"},
+		{"failed", api.EventFailed, "verification failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var postedComment string
+			ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				postedComment = body["body"]
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			}))
+			defer ghServer.Close()
+
+			handler := NewCallbackHandler("", newTestClientFromServer(t, ghServer), nil, ctrl.Log.WithName("test"))
+			handler.RegisterTask(context.Background(), "task-verify", TaskMetadata{
+				Owner: "org", Repo: "repo", IssueNumber: 42, Verification: true,
+			})
+
+			handler.handleCallback(context.Background(), &api.CallbackPayload{
+				TaskID:  "task-verify",
+				Event:   tt.event,
+				Message: "TestLogin passes on main",
+			})
+
+			assert.Contains(t, postedComment, tt.want)
+			assert.Contains(t, postedComment, "TestLogin passes on main")
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,13 +30,31 @@ import (
 	"strings"
 
 	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
 	"github.com/go-logr/logr"
 	gh "github.com/google/go-github/v75/github"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
-// shepherdMentionRegex matches @shepherd mentions but not email-style patterns
+// DefaultMentionHandle is the handle that triggers tasks unless another
+// is configured with WithMentionHandle.
+const DefaultMentionHandle = "shepherd"
+
+// mentionHandleRegex matches the handles WithMentionHandle accepts: the
+// characters of GitHub user names and App slugs.
+var mentionHandleRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// ValidMentionHandle reports whether handle, without the @, can be used as
+// the mention that triggers tasks.
+func ValidMentionHandle(handle string) bool {
+	return mentionHandleRegex.MatchString(handle)
+}
+
+// mentionRegex matches @handle mentions but not email-style patterns
 // (e.g., user@shepherd.io). Requires start-of-string or whitespace before the @.
-var shepherdMentionRegex = regexp.MustCompile(`(?i)(?:^|\s)@shepherd\b`)
+func mentionRegex(handle string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|\s)@` + regexp.QuoteMeta(handle) + `\b`)
+}
 
 // WebhookHandler handles incoming GitHub webhooks.
 type WebhookHandler struct {
@@ -46,6 +65,82 @@ type WebhookHandler struct {
 	callbackURL            string
 	defaultSandboxTemplate string
 	log                    logr.Logger
+	verifyAfterMerge       bool
+	repos                  *RepoCache            // nil leaves repo.ref to the runner
+	issueContexts          *issueContextCache    // nil fetches comments for every task
+	guard                  *EventGuard           // nil handles events without limits
+	deliveries             *DeliveryDeduplicator // nil handles redeliveries again
+	minPermission          string                // "" accepts mentions from anyone
+	ackReactions           bool                  // acknowledge mentions with reactions, not comments
+	allowlist              *RepoAllowlist        // nil accepts mentions in every repository
+	triggerLabel           string                // "" ignores labels
+	assignee               string                // "" ignores assignments
+	repoConfigPath         string                // "" reads no repository configuration
+	// handle is mentioned to trigger a task, and mention matches it.
+	handle  string
+	mention *regexp.Regexp
+}
+
+// WebhookOption configures optional WebhookHandler behavior.
+type WebhookOption func(*WebhookHandler)
+
+// WithPostMergeVerification schedules a verification task when a PR opened
+// by shepherd is merged.
+func WithPostMergeVerification() WebhookOption {
+	return func(h *WebhookHandler) {
+		h.verifyAfterMerge = true
+	}
+}
+
+// WithEventGuard handles issue comment and pull request events through
+// guard, which limits their duration and how many run at once.
+func WithEventGuard(guard *EventGuard) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.guard = guard
+	}
+}
+
+// WithDeliveryDeduplication skips deliveries of issue comment, issues and
+// pull request events that deliveries saw before, so GitHub's redeliveries
+// do not create tasks twice.
+func WithDeliveryDeduplication(deliveries *DeliveryDeduplicator) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.deliveries = deliveries
+	}
+}
+
+// WithRepoCache looks up repository metadata to set the ref of new tasks
+// to the default branch and to validate refs taken from events.
+func WithRepoCache(repos *RepoCache) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.repos = repos
+	}
+}
+
+// WithRepoConfigPath reads each repository's configuration from the file
+// at path on its default branch, rather than from DefaultRepoConfigPath.
+// An empty path reads none. It needs WithRepoCache.
+func WithRepoConfigPath(path string) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.repoConfigPath = path
+	}
+}
+
+// WithIssueContextCache keeps the context built for up to maxEntries issues,
+// so triggering another task on an unchanged issue does not fetch all of its
+// comments again.
+func WithIssueContextCache(maxEntries int) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.issueContexts = newIssueContextCache(maxEntries)
+	}
+}
+
+// WithMentionHandle triggers tasks on mentions of @handle instead of
+// @shepherd, typically the slug of the GitHub App.
+func WithMentionHandle(handle string) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.handle = handle
+	}
 }
 
 // NewWebhookHandler creates a new webhook handler.
@@ -57,8 +152,9 @@ func NewWebhookHandler(
 	callbackURL string,
 	defaultSandboxTemplate string,
 	log logr.Logger,
+	opts ...WebhookOption,
 ) *WebhookHandler {
-	return &WebhookHandler{
+	h := &WebhookHandler{
 		secret:                 secret,
 		ghClient:               ghClient,
 		apiClient:              apiClient,
@@ -66,7 +162,14 @@ func NewWebhookHandler(
 		callbackURL:            callbackURL,
 		defaultSandboxTemplate: defaultSandboxTemplate,
 		log:                    log,
+		handle:                 DefaultMentionHandle,
+		repoConfigPath:         DefaultRepoConfigPath,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	h.mention = mentionRegex(h.handle)
+	return h
 }
 
 // ServeHTTP handles webhook requests.
@@ -92,18 +195,41 @@ func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.rememberInstallation(body)
+
 	// Route by event type
 	eventType := r.Header.Get("X-GitHub-Event")
 	h.log.V(1).Info("received webhook", "event", eventType)
 
+	var handle func(context.Context, []byte)
 	switch eventType {
 	case "issue_comment":
-		h.handleIssueComment(r.Context(), body)
+		handle = h.handleIssueComment
+	case "issues":
+		handle = h.handleIssues
+	case "pull_request":
+		handle = h.handlePullRequest
+	case "pull_request_review_comment":
+		handle = h.handleReviewComment
+	case "repository":
+		h.handleRepository(body)
 	case "ping":
 		h.log.Info("received ping webhook")
 	default:
 		h.log.V(1).Info("ignoring event type", "event", eventType)
 	}
+	delivery := r.Header.Get("X-GitHub-Delivery")
+	if handle != nil && h.deliveries.seen(r.Context(), delivery) {
+		h.log.Info("skipping duplicate delivery", "event", eventType, "delivery", delivery)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if handle != nil && !h.guard.Run(r.Context(), eventType, func(ctx context.Context) { handle(ctx, body) }) {
+		// GitHub records the failed delivery, so it can be redelivered.
+		h.deliveries.forget(r.Context(), delivery)
+		http.Error(w, "too many events in progress", http.StatusServiceUnavailable)
+		return
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
@@ -125,6 +251,40 @@ func (h *WebhookHandler) verifySignature(body []byte, signature string) bool {
 	return hmac.Equal([]byte(expected), []byte(signature))
 }
 
+// rememberInstallation records the App installation a webhook about a
+// repository came from, which the client then authenticates as for that
+// repository.
+func (h *WebhookHandler) rememberInstallation(body []byte) {
+	var event struct {
+		Installation *gh.Installation `json:"installation"`
+		Repo         *gh.Repository   `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return
+	}
+	h.ghClient.RememberInstallation(event.Repo.GetOwner().GetLogin(), event.Repo.GetName(), event.Installation.GetID())
+}
+
+// handleRepository drops cached metadata of a repository that was changed,
+// for example renamed or given a new default branch.
+func (h *WebhookHandler) handleRepository(body []byte) {
+	if h.repos == nil {
+		return
+	}
+	var event gh.RepositoryEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.log.Error(err, "failed to parse repository event")
+		return
+	}
+	owner := event.GetRepo().GetOwner().GetLogin()
+	repo := event.GetRepo().GetName()
+	h.repos.Invalidate(owner, repo)
+	if from := event.GetChanges().GetRepo().GetName().GetFrom(); from != "" {
+		h.repos.Invalidate(owner, from)
+	}
+	h.log.V(1).Info("invalidated repository metadata", "repo", event.GetRepo().GetFullName(), "action", event.GetAction())
+}
+
 // handleIssueComment processes issue_comment events.
 func (h *WebhookHandler) handleIssueComment(ctx context.Context, body []byte) {
 	var event gh.IssueCommentEvent
@@ -138,25 +298,88 @@ func (h *WebhookHandler) handleIssueComment(ctx context.Context, body []byte) {
 		return
 	}
 
-	// Check for @shepherd mention
+	// Check for a mention of the adapter's handle
 	commentBody := event.GetComment().GetBody()
-	if !shepherdMentionRegex.MatchString(commentBody) {
+	if !h.mention.MatchString(commentBody) {
 		return
 	}
 
-	// Extract task description from comment
-	description := strings.TrimSpace(shepherdMentionRegex.ReplaceAllString(commentBody, ""))
-	if description == "" {
-		description = "Work on this issue"
-	}
-
-	h.log.Info("processing @shepherd mention",
+	h.log.Info("processing mention",
 		"repo", event.GetRepo().GetFullName(),
 		"issue", event.GetIssue().GetNumber(),
 		"user", event.GetComment().GetUser().GetLogin(),
 	)
 
-	h.processTask(ctx, &event, description)
+	if !h.authorized(ctx, event.GetRepo(), event.GetIssue().GetNumber(), event.GetComment().GetUser().GetLogin()) {
+		return
+	}
+
+	text := h.mention.ReplaceAllString(commentBody, "")
+	if command := controlCommand(text); command != "" {
+		h.handleControl(ctx, &event, command)
+		return
+	}
+	cmd, err := parseCommand(text)
+	if err != nil {
+		h.rejectCommand(ctx, event.GetRepo(), event.GetIssue().GetNumber(), err)
+		return
+	}
+
+	// Comments on a PR's conversation arrive as issue comments too.
+	if event.GetIssue().IsPullRequest() {
+		cmd.describe("pull request")
+		h.processPullRequestComment(ctx, &event, cmd)
+		return
+	}
+
+	cmd.describe("issue")
+	h.processTask(ctx, &event, cmd)
+}
+
+// handleIssues processes issues events, creating a task when the trigger
+// label is applied to an issue or the issue is assigned to the assignee,
+// and cancelling it when the issue is unassigned.
+func (h *WebhookHandler) handleIssues(ctx context.Context, body []byte) {
+	if h.triggerLabel == "" && h.assignee == "" {
+		return
+	}
+	var event gh.IssuesEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.log.Error(err, "failed to parse issues event")
+		return
+	}
+	switch event.GetAction() {
+	case "labeled":
+		h.handleLabeled(ctx, &event)
+	case "assigned":
+		h.handleAssigned(ctx, &event)
+	case "unassigned":
+		h.handleUnassigned(ctx, &event)
+	}
+}
+
+// rejectCommand answers a mention that could not be parsed with the
+// command's usage.
+func (h *WebhookHandler) rejectCommand(ctx context.Context, repo *gh.Repository, number int, err error) {
+	h.log.Info("invalid command", "error", err.Error())
+	if commentErr := h.ghClient.PostComment(ctx, repo.GetOwner().GetLogin(), repo.GetName(), number,
+		formatInvalidCommand(err, h.handle)); commentErr != nil {
+		h.log.Error(commentErr, "failed to post invalid-command comment")
+	}
+}
+
+// languageLabelKey holds the primary language of a task's repository, so
+// operator template routes can send tasks to a sandbox with its tooling.
+const languageLabelKey = "shepherd.io/language"
+
+// languageLabel converts a GitHub language name such as "Python" or "C++"
+// to a label value, or "" if there is none.
+func languageLabel(language string) string {
+	value := strings.NewReplacer("+", "p", "#", "sharp", " ", "-").Replace(strings.ToLower(language))
+	if len(validation.IsValidLabelValue(value)) > 0 {
+		return ""
+	}
+	return value
 }
 
 // maxContextSize is the soft limit for context passed to the API.
@@ -164,29 +387,54 @@ func (h *WebhookHandler) handleIssueComment(ctx context.Context, body []byte) {
 // safe headroom since gzip typically achieves 3-5x compression on text.
 const maxContextSize = 1_000_000 // 1MB
 
-// processTask handles the task creation workflow.
-func (h *WebhookHandler) processTask(ctx context.Context, event *gh.IssueCommentEvent, description string) {
-	owner := event.GetRepo().GetOwner().GetLogin()
-	repo := event.GetRepo().GetName()
-	issueNumber := event.GetIssue().GetNumber()
-	repoFullName := event.GetRepo().GetFullName()
-	issueURL := event.GetIssue().GetHTMLURL()
-	repoURL := event.GetRepo().GetCloneURL()
+// issueTrigger is a request for a task on an issue: a mention in one of
+// its comments, or the trigger label applied to it.
+type issueTrigger struct {
+	repo        *gh.Repository
+	issue       *gh.Issue
+	requestedBy string
+	// comment is the comment with the mention, or nil for a label.
+	comment *gh.IssueComment
+}
+
+// processTask handles the task creation workflow for a mention on an
+// issue.
+func (h *WebhookHandler) processTask(ctx context.Context, event *gh.IssueCommentEvent, cmd taskCommand) {
+	h.processIssueTask(ctx, issueTrigger{
+		repo:        event.GetRepo(),
+		issue:       event.GetIssue(),
+		requestedBy: event.GetComment().GetUser().GetLogin(),
+		comment:     event.GetComment(),
+	}, cmd)
+}
+
+// processIssueTask creates a task for an issue, unless one is running.
+func (h *WebhookHandler) processIssueTask(ctx context.Context, trigger issueTrigger, cmd taskCommand) {
+	owner := trigger.repo.GetOwner().GetLogin()
+	repo := trigger.repo.GetName()
+	issueNumber := trigger.issue.GetNumber()
+	repoFullName := trigger.repo.GetFullName()
+	issueURL := trigger.issue.GetHTMLURL()
+	repoURL := trigger.repo.GetCloneURL()
 
 	// Format label values
 	repoLabel := strings.ReplaceAll(repoFullName, "/", "-")
 	issueLabel := fmt.Sprintf("%d", issueNumber)
 
-	// Check for active tasks (deduplication)
-	activeTasks, err := h.apiClient.GetActiveTasks(ctx, repoLabel, issueLabel)
+	cfg, ok := h.repoConfig(ctx, trigger.repo, issueNumber, cmd.command())
+	if !ok {
+		return
+	}
+
+	// Check for an active task of the issue (deduplication)
+	task, err := h.apiClient.GetActiveTask(ctx, issueURL)
 	if err != nil {
 		h.log.Error(err, "failed to check for active tasks")
 		// Continue anyway - better to potentially create duplicate than fail silently
 	}
 
-	if len(activeTasks) > 0 {
-		task := activeTasks[0]
-		h.log.Info("task already running", "taskID", task.ID, "status", task.Status.Phase)
+	if task != nil {
+		h.log.Info("task already running", logging.TaskID, task.ID, "status", task.Status.Phase)
 
 		if commentErr := h.ghClient.PostComment(ctx, owner, repo, issueNumber,
 			formatAlreadyRunning(task.ID, task.Status.Phase)); commentErr != nil {
@@ -196,19 +444,38 @@ func (h *WebhookHandler) processTask(ctx context.Context, event *gh.IssueComment
 	}
 
 	// Build context from issue body and comments
-	issueBody := event.GetIssue().GetBody()
-	taskContext := h.buildContext(ctx, owner, repo, issueNumber, issueBody)
+	taskContext := h.issueContext(ctx, owner, repo, trigger.issue)
+
+	// Check out the default branch explicitly, so a task is not affected
+	// by the default branch changing while it waits for a sandbox.
+	ref := cmd.Branch
+	if h.repos != nil {
+		ref, err = h.repos.ResolveRef(ctx, owner, repo, cmd.Branch)
+		if errors.Is(err, errUnknownRef) {
+			h.log.Info("not creating task for unknown branch", "ref", cmd.Branch)
+			if commentErr := h.ghClient.PostComment(ctx, owner, repo, issueNumber,
+				formatUnknownBranch(cmd.Branch)); commentErr != nil {
+				h.log.Error(commentErr, "failed to post unknown-branch comment")
+			}
+			return
+		}
+		if err != nil {
+			h.log.Error(err, "failed to look up branch, using it unresolved", "ref", cmd.Branch)
+			ref = cmd.Branch
+		}
+	}
 
 	// Create task
 	createReq := api.CreateTaskRequest{
 		Repo: api.RepoRequest{
 			URL: repoURL,
+			Ref: ref,
 		},
 		Task: api.TaskRequest{
-			Description: description,
+			Description: cmd.Description,
 			Context:     taskContext,
 			SourceURL:   issueURL,
-			SourceType:  "issue",
+			SourceType:  api.SourceTypeIssue,
 			SourceID:    issueLabel,
 		},
 		Callback: h.callbackURL,
@@ -218,40 +485,105 @@ func (h *WebhookHandler) processTask(ctx context.Context, event *gh.IssueComment
 		Labels: map[string]string{
 			"shepherd.io/repo":  repoLabel,
 			"shepherd.io/issue": issueLabel,
+			// Bot logins end in "[bot]", which is not valid in a label value
+			"shepherd.io/requested-by": strings.TrimSuffix(trigger.requestedBy, "[bot]"),
 		},
 	}
+	if l := languageLabel(trigger.repo.GetLanguage()); l != "" {
+		createReq.Labels[languageLabelKey] = l
+	}
+	cfg.apply(&createReq)
+	cmd.apply(&createReq)
+	lineageKey, previousID, lineage := h.issueLineage(ctx, repoLabel, issueLabel)
+	if lineageKey != "" {
+		createReq.Labels[lineageKey] = previousID
+	}
 
 	taskResp, err := h.apiClient.CreateTask(ctx, createReq)
 	if err != nil {
-		h.log.Error(err, "failed to create task")
-		if commentErr := h.ghClient.PostComment(ctx, owner, repo, issueNumber,
-			formatFailed("Failed to create task")); commentErr != nil {
-			h.log.Error(commentErr, "failed to post error comment")
-		}
+		h.reportCreateFailure(ctx, owner, repo, issueNumber, err)
 		return
 	}
 
-	h.log.Info("created task", "taskID", taskResp.ID)
+	h.log.Info("created task", logging.TaskID, taskResp.ID, logging.CorrelationID, taskResp.CorrelationID)
+
+	meta := TaskMetadata{
+		Owner:         owner,
+		Repo:          repo,
+		IssueNumber:   issueNumber,
+		Mode:          cmd.Mode,
+		CorrelationID: taskResp.CorrelationID,
+		Lineage:       lineage,
+	}
+	h.reactTo(&meta, trigger.comment.GetID(), trigger.comment.GetHTMLURL(), false)
+	h.acknowledge(ctx, taskResp, meta)
+}
+
+// reportCreateFailure tells the issue or PR that its task could not be
+// created, without exposing the API's error details.
+func (h *WebhookHandler) reportCreateFailure(ctx context.Context, owner, repo string, number int, err error) {
+	comment := formatFailed("Failed to create task", h.handle)
+	var maintErr *MaintenanceError
+	if errors.As(err, &maintErr) {
+		h.log.Info("not creating task, shepherd is under maintenance")
+		comment = formatMaintenance(maintErr.Message, maintErr.Until, h.handle)
+	} else {
+		h.log.Error(err, "failed to create task")
+	}
+	if commentErr := h.ghClient.PostComment(ctx, owner, repo, number, comment); commentErr != nil {
+		h.log.Error(commentErr, "failed to post error comment")
+	}
+}
+
+// acknowledge registers the metadata of a created task for callback
+// handling and acknowledges the mention: with a reaction if meta has one to
+// react to, or else, or if reacting fails, with a comment, retrying it in
+// the background if GitHub rejects it.
+func (h *WebhookHandler) acknowledge(ctx context.Context, taskResp *api.TaskResponse, meta TaskMetadata) {
+	h.callbackHandler.RegisterTask(ctx, taskResp.ID, meta)
+	if meta.ReactTo != 0 {
+		err := h.ghClient.AddCommentReaction(ctx, meta.Owner, meta.Repo, meta.ReactTo, meta.ReactToReview, reactionStarted)
+		if err == nil {
+			return
+		}
+		h.log.Error(err, "failed to react to mention, acknowledging with a comment", logging.TaskID, taskResp.ID)
+	}
 
-	// Register task metadata for callback handling
-	h.callbackHandler.RegisterTask(taskResp.ID, TaskMetadata{
-		Owner:       owner,
-		Repo:        repo,
-		IssueNumber: issueNumber,
-	})
+	ack, commentErr := h.ghClient.CreateComment(ctx, meta.Owner, meta.Repo, meta.IssueNumber,
+		withTaskMarker(formatAcknowledge(taskResp.ID, meta.Lineage), taskResp.ID, taskResp.CorrelationID))
+	if commentErr != nil {
+		h.log.Error(commentErr, "failed to post acknowledgment comment, retrying in the background")
+		h.callbackHandler.queueAck(ctx, taskResp.ID, meta)
+		return
+	}
+	h.callbackHandler.setAck(ctx, taskResp.ID, ack)
+}
 
-	// Post acknowledgment comment
-	if commentErr := h.ghClient.PostComment(ctx, owner, repo, issueNumber,
-		formatAcknowledge(taskResp.ID)); commentErr != nil {
-		h.log.Error(commentErr, "failed to post acknowledgment comment")
+// issueContext returns the task context for an issue, reusing the context
+// built for an earlier trigger if the issue has not changed since.
+func (h *WebhookHandler) issueContext(ctx context.Context, owner, repo string, issue *gh.Issue) string {
+	updatedAt := issue.GetUpdatedAt().Time
+	if h.issueContexts == nil || updatedAt.IsZero() {
+		taskContext, _ := h.buildContext(ctx, owner, repo, issue.GetNumber(), issue.GetBody())
+		return taskContext
+	}
+	if taskContext, ok := h.issueContexts.get(owner, repo, issue.GetNumber(), updatedAt); ok {
+		h.log.V(1).Info("reusing issue context", "issue", issue.GetNumber())
+		return taskContext
+	}
+	taskContext, complete := h.buildContext(ctx, owner, repo, issue.GetNumber(), issue.GetBody())
+	if complete {
+		h.issueContexts.put(owner, repo, issue.GetNumber(), updatedAt, taskContext)
 	}
+	return taskContext
 }
 
 // buildContext assembles the context string from issue body and comments.
+// complete is false if the comments could not be fetched.
 // Truncates if the total context exceeds maxContextSize.
 func (h *WebhookHandler) buildContext(
 	ctx context.Context, owner, repo string, issueNumber int, issueBody string,
-) string {
+) (taskContext string, complete bool) {
 	var sb strings.Builder
 	sb.WriteString("## Issue Description\n\n")
 	sb.WriteString(issueBody)
@@ -261,7 +593,7 @@ func (h *WebhookHandler) buildContext(
 	comments, err := h.ghClient.ListIssueComments(ctx, owner, repo, issueNumber)
 	if err != nil {
 		h.log.Error(err, "failed to fetch issue comments")
-		return sb.String()
+		return sb.String(), false
 	}
 
 	if len(comments) > 0 {
@@ -277,5 +609,5 @@ func (h *WebhookHandler) buildContext(
 		}
 	}
 
-	return sb.String()
+	return sb.String(), true
 }
@@ -28,10 +28,14 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	gh "github.com/google/go-github/v75/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
 )
 
 const (
@@ -108,33 +112,100 @@ func TestWebhookHandler_ServeHTTP(t *testing.T) {
 		handler.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+
+	t.Run("rejects events over capacity", func(t *testing.T) {
+		full := NewWebhookHandler(secret, nil, nil, nil, "", "default", ctrl.Log.WithName("test"),
+			WithEventGuard(NewEventGuard(0, time.Minute, ctrl.Log.WithName("test"))))
+
+		body := []byte(`{"action":"created","comment":{"body":"@shepherd fix"}}`)
+		w := httptest.NewRecorder()
+		full.ServeHTTP(w, signedRequest(t, secret, body, "issue_comment"))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		// Pings are not limited.
+		w = httptest.NewRecorder()
+		full.ServeHTTP(w, signedRequest(t, secret, []byte(`{"zen":"test"}`), "ping"))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
 }
 
-func TestShepherdMentionRegex(t *testing.T) {
+func TestMentionRegex(t *testing.T) {
 	tests := []struct {
-		input string
-		match bool
+		handle string
+		input  string
+		match  bool
 	}{
-		{"@shepherd fix this bug", true},
-		{"@SHEPHERD fix this bug", true},
-		{"@Shepherd fix this bug", true},
-		{"Hey @shepherd can you help?", true},
-		{"@shepherd", true},
-		{"\n@shepherd fix it", true},
-		{"@shepherding", false},
-		{"no mention here", false},
-		{"email@shepherd.io", false},
-		{"user@shepherd", false},
-		{"test@shepherd.com stuff", false},
+		{DefaultMentionHandle, "@shepherd fix this bug", true},
+		{DefaultMentionHandle, "@SHEPHERD fix this bug", true},
+		{DefaultMentionHandle, "@Shepherd fix this bug", true},
+		{DefaultMentionHandle, "Hey @shepherd can you help?", true},
+		{DefaultMentionHandle, "@shepherd", true},
+		{DefaultMentionHandle, "\n@shepherd fix it", true},
+		{DefaultMentionHandle, "@shepherding", false},
+		{DefaultMentionHandle, "no mention here", false},
+		{DefaultMentionHandle, "email@shepherd.io", false},
+		{DefaultMentionHandle, "user@shepherd", false},
+		{DefaultMentionHandle, "test@shepherd.com stuff", false},
+		{"acme-coder", "@acme-coder fix this bug", true},
+		{"acme-coder", "@Acme-Coder fix this bug", true},
+		{"acme-coder", "@shepherd fix this bug", false},
+		{"acme-coder", "@acme-coders fix this bug", false},
+		{"acme.coder", "@acmexcoder fix this bug", false},
 	}
 
 	for _, tc := range tests {
-		t.Run(tc.input, func(t *testing.T) {
-			assert.Equal(t, tc.match, shepherdMentionRegex.MatchString(tc.input))
+		t.Run(tc.handle+" "+tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.match, mentionRegex(tc.handle).MatchString(tc.input))
 		})
 	}
 }
 
+func TestWebhookHandler_MentionHandle(t *testing.T) {
+	var descriptions []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == testAPITasksPath+"/active":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+		case r.URL.Path == testAPITasksPath && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`[]`))
+		case r.URL.Path == testAPITasksPath && r.Method == http.MethodPost:
+			var req api.CreateTaskRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			descriptions = append(descriptions, req.Task.Description)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"new-task-123"}`))
+		}
+	}))
+	defer apiServer.Close()
+	ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer ghServer.Close()
+
+	ghClient := newTestClientFromServer(t, ghServer)
+	apiClient := NewAPIClient(apiServer.URL)
+	callbackHandler := NewCallbackHandler("", ghClient, apiClient, ctrl.Log.WithName("test"),
+		WithCallbackMentionHandle("acme-coder"))
+	handler := NewWebhookHandler("", ghClient, apiClient, callbackHandler, "http://callback", "default",
+		ctrl.Log.WithName("test"), WithMentionHandle("acme-coder"))
+
+	for _, comment := range []string{"@shepherd fix this bug", "@acme-coder fix the login bug"} {
+		body, err := json.Marshal(createTestIssueCommentEvent("org", "repo", 42, comment))
+		require.NoError(t, err)
+		handler.handleIssueComment(context.Background(), body)
+	}
+	assert.Equal(t, []string{"fix the login bug"}, descriptions)
+	assert.Contains(t, formatFailed("boom", callbackHandler.handle), "commenting with @acme-coder again")
+}
+
 func TestWebhookHandler_BuildContext(t *testing.T) {
 	t.Run("includes issue body and comments", func(t *testing.T) {
 		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -150,7 +221,8 @@ func TestWebhookHandler_BuildContext(t *testing.T) {
 		ghClient := newTestClientFromServer(t, ghServer)
 		handler := NewWebhookHandler("secret", ghClient, nil, nil, "", "default", ctrl.Log.WithName("test"))
 
-		result := handler.buildContext(context.Background(), "testorg", "testrepo", 42, "Issue body text")
+		result, complete := handler.buildContext(context.Background(), "testorg", "testrepo", 42, "Issue body text")
+		assert.True(t, complete)
 
 		assert.Contains(t, result, "## Issue Description")
 		assert.Contains(t, result, "Issue body text")
@@ -187,7 +259,7 @@ func TestWebhookHandler_BuildContext(t *testing.T) {
 		ghClient := newTestClientFromServer(t, ghServer)
 		handler := NewWebhookHandler("secret", ghClient, nil, nil, "", "default", ctrl.Log.WithName("test"))
 
-		result := handler.buildContext(context.Background(), "testorg", "testrepo", 1, "Short issue body")
+		result, _ := handler.buildContext(context.Background(), "testorg", "testrepo", 1, "Short issue body")
 
 		assert.Contains(t, result, "truncated due to size limit")
 		assert.LessOrEqual(t, len(result), maxContextSize+500)
@@ -202,7 +274,8 @@ func TestWebhookHandler_BuildContext(t *testing.T) {
 		ghClient := newTestClientFromServer(t, ghServer)
 		handler := NewWebhookHandler("secret", ghClient, nil, nil, "", "default", ctrl.Log.WithName("test"))
 
-		result := handler.buildContext(context.Background(), "testorg", "testrepo", 1, "Issue body")
+		result, complete := handler.buildContext(context.Background(), "testorg", "testrepo", 1, "Issue body")
+		assert.False(t, complete)
 
 		assert.Contains(t, result, "## Issue Description")
 		assert.Contains(t, result, "Issue body")
@@ -213,9 +286,9 @@ func TestWebhookHandler_BuildContext(t *testing.T) {
 func TestWebhookHandler_ProcessTask(t *testing.T) {
 	t.Run("deduplication - posts already running comment when active task exists", func(t *testing.T) {
 		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.URL.Path == testAPITasksPath && r.URL.Query().Get("active") == "true" {
+			if r.URL.Path == testAPITasksPath+"/active" {
 				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(`[{"id":"existing-task","status":{"phase":"Running"}}]`))
+				_, _ = w.Write([]byte(`{"id":"existing-task","status":{"phase":"Running"}}`))
 			}
 		}))
 		defer apiServer.Close()
@@ -246,7 +319,7 @@ func TestWebhookHandler_ProcessTask(t *testing.T) {
 		)
 
 		event := createTestIssueCommentEvent("org", "repo", 42, "@shepherd fix this")
-		handler.processTask(context.Background(), event, "fix this")
+		handler.processTask(context.Background(), event, taskCommand{Description: "fix this"})
 
 		assert.Contains(t, postedComment, "existing-task")
 		assert.Contains(t, postedComment, "already running")
@@ -300,7 +373,8 @@ func TestWebhookHandler_ProcessTask(t *testing.T) {
 		)
 
 		event := createTestIssueCommentEvent("org", "repo", 42, "@shepherd fix this bug")
-		handler.processTask(context.Background(), event, "fix this bug")
+		event.Repo.Language = gh.Ptr("Python")
+		handler.processTask(context.Background(), event, taskCommand{Description: "fix this bug"})
 
 		assert.Contains(t, postedComment, "new-task-123")
 		assert.Contains(t, postedComment, "working on your request")
@@ -309,6 +383,60 @@ func TestWebhookHandler_ProcessTask(t *testing.T) {
 		assert.Equal(t, "fix this bug", taskMap["description"])
 		runnerMap := createdTask["runner"].(map[string]any)
 		assert.Equal(t, "custom-template", runnerMap["sandboxTemplateName"])
+		labelsMap := createdTask["labels"].(map[string]any)
+		assert.Equal(t, "testuser", labelsMap["shepherd.io/requested-by"])
+		assert.Equal(t, "python", labelsMap["shepherd.io/language"])
+	})
+
+	t.Run("rerun - relates the task to the issue's last task", func(t *testing.T) {
+		var createdTask api.CreateTaskRequest
+		var postedComment string
+
+		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == testAPITasksPath {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.Method {
+				case http.MethodGet:
+					assert.Equal(t, "org-repo", r.URL.Query().Get("repo"))
+					assert.Equal(t, "42", r.URL.Query().Get("issue"))
+					_, _ = w.Write([]byte(`[{"id":"old-task","task":{"sourceType":"issue"},` +
+						`"status":{"phase":"Failed"},"completionTime":"2026-10-10T08:00:00Z"}]`))
+				case http.MethodPost:
+					_ = json.NewDecoder(r.Body).Decode(&createdTask)
+					w.WriteHeader(http.StatusCreated)
+					_, _ = w.Write([]byte(`{"id":"new-task-123","status":{"phase":"Pending"}}`))
+				}
+			}
+		}))
+		defer apiServer.Close()
+
+		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == http.MethodPost && r.URL.Path == testGHCommentsPath {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				postedComment = body["body"]
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":1,"html_url":"https://github.com/org/repo/issues/42#issuecomment-1"}`))
+			} else if r.Method == http.MethodGet && r.URL.Path == testGHCommentsPath {
+				_, _ = w.Write([]byte(`[]`))
+			}
+		}))
+		defer ghServer.Close()
+
+		ghClient := newTestClientFromServer(t, ghServer)
+		apiClient := NewAPIClient(apiServer.URL)
+		callbackHandler := NewCallbackHandler("secret", ghClient, apiClient, ctrl.Log.WithName("test"))
+		handler := NewWebhookHandler("secret", ghClient, apiClient, callbackHandler, "http://callback", "default",
+			ctrl.Log.WithName("test"))
+
+		handler.processTask(context.Background(), createTestIssueCommentEvent("org", "repo", 42, "@shepherd again"), taskCommand{Description: "again"})
+
+		assert.Equal(t, "old-task", createdTask.Labels["shepherd.io/retry-of"])
+		assert.Contains(t, postedComment, "This task retries old-task, which failed.")
+		meta, _ := storedTask(callbackHandler, "new-task-123")
+		assert.Equal(t, "retries old-task, which failed", meta.Lineage)
+		assert.Equal(t, "https://github.com/org/repo/issues/42#issuecomment-1", meta.AckURL)
 	})
 
 	t.Run("API failure - posts error comment", func(t *testing.T) {
@@ -357,13 +485,67 @@ func TestWebhookHandler_ProcessTask(t *testing.T) {
 		)
 
 		event := createTestIssueCommentEvent("org", "repo", 42, "@shepherd fix this")
-		handler.processTask(context.Background(), event, "fix this")
+		handler.processTask(context.Background(), event, taskCommand{Description: "fix this"})
 
 		// Should show generic error message, not internal API error details (security fix)
 		assert.Contains(t, postedComment, "unable to complete")
 		assert.Contains(t, postedComment, "Failed to create task")
 		assert.NotContains(t, postedComment, "repo.url")
 	})
+
+	t.Run("maintenance - posts maintenance comment", func(t *testing.T) {
+		var postedComment string
+
+		apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == testAPITasksPath {
+				switch r.Method {
+				case http.MethodGet:
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`[]`))
+				case http.MethodPost:
+					w.Header().Set("Content-Type", "application/json")
+					w.Header().Set(api.MaintenanceHeader, "true")
+					w.WriteHeader(http.StatusServiceUnavailable)
+					_, _ = w.Write([]byte(`{"error":"shepherd is under maintenance","details":"Upgrading the cluster"}`))
+				}
+			}
+		}))
+		defer apiServer.Close()
+
+		ghServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost && r.URL.Path == testGHCommentsPath {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				postedComment = body["body"]
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			} else if r.Method == http.MethodGet && r.URL.Path == testGHCommentsPath {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`[]`))
+			}
+		}))
+		defer ghServer.Close()
+
+		ghClient := newTestClientFromServer(t, ghServer)
+		apiClient := NewAPIClient(apiServer.URL)
+		callbackHandler := NewCallbackHandler("secret", ghClient, apiClient, ctrl.Log.WithName("test"))
+		handler := NewWebhookHandler(
+			"secret",
+			ghClient,
+			apiClient,
+			callbackHandler,
+			"http://callback",
+			"default",
+			ctrl.Log.WithName("test"),
+		)
+
+		event := createTestIssueCommentEvent("org", "repo", 42, "@shepherd fix this")
+		handler.processTask(context.Background(), event, taskCommand{Description: "fix this"})
+
+		assert.Contains(t, postedComment, "under maintenance")
+		assert.Contains(t, postedComment, "Upgrading the cluster")
+		assert.NotContains(t, postedComment, "Failed to create task")
+	})
 }
 
 // Helper to create a test GitHub client from an httptest server
@@ -395,3 +577,16 @@ func createTestIssueCommentEvent(owner, repo string, issueNum int, commentBody s
 		},
 	}
 }
+
+func TestLanguageLabel(t *testing.T) {
+	tests := map[string]string{
+		"Python":           "python",
+		"C++":              "cpp",
+		"C#":               "csharp",
+		"Jupyter Notebook": "jupyter-notebook",
+		"":                 "",
+	}
+	for language, want := range tests {
+		assert.Equal(t, want, languageLabel(language), language)
+	}
+}
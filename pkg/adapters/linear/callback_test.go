@@ -0,0 +1,182 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linear
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func callbackRequest(t *testing.T, secret string, payload api.CallbackPayload) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(body))
+	req.Header.Set("X-Shepherd-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestCallbackHandler_PostsComments(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload api.CallbackPayload
+		want    string
+	}{
+		{
+			name: "completed with PR",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventCompleted,
+				Details: map[string]any{"pr_url": "https://github.com/org/app/pull/7", "summary": "Fixed the handler"}},
+			want: "Pull Request: https://github.com/org/app/pull/7\n\n> Fixed the handler",
+		},
+		{
+			name:    "failed",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed, Message: "tests did not pass"},
+			want:    "Error: tests did not pass",
+		},
+		{
+			name:    "cancelled",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventCancelled, Message: "cancelled by alice"},
+			want:    "cancelled by alice",
+		},
+		{
+			name: "links",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed,
+				Links: &api.TaskLinks{Dashboard: "https://shepherd.example.com/tasks/task-1"}},
+			want: "[View task](https://shepherd.example.com/tasks/task-1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeLinear{}
+			_, h := newTestWebhookHandler(t, f)
+			h.secret = "secret"
+			h.RegisterTask("task-1", TaskMetadata{IssueID: "issue-uuid"})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, callbackRequest(t, "secret", tt.payload))
+			assert.Equal(t, http.StatusOK, w.Code)
+			require.Len(t, f.posted(), 1)
+			assert.Contains(t, f.posted()[0], tt.want)
+			assert.Empty(t, h.tasks, "metadata of a finished task is dropped")
+		})
+	}
+}
+
+func TestCallbackHandler_IgnoresIntermediateEvents(t *testing.T) {
+	f := &fakeLinear{}
+	_, h := newTestWebhookHandler(t, f)
+	h.RegisterTask("task-1", TaskMetadata{IssueID: "issue-uuid"})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "", api.CallbackPayload{TaskID: "task-1", Event: api.EventStarted}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, f.posted())
+}
+
+func TestCallbackHandler_InvalidSignature(t *testing.T) {
+	f := &fakeLinear{}
+	_, h := newTestWebhookHandler(t, f)
+	h.secret = "secret"
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "wrong", api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, f.posted())
+}
+
+func TestCallbackHandler_VerifiesRotatedSecretsAndKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	f := &fakeLinear{}
+	_, h := newTestWebhookHandler(t, f)
+	h.secret, h.secondarySecret, h.publicKeys = "secret", "new-secret", []ed25519.PublicKey{pub}
+	h.RegisterTask("task-1", TaskMetadata{IssueID: "issue-uuid"})
+	payload := api.CallbackPayload{TaskID: "task-1", Event: api.EventStarted}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "new-secret", payload))
+	assert.Equal(t, http.StatusOK, w.Code, "secondary secret")
+
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(body))
+	req.Header.Add("X-Shepherd-Signature", "sha256=unknown")
+	req.Header.Add("X-Shepherd-Signature", api.SignCallbackEd25519(priv, body))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "public key")
+}
+
+func TestCallbackHandler_RecoversMetadataFromAPI(t *testing.T) {
+	f := &fakeLinear{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/tasks/task-1", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.TaskResponse{ID: "task-1", Task: api.TaskRequest{SourceURL: testIssueURL}})
+	})
+	mux.Handle("/", f)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	h := NewCallbackHandler("", newClient(srv.Client(), srv.URL+"/graphql", "lin_api_test"), NewAPIClient(srv.URL),
+		time.Minute, ctrl.Log.WithName("test"))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "", api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, f.posted(), 1)
+	assert.Contains(t, f.posted()[0], "Error: Unknown error")
+}
+
+func TestParseIssueURL(t *testing.T) {
+	tests := []struct {
+		sourceURL string
+		want      string
+		wantErr   bool
+	}{
+		{sourceURL: testIssueURL, want: "ENG-7"},
+		{sourceURL: "https://linear.app/acme/issue/ENG-7", want: "ENG-7"},
+		{sourceURL: "", wantErr: true},
+		{sourceURL: "https://linear.app/acme/project/launch-1a2b", wantErr: true},
+		{sourceURL: "https://linear.app/acme/issue/not-an-id/slug", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sourceURL, func(t *testing.T) {
+			got, err := parseIssueURL(tt.sourceURL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package linear implements the Shepherd adapter for Linear. Mentioning
+// @shepherd in a comment on an issue, or adding the trigger label to it,
+// creates a task, and the adapter comments on the issue with the result.
+package linear
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+)
+
+// defaultEndpoint is Linear's GraphQL API.
+const defaultEndpoint = "https://api.linear.app/graphql"
+
+// Client talks to the Linear GraphQL API with an API key.
+type Client struct {
+	http     *http.Client
+	endpoint string
+	apiKey   string
+}
+
+// NewClient creates a client authenticated with a personal API key of the
+// account the adapter comments as.
+func NewClient(apiKey string) (*Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("linear API key is required")
+	}
+	return newClient(&http.Client{Transport: tracing.Transport(http.DefaultTransport)}, defaultEndpoint, apiKey), nil
+}
+
+func newClient(httpClient *http.Client, endpoint, apiKey string) *Client {
+	return &Client{
+		http:     httpClient,
+		endpoint: endpoint,
+		apiKey:   apiKey,
+	}
+}
+
+// Issue is a Linear issue with its comments, oldest first.
+type Issue struct {
+	ID          string
+	Identifier  string // e.g. "ENG-123"
+	Title       string
+	Description string
+	URL         string
+	TeamKey     string // e.g. "ENG"
+	Comments    []Comment
+}
+
+// Comment is a comment on a Linear issue.
+type Comment struct {
+	Body   string
+	Author string
+}
+
+// Viewer returns the ID of the user the API key belongs to.
+func (c *Client) Viewer(ctx context.Context) (string, error) {
+	var resp struct {
+		Viewer struct {
+			ID string `json:"id"`
+		} `json:"viewer"`
+	}
+	if err := c.query(ctx, `query { viewer { id } }`, nil, &resp); err != nil {
+		return "", fmt.Errorf("getting viewer: %w", err)
+	}
+	return resp.Viewer.ID, nil
+}
+
+// CheckCredentials verifies that the API key is accepted by Linear.
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	_, err := c.Viewer(ctx)
+	return err
+}
+
+const issueQuery = `query($id: String!) {
+  issue(id: $id) {
+    id identifier title description url
+    team { key }
+    comments(first: 250) { nodes { body createdAt user { name } } }
+  }
+}`
+
+// GetIssue fetches an issue by its ID or identifier, such as "ENG-123",
+// with up to 250 of its comments.
+func (c *Client) GetIssue(ctx context.Context, id string) (*Issue, error) {
+	var resp struct {
+		Issue *struct {
+			ID          string `json:"id"`
+			Identifier  string `json:"identifier"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			URL         string `json:"url"`
+			Team        struct {
+				Key string `json:"key"`
+			} `json:"team"`
+			Comments struct {
+				Nodes []struct {
+					Body      string `json:"body"`
+					CreatedAt string `json:"createdAt"`
+					User      *struct {
+						Name string `json:"name"`
+					} `json:"user"`
+				} `json:"nodes"`
+			} `json:"comments"`
+		} `json:"issue"`
+	}
+	if err := c.query(ctx, issueQuery, map[string]any{"id": id}, &resp); err != nil {
+		return nil, fmt.Errorf("getting issue: %w", err)
+	}
+	if resp.Issue == nil {
+		return nil, fmt.Errorf("getting issue: issue %s not found", id)
+	}
+
+	nodes := resp.Issue.Comments.Nodes
+	// RFC 3339 timestamps in UTC sort chronologically as strings.
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].CreatedAt < nodes[j].CreatedAt })
+	issue := &Issue{
+		ID:          resp.Issue.ID,
+		Identifier:  resp.Issue.Identifier,
+		Title:       resp.Issue.Title,
+		Description: resp.Issue.Description,
+		URL:         resp.Issue.URL,
+		TeamKey:     resp.Issue.Team.Key,
+	}
+	for _, n := range nodes {
+		comment := Comment{Body: n.Body, Author: "Integration"}
+		if n.User != nil {
+			comment.Author = n.User.Name
+		}
+		issue.Comments = append(issue.Comments, comment)
+	}
+	return issue, nil
+}
+
+// CreateComment comments on the issue with the given ID.
+func (c *Client) CreateComment(ctx context.Context, issueID, body string) error {
+	var resp struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	err := c.query(ctx, `mutation($input: CommentCreateInput!) { commentCreate(input: $input) { success } }`,
+		map[string]any{"input": map[string]string{"issueId": issueID, "body": body}}, &resp)
+	if err != nil {
+		return fmt.Errorf("creating comment: %w", err)
+	}
+	if !resp.CommentCreate.Success {
+		return fmt.Errorf("creating comment: not successful")
+	}
+	return nil
+}
+
+// query runs a GraphQL query and decodes its data into out. Linear reports
+// most failures in the response's errors, with a 200 or 400 status.
+func (c *Client) query(ctx context.Context, query string, variables map[string]any, out any) error {
+	data, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	// Personal API keys are sent as they are, without "Bearer".
+	req.Header.Set("Authorization", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("linear API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("linear API error: %s", strings.Join(messages, "; "))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("linear API returned %d", resp.StatusCode)
+	}
+	return json.Unmarshal(result.Data, out)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linear
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphQLRequest is a GraphQL request as the client sends it.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// newTestClient creates a Client backed by a test HTTP server.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return newClient(srv.Client(), srv.URL+"/graphql", "lin_api_test")
+}
+
+func TestClient_GetIssue(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "lin_api_test", r.Header.Get("Authorization"))
+		var req graphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "ENG-7", req.Variables["id"])
+		_, _ = w.Write([]byte(`{"data": {"issue": {
+			"id": "issue-uuid", "identifier": "ENG-7", "title": "Login broken", "description": "It 500s",
+			"url": "https://linear.app/acme/issue/ENG-7/login-broken", "team": {"key": "ENG"},
+			"comments": {"nodes": [
+				{"body": "second", "createdAt": "2026-10-02T10:00:00.000Z", "user": null},
+				{"body": "first", "createdAt": "2026-10-01T10:00:00.000Z", "user": {"name": "Alice"}}
+			]}}}}`))
+	}))
+
+	issue, err := client.GetIssue(context.Background(), "ENG-7")
+	require.NoError(t, err)
+	assert.Equal(t, &Issue{
+		ID:          "issue-uuid",
+		Identifier:  "ENG-7",
+		Title:       "Login broken",
+		Description: "It 500s",
+		URL:         "https://linear.app/acme/issue/ENG-7/login-broken",
+		TeamKey:     "ENG",
+		Comments:    []Comment{{Body: "first", Author: "Alice"}, {Body: "second", Author: "Integration"}},
+	}, issue)
+}
+
+func TestClient_CreateComment(t *testing.T) {
+	var received graphQLRequest
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte(`{"data": {"commentCreate": {"success": true}}}`))
+	}))
+
+	require.NoError(t, client.CreateComment(context.Background(), "issue-uuid", "Hello from Shepherd"))
+	assert.Contains(t, received.Query, "commentCreate")
+	assert.Equal(t, map[string]any{"issueId": "issue-uuid", "body": "Hello from Shepherd"}, received.Variables["input"])
+}
+
+func TestClient_Errors(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors": [{"message": "Entity not found"}]}`))
+	}))
+	_, err := client.GetIssue(context.Background(), "ENG-404")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Entity not found")
+
+	client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	err = client.CheckCredentials(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "429")
+
+	client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"commentCreate": {"success": false}}}`))
+	}))
+	assert.Error(t, client.CreateComment(context.Background(), "issue-uuid", "body"))
+}
+
+func TestNewClient_Validation(t *testing.T) {
+	_, err := NewClient("")
+	assert.Error(t, err)
+}
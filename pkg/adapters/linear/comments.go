@@ -0,0 +1,132 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linear
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// Comment templates for different events. They match the ones the forge
+// adapters post, so users see the same messages everywhere.
+const (
+	commentAcknowledge = `Shepherd is working on your request.
+
+Task ID: %s
+Repository: %s
+
+I'll update this issue when I'm done.`
+
+	commentAlreadyRunning = `A Shepherd task is already running for this issue.
+
+Task ID: %s
+Status: %s
+
+Please wait for it to complete before triggering a new one.`
+
+	commentCompleted = `Shepherd has completed the task.
+
+Pull Request: %s
+
+%sPlease review the changes.`
+
+	commentFailed = `Shepherd was unable to complete the task.
+
+Error: %s
+
+You can trigger a new attempt by commenting with @shepherd again.`
+
+	commentCancelled = `The Shepherd task was cancelled.
+
+%s
+
+You can trigger a new attempt by commenting with @shepherd again.`
+
+	commentNoRepository = `Shepherd does not know which repository to work on.
+
+End your comment with "in owner/repo", or ask an operator to map team %s to a repository.`
+)
+
+func formatAcknowledge(taskID, repoName string) string {
+	return fmt.Sprintf(commentAcknowledge, taskID, repoName)
+}
+
+func formatAlreadyRunning(taskID, status string) string {
+	return fmt.Sprintf(commentAlreadyRunning, taskID, status)
+}
+
+// formatCompleted announces the PR of a completed task, with the runner's
+// summary of the change if it sent one.
+func formatCompleted(prURL, summary string) string {
+	if summary != "" {
+		summary = quoteSummary(summary) + "\n\n"
+	}
+	return fmt.Sprintf(commentCompleted, prURL, summary)
+}
+
+// formatCompletedWithoutPR announces a task that completed without a PR.
+func formatCompletedWithoutPR(summary string) string {
+	if summary == "" {
+		return "Shepherd completed the task successfully."
+	}
+	return "Shepherd completed the task successfully.\n\n" + quoteSummary(summary)
+}
+
+// quoteSummary renders the runner's summary as a Markdown block quote, so
+// it stands apart from the comment's own text.
+func quoteSummary(summary string) string {
+	return "> " + strings.ReplaceAll(strings.TrimSpace(summary), "\n", "\n> ")
+}
+
+func formatFailed(errorMsg string) string {
+	if errorMsg == "" {
+		errorMsg = "Unknown error"
+	}
+	return fmt.Sprintf(commentFailed, errorMsg)
+}
+
+func formatCancelled(reason string) string {
+	if reason == "" {
+		reason = "No reason given"
+	}
+	return fmt.Sprintf(commentCancelled, reason)
+}
+
+func formatNoRepository(teamKey string) string {
+	return fmt.Sprintf(commentNoRepository, teamKey)
+}
+
+// withLinks appends the links the API server sent with a callback to
+// comment.
+func withLinks(comment string, links *api.TaskLinks) string {
+	if links == nil {
+		return comment
+	}
+	var parts []string
+	if links.Dashboard != "" {
+		parts = append(parts, fmt.Sprintf("[View task](%s)", links.Dashboard))
+	}
+	if links.Logs != "" {
+		parts = append(parts, fmt.Sprintf("[Logs](%s)", links.Logs))
+	}
+	if len(parts) == 0 {
+		return comment
+	}
+	return comment + "\n\n" + strings.Join(parts, " · ")
+}
@@ -0,0 +1,423 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linear
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+var (
+	// mentionRegex matches @shepherd mentions but not email-style patterns
+	// (e.g., user@shepherd.io). Requires start-of-string or whitespace before the @.
+	mentionRegex = regexp.MustCompile(`(?i)(?:^|\s)@shepherd\b`)
+	// repoSuffixRegex matches a repository given at the end of a request,
+	// as in "fix the login bug in org/repo".
+	repoSuffixRegex = regexp.MustCompile(`(?is)\s+in\s+(\S+?)\.?\s*$`)
+	// repoNameRegex matches an owner/repo repository name.
+	repoNameRegex = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+)
+
+// maxContextSize is the soft limit for context passed to the API, the same
+// as the GitHub adapter's.
+const maxContextSize = 1_000_000 // 1MB
+
+// maxWebhookAge is how far the timestamp of a webhook may be from now.
+// Older deliveries are rejected as possible replays, as Linear recommends.
+const maxWebhookAge = time.Minute
+
+// webhookEvent is the part of a Linear webhook payload the adapter uses.
+type webhookEvent struct {
+	Action string `json:"action"` // "create", "update" or "remove"
+	Type   string `json:"type"`   // "Comment", "Issue", ...
+	Actor  struct {
+		ID string `json:"id"`
+	} `json:"actor"`
+	Data        json.RawMessage `json:"data"`
+	UpdatedFrom json.RawMessage `json:"updatedFrom"`
+	// WebhookTimestamp is when Linear sent the webhook, in milliseconds
+	// since the epoch.
+	WebhookTimestamp int64 `json:"webhookTimestamp"`
+}
+
+type commentData struct {
+	Body    string `json:"body"`
+	IssueID string `json:"issueId"`
+	UserID  string `json:"userId"`
+}
+
+type issueData struct {
+	ID     string `json:"id"`
+	Labels []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// Triggers configures what creates tasks and on which repository.
+type Triggers struct {
+	// Label creates a task when it is added to an issue; empty disables
+	// label triggers.
+	Label string
+	// TeamRepos maps team keys, such as "ENG", to the repository of the
+	// team's tasks, as owner/repo or an https URL.
+	TeamRepos map[string]string
+	// RepoBaseURL is the forge owner/repo names are looked up on.
+	RepoBaseURL string
+}
+
+// Validate checks that every team is mapped to a repository.
+func (t Triggers) Validate() error {
+	for team, ref := range t.TeamRepos {
+		if _, _, ok := resolveRepo(ref, t.RepoBaseURL); !ok {
+			return fmt.Errorf("repository %q of team %s is neither owner/repo nor an https URL", ref, team)
+		}
+	}
+	return nil
+}
+
+// WebhookHandler handles incoming Linear webhooks. Linear expects an
+// answer within five seconds, so tasks are created after the response is
+// sent.
+type WebhookHandler struct {
+	secret                 string
+	client                 *Client
+	apiClient              *APIClient
+	callbackHandler        *CallbackHandler
+	callbackURL            string
+	triggers               Triggers
+	defaultSandboxTemplate string
+	eventTimeout           time.Duration
+	log                    logr.Logger
+	now                    func() time.Time
+
+	mu sync.Mutex
+	// viewerID is the adapter's own user, whose comments are ignored.
+	viewerID string
+}
+
+// NewWebhookHandler creates a new webhook handler. Each event is handled
+// within eventTimeout.
+func NewWebhookHandler(
+	secret string,
+	client *Client,
+	apiClient *APIClient,
+	callbackHandler *CallbackHandler,
+	callbackURL string,
+	triggers Triggers,
+	defaultSandboxTemplate string,
+	eventTimeout time.Duration,
+	log logr.Logger,
+) *WebhookHandler {
+	return &WebhookHandler{
+		secret:                 secret,
+		client:                 client,
+		apiClient:              apiClient,
+		callbackHandler:        callbackHandler,
+		callbackURL:            callbackURL,
+		triggers:               triggers,
+		defaultSandboxTemplate: defaultSandboxTemplate,
+		eventTimeout:           eventTimeout,
+		log:                    log,
+		now:                    time.Now,
+	}
+}
+
+// ServeHTTP handles webhook requests.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Read body with 10MB limit
+	body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		h.log.Error(err, "failed to read webhook body")
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verifySignature(body, r.Header.Get("Linear-Signature")) {
+		h.log.Info("webhook signature verification failed")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.log.Error(err, "failed to parse webhook")
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	if age := h.now().Sub(time.UnixMilli(event.WebhookTimestamp)); age > maxWebhookAge || age < -maxWebhookAge {
+		h.log.Info("rejecting stale webhook", "age", age)
+		http.Error(w, "stale webhook", http.StatusUnauthorized)
+		return
+	}
+
+	h.log.V(1).Info("received webhook", "type", event.Type, "action", event.Action)
+
+	switch {
+	case event.Type == "Comment" && event.Action == "create":
+		h.background(r, func(ctx context.Context) { h.handleComment(ctx, &event) })
+	case event.Type == "Issue" && h.triggers.Label != "" && (event.Action == "create" || event.Action == "update"):
+		h.background(r, func(ctx context.Context) { h.handleIssue(ctx, &event) })
+	default:
+		h.log.V(1).Info("ignoring event", "type", event.Type, "action", event.Action)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySignature verifies the webhook signature, the hex-encoded
+// HMAC-SHA256 of the body.
+func (h *WebhookHandler) verifySignature(body []byte, signature string) bool {
+	if h.secret == "" {
+		return true // No verification if no secret configured
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// background runs fn after the response is sent, within eventTimeout.
+func (h *WebhookHandler) background(r *http.Request, fn func(ctx context.Context)) {
+	ctx := context.WithoutCancel(r.Context())
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, h.eventTimeout)
+		defer cancel()
+		fn(ctx)
+	}()
+}
+
+// handleComment creates a task for a new comment that mentions @shepherd.
+func (h *WebhookHandler) handleComment(ctx context.Context, event *webhookEvent) {
+	var data commentData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		h.log.Error(err, "failed to parse comment")
+		return
+	}
+	if !mentionRegex.MatchString(data.Body) {
+		return
+	}
+	if h.isOwnComment(ctx, data.UserID) {
+		// The adapter's comments mention @shepherd in their instructions.
+		return
+	}
+
+	description := strings.TrimSpace(mentionRegex.ReplaceAllString(data.Body, ""))
+	repoRef := ""
+	if m := repoSuffixRegex.FindStringSubmatchIndex(description); m != nil {
+		candidate := description[m[2]:m[3]]
+		if _, _, ok := resolveRepo(candidate, h.triggers.RepoBaseURL); ok {
+			repoRef = candidate
+			description = strings.TrimSpace(description[:m[0]])
+		}
+	}
+	if description == "" {
+		description = "Work on this issue"
+	}
+
+	h.log.Info("processing @shepherd mention", "issue", data.IssueID, "user", data.UserID)
+	h.processTask(ctx, data.IssueID, description, repoRef, data.UserID)
+}
+
+// isOwnComment reports whether userID is the user the adapter comments
+// as. The user is looked up once; until that succeeds, no comment is
+// taken for the adapter's own.
+func (h *WebhookHandler) isOwnComment(ctx context.Context, userID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.viewerID == "" {
+		id, err := h.client.Viewer(ctx)
+		if err != nil {
+			h.log.Error(err, "failed to look up the adapter's own user")
+			return false
+		}
+		h.viewerID = id
+	}
+	return userID == h.viewerID
+}
+
+// handleIssue creates a task for an issue that the trigger label was just
+// added to, including issues created with it.
+func (h *WebhookHandler) handleIssue(ctx context.Context, event *webhookEvent) {
+	var data issueData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		h.log.Error(err, "failed to parse issue")
+		return
+	}
+	var labelID string
+	for _, l := range data.Labels {
+		if strings.EqualFold(l.Name, h.triggers.Label) {
+			labelID = l.ID
+		}
+	}
+	if labelID == "" {
+		return
+	}
+	if event.Action == "update" {
+		// updatedFrom holds the previous values of the changed fields
+		// only, so labelIds is missing unless the labels changed.
+		var previous struct {
+			LabelIDs *[]string `json:"labelIds"`
+		}
+		if len(event.UpdatedFrom) > 0 {
+			_ = json.Unmarshal(event.UpdatedFrom, &previous)
+		}
+		if previous.LabelIDs == nil || slices.Contains(*previous.LabelIDs, labelID) {
+			return
+		}
+	}
+
+	h.log.Info("processing trigger label", "issue", data.ID, "user", event.Actor.ID)
+	h.processTask(ctx, data.ID, "Work on this issue", "", event.Actor.ID)
+}
+
+// processTask handles the task creation workflow. The repository is
+// repoRef if set, or the one of the issue's team.
+func (h *WebhookHandler) processTask(ctx context.Context, issueID, description, repoRef, userID string) {
+	meta := TaskMetadata{IssueID: issueID}
+	issue, err := h.client.GetIssue(ctx, issueID)
+	if err != nil {
+		h.log.Error(err, "failed to fetch issue", "issue", issueID)
+		return
+	}
+
+	if repoRef == "" {
+		repoRef = h.triggers.TeamRepos[issue.TeamKey]
+	}
+	repoName, repoURL, ok := resolveRepo(repoRef, h.triggers.RepoBaseURL)
+	if !ok {
+		h.log.Info("no repository for issue", "issue", issue.Identifier, "team", issue.TeamKey)
+		h.postComment(ctx, meta, formatNoRepository(issue.TeamKey))
+		return
+	}
+
+	// Check for an active task of the issue (deduplication)
+	task, err := h.apiClient.GetActiveTask(ctx, issue.URL)
+	if err != nil {
+		h.log.Error(err, "failed to check for active tasks")
+		// Continue anyway - better to potentially create duplicate than fail silently
+	}
+	if task != nil {
+		h.log.Info("task already running", logging.TaskID, task.ID, "status", task.Status.Phase)
+		h.postComment(ctx, meta, formatAlreadyRunning(task.ID, task.Status.Phase))
+		return
+	}
+
+	createReq := api.CreateTaskRequest{
+		Repo: api.RepoRequest{URL: repoURL},
+		Task: api.TaskRequest{
+			Description: description,
+			Context:     h.buildContext(issue),
+			SourceURL:   issue.URL,
+			SourceType:  api.SourceTypeIssue,
+			SourceID:    issue.Identifier,
+		},
+		Callback: h.callbackURL,
+		Runner: &api.RunnerConfig{
+			SandboxTemplateName: h.defaultSandboxTemplate,
+		},
+		Labels: map[string]string{
+			"shepherd.io/repo":         strings.ReplaceAll(repoName, "/", "-"),
+			"shepherd.io/issue":        issue.Identifier,
+			"shepherd.io/requested-by": userID,
+		},
+	}
+
+	taskResp, err := h.apiClient.CreateTask(ctx, createReq)
+	if err != nil {
+		h.log.Error(err, "failed to create task")
+		h.postComment(ctx, meta, formatFailed("Failed to create task"))
+		return
+	}
+
+	h.log.Info("created task", logging.TaskID, taskResp.ID, logging.CorrelationID, taskResp.CorrelationID)
+
+	h.callbackHandler.RegisterTask(taskResp.ID, meta)
+	h.postComment(ctx, meta, formatAcknowledge(taskResp.ID, repoName))
+}
+
+func (h *WebhookHandler) postComment(ctx context.Context, meta TaskMetadata, body string) {
+	if err := h.client.CreateComment(ctx, meta.IssueID, body); err != nil {
+		h.log.Error(err, "failed to post comment", "issue", meta.IssueID)
+	}
+}
+
+// buildContext assembles the context string from the issue's title,
+// description and comments, truncated once it exceeds maxContextSize.
+func (h *WebhookHandler) buildContext(issue *Issue) string {
+	var sb strings.Builder
+	sb.WriteString("## Issue Description\n\n")
+	sb.WriteString("# " + issue.Title + "\n\n")
+	sb.WriteString(issue.Description)
+	sb.WriteString("\n\n")
+
+	if len(issue.Comments) > 0 {
+		sb.WriteString("## Comments\n\n")
+		for _, c := range issue.Comments {
+			entry := fmt.Sprintf("**%s** wrote:\n\n%s\n\n---\n\n", c.Author, c.Body)
+			if sb.Len()+len(entry) > maxContextSize {
+				sb.WriteString("\n\n--- Context truncated due to size limit ---\n")
+				h.log.Info("context truncated", "issue", issue.Identifier, "size", sb.Len())
+				break
+			}
+			sb.WriteString(entry)
+		}
+	}
+	return sb.String()
+}
+
+// resolveRepo resolves a repository given as owner/repo on the forge at
+// baseURL, or as the https URL of a repository, to its name and clone URL.
+func resolveRepo(ref, baseURL string) (name, cloneURL string, ok bool) {
+	if repoNameRegex.MatchString(ref) {
+		name = strings.TrimSuffix(ref, ".git")
+		return name, strings.TrimSuffix(baseURL, "/") + "/" + name + ".git", true
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", "", false
+	}
+	name = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if !repoNameRegex.MatchString(name) {
+		return "", "", false
+	}
+	return name, "https://" + u.Host + "/" + name + ".git", true
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linear
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+const testIssueURL = "https://linear.app/acme/issue/ENG-7/login-broken"
+
+func signedRequest(t *testing.T, secret string, payload map[string]any, at time.Time) *http.Request {
+	t.Helper()
+	payload["webhookTimestamp"] = at.UnixMilli()
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	req.Header.Set("Linear-Signature", hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func commentPayload(body, userID string) map[string]any {
+	return map[string]any{
+		"action": "create",
+		"type":   "Comment",
+		"actor":  map[string]any{"id": userID},
+		"data":   map[string]any{"id": "comment-1", "body": body, "issueId": "issue-uuid", "userId": userID},
+	}
+}
+
+// fakeLinear serves the Linear GraphQL API and the Shepherd API tasks
+// endpoints, recording what was posted.
+type fakeLinear struct {
+	mu         sync.Mutex
+	teamKey    string
+	comments   []string
+	created    []api.CreateTaskRequest
+	activeTask *api.TaskResponse
+}
+
+func (f *fakeLinear) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/graphql":
+		var req graphQLRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case strings.Contains(req.Query, "viewer"):
+			_, _ = w.Write([]byte(`{"data": {"viewer": {"id": "shepherd-user"}}}`))
+		case strings.Contains(req.Query, "commentCreate"):
+			input, _ := req.Variables["input"].(map[string]any)
+			body, _ := input["body"].(string)
+			f.comments = append(f.comments, body)
+			_, _ = w.Write([]byte(`{"data": {"commentCreate": {"success": true}}}`))
+		default:
+			teamKey := f.teamKey
+			if teamKey == "" {
+				teamKey = "ENG"
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"issue": map[string]any{
+				"id": "issue-uuid", "identifier": "ENG-7", "title": "Login broken", "description": "It 500s",
+				"url": testIssueURL, "team": map[string]any{"key": teamKey},
+				"comments": map[string]any{"nodes": []map[string]any{
+					{"body": "I can reproduce this", "createdAt": "2026-10-01T10:00:00.000Z", "user": map[string]any{"name": "Bob"}},
+				}},
+			}}})
+		}
+	case r.URL.Path == "/api/v1/tasks/active":
+		if f.activeTask == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.activeTask)
+	case r.URL.Path == "/api/v1/tasks" && r.Method == http.MethodPost:
+		var req api.CreateTaskRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.created = append(f.created, req)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(api.TaskResponse{ID: "task-abc"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// posted returns the comments posted so far.
+func (f *fakeLinear) posted() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.comments...)
+}
+
+func newTestWebhookHandler(t *testing.T, f *fakeLinear) (*WebhookHandler, *CallbackHandler) {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client := newClient(srv.Client(), srv.URL+"/graphql", "lin_api_test")
+	apiClient := NewAPIClient(srv.URL)
+	log := ctrl.Log.WithName("test")
+	cb := NewCallbackHandler("", client, apiClient, time.Minute, log)
+	triggers := Triggers{
+		Label:       "shepherd",
+		TeamRepos:   map[string]string{"ENG": "org/app"},
+		RepoBaseURL: "https://github.com",
+	}
+	return NewWebhookHandler("secret", client, apiClient, cb, "http://adapter/callback", triggers, "default",
+		time.Minute, log), cb
+}
+
+func TestWebhookHandler_SignatureVerification(t *testing.T) {
+	h, _ := newTestWebhookHandler(t, &fakeLinear{})
+	payload := map[string]any{"action": "create", "type": "Project"}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "secret", payload, time.Now()))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "wrong", payload, time.Now()))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "secret", payload, time.Now().Add(-5*time.Minute)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "stale timestamp")
+}
+
+func TestWebhookHandler_CommentCreatesTask(t *testing.T) {
+	tests := []struct {
+		name            string
+		comment         string
+		wantDescription string
+		wantRepoURL     string
+		wantRepoLabel   string
+	}{
+		{
+			name:            "team repository",
+			comment:         "@shepherd fix the login page",
+			wantDescription: "fix the login page",
+			wantRepoURL:     "https://github.com/org/app.git",
+			wantRepoLabel:   "org-app",
+		},
+		{
+			name:            "repository in the comment",
+			comment:         "@shepherd fix the login page in org/web.",
+			wantDescription: "fix the login page",
+			wantRepoURL:     "https://github.com/org/web.git",
+			wantRepoLabel:   "org-web",
+		},
+		{
+			name:            "not a repository",
+			comment:         "@shepherd fix the bug in production",
+			wantDescription: "fix the bug in production",
+			wantRepoURL:     "https://github.com/org/app.git",
+			wantRepoLabel:   "org-app",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeLinear{}
+			h, cb := newTestWebhookHandler(t, f)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, signedRequest(t, "secret", commentPayload(tt.comment, "alice-user"), time.Now()))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			require.Eventually(t, func() bool { return len(f.posted()) == 1 }, 5*time.Second, 10*time.Millisecond)
+			assert.Contains(t, f.posted()[0], "Task ID: task-abc")
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			require.Len(t, f.created, 1)
+			req := f.created[0]
+			assert.Equal(t, tt.wantRepoURL, req.Repo.URL)
+			assert.Equal(t, tt.wantDescription, req.Task.Description)
+			assert.Equal(t, testIssueURL, req.Task.SourceURL)
+			assert.Equal(t, "ENG-7", req.Task.SourceID)
+			assert.Contains(t, req.Task.Context, "# Login broken\n\nIt 500s")
+			assert.Contains(t, req.Task.Context, "**Bob** wrote:\n\nI can reproduce this")
+			assert.Equal(t, map[string]string{
+				"shepherd.io/repo":         tt.wantRepoLabel,
+				"shepherd.io/issue":        "ENG-7",
+				"shepherd.io/requested-by": "alice-user",
+			}, req.Labels)
+
+			cb.mu.RLock()
+			defer cb.mu.RUnlock()
+			assert.Equal(t, TaskMetadata{IssueID: "issue-uuid"}, cb.tasks["task-abc"])
+		})
+	}
+}
+
+func TestWebhookHandler_IgnoresComments(t *testing.T) {
+	tests := map[string]map[string]any{
+		"no mention":    commentPayload("looks good to me", "alice-user"),
+		"email address": commentPayload("contact user@shepherd.io", "alice-user"),
+		"own comment":   commentPayload("comment with @shepherd again", "shepherd-user"),
+	}
+	for name, payload := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &fakeLinear{}
+			h, _ := newTestWebhookHandler(t, f)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, signedRequest(t, "secret", payload, time.Now()))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Never(t, func() bool { return len(f.posted()) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+			assert.Empty(t, f.created)
+		})
+	}
+}
+
+func TestWebhookHandler_NoRepository(t *testing.T) {
+	f := &fakeLinear{teamKey: "OPS"}
+	h, _ := newTestWebhookHandler(t, f)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "secret", commentPayload("@shepherd rotate the keys", "alice-user"), time.Now()))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Eventually(t, func() bool { return len(f.posted()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, f.posted()[0], "map team OPS to a repository")
+	assert.Empty(t, f.created)
+}
+
+func TestWebhookHandler_TaskAlreadyRunning(t *testing.T) {
+	f := &fakeLinear{activeTask: &api.TaskResponse{ID: "task-old", Status: api.TaskStatusSummary{Phase: "Running"}}}
+	h, _ := newTestWebhookHandler(t, f)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "secret", commentPayload("@shepherd again", "alice-user"), time.Now()))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Eventually(t, func() bool { return len(f.posted()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, f.posted()[0], "Task ID: task-old")
+	assert.Empty(t, f.created)
+}
+
+func TestWebhookHandler_TriggerLabel(t *testing.T) {
+	issuePayload := func(action string, updatedFrom map[string]any) map[string]any {
+		payload := map[string]any{
+			"action": action,
+			"type":   "Issue",
+			"actor":  map[string]any{"id": "alice-user"},
+			"data": map[string]any{
+				"id":     "issue-uuid",
+				"labels": []map[string]any{{"id": "label-bug", "name": "bug"}, {"id": "label-shepherd", "name": "Shepherd"}},
+			},
+		}
+		if updatedFrom != nil {
+			payload["updatedFrom"] = updatedFrom
+		}
+		return payload
+	}
+	tests := []struct {
+		name    string
+		payload map[string]any
+		want    bool
+	}{
+		{name: "label added", payload: issuePayload("update", map[string]any{"labelIds": []string{"label-bug"}}), want: true},
+		{name: "created with label", payload: issuePayload("create", nil), want: true},
+		{name: "label already set", payload: issuePayload("update",
+			map[string]any{"labelIds": []string{"label-bug", "label-shepherd"}})},
+		{name: "labels unchanged", payload: issuePayload("update", map[string]any{"title": "Old title"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeLinear{}
+			h, _ := newTestWebhookHandler(t, f)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, signedRequest(t, "secret", tt.payload, time.Now()))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			if !tt.want {
+				assert.Never(t, func() bool { return len(f.posted()) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+				return
+			}
+			require.Eventually(t, func() bool { return len(f.posted()) == 1 }, 5*time.Second, 10*time.Millisecond)
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			require.Len(t, f.created, 1)
+			assert.Equal(t, "Work on this issue", f.created[0].Task.Description)
+			assert.Equal(t, "alice-user", f.created[0].Labels["shepherd.io/requested-by"])
+		})
+	}
+}
+
+func TestTriggers_Validate(t *testing.T) {
+	valid := Triggers{RepoBaseURL: "https://github.com", TeamRepos: map[string]string{
+		"ENG": "org/app",
+		"OPS": "https://gitea.example.com/org/ops.git",
+	}}
+	assert.NoError(t, valid.Validate())
+
+	invalid := Triggers{RepoBaseURL: "https://github.com", TeamRepos: map[string]string{"ENG": "app"}}
+	assert.ErrorContains(t, invalid.Validate(), `repository "app" of team ENG`)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/client"
+)
+
+// APIClient communicates with the Shepherd API.
+type APIClient struct {
+	api *client.Client
+}
+
+// NewAPIClient creates a new API client.
+func NewAPIClient(baseURL string) *APIClient {
+	return &APIClient{api: client.New(baseURL)}
+}
+
+// Ping checks that the API server is reachable and serving.
+func (c *APIClient) Ping(ctx context.Context) error {
+	err := c.api.Healthz(ctx)
+	if code := client.StatusCode(err); code != 0 {
+		return fmt.Errorf("API health check returned %d", code)
+	}
+	return err
+}
+
+// GetActiveTask returns the active task created for sourceURL, or nil if
+// there is none.
+func (c *APIClient) GetActiveTask(ctx context.Context, sourceURL string) (*api.TaskResponse, error) {
+	task, err := c.api.GetActiveTask(ctx, &client.GetActiveTaskParams{SourceURL: sourceURL})
+	if client.StatusCode(err) == http.StatusNotFound {
+		return nil, nil
+	}
+	return task, err
+}
+
+// GetTask fetches a single task by ID, to recover task metadata for
+// callbacks received after a restart.
+func (c *APIClient) GetTask(ctx context.Context, taskID string) (*api.TaskResponse, error) {
+	return c.api.GetTask(ctx, taskID, nil)
+}
+
+// CreateTask creates a new task via the API.
+func (c *APIClient) CreateTask(ctx context.Context, createReq api.CreateTaskRequest) (*api.TaskResponse, error) {
+	return c.api.CreateTask(ctx, nil, createReq)
+}
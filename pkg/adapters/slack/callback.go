@@ -0,0 +1,235 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// TaskMetadata stores the Slack thread a task's updates are posted to.
+type TaskMetadata struct {
+	Channel  string
+	ThreadTS string
+}
+
+// CallbackHandler handles callback notifications from the Shepherd API.
+type CallbackHandler struct {
+	secret       string
+	client       *Client
+	apiClient    *APIClient
+	eventTimeout time.Duration
+	log          logr.Logger
+
+	// secondarySecret is also accepted, so the secret can be rotated
+	// without rejecting callbacks signed with the other one.
+	secondarySecret string
+	// publicKeys verify Ed25519 signatures of callbacks; without them
+	// only HMAC signatures are accepted.
+	publicKeys []ed25519.PublicKey
+
+	// tasks holds the thread of each running task. Threads of tasks
+	// created before a restart are found again from the task's permalink.
+	mu    sync.RWMutex
+	tasks map[string]TaskMetadata
+}
+
+// CallbackOption configures optional CallbackHandler behavior.
+type CallbackOption func(*CallbackHandler)
+
+// WithSecondaryCallbackSecret also accepts callbacks signed with secret, for
+// rotating the callback secret.
+func WithSecondaryCallbackSecret(secret string) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.secondarySecret = secret
+	}
+}
+
+// WithCallbackPublicKeys also accepts callbacks with an Ed25519 signature
+// made with the private key of any of keys, so the adapter needs no shared
+// secret.
+func WithCallbackPublicKeys(keys ...ed25519.PublicKey) CallbackOption {
+	return func(h *CallbackHandler) {
+		h.publicKeys = keys
+	}
+}
+
+// NewCallbackHandler creates a new callback handler. Each callback is
+// handled within eventTimeout.
+func NewCallbackHandler(
+	secret string, client *Client, apiClient *APIClient, eventTimeout time.Duration, log logr.Logger,
+	opts ...CallbackOption,
+) *CallbackHandler {
+	h := &CallbackHandler{
+		secret:       secret,
+		client:       client,
+		apiClient:    apiClient,
+		eventTimeout: eventTimeout,
+		log:          log,
+		tasks:        make(map[string]TaskMetadata),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// RegisterTask stores metadata for a task so that callback notifications
+// can be routed back to the correct thread.
+func (h *CallbackHandler) RegisterTask(taskID string, meta TaskMetadata) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tasks[taskID] = meta
+}
+
+// ServeHTTP handles callback requests from the Shepherd API.
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Read body with 1MB limit
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		h.log.Error(err, "failed to read callback body")
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verifySignature(body, r.Header.Values("X-Shepherd-Signature")...) {
+		h.log.Info("callback signature verification failed")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var payload api.CallbackPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		h.log.Error(err, "failed to parse callback payload")
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	h.log.Info("received callback", logging.TaskID, payload.TaskID, logging.CorrelationID, payload.CorrelationID,
+		"event", payload.Event)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), h.eventTimeout)
+	defer cancel()
+	h.handleCallback(ctx, &payload)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySignature verifies the signatures from the API. The API sends one
+// header per secret and key; it passes if any of them was made with either
+// secret or with the key of a public key.
+func (h *CallbackHandler) verifySignature(body []byte, signatures ...string) bool {
+	v := api.CallbackVerifier{Secrets: []string{h.secret, h.secondarySecret}, PublicKeys: h.publicKeys}
+	return v.Verify(body, signatures...)
+}
+
+// resolveTaskMetadata returns the thread of a task. A task this adapter
+// does not know, because it restarted since creating the task, is looked
+// up in the API and its thread read from the permalink it was created
+// with.
+func (h *CallbackHandler) resolveTaskMetadata(ctx context.Context, taskID string) (TaskMetadata, bool) {
+	h.mu.RLock()
+	meta, ok := h.tasks[taskID]
+	h.mu.RUnlock()
+	if ok {
+		return meta, true
+	}
+
+	task, err := h.apiClient.GetTask(ctx, taskID)
+	if err != nil {
+		h.log.Error(err, "failed to fetch task from API for callback", logging.TaskID, taskID)
+		return TaskMetadata{}, false
+	}
+	meta, err = parsePermalink(task.Task.SourceURL)
+	if err != nil {
+		h.log.Error(err, "task's source URL is not a Slack thread", logging.TaskID, taskID, "sourceURL", task.Task.SourceURL)
+		return TaskMetadata{}, false
+	}
+
+	h.RegisterTask(taskID, meta)
+	h.log.Info("recovered task thread from API",
+		logging.TaskID, taskID, "channel", meta.Channel, "thread", meta.ThreadTS)
+	return meta, true
+}
+
+// handleCallback posts an update to the task's thread. Unlike the forge
+// adapters, it also relays intermediate events, since thread replies do
+// not notify the whole channel.
+func (h *CallbackHandler) handleCallback(ctx context.Context, payload *api.CallbackPayload) {
+	var msg string
+	terminal := true
+	switch payload.Event {
+	case api.EventCompleted:
+		prURL, _ := payload.Details["pr_url"].(string)
+		summary, _ := payload.Details["summary"].(string)
+		if prURL != "" {
+			msg = formatCompleted(prURL, summary)
+		} else {
+			msg = formatCompletedWithoutPR(summary)
+		}
+	case api.EventFailed:
+		msg = formatFailed(payload.Message)
+	case api.EventCancelled:
+		msg = formatCancelled(payload.Message)
+	case api.EventStarted:
+		msg, terminal = messageStarted, false
+	case api.EventProgress:
+		terminal = false
+		if warning, _ := payload.Details[api.TimeoutWarningDetail].(bool); warning {
+			msg = messageTimeoutWarning
+		} else if payload.Message != "" {
+			msg = formatProgress(payload.Message)
+		} else {
+			h.log.V(1).Info("ignoring progress event without a message", logging.TaskID, payload.TaskID)
+			return
+		}
+	default:
+		h.log.Info("unknown callback event type", "event", payload.Event)
+		return
+	}
+
+	meta, ok := h.resolveTaskMetadata(ctx, payload.TaskID)
+	if !ok {
+		h.log.Info("unable to find the task's thread, cannot post message", logging.TaskID, payload.TaskID)
+		return
+	}
+	if terminal {
+		h.mu.Lock()
+		delete(h.tasks, payload.TaskID)
+		h.mu.Unlock()
+		msg = withLinks(msg, payload.Links)
+	}
+
+	if _, err := h.client.PostMessage(ctx, meta.Channel, meta.ThreadTS, msg); err != nil {
+		h.log.Error(err, "failed to post callback message", logging.TaskID, payload.TaskID, "event", payload.Event)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func callbackRequest(t *testing.T, secret string, payload api.CallbackPayload) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(body))
+	req.Header.Set("X-Shepherd-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func newTestCallbackHandler(t *testing.T, handler http.Handler, opts ...CallbackOption) *CallbackHandler {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return NewCallbackHandler("secret", newClient(srv.Client(), srv.URL, "xoxb-test"), NewAPIClient(srv.URL),
+		time.Minute, ctrl.Log.WithName("test"), opts...)
+}
+
+func TestCallbackHandler_PostsToThread(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  api.CallbackPayload
+		want     string
+		terminal bool
+	}{
+		{
+			name:    "started",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventStarted},
+			want:    "The task has started.",
+		},
+		{
+			name:    "progress",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventProgress, Message: "Running tests <fast>"},
+			want:    "Progress: Running tests &lt;fast&gt;",
+		},
+		{
+			name: "timeout warning",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventProgress,
+				Details: map[string]any{api.TimeoutWarningDetail: true}},
+			want: "about to time out",
+		},
+		{
+			name: "completed with PR",
+			payload: api.CallbackPayload{TaskID: "task-1", Event: api.EventCompleted,
+				Details: map[string]any{"pr_url": "https://github.com/org/repo/pull/7", "summary": "Fixed the redirect."},
+				Links:   &api.TaskLinks{Dashboard: "https://shepherd.example.com/tasks/task-1"}},
+			want: "Shepherd has completed the task: <https://github.com/org/repo/pull/7|pull request>\n" +
+				"> Fixed the redirect.\n<https://shepherd.example.com/tasks/task-1|View task>",
+			terminal: true,
+		},
+		{
+			name:     "failed",
+			payload:  api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed, Message: "tests did not pass"},
+			want:     "Error: tests did not pass",
+			terminal: true,
+		},
+		{
+			name:     "cancelled",
+			payload:  api.CallbackPayload{TaskID: "task-1", Event: api.EventCancelled, Message: "cancelled by alice"},
+			want:     "cancelled by alice",
+			terminal: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeSlack{}
+			h := newTestCallbackHandler(t, f)
+			h.RegisterTask("task-1", TaskMetadata{Channel: "C456", ThreadTS: "1700000000.000100"})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, callbackRequest(t, "secret", tt.payload))
+			assert.Equal(t, http.StatusOK, w.Code)
+			require.Len(t, f.messages, 1)
+			assert.Equal(t, "C456", f.messages[0].Channel)
+			assert.Equal(t, "1700000000.000100", f.messages[0].ThreadTS)
+			assert.Contains(t, f.messages[0].Text, tt.want)
+			if tt.terminal {
+				assert.Empty(t, h.tasks, "metadata of a finished task is dropped")
+			} else {
+				assert.Len(t, h.tasks, 1)
+			}
+		})
+	}
+}
+
+func TestCallbackHandler_IgnoresEmptyProgress(t *testing.T) {
+	f := &fakeSlack{}
+	h := newTestCallbackHandler(t, f)
+	h.RegisterTask("task-1", TaskMetadata{Channel: "C456", ThreadTS: "1700000000.000100"})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "secret", api.CallbackPayload{TaskID: "task-1", Event: api.EventProgress}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, f.messages)
+}
+
+func TestCallbackHandler_InvalidSignature(t *testing.T) {
+	f := &fakeSlack{}
+	h := newTestCallbackHandler(t, f)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "wrong", api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, f.messages)
+}
+
+func TestCallbackHandler_VerifiesRotatedSecretsAndKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	f := &fakeSlack{}
+	h := newTestCallbackHandler(t, f, WithSecondaryCallbackSecret("new-secret"), WithCallbackPublicKeys(pub))
+	h.RegisterTask("task-1", TaskMetadata{Channel: "C456", ThreadTS: "1700000000.000100"})
+	payload := api.CallbackPayload{TaskID: "task-1", Event: api.EventProgress}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "new-secret", payload))
+	assert.Equal(t, http.StatusOK, w.Code, "secondary secret")
+
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader(body))
+	req.Header.Add("X-Shepherd-Signature", "sha256=unknown")
+	req.Header.Add("X-Shepherd-Signature", api.SignCallbackEd25519(priv, body))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "public key")
+}
+
+func TestCallbackHandler_RecoversMetadataFromAPI(t *testing.T) {
+	f := &fakeSlack{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/tasks/task-1", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.TaskResponse{
+			ID:   "task-1",
+			Task: api.TaskRequest{SourceURL: "https://slack.com/archives/C456/p1700000000000100"},
+		})
+	})
+	mux.Handle("/", f)
+	h := newTestCallbackHandler(t, mux)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, callbackRequest(t, "secret", api.CallbackPayload{TaskID: "task-1", Event: api.EventFailed}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, f.messages, 1)
+	assert.Equal(t, message{Channel: "C456", ThreadTS: "1700000000.000100",
+		Text: "Shepherd was unable to complete the task.\nError: Unknown error"}, f.messages[0])
+}
+
+func TestParsePermalink(t *testing.T) {
+	tests := []struct {
+		name      string
+		sourceURL string
+		want      TaskMetadata
+		wantErr   bool
+	}{
+		{name: "thread", sourceURL: permalink("C456", "1700000000.000100"),
+			want: TaskMetadata{Channel: "C456", ThreadTS: "1700000000.000100"}},
+		{name: "empty", wantErr: true},
+		{name: "other host", sourceURL: "https://example.com/archives/C456/p1700000000000100", wantErr: true},
+		{name: "not a message", sourceURL: "https://slack.com/archives/C456", wantErr: true},
+		{name: "bad timestamp", sourceURL: "https://slack.com/archives/C456/pabc", wantErr: true},
+		{name: "short timestamp", sourceURL: "https://slack.com/archives/C456/p123", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePermalink(tt.sourceURL)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
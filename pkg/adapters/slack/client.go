@@ -0,0 +1,150 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package slack implements the Shepherd adapter for Slack. A slash command
+// or a mention of the Shepherd app creates a task, and the adapter follows
+// it in a message thread until it links the resulting pull request.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+)
+
+// defaultBaseURL is the root of the Slack Web API.
+const defaultBaseURL = "https://slack.com/api"
+
+// Client talks to the Slack Web API with a bot token.
+type Client struct {
+	http    *http.Client
+	baseURL string // Web API root without a trailing slash
+	token   string
+}
+
+// NewClient creates a client authenticated with the bot token, which
+// starts with "xoxb-".
+func NewClient(token string) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("slack bot token is required")
+	}
+	return newClient(&http.Client{Transport: tracing.Transport(http.DefaultTransport)}, defaultBaseURL, token), nil
+}
+
+func newClient(httpClient *http.Client, baseURL, token string) *Client {
+	return &Client{
+		http:    httpClient,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}
+}
+
+// CheckCredentials verifies that the bot token is accepted by Slack.
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	if err := c.call(ctx, "auth.test", struct{}{}, nil); err != nil {
+		return fmt.Errorf("testing authentication: %w", err)
+	}
+	return nil
+}
+
+// PostMessage posts text to channel, as a reply in the thread of threadTS
+// unless it is empty, and returns the timestamp of the new message.
+func (c *Client) PostMessage(ctx context.Context, channel, threadTS, text string) (string, error) {
+	req := map[string]string{"channel": channel, "text": text}
+	if threadTS != "" {
+		req["thread_ts"] = threadTS
+	}
+	var resp struct {
+		TS string `json:"ts"`
+	}
+	if err := c.call(ctx, "chat.postMessage", req, &resp); err != nil {
+		return "", fmt.Errorf("posting message: %w", err)
+	}
+	return resp.TS, nil
+}
+
+// Respond sends text to the response URL of a slash command. Only the
+// user who ran the command sees it.
+func (c *Client) Respond(ctx context.Context, responseURL, text string) error {
+	body, err := json.Marshal(map[string]string{"response_type": "ephemeral", "text": text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("responding to command: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("responding to command: response URL returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// call invokes a Web API method and decodes the response into out, unless
+// out is nil. Slack reports most failures with a 200 response whose "ok"
+// is false.
+func (c *Client) call(ctx context.Context, method string, in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+method, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %d: %s", method, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var status struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return fmt.Errorf("decoding %s response: %w", method, err)
+	}
+	if !status.OK {
+		return fmt.Errorf("%s failed: %s", method, status.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
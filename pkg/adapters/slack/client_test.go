@@ -0,0 +1,89 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient creates a Client backed by a test HTTP server.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return newClient(srv.Client(), srv.URL+"/", "xoxb-test")
+}
+
+func TestClient_PostMessage(t *testing.T) {
+	var received map[string]string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat.postMessage", r.URL.Path)
+		assert.Equal(t, "Bearer xoxb-test", r.Header.Get("Authorization"))
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte(`{"ok": true, "ts": "1700000000.000200"}`))
+	}))
+
+	ts, err := client.PostMessage(context.Background(), "C123", "1700000000.000100", "Hello from Shepherd")
+	require.NoError(t, err)
+	assert.Equal(t, "1700000000.000200", ts)
+	assert.Equal(t, map[string]string{
+		"channel":   "C123",
+		"thread_ts": "1700000000.000100",
+		"text":      "Hello from Shepherd",
+	}, received)
+}
+
+func TestClient_PostMessage_Error(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": false, "error": "not_in_channel"}`))
+	}))
+
+	_, err := client.PostMessage(context.Background(), "C123", "", "body")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not_in_channel")
+
+	client = newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	_, err = client.PostMessage(context.Background(), "C123", "", "body")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "429")
+}
+
+func TestClient_Respond(t *testing.T) {
+	var received map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	t.Cleanup(srv.Close)
+	client := newClient(srv.Client(), "https://slack.invalid/api", "xoxb-test")
+
+	require.NoError(t, client.Respond(context.Background(), srv.URL+"/commands/T1/2", "Not in this channel"))
+	assert.Equal(t, map[string]string{"response_type": "ephemeral", "text": "Not in this channel"}, received)
+}
+
+func TestNewClient_Validation(t *testing.T) {
+	_, err := NewClient("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// eventEnvelope is the part of an Events API request the adapter uses.
+type eventEnvelope struct {
+	// Type is "url_verification" when the events URL is configured, and
+	// "event_callback" for events.
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Event     struct {
+		Type     string `json:"type"`
+		User     string `json:"user"`
+		Text     string `json:"text"`
+		Channel  string `json:"channel"`
+		TS       string `json:"ts"`
+		ThreadTS string `json:"thread_ts"`
+	} `json:"event"`
+}
+
+// Handler handles the slash commands and events Slack sends the adapter.
+// Slack expects an answer within three seconds, so tasks are created after
+// the response is sent.
+type Handler struct {
+	signingSecret          string
+	client                 *Client
+	apiClient              *APIClient
+	callbackHandler        *CallbackHandler
+	callbackURL            string
+	repoBaseURL            string
+	defaultSandboxTemplate string
+	eventTimeout           time.Duration
+	log                    logr.Logger
+	now                    func() time.Time
+}
+
+// NewHandler creates a new handler. Repositories given as owner/repo are
+// looked up on the forge at repoBaseURL, and each request is handled
+// within eventTimeout.
+func NewHandler(
+	signingSecret string,
+	client *Client,
+	apiClient *APIClient,
+	callbackHandler *CallbackHandler,
+	callbackURL string,
+	repoBaseURL string,
+	defaultSandboxTemplate string,
+	eventTimeout time.Duration,
+	log logr.Logger,
+) *Handler {
+	return &Handler{
+		signingSecret:          signingSecret,
+		client:                 client,
+		apiClient:              apiClient,
+		callbackHandler:        callbackHandler,
+		callbackURL:            callbackURL,
+		repoBaseURL:            repoBaseURL,
+		defaultSandboxTemplate: defaultSandboxTemplate,
+		eventTimeout:           eventTimeout,
+		log:                    log,
+		now:                    time.Now,
+	}
+}
+
+// readSigned reads the body of a request and verifies Slack's signature,
+// answering the request itself if either fails.
+func (h *Handler) readSigned(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		h.log.Error(err, "failed to read request body")
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return nil, false
+	}
+	if !verifySignature(h.signingSecret, r.Header, body, h.now()) {
+		h.log.Info("slack signature verification failed")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return nil, false
+	}
+	return body, true
+}
+
+// background runs fn after the response is sent, within eventTimeout.
+func (h *Handler) background(r *http.Request, fn func(ctx context.Context)) {
+	ctx := context.WithoutCancel(r.Context())
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, h.eventTimeout)
+		defer cancel()
+		fn(ctx)
+	}()
+}
+
+// ServeCommand handles a slash command, such as
+// "/shepherd fix the login bug in org/repo". The request is posted to the
+// channel, and the task is followed in the thread of that message.
+func (h *Handler) ServeCommand(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.readSigned(w, r)
+	if !ok {
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+
+	userID, channel := form.Get("user_id"), form.Get("channel_id")
+	req, err := parseRequest(form.Get("text"), h.repoBaseURL)
+	if err != nil {
+		h.log.V(1).Info("ignoring invalid command", "error", err.Error())
+		writeEphemeral(w, "Shepherd could not read that request: "+escape(err.Error())+". "+usage)
+		return
+	}
+
+	h.log.Info("processing slash command", "repo", req.RepoName, "user", userID, "channel", channel)
+	responseURL := form.Get("response_url")
+	h.background(r, func(ctx context.Context) {
+		threadTS, err := h.client.PostMessage(ctx, channel, "", formatRequest(userID, req.RepoName, req.Description))
+		if err != nil {
+			h.log.Error(err, "failed to post request message", "channel", channel)
+			if err := h.client.Respond(ctx, responseURL,
+				"Shepherd could not post to this channel. Invite the Shepherd app to it and try again."); err != nil {
+				h.log.Error(err, "failed to respond to command")
+			}
+			return
+		}
+		h.startTask(ctx, req, userID, TaskMetadata{Channel: channel, ThreadTS: threadTS})
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeEphemeral answers a slash command with a message only the user who
+// ran it sees.
+func writeEphemeral(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"response_type": "ephemeral", "text": text})
+}
+
+// ServeEvents handles Events API requests. A mention of the app creates a
+// task, followed in the mention's thread.
+func (h *Handler) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.readSigned(w, r)
+	if !ok {
+		return
+	}
+	var envelope eventEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		h.log.Error(err, "failed to parse event")
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case envelope.Type == "url_verification":
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(envelope.Challenge))
+		return
+	case r.Header.Get("X-Slack-Retry-Num") != "":
+		// The first delivery was answered and is handled in the background.
+		h.log.V(1).Info("ignoring event retry", "reason", r.Header.Get("X-Slack-Retry-Reason"))
+	case envelope.Type == "event_callback" && envelope.Event.Type == "app_mention":
+		h.handleMention(w, r, &envelope)
+		return
+	default:
+		h.log.V(1).Info("ignoring event", "type", envelope.Type, "event", envelope.Event.Type)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) handleMention(w http.ResponseWriter, r *http.Request, envelope *eventEnvelope) {
+	event := envelope.Event
+	meta := TaskMetadata{Channel: event.Channel, ThreadTS: event.ThreadTS}
+	if meta.ThreadTS == "" {
+		meta.ThreadTS = event.TS
+	}
+
+	req, err := parseRequest(event.Text, h.repoBaseURL)
+	h.background(r, func(ctx context.Context) {
+		if err != nil {
+			h.log.V(1).Info("ignoring invalid mention", "error", err.Error())
+			h.postMessage(ctx, meta, "Shepherd could not read that request: "+escape(err.Error())+". "+usage)
+			return
+		}
+		h.log.Info("processing app mention", "repo", req.RepoName, "user", event.User, "channel", event.Channel)
+
+		// One task at a time per thread
+		task, err := h.apiClient.GetActiveTask(ctx, permalink(meta.Channel, meta.ThreadTS))
+		if err != nil {
+			h.log.Error(err, "failed to check for active tasks")
+			// Continue anyway - better to potentially create duplicate than fail silently
+		}
+		if task != nil {
+			h.log.Info("task already running", logging.TaskID, task.ID, "status", task.Status.Phase)
+			h.postMessage(ctx, meta, formatAlreadyRunning(task.ID, task.Status.Phase))
+			return
+		}
+		h.startTask(ctx, req, event.User, meta)
+	})
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// startTask creates the task and follows it in the thread of meta.
+func (h *Handler) startTask(ctx context.Context, req taskRequest, userID string, meta TaskMetadata) {
+	createReq := api.CreateTaskRequest{
+		Repo: api.RepoRequest{URL: req.RepoURL},
+		Task: api.TaskRequest{
+			Description: req.Description,
+			SourceURL:   permalink(meta.Channel, meta.ThreadTS),
+		},
+		Callback: h.callbackURL,
+		Runner: &api.RunnerConfig{
+			SandboxTemplateName: h.defaultSandboxTemplate,
+		},
+		Labels: map[string]string{
+			"shepherd.io/repo":         strings.ReplaceAll(req.RepoName, "/", "-"),
+			"shepherd.io/requested-by": userID,
+		},
+	}
+
+	taskResp, err := h.apiClient.CreateTask(ctx, createReq)
+	if err != nil {
+		h.log.Error(err, "failed to create task")
+		h.postMessage(ctx, meta, formatFailed("Failed to create task"))
+		return
+	}
+
+	h.log.Info("created task", logging.TaskID, taskResp.ID, logging.CorrelationID, taskResp.CorrelationID)
+
+	h.callbackHandler.RegisterTask(taskResp.ID, meta)
+	h.postMessage(ctx, meta, formatAcknowledge(taskResp.ID))
+}
+
+func (h *Handler) postMessage(ctx context.Context, meta TaskMetadata, text string) {
+	if _, err := h.client.PostMessage(ctx, meta.Channel, meta.ThreadTS, text); err != nil {
+		h.log.Error(err, "failed to post message", "channel", meta.Channel, "thread", meta.ThreadTS)
+	}
+}
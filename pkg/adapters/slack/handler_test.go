@@ -0,0 +1,277 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func signedRequest(t *testing.T, secret, path string, body []byte, at time.Time) *http.Request {
+	t.Helper()
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+// message is a message posted through the fake Slack API.
+type message struct {
+	Channel  string `json:"channel"`
+	ThreadTS string `json:"thread_ts"`
+	Text     string `json:"text"`
+}
+
+// fakeSlack serves the Slack chat.postMessage method and the Shepherd API
+// tasks endpoints, recording what was posted.
+type fakeSlack struct {
+	mu         sync.Mutex
+	messages   []message
+	created    []api.CreateTaskRequest
+	activeTask *api.TaskResponse
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/chat.postMessage":
+		var msg message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		f.messages = append(f.messages, msg)
+		_, _ = w.Write([]byte(`{"ok": true, "ts": "1700000000.000100"}`))
+	case r.URL.Path == "/api/v1/tasks/active":
+		if f.activeTask == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.activeTask)
+	case r.URL.Path == "/api/v1/tasks" && r.Method == http.MethodPost:
+		var req api.CreateTaskRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.created = append(f.created, req)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(api.TaskResponse{ID: "task-abc"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// posted returns the messages posted so far.
+func (f *fakeSlack) posted() []message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]message(nil), f.messages...)
+}
+
+func newTestHandler(t *testing.T, f *fakeSlack) (*Handler, *CallbackHandler) {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	client := newClient(srv.Client(), srv.URL, "xoxb-test")
+	apiClient := NewAPIClient(srv.URL)
+	log := ctrl.Log.WithName("test")
+	cb := NewCallbackHandler("", client, apiClient, time.Minute, log)
+	return NewHandler("secret", client, apiClient, cb, "http://adapter/callback", "https://github.com",
+		"default", time.Minute, log), cb
+}
+
+func TestHandler_SignatureVerification(t *testing.T) {
+	h, _ := newTestHandler(t, &fakeSlack{})
+	body := []byte(`{"type":"url_verification","challenge":"abc"}`)
+
+	w := httptest.NewRecorder()
+	h.ServeEvents(w, signedRequest(t, "secret", "/slack/events", body, time.Now()))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abc", w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeEvents(w, signedRequest(t, "wrong", "/slack/events", body, time.Now()))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeEvents(w, signedRequest(t, "secret", "/slack/events", body, time.Now().Add(-10*time.Minute)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "stale timestamp")
+}
+
+func TestHandler_SlashCommand(t *testing.T) {
+	f := &fakeSlack{}
+	h, cb := newTestHandler(t, f)
+	form := url.Values{
+		"command":      {"/shepherd"},
+		"text":         {"fix the login bug in org/repo"},
+		"user_id":      {"U123"},
+		"channel_id":   {"C456"},
+		"response_url": {"https://hooks.slack.invalid/commands/1"},
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeCommand(w, signedRequest(t, "secret", "/slack/commands", []byte(form.Encode()), time.Now()))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Eventually(t, func() bool { return len(f.posted()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	messages := f.posted()
+	assert.Equal(t, message{Channel: "C456", Text: "<@U123> asked Shepherd to work on org/repo:\n> fix the login bug"},
+		messages[0], "request posted to the channel")
+	assert.Equal(t, message{Channel: "C456", ThreadTS: "1700000000.000100", Text: "Shepherd is working on it. Task ID: `task-abc`"},
+		messages[1], "acknowledged in the request's thread")
+
+	require.Len(t, f.created, 1)
+	req := f.created[0]
+	assert.Equal(t, "https://github.com/org/repo.git", req.Repo.URL)
+	assert.Equal(t, "fix the login bug", req.Task.Description)
+	assert.Equal(t, "https://slack.com/archives/C456/p1700000000000100", req.Task.SourceURL)
+	assert.Equal(t, "http://adapter/callback", req.Callback)
+	assert.Equal(t, map[string]string{"shepherd.io/repo": "org-repo", "shepherd.io/requested-by": "U123"}, req.Labels)
+
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	assert.Equal(t, TaskMetadata{Channel: "C456", ThreadTS: "1700000000.000100"}, cb.tasks["task-abc"])
+}
+
+func TestHandler_SlashCommandWithoutRepository(t *testing.T) {
+	f := &fakeSlack{}
+	h, _ := newTestHandler(t, f)
+	form := url.Values{"text": {"fix the login bug"}, "user_id": {"U123"}, "channel_id": {"C456"}}
+
+	w := httptest.NewRecorder()
+	h.ServeCommand(w, signedRequest(t, "secret", "/slack/commands", []byte(form.Encode()), time.Now()))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp map[string]string
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "ephemeral", resp["response_type"])
+	assert.Contains(t, resp["text"], "no repository given")
+	assert.Empty(t, f.posted())
+}
+
+func mentionBody(t *testing.T, text, threadTS string) []byte {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"type": "event_callback",
+		"event": map[string]any{
+			"type":      "app_mention",
+			"user":      "U123",
+			"text":      text,
+			"channel":   "C456",
+			"ts":        "1700000000.000300",
+			"thread_ts": threadTS,
+		},
+	})
+	require.NoError(t, err)
+	return body
+}
+
+func TestHandler_AppMention(t *testing.T) {
+	f := &fakeSlack{}
+	h, _ := newTestHandler(t, f)
+
+	body := mentionBody(t, "<@U0SHEPHERD> fix the login bug in <https://gitea.example.com/org/repo>", "1700000000.000050")
+	w := httptest.NewRecorder()
+	h.ServeEvents(w, signedRequest(t, "secret", "/slack/events", body, time.Now()))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Eventually(t, func() bool { return len(f.posted()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "1700000000.000050", f.posted()[0].ThreadTS, "replies in the mention's thread")
+
+	require.Len(t, f.created, 1)
+	assert.Equal(t, "https://gitea.example.com/org/repo.git", f.created[0].Repo.URL)
+	assert.Equal(t, "https://slack.com/archives/C456/p1700000000000050", f.created[0].Task.SourceURL)
+}
+
+func TestHandler_AppMentionTaskAlreadyRunning(t *testing.T) {
+	f := &fakeSlack{activeTask: &api.TaskResponse{ID: "task-old", Status: api.TaskStatusSummary{Phase: "Running"}}}
+	h, _ := newTestHandler(t, f)
+
+	w := httptest.NewRecorder()
+	h.ServeEvents(w, signedRequest(t, "secret", "/slack/events", mentionBody(t, "<@U0SHEPHERD> again in org/repo", ""), time.Now()))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Eventually(t, func() bool { return len(f.posted()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	msg := f.posted()[0]
+	assert.Equal(t, "1700000000.000300", msg.ThreadTS, "a top-level mention starts a thread")
+	assert.Contains(t, msg.Text, "task-old")
+	assert.Empty(t, f.created)
+}
+
+func TestHandler_IgnoresEventRetries(t *testing.T) {
+	f := &fakeSlack{}
+	h, _ := newTestHandler(t, f)
+
+	req := signedRequest(t, "secret", "/slack/events", mentionBody(t, "<@U0SHEPHERD> fix it in org/repo", ""), time.Now())
+	req.Header.Set("X-Slack-Retry-Num", "1")
+	w := httptest.NewRecorder()
+	h.ServeEvents(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, f.posted())
+}
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    taskRequest
+		wantErr bool
+	}{
+		{name: "owner/repo", text: "fix the login bug in org/repo",
+			want: taskRequest{Description: "fix the login bug", RepoURL: "https://github.com/org/repo.git", RepoName: "org/repo"}},
+		{name: "mention and multiple lines", text: "<@U0SHEPHERD> fix the login bug\nand add a test in org/repo.git ",
+			want: taskRequest{Description: "fix the login bug\nand add a test", RepoURL: "https://github.com/org/repo.git", RepoName: "org/repo"}},
+		{name: "URL with label", text: "update docs in <https://gitea.example.com/org/repo|org/repo>",
+			want: taskRequest{Description: "update docs", RepoURL: "https://gitea.example.com/org/repo.git", RepoName: "org/repo"}},
+		{name: "no repository", text: "fix the login bug", wantErr: true},
+		{name: "not a repository", text: "fix it in production", wantErr: true},
+		{name: "http URL", text: "fix it in http://example.com/org/repo", wantErr: true},
+		{name: "URL without repository", text: "fix it in https://example.com/org", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRequest(tt.text, "https://github.com/")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// usage explains the grammar of a request.
+const usage = "Tell me what to do and in which repository, e.g. `/shepherd fix the login bug in org/repo`."
+
+// Message templates in Slack's mrkdwn. Text that comes from users or the
+// runner goes through escape first.
+const (
+	messageRequest = "<@%s> asked Shepherd to work on %s:\n%s"
+
+	messageAcknowledge = "Shepherd is working on it. Task ID: `%s`"
+
+	messageAlreadyRunning = "A Shepherd task is already running in this thread. Task ID: `%s`, status: %s"
+
+	messageStarted = "The task has started."
+
+	messageCompleted = "Shepherd has completed the task: <%s|pull request>"
+
+	messageFailed = "Shepherd was unable to complete the task.\nError: %s"
+
+	messageCancelled = "The Shepherd task was cancelled.\n%s"
+
+	messageTimeoutWarning = "The task is about to time out. Work that is not pushed by then will be lost."
+)
+
+// escape replaces the characters Slack treats as control characters in
+// message text.
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// quote renders s as a block quote.
+func quote(s string) string {
+	return "> " + strings.ReplaceAll(escape(strings.TrimSpace(s)), "\n", "\n> ")
+}
+
+func formatRequest(userID, repo, description string) string {
+	return fmt.Sprintf(messageRequest, userID, escape(repo), quote(description))
+}
+
+func formatAcknowledge(taskID string) string {
+	return fmt.Sprintf(messageAcknowledge, taskID)
+}
+
+func formatAlreadyRunning(taskID, status string) string {
+	return fmt.Sprintf(messageAlreadyRunning, taskID, status)
+}
+
+// formatProgress relays a progress update of the runner.
+func formatProgress(message string) string {
+	return "Progress: " + escape(message)
+}
+
+// formatCompleted links the PR of a completed task, with the runner's
+// summary of the change if it sent one.
+func formatCompleted(prURL, summary string) string {
+	msg := fmt.Sprintf(messageCompleted, prURL)
+	if summary != "" {
+		msg += "\n" + quote(summary)
+	}
+	return msg
+}
+
+// formatCompletedWithoutPR announces a task that completed without a PR.
+func formatCompletedWithoutPR(summary string) string {
+	if summary == "" {
+		return "Shepherd completed the task successfully."
+	}
+	return "Shepherd completed the task successfully.\n" + quote(summary)
+}
+
+func formatFailed(errorMsg string) string {
+	if errorMsg == "" {
+		errorMsg = "Unknown error"
+	}
+	return fmt.Sprintf(messageFailed, escape(errorMsg))
+}
+
+func formatCancelled(reason string) string {
+	if reason == "" {
+		reason = "No reason given"
+	}
+	return fmt.Sprintf(messageCancelled, escape(reason))
+}
+
+// withLinks appends the links the API server sent with a callback to msg.
+func withLinks(msg string, links *api.TaskLinks) string {
+	if links == nil {
+		return msg
+	}
+	var parts []string
+	if links.Dashboard != "" {
+		parts = append(parts, fmt.Sprintf("<%s|View task>", links.Dashboard))
+	}
+	if links.Logs != "" {
+		parts = append(parts, fmt.Sprintf("<%s|Logs>", links.Logs))
+	}
+	if len(parts) == 0 {
+		return msg
+	}
+	return msg + "\n" + strings.Join(parts, " · ")
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRequestAge is how far the timestamp of a signed request may be from
+// now. Older requests are rejected as possible replays.
+const maxRequestAge = 5 * time.Minute
+
+var (
+	// requestRegex splits a request into what to do and the repository,
+	// given last after "in".
+	requestRegex = regexp.MustCompile(`(?is)^(.+?)\s+in\s+(\S+)\s*$`)
+	// repoNameRegex matches an owner/repo repository name.
+	repoNameRegex = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+	// mentionRegex matches user mentions, such as the app mention that
+	// starts the text of an app_mention event.
+	mentionRegex = regexp.MustCompile(`<@[A-Z0-9]+(?:\|[^>]*)?>`)
+)
+
+// taskRequest is a request for a task, parsed from a slash command or an
+// app mention.
+type taskRequest struct {
+	Description string
+	RepoURL     string
+	RepoName    string // owner/repo
+}
+
+// parseRequest parses "<what to do> in <repository>", where the repository
+// is owner/repo on the forge at repoBaseURL, or the URL of a repository.
+func parseRequest(text, repoBaseURL string) (taskRequest, error) {
+	text = strings.TrimSpace(mentionRegex.ReplaceAllString(text, ""))
+	m := requestRegex.FindStringSubmatch(text)
+	if m == nil {
+		return taskRequest{}, fmt.Errorf("no repository given")
+	}
+	description := strings.TrimSpace(m[1])
+
+	// Slack sends URLs as <url> or <url|text>.
+	repo := strings.TrimSuffix(strings.TrimPrefix(m[2], "<"), ">")
+	repo, _, _ = strings.Cut(repo, "|")
+
+	if repoNameRegex.MatchString(repo) {
+		name := strings.TrimSuffix(repo, ".git")
+		return taskRequest{
+			Description: description,
+			RepoURL:     strings.TrimSuffix(repoBaseURL, "/") + "/" + name + ".git",
+			RepoName:    name,
+		}, nil
+	}
+
+	u, err := url.Parse(repo)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return taskRequest{}, fmt.Errorf("%q is neither owner/repo nor an https URL", repo)
+	}
+	name := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if !repoNameRegex.MatchString(name) {
+		return taskRequest{}, fmt.Errorf("%q is not a repository URL", repo)
+	}
+	return taskRequest{
+		Description: description,
+		RepoURL:     "https://" + u.Host + "/" + name + ".git",
+		RepoName:    name,
+	}, nil
+}
+
+// verifySignature checks Slack's signature of a request: the hex-encoded
+// HMAC-SHA256 of "v0:<timestamp>:<body>" with the app's signing secret,
+// prefixed with "v0=".
+func verifySignature(secret string, header http.Header, body []byte, now time.Time) bool {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(sec, 0)); age > maxRequestAge || age < -maxRequestAge {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature")))
+}
+
+// permalink returns the URL of the message ts in channel, which is the
+// source URL of the task started in its thread. It has no workspace in
+// its host; Slack redirects it to the user's workspace.
+func permalink(channel, ts string) string {
+	return "https://slack.com/archives/" + channel + "/p" + strings.Replace(ts, ".", "", 1)
+}
+
+// parsePermalink extracts the channel and thread timestamp from a URL
+// returned by permalink.
+func parsePermalink(sourceURL string) (TaskMetadata, error) {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return TaskMetadata{}, fmt.Errorf("invalid sourceURL: %w", err)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if u.Host != "slack.com" || len(parts) != 3 || parts[0] != "archives" || !strings.HasPrefix(parts[2], "p") {
+		return TaskMetadata{}, fmt.Errorf("unexpected sourceURL format: %s", sourceURL)
+	}
+	// Timestamps have six digits after the dot.
+	digits := strings.TrimPrefix(parts[2], "p")
+	if len(digits) <= 6 {
+		return TaskMetadata{}, fmt.Errorf("invalid message timestamp in sourceURL: %s", sourceURL)
+	}
+	if _, err := strconv.ParseUint(digits, 10, 64); err != nil {
+		return TaskMetadata{}, fmt.Errorf("invalid message timestamp in sourceURL: %w", err)
+	}
+	return TaskMetadata{
+		Channel:  parts[1],
+		ThreadTS: digits[:len(digits)-6] + "." + digits[len(digits)-6:],
+	}, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"k8s.io/apimachinery/pkg/util/validation"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/archive"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// taskArchiver writes a record of every finished task to the archive. The
+// record is written before the Notified condition is set, and the
+// operator's finalizer waits for that condition, so a finished task is
+// archived before it can be deleted.
+type taskArchiver struct {
+	store    archive.Archiver
+	eventHub EventStore // Events are only buffered for a few minutes after a task finished
+	now      func() time.Time
+}
+
+// archiveKey returns the object key of a task's archived record.
+func archiveKey(namespace, taskID string) string {
+	return "tasks/" + namespace + "/" + taskID + ".json"
+}
+
+// archive writes the record of a finished task. A nil archiver does nothing.
+func (a *taskArchiver) archive(ctx context.Context, task *toolkitv1alpha1.AgentTask) error {
+	if a == nil {
+		return nil
+	}
+
+	obj := task.DeepCopy()
+	obj.APIVersion = toolkitv1alpha1.GroupVersion.String()
+	obj.Kind = "AgentTask"
+	obj.ManagedFields = nil
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("encoding task: %w", err)
+	}
+
+	record := ArchivedTask{
+		ArchivedAt: a.now().UTC().Format(time.RFC3339),
+		Task:       taskToResponse(task),
+		Events:     []TaskEvent{},
+		Object:     raw,
+	}
+	if a.eventHub != nil {
+		record.Events = append(record.Events, a.eventHub.Events(task.Name)...)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encoding archive record: %w", err)
+	}
+	return a.store.Put(ctx, archiveKey(task.Namespace, task.Name), data)
+}
+
+// getArchivedTask handles GET /api/v1/archive/tasks/{taskID}.
+func (h *taskHandler) getArchivedTask(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	taskID := chi.URLParam(r, "taskID")
+
+	if h.archiver == nil {
+		writeError(w, http.StatusServiceUnavailable, "archive not configured", "")
+		return
+	}
+	if len(validation.IsDNS1123Subdomain(taskID)) > 0 {
+		writeError(w, http.StatusNotFound, "archived task not found", "")
+		return
+	}
+
+	data, err := h.archiver.store.Get(r.Context(), archiveKey(h.namespace, taskID))
+	if errors.Is(err, archive.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "archived task not found", "")
+		return
+	}
+	if err != nil {
+		log.Error(err, "failed to read archive", logging.TaskID, taskID)
+		writeError(w, http.StatusBadGateway, "failed to read archive", "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
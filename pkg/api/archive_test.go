@@ -0,0 +1,202 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/archive"
+)
+
+// memArchive is an in-memory archive.Archiver.
+type memArchive struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	err     error
+}
+
+func newMemArchive() *memArchive {
+	return &memArchive{objects: map[string][]byte{}}
+}
+
+func (m *memArchive) Put(_ context.Context, key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.objects[key] = data
+	return nil
+}
+
+func (m *memArchive) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, m.err
+	}
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, archive.ErrNotFound
+	}
+	return data, nil
+}
+
+func withArchive(h *taskHandler, store archive.Archiver) {
+	h.archiver = &taskArchiver{
+		store:    store,
+		eventHub: h.eventHub,
+		now:      func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) },
+	}
+}
+
+func archiveRouter(h *taskHandler) *chi.Mux {
+	r := testRouter(h)
+	r.Get("/api/v1/archive/tasks/{taskID}", h.getArchivedTask)
+	return r
+}
+
+func TestArchive_WritesRecord(t *testing.T) {
+	task := newTask("task-abc", nil, []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonSucceeded,
+	}})
+	h := newTestHandler()
+	task.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "shepherd"}}
+	store := newMemArchive()
+	withArchive(h, store)
+	h.eventHub.Publish("task-abc", []TaskEvent{{Type: EventTypeThinking, Summary: "reading the code"}})
+
+	require.NoError(t, h.archiver.archive(context.Background(), task))
+
+	data, ok := store.objects["tasks/default/task-abc.json"]
+	require.True(t, ok, "record written under the task's key")
+	var record ArchivedTask
+	require.NoError(t, json.Unmarshal(data, &record))
+	assert.Equal(t, "2026-03-01T12:00:00Z", record.ArchivedAt)
+	assert.Equal(t, "task-abc", record.Task.ID)
+	require.Len(t, record.Events, 1)
+	assert.Equal(t, "reading the code", record.Events[0].Summary)
+
+	var obj toolkitv1alpha1.AgentTask
+	require.NoError(t, json.Unmarshal(record.Object, &obj))
+	assert.Equal(t, "AgentTask", obj.Kind)
+	assert.Equal(t, toolkitv1alpha1.GroupVersion.String(), obj.APIVersion)
+	assert.Equal(t, "https://github.com/test/repo", obj.Spec.Repo.URL)
+	assert.Empty(t, obj.ManagedFields)
+}
+
+func TestArchive_NilArchiver(t *testing.T) {
+	var a *taskArchiver
+	assert.NoError(t, a.archive(context.Background(), newTask("task-abc", nil, nil)))
+}
+
+func TestGetArchivedTask(t *testing.T) {
+	h := newTestHandler()
+	store := newMemArchive()
+	withArchive(h, store)
+	task := newTask("task-abc", nil, nil)
+	require.NoError(t, h.archiver.archive(context.Background(), task))
+
+	w := doGet(t, archiveRouter(h), "/api/v1/archive/tasks/task-abc")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/archive/tasks/task-abc", nil), w)
+
+	var record ArchivedTask
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &record))
+	assert.Equal(t, "task-abc", record.Task.ID)
+	assert.NotNil(t, record.Events)
+}
+
+func TestGetArchivedTask_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		store    *memArchive
+		taskID   string
+		wantCode int
+	}{
+		{"not configured", nil, "task-abc", http.StatusServiceUnavailable},
+		{"not archived", newMemArchive(), "task-abc", http.StatusNotFound},
+		{"invalid name", newMemArchive(), "Task_ABC", http.StatusNotFound},
+		{"store error", &memArchive{err: errors.New("connection refused")}, "task-abc", http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			if tt.store != nil {
+				withArchive(h, tt.store)
+			}
+			path := "/api/v1/archive/tasks/" + tt.taskID
+			w := doGet(t, archiveRouter(h), path)
+			assert.Equal(t, tt.wantCode, w.Code)
+			validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, path, nil), w)
+		})
+	}
+}
+
+func TestUpdateTaskStatus_ArchivesTerminalTask(t *testing.T) {
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	task := statusTask("task-abc", adapter.URL, nil)
+	h := newTestHandlerWithCallback("test-secret", task)
+	store := newMemArchive()
+	withArchive(h, store)
+
+	w := postJSON(t, testRouter(h), "/api/v1/tasks/task-abc/status", StatusUpdateRequest{
+		Event:   "completed",
+		Message: "done",
+		Details: map[string]any{"pr_url": "https://github.com/org/repo/pull/1"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	data, ok := store.objects["tasks/default/task-abc.json"]
+	require.True(t, ok, "terminal update archives the task")
+	var record ArchivedTask
+	require.NoError(t, json.Unmarshal(data, &record))
+	assert.Equal(t, "https://github.com/org/repo/pull/1", record.Task.Status.PRURL)
+}
+
+func TestUpdateTaskStatus_ArchiveFailureDoesNotBlockCallback(t *testing.T) {
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	task := statusTask("task-abc", adapter.URL, nil)
+	h := newTestHandlerWithCallback("test-secret", task)
+	withArchive(h, &memArchive{err: errors.New("access denied")})
+
+	w := postJSON(t, testRouter(h), "/api/v1/tasks/task-abc/status", StatusUpdateRequest{Event: "failed", Message: "boom"})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
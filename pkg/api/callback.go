@@ -19,27 +19,43 @@ package api
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+	"github.com/NissesSenap/shepherd/pkg/validate"
 )
 
-// callbackSender sends HMAC-signed callbacks to adapters.
+// callbackSender sends signed callbacks to adapters.
 type callbackSender struct {
 	secret     string
 	httpClient *http.Client
+	links      LinkOptions
+	// secondarySecret, when set, signs callbacks too, so adapters holding
+	// either secret accept them while the secret is rotated.
+	secondarySecret string
+	// signingKey, when set, adds an Ed25519 signature that adapters
+	// verify with the published public key instead of a shared secret.
+	signingKey ed25519.PrivateKey
+	// format is used for tasks that do not choose a callback format;
+	// empty means json.
+	format string
+	// source is the CloudEvents source attribute of cloudevents callbacks.
+	source string
 }
 
 func newCallbackSender(secret string) *callbackSender {
 	return &callbackSender{
 		secret: secret,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Transport: tracing.Transport(nil),
+			Timeout:   10 * time.Second,
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return http.ErrUseLastResponse
 			},
@@ -47,30 +63,76 @@ func newCallbackSender(secret string) *callbackSender {
 	}
 }
 
-// send POSTs a callback payload to the given URL with HMAC-SHA256 signature.
+// callbackTransport is the transport of callbacks in production. It only
+// connects to addresses targets allows, however the callback host resolves
+// by the time the callback is sent.
+func callbackTransport(targets *validate.CallbackTargets) *http.Transport {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.DialContext = targets.DialContext
+	return tr
+}
+
+// send POSTs a callback payload to the given URL with HMAC-SHA256 and
+// Ed25519 signatures, as configured, in the sender's default format.
+// The links to the task's pages are added to the payload.
 func (s *callbackSender) send(ctx context.Context, url string, payload CallbackPayload) error {
-	body, err := json.Marshal(payload)
+	_, err := s.deliver(ctx, toolkitv1alpha1.CallbackSpec{URL: url}, payload)
+	return err
+}
+
+// deliver is send for a task's callback spec, also returning the HTTP status
+// the adapter answered with, or zero if it did not answer.
+func (s *callbackSender) deliver(ctx context.Context, target toolkitv1alpha1.CallbackSpec, payload CallbackPayload) (int, error) {
+	payload.Links = s.links.forTask(payload.TaskID)
+	format := target.Format
+	if format == "" {
+		format = s.format
+	}
+	code, err := s.post(ctx, target.URL, format, payload)
+	result := resultSuccess
+	if err != nil {
+		result = resultFailure
+	}
+	callbacksSent.WithLabelValues(payload.Event, result).Inc()
+	return code, err
+}
+
+func (s *callbackSender) post(ctx context.Context, url, format string, payload CallbackPayload) (int, error) {
+	contentType := "application/json"
+	var v any = payload
+	if format == toolkitv1alpha1.CallbackFormatCloudEvents {
+		contentType = cloudEventContentType
+		v = s.cloudEvent(payload, time.Now())
+	}
+	body, err := json.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("marshaling callback payload: %w", err)
+		return 0, fmt.Errorf("marshaling callback payload: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("creating callback request: %w", err)
+		return 0, fmt.Errorf("creating callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if payload.CorrelationID != "" {
+		req.Header.Set(logging.CorrelationIDHeader, payload.CorrelationID)
+	}
+
+	// HMAC-SHA256 signature. The primary signature comes first, for
+	// adapters that only read one.
+	for _, secret := range []string{s.secret, s.secondarySecret} {
+		if secret == "" {
+			continue
+		}
+		req.Header.Add("X-Shepherd-Signature", signCallbackHMAC(secret, body))
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	// HMAC-SHA256 signature
-	if s.secret != "" {
-		mac := hmac.New(sha256.New, []byte(s.secret))
-		mac.Write(body)
-		sig := hex.EncodeToString(mac.Sum(nil))
-		req.Header.Set("X-Shepherd-Signature", "sha256="+sig)
+	if s.signingKey != nil {
+		req.Header.Add("X-Shepherd-Signature", SignCallbackEd25519(s.signingKey, body))
 	}
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("sending callback to %s: %w", url, err)
+		return 0, fmt.Errorf("sending callback to %s: %w", url, err)
 	}
 	defer func() {
 		// Drain response body to enable HTTP keep-alive connection reuse
@@ -79,8 +141,8 @@ func (s *callbackSender) send(ctx context.Context, url string, payload CallbackP
 	}()
 
 	if resp.StatusCode >= 300 {
-		return fmt.Errorf("callback to %s returned status %d", url, resp.StatusCode)
+		return resp.StatusCode, fmt.Errorf("callback to %s returned status %d", url, resp.StatusCode)
 	}
 
-	return nil
+	return resp.StatusCode, nil
 }
@@ -0,0 +1,73 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+const (
+	// maxCallbackHistory is how many callback attempts are kept on a task,
+	// matching the AgentTask CRD validation. The oldest are dropped first.
+	maxCallbackHistory = 20
+
+	// maxCallbackErrorLength bounds the error recorded for an attempt.
+	maxCallbackErrorLength = 1024
+)
+
+// newCallbackAttempt describes an attempt to send event at time at, which
+// the adapter answered with statusCode or failed with err.
+func newCallbackAttempt(event string, at time.Time, statusCode int, err error, replay bool) toolkitv1alpha1.CallbackAttempt {
+	attempt := toolkitv1alpha1.CallbackAttempt{
+		Time:       metav1.NewTime(at.UTC().Truncate(time.Second)),
+		Event:      event,
+		StatusCode: int32(statusCode),
+		Replay:     replay,
+	}
+	if err == nil {
+		return attempt
+	}
+	attempt.Error = err.Error()
+	if len(attempt.Error) > maxCallbackErrorLength {
+		attempt.Error = attempt.Error[:maxCallbackErrorLength-3] + "..."
+	}
+	return attempt
+}
+
+// recordCallbackAttempt appends attempt to the callback history of task,
+// dropping the oldest attempts beyond maxCallbackHistory.
+func recordCallbackAttempt(task *toolkitv1alpha1.AgentTask, attempt toolkitv1alpha1.CallbackAttempt) {
+	history := append(task.Status.CallbackHistory, attempt)
+	if n := len(history) - maxCallbackHistory; n > 0 {
+		history = history[n:]
+	}
+	task.Status.CallbackHistory = history
+}
+
+func callbackAttemptToResponse(attempt toolkitv1alpha1.CallbackAttempt) CallbackAttemptResponse {
+	return CallbackAttemptResponse{
+		Time:       attempt.Time.UTC().Format(time.RFC3339),
+		Event:      attempt.Event,
+		StatusCode: int(attempt.StatusCode),
+		Error:      attempt.Error,
+		Replay:     attempt.Replay,
+	}
+}
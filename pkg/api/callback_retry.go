@@ -0,0 +1,104 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// CallbackAttemptsAnnotation records how many times the terminal callback
+// of a task has failed. Together with the CallbackFailed condition it lets
+// any API server replica, including one started after the failure, retry
+// the callback on schedule.
+const CallbackAttemptsAnnotation = "shepherd.io/callback-attempts"
+
+const (
+	// maxCallbackAttempts is how many times a terminal callback is sent
+	// before CallbackFailed becomes final.
+	maxCallbackAttempts = 8
+
+	// callbackRetryBase is the wait after the first failed attempt. It
+	// doubles with every further attempt, up to callbackRetryMax, so the
+	// attempts span a little over an hour.
+	callbackRetryBase = 30 * time.Second
+	callbackRetryMax  = 30 * time.Minute
+)
+
+// callbackAttempts returns the number of failed callback attempts recorded
+// on task.
+func callbackAttempts(task *toolkitv1alpha1.AgentTask) int {
+	n, err := strconv.Atoi(task.Annotations[CallbackAttemptsAnnotation])
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// callbackBackoff returns how long to wait before retrying a callback that
+// failed attempts times.
+func callbackBackoff(attempts int) time.Duration {
+	backoff := callbackRetryBase
+	for i := 1; i < attempts && backoff < callbackRetryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, callbackRetryMax)
+}
+
+// callbackDue reports whether the terminal callback of task should be sent
+// at now: it has not been claimed yet, its CallbackPending claim expired, or
+// it failed and its next retry is due.
+func callbackDue(task *toolkitv1alpha1.AgentTask, now time.Time) bool {
+	cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionNotified)
+	if cond == nil {
+		return true
+	}
+	switch cond.Reason {
+	case toolkitv1alpha1.ReasonCallbackSent:
+		return false
+	case toolkitv1alpha1.ReasonCallbackPending:
+		return now.Sub(cond.LastTransitionTime.Time) >= callbackPendingTTL
+	case toolkitv1alpha1.ReasonCallbackFailed:
+		attempts := callbackAttempts(task)
+		return attempts < maxCallbackAttempts &&
+			now.Sub(cond.LastTransitionTime.Time) >= callbackBackoff(attempts)
+	}
+	return true
+}
+
+// recordCallbackFailure counts a failed callback attempt on task and returns
+// the message for its CallbackFailed condition. task is updated to the
+// patched object.
+func recordCallbackFailure(ctx context.Context, tasks TaskStore, task *toolkitv1alpha1.AgentTask, callbackErr error) (string, error) {
+	attempts := callbackAttempts(task) + 1
+	var err error
+	if err = tasks.SetAnnotation(ctx, task, CallbackAttemptsAnnotation, strconv.Itoa(attempts)); err != nil {
+		err = fmt.Errorf("recording callback attempt: %w", err)
+	}
+
+	if attempts >= maxCallbackAttempts {
+		return fmt.Sprintf("Callback failed after %d attempts: %v", attempts, callbackErr), err
+	}
+	return fmt.Sprintf("Callback failed (attempt %d of %d), retrying in %s: %v",
+		attempts, maxCallbackAttempts, callbackBackoff(attempts), callbackErr), err
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestCallbackBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, callbackBackoff(1))
+	assert.Equal(t, time.Minute, callbackBackoff(2))
+	assert.Equal(t, 16*time.Minute, callbackBackoff(6))
+	assert.Equal(t, 30*time.Minute, callbackBackoff(7))
+	assert.Equal(t, 30*time.Minute, callbackBackoff(50))
+}
+
+func TestCallbackDue(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	notified := func(reason string, since time.Duration, attempts string) *toolkitv1alpha1.AgentTask {
+		task := &toolkitv1alpha1.AgentTask{}
+		if attempts != "" {
+			task.Annotations = map[string]string{CallbackAttemptsAnnotation: attempts}
+		}
+		if reason != "" {
+			task.Status.Conditions = []metav1.Condition{{
+				Type:               toolkitv1alpha1.ConditionNotified,
+				Reason:             reason,
+				LastTransitionTime: metav1.NewTime(now.Add(-since)),
+			}}
+		}
+		return task
+	}
+
+	tests := []struct {
+		name string
+		task *toolkitv1alpha1.AgentTask
+		want bool
+	}{
+		{"not claimed", notified("", 0, ""), true},
+		{"sent", notified(toolkitv1alpha1.ReasonCallbackSent, time.Hour, ""), false},
+		{"fresh claim", notified(toolkitv1alpha1.ReasonCallbackPending, time.Minute, ""), false},
+		{"expired claim", notified(toolkitv1alpha1.ReasonCallbackPending, callbackPendingTTL, ""), true},
+		{"failed, backing off", notified(toolkitv1alpha1.ReasonCallbackFailed, 50*time.Second, "2"), false},
+		{"failed, retry due", notified(toolkitv1alpha1.ReasonCallbackFailed, time.Minute, "2"), true},
+		{"failed before attempts were counted", notified(toolkitv1alpha1.ReasonCallbackFailed, time.Minute, ""), true},
+		{"failed, invalid count", notified(toolkitv1alpha1.ReasonCallbackFailed, time.Minute, "many"), true},
+		{"failed, attempts exhausted", notified(toolkitv1alpha1.ReasonCallbackFailed, 24*time.Hour, "8"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, callbackDue(tt.task, now))
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+// CallbackSignatureAlgorithmEd25519 is the algorithm of asymmetric callback
+// signatures. They are sent as "ed25519=<base64 signature of the body>" in
+// an X-Shepherd-Signature header, next to the HMAC signatures.
+const CallbackSignatureAlgorithmEd25519 = "ed25519"
+
+const (
+	ed25519SignaturePrefix = CallbackSignatureAlgorithmEd25519 + "="
+	hmacSignaturePrefix    = "sha256="
+)
+
+// LoadCallbackSigningKey reads an Ed25519 private key in PKCS #8 PEM form,
+// as written by "openssl genpkey -algorithm ed25519".
+func LoadCallbackSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading callback signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("callback signing key %s is not a PEM private key", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing callback signing key: %w", err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("callback signing key %s is a %T, not an Ed25519 key", path, key)
+	}
+	return priv, nil
+}
+
+// ParseCallbackPublicKeys parses the Ed25519 public keys callbacks are
+// verified with, in PKIX PEM form as served by
+// GET /api/v1/callback-signing-key. data may hold several keys, so the
+// old and new key are both accepted while the signing key is replaced.
+func ParseCallbackPublicKeys(data []byte) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("unexpected PEM block %q, want PUBLIC KEY", block.Type)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key: %w", err)
+		}
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key is a %T, not an Ed25519 key", key)
+		}
+		keys = append(keys, pub)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no PEM public key found")
+	}
+	return keys, nil
+}
+
+// LoadCallbackPublicKeys reads the public keys of ParseCallbackPublicKeys
+// from path.
+func LoadCallbackPublicKeys(path string) ([]ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading callback public key: %w", err)
+	}
+	keys, err := ParseCallbackPublicKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("callback public key %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// signCallbackHMAC returns the X-Shepherd-Signature value of body signed
+// with secret.
+func signCallbackHMAC(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmacSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignCallbackEd25519 returns the X-Shepherd-Signature value of body signed
+// with key.
+func SignCallbackEd25519(key ed25519.PrivateKey, body []byte) string {
+	return ed25519SignaturePrefix + base64.StdEncoding.EncodeToString(ed25519.Sign(key, body))
+}
+
+// VerifyCallbackEd25519 reports whether signature, an X-Shepherd-Signature
+// value, is an Ed25519 signature of body made with the key of pub.
+// Signatures of other algorithms do not verify.
+func VerifyCallbackEd25519(pub ed25519.PublicKey, body []byte, signature string) bool {
+	encoded, ok := strings.CutPrefix(signature, ed25519SignaturePrefix)
+	if !ok {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, body, sig)
+}
+
+// CallbackVerifier verifies the X-Shepherd-Signature headers of callbacks
+// in adapters. The API sends one header per secret and key, so a callback
+// passes if any of its signatures was made with one of Secrets or with the
+// private key of one of PublicKeys. Empty secrets are ignored; a verifier
+// without secrets or keys accepts every callback.
+type CallbackVerifier struct {
+	Secrets    []string
+	PublicKeys []ed25519.PublicKey
+}
+
+// Verify reports whether any of signatures verifies body.
+func (v CallbackVerifier) Verify(body []byte, signatures ...string) bool {
+	secrets := slices.DeleteFunc(slices.Clone(v.Secrets), func(s string) bool { return s == "" })
+	if len(secrets) == 0 && len(v.PublicKeys) == 0 {
+		return true // No verification if no secret
+	}
+
+	for _, key := range v.PublicKeys {
+		for _, signature := range signatures {
+			if VerifyCallbackEd25519(key, body, signature) {
+				return true
+			}
+		}
+	}
+
+	for _, secret := range secrets {
+		expected := signCallbackHMAC(secret, body)
+		for _, signature := range signatures {
+			if strings.HasPrefix(signature, hmacSignaturePrefix) && hmac.Equal([]byte(expected), []byte(signature)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getCallbackSigningKey handles GET /api/v1/callback-signing-key, which
+// publishes the public key of callback signatures so adapters can verify
+// callbacks without sharing a secret with the API server.
+func (h *taskHandler) getCallbackSigningKey(w http.ResponseWriter, _ *http.Request) {
+	if h.callback.signingKey == nil {
+		writeError(w, http.StatusNotFound, "callbacks are not signed with a key", "")
+		return
+	}
+	der, err := x509.MarshalPKIXPublicKey(h.callback.signingKey.Public())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode public key", "")
+		return
+	}
+	writeJSON(w, http.StatusOK, CallbackSigningKeyResponse{
+		Algorithm: CallbackSignatureAlgorithmEd25519,
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	})
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKey writes key as a PKCS #8 PEM file and returns its path.
+func writeKey(t *testing.T, key any) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	return path
+}
+
+func TestLoadCallbackSigningKey(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	got, err := LoadCallbackSigningKey(writeKey(t, priv))
+	require.NoError(t, err)
+	assert.Equal(t, priv, got)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = LoadCallbackSigningKey(writeKey(t, ecKey))
+	assert.ErrorContains(t, err, "not an Ed25519 key")
+
+	notPEM := filepath.Join(t.TempDir(), "key.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("secret"), 0o600))
+	_, err = LoadCallbackSigningKey(notPEM)
+	assert.ErrorContains(t, err, "is not a PEM private key")
+}
+
+func TestParseCallbackPublicKeys(t *testing.T) {
+	encode := func(key any) string {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		require.NoError(t, err)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+	oldKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	newKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	keys, err := ParseCallbackPublicKeys([]byte(encode(oldKey) + encode(newKey)))
+	require.NoError(t, err)
+	assert.Equal(t, []ed25519.PublicKey{oldKey, newKey}, keys)
+
+	_, err = ParseCallbackPublicKeys([]byte("not a key"))
+	assert.ErrorContains(t, err, "no PEM public key")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, err = ParseCallbackPublicKeys([]byte(encode(&ecKey.PublicKey)))
+	assert.ErrorContains(t, err, "not an Ed25519 key")
+}
+
+func TestVerifyCallbackEd25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	body := []byte(`{"taskID":"task-abc","event":"completed"}`)
+	sig := SignCallbackEd25519(priv, body)
+
+	assert.True(t, VerifyCallbackEd25519(pub, body, sig))
+	assert.False(t, VerifyCallbackEd25519(pub, []byte(`{}`), sig), "other body")
+	assert.False(t, VerifyCallbackEd25519(pub, body, "sha256="+sig[len("ed25519="):]), "other algorithm")
+	assert.False(t, VerifyCallbackEd25519(pub, body, "ed25519=not base64!"))
+}
+
+func TestCallbackVerifier(t *testing.T) {
+	body := []byte(`{"taskID":"task-abc","event":"completed"}`)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, otherPriv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	t.Run("no secrets or keys", func(t *testing.T) {
+		assert.True(t, CallbackVerifier{Secrets: []string{"", ""}}.Verify(body))
+	})
+
+	t.Run("secrets", func(t *testing.T) {
+		v := CallbackVerifier{Secrets: []string{"old-secret", "new-secret"}}
+		assert.True(t, v.Verify(body, signCallbackHMAC("old-secret", body)), "old secret")
+		assert.True(t, v.Verify(body, signCallbackHMAC("unknown", body), signCallbackHMAC("new-secret", body)),
+			"any signature")
+		assert.False(t, v.Verify(body, signCallbackHMAC("unknown", body)))
+		assert.False(t, v.Verify([]byte(`{}`), signCallbackHMAC("old-secret", body)), "other body")
+		assert.False(t, v.Verify(body), "a secret requires a signature")
+	})
+
+	t.Run("public keys", func(t *testing.T) {
+		v := CallbackVerifier{PublicKeys: []ed25519.PublicKey{pub}}
+		assert.True(t, v.Verify(body, "sha256=unknown", SignCallbackEd25519(priv, body)))
+		assert.False(t, v.Verify(body, SignCallbackEd25519(otherPriv, body)), "other key")
+		assert.False(t, v.Verify(body), "a key requires a signature")
+	})
+}
+
+func TestLoadCallbackPublicKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key.pub")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	keys, err := LoadCallbackPublicKeys(path)
+	require.NoError(t, err)
+	assert.Equal(t, []ed25519.PublicKey{pub}, keys)
+
+	_, err = LoadCallbackPublicKeys(filepath.Join(t.TempDir(), "missing.pub"))
+	assert.ErrorContains(t, err, "reading callback public key")
+}
+
+func TestGetCallbackSigningKey(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		h := newTestHandler()
+		r := testRouter(h)
+		w := doGet(t, r, "/api/v1/callback-signing-key")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("publishes the public key", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		h := newTestHandler()
+		h.callback.signingKey = priv
+		r := testRouter(h)
+
+		w := doGet(t, r, "/api/v1/callback-signing-key")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		doc := loadSpec(t)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/callback-signing-key", nil)
+		validateResponse(t, doc, req, w)
+
+		var resp CallbackSigningKeyResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, CallbackSignatureAlgorithmEd25519, resp.Algorithm)
+		got, err := ParseCallbackPublicKeys([]byte(resp.PublicKey))
+		require.NoError(t, err)
+		assert.Equal(t, []ed25519.PublicKey{pub}, got)
+	})
+}
@@ -18,6 +18,7 @@ package api
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -25,11 +26,16 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/validate"
 )
 
 func TestCallbackSender_HMACSignature(t *testing.T) {
@@ -64,6 +70,139 @@ func TestCallbackSender_HMACSignature(t *testing.T) {
 	require.NoError(t, json.Unmarshal(receivedBody, &got))
 	assert.Equal(t, payload.TaskID, got.TaskID)
 	assert.Equal(t, payload.Event, got.Event)
+	assert.Nil(t, got.Links, "no links configured")
+}
+
+func TestCallbackSender_SecondarySecret(t *testing.T) {
+	var signatures []string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures = r.Header.Values("X-Shepherd-Signature")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	sender := newCallbackSender("new-secret")
+	sender.secondarySecret = "old-secret"
+	require.NoError(t, sender.send(context.Background(), srv.URL, CallbackPayload{TaskID: "task-abc", Event: EventCompleted}))
+
+	require.Len(t, signatures, 2)
+	for i, secret := range []string{"new-secret", "old-secret"} {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signatures[i], secret)
+	}
+}
+
+func TestCallbackSender_SigningKey(t *testing.T) {
+	var signatures []string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures = r.Header.Values("X-Shepherd-Signature")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	sender := newCallbackSender("secret")
+	sender.signingKey = priv
+	require.NoError(t, sender.send(context.Background(), srv.URL, CallbackPayload{TaskID: "task-abc", Event: EventCompleted}))
+
+	require.Len(t, signatures, 2)
+	assert.True(t, strings.HasPrefix(signatures[0], "sha256="), "HMAC signature first")
+	assert.True(t, VerifyCallbackEd25519(pub, body, signatures[1]))
+}
+
+func TestCallbackSender_Links(t *testing.T) {
+	var got CallbackPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	sender := newCallbackSender("")
+	sender.links = LinkOptions{
+		DashboardURL: "https://shepherd.example.com/",
+		LogsURL:      `https://grafana.example.com/explore?query={task_id="{taskID}"}`,
+	}
+	err := sender.send(context.Background(), srv.URL, CallbackPayload{TaskID: "task-abc", Event: EventCompleted})
+	require.NoError(t, err)
+
+	require.NotNil(t, got.Links)
+	assert.Equal(t, "https://shepherd.example.com/tasks/task-abc", got.Links.Dashboard)
+	assert.Equal(t, `https://grafana.example.com/explore?query={task_id="task-abc"}`, got.Links.Logs)
+}
+
+func TestCallbackSender_CorrelationID(t *testing.T) {
+	var got CallbackPayload
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Correlation-ID")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	sender := newCallbackSender("")
+	err := sender.send(context.Background(), srv.URL, CallbackPayload{TaskID: "task-abc", Event: EventCompleted, CorrelationID: "corr-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "corr-1", header)
+	assert.Equal(t, "corr-1", got.CorrelationID)
+}
+
+func TestCallbackSender_CloudEvents(t *testing.T) {
+	var contentType string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	sender := newCallbackSender("")
+	sender.source = cloudEventSource("shepherd")
+	payload := CallbackPayload{TaskID: "task-abc", Event: EventCompleted, Message: "done", CorrelationID: "corr-1"}
+
+	t.Run("per task", func(t *testing.T) {
+		_, err := sender.deliver(context.Background(), toolkitv1alpha1.CallbackSpec{
+			URL:    srv.URL,
+			Format: toolkitv1alpha1.CallbackFormatCloudEvents,
+		}, payload)
+		require.NoError(t, err)
+		assert.Equal(t, "application/cloudevents+json", contentType)
+
+		var event CloudEvent
+		require.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, "1.0", event.SpecVersion)
+		assert.NotEmpty(t, event.ID)
+		assert.Equal(t, "/shepherd/namespaces/shepherd", event.Source)
+		assert.Equal(t, "io.shepherd.task.completed", event.Type)
+		assert.Equal(t, "task-abc", event.Subject)
+		assert.Equal(t, "corr-1", event.CorrelationID)
+		_, err = time.Parse(time.RFC3339Nano, event.Time)
+		require.NoError(t, err)
+		assert.Equal(t, "done", event.Data.Message)
+	})
+
+	t.Run("task overrides default", func(t *testing.T) {
+		sender.format = toolkitv1alpha1.CallbackFormatCloudEvents
+		defer func() { sender.format = "" }()
+
+		_, err := sender.deliver(context.Background(), toolkitv1alpha1.CallbackSpec{
+			URL:    srv.URL,
+			Format: toolkitv1alpha1.CallbackFormatJSON,
+		}, payload)
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", contentType)
+
+		require.NoError(t, sender.send(context.Background(), srv.URL, payload))
+		assert.Equal(t, "application/cloudevents+json", contentType)
+	})
 }
 
 func TestCallbackSender_EmptySecretSkipsSignature(t *testing.T) {
@@ -100,6 +239,28 @@ func TestCallbackSender_NetworkError(t *testing.T) {
 	assert.Contains(t, err.Error(), "sending callback")
 }
 
+func TestCallbackSender_BlocksInternalAddresses(t *testing.T) {
+	var called atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called.Store(true)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// An empty deny list leaves 127.0.0.1 to the address check.
+	targets, err := validate.NewCallbackTargets(validate.CallbackTargetOptions{Denied: []string{}})
+	require.NoError(t, err)
+	sender := newCallbackSender("secret")
+	sender.httpClient.Transport = callbackTransport(targets)
+
+	// The test server listens on loopback, which a callback must not reach
+	// however the task got past validation.
+	err = sender.send(context.Background(), srv.URL, CallbackPayload{TaskID: "task-abc", Event: "completed"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "blocked address 127.0.0.1")
+	assert.False(t, called.Load())
+}
+
 func TestCallbackSender_RespectsTimeout(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		time.Sleep(500 * time.Millisecond)
@@ -0,0 +1,80 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// cloudEventContentType is the Content-Type of callbacks sent as CloudEvents
+// in structured mode.
+const cloudEventContentType = "application/cloudevents+json"
+
+// cloudEventTypePrefix is prepended to the callback event to form the
+// CloudEvents type, e.g. io.shepherd.task.completed.
+const cloudEventTypePrefix = "io.shepherd.task."
+
+// defaultCloudEventSource is the CloudEvents source when the API server
+// does not know its namespace.
+const defaultCloudEventSource = "/shepherd"
+
+// CloudEvent is a CloudEvents 1.0 event in the structured JSON format,
+// carrying a CallbackPayload as its data.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject"`
+	Time            string          `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            CallbackPayload `json:"data"`
+	// CorrelationID is the task's correlation ID, as an extension attribute
+	// so routers can filter on it without reading the data.
+	CorrelationID string `json:"correlationid,omitempty"`
+}
+
+// cloudEventSource returns the CloudEvents source of the callbacks for the
+// tasks in namespace.
+func cloudEventSource(namespace string) string {
+	if namespace == "" {
+		return defaultCloudEventSource
+	}
+	return defaultCloudEventSource + "/namespaces/" + namespace
+}
+
+// cloudEvent wraps payload in a CloudEvent. Every call gets a new ID, so a
+// retried or replayed callback is a new event.
+func (s *callbackSender) cloudEvent(payload CallbackPayload, now time.Time) CloudEvent {
+	source := s.source
+	if source == "" {
+		source = defaultCloudEventSource
+	}
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              string(uuid.NewUUID()),
+		Source:          source,
+		Type:            cloudEventTypePrefix + payload.Event,
+		Subject:         payload.TaskID,
+		Time:            now.UTC().Format(time.RFC3339Nano),
+		DataContentType: "application/json",
+		Data:            payload,
+		CorrelationID:   payload.CorrelationID,
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Event privacy levels control how much detail of the agent's activity a
+// runner sends out of the sandbox as task events.
+const (
+	// EventPrivacyFull sends events as the agent produced them.
+	EventPrivacyFull = "full"
+	// EventPrivacySummariesOnly sends events without tool inputs and
+	// outputs: tool calls and results are reduced to the tool's name and
+	// whether it succeeded.
+	EventPrivacySummariesOnly = "summaries-only"
+	// EventPrivacyMinimal sends no agent events; only the runner's status
+	// reports of the task's phases leave the sandbox.
+	EventPrivacyMinimal = "minimal"
+)
+
+// ValidEventPrivacy reports whether level is a known event privacy level.
+func ValidEventPrivacy(level string) bool {
+	switch level {
+	case EventPrivacyFull, EventPrivacySummariesOnly, EventPrivacyMinimal:
+		return true
+	}
+	return false
+}
+
+// EventPrivacyOptions chooses the event privacy level of a task by its
+// repository, for repositories whose code must not leave the sandbox.
+type EventPrivacyOptions struct {
+	// Default is the level of repositories without one of their own;
+	// empty means EventPrivacyFull.
+	Default string
+	// Repos maps "owner/repo", or "owner" for all repositories of an
+	// owner, to a level. Keys are matched case-insensitively and a
+	// repository's own entry wins over its owner's.
+	Repos map[string]string
+}
+
+// Level returns the event privacy level of tasks for repoURL.
+func (o EventPrivacyOptions) Level(repoURL string) string {
+	if owner, repo, ok := repoOwnerName(repoURL); ok {
+		for key, level := range o.Repos {
+			if strings.EqualFold(key, owner+"/"+repo) {
+				return level
+			}
+		}
+		for key, level := range o.Repos {
+			if strings.EqualFold(key, owner) {
+				return level
+			}
+		}
+	}
+	if o.Default == "" {
+		return EventPrivacyFull
+	}
+	return o.Default
+}
+
+// repoOwnerName returns the last two path segments of repoURL, which are
+// the owner and name of the repository whatever path prefix the forge
+// serves repositories under.
+func repoOwnerName(repoURL string) (owner, repo string, ok bool) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", false
+	}
+	parts := strings.Split(strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", false
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], true
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventPrivacyOptions_Level(t *testing.T) {
+	opts := EventPrivacyOptions{
+		Default: EventPrivacySummariesOnly,
+		Repos: map[string]string{
+			"acme":          EventPrivacyMinimal,
+			"acme/website":  EventPrivacyFull,
+			"other/secrets": EventPrivacyMinimal,
+		},
+	}
+
+	tests := []struct {
+		repoURL string
+		want    string
+	}{
+		{"https://github.com/acme/payments", EventPrivacyMinimal},
+		{"https://github.com/ACME/Payments.git", EventPrivacyMinimal},
+		{"https://github.com/acme/website", EventPrivacyFull},
+		{"https://github.com/other/secrets", EventPrivacyMinimal},
+		{"https://ghe.example.com/github/other/secrets.git", EventPrivacyMinimal},
+		{"https://github.com/other/public", EventPrivacySummariesOnly},
+		{"not a url", EventPrivacySummariesOnly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repoURL, func(t *testing.T) {
+			assert.Equal(t, tt.want, opts.Level(tt.repoURL))
+		})
+	}
+
+	assert.Equal(t, EventPrivacyFull, EventPrivacyOptions{}.Level("https://github.com/org/repo"))
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventSchemaVersion is the version of the TaskEvent schema this package
+// defines. Runners send it in the EventSchemaVersionHeader of
+// POST /api/v1/tasks/{taskID}/events; requests without the header are
+// treated as version 1.
+const EventSchemaVersion = 1
+
+// EventSchemaVersionHeader carries the TaskEvent schema version of the
+// events in a request. The API server sets it on its response to the
+// version it translated them to.
+const EventSchemaVersionHeader = "X-Shepherd-Event-Schema"
+
+// EventSchema describes one version of the TaskEvent schema.
+type EventSchema struct {
+	Version int `json:"version"`
+	// Types are the accepted values of TaskEvent.Type.
+	Types []TaskEventType `json:"types"`
+	// Required are the JSON names of the fields every event must set.
+	Required []string `json:"required"`
+
+	// upgrade translates an event of this version to the next version. It
+	// is nil for the current version.
+	upgrade func(TaskEvent) TaskEvent
+}
+
+// EventSchemaResponse is the JSON response for GET /api/v1/event-schemas.
+type EventSchemaResponse struct {
+	Current int           `json:"current"`
+	Schemas []EventSchema `json:"schemas"`
+}
+
+// eventSchemaRegistry holds every TaskEvent schema version the API server
+// accepts, oldest first. The last entry is the current version.
+//
+// A change that older runners cannot satisfy, such as a new required field
+// or a renamed event type, gets a new version, and the previous entry an
+// upgrade function, so runner images built against the old schema keep
+// working against a newer API server.
+type eventSchemaRegistry []EventSchema
+
+// eventSchemas is the registry of the TaskEvent schema versions.
+var eventSchemas = eventSchemaRegistry{
+	{
+		Version:  1,
+		Types:    []TaskEventType{EventTypeThinking, EventTypeToolCall, EventTypeToolResult, EventTypeError},
+		Required: []string{"sequence", "timestamp", "type", "summary"},
+	},
+}
+
+// EventSchemas returns the TaskEvent schema versions the API server
+// accepts, oldest first.
+func EventSchemas() []EventSchema {
+	return slices.Clone(eventSchemas)
+}
+
+func (reg eventSchemaRegistry) versions() []string {
+	versions := make([]string, len(reg))
+	for i, s := range reg {
+		versions[i] = strconv.Itoa(s.Version)
+	}
+	return versions
+}
+
+// index returns the position of version in the registry.
+func (reg eventSchemaRegistry) index(version int) (int, bool) {
+	return slices.BinarySearchFunc(reg, version, func(s EventSchema, v int) int { return s.Version - v })
+}
+
+// eventError is a validation failure, reported as an ErrorResponse.
+type eventError struct {
+	message string
+	details string
+}
+
+func (e *eventError) Error() string {
+	if e.details == "" {
+		return e.message
+	}
+	return e.message + ": " + e.details
+}
+
+// requestVersion returns the schema version named by the request's
+// EventSchemaVersionHeader.
+func (reg eventSchemaRegistry) requestVersion(r *http.Request) (int, error) {
+	value := r.Header.Get(EventSchemaVersionHeader)
+	if value == "" {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &eventError{message: "invalid " + EventSchemaVersionHeader + " header", details: "must be an integer"}
+	}
+	if _, ok := reg.index(version); !ok {
+		return 0, &eventError{
+			message: "unsupported event schema version",
+			details: fmt.Sprintf("version %d; supported versions: %s", version, strings.Join(reg.versions(), ", ")),
+		}
+	}
+	return version, nil
+}
+
+// validate checks e against the schema of its version.
+func (s EventSchema) validate(e TaskEvent) error {
+	for _, field := range s.Required {
+		switch field {
+		case "type":
+			if e.Type == "" {
+				return &eventError{message: "event type is required"}
+			}
+			if !slices.Contains(s.Types, e.Type) {
+				types := make([]string, len(s.Types))
+				for i, t := range s.Types {
+					types[i] = string(t)
+				}
+				return &eventError{message: "invalid event type", details: "must be one of: " + strings.Join(types, ", ")}
+			}
+		case "summary":
+			if e.Summary == "" {
+				return &eventError{message: "event summary is required"}
+			}
+		case "sequence":
+			if e.Sequence <= 0 {
+				return &eventError{message: "event sequence must be positive"}
+			}
+		case "timestamp":
+			if _, err := time.Parse(time.RFC3339Nano, e.Timestamp); err != nil {
+				return &eventError{message: "invalid event timestamp", details: "must be RFC3339 date-time format"}
+			}
+		}
+	}
+	return nil
+}
+
+// translate validates events of the given schema version and upgrades them
+// to the current version.
+func (reg eventSchemaRegistry) translate(version int, events []TaskEvent) ([]TaskEvent, error) {
+	start, ok := reg.index(version)
+	if !ok {
+		return nil, fmt.Errorf("unknown event schema version %d", version)
+	}
+	for _, e := range events {
+		if err := reg[start].validate(e); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range reg[start : len(reg)-1] {
+		for i := range events {
+			events[i] = s.upgrade(events[i])
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestEventSchemaVersionIsLatest(t *testing.T) {
+	assert.Equal(t, EventSchemaVersion, eventSchemas[len(eventSchemas)-1].Version)
+	assert.Nil(t, eventSchemas[len(eventSchemas)-1].upgrade, "the current version has nothing to upgrade to")
+	for _, s := range eventSchemas[:len(eventSchemas)-1] {
+		assert.NotNil(t, s.upgrade, "version %d needs an upgrade to the next version", s.Version)
+	}
+}
+
+func TestEventSchemaRegistry_Translate(t *testing.T) {
+	// A registry in which version 2 renamed the "text" event type of
+	// version 1 to "thinking".
+	reg := eventSchemaRegistry{
+		{
+			Version:  1,
+			Types:    []TaskEventType{"text", EventTypeToolCall},
+			Required: []string{"sequence", "timestamp", "type", "summary"},
+			upgrade: func(e TaskEvent) TaskEvent {
+				if e.Type == "text" {
+					e.Type = EventTypeThinking
+				}
+				return e
+			},
+		},
+		{
+			Version:  2,
+			Types:    []TaskEventType{EventTypeThinking, EventTypeToolCall},
+			Required: []string{"sequence", "timestamp", "type", "summary"},
+		},
+	}
+	event := func(typ TaskEventType) TaskEvent {
+		return TaskEvent{Sequence: 1, Timestamp: "2026-01-01T00:00:00Z", Type: typ, Summary: "Analyzing code"}
+	}
+
+	events, err := reg.translate(1, []TaskEvent{event("text"), event(EventTypeToolCall)})
+	require.NoError(t, err)
+	assert.Equal(t, EventTypeThinking, events[0].Type)
+	assert.Equal(t, EventTypeToolCall, events[1].Type)
+
+	_, err = reg.translate(2, []TaskEvent{event("text")})
+	assert.EqualError(t, err, "invalid event type: must be one of: thinking, tool_call")
+
+	_, err = reg.translate(1, []TaskEvent{event(EventTypeThinking)})
+	assert.EqualError(t, err, "invalid event type: must be one of: text, tool_call",
+		"events are validated against the schema of their version")
+}
+
+func postEventsWithSchema(t *testing.T, router http.Handler, taskID, version string) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(PostEventRequest{Events: []TaskEvent{
+		{Sequence: 1, Timestamp: "2026-01-01T00:00:00Z", Type: EventTypeThinking, Summary: "Analyzing code"},
+	}})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/"+taskID+"/events", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	if version != "" {
+		req.Header.Set(EventSchemaVersionHeader, version)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPostEvents_SchemaVersion(t *testing.T) {
+	task := newTask("task-schema", nil, []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionUnknown,
+		Reason: toolkitv1alpha1.ReasonRunning,
+	}})
+	router := testRouter(newTestHandler(task))
+
+	tests := []struct {
+		name        string
+		version     string
+		wantCode    int
+		wantError   string
+		wantDetails string
+	}{
+		{name: "current", version: "1", wantCode: http.StatusOK},
+		{name: "omitted", wantCode: http.StatusOK},
+		{name: "newer than the API", version: "99", wantCode: http.StatusBadRequest,
+			wantError: "unsupported event schema version", wantDetails: "version 99; supported versions: 1"},
+		{name: "not a number", version: "v1", wantCode: http.StatusBadRequest,
+			wantError: "invalid X-Shepherd-Event-Schema header", wantDetails: "must be an integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postEventsWithSchema(t, router, "task-schema", tt.version)
+			require.Equal(t, tt.wantCode, w.Code, w.Body.String())
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, "1", w.Header().Get(EventSchemaVersionHeader))
+				return
+			}
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+			assert.Equal(t, tt.wantError, errResp.Error)
+			assert.Equal(t, tt.wantDetails, errResp.Details)
+		})
+	}
+}
+
+func TestGetEventSchemas(t *testing.T) {
+	router := testRouter(newTestHandler())
+
+	w := doGet(t, router, "/api/v1/event-schemas")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp EventSchemaResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, EventSchemaVersion, resp.Current)
+	require.Len(t, resp.Schemas, 1)
+	assert.Equal(t, []TaskEventType{EventTypeThinking, EventTypeToolCall, EventTypeToolResult, EventTypeError}, resp.Schemas[0].Types)
+	assert.Equal(t, []string{"sequence", "timestamp", "type", "summary"}, resp.Schemas[0].Required)
+
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/event-schemas", nil), w)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package api
 
 import (
+	"slices"
 	"sort"
 	"sync"
 
@@ -143,6 +144,19 @@ func (h *EventHub) Subscribe(taskID string, after int64) (history []TaskEvent, c
 	return history, subCh, unsubscribe
 }
 
+// Events returns a copy of the buffered events of a task, oldest first.
+func (h *EventHub) Events(taskID string) []TaskEvent {
+	h.mu.RLock()
+	ts, ok := h.tasks[taskID]
+	h.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return slices.Clone(ts.events)
+}
+
 // Complete marks a task stream as done and closes all subscriber channels.
 func (h *EventHub) Complete(taskID string) {
 	h.mu.RLock()
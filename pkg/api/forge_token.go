@@ -0,0 +1,79 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// staticTokenLifetime is the expiry reported for static tokens. They do
+// not expire on their own; runners are told the same lifetime as for
+// GitHub installation tokens.
+const staticTokenLifetime = time.Hour
+
+// StaticTokenProvider hands out one access token for every repository of
+// a forge that has no short-lived tokens, such as a Gitea or Forgejo
+// instance. The token should belong to a bot account that can push to, and
+// open pull requests on, the repositories shepherd works on.
+type StaticTokenProvider struct {
+	token string
+}
+
+// NewStaticTokenProvider reads the token from the file at path.
+func NewStaticTokenProvider(path string) (*StaticTokenProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading token: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return nil, fmt.Errorf("token file %s is empty", path)
+	}
+	return &StaticTokenProvider{token: token}, nil
+}
+
+// GetToken returns the token, whatever the repository.
+func (p *StaticTokenProvider) GetToken(context.Context, string) (string, time.Time, error) {
+	return p.token, time.Now().Add(staticTokenLifetime), nil
+}
+
+// hostTokenProvider issues tokens for repositories on host with provider
+// and for all others with fallback, which may be nil.
+type hostTokenProvider struct {
+	host     string
+	provider TokenProvider
+	fallback TokenProvider
+}
+
+func (p *hostTokenProvider) GetToken(ctx context.Context, repoURL string) (string, time.Time, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid repo URL: %w", err)
+	}
+	if strings.EqualFold(u.Host, p.host) {
+		return p.provider.GetToken(ctx, repoURL)
+	}
+	if p.fallback == nil {
+		return "", time.Time{}, fmt.Errorf("no token provider for repositories on %s", u.Host)
+	}
+	return p.fallback.GetToken(ctx, repoURL)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStaticTokenProvider(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(path, []byte("gitea-token\n"), 0o600))
+
+	p, err := NewStaticTokenProvider(path)
+	require.NoError(t, err)
+	token, expiresAt, err := p.GetToken(context.Background(), "https://gitea.example.com/org/repo.git")
+	require.NoError(t, err)
+	assert.Equal(t, "gitea-token", token)
+	assert.WithinDuration(t, time.Now().Add(staticTokenLifetime), expiresAt, time.Minute)
+
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(empty, []byte("\n"), 0o600))
+	_, err = NewStaticTokenProvider(empty)
+	assert.Error(t, err)
+
+	_, err = NewStaticTokenProvider(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestHostTokenProvider(t *testing.T) {
+	gitea := &StaticTokenProvider{token: "gitea-token"}
+	github := &mockTokenProvider{token: "github-token", expiresAt: time.Now().Add(time.Hour)}
+
+	p := &hostTokenProvider{host: "gitea.example.com", provider: gitea, fallback: github}
+	token, _, err := p.GetToken(context.Background(), "https://Gitea.example.com/org/repo.git")
+	require.NoError(t, err)
+	assert.Equal(t, "gitea-token", token)
+
+	token, _, err = p.GetToken(context.Background(), "https://github.com/org/repo.git")
+	require.NoError(t, err)
+	assert.Equal(t, "github-token", token)
+
+	p.fallback = nil
+	_, _, err = p.GetToken(context.Background(), "https://github.com/org/repo.git")
+	assert.ErrorContains(t, err, "no token provider for repositories on github.com")
+}
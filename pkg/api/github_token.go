@@ -23,10 +23,14 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
 	gh "github.com/google/go-github/v75/github"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/NissesSenap/shepherd/pkg/forge"
 )
 
 // TokenProvider generates GitHub installation tokens.
@@ -39,37 +43,79 @@ type TokenProvider interface {
 type GitHubClient struct {
 	appsTransport  *ghinstallation.AppsTransport
 	installationID int64
+	// installations finds the installation of each repository when
+	// installationID is 0.
+	installations *forge.GitHubInstallations
+	// basePath prefixes repository paths on the GitHub instance; see
+	// forge.BasePath.
+	basePath string
+
+	reposOnce      sync.Once
+	reposTransport *ghinstallation.Transport
+	repos          *gh.Client
 }
 
-// NewGitHubClient creates a new GitHub client from app credentials.
-func NewGitHubClient(appID, installationID int64, privateKeyPath string) (*GitHubClient, error) {
+// NewGitHubClient creates a new GitHub client from app credentials. With
+// installationID 0, tokens are issued by the App's installation on each
+// task's repository, so the App can be installed on several organizations.
+// baseURL points it at a GitHub Enterprise Server instance; empty means
+// github.com.
+func NewGitHubClient(appID, installationID int64, privateKeyPath, baseURL string) (*GitHubClient, error) {
 	keyData, err := os.ReadFile(privateKeyPath)
 	if err != nil {
 		return nil, fmt.Errorf("reading private key: %w", err)
 	}
 
-	atr, err := ghinstallation.NewAppsTransport(http.DefaultTransport, appID, keyData)
+	// Installation tokens and API requests all go through the apps
+	// transport's base, so they all wait out rate limits.
+	limits := forge.NewRateLimitTransport(http.DefaultTransport, "api", ctrl.Log.WithName("github"))
+	atr, err := ghinstallation.NewAppsTransport(limits, appID, keyData)
 	if err != nil {
 		return nil, fmt.Errorf("creating apps transport: %w", err)
 	}
+	apiClient, err := forge.NewGitHubClient(nil, baseURL, "")
+	if err != nil {
+		return nil, err
+	}
+	atr.BaseURL = forge.APIURL(apiClient)
 
-	return &GitHubClient{
+	c := &GitHubClient{
 		appsTransport:  atr,
 		installationID: installationID,
-	}, nil
+		basePath:       forge.BasePath(baseURL),
+	}
+	if installationID == 0 {
+		if c.installations, err = forge.NewGitHubInstallations(atr); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
 }
 
-// GetToken returns a token for the installation, optionally scoped to a repository.
+// GetToken returns a token for the installation, optionally scoped to a
+// repository. Without a fixed installation, the repository is required.
 func (c *GitHubClient) GetToken(ctx context.Context, repoURL string) (string, time.Time, error) {
-	// Create a fresh transport per call to support per-repo scoping.
-	// NewFromAppsTransport is cheap (no network call).
-	tr := ghinstallation.NewFromAppsTransport(c.appsTransport, c.installationID)
-
+	installationID := c.installationID
+	var repoName string
 	if repoURL != "" {
-		repoName, err := parseRepoName(repoURL)
+		owner, name, err := parseRepoFullName(c.basePath, repoURL)
 		if err != nil {
 			return "", time.Time{}, err
 		}
+		repoName = name
+		if c.installations != nil {
+			if installationID, err = c.installations.ID(ctx, owner, name); err != nil {
+				return "", time.Time{}, err
+			}
+		}
+	} else if c.installations != nil {
+		return "", time.Time{}, fmt.Errorf("a repository is required to find the GitHub App installation")
+	}
+
+	// Create a fresh transport per call to support per-repo scoping.
+	// NewFromAppsTransport is cheap (no network call).
+	tr := ghinstallation.NewFromAppsTransport(c.appsTransport, installationID)
+	if repoName != "" {
 		tr.InstallationTokenOptions = &gh.InstallationTokenOptions{
 			Repositories: []string{repoName},
 		}
@@ -84,19 +130,72 @@ func (c *GitHubClient) GetToken(ctx context.Context, repoURL string) (string, ti
 	return token, time.Now().Add(time.Hour), nil
 }
 
+// RepoSize returns the size of a repository in bytes, as reported by GitHub.
+func (c *GitHubClient) RepoSize(ctx context.Context, repoURL string) (int64, error) {
+	owner, name, err := parseRepoFullName(c.basePath, repoURL)
+	if err != nil {
+		return 0, err
+	}
+	repo, _, err := c.reposClient().Repositories.Get(ctx, owner, name)
+	if err != nil {
+		return 0, fmt.Errorf("getting repository: %w", err)
+	}
+	// GitHub reports the size in kilobytes.
+	return int64(repo.GetSize()) * 1024, nil
+}
+
+// reposClient returns a client authenticated as the installation, or as the
+// installation on the repository of each request. Unlike GetToken, it
+// shares its transports, so their tokens are reused until they expire.
+func (c *GitHubClient) reposClient() *gh.Client {
+	c.reposOnce.Do(func() {
+		var transport http.RoundTripper = c.installations
+		if c.installations == nil {
+			c.reposTransport = ghinstallation.NewFromAppsTransport(c.appsTransport, c.installationID)
+			transport = c.reposTransport
+		}
+		c.repos = gh.NewClient(&http.Client{Transport: transport})
+		// Talk to the API the app authenticates against.
+		if u, err := url.Parse(strings.TrimSuffix(c.appsTransport.BaseURL, "/") + "/"); err == nil {
+			c.repos.BaseURL = u
+		}
+	})
+	return c.repos
+}
+
+// CheckCredentials verifies that the app can authenticate as its
+// installation, or as the App without a fixed installation. The
+// installation token is cached, so GitHub is only asked for a new one when
+// it expires.
+func (c *GitHubClient) CheckCredentials(ctx context.Context) error {
+	if c.installations != nil {
+		return c.installations.CheckCredentials(ctx)
+	}
+	c.reposClient()
+	if _, err := c.reposTransport.Token(ctx); err != nil {
+		return fmt.Errorf("getting installation token: %w", err)
+	}
+	return nil
+}
+
 // parseRepoName extracts "repo" from "https://github.com/org/repo.git" or "https://github.com/org/repo".
-func parseRepoName(repoURL string) (string, error) {
+func parseRepoName(basePath, repoURL string) (string, error) {
+	_, name, err := parseRepoFullName(basePath, repoURL)
+	return name, err
+}
+
+// parseRepoFullName extracts "org" and "repo" from a repository URL whose
+// path starts with basePath.
+func parseRepoFullName(basePath, repoURL string) (owner, name string, err error) {
 	if repoURL == "" {
-		return "", fmt.Errorf("repo URL is required")
+		return "", "", fmt.Errorf("repo URL is required")
 	}
-	u, err := url.Parse(repoURL)
+	parts, err := forge.SplitPath(basePath, repoURL)
 	if err != nil {
-		return "", fmt.Errorf("invalid repo URL: %w", err)
+		return "", "", fmt.Errorf("invalid repo URL: %w", err)
 	}
-	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
 	if len(parts) != 2 {
-		return "", fmt.Errorf("repo URL must be owner/repo format: %s", repoURL)
+		return "", "", fmt.Errorf("repo URL must be owner/repo format: %s", repoURL)
 	}
-	name := parts[1]
-	return strings.TrimSuffix(name, ".git"), nil
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), nil
 }
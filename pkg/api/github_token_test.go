@@ -26,12 +26,16 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/forge"
 )
 
 func TestParseRepoName(t *testing.T) {
@@ -85,7 +89,7 @@ func TestParseRepoName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseRepoName(tt.repoURL)
+			got, err := parseRepoName("", tt.repoURL)
 			if tt.wantError {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errorMsg)
@@ -97,6 +101,22 @@ func TestParseRepoName(t *testing.T) {
 	}
 }
 
+func TestParseRepoFullName_Enterprise(t *testing.T) {
+	owner, name, err := parseRepoFullName("", "https://ghe.example.com/org/repo.git")
+	require.NoError(t, err)
+	assert.Equal(t, "org", owner)
+	assert.Equal(t, "repo", name)
+
+	owner, name, err = parseRepoFullName("/github", "https://proxy.example.com/github/org/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "org", owner)
+	assert.Equal(t, "repo", name)
+
+	_, _, err = parseRepoFullName("/github", "https://proxy.example.com/org/repo")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid repo URL")
+}
+
 func TestGitHubClient_GetToken(t *testing.T) {
 	// Generate a test RSA private key
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
@@ -222,6 +242,49 @@ func TestGitHubClient_GetToken_EmptyRepoURL(t *testing.T) {
 	assert.GreaterOrEqual(t, requestCount, 1, "should have made at least one request to token endpoint")
 }
 
+func TestGitHubClient_GetToken_LooksUpInstallation(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+
+	var tokenPaths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/otherorg/myrepo/installation":
+			_, _ = w.Write([]byte(`{"id":424242}`))
+		case "/app/installations/424242/access_tokens":
+			tokenPaths = append(tokenPaths, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"token":"ghs_other_org_token","expires_at":"2026-02-08T13:00:00Z"}`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	atr, err := ghinstallation.NewAppsTransport(http.DefaultTransport, 12345, privateKeyPEM)
+	require.NoError(t, err)
+	atr.BaseURL = ts.URL
+	installations, err := forge.NewGitHubInstallations(atr)
+	require.NoError(t, err)
+	client := &GitHubClient{appsTransport: atr, installations: installations}
+
+	token, _, err := client.GetToken(context.Background(), "https://github.com/otherorg/myrepo.git")
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_other_org_token", token)
+	assert.Equal(t, []string{"/app/installations/424242/access_tokens"}, tokenPaths)
+
+	_, _, err = client.GetToken(context.Background(), "")
+	assert.ErrorContains(t, err, "a repository is required")
+
+	_, _, err = client.GetToken(context.Background(), "https://github.com/unknown/repo.git")
+	assert.ErrorContains(t, err, "finding the GitHub App installation on unknown/repo")
+}
+
 func TestGitHubClient_GetToken_InvalidRepoURL(t *testing.T) {
 	// Generate a test RSA private key
 	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
@@ -286,3 +349,79 @@ func TestGitHubClient_GetToken_APIError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "getting installation token")
 }
+
+func TestGitHubClient_RepoSize(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+
+	var tokenRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app/installations/67890/access_tokens":
+			tokenRequests++
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"token":      "ghs_test_installation_token",
+				"expires_at": "2099-01-01T00:00:00Z",
+			})
+		case "/repos/myorg/myrepo":
+			assert.Equal(t, "token ghs_test_installation_token", r.Header.Get("Authorization"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"full_name": "myorg/myrepo", "size": 2048})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	atr, err := ghinstallation.NewAppsTransport(http.DefaultTransport, 12345, privateKeyPEM)
+	require.NoError(t, err)
+	atr.BaseURL = ts.URL
+	client := &GitHubClient{appsTransport: atr, installationID: 67890}
+
+	for range 2 {
+		size, err := client.RepoSize(context.Background(), "https://github.com/myorg/myrepo.git")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2048*1024), size)
+	}
+	assert.Equal(t, 1, tokenRequests, "installation token is reused")
+
+	_, err = client.RepoSize(context.Background(), "https://github.com/myorg/missing")
+	assert.ErrorContains(t, err, "getting repository")
+}
+
+func TestNewGitHubClient_Enterprise(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}), 0o600))
+
+	var tokenPath, tokenBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		tokenBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"token":      "ghs_enterprise_token",
+			"expires_at": "2026-02-08T13:00:00Z",
+		})
+	}))
+	defer ts.Close()
+
+	// An instance served below /ghe, as behind a path-routing proxy.
+	client, err := NewGitHubClient(12345, 67890, keyPath, ts.URL+"/ghe")
+	require.NoError(t, err)
+
+	token, _, err := client.GetToken(context.Background(), ts.URL+"/ghe/org/repo.git")
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_enterprise_token", token)
+	assert.Equal(t, "/ghe/api/v3/app/installations/67890/access_tokens", tokenPath)
+	assert.Contains(t, tokenBody, `"repositories":["repo"]`)
+}
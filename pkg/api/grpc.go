@@ -0,0 +1,385 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/api/shepherdv1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// grpcMaxMessageSize matches the body limit of the REST endpoints runners
+// post to.
+const grpcMaxMessageSize = 10 << 20 // 10 MiB
+
+// newGRPCServer returns a gRPC server with the limits of the REST API.
+func newGRPCServer() *grpc.Server {
+	return grpc.NewServer(grpc.MaxRecvMsgSize(grpcMaxMessageSize))
+}
+
+// grpcHandler sends gRPC requests to srv and everything else to next, so
+// that both APIs share a port.
+func grpcHandler(srv *grpc.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Streams may outlive the server's read and write timeouts.
+		rc := http.NewResponseController(w)
+		_ = rc.SetReadDeadline(time.Time{})
+		_ = rc.SetWriteDeadline(time.Time{})
+		srv.ServeHTTP(w, r)
+	})
+}
+
+// restBridge serves gRPC methods by calling the REST handlers in-process,
+// so that both APIs share their validation, policy and quota checks.
+type restBridge struct {
+	router http.Handler
+	prefix string // Path of the /api/v1 routes on router
+}
+
+// call sends a request with the JSON encoding of body, if any, to path
+// below the bridge's prefix and decodes the response into out. Error
+// responses are returned as gRPC status errors.
+func (b restBridge) call(ctx context.Context, method, path string, header http.Header, body, out any) error {
+	reqBody := io.Reader(http.NoBody)
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return status.Errorf(codes.Internal, "encoding request: %v", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, b.prefix+path, reqBody)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(logging.CorrelationIDHeader); len(ids) > 0 && req.Header.Get(logging.CorrelationIDHeader) == "" {
+			req.Header.Set(logging.CorrelationIDHeader, ids[0])
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+
+	resp := &bufferedResponse{header: http.Header{}}
+	b.router.ServeHTTP(resp, req)
+	if resp.code == 0 {
+		resp.code = http.StatusOK
+	}
+	if resp.code >= http.StatusMultipleChoices {
+		return restError(resp.code, resp.body.Bytes())
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.body.Bytes(), out); err != nil {
+		return status.Errorf(codes.Internal, "decoding response: %v", err)
+	}
+	return nil
+}
+
+// restError converts a REST error response into a gRPC status error.
+func restError(code int, body []byte) error {
+	var errResp ErrorResponse
+	msg := http.StatusText(code)
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+		msg = errResp.Error
+		if errResp.Details != "" {
+			msg += ": " + errResp.Details
+		}
+	}
+	return status.Error(grpcCode(code), msg)
+}
+
+// grpcCode returns the gRPC code of an HTTP status.
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusGone, http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	}
+	return codes.Internal
+}
+
+// bufferedResponse is an http.ResponseWriter that keeps the response of an
+// in-process request.
+type bufferedResponse struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (r *bufferedResponse) Header() http.Header { return r.header }
+
+func (r *bufferedResponse) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+}
+
+func (r *bufferedResponse) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(p)
+}
+
+// taskService implements shepherdv1.TaskService on the public port.
+type taskService struct {
+	shepherdv1.UnimplementedTaskServiceServer
+
+	rest    restBridge
+	handler *taskHandler
+	log     logr.Logger
+}
+
+func (s *taskService) CreateTask(ctx context.Context, req *shepherdv1.CreateTaskRequest) (*shepherdv1.Task, error) {
+	header := http.Header{}
+	if id := req.GetCorrelationId(); id != "" {
+		header.Set(logging.CorrelationIDHeader, id)
+	}
+	var task TaskResponse
+	if err := s.rest.call(ctx, http.MethodPost, "/tasks", header, createTaskRequestFromProto(req), &task); err != nil {
+		return nil, err
+	}
+	return taskToProto(task), nil
+}
+
+func (s *taskService) GetTask(ctx context.Context, req *shepherdv1.GetTaskRequest) (*shepherdv1.Task, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	var task TaskResponse
+	if err := s.rest.call(ctx, http.MethodGet, "/tasks/"+url.PathEscape(req.GetId()), nil, nil, &task); err != nil {
+		return nil, err
+	}
+	return taskToProto(task), nil
+}
+
+func (s *taskService) ListTasks(ctx context.Context, req *shepherdv1.ListTasksRequest) (*shepherdv1.ListTasksResponse, error) {
+	q := url.Values{}
+	for key, value := range map[string]string{
+		"repo":  req.GetRepo(),
+		"issue": req.GetIssue(),
+		"fleet": req.GetFleet(),
+		"sort":  req.GetSort(),
+		"order": req.GetOrder(),
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	if req.GetActive() {
+		q.Set("active", "true")
+	}
+	for _, phase := range req.GetPhases() {
+		q.Add("phase", phase)
+	}
+	path := "/tasks"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var tasks []TaskResponse
+	if err := s.rest.call(ctx, http.MethodGet, path, nil, nil, &tasks); err != nil {
+		return nil, err
+	}
+	resp := &shepherdv1.ListTasksResponse{}
+	for _, task := range tasks {
+		resp.Tasks = append(resp.Tasks, taskToProto(task))
+	}
+	return resp, nil
+}
+
+// WatchTaskEvents streams a task's events like the WebSocket of streamEvents.
+func (s *taskService) WatchTaskEvents(req *shepherdv1.WatchTaskEventsRequest, stream shepherdv1.TaskService_WatchTaskEventsServer) error {
+	ctx := stream.Context()
+	taskID := req.GetTaskId()
+	if taskID == "" {
+		return status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	log := s.log.WithValues(logging.TaskID, taskID)
+
+	var task toolkitv1alpha1.AgentTask
+	key := client.ObjectKey{Namespace: s.handler.namespace, Name: taskID}
+	if err := s.handler.client.Get(ctx, key, &task); err != nil {
+		if apierrors.IsNotFound(err) {
+			return status.Error(codes.NotFound, "task not found")
+		}
+		log.Error(err, "failed to get task")
+		return status.Error(codes.Internal, "failed to get task")
+	}
+
+	history, ch, unsubscribe := s.handler.eventHub.Subscribe(taskID, req.GetAfter())
+	if unsubscribe != nil {
+		defer unsubscribe()
+	}
+	send := func(e TaskEvent) error {
+		return stream.Send(&shepherdv1.WatchTaskEventsResponse{
+			Kind: &shepherdv1.WatchTaskEventsResponse_Event{Event: taskEventToProto(e)},
+		})
+	}
+	for _, e := range history {
+		if err := send(e); err != nil {
+			return err
+		}
+	}
+
+	if ch != nil {
+	live:
+		for {
+			select {
+			case e, ok := <-ch:
+				if !ok {
+					break live
+				}
+				if err := send(e); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+		// See streamEvents: a closed channel of an unfinished stream means
+		// this subscriber fell behind.
+		if !s.handler.eventHub.IsStreamDone(taskID) {
+			return status.Error(codes.ResourceExhausted, "slow consumer evicted")
+		}
+		if err := s.handler.client.Get(ctx, key, &task); err != nil {
+			log.Error(err, "failed to get task for completion")
+			return status.Error(codes.Internal, "failed to get task status")
+		}
+	}
+
+	return stream.Send(&shepherdv1.WatchTaskEventsResponse{
+		Kind: &shepherdv1.WatchTaskEventsResponse_Complete{Complete: &shepherdv1.TaskComplete{
+			TaskId: taskID,
+			Status: extractStatus(&task).Phase,
+			PrUrl:  task.Status.Result.PRURL,
+			Error:  task.Status.Result.Error,
+		}},
+	})
+}
+
+// runnerService implements shepherdv1.RunnerService on the internal port.
+type runnerService struct {
+	shepherdv1.UnimplementedRunnerServiceServer
+
+	rest restBridge
+}
+
+func (s *runnerService) GetTaskData(ctx context.Context, req *shepherdv1.GetTaskDataRequest) (*shepherdv1.TaskData, error) {
+	if req.GetTaskId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	var data TaskDataResponse
+	if err := s.rest.call(ctx, http.MethodGet, "/tasks/"+url.PathEscape(req.GetTaskId())+"/data", nil, nil, &data); err != nil {
+		return nil, err
+	}
+	return taskDataToProto(data), nil
+}
+
+func (s *runnerService) UpdateTaskStatus(ctx context.Context, req *shepherdv1.UpdateTaskStatusRequest) (*shepherdv1.UpdateTaskStatusResponse, error) {
+	if req.GetTaskId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	body := StatusUpdateRequest{
+		Event:   req.GetEvent(),
+		Message: req.GetMessage(),
+		Details: structFromProto(req.GetDetails()),
+	}
+	var resp map[string]string
+	if err := s.rest.call(ctx, http.MethodPost, "/tasks/"+url.PathEscape(req.GetTaskId())+"/status", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &shepherdv1.UpdateTaskStatusResponse{Status: resp["status"], Note: resp["note"]}, nil
+}
+
+// ReportEvents posts each message of the stream as one batch of events.
+func (s *runnerService) ReportEvents(stream shepherdv1.RunnerService_ReportEventsServer) error {
+	ctx := stream.Context()
+	header := http.Header{}
+	header.Set(EventSchemaVersionHeader, strconv.Itoa(EventSchemaVersion))
+
+	var accepted int64
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&shepherdv1.ReportEventsResponse{Accepted: accepted})
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetTaskId() == "" {
+			return status.Error(codes.InvalidArgument, "task_id is required")
+		}
+
+		body := PostEventRequest{Events: make([]TaskEvent, 0, len(req.GetEvents()))}
+		for _, e := range req.GetEvents() {
+			body.Events = append(body.Events, taskEventFromProto(e))
+		}
+		path := fmt.Sprintf("/tasks/%s/events", url.PathEscape(req.GetTaskId()))
+		if err := s.rest.call(ctx, http.MethodPost, path, header, body, nil); err != nil {
+			return err
+		}
+		accepted += int64(len(body.Events))
+	}
+}
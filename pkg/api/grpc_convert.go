@@ -0,0 +1,201 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/NissesSenap/shepherd/pkg/api/shepherdv1"
+)
+
+// Conversions between the REST types and their protobuf messages. Times
+// the REST API formats as RFC 3339 become timestamps, and durations become
+// durations; values that fail to parse are left unset.
+
+func createTaskRequestFromProto(in *shepherdv1.CreateTaskRequest) CreateTaskRequest {
+	req := CreateTaskRequest{
+		Repo:           repoFromProto(in.GetRepo()),
+		Task:           taskDetailsFromProto(in.GetTask()),
+		Callback:       in.GetCallbackUrl(),
+		Labels:         in.GetLabels(),
+		Priority:       in.GetPriority(),
+		DependsOn:      in.GetDependsOn(),
+		CallbackFormat: in.GetCallbackFormat(),
+		TemplateRef:    in.GetTemplateRef(),
+	}
+	if r := in.GetRunner(); r != nil {
+		req.Runner = &RunnerConfig{
+			SandboxTemplateName: r.GetSandboxTemplateName(),
+			ServiceAccountName:  r.GetServiceAccountName(),
+		}
+		if r.GetTimeout() != nil {
+			req.Runner.Timeout = r.GetTimeout().AsDuration().String()
+		}
+	}
+	return req
+}
+
+func repoFromProto(in *shepherdv1.Repo) RepoRequest {
+	return RepoRequest{URL: in.GetUrl(), Ref: in.GetRef()}
+}
+
+func repoToProto(in RepoRequest) *shepherdv1.Repo {
+	return &shepherdv1.Repo{Url: in.URL, Ref: in.Ref}
+}
+
+func taskDetailsFromProto(in *shepherdv1.TaskDetails) TaskRequest {
+	return TaskRequest{
+		Description: in.GetDescription(),
+		Context:     in.GetContext(),
+		SourceURL:   in.GetSourceUrl(),
+		SourceType:  in.GetSourceType(),
+		SourceID:    in.GetSourceId(),
+	}
+}
+
+func taskToProto(in TaskResponse) *shepherdv1.Task {
+	out := &shepherdv1.Task{
+		Id:        in.ID,
+		Namespace: in.Namespace,
+		Repo:      repoToProto(in.Repo),
+		Task: &shepherdv1.TaskDetails{
+			Description: in.Task.Description,
+			Context:     in.Task.Context,
+			SourceUrl:   in.Task.SourceURL,
+			SourceType:  in.Task.SourceType,
+			SourceId:    in.Task.SourceID,
+		},
+		CallbackUrl:   in.CallbackURL,
+		RequestedBy:   in.RequestedBy,
+		CorrelationId: in.CorrelationID,
+		Priority:      in.Priority,
+		DependsOn:     in.DependsOn,
+		Status: &shepherdv1.TaskStatus{
+			Phase:               in.Status.Phase,
+			Message:             in.Status.Message,
+			SandboxClaimName:    in.Status.SandboxClaimName,
+			SandboxTemplateName: in.Status.SandboxTemplateName,
+			PrUrl:               in.Status.PRURL,
+			Error:               in.Status.Error,
+			CostUsd:             in.Status.CostUSD,
+			Deadline:            timestampToProto(in.Status.Deadline),
+			RemainingSeconds:    in.Status.RemainingSeconds,
+		},
+		CreatedAt:      timestampToProto(in.CreatedAt),
+		QueuedSeconds:  in.QueuedSeconds,
+		RunningSeconds: in.RunningSeconds,
+		TotalSeconds:   in.TotalSeconds,
+	}
+	if in.CompletionTime != nil {
+		out.CompletionTime = timestampToProto(*in.CompletionTime)
+	}
+	for _, n := range in.Notes {
+		out.Notes = append(out.Notes, &shepherdv1.Note{
+			Author:    n.Author,
+			Text:      n.Text,
+			CreatedAt: timestampToProto(n.CreatedAt),
+		})
+	}
+	return out
+}
+
+func taskDataToProto(in TaskDataResponse) *shepherdv1.TaskData {
+	out := &shepherdv1.TaskData{
+		Version:      int32(in.Version),
+		TaskId:       in.TaskID,
+		Description:  in.Description,
+		Context:      in.Context,
+		SourceUrl:    in.SourceURL,
+		SourceType:   in.SourceType,
+		Repo:         repoToProto(in.Repo),
+		StartedAt:    timestampToProto(in.StartedAt),
+		Deadline:     timestampToProto(in.Deadline),
+		EventPrivacy: in.EventPrivacy,
+	}
+	if d, err := time.ParseDuration(in.Timeout); err == nil {
+		out.Timeout = durationpb.New(d)
+	}
+	return out
+}
+
+func taskEventFromProto(in *shepherdv1.TaskEvent) TaskEvent {
+	out := TaskEvent{
+		Sequence: in.GetSequence(),
+		Type:     TaskEventType(in.GetType()),
+		Summary:  in.GetSummary(),
+		Tool:     in.GetTool(),
+		Input:    structFromProto(in.GetInput()),
+		Metadata: structFromProto(in.GetMetadata()),
+	}
+	if in.GetTimestamp() != nil {
+		out.Timestamp = in.GetTimestamp().AsTime().UTC().Format(time.RFC3339Nano)
+	}
+	if o := in.GetOutput(); o != nil {
+		out.Output = &TaskEventOutput{Success: o.GetSuccess(), Summary: o.GetSummary()}
+	}
+	return out
+}
+
+func taskEventToProto(in TaskEvent) *shepherdv1.TaskEvent {
+	out := &shepherdv1.TaskEvent{
+		Sequence:  in.Sequence,
+		Timestamp: timestampToProto(in.Timestamp),
+		Type:      string(in.Type),
+		Summary:   in.Summary,
+		Tool:      in.Tool,
+		Input:     structToProto(in.Input),
+		Metadata:  structToProto(in.Metadata),
+	}
+	if in.Output != nil {
+		out.Output = &shepherdv1.TaskEventOutput{Success: in.Output.Success, Summary: in.Output.Summary}
+	}
+	return out
+}
+
+// timestampToProto parses an RFC 3339 time, returning nil for an empty or
+// invalid one.
+func timestampToProto(s string) *timestamppb.Timestamp {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func structFromProto(in *structpb.Struct) map[string]any {
+	if in == nil {
+		return nil
+	}
+	return in.AsMap()
+}
+
+// structToProto converts a decoded JSON object, returning nil for values
+// that have no protobuf equivalent.
+func structToProto(in map[string]any) *structpb.Struct {
+	if in == nil {
+		return nil
+	}
+	out, err := structpb.NewStruct(in)
+	if err != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/api/shepherdv1"
+)
+
+// testGRPCConn serves both gRPC services of h over an in-memory listener.
+func testGRPCConn(t *testing.T, h *taskHandler) *grpc.ClientConn {
+	t.Helper()
+	router := testRouter(h)
+	bridge := restBridge{router: router, prefix: "/api/v1"}
+
+	srv := newGRPCServer()
+	shepherdv1.RegisterTaskServiceServer(srv, &taskService{rest: bridge, handler: h, log: logr.Discard()})
+	shepherdv1.RegisterRunnerServiceServer(srv, &runnerService{rest: bridge})
+
+	ln := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestGRPC_CreateAndGetTask(t *testing.T) {
+	h := newTestHandler()
+	tasks := shepherdv1.NewTaskServiceClient(testGRPCConn(t, h))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	created, err := tasks.CreateTask(ctx, &shepherdv1.CreateTaskRequest{
+		Repo:          &shepherdv1.Repo{Url: "https://github.com/test-org/test-repo"},
+		Task:          &shepherdv1.TaskDetails{Description: "Fix the login bug", Context: "Issue #42"},
+		CallbackUrl:   "https://example.com/callback",
+		Runner:        &shepherdv1.RunnerConfig{SandboxTemplateName: "default-template", Timeout: durationpb.New(45 * time.Minute)},
+		Labels:        map[string]string{"shepherd.io/repo": "test-org-test-repo"},
+		CorrelationId: "corr-grpc",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.GetId())
+	assert.Equal(t, "corr-grpc", created.GetCorrelationId())
+	assert.Equal(t, string(toolkitv1alpha1.PhasePending), created.GetStatus().GetPhase())
+
+	got, err := tasks.GetTask(ctx, &shepherdv1.GetTaskRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/test-org/test-repo", got.GetRepo().GetUrl())
+	assert.Equal(t, "Fix the login bug", got.GetTask().GetDescription())
+	assert.NotNil(t, got.GetCreatedAt())
+
+	list, err := tasks.ListTasks(ctx, &shepherdv1.ListTasksRequest{Repo: "https://github.com/test-org/test-repo"})
+	require.NoError(t, err)
+	require.Len(t, list.GetTasks(), 1)
+	assert.Equal(t, created.GetId(), list.GetTasks()[0].GetId())
+}
+
+func TestGRPC_ErrorCodes(t *testing.T) {
+	h := newTestHandler()
+	tasks := shepherdv1.NewTaskServiceClient(testGRPCConn(t, h))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := tasks.GetTask(ctx, &shepherdv1.GetTaskRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = tasks.CreateTask(ctx, &shepherdv1.CreateTaskRequest{
+		Task: &shepherdv1.TaskDetails{Description: "No repository"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPC_ReportAndWatchEvents(t *testing.T) {
+	task := newTask("task-grpc", nil, []metav1.Condition{
+		{
+			Type:   toolkitv1alpha1.ConditionSucceeded,
+			Status: metav1.ConditionUnknown,
+			Reason: toolkitv1alpha1.ReasonRunning,
+		},
+	})
+	h := newTestHandler(task)
+	conn := testGRPCConn(t, h)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	report, err := shepherdv1.NewRunnerServiceClient(conn).ReportEvents(ctx)
+	require.NoError(t, err)
+	for seq := int64(1); seq <= 2; seq++ {
+		require.NoError(t, report.Send(&shepherdv1.ReportEventsRequest{
+			TaskId: "task-grpc",
+			Events: []*shepherdv1.TaskEvent{{
+				Sequence:  seq,
+				Timestamp: timestamppb.New(time.Date(2026, 1, 1, 0, 0, int(seq), 0, time.UTC)),
+				Type:      string(EventTypeThinking),
+				Summary:   "Analyzing",
+			}},
+		}))
+	}
+	resp, err := report.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resp.GetAccepted())
+
+	watch, err := shepherdv1.NewTaskServiceClient(conn).WatchTaskEvents(ctx, &shepherdv1.WatchTaskEventsRequest{TaskId: "task-grpc", After: 1})
+	require.NoError(t, err)
+	msg, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), msg.GetEvent().GetSequence())
+	assert.Equal(t, "Analyzing", msg.GetEvent().GetSummary())
+
+	h.eventHub.Complete("task-grpc")
+	msg, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "task-grpc", msg.GetComplete().GetTaskId())
+	_, err = watch.Recv()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestGRPCHandler_FallsBackToREST(t *testing.T) {
+	rest := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := grpcHandler(newGRPCServer(), rest)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+	req.Header.Set("Content-Type", "application/grpc")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	// HTTP/1 requests never reach gRPC, whatever their content type.
+	assert.Equal(t, http.StatusTeapot, w.Code)
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// adminCancelledMessage is the failure message of tasks cancelled through
+// the admin API.
+const adminCancelledMessage = "Cancelled by an administrator"
+
+// errAlreadyFinished is returned when a task finished before it could be
+// cancelled.
+var errAlreadyFinished = errors.New("task already finished")
+
+// adminRoutes registers the endpoints used during incidents, such as an
+// adapter flooding the API with bad tasks, and for maintenance.
+func (h *taskHandler) adminRoutes(r chi.Router) {
+	r.Post("/tasks/cancel", h.cancelTasks)
+	r.Delete("/tasks", h.deleteTasks)
+	r.Put("/maintenance", h.setMaintenance)
+	r.Delete("/maintenance", h.endMaintenance)
+}
+
+// cancelTasks handles POST /api/v1/admin/tasks/cancel.
+// Query parameters:
+//   - selector: label selector of the tasks to cancel (required)
+//   - dryRun: if "true", only report the tasks that would be cancelled
+//
+// Unfinished tasks are marked Cancelled; the operator releases their
+// sandboxes and the status watcher sends the adapters a "cancelled"
+// callback, as for a deleted task.
+func (h *taskHandler) cancelTasks(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10) // 64 KiB
+	var req CancelTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+	message := adminCancelledMessage
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		message += ": " + reason
+	}
+
+	tasks, ok := h.listAdminTasks(w, r)
+	if !ok {
+		return
+	}
+	resp := BulkTaskResponse{DryRun: r.URL.Query().Get("dryRun") == "true", Tasks: []string{}}
+	for i := range tasks {
+		task := &tasks[i]
+		if task.IsTerminal() {
+			continue
+		}
+		resp.Matched++
+		if resp.DryRun {
+			resp.Tasks = append(resp.Tasks, task.Name)
+			continue
+		}
+		_, err := h.markCancelled(r, task.Name, message)
+		switch {
+		case errors.Is(err, errAlreadyFinished):
+			resp.Matched--
+			continue
+		case err != nil:
+			log.Error(err, "failed to cancel task", logging.TaskID, task.Name)
+			resp.Errors = append(resp.Errors, BulkTaskError{TaskID: task.Name, Error: err.Error()})
+			continue
+		}
+		resp.Tasks = append(resp.Tasks, task.Name)
+		h.completeEventStream(task.Name)
+	}
+
+	log.Info("bulk cancel", "selector", r.URL.Query().Get("selector"), "dryRun", resp.DryRun,
+		"matched", resp.Matched, "cancelled", len(resp.Tasks), "failed", len(resp.Errors))
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// markCancelled marks the named task Cancelled with message, unless it
+// finished in the meantime.
+func (h *taskHandler) markCancelled(r *http.Request, name, message string) (*toolkitv1alpha1.AgentTask, error) {
+	return retryStatusUpdate(r.Context(), h.tasks, name, func(task *toolkitv1alpha1.AgentTask) error {
+		if task.IsTerminal() {
+			return errAlreadyFinished
+		}
+		now := metav1.Now()
+		task.Status.CompletionTime = &now
+		task.Status.GraceDeadline = nil
+		apimeta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+			Type:               toolkitv1alpha1.ConditionSucceeded,
+			Status:             metav1.ConditionFalse,
+			Reason:             toolkitv1alpha1.ReasonCancelled,
+			Message:            message,
+			ObservedGeneration: task.Generation,
+		})
+		task.Status.Phase = task.ComputePhase()
+		return nil
+	})
+}
+
+// completeEventStream tells the event stream subscribers of a task that
+// finished without a runner report that it is complete, and drops its
+// buffered events five minutes later.
+func (h *taskHandler) completeEventStream(taskID string) {
+	if h.eventHub == nil {
+		return
+	}
+	h.eventHub.Complete(taskID)
+	go func() {
+		time.Sleep(5 * time.Minute)
+		h.eventHub.Cleanup(taskID)
+	}()
+}
+
+// deleteTasks handles DELETE /api/v1/admin/tasks.
+// Query parameters:
+//   - selector: label selector of the tasks to delete (required)
+//   - olderThan: only delete tasks that finished at least this long ago,
+//     as a duration such as "24h"
+//   - dryRun: if "true", only report the tasks that would be deleted
+//
+// Only finished tasks are deleted; cancel unfinished ones first.
+func (h *taskHandler) deleteTasks(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+
+	var olderThan time.Duration
+	if v := r.URL.Query().Get("olderThan"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			writeError(w, http.StatusBadRequest, "invalid olderThan", fmt.Sprintf("%q is not a duration such as 24h", v))
+			return
+		}
+		olderThan = d
+	}
+
+	tasks, ok := h.listAdminTasks(w, r)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	resp := BulkTaskResponse{DryRun: r.URL.Query().Get("dryRun") == "true", Tasks: []string{}}
+	for i := range tasks {
+		task := &tasks[i]
+		if !task.IsTerminal() || now.Sub(finishedAt(task)) < olderThan {
+			continue
+		}
+		resp.Matched++
+		if resp.DryRun {
+			resp.Tasks = append(resp.Tasks, task.Name)
+			continue
+		}
+		if err := h.tasks.Delete(r.Context(), task); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "failed to delete task", logging.TaskID, task.Name)
+			resp.Errors = append(resp.Errors, BulkTaskError{TaskID: task.Name, Error: err.Error()})
+			continue
+		}
+		resp.Tasks = append(resp.Tasks, task.Name)
+	}
+
+	log.Info("bulk delete", "selector", r.URL.Query().Get("selector"), "olderThan", olderThan, "dryRun", resp.DryRun,
+		"matched", resp.Matched, "deleted", len(resp.Tasks), "failed", len(resp.Errors))
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// listAdminTasks lists the tasks matching the request's selector. A
+// selector is required, so a forgotten parameter cannot act on every task;
+// "shepherd.io/repo" matches all tasks created through the API. It writes
+// the error response and returns false if the selector is missing or
+// invalid.
+func (h *taskHandler) listAdminTasks(w http.ResponseWriter, r *http.Request) ([]toolkitv1alpha1.AgentTask, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("selector"))
+	if raw == "" {
+		writeError(w, http.StatusBadRequest, "selector is required", "e.g. selector=shepherd.io/repo=acme-app")
+		return nil, false
+	}
+	selector, err := labels.Parse(raw)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid selector", err.Error())
+		return nil, false
+	}
+
+	tasks, err := h.tasks.List(r.Context(), selector, ReadConsistent)
+	if err != nil {
+		logf.FromContext(r.Context()).Error(err, "failed to list tasks")
+		writeError(w, http.StatusInternalServerError, "failed to list tasks", "")
+		return nil, false
+	}
+	return tasks, true
+}
+
+// finishedAt returns when a finished task finished: its completion time,
+// or for tasks the operator failed without one, when its Succeeded
+// condition last changed.
+func finishedAt(task *toolkitv1alpha1.AgentTask) time.Time {
+	if task.Status.CompletionTime != nil {
+		return task.Status.CompletionTime.Time
+	}
+	if cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded); cond != nil {
+		return cond.LastTransitionTime.Time
+	}
+	return task.CreationTimestamp.Time
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// adminTask returns a task of repo in the phase given by status and reason,
+// finished at finished if it is terminal.
+func adminTask(name, repo string, status metav1.ConditionStatus, reason string, finished time.Time) *toolkitv1alpha1.AgentTask {
+	task := newTask(name, map[string]string{toolkitv1alpha1.RepoLabel: repo}, []metav1.Condition{{
+		Type:               toolkitv1alpha1.ConditionSucceeded,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: metav1.NewTime(finished),
+	}})
+	if status != metav1.ConditionUnknown {
+		task.Status.CompletionTime = &metav1.Time{Time: finished}
+	}
+	return task
+}
+
+func decodeBulk(t *testing.T, w *httptest.ResponseRecorder) BulkTaskResponse {
+	t.Helper()
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp BulkTaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestCancelTasks(t *testing.T) {
+	now := time.Now()
+	h := newTestHandler(
+		adminTask("task-running", "acme-app", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, now),
+		adminTask("task-queued", "acme-app", metav1.ConditionUnknown, toolkitv1alpha1.ReasonQueued, now),
+		adminTask("task-done", "acme-app", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded, now),
+		adminTask("task-other", "acme-web", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, now),
+	)
+	router := testRouter(h)
+	doc := loadSpec(t)
+
+	path := "/api/v1/admin/tasks/cancel?selector=shepherd.io/repo%3Dacme-app&dryRun=true"
+	w := postJSON(t, router, path, CancelTasksRequest{})
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Content-Type", "application/json")
+	validateResponse(t, doc, req, w)
+	resp := decodeBulk(t, w)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, 2, resp.Matched)
+	assert.ElementsMatch(t, []string{"task-running", "task-queued"}, resp.Tasks)
+
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "task-running"}, &task))
+	assert.False(t, task.IsTerminal(), "a dry run changes nothing")
+
+	path = "/api/v1/admin/tasks/cancel?selector=shepherd.io/repo%3Dacme-app"
+	w = postJSON(t, router, path, CancelTasksRequest{Reason: "adapter flood"})
+	resp = decodeBulk(t, w)
+	assert.False(t, resp.DryRun)
+	assert.Equal(t, 2, resp.Matched)
+	assert.ElementsMatch(t, []string{"task-running", "task-queued"}, resp.Tasks)
+	assert.Empty(t, resp.Errors)
+
+	for _, name := range []string{"task-running", "task-queued"} {
+		require.NoError(t, h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, &task))
+		cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+		require.NotNil(t, cond)
+		assert.Equal(t, toolkitv1alpha1.ReasonCancelled, cond.Reason, name)
+		assert.Equal(t, "Cancelled by an administrator: adapter flood", cond.Message)
+		assert.Equal(t, toolkitv1alpha1.PhaseCancelled, task.Status.Phase)
+		assert.NotNil(t, task.Status.CompletionTime)
+	}
+	require.NoError(t, h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "task-done"}, &task))
+	assert.Equal(t, toolkitv1alpha1.ReasonSucceeded,
+		apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded).Reason)
+	require.NoError(t, h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "task-other"}, &task))
+	assert.False(t, task.IsTerminal())
+}
+
+func TestCancelTasks_RunnerReportAfterCancel(t *testing.T) {
+	task := adminTask("task-1", "acme-app", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, time.Now())
+	h := newTestHandler(task)
+	router := testRouter(h)
+
+	w := postJSON(t, router, "/api/v1/admin/tasks/cancel?selector=shepherd.io/repo", nil)
+	assert.Equal(t, []string{"task-1"}, decodeBulk(t, w).Tasks)
+
+	w = postJSON(t, router, "/api/v1/tasks/task-1/status", StatusUpdateRequest{Event: EventCompleted, Message: "done"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "task cancelled")
+
+	var got toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(t.Context(), client.ObjectKeyFromObject(task), &got))
+	assert.Equal(t, toolkitv1alpha1.ReasonCancelled,
+		apimeta.FindStatusCondition(got.Status.Conditions, toolkitv1alpha1.ConditionSucceeded).Reason)
+}
+
+func TestDeleteTasks(t *testing.T) {
+	now := time.Now()
+	h := newTestHandler(
+		adminTask("task-old", "acme-app", metav1.ConditionFalse, toolkitv1alpha1.ReasonFailed, now.Add(-48*time.Hour)),
+		adminTask("task-recent", "acme-app", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded, now.Add(-time.Hour)),
+		adminTask("task-running", "acme-app", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, now.Add(-72*time.Hour)),
+	)
+	router := testRouter(h)
+
+	path := "/api/v1/admin/tasks?selector=shepherd.io/repo%3Dacme-app&olderThan=24h"
+	req := httptest.NewRequest(http.MethodDelete, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	validateResponse(t, loadSpec(t), req, w)
+	resp := decodeBulk(t, w)
+	assert.Equal(t, 1, resp.Matched)
+	assert.Equal(t, []string{"task-old"}, resp.Tasks)
+
+	var task toolkitv1alpha1.AgentTask
+	err := h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "task-old"}, &task)
+	assert.True(t, apierrors.IsNotFound(err))
+	for _, name := range []string{"task-recent", "task-running"} {
+		assert.NoError(t, h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: name}, &task))
+	}
+
+	// Without olderThan every finished task matches.
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/admin/tasks?selector=shepherd.io/repo&dryRun=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	resp = decodeBulk(t, w)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, []string{"task-recent"}, resp.Tasks)
+}
+
+func TestAdminTasks_InvalidRequests(t *testing.T) {
+	router := testRouter(newTestHandler())
+	doc := loadSpec(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{"cancel without selector", http.MethodPost, "/api/v1/admin/tasks/cancel"},
+		{"cancel with invalid selector", http.MethodPost, "/api/v1/admin/tasks/cancel?selector=a%20b%20c"},
+		{"delete without selector", http.MethodDelete, "/api/v1/admin/tasks"},
+		{"delete with invalid olderThan", http.MethodDelete, "/api/v1/admin/tasks?selector=shepherd.io/repo&olderThan=yesterday"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			validateResponse(t, doc, req, w)
+		})
+	}
+}
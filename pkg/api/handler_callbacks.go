@@ -0,0 +1,123 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+)
+
+// getCallbacks handles GET /api/v1/tasks/{taskID}/callbacks.
+func (h *taskHandler) getCallbacks(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	taskID := chi.URLParam(r, "taskID")
+
+	task, err := h.tasks.Get(r.Context(), taskID, ReadConsistent)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			writeError(w, http.StatusNotFound, "task not found", "")
+			return
+		}
+		log.Error(err, "failed to get task", logging.TaskID, taskID)
+		writeError(w, http.StatusInternalServerError, "failed to get task", "")
+		return
+	}
+
+	resp := CallbackHistoryResponse{
+		FailedAttempts: callbackAttempts(task),
+		Attempts:       make([]CallbackAttemptResponse, 0, len(task.Status.CallbackHistory)),
+	}
+	if cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionNotified); cond != nil {
+		resp.Status = cond.Reason
+		resp.Message = cond.Message
+	}
+	for _, attempt := range task.Status.CallbackHistory {
+		resp.Attempts = append(resp.Attempts, callbackAttemptToResponse(attempt))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// replayCallback handles POST /api/v1/tasks/{taskID}/callbacks/replay. It
+// sends the terminal callback of a finished task again, whether or not it
+// was delivered before, and records the attempt. A delivered replay marks
+// the task notified, which ends the automatic retries.
+func (h *taskHandler) replayCallback(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	taskID := chi.URLParam(r, "taskID")
+	task, err := h.tasks.Get(r.Context(), taskID, ReadConsistent)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			writeError(w, http.StatusNotFound, "task not found", "")
+			return
+		}
+		log.Error(err, "failed to get task", logging.TaskID, taskID)
+		writeError(w, http.StatusInternalServerError, "failed to get task", "")
+		return
+	}
+	log = logging.ForTask(log.WithValues(logging.TaskID, taskID), task.Annotations)
+	if !task.IsTerminal() {
+		writeError(w, http.StatusConflict, "task has not finished", "only the terminal callback can be replayed")
+		return
+	}
+	payload, ok := terminalCallbackPayload(task)
+	if !ok {
+		writeError(w, http.StatusConflict, "task has not finished", "")
+		return
+	}
+
+	ctx, span := tracing.Tracer().Start(tracing.FromAnnotations(r.Context(), task.Annotations), "replay terminal callback")
+	defer span.End()
+	code, callbackErr := h.callback.deliver(ctx, task.Spec.Callback, payload)
+	attempt := newCallbackAttempt(payload.Event, time.Now(), code, callbackErr, true)
+
+	// The history list is replaced as a whole, so re-read on conflict.
+	_, err = retryStatusUpdate(ctx, h.tasks, taskID, func(fresh *toolkitv1alpha1.AgentTask) error {
+		recordCallbackAttempt(fresh, attempt)
+		if callbackErr == nil {
+			apimeta.SetStatusCondition(&fresh.Status.Conditions, metav1.Condition{
+				Type:               toolkitv1alpha1.ConditionNotified,
+				Status:             metav1.ConditionTrue,
+				Reason:             toolkitv1alpha1.ReasonCallbackSent,
+				Message:            fmt.Sprintf("Adapter notified: %s (replayed)", payload.Event),
+				ObservedGeneration: fresh.Generation,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "failed to record replayed callback")
+	}
+
+	if callbackErr != nil {
+		log.Error(callbackErr, "replayed callback failed", "callbackURL", task.Spec.Callback.URL)
+		writeError(w, http.StatusBadGateway, "callback failed", callbackErr.Error())
+		return
+	}
+	log.Info("replayed terminal callback", "event", payload.Event, "callbackURL", task.Spec.Callback.URL)
+	writeJSON(w, http.StatusOK, callbackAttemptToResponse(attempt))
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestCallbackHistoryAndReplay(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	var received atomic.Int32
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		received.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer adapter.Close()
+
+	h := newTestHandlerWithCallback("test-secret", statusTask("task-abc", adapter.URL, nil))
+	router := testRouter(h)
+	doc := loadSpec(t)
+
+	w := postJSON(t, router, "/api/v1/tasks/task-abc/status", StatusUpdateRequest{Event: EventCompleted, Message: "done"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = doGet(t, router, "/api/v1/tasks/task-abc/callbacks")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, doc, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task-abc/callbacks", nil), w)
+	var history CallbackHistoryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &history))
+	assert.Equal(t, toolkitv1alpha1.ReasonCallbackFailed, history.Status)
+	assert.Equal(t, 1, history.FailedAttempts)
+	require.Len(t, history.Attempts, 1)
+	assert.Equal(t, EventCompleted, history.Attempts[0].Event)
+	assert.Equal(t, http.StatusServiceUnavailable, history.Attempts[0].StatusCode)
+	assert.Contains(t, history.Attempts[0].Error, "returned status 503")
+	assert.False(t, history.Attempts[0].Replay)
+
+	// A replay while the adapter is still down fails and is recorded.
+	w = postJSON(t, router, "/api/v1/tasks/task-abc/callbacks/replay", nil)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	down.Store(false)
+	w = postJSON(t, router, "/api/v1/tasks/task-abc/callbacks/replay", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task-abc/callbacks/replay", nil)
+	req.Header.Set("Content-Type", "application/json")
+	validateResponse(t, doc, req, w)
+	var attempt CallbackAttemptResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &attempt))
+	assert.Equal(t, http.StatusAccepted, attempt.StatusCode)
+	assert.Empty(t, attempt.Error)
+	assert.True(t, attempt.Replay)
+	assert.Equal(t, int32(3), received.Load())
+
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "task-abc"}, &task))
+	notified := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionNotified)
+	require.NotNil(t, notified)
+	assert.Equal(t, toolkitv1alpha1.ReasonCallbackSent, notified.Reason, "a delivered replay ends the retries")
+	require.Len(t, task.Status.CallbackHistory, 3)
+	assert.True(t, task.Status.CallbackHistory[1].Replay)
+	assert.NotEmpty(t, task.Status.CallbackHistory[1].Error)
+}
+
+func TestReplayCallback_Errors(t *testing.T) {
+	running := statusTask("task-running", "http://127.0.0.1:1", []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionUnknown,
+		Reason: toolkitv1alpha1.ReasonRunning,
+	}})
+	router := testRouter(newTestHandlerWithCallback("", running))
+	doc := loadSpec(t)
+
+	for path, code := range map[string]int{
+		"/api/v1/tasks/task-running/callbacks/replay": http.StatusConflict,
+		"/api/v1/tasks/missing/callbacks/replay":      http.StatusNotFound,
+	} {
+		w := postJSON(t, router, path, nil)
+		assert.Equal(t, code, w.Code, path)
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Content-Type", "application/json")
+		validateResponse(t, doc, req, w)
+	}
+
+	w := doGet(t, router, "/api/v1/tasks/missing/callbacks")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRecordCallbackAttempt(t *testing.T) {
+	task := &toolkitv1alpha1.AgentTask{}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range maxCallbackHistory + 5 {
+		recordCallbackAttempt(task, newCallbackAttempt(EventFailed, start.Add(time.Duration(i)*time.Minute), 0, errors.New("refused"), false))
+	}
+	require.Len(t, task.Status.CallbackHistory, maxCallbackHistory)
+	assert.Equal(t, start.Add(5*time.Minute), task.Status.CallbackHistory[0].Time.Time, "the oldest attempts are dropped")
+
+	attempt := newCallbackAttempt(EventCompleted, start, http.StatusBadGateway, errors.New(strings.Repeat("x", 5000)), true)
+	assert.Len(t, attempt.Error, maxCallbackErrorLength)
+	assert.Equal(t, int32(http.StatusBadGateway), attempt.StatusCode)
+}
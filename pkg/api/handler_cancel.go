@@ -0,0 +1,85 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// cancelledMessage is the failure message of tasks cancelled through
+// POST /api/v1/tasks/{taskID}/cancel.
+const cancelledMessage = "Cancelled through the API"
+
+// maxCancelReasonLength limits the reason given for cancelling a task.
+const maxCancelReasonLength = 200
+
+// cancelTask handles POST /api/v1/tasks/{taskID}/cancel.
+//
+// The task is marked Cancelled; as with the admin bulk cancel, the operator
+// releases its sandbox and the status watcher sends the adapter a
+// "cancelled" callback.
+func (h *taskHandler) cancelTask(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	taskID := chi.URLParam(r, "taskID")
+
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10) // 64 KiB
+	var req CancelTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if n := utf8.RuneCountInString(req.Reason); n > maxCancelReasonLength {
+		writeError(w, http.StatusBadRequest, "reason is too long",
+			fmt.Sprintf("%d characters exceeds the limit of %d", n, maxCancelReasonLength))
+		return
+	}
+	message := cancelledMessage
+	if req.Reason != "" {
+		message += ": " + req.Reason
+	}
+
+	task, err := h.markCancelled(r, taskID, message)
+	switch {
+	case errors.Is(err, errAlreadyFinished):
+		writeError(w, http.StatusGone, "task is terminal", "")
+		return
+	case apierrors.IsNotFound(err):
+		writeError(w, http.StatusNotFound, "task not found", "")
+		return
+	case err != nil:
+		log.Error(err, "failed to cancel task", logging.TaskID, taskID)
+		writeError(w, http.StatusInternalServerError, "failed to cancel task", "")
+		return
+	}
+	h.completeEventStream(taskID)
+
+	log.Info("cancelled task", logging.TaskID, taskID, "reason", req.Reason)
+	writeJSON(w, http.StatusOK, taskToResponse(task))
+}
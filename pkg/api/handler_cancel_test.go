@@ -0,0 +1,93 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestCancelTask(t *testing.T) {
+	h := newTestHandler(adminTask("task-1", "acme-app", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, time.Now()))
+	h.eventHub.Publish("task-1", []TaskEvent{{Sequence: 1, Type: EventTypeThinking, Summary: "Reading"}})
+	router := testRouter(h)
+
+	w := postJSON(t, router, "/api/v1/tasks/task-1/cancel", CancelTaskRequest{Reason: "  wrong repository "})
+	require.Equal(t, http.StatusOK, w.Code)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task-1/cancel", nil)
+	req.Header.Set("Content-Type", "application/json")
+	validateResponse(t, loadSpec(t), req, w)
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, string(toolkitv1alpha1.PhaseCancelled), resp.Status.Phase)
+
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "task-1"}, &task))
+	cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	require.NotNil(t, cond)
+	assert.Equal(t, toolkitv1alpha1.ReasonCancelled, cond.Reason)
+	assert.Equal(t, "Cancelled through the API: wrong repository", cond.Message)
+	assert.True(t, h.eventHub.IsStreamDone("task-1"), "event stream subscribers are told the task finished")
+
+	// Without a body.
+	h = newTestHandler(adminTask("task-2", "acme-app", metav1.ConditionUnknown, toolkitv1alpha1.ReasonQueued, time.Now()))
+	w = postJSON(t, testRouter(h), "/api/v1/tasks/task-2/cancel", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: "task-2"}, &task))
+	assert.Equal(t, cancelledMessage,
+		apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded).Message)
+}
+
+func TestCancelTask_Rejected(t *testing.T) {
+	h := newTestHandler(
+		adminTask("task-1", "acme-app", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, time.Now()),
+		adminTask("task-done", "acme-app", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded, time.Now()),
+	)
+	router := testRouter(h)
+
+	tests := []struct {
+		name string
+		path string
+		body any
+		code int
+	}{
+		{"unknown task", "/api/v1/tasks/nope/cancel", nil, http.StatusNotFound},
+		{"terminal task", "/api/v1/tasks/task-done/cancel", nil, http.StatusGone},
+		{"long reason", "/api/v1/tasks/task-1/cancel",
+			CancelTaskRequest{Reason: strings.Repeat("x", maxCancelReasonLength+1)}, http.StatusBadRequest},
+		{"invalid body", "/api/v1/tasks/task-1/cancel", "not an object", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(t, router, tt.path, tt.body)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -18,33 +18,35 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"k8s.io/apimachinery/pkg/api/errors"
-	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
-	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
 )
 
 // getTaskData handles GET /api/v1/tasks/{taskID}/data.
-// Returns decompressed task description, context, repo info.
+// Returns decompressed task description, context, repo info and the time
+// budget of the run.
 // TODO: Authenticate via per-task bearer token (see #22)
 func (h *taskHandler) getTaskData(w http.ResponseWriter, r *http.Request) {
-	log := ctrl.Log.WithName("api")
+	log := logf.FromContext(r.Context())
 	taskID := chi.URLParam(r, "taskID")
 
-	var task toolkitv1alpha1.AgentTask
-	key := client.ObjectKey{Namespace: h.namespace, Name: taskID}
-	if err := h.client.Get(r.Context(), key, &task); err != nil {
+	task, err := h.tasks.Get(r.Context(), taskID, ReadConsistent)
+	if err != nil {
 		if errors.IsNotFound(err) {
 			writeError(w, http.StatusNotFound, "task not found", "")
 			return
 		}
-		log.Error(err, "failed to get task", "taskID", taskID)
+		log.Error(err, "failed to get task", logging.TaskID, taskID)
 		writeError(w, http.StatusInternalServerError, "failed to get task", "")
 		return
 	}
+	log = logging.ForTask(log.WithValues(logging.TaskID, taskID), task.Annotations)
+	log.V(1).Info("serving task data")
 
 	if task.IsTerminal() {
 		writeError(w, http.StatusGone, "task is terminal", "")
@@ -53,19 +55,40 @@ func (h *taskHandler) getTaskData(w http.ResponseWriter, r *http.Request) {
 
 	context, err := decompressContext(task.Spec.Task.Context, task.Spec.Task.ContextEncoding)
 	if err != nil {
-		log.Error(err, "failed to decompress context", "taskID", taskID)
+		log.Error(err, "failed to decompress context")
 		writeError(w, http.StatusInternalServerError, "failed to decompress context", "")
 		return
 	}
 
+	timeout := task.RunnerTimeout()
 	resp := TaskDataResponse{
+		Version:     1, // see TaskDataVersion
+		TaskID:      task.Name,
 		Description: task.Spec.Task.Description,
 		Context:     context,
 		SourceURL:   task.Spec.Task.SourceURL,
+		SourceType:  task.Spec.Task.SourceType,
 		Repo: RepoRequest{
 			URL: task.Spec.Repo.URL,
 			Ref: task.Spec.Repo.Ref,
 		},
+		Timeout: timeout.String(),
+		Model:   task.Spec.Runner.Model,
+	}
+	if task.Spec.Task.SourceType != SourceTypePullRequest {
+		resp.Branch = task.BranchName()
+	}
+	if level := h.eventPrivacy.Level(task.Spec.Repo.URL); level != EventPrivacyFull {
+		resp.Version = 2
+		resp.EventPrivacy = level
+	}
+	if mode := task.Labels[ModeLabel]; mode != "" && mode != ModeFix {
+		resp.Version = TaskDataVersion
+		resp.Mode = mode
+	}
+	if start := task.Status.StartTime; start != nil {
+		resp.StartedAt = start.UTC().Format(time.RFC3339)
+		resp.Deadline = start.Add(timeout).UTC().Format(time.RFC3339)
 	}
 
 	writeJSON(w, http.StatusOK, resp)
@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"time"
 )
 
 func TestGetTaskData_ReturnsDecompressedContext(t *testing.T) {
@@ -46,6 +47,7 @@ func TestGetTaskData_ReturnsDecompressedContext(t *testing.T) {
 				Context:         compressed,
 				ContextEncoding: encoding,
 				SourceURL:       "https://github.com/org/repo/issues/42",
+				SourceType:      "issue",
 			},
 			Callback: toolkitv1alpha1.CallbackSpec{URL: "https://example.com/cb"},
 		},
@@ -65,11 +67,49 @@ func TestGetTaskData_ReturnsDecompressedContext(t *testing.T) {
 
 	var resp TaskDataResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Version, "tasks with full event privacy stay at version 1")
+	assert.Equal(t, "task-data-1", resp.TaskID)
 	assert.Equal(t, "Fix the login bug", resp.Description)
 	assert.Equal(t, "Additional context for the task", resp.Context)
 	assert.Equal(t, "https://github.com/org/repo/issues/42", resp.SourceURL)
+	assert.Equal(t, "issue", resp.SourceType)
 	assert.Equal(t, "https://github.com/org/repo", resp.Repo.URL)
 	assert.Equal(t, "main", resp.Repo.Ref)
+	assert.Equal(t, "30m0s", resp.Timeout, "falls back to the default timeout")
+	assert.Empty(t, resp.StartedAt, "not started yet")
+	assert.Empty(t, resp.Deadline)
+}
+
+func TestGetTaskData_Deadline(t *testing.T) {
+	start := metav1.NewTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	task := &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "task-running",
+			Namespace: "default",
+		},
+		Spec: toolkitv1alpha1.AgentTaskSpec{
+			Repo:     toolkitv1alpha1.RepoSpec{URL: "https://github.com/org/repo"},
+			Task:     toolkitv1alpha1.TaskSpec{Description: "A task"},
+			Callback: toolkitv1alpha1.CallbackSpec{URL: "https://example.com/cb"},
+			Runner: toolkitv1alpha1.RunnerSpec{
+				SandboxTemplateName: "default",
+				Timeout:             metav1.Duration{Duration: 2 * time.Hour},
+			},
+		},
+		Status: toolkitv1alpha1.AgentTaskStatus{StartTime: &start},
+	}
+
+	h := newTestHandler(task)
+	w := doGet(t, testRouter(h), "/api/v1/tasks/task-running/data")
+	require.Equal(t, http.StatusOK, w.Code)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task-running/data", nil)
+	validateResponse(t, loadSpec(t), req, w)
+
+	var resp TaskDataResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "2h0m0s", resp.Timeout)
+	assert.Equal(t, "2026-03-01T12:00:00Z", resp.StartedAt)
+	assert.Equal(t, "2026-03-01T14:00:00Z", resp.Deadline)
 }
 
 func TestGetTaskData_NotFound(t *testing.T) {
@@ -171,3 +211,106 @@ func TestGetTaskData_TerminalTaskRejected(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
 	assert.Equal(t, "task is terminal", errResp.Error)
 }
+
+func TestGetTaskData_EventPrivacy(t *testing.T) {
+	task := &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "task-private",
+			Namespace: "default",
+		},
+		Spec: toolkitv1alpha1.AgentTaskSpec{
+			Repo:     toolkitv1alpha1.RepoSpec{URL: "https://github.com/Acme/payments.git"},
+			Task:     toolkitv1alpha1.TaskSpec{Description: "A task"},
+			Callback: toolkitv1alpha1.CallbackSpec{URL: "https://example.com/cb"},
+		},
+	}
+
+	h := newTestHandler(task)
+	h.eventPrivacy = EventPrivacyOptions{Repos: map[string]string{"acme/payments": EventPrivacyMinimal}}
+	router := testRouter(h)
+
+	w := doGet(t, router, "/api/v1/tasks/task-private/data")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	doc := loadSpec(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task-private/data", nil)
+	validateResponse(t, doc, req, w)
+
+	var resp TaskDataResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Version, "older runners must refuse the task")
+	assert.Equal(t, EventPrivacyMinimal, resp.EventPrivacy)
+}
+
+func TestGetTaskData_Mode(t *testing.T) {
+	task := &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "task-plan",
+			Namespace: "default",
+			Labels:    map[string]string{ModeLabel: ModePlan},
+		},
+		Spec: toolkitv1alpha1.AgentTaskSpec{
+			Repo:     toolkitv1alpha1.RepoSpec{URL: "https://github.com/org/repo.git"},
+			Task:     toolkitv1alpha1.TaskSpec{Description: "A task"},
+			Callback: toolkitv1alpha1.CallbackSpec{URL: "https://example.com/cb"},
+		},
+	}
+	fix := task.DeepCopy()
+	fix.Name = "task-fix"
+	fix.Labels[ModeLabel] = ModeFix
+
+	router := testRouter(newTestHandler(task, fix))
+
+	w := doGet(t, router, "/api/v1/tasks/task-plan/data")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task-plan/data", nil), w)
+	var resp TaskDataResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, TaskDataVersion, resp.Version, "older runners must refuse the task")
+	assert.Equal(t, ModePlan, resp.Mode)
+
+	w = doGet(t, router, "/api/v1/tasks/task-fix/data")
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = TaskDataResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Version, "fix is the mode of tasks without the label")
+	assert.Empty(t, resp.Mode)
+}
+
+func TestGetTaskData_ModelAndBranch(t *testing.T) {
+	task := &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "task-1", Namespace: "default"},
+		Spec: toolkitv1alpha1.AgentTaskSpec{
+			Repo:     toolkitv1alpha1.RepoSpec{URL: "https://github.com/org/repo.git"},
+			Task:     toolkitv1alpha1.TaskSpec{Description: "A task"},
+			Callback: toolkitv1alpha1.CallbackSpec{URL: "https://example.com/cb"},
+			Runner:   toolkitv1alpha1.RunnerSpec{Model: "claude-opus-4-1", BranchPrefix: "bot/"},
+		},
+	}
+	defaults := task.DeepCopy()
+	defaults.Name = "task-2"
+	defaults.Spec.Runner = toolkitv1alpha1.RunnerSpec{}
+	pr := task.DeepCopy()
+	pr.Name = "task-pr"
+	pr.Spec.Task.SourceType = SourceTypePullRequest
+
+	router := testRouter(newTestHandler(task, defaults, pr))
+
+	w := doGet(t, router, "/api/v1/tasks/task-1/data")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task-1/data", nil), w)
+	var resp TaskDataResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Version, "older runners may ignore the fields")
+	assert.Equal(t, "claude-opus-4-1", resp.Model)
+	assert.Equal(t, "bot/task-1", resp.Branch)
+
+	resp = TaskDataResponse{}
+	require.NoError(t, json.Unmarshal(doGet(t, router, "/api/v1/tasks/task-2/data").Body.Bytes(), &resp))
+	assert.Empty(t, resp.Model)
+	assert.Equal(t, "shepherd/task-2", resp.Branch)
+
+	resp = TaskDataResponse{}
+	require.NoError(t, json.Unmarshal(doGet(t, router, "/api/v1/tasks/task-pr/data").Body.Bytes(), &resp))
+	assert.Empty(t, resp.Branch, "tasks of a PR push to its branch")
+}
@@ -18,40 +18,46 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
-	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
 )
 
 // postEvents handles POST /api/v1/tasks/{taskID}/events (internal port 8081).
 func (h *taskHandler) postEvents(w http.ResponseWriter, r *http.Request) {
-	log := ctrl.Log.WithName("api")
+	log := logf.FromContext(r.Context())
 	taskID := chi.URLParam(r, "taskID")
 
 	// Validate task exists and is not terminal
-	var task toolkitv1alpha1.AgentTask
-	key := client.ObjectKey{Namespace: h.namespace, Name: taskID}
-	if err := h.client.Get(r.Context(), key, &task); err != nil {
+	task, err := h.tasks.Get(r.Context(), taskID, ReadConsistent)
+	if err != nil {
 		if apierrors.IsNotFound(err) {
 			writeError(w, http.StatusNotFound, "task not found", "")
 			return
 		}
-		log.Error(err, "failed to get task", "taskID", taskID)
+		log.Error(err, "failed to get task", logging.TaskID, taskID)
 		writeError(w, http.StatusInternalServerError, "failed to get task", "")
 		return
 	}
+	log = logging.ForTask(log.WithValues(logging.TaskID, taskID), task.Annotations)
 
 	if task.IsTerminal() {
 		writeError(w, http.StatusGone, "task is terminal", "")
 		return
 	}
 
+	version, err := eventSchemas.requestVersion(r)
+	if err != nil {
+		writeEventError(w, err)
+		return
+	}
+
 	r.Body = http.MaxBytesReader(w, r.Body, 10<<20) // 10 MiB
 	var req PostEventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -64,40 +70,48 @@ func (h *taskHandler) postEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Known event types matching the OpenAPI spec enum.
-	validEventTypes := map[TaskEventType]bool{
-		EventTypeThinking:   true,
-		EventTypeToolCall:   true,
-		EventTypeToolResult: true,
-		EventTypeError:      true,
+	// Events are validated against the schema version the runner was built
+	// with, then translated to the current one.
+	events, err := eventSchemas.translate(version, req.Events)
+	if err != nil {
+		writeEventError(w, err)
+		return
+	}
+	req.Events = events
+	if version != EventSchemaVersion {
+		log.V(1).Info("translated events from an older schema", "schemaVersion", version)
 	}
 
-	// Validate each event
-	for i, e := range req.Events {
-		if e.Type == "" {
-			writeError(w, http.StatusBadRequest, "event type is required", "")
-			return
-		}
-		if !validEventTypes[e.Type] {
-			writeError(w, http.StatusBadRequest, "invalid event type", "must be one of: thinking, tool_call, tool_result, error")
-			return
-		}
-		if e.Summary == "" {
-			writeError(w, http.StatusBadRequest, "event summary is required", "")
-			return
+	// Stamp the events with the task's correlation ID, so stream consumers
+	// can join them with the callbacks and logs of the same run.
+	if id := task.Annotations[logging.CorrelationIDAnnotation]; id != "" {
+		for i := range req.Events {
+			if req.Events[i].Metadata == nil {
+				req.Events[i].Metadata = map[string]any{}
+			}
+			req.Events[i].Metadata[EventMetadataCorrelationID] = id
 		}
-		if e.Sequence <= 0 {
-			writeError(w, http.StatusBadRequest, "event sequence must be positive", "")
-			return
-		}
-		if _, err := time.Parse(time.RFC3339Nano, e.Timestamp); err != nil {
-			writeError(w, http.StatusBadRequest, "invalid event timestamp", "must be RFC3339 date-time format")
-			return
-		}
-		_ = i // validated
 	}
 
+	log.V(1).Info("publishing events", "count", len(req.Events),
+		"firstSequence", req.Events[0].Sequence, "lastSequence", req.Events[len(req.Events)-1].Sequence)
 	h.eventHub.Publish(taskID, req.Events)
+	eventsIngested.Add(float64(len(req.Events)))
 
+	w.Header().Set(EventSchemaVersionHeader, strconv.Itoa(EventSchemaVersion))
 	writeJSON(w, http.StatusOK, map[string]string{"status": "accepted"})
 }
+
+// getEventSchemas handles GET /api/v1/event-schemas (internal port 8081).
+func (h *taskHandler) getEventSchemas(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, EventSchemaResponse{Current: EventSchemaVersion, Schemas: EventSchemas()})
+}
+
+func writeEventError(w http.ResponseWriter, err error) {
+	var ee *eventError
+	if !errors.As(err, &ee) {
+		writeError(w, http.StatusInternalServerError, "failed to validate events", "")
+		return
+	}
+	writeError(w, http.StatusBadRequest, ee.message, ee.details)
+}
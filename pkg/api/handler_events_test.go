@@ -27,6 +27,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestPostEvents_Valid(t *testing.T) {
@@ -48,9 +50,11 @@ func TestPostEvents_Valid(t *testing.T) {
 		},
 	}
 
+	ingested := testutil.ToFloat64(eventsIngested)
 	w := postJSON(t, router, "/api/v1/tasks/task-events/events", req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ingested+2, testutil.ToFloat64(eventsIngested))
 
 	// Contract validation
 	doc := loadSpec(t)
@@ -66,6 +70,37 @@ func TestPostEvents_Valid(t *testing.T) {
 	assert.Equal(t, "Analyzing code", history[0].Summary)
 }
 
+func TestPostEvents_StampsCorrelationID(t *testing.T) {
+	task := newTask("task-events", nil, []metav1.Condition{
+		{
+			Type:   toolkitv1alpha1.ConditionSucceeded,
+			Status: metav1.ConditionUnknown,
+			Reason: toolkitv1alpha1.ReasonRunning,
+		},
+	})
+	task.Annotations = map[string]string{logging.CorrelationIDAnnotation: "run-1"}
+
+	h := newTestHandler(task)
+	router := testRouter(h)
+
+	req := PostEventRequest{
+		Events: []TaskEvent{
+			{Sequence: 1, Timestamp: "2026-01-01T00:00:00Z", Type: EventTypeThinking, Summary: "Analyzing code"},
+			{Sequence: 2, Timestamp: "2026-01-01T00:00:01Z", Type: EventTypeToolCall, Summary: "Reading file",
+				Tool: "Read", Metadata: map[string]any{"fullContent": "main.go"}},
+		},
+	}
+	w := postJSON(t, router, "/api/v1/tasks/task-events/events", req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	history, _, unsub := h.eventHub.Subscribe("task-events", 0)
+	defer unsub()
+
+	require.Len(t, history, 2)
+	assert.Equal(t, map[string]any{EventMetadataCorrelationID: "run-1"}, history[0].Metadata)
+	assert.Equal(t, map[string]any{"fullContent": "main.go", EventMetadataCorrelationID: "run-1"}, history[1].Metadata)
+}
+
 func TestPostEvents_TaskNotFound(t *testing.T) {
 	h := newTestHandler()
 	router := testRouter(h)
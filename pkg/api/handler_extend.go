@@ -0,0 +1,125 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// Limits on timeout extensions, matching the AgentTask CRD validation.
+const (
+	maxTimeoutExtensionsPerTask = 20
+	maxExtensionReasonLength    = 200
+)
+
+var (
+	errTaskTerminal          = errors.New("task is terminal")
+	errTooManyExtensions     = fmt.Errorf("task already has %d timeout extensions", maxTimeoutExtensionsPerTask)
+	errTimeoutExtensionLimit = errors.New("timeout extension limit exceeded")
+)
+
+// extendTimeout handles POST /api/v1/tasks/{taskID}/extend.
+func (h *taskHandler) extendTimeout(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	taskID := chi.URLParam(r, "taskID")
+
+	if h.maxTimeoutExtension <= 0 {
+		writeError(w, http.StatusForbidden, "timeout extensions are disabled", "")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10) // 64 KiB
+	var req ExtendTimeoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+	extension, err := time.ParseDuration(req.Duration)
+	if err != nil || extension <= 0 {
+		writeError(w, http.StatusBadRequest, "duration must be a positive Go duration, e.g. 30m", req.Duration)
+		return
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if n := utf8.RuneCountInString(req.Reason); n > maxExtensionReasonLength {
+		writeError(w, http.StatusBadRequest, "reason is too long",
+			fmt.Sprintf("%d characters exceeds the limit of %d", n, maxExtensionReasonLength))
+		return
+	}
+
+	ext := toolkitv1alpha1.TimeoutExtension{
+		Duration:  metav1.Duration{Duration: extension},
+		Reason:    req.Reason,
+		CreatedAt: metav1.NewTime(time.Now().UTC().Truncate(time.Second)),
+	}
+	// The extension list is replaced as a whole, so an extension granted
+	// concurrently makes the update conflict; re-read and check the limit
+	// again.
+	task, err := retryStatusUpdate(r.Context(), h.tasks, taskID, func(task *toolkitv1alpha1.AgentTask) error {
+		if task.IsTerminal() {
+			return errTaskTerminal
+		}
+		if len(task.Status.TimeoutExtensions) >= maxTimeoutExtensionsPerTask {
+			return errTooManyExtensions
+		}
+		if task.TimeoutExtension()+extension > h.maxTimeoutExtension {
+			return errTimeoutExtensionLimit
+		}
+		task.Status.TimeoutExtensions = append(task.Status.TimeoutExtensions, ext)
+		// Warn the adapter again as the new deadline approaches.
+		apimeta.RemoveStatusCondition(&task.Status.Conditions, toolkitv1alpha1.ConditionTimeoutWarning)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errTaskTerminal):
+		writeError(w, http.StatusGone, "task is terminal", "")
+		return
+	case errors.Is(err, errTooManyExtensions):
+		writeError(w, http.StatusConflict, "too many timeout extensions", err.Error())
+		return
+	case errors.Is(err, errTimeoutExtensionLimit):
+		writeError(w, http.StatusUnprocessableEntity, "timeout extension limit exceeded",
+			fmt.Sprintf("task was already extended by %s; extensions may add up to %s",
+				task.TimeoutExtension(), h.maxTimeoutExtension))
+		return
+	case apierrors.IsNotFound(err):
+		writeError(w, http.StatusNotFound, "task not found", "")
+		return
+	case err != nil:
+		log.Error(err, "failed to extend timeout", logging.TaskID, taskID)
+		writeError(w, http.StatusInternalServerError, "failed to extend timeout", "")
+		return
+	}
+
+	log.Info("extended task timeout", logging.TaskID, taskID,
+		"extension", extension, "timeout", task.RunnerTimeout(), "reason", ext.Reason)
+	writeJSON(w, http.StatusOK, taskToResponse(task))
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func runningTask(name string) *toolkitv1alpha1.AgentTask {
+	task := newTask(name, nil, []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionTimeoutWarning,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonWarningSent,
+	}})
+	task.Spec.Runner.Timeout = metav1.Duration{Duration: 30 * time.Minute}
+	task.Status.StartTime = &metav1.Time{Time: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	return task
+}
+
+func TestExtendTimeout(t *testing.T) {
+	h := newTestHandler(runningTask("task-1"))
+	h.maxTimeoutExtension = time.Hour
+	router := testRouter(h)
+
+	w := postJSON(t, router, "/api/v1/tasks/task-1/extend", ExtendTimeoutRequest{
+		Duration: "20m",
+		Reason:   "  large test suite  ",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Contract validation
+	doc := loadSpec(t)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task-1/extend", nil)
+	req.Header.Set("Content-Type", "application/json")
+	validateResponse(t, doc, req, w)
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "2026-03-01T12:50:00Z", resp.Status.Deadline)
+	require.Len(t, resp.TimeoutExtensions, 1)
+	assert.Equal(t, "20m0s", resp.TimeoutExtensions[0].Duration)
+	assert.Equal(t, "large test suite", resp.TimeoutExtensions[0].Reason)
+	assert.NotEmpty(t, resp.TimeoutExtensions[0].CreatedAt)
+
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "task-1"}, &task))
+	assert.Equal(t, 50*time.Minute, task.RunnerTimeout())
+	assert.Nil(t, apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionTimeoutWarning),
+		"warning cleared so the adapter is warned again")
+
+	// Extensions add up against the limit.
+	w = postJSON(t, router, "/api/v1/tasks/task-1/extend", ExtendTimeoutRequest{Duration: "40m"})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = postJSON(t, router, "/api/v1/tasks/task-1/extend", ExtendTimeoutRequest{Duration: "1s"})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	validateResponse(t, doc, req, w)
+}
+
+func TestExtendTimeout_Rejected(t *testing.T) {
+	terminal := newTask("task-done", nil, []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonSucceeded,
+	}})
+	full := runningTask("task-full")
+	for range maxTimeoutExtensionsPerTask {
+		full.Status.TimeoutExtensions = append(full.Status.TimeoutExtensions, toolkitv1alpha1.TimeoutExtension{
+			Duration:  metav1.Duration{Duration: time.Second},
+			CreatedAt: metav1.Now(),
+		})
+	}
+	h := newTestHandler(runningTask("task-1"), terminal, full)
+	h.maxTimeoutExtension = time.Hour
+	router := testRouter(h)
+
+	tests := []struct {
+		name string
+		path string
+		req  ExtendTimeoutRequest
+		code int
+	}{
+		{"missing duration", "/api/v1/tasks/task-1/extend", ExtendTimeoutRequest{}, http.StatusBadRequest},
+		{"invalid duration", "/api/v1/tasks/task-1/extend", ExtendTimeoutRequest{Duration: "soon"}, http.StatusBadRequest},
+		{"negative duration", "/api/v1/tasks/task-1/extend", ExtendTimeoutRequest{Duration: "-5m"}, http.StatusBadRequest},
+		{"long reason", "/api/v1/tasks/task-1/extend", ExtendTimeoutRequest{Duration: "5m", Reason: strings.Repeat("x", maxExtensionReasonLength+1)}, http.StatusBadRequest},
+		{"over limit", "/api/v1/tasks/task-1/extend", ExtendTimeoutRequest{Duration: "2h"}, http.StatusUnprocessableEntity},
+		{"unknown task", "/api/v1/tasks/nope/extend", ExtendTimeoutRequest{Duration: "5m"}, http.StatusNotFound},
+		{"terminal task", "/api/v1/tasks/task-done/extend", ExtendTimeoutRequest{Duration: "5m"}, http.StatusGone},
+		{"too many extensions", "/api/v1/tasks/task-full/extend", ExtendTimeoutRequest{Duration: "5m"}, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(t, router, tt.path, tt.req)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestExtendTimeout_Disabled(t *testing.T) {
+	h := newTestHandler(runningTask("task-1"))
+
+	w := postJSON(t, testRouter(h), "/api/v1/tasks/task-1/extend", ExtendTimeoutRequest{Duration: "5m"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
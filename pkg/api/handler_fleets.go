@@ -0,0 +1,90 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// getFleet handles GET /api/v1/fleets/{fleetID}. The response is built from
+// the TaskFleet status, which the operator keeps aggregated from the child
+// tasks; use GET /api/v1/tasks?fleet={fleetID} for the full task objects.
+func (h *taskHandler) getFleet(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	fleetID := chi.URLParam(r, "fleetID")
+
+	var fleet toolkitv1alpha1.TaskFleet
+	key := client.ObjectKey{Namespace: h.namespace, Name: fleetID}
+	if err := h.client.Get(r.Context(), key, &fleet); err != nil {
+		if errors.IsNotFound(err) {
+			writeError(w, http.StatusNotFound, "fleet not found", "")
+			return
+		}
+		log.Error(err, "failed to get fleet", "fleetID", fleetID)
+		writeError(w, http.StatusInternalServerError, "failed to get fleet", "")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, fleetToResponse(&fleet))
+}
+
+func fleetToResponse(fleet *toolkitv1alpha1.TaskFleet) FleetResponse {
+	phase := toolkitv1alpha1.ReasonPending
+	message := ""
+	if cond := apimeta.FindStatusCondition(fleet.Status.Conditions, toolkitv1alpha1.ConditionSucceeded); cond != nil {
+		phase = cond.Reason
+		message = cond.Message
+	}
+
+	tasks := make([]FleetTaskResponse, 0, len(fleet.Status.Tasks))
+	for _, t := range fleet.Status.Tasks {
+		tasks = append(tasks, FleetTaskResponse{
+			ID:      t.Name,
+			RepoURL: t.RepoURL,
+			Phase:   t.Phase,
+			PRURL:   t.PRURL,
+			Error:   t.Error,
+		})
+	}
+
+	return FleetResponse{
+		ID:          fleet.Name,
+		Namespace:   fleet.Namespace,
+		Description: fleet.Spec.Task.Description,
+		RepoQuery:   fleet.Spec.RepoQuery,
+		Status: FleetStatusSummary{
+			Phase:     phase,
+			Message:   message,
+			Total:     fleet.Status.Total,
+			Pending:   fleet.Status.Pending,
+			Running:   fleet.Status.Running,
+			Succeeded: fleet.Status.Succeeded,
+			Failed:    fleet.Status.Failed,
+		},
+		Tasks:     tasks,
+		CreatedAt: fleet.CreationTimestamp.UTC().Format(time.RFC3339),
+	}
+}
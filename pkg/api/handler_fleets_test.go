@@ -0,0 +1,114 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestGetFleet(t *testing.T) {
+	fleet := &toolkitv1alpha1.TaskFleet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "bump-go",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)),
+		},
+		Spec: toolkitv1alpha1.TaskFleetSpec{
+			RepoQuery: "org:acme language:go",
+			Task:      toolkitv1alpha1.TaskSpec{Description: "Bump Go to 1.26"},
+		},
+		Status: toolkitv1alpha1.TaskFleetStatus{
+			Conditions: []metav1.Condition{{
+				Type:    toolkitv1alpha1.ConditionSucceeded,
+				Status:  metav1.ConditionUnknown,
+				Reason:  toolkitv1alpha1.ReasonRunning,
+				Message: "1 of 2 tasks finished",
+			}},
+			Total:     2,
+			Running:   1,
+			Succeeded: 1,
+			Tasks: []toolkitv1alpha1.FleetTaskStatus{
+				{
+					Name:    "bump-go-1a2b3c4d",
+					RepoURL: "https://github.com/acme/api",
+					Phase:   toolkitv1alpha1.ReasonSucceeded,
+					PRURL:   "https://github.com/acme/api/pull/3",
+				},
+				{
+					Name:    "bump-go-5e6f7a8b",
+					RepoURL: "https://github.com/acme/web",
+					Phase:   toolkitv1alpha1.ReasonRunning,
+				},
+			},
+		},
+	}
+	router := testRouter(newTestHandler(fleet))
+
+	w := doGet(t, router, "/api/v1/fleets/bump-go")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/fleets/bump-go", nil), w)
+
+	var resp FleetResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "bump-go", resp.ID)
+	assert.Equal(t, "Bump Go to 1.26", resp.Description)
+	assert.Equal(t, "org:acme language:go", resp.RepoQuery)
+	assert.Equal(t, FleetStatusSummary{
+		Phase:     toolkitv1alpha1.ReasonRunning,
+		Message:   "1 of 2 tasks finished",
+		Total:     2,
+		Running:   1,
+		Succeeded: 1,
+	}, resp.Status)
+	require.Len(t, resp.Tasks, 2)
+	assert.Equal(t, "https://github.com/acme/api/pull/3", resp.Tasks[0].PRURL)
+	assert.Equal(t, toolkitv1alpha1.ReasonRunning, resp.Tasks[1].Phase)
+}
+
+func TestGetFleet_NotReconciled(t *testing.T) {
+	fleet := &toolkitv1alpha1.TaskFleet{
+		ObjectMeta: metav1.ObjectMeta{Name: "new-fleet", Namespace: "default"},
+	}
+	router := testRouter(newTestHandler(fleet))
+
+	w := doGet(t, router, "/api/v1/fleets/new-fleet")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/fleets/new-fleet", nil), w)
+
+	var resp FleetResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, toolkitv1alpha1.ReasonPending, resp.Status.Phase)
+	assert.Empty(t, resp.Tasks)
+}
+
+func TestGetFleet_NotFound(t *testing.T) {
+	router := testRouter(newTestHandler())
+
+	w := doGet(t, router, "/api/v1/fleets/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/fleets/missing", nil), w)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// Limits on notes, matching the AgentTask CRD validation.
+const (
+	maxNotesPerTask     = 100
+	maxNoteTextLength   = 2000
+	maxNoteAuthorLength = 100
+)
+
+// addNote handles POST /api/v1/tasks/{taskID}/notes.
+func (h *taskHandler) addNote(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	taskID := chi.URLParam(r, "taskID")
+
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10) // 64 KiB
+	var req CreateNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	req.Author = strings.TrimSpace(req.Author)
+	if req.Text == "" {
+		writeError(w, http.StatusBadRequest, "text is required", "")
+		return
+	}
+	if n := utf8.RuneCountInString(req.Text); n > maxNoteTextLength {
+		writeError(w, http.StatusBadRequest, "text is too long",
+			fmt.Sprintf("%d characters exceeds the limit of %d", n, maxNoteTextLength))
+		return
+	}
+	if n := utf8.RuneCountInString(req.Author); n > maxNoteAuthorLength {
+		writeError(w, http.StatusBadRequest, "author is too long",
+			fmt.Sprintf("%d characters exceeds the limit of %d", n, maxNoteAuthorLength))
+		return
+	}
+
+	note := toolkitv1alpha1.TaskNote{
+		Author:    req.Author,
+		Text:      req.Text,
+		CreatedAt: metav1.NewTime(time.Now().UTC().Truncate(time.Second)),
+	}
+	errTooManyNotes := fmt.Errorf("task already has %d notes", maxNotesPerTask)
+	// The notes list is replaced as a whole, so a note added concurrently
+	// makes the update conflict; re-read and append again.
+	_, err := retryStatusUpdate(r.Context(), h.tasks, taskID, func(task *toolkitv1alpha1.AgentTask) error {
+		if len(task.Status.Notes) >= maxNotesPerTask {
+			return errTooManyNotes
+		}
+		task.Status.Notes = append(task.Status.Notes, note)
+		return nil
+	})
+	switch {
+	case err == errTooManyNotes:
+		writeError(w, http.StatusConflict, "too many notes", err.Error())
+		return
+	case errors.IsNotFound(err):
+		writeError(w, http.StatusNotFound, "task not found", "")
+		return
+	case err != nil:
+		log.Error(err, "failed to add note", logging.TaskID, taskID)
+		writeError(w, http.StatusInternalServerError, "failed to add note", "")
+		return
+	}
+
+	log.Info("note added to task", logging.TaskID, taskID, "author", note.Author)
+	writeJSON(w, http.StatusCreated, noteToResponse(note))
+}
+
+func noteToResponse(note toolkitv1alpha1.TaskNote) NoteResponse {
+	return NoteResponse{
+		Author:    note.Author,
+		Text:      note.Text,
+		CreatedAt: note.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestAddNote(t *testing.T) {
+	h := newTestHandler(newTask("task-1", nil, nil))
+	router := testRouter(h)
+
+	w := postJSON(t, router, "/api/v1/tasks/task-1/notes", CreateNoteRequest{
+		Author: "alice",
+		Text:   "  retried after infra outage  ",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	// Contract validation
+	doc := loadSpec(t)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task-1/notes", nil)
+	req.Header.Set("Content-Type", "application/json")
+	validateResponse(t, doc, req, w)
+
+	var note NoteResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &note))
+	assert.Equal(t, "alice", note.Author)
+	assert.Equal(t, "retried after infra outage", note.Text)
+	assert.NotEmpty(t, note.CreatedAt)
+
+	w = postJSON(t, router, "/api/v1/tasks/task-1/notes", CreateNoteRequest{Text: "PR superseded by #55"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	// Notes are returned with the task, oldest first.
+	w = doGet(t, router, "/api/v1/tasks/task-1")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, doc, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task-1", nil), w)
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Notes, 2)
+	assert.Equal(t, "retried after infra outage", resp.Notes[0].Text)
+	assert.Equal(t, "PR superseded by #55", resp.Notes[1].Text)
+	assert.Empty(t, resp.Notes[1].Author)
+}
+
+func TestAddNote_Invalid(t *testing.T) {
+	h := newTestHandler(newTask("task-1", nil, nil))
+	router := testRouter(h)
+
+	tests := []struct {
+		name string
+		path string
+		req  CreateNoteRequest
+		code int
+	}{
+		{"empty text", "/api/v1/tasks/task-1/notes", CreateNoteRequest{Text: "   "}, http.StatusBadRequest},
+		{"long text", "/api/v1/tasks/task-1/notes", CreateNoteRequest{Text: strings.Repeat("x", maxNoteTextLength+1)}, http.StatusBadRequest},
+		{"long author", "/api/v1/tasks/task-1/notes", CreateNoteRequest{Author: strings.Repeat("a", maxNoteAuthorLength+1), Text: "hi"}, http.StatusBadRequest},
+		{"unknown task", "/api/v1/tasks/nope/notes", CreateNoteRequest{Text: "hi"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(t, router, tt.path, tt.req)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestAddNote_Limit(t *testing.T) {
+	task := newTask("task-1", nil, nil)
+	for range maxNotesPerTask {
+		task.Status.Notes = append(task.Status.Notes, toolkitv1alpha1.TaskNote{Text: "note", CreatedAt: metav1.Now()})
+	}
+	h := newTestHandler(task)
+
+	w := postJSON(t, testRouter(h), "/api/v1/tasks/task-1/notes", CreateNoteRequest{Text: "one too many"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
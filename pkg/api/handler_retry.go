@@ -0,0 +1,114 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"maps"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// Labels that link a task to the earlier task of its source it follows.
+const (
+	// RetryOfLabel names the failed, timed out or cancelled task that a
+	// task retries. Retries created through POST
+	// /api/v1/tasks/{taskID}/retry carry it.
+	RetryOfLabel = "shepherd.io/retry-of"
+	// SupersedesLabel names the succeeded task whose result a task
+	// replaces.
+	SupersedesLabel = "shepherd.io/supersedes"
+)
+
+// retryTask handles POST /api/v1/tasks/{taskID}/retry.
+//
+// The retry is a new task with the spec and labels of the finished task,
+// labeled RetryOfLabel with its name. It goes through the same maintenance,
+// quota and policy checks as a created task, and gets its own correlation
+// ID.
+func (h *taskHandler) retryTask(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	taskID := chi.URLParam(r, "taskID")
+	if h.rejectDuringMaintenance(w, r) {
+		return
+	}
+	correlationID, ok := requestCorrelationID(w, r)
+	if !ok {
+		return
+	}
+
+	previous, err := h.tasks.Get(r.Context(), taskID, ReadConsistent)
+	if errors.IsNotFound(err) {
+		writeError(w, http.StatusNotFound, "task not found", "")
+		return
+	}
+	if err != nil {
+		log.Error(err, "failed to get task", logging.TaskID, taskID)
+		writeError(w, http.StatusInternalServerError, "failed to get task", "")
+		return
+	}
+	switch {
+	case !previous.IsTerminal():
+		writeError(w, http.StatusConflict, "task has not finished", "")
+		return
+	case previous.ComputePhase() == toolkitv1alpha1.PhaseSucceeded:
+		writeError(w, http.StatusConflict, "task succeeded", "only failed, timed out or cancelled tasks can be retried")
+		return
+	}
+	log = log.WithValues(logging.Repo, previous.Spec.Repo.URL, logging.CorrelationID, correlationID)
+
+	exceeded, err := h.checkQuota(r.Context(), previous.Spec.Repo.URL)
+	if err != nil {
+		log.Error(err, "failed to check task quota")
+		writeError(w, http.StatusInternalServerError, "failed to check task quota", "")
+		return
+	}
+	if exceeded != "" {
+		writeError(w, http.StatusTooManyRequests, "task quota exceeded", exceeded)
+		return
+	}
+
+	labels := maps.Clone(previous.Labels)
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	delete(labels, SupersedesLabel)
+	labels[RetryOfLabel] = previous.Name
+
+	spec := *previous.Spec.DeepCopy()
+	spec.Suspend = false
+	task := &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "task-" + rand.String(8),
+			Namespace: h.namespace,
+			Labels:    labels,
+		},
+		Spec: spec,
+	}
+	if !h.submitTask(w, r, log, task, correlationID) {
+		return
+	}
+	log.Info("retried task", logging.TaskID, task.Name, "retryOf", previous.Name)
+	writeJSON(w, http.StatusCreated, taskToResponse(task))
+}
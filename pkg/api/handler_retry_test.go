@@ -0,0 +1,97 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+func TestRetryTask(t *testing.T) {
+	previous := adminTask("task-1", "acme-app", metav1.ConditionFalse, toolkitv1alpha1.ReasonFailed, time.Now())
+	previous.Labels["shepherd.io/issue"] = "42"
+	previous.Labels[SupersedesLabel] = "task-0"
+	previous.Spec.Runner.SandboxTemplateName = "default-template"
+	previous.Spec.Priority = 5
+	previous.Spec.Suspend = true
+	previous.Annotations = map[string]string{logging.CorrelationIDAnnotation: "run-1"}
+	h := newTestHandler(previous)
+	router := testRouter(h)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task-1/retry", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(logging.CorrelationIDHeader, "run-2")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	validateResponse(t, loadSpec(t), req, w)
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEqual(t, "task-1", resp.ID)
+	assert.Equal(t, "run-2", resp.CorrelationID)
+
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(t.Context(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+	assert.Equal(t, map[string]string{
+		toolkitv1alpha1.RepoLabel: "acme-app",
+		"shepherd.io/issue":       "42",
+		RetryOfLabel:              "task-1",
+	}, task.Labels)
+	assert.Equal(t, previous.Spec.Repo, task.Spec.Repo)
+	assert.Equal(t, previous.Spec.Task, task.Spec.Task)
+	assert.Equal(t, "default-template", task.Spec.Runner.SandboxTemplateName)
+	assert.Equal(t, int32(5), task.Spec.Priority)
+	assert.False(t, task.Spec.Suspend, "a retry runs")
+	assert.Empty(t, task.Status.Conditions)
+}
+
+func TestRetryTask_Rejected(t *testing.T) {
+	now := time.Now()
+	h := newTestHandler(
+		adminTask("task-running", "acme-app", metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning, now),
+		adminTask("task-done", "acme-app", metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded, now),
+	)
+	router := testRouter(h)
+
+	tests := []struct {
+		name string
+		path string
+		code int
+	}{
+		{"unknown task", "/api/v1/tasks/nope/retry", http.StatusNotFound},
+		{"unfinished task", "/api/v1/tasks/task-running/retry", http.StatusConflict},
+		{"succeeded task", "/api/v1/tasks/task-done/retry", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(t, router, tt.path, nil)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// searchTasks handles GET /api/v1/tasks/search.
+// Query parameters:
+//   - q: search terms (required). Every whitespace-separated term must appear,
+//     case-insensitively, in the task ID, description, repo URL, requesting
+//     user or PR URL.
+//   - limit: maximum number of results (default 50, max 200)
+//
+// Results are ordered newest first. The search scans the informer cache
+// rather than the API server, so it is cheap to call while typing.
+func (h *taskHandler) searchTasks(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+
+	terms := strings.Fields(strings.ToLower(r.URL.Query().Get("q")))
+	if len(terms) == 0 {
+		writeError(w, http.StatusBadRequest, "q is required", "")
+		return
+	}
+
+	limit := defaultSearchLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			writeError(w, http.StatusBadRequest, "invalid limit",
+				fmt.Sprintf("must be between 1 and %d", maxSearchLimit))
+			return
+		}
+		limit = n
+	}
+
+	items, err := h.tasks.List(r.Context(), nil, ReadCached)
+	if err != nil {
+		log.Error(err, "failed to list tasks for search")
+		writeError(w, http.StatusInternalServerError, "failed to search tasks", "")
+		return
+	}
+
+	tasks := make([]TaskResponse, 0)
+	for i := range items {
+		task := &items[i]
+		if matchesSearch(task, terms) {
+			tasks = append(tasks, taskToResponse(task))
+		}
+	}
+	sortTasks(tasks, "createdAt", true)
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+// matchesSearch reports whether every term occurs in one of the task's
+// searchable fields. Terms must already be lower-case.
+func matchesSearch(task *toolkitv1alpha1.AgentTask, terms []string) bool {
+	haystack := strings.ToLower(strings.Join([]string{
+		task.Name,
+		task.Spec.Task.Description,
+		task.Spec.Repo.URL,
+		task.Labels["shepherd.io/requested-by"],
+		task.Status.Result.PRURL,
+	}, "\n"))
+	for _, term := range terms {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
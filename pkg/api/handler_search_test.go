@@ -0,0 +1,138 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func searchFixtures() []*toolkitv1alpha1.AgentTask {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	auth := newTask("task-auth", map[string]string{"shepherd.io/requested-by": "alice"}, nil)
+	auth.Spec.Task.Description = "Fix the flaky auth test"
+	auth.Spec.Repo.URL = "https://github.com/org/backend"
+	auth.CreationTimestamp = metav1.NewTime(base)
+
+	login := newTask("task-login", map[string]string{"shepherd.io/requested-by": "bob"}, nil)
+	login.Spec.Task.Description = "Auth: handle expired sessions on login"
+	login.Spec.Repo.URL = "https://github.com/org/frontend"
+	login.Status.Result.PRURL = "https://github.com/org/frontend/pull/17"
+	login.CreationTimestamp = metav1.NewTime(base.Add(time.Hour))
+
+	docs := newTask("task-docs", nil, nil)
+	docs.Spec.Task.Description = "Update the README"
+	docs.Spec.Repo.URL = "https://github.com/org/docs"
+	docs.CreationTimestamp = metav1.NewTime(base.Add(2 * time.Hour))
+
+	return []*toolkitv1alpha1.AgentTask{auth, login, docs}
+}
+
+func TestSearchTasks(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"description, newest first", "q=auth", []string{"task-login", "task-auth"}},
+		{"all terms must match", "q=flaky+auth", []string{"task-auth"}},
+		{"case-insensitive", "q=README", []string{"task-docs"}},
+		{"repo URL", "q=org/frontend", []string{"task-login"}},
+		{"requesting user", "q=alice", []string{"task-auth"}},
+		{"PR URL", "q=pull/17", []string{"task-login"}},
+		{"task ID", "q=task-docs", []string{"task-docs"}},
+		{"no match", "q=kubernetes", []string{}},
+		{"limit", "q=org&limit=2", []string{"task-docs", "task-login"}},
+	}
+
+	fixtures := searchFixtures()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(fixtures[0], fixtures[1], fixtures[2])
+			router := testRouter(h)
+
+			w := doGet(t, router, "/api/v1/tasks/search?"+tt.query)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/search?"+tt.query, nil)
+			validateResponse(t, loadSpec(t), req, w)
+
+			var tasks []TaskResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tasks))
+			ids := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				ids = append(ids, task.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestSearchTasks_RequestedBy(t *testing.T) {
+	fixtures := searchFixtures()
+	h := newTestHandler(fixtures[0])
+	router := testRouter(h)
+
+	w := doGet(t, router, "/api/v1/tasks/search?q=alice")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var tasks []TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tasks))
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "alice", tasks[0].RequestedBy)
+}
+
+func TestSearchTasks_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{"missing q", "", "q is required"},
+		{"blank q", "q=+++", "q is required"},
+		{"zero limit", "q=auth&limit=0", "invalid limit"},
+		{"limit too large", "q=auth&limit=201", "invalid limit"},
+		{"non-numeric limit", "q=auth&limit=ten", "invalid limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			router := testRouter(h)
+
+			w := doGet(t, router, "/api/v1/tasks/search?"+tt.query)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/search?"+tt.query, nil)
+			validateResponse(t, loadSpec(t), req, w)
+
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+			assert.Equal(t, tt.wantErr, errResp.Error)
+		})
+	}
+}
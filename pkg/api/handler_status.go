@@ -17,24 +17,39 @@ limitations under the License.
 package api
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
 )
 
+// maxSummaryLen is the longest summary status.result.summary holds.
+const maxSummaryLen = 4000
+
+// truncateSummary cuts summary to maxSummaryLen characters, so a runner
+// that does not truncate it cannot get its status update rejected.
+func truncateSummary(summary string) string {
+	if runes := []rune(summary); len(runes) > maxSummaryLen {
+		return string(runes[:maxSummaryLen])
+	}
+	return summary
+}
+
 // updateTaskStatus handles POST /api/v1/tasks/{taskID}/status.
 func (h *taskHandler) updateTaskStatus(w http.ResponseWriter, r *http.Request) {
-	log := ctrl.Log.WithName("api")
+	log := logf.FromContext(r.Context())
 	taskID := chi.URLParam(r, "taskID")
 
 	r.Body = http.MaxBytesReader(w, r.Body, 10<<20) // 10 MiB
@@ -62,21 +77,53 @@ func (h *taskHandler) updateTaskStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fetch the task
-	var task toolkitv1alpha1.AgentTask
-	key := client.ObjectKey{Namespace: h.namespace, Name: taskID}
-	if err := h.client.Get(r.Context(), key, &task); err != nil {
+	task, err := h.tasks.Get(r.Context(), taskID, ReadConsistent)
+	if err != nil {
 		if apierrors.IsNotFound(err) {
 			writeError(w, http.StatusNotFound, "task not found", "")
 			return
 		}
-		log.Error(err, "failed to get task", "taskID", taskID)
+		log.Error(err, "failed to get task", logging.TaskID, taskID)
 		writeError(w, http.StatusInternalServerError, "failed to get task", "")
 		return
 	}
+	log = logging.ForTask(log.WithValues(logging.TaskID, taskID, logging.Repo, task.Spec.Repo.URL), task.Annotations)
+	log.V(1).Info("received status update", "event", req.Event, "message", req.Message)
+
+	// Runners report cost and summary with their fallback terminal event,
+	// which usually arrives after the Stop hook's event and is deduplicated
+	// below, so they are recorded on their own first. Failure only loses
+	// these figures.
+	var cost, summary string
+	if usd, ok := req.Details["cost_usd"].(float64); ok && usd > 0 && task.Status.Result.CostUSD == "" {
+		cost = strconv.FormatFloat(usd, 'f', 4, 64)
+	}
+	if s, ok := req.Details["summary"].(string); ok && task.Status.Result.Summary == "" {
+		summary = truncateSummary(strings.TrimSpace(s))
+	}
+	if cost != "" || summary != "" {
+		err := h.tasks.UpdateStatus(r.Context(), task, func(task *toolkitv1alpha1.AgentTask) error {
+			task.Status.Result.CostUSD = cmp.Or(task.Status.Result.CostUSD, cost)
+			task.Status.Result.Summary = cmp.Or(task.Status.Result.Summary, summary)
+			return nil
+		})
+		if err != nil {
+			log.Error(err, "failed to record task cost and summary")
+			task.Status.Result.CostUSD = ""
+			task.Status.Result.Summary = ""
+		}
+	}
 
 	// For terminal events, check dedup before doing any work
 	isTerminal := req.Event == EventCompleted || req.Event == EventFailed
 	if isTerminal {
+		// A runner reporting after an administrator cancelled its task
+		// must not overwrite the cancellation.
+		if cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded); cond != nil &&
+			cond.Reason == toolkitv1alpha1.ReasonCancelled {
+			writeJSON(w, http.StatusOK, map[string]string{"status": "accepted", "note": "task cancelled"})
+			return
+		}
 		notifiedCond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionNotified)
 		if notifiedCond != nil {
 			// Only dedup on definitively complete callbacks (CallbackSent or CallbackFailed)
@@ -99,56 +146,64 @@ func (h *taskHandler) updateTaskStatus(w http.ResponseWriter, r *http.Request) {
 	// Update CRD status fields based on event
 	// Only terminal events modify status fields
 	if isTerminal {
-		now := metav1.Now()
-		task.Status.CompletionTime = &now
-		task.Status.GraceDeadline = nil
-
-		switch req.Event {
-		case EventCompleted:
-			if prURL, ok := req.Details["pr_url"].(string); ok {
-				task.Status.Result.PRURL = prURL
-			}
-			apimeta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
-				Type:               toolkitv1alpha1.ConditionSucceeded,
-				Status:             metav1.ConditionTrue,
-				Reason:             toolkitv1alpha1.ReasonSucceeded,
-				Message:            req.Message,
-				ObservedGeneration: task.Generation,
-			})
-		case EventFailed:
-			if errMsg, ok := req.Details["error"].(string); ok {
-				task.Status.Result.Error = errMsg
+		// Single status update with all changes (result + Notified
+		// condition). It fails if the task changed since it was read, which
+		// makes it a claim: only one writer wins.
+		err := h.tasks.UpdateStatus(r.Context(), task, func(task *toolkitv1alpha1.AgentTask) error {
+			now := metav1.Now()
+			task.Status.CompletionTime = &now
+			task.Status.GraceDeadline = nil
+
+			switch req.Event {
+			case EventCompleted:
+				if prURL, ok := req.Details["pr_url"].(string); ok {
+					task.Status.Result.PRURL = prURL
+				}
+				apimeta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+					Type:               toolkitv1alpha1.ConditionSucceeded,
+					Status:             metav1.ConditionTrue,
+					Reason:             toolkitv1alpha1.ReasonSucceeded,
+					Message:            req.Message,
+					ObservedGeneration: task.Generation,
+				})
+			case EventFailed:
+				if errMsg, ok := req.Details["error"].(string); ok {
+					task.Status.Result.Error = errMsg
+				}
+				apimeta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
+					Type:               toolkitv1alpha1.ConditionSucceeded,
+					Status:             metav1.ConditionFalse,
+					Reason:             toolkitv1alpha1.ReasonFailed,
+					Message:            req.Message,
+					ObservedGeneration: task.Generation,
+				})
 			}
+
+			// Set Notified condition to CallbackPending (Unknown status) in the SAME update
+			// as result fields to avoid a double-write race (resource version changes after first update).
 			apimeta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
-				Type:               toolkitv1alpha1.ConditionSucceeded,
-				Status:             metav1.ConditionFalse,
-				Reason:             toolkitv1alpha1.ReasonFailed,
-				Message:            req.Message,
+				Type:               toolkitv1alpha1.ConditionNotified,
+				Status:             metav1.ConditionUnknown,
+				Reason:             toolkitv1alpha1.ReasonCallbackPending,
+				Message:            fmt.Sprintf("Sending callback to adapter: %s", req.Event),
 				ObservedGeneration: task.Generation,
 			})
-		}
 
-		// Set Notified condition to CallbackPending (Unknown status) in the SAME update
-		// as result fields to avoid a double-write race (resource version changes after first update).
-		apimeta.SetStatusCondition(&task.Status.Conditions, metav1.Condition{
-			Type:               toolkitv1alpha1.ConditionNotified,
-			Status:             metav1.ConditionUnknown,
-			Reason:             toolkitv1alpha1.ReasonCallbackPending,
-			Message:            fmt.Sprintf("Sending callback to adapter: %s", req.Event),
-			ObservedGeneration: task.Generation,
+			task.Status.Phase = task.ComputePhase()
+			return nil
 		})
-
-		// Single status update with all changes (result + Notified condition)
-		if err := h.client.Status().Update(r.Context(), &task); err != nil {
+		if err != nil {
 			if apierrors.IsConflict(err) {
 				// Someone else claimed the task first — treat as accepted
 				writeJSON(w, http.StatusOK, map[string]string{"status": "accepted", "note": "task already claimed"})
 				return
 			}
-			log.Error(err, "failed to update task status", "taskID", taskID)
+			log.Error(err, "failed to update task status")
 			writeError(w, http.StatusInternalServerError, "failed to update task status", "")
 			return
 		}
+		log.Info("recorded terminal status", logging.Phase,
+			apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded).Reason)
 	}
 
 	// Notify EventHub subscribers that the task is complete (terminal events only)
@@ -163,55 +218,70 @@ func (h *taskHandler) updateTaskStatus(w http.ResponseWriter, r *http.Request) {
 	// Forward callback to adapter (after successful status update)
 	callbackURL := task.Spec.Callback.URL
 	payload := CallbackPayload{
-		TaskID:  taskID,
-		Event:   req.Event,
-		Message: req.Message,
-		Details: req.Details,
+		TaskID:        taskID,
+		Event:         req.Event,
+		Message:       req.Message,
+		Details:       req.Details,
+		CorrelationID: task.Annotations[logging.CorrelationIDAnnotation],
 	}
 
-	callbackErr := h.callback.send(r.Context(), callbackURL, payload)
+	callbackCode, callbackErr := h.callback.deliver(r.Context(), task.Spec.Callback, payload)
 
 	// Phase 2: Update Notified condition based on callback result (terminal events only)
 	if isTerminal {
 		// Re-fetch the task to get fresh resourceVersion
-		var freshTask toolkitv1alpha1.AgentTask
-		key := client.ObjectKey{Namespace: h.namespace, Name: taskID}
-		if err := h.client.Get(r.Context(), key, &freshTask); err != nil {
-			log.Error(err, "failed to re-fetch task for callback status update", "taskID", taskID)
+		freshTask, err := h.tasks.Get(r.Context(), taskID, ReadConsistent)
+		if err != nil {
+			log.Error(err, "failed to re-fetch task for callback status update")
 			// Continue without updating callback status — watcher can retry if CallbackPending TTL expires
 		} else {
-			// Update Notified condition based on callback result
+			if err := h.archiver.archive(r.Context(), freshTask); err != nil {
+				log.Error(err, "failed to archive task")
+			}
+			// Update Notified condition based on callback result. A failed
+			// callback is retried by the status watcher with backoff.
+			var failureMessage string
 			if callbackErr != nil {
-				apimeta.SetStatusCondition(&freshTask.Status.Conditions, metav1.Condition{
-					Type:               toolkitv1alpha1.ConditionNotified,
-					Status:             metav1.ConditionTrue,
-					Reason:             toolkitv1alpha1.ReasonCallbackFailed,
-					Message:            fmt.Sprintf("Adapter callback failed: %v", callbackErr),
-					ObservedGeneration: freshTask.Generation,
-				})
-			} else {
-				apimeta.SetStatusCondition(&freshTask.Status.Conditions, metav1.Condition{
-					Type:               toolkitv1alpha1.ConditionNotified,
-					Status:             metav1.ConditionTrue,
-					Reason:             toolkitv1alpha1.ReasonCallbackSent,
-					Message:            fmt.Sprintf("Adapter notified: %s", req.Event),
-					ObservedGeneration: freshTask.Generation,
-				})
+				var recordErr error
+				failureMessage, recordErr = recordCallbackFailure(r.Context(), h.tasks, freshTask, callbackErr)
+				if recordErr != nil {
+					log.Error(recordErr, "failed to record callback attempt")
+				}
 			}
-
-			if err := h.client.Status().Update(r.Context(), &freshTask); err != nil {
-				log.Error(err, "failed to update callback status", "taskID", taskID)
+			err := h.tasks.UpdateStatus(r.Context(), freshTask, func(freshTask *toolkitv1alpha1.AgentTask) error {
+				recordCallbackAttempt(freshTask, newCallbackAttempt(req.Event, time.Now(), callbackCode, callbackErr, false))
+				if callbackErr != nil {
+					apimeta.SetStatusCondition(&freshTask.Status.Conditions, metav1.Condition{
+						Type:               toolkitv1alpha1.ConditionNotified,
+						Status:             metav1.ConditionTrue,
+						Reason:             toolkitv1alpha1.ReasonCallbackFailed,
+						Message:            failureMessage,
+						ObservedGeneration: freshTask.Generation,
+					})
+				} else {
+					apimeta.SetStatusCondition(&freshTask.Status.Conditions, metav1.Condition{
+						Type:               toolkitv1alpha1.ConditionNotified,
+						Status:             metav1.ConditionTrue,
+						Reason:             toolkitv1alpha1.ReasonCallbackSent,
+						Message:            fmt.Sprintf("Adapter notified: %s", req.Event),
+						ObservedGeneration: freshTask.Generation,
+					})
+				}
+				return nil
+			})
+			if err != nil {
+				log.Error(err, "failed to update callback status")
 				// Don't fail the request — the condition remains CallbackPending which watcher can retry if TTL expires
 			}
 		}
 
 		if callbackErr != nil {
-			log.Error(callbackErr, "failed to send adapter callback", "taskID", taskID, "callbackURL", callbackURL)
+			log.Error(callbackErr, "failed to send adapter callback", "callbackURL", callbackURL)
 		}
 	} else {
 		// Non-terminal events: just log callback errors, don't update condition
 		if callbackErr != nil {
-			log.Error(callbackErr, "failed to send adapter callback", "taskID", taskID, "callbackURL", callbackURL)
+			log.Error(callbackErr, "failed to send adapter callback", "callbackURL", callbackURL)
 		}
 	}
 
@@ -49,6 +49,7 @@ func newTestHandlerWithCallback(secret string, objs ...client.Object) *taskHandl
 
 	return &taskHandler{
 		client:    c,
+		tasks:     newKubeTaskStore(c, nil, "default"),
 		namespace: "default",
 		callback:  newCallbackSender(secret),
 		eventHub:  NewEventHub(),
@@ -291,6 +292,73 @@ func TestUpdateTaskStatus_DuplicateTerminalSkipsCallback(t *testing.T) {
 	}
 }
 
+func TestUpdateTaskStatus_RecordsCostAfterNotification(t *testing.T) {
+	var callbackCount atomic.Int32
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		callbackCount.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	// The Stop hook already reported completion; the runner's fallback
+	// event carries the cost.
+	task := statusTask("task-abc", adapter.URL, []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionNotified,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonCallbackSent,
+	}})
+	h := newTestHandlerWithCallback("test-secret", task)
+	router := testRouter(h)
+
+	w := postJSON(t, router, "/api/v1/tasks/task-abc/status", StatusUpdateRequest{
+		Event:   "completed",
+		Message: "claude code completed",
+		Details: map[string]any{"cost_usd": 0.4213},
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int32(0), callbackCount.Load(), "duplicate terminal event must not be forwarded")
+
+	var updated toolkitv1alpha1.AgentTask
+	err := h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "task-abc"}, &updated)
+	require.NoError(t, err)
+	assert.Equal(t, "0.4213", updated.Status.Result.CostUSD)
+
+	resp := taskToResponse(&updated)
+	assert.InDelta(t, 0.4213, resp.Status.CostUSD, 1e-9)
+}
+
+func TestUpdateTaskStatus_RecordsSummary(t *testing.T) {
+	var payload CallbackPayload
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	task := statusTask("task-abc", adapter.URL, nil)
+	h := newTestHandlerWithCallback("test-secret", task)
+	router := testRouter(h)
+
+	w := postJSON(t, router, "/api/v1/tasks/task-abc/status", StatusUpdateRequest{
+		Event:   "completed",
+		Message: "task completed",
+		Details: map[string]any{
+			"pr_url":  "https://github.com/org/repo/pull/1",
+			"summary": " Fixed the login redirect. " + strings.Repeat("x", maxSummaryLen),
+		},
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated toolkitv1alpha1.AgentTask
+	err := h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "task-abc"}, &updated)
+	require.NoError(t, err)
+	assert.Len(t, updated.Status.Result.Summary, maxSummaryLen)
+	assert.True(t, strings.HasPrefix(updated.Status.Result.Summary, "Fixed the login redirect."))
+	assert.Equal(t, updated.Status.Result.Summary, taskToResponse(&updated).Status.Summary)
+	assert.Contains(t, payload.Details["summary"], "Fixed the login redirect.")
+}
+
 func TestUpdateTaskStatus_AdapterFailureDoesNotFailRequest(t *testing.T) {
 	// Adapter that always returns 500
 	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -386,7 +454,7 @@ func TestUpdateTaskStatus_K8sGetError(t *testing.T) {
 		}).
 		Build()
 
-	h := &taskHandler{client: c, namespace: "default", callback: newCallbackSender("")}
+	h := &taskHandler{client: c, tasks: newKubeTaskStore(c, nil, "default"), namespace: "default", callback: newCallbackSender("")}
 	router := testRouter(h)
 
 	w := postJSON(t, router, "/api/v1/tasks/task-abc/status", StatusUpdateRequest{
@@ -414,13 +482,13 @@ func TestUpdateTaskStatus_K8sStatusUpdateError(t *testing.T) {
 		WithStatusSubresource(&toolkitv1alpha1.AgentTask{}).
 		WithObjects(task).
 		WithInterceptorFuncs(interceptor.Funcs{
-			SubResourceUpdate: func(_ context.Context, _ client.Client, _ string, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+			SubResourcePatch: func(_ context.Context, _ client.Client, _ string, _ client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
 				return fmt.Errorf("conflict: resource version changed")
 			},
 		}).
 		Build()
 
-	h := &taskHandler{client: c, namespace: "default", callback: newCallbackSender("test-secret")}
+	h := &taskHandler{client: c, tasks: newKubeTaskStore(c, nil, "default"), namespace: "default", callback: newCallbackSender("test-secret")}
 	router := testRouter(h)
 
 	w := postJSON(t, router, "/api/v1/tasks/task-abc/status", StatusUpdateRequest{
@@ -568,4 +636,5 @@ func TestUpdateTaskStatus_TwoPhaseCondition_CallbackFailure(t *testing.T) {
 	assert.Equal(t, metav1.ConditionTrue, notified.Status, "final status should be True even for failed callback")
 	assert.Equal(t, toolkitv1alpha1.ReasonCallbackFailed, notified.Reason)
 	assert.Contains(t, notified.Message, "failed")
+	assert.Equal(t, "1", updated.Annotations[CallbackAttemptsAnnotation], "the attempt is counted for the watcher's retries")
 }
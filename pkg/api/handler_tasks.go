@@ -23,22 +23,34 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/rand"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/policy"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+	"github.com/NissesSenap/shepherd/pkg/validate"
 )
 
 const maxCompressedContextSize = 1_400_000 // ~1.4MB, etcd limit minus overhead
 
+const maxTaskPriority = 1000 // Matches the AgentTask CRD validation
+
+const maxTaskDependencies = 16 // Matches the AgentTask CRD validation
+
 // Kubernetes label value regex: must be ≤63 characters and match [a-z0-9A-Z]([a-z0-9A-Z-_.]*[a-z0-9A-Z])? (or empty)
 var labelValueRegex = regexp.MustCompile(`^$|^[a-z0-9A-Z]([a-z0-9A-Z-_.]*[a-z0-9A-Z])?$`)
 
@@ -54,54 +66,283 @@ func validateLabelValue(value string) error {
 	return nil
 }
 
+// filterWildcardHint is reported for filter values with wildcards, which
+// label selectors cannot express.
+const filterWildcardHint = "wildcards are not supported, filters match whole values; " +
+	"use GET /api/v1/tasks/search?q=... to match part of a repository or description"
+
+// labelValueHint describes valid label values in filter errors.
+const labelValueHint = "label values are at most 63 letters, digits, '-', '_' and '.', " +
+	"starting and ending with a letter or digit"
+
+// issuePathSegments name the URL path segment that is followed by the
+// issue number or identifier, on the forges shepherd has adapters for.
+var issuePathSegments = []string{"issues", "pull", "pulls", "issue"}
+
+// repoPathStops are URL path segments that follow the repository path,
+// such as in https://github.com/org/repo/tree/main.
+var repoPathStops = []string{"issues", "pull", "pulls", "tree", "blob", "commit", "-"}
+
 // normalizeRepoFilter converts a repo filter value to a valid Kubernetes label value.
-// It handles full URLs (https://github.com/org/repo), slash forms (org/repo),
-// and already-valid label values (org-repo).
-// NOTE: keep in sync with web/src/lib/filters.ts:repoUrlToLabel
+// It handles full URLs (https://github.com/org/repo), including links to
+// a page of the repository such as an issue, SSH remotes
+// (git@github.com:org/repo.git), slash forms (org/repo), and already-valid
+// label values (org-repo).
+// NOTE: keep in sync with web/src/lib/filters.ts:repoUrlToLabel, which only
+// needs to handle the clone URLs of tasks.
 func normalizeRepoFilter(value string) (string, error) {
-	if strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://") {
+	value = strings.TrimSpace(value)
+	if strings.ContainsAny(value, "*?") {
+		return "", fmt.Errorf("%q: %s", value, filterWildcardHint)
+	}
+	switch {
+	case strings.Contains(value, "://"):
 		u, err := url.Parse(value)
 		if err != nil {
 			return "", fmt.Errorf("invalid repo filter URL: %w", err)
 		}
-		value = strings.TrimPrefix(u.Path, "/")
-		value = strings.TrimSuffix(value, ".git")
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		for i, segment := range segments {
+			if i >= 2 && slices.Contains(repoPathStops, segment) {
+				segments = segments[:i]
+				break
+			}
+		}
+		value = strings.Join(segments, "/")
+	case strings.Contains(value, "@") && strings.Contains(value, ":"):
+		// SSH remote such as git@github.com:org/repo.git.
+		_, value, _ = strings.Cut(value, ":")
 	}
+	value = strings.TrimSuffix(strings.Trim(value, "/"), ".git")
 	value = strings.ReplaceAll(value, "/", "-")
 	if value == "" {
-		return "", fmt.Errorf("repo filter is empty after normalization")
+		return "", fmt.Errorf("repo filter is empty after normalization; " +
+			"use the repository URL, owner/repo, or owner-repo")
 	}
 	if err := validateLabelValue(value); err != nil {
-		return "", fmt.Errorf("repo filter is not a valid label value: %w", err)
+		return "", fmt.Errorf("repo filter %q is not a valid label value: %w; "+
+			"use the repository URL, owner/repo, or owner-repo", value, err)
 	}
 	return value, nil
 }
 
+// normalizeIssueFilter converts an issue filter value to the
+// shepherd.io/issue label value: the issue number, or the identifier for
+// Linear issues. Besides that form it accepts "#42" and links to the issue
+// or pull request. For a link it also returns the repository's label
+// value, or "" if the link does not name one.
+func normalizeIssueFilter(value string) (issue, repo string, err error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "#")
+	if strings.ContainsAny(value, "*?") {
+		return "", "", fmt.Errorf("%q: %s", value, filterWildcardHint)
+	}
+	if strings.Contains(value, "://") {
+		u, err := url.Parse(value)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid issue filter URL: %w", err)
+		}
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		idx := slices.IndexFunc(segments, func(segment string) bool {
+			return slices.Contains(issuePathSegments, segment)
+		})
+		if idx < 0 || idx+1 >= len(segments) {
+			return "", "", fmt.Errorf("issue filter URL %q does not link to an issue or pull request", value)
+		}
+		issue = segments[idx+1]
+		if segments[idx] != "issue" && idx >= 2 {
+			// Linear issue links name the workspace, not a repository.
+			repo = strings.Join(segments[:idx], "-")
+			repo = strings.TrimSuffix(repo, "--") // GitLab's /-/issues/ separator
+		}
+		value = issue
+	}
+	if value == "" {
+		return "", "", fmt.Errorf("issue filter is empty after normalization; " +
+			"use the issue number, #number, or the issue URL")
+	}
+	if err := validateLabelValue(value); err != nil {
+		return "", "", fmt.Errorf("issue filter %q is not a valid label value: %w; "+
+			"use the issue number, #number, or the issue URL", value, err)
+	}
+	return value, repo, nil
+}
+
+// normalizeFleetFilter validates a fleet filter value, which must be the
+// fleet's ID.
+func normalizeFleetFilter(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if strings.ContainsAny(value, "*?") {
+		return "", fmt.Errorf("%q: %s", value, filterWildcardHint)
+	}
+	if err := validateLabelValue(value); err != nil {
+		return "", fmt.Errorf("fleet filter %q is not a valid label value: %w; use the fleet ID (%s)",
+			value, err, labelValueHint)
+	}
+	return value, nil
+}
+
+// taskPhases lists the phases reported in TaskStatusSummary.Phase, which
+// is the task's status.phase.
+var taskPhases = []string{
+	string(toolkitv1alpha1.PhasePending),
+	string(toolkitv1alpha1.PhaseProvisioning),
+	string(toolkitv1alpha1.PhaseRunning),
+	string(toolkitv1alpha1.PhaseSucceeded),
+	string(toolkitv1alpha1.PhaseFailed),
+	string(toolkitv1alpha1.PhaseTimedOut),
+	string(toolkitv1alpha1.PhaseCancelled),
+}
+
+// parsePhaseFilter parses one or more phase query values, each of which may
+// be a comma-separated list (e.g. "Failed,TimedOut"). Phases are matched
+// case-insensitively and returned in canonical form. A nil set means no
+// phase filter.
+func parsePhaseFilter(values []string) (map[string]bool, error) {
+	var phases map[string]bool
+	for _, value := range values {
+		for p := range strings.SplitSeq(value, ",") {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			idx := slices.IndexFunc(taskPhases, func(known string) bool {
+				return strings.EqualFold(known, p)
+			})
+			if idx < 0 {
+				return nil, fmt.Errorf("unknown phase %q, must be one of %s", p, strings.Join(taskPhases, ", "))
+			}
+			if phases == nil {
+				phases = make(map[string]bool)
+			}
+			phases[taskPhases[idx]] = true
+		}
+	}
+	return phases, nil
+}
+
+// taskSortFields lists the fields accepted by the sort query parameter.
+var taskSortFields = []string{"createdAt", "completionTime", "phase"}
+
+// parseTaskSort validates the sort and order query parameters. An empty sort
+// field keeps the order returned by the Kubernetes list. Order defaults to
+// ascending.
+func parseTaskSort(field, order string) (string, bool, error) {
+	if field != "" && !slices.Contains(taskSortFields, field) {
+		return "", false, fmt.Errorf("unknown sort field %q, must be one of %s", field, strings.Join(taskSortFields, ", "))
+	}
+	switch order {
+	case "", "asc":
+		return field, false, nil
+	case "desc":
+		return field, true, nil
+	default:
+		return "", false, fmt.Errorf("unknown order %q, must be asc or desc", order)
+	}
+}
+
+// sortTasks sorts tasks by the given field, breaking ties by ID. Tasks
+// without a completion time always sort last when sorting by completionTime.
+// Timestamps are RFC3339 in UTC, so they compare correctly as strings.
+func sortTasks(tasks []TaskResponse, field string, desc bool) {
+	if field == "" {
+		return
+	}
+	slices.SortStableFunc(tasks, func(a, b TaskResponse) int {
+		var c int
+		switch field {
+		case "createdAt":
+			c = strings.Compare(a.CreatedAt, b.CreatedAt)
+		case "completionTime":
+			switch {
+			case a.CompletionTime == nil && b.CompletionTime == nil:
+			case a.CompletionTime == nil:
+				return 1
+			case b.CompletionTime == nil:
+				return -1
+			default:
+				c = strings.Compare(*a.CompletionTime, *b.CompletionTime)
+			}
+		case "phase":
+			c = strings.Compare(a.Status.Phase, b.Status.Phase)
+		}
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+}
+
 // taskHandler holds dependencies for task endpoints.
 type taskHandler struct {
-	client       client.Client
-	namespace    string
-	callback     *callbackSender
-	githubClient TokenProvider // nil if GitHub App not configured
-	eventHub     *EventHub
+	client         client.Client // Templates, fleets and the maintenance ConfigMap
+	tasks          TaskStore
+	namespace      string
+	callback       *callbackSender
+	githubClient   TokenProvider // nil if neither the GitHub App nor a Gitea token is configured
+	eventHub       EventStore
+	feed           *taskFeed
+	quota          TaskQuota
+	archiver       *taskArchiver    // nil if archiving is not configured
+	policy         policy.Evaluator // nil if no admission policy is configured
+	policyFailOpen bool             // Create tasks when policy evaluation fails
+	validation     validate.Options
+	repoSizer      RepoSizer // nil if GitHub App not configured
+	repoSize       RepoSizeLimits
+	eventPrivacy   EventPrivacyOptions
+	taskIndex      *taskIndex // nil looks up active tasks in the informer cache
+
+	// maxTimeoutExtension bounds the total extension of a task's timeout;
+	// zero disables extensions.
+	maxTimeoutExtension time.Duration
+
+	// callbackTargets checks the addresses of callback hosts; nil only
+	// checks the callback URL itself.
+	callbackTargets *validate.CallbackTargets
 }
 
 // createTask handles POST /api/v1/tasks.
 func (h *taskHandler) createTask(w http.ResponseWriter, r *http.Request) {
-	log := ctrl.Log.WithName("api")
+	log := logf.FromContext(r.Context())
+	if h.rejectDuringMaintenance(w, r) {
+		return
+	}
 	r.Body = http.MaxBytesReader(w, r.Body, 10<<20) // 10 MiB
 	var req CreateTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body", err.Error())
 		return
 	}
+	correlationID, ok := requestCorrelationID(w, r)
+	if !ok {
+		return
+	}
+	log = log.WithValues(logging.Repo, req.Repo.URL, logging.CorrelationID, correlationID)
+
+	var tmpl *toolkitv1alpha1.TaskTemplate
+	if req.TemplateRef != "" {
+		tmpl = &toolkitv1alpha1.TaskTemplate{}
+		err := h.client.Get(r.Context(), client.ObjectKey{Namespace: h.namespace, Name: req.TemplateRef}, tmpl)
+		if errors.IsNotFound(err) {
+			writeError(w, http.StatusBadRequest, "invalid templateRef", fmt.Sprintf("task template %q not found", req.TemplateRef))
+			return
+		}
+		if err != nil {
+			log.Error(err, "failed to get task template", "template", req.TemplateRef)
+			writeError(w, http.StatusInternalServerError, "failed to get task template", "")
+			return
+		}
+		applyTaskTemplate(&req, tmpl)
+	}
 
 	// Validate required fields
 	if req.Repo.URL == "" {
 		writeError(w, http.StatusBadRequest, "repo.url is required", "")
 		return
 	}
-	if len(req.Repo.URL) < 8 || req.Repo.URL[:8] != "https://" {
+	if validate.RepoURL(req.Repo.URL) != nil {
 		writeError(w, http.StatusBadRequest, "repo.url must start with https://", "CRD schema requires HTTPS URLs")
 		return
 	}
@@ -114,33 +355,44 @@ func (h *taskHandler) createTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate callback URL
-	parsedURL, err := url.Parse(req.Callback)
-	if err != nil {
+	if err := h.callbackTargets.Check(r.Context(), req.Callback); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid callbackURL", err.Error())
 		return
 	}
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		writeError(w, http.StatusBadRequest, "invalid callbackURL scheme", "must be http or https")
+	switch req.CallbackFormat {
+	case "", toolkitv1alpha1.CallbackFormatJSON, toolkitv1alpha1.CallbackFormatCloudEvents:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid callbackFormat", "must be json or cloudevents")
 		return
 	}
-	// Validate hostname is non-empty
-	hostname := parsedURL.Hostname()
-	if hostname == "" {
-		writeError(w, http.StatusBadRequest, "invalid callbackURL host", "hostname is empty")
+
+	if req.Priority < 0 || req.Priority > maxTaskPriority {
+		writeError(w, http.StatusBadRequest, "invalid priority",
+			fmt.Sprintf("must be between 0 and %d", maxTaskPriority))
 		return
 	}
-	// Block well-known metadata IPs and localhost
-	blockedHosts := map[string]bool{
-		"169.254.169.254": true,
-		"localhost":       true,
-		"127.0.0.1":       true,
-		"::1":             true,
-		"[::1]":           true,
-		"0.0.0.0":         true,
-	}
-	if blockedHosts[hostname] {
-		writeError(w, http.StatusBadRequest, "invalid callbackURL host", "blocked host")
+
+	if len(req.DependsOn) > maxTaskDependencies {
+		writeError(w, http.StatusBadRequest, "invalid dependsOn",
+			fmt.Sprintf("at most %d dependencies are allowed", maxTaskDependencies))
+		return
+	}
+	for _, dep := range req.DependsOn {
+		_, err := h.tasks.Get(r.Context(), dep, ReadConsistent)
+		if errors.IsNotFound(err) {
+			writeError(w, http.StatusBadRequest, "invalid dependsOn", fmt.Sprintf("task %q not found", dep))
+			return
+		}
+		if err != nil {
+			log.Error(err, "failed to get dependency", "dependency", dep)
+			writeError(w, http.StatusInternalServerError, "failed to get dependency", "")
+			return
+		}
+	}
+
+	if mode, ok := req.Labels[ModeLabel]; ok && !ValidMode(mode) {
+		writeError(w, http.StatusBadRequest, "invalid "+ModeLabel+" label",
+			fmt.Sprintf("must be %s, %s or %s", ModeFix, ModePlan, ModeReview))
 		return
 	}
 
@@ -150,6 +402,17 @@ func (h *taskHandler) createTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	exceeded, err := h.checkQuota(r.Context(), req.Repo.URL)
+	if err != nil {
+		log.Error(err, "failed to check task quota")
+		writeError(w, http.StatusInternalServerError, "failed to check task quota", "")
+		return
+	}
+	if exceeded != "" {
+		writeError(w, http.StatusTooManyRequests, "task quota exceeded", exceeded)
+		return
+	}
+
 	// Compress context (if provided)
 	var compressedCtx, encoding string
 	if req.Task.Context != "" {
@@ -181,9 +444,26 @@ func (h *taskHandler) createTask(w http.ResponseWriter, r *http.Request) {
 				writeError(w, http.StatusBadRequest, "invalid runner.timeout", err.Error())
 				return
 			}
+			if err := validate.Timeout(d); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid runner.timeout", err.Error())
+				return
+			}
 			runnerSpec.Timeout = metav1.Duration{Duration: d}
 		}
 		runnerSpec.ServiceAccountName = req.Runner.ServiceAccountName
+		if err := validate.Model(req.Runner.Model); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid runner.model", err.Error())
+			return
+		}
+		if err := validate.BranchPrefix(req.Runner.BranchPrefix); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid runner.branchPrefix", err.Error())
+			return
+		}
+		runnerSpec.Model = req.Runner.Model
+		runnerSpec.BranchPrefix = req.Runner.BranchPrefix
+	}
+	if tmpl != nil {
+		runnerSpec.Resources = tmpl.Spec.Runner.Resources
 	}
 
 	// Validate SourceType and SourceID as Kubernetes label values
@@ -231,28 +511,100 @@ func (h *taskHandler) createTask(w http.ResponseWriter, r *http.Request) {
 				SourceID:        req.Task.SourceID,
 			},
 			Callback: toolkitv1alpha1.CallbackSpec{
-				URL: req.Callback,
+				URL:    req.Callback,
+				Format: req.CallbackFormat,
 			},
-			Runner: runnerSpec,
+			Runner:    runnerSpec,
+			Priority:  req.Priority,
+			DependsOn: slices.Compact(slices.Sorted(slices.Values(req.DependsOn))),
 		},
 	}
 
-	if err := h.client.Create(r.Context(), task); err != nil {
+	if !h.submitTask(w, r, log, task, correlationID) {
+		return
+	}
+	writeJSON(w, http.StatusCreated, taskToResponse(task))
+}
+
+// requestCorrelationID returns the correlation ID of a request, generating
+// one if it has none. An invalid one is answered with 400 and ok false.
+func requestCorrelationID(w http.ResponseWriter, r *http.Request) (correlationID string, ok bool) {
+	correlationID = r.Header.Get(logging.CorrelationIDHeader)
+	if correlationID == "" {
+		return logging.NewCorrelationID(), true
+	}
+	if !logging.ValidCorrelationID(correlationID) {
+		writeError(w, http.StatusBadRequest, "invalid "+logging.CorrelationIDHeader+" header",
+			"must be 1-64 letters, digits, '.', '_' or '-'")
+		return "", false
+	}
+	return correlationID, true
+}
+
+// submitTask evaluates task policies and the repository size against a
+// task built by createTask or retryTask, and creates it. On failure it
+// writes the error response and returns false.
+func (h *taskHandler) submitTask(w http.ResponseWriter, r *http.Request, log logr.Logger,
+	task *toolkitv1alpha1.AgentTask, correlationID string) bool {
+	// Record the correlation ID and the request's trace on the task, so the
+	// operator's reconciles and the runner's work carry them.
+	task.Annotations = map[string]string{logging.CorrelationIDAnnotation: correlationID}
+	tracing.InjectAnnotations(r.Context(), task.Annotations)
+
+	if h.policy != nil {
+		decision, err := h.policy.Evaluate(r.Context(), policy.NewInput(task, time.Now()))
+		switch {
+		case err != nil && h.policyFailOpen:
+			log.Error(err, "failed to evaluate task policy, creating task anyway")
+		case err != nil:
+			log.Error(err, "failed to evaluate task policy")
+			writeError(w, http.StatusInternalServerError, "failed to evaluate task policy", "")
+			return false
+		case !decision.Allowed:
+			log.Info("task denied by policy", "policy", decision.Policy)
+			writeError(w, http.StatusForbidden, "denied by policy", decision.Message)
+			return false
+		default:
+			decision.Apply(task)
+		}
+	}
+
+	// Checked after policies, which may change the template.
+	requestedTemplate := task.Spec.Runner.SandboxTemplateName
+	tooLarge, err := h.checkRepoSize(r.Context(), task)
+	if err != nil {
+		// The clone will still fail visibly; an unreachable GitHub API
+		// should not stop tasks from being created.
+		log.Error(err, "failed to check repository size, creating task anyway")
+	}
+	if tooLarge != "" {
+		writeError(w, http.StatusUnprocessableEntity, "repository too large for sandbox", tooLarge)
+		return false
+	}
+	if t := task.Spec.Runner.SandboxTemplateName; t != requestedTemplate {
+		log.Info("repository too large for sandbox template, using a larger one", "from", requestedTemplate, "to", t)
+	}
+	if err := h.validation.SandboxTemplate(task.Spec.Runner.SandboxTemplateName); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid runner.sandboxTemplateName", err.Error())
+		return false
+	}
+
+	if err := h.tasks.Create(r.Context(), task); err != nil {
 		if errors.IsAlreadyExists(err) {
 			writeError(w, http.StatusConflict, "task already exists", err.Error())
-			return
+			return false
 		}
 		if errors.IsInvalid(err) {
 			writeError(w, http.StatusBadRequest, "invalid task specification", err.Error())
-			return
+			return false
 		}
 		log.Error(err, "failed to create task")
 		writeError(w, http.StatusInternalServerError, "failed to create task", "")
-		return
+		return false
 	}
-
-	resp := taskToResponse(task)
-	writeJSON(w, http.StatusCreated, resp)
+	log.Info("created task", logging.TaskID, task.Name)
+	h.taskIndex.put(sourceKey(task.Spec.Task.SourceURL), task.Name)
+	return true
 }
 
 // listTasks handles GET /api/v1/tasks.
@@ -261,80 +613,143 @@ func (h *taskHandler) createTask(w http.ResponseWriter, r *http.Request) {
 //   - issue: filter by shepherd.io/issue label
 //   - active: if "true", only return tasks with Succeeded=Unknown (non-terminal)
 func (h *taskHandler) listTasks(w http.ResponseWriter, r *http.Request) {
-	log := ctrl.Log.WithName("api")
-	var taskList toolkitv1alpha1.AgentTaskList
+	log := logf.FromContext(r.Context())
 
-	listOpts := []client.ListOption{
-		client.InNamespace(h.namespace),
+	labelSelector, msg, err := taskLabelFilters(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, msg, err.Error())
+		return
 	}
 
-	// Build label selector from query params
-	labelSelector := map[string]string{}
-	if repo := r.URL.Query().Get("repo"); repo != "" {
-		normalized, err := normalizeRepoFilter(repo)
-		if err != nil {
-			writeError(w, http.StatusBadRequest, "invalid repo filter", err.Error())
-			return
-		}
-		labelSelector["shepherd.io/repo"] = normalized
-	}
-	if issue := r.URL.Query().Get("issue"); issue != "" {
-		if err := validateLabelValue(issue); err != nil {
-			writeError(w, http.StatusBadRequest, "invalid issue filter", err.Error())
-			return
-		}
-		labelSelector["shepherd.io/issue"] = issue
-	}
-	if fleet := r.URL.Query().Get("fleet"); fleet != "" {
-		if err := validateLabelValue(fleet); err != nil {
-			writeError(w, http.StatusBadRequest, "invalid fleet filter", err.Error())
-			return
-		}
-		labelSelector["shepherd.io/fleet"] = fleet
+	phases, err := parsePhaseFilter(r.URL.Query()["phase"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid phase filter", err.Error())
+		return
 	}
-	if len(labelSelector) > 0 {
-		listOpts = append(listOpts, client.MatchingLabels(labelSelector))
+
+	sortField, sortDesc, err := parseTaskSort(r.URL.Query().Get("sort"), r.URL.Query().Get("order"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid sort", err.Error())
+		return
 	}
 
-	if err := h.client.List(r.Context(), &taskList, listOpts...); err != nil {
+	items, err := h.tasks.List(r.Context(), labels.SelectorFromSet(labelSelector), readConsistency(r))
+	if err != nil {
 		log.Error(err, "failed to list tasks")
 		writeError(w, http.StatusInternalServerError, "failed to list tasks", "")
 		return
 	}
 
-	// Filter active tasks in-memory if requested
+	// Filter active tasks and phases in-memory if requested
 	active := r.URL.Query().Get("active") == "true"
 
-	tasks := make([]TaskResponse, 0, len(taskList.Items))
-	for i := range taskList.Items {
-		task := &taskList.Items[i]
+	tasks := make([]TaskResponse, 0, len(items))
+	for i := range items {
+		task := &items[i]
 		if active && task.IsTerminal() {
 			continue
 		}
-		tasks = append(tasks, taskToResponse(task))
+		resp := taskToResponse(task)
+		if phases != nil && !phases[resp.Status.Phase] {
+			continue
+		}
+		tasks = append(tasks, resp)
 	}
+	sortTasks(tasks, sortField, sortDesc)
 
 	writeJSON(w, http.StatusOK, tasks)
 }
 
+// taskLabelFilters builds a label selector from the repo, issue and fleet
+// query parameters, normalizing common inputs such as repository and issue
+// URLs to the label values tasks are stored with. On error it also returns
+// the message to report.
+func taskLabelFilters(query url.Values) (map[string]string, string, error) {
+	labelSelector := map[string]string{}
+	if repo := query.Get("repo"); repo != "" {
+		normalized, err := normalizeRepoFilter(repo)
+		if err != nil {
+			return nil, "invalid repo filter", err
+		}
+		labelSelector["shepherd.io/repo"] = normalized
+	}
+	if issue := query.Get("issue"); issue != "" {
+		normalized, repo, err := normalizeIssueFilter(issue)
+		if err != nil {
+			return nil, "invalid issue filter", err
+		}
+		if repo != "" {
+			if selected, ok := labelSelector["shepherd.io/repo"]; ok && selected != repo {
+				return nil, "invalid issue filter", fmt.Errorf(
+					"issue URL is in repository %s, but the repo filter selects %s", repo, selected)
+			}
+			labelSelector["shepherd.io/repo"] = repo
+		}
+		labelSelector["shepherd.io/issue"] = normalized
+	}
+	if fleet := query.Get("fleet"); fleet != "" {
+		normalized, err := normalizeFleetFilter(fleet)
+		if err != nil {
+			return nil, "invalid fleet filter", err
+		}
+		labelSelector["shepherd.io/fleet"] = normalized
+	}
+	return labelSelector, "", nil
+}
+
+// getActiveTask handles GET /api/v1/tasks/active?sourceURL=...
+// It returns the newest non-terminal task created for the source, such as
+// an issue URL, so adapters can skip duplicate triggers without listing
+// tasks.
+func (h *taskHandler) getActiveTask(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	sourceURL := r.URL.Query().Get("sourceURL")
+	if sourceURL == "" {
+		writeError(w, http.StatusBadRequest, "sourceURL is required", "")
+		return
+	}
+
+	task, err := h.activeTaskFor(r.Context(), sourceURL)
+	if err != nil {
+		log.Error(err, "failed to look up active task", "sourceURL", sourceURL)
+		writeError(w, http.StatusInternalServerError, "failed to look up active task", "")
+		return
+	}
+	if task == nil {
+		writeError(w, http.StatusNotFound, "no active task", "")
+		return
+	}
+	writeJSON(w, http.StatusOK, taskToResponse(task))
+}
+
 // getTask handles GET /api/v1/tasks/{taskID}.
 func (h *taskHandler) getTask(w http.ResponseWriter, r *http.Request) {
-	log := ctrl.Log.WithName("api")
+	log := logf.FromContext(r.Context())
 	taskID := chi.URLParam(r, "taskID")
 
-	var task toolkitv1alpha1.AgentTask
-	key := client.ObjectKey{Namespace: h.namespace, Name: taskID}
-	if err := h.client.Get(r.Context(), key, &task); err != nil {
+	task, err := h.tasks.Get(r.Context(), taskID, readConsistency(r))
+	if err != nil {
 		if errors.IsNotFound(err) {
 			writeError(w, http.StatusNotFound, "task not found", "")
 			return
 		}
-		log.Error(err, "failed to get task", "taskID", taskID)
+		log.Error(err, "failed to get task", logging.TaskID, taskID)
 		writeError(w, http.StatusInternalServerError, "failed to get task", "")
 		return
 	}
+	logging.ForTask(log, task.Annotations).V(1).Info("serving task", logging.TaskID, taskID)
 
-	writeJSON(w, http.StatusOK, taskToResponse(&task))
+	writeJSON(w, http.StatusOK, taskToResponse(task))
+}
+
+// readConsistency returns how current a request's reads must be: cached
+// reads may briefly lag behind the Kubernetes API, unless the request asks
+// for a live read with ?consistent=true.
+func readConsistency(r *http.Request) Consistency {
+	if r.URL.Query().Get("consistent") == "true" {
+		return ReadConsistent
+	}
+	return ReadCached
 }
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
@@ -368,30 +783,97 @@ func taskToResponse(task *toolkitv1alpha1.AgentTask) TaskResponse {
 			SourceType:  task.Spec.Task.SourceType,
 			SourceID:    task.Spec.Task.SourceID,
 		},
-		CallbackURL: task.Spec.Callback.URL,
-		Status:      extractStatus(task),
-		CreatedAt:   task.CreationTimestamp.UTC().Format(time.RFC3339),
+		CallbackURL:   task.Spec.Callback.URL,
+		RequestedBy:   task.Labels["shepherd.io/requested-by"],
+		CorrelationID: task.Annotations[logging.CorrelationIDAnnotation],
+		Priority:      task.Spec.Priority,
+		DependsOn:     task.Spec.DependsOn,
+		Status:        extractStatus(task),
+		CreatedAt:     task.CreationTimestamp.UTC().Format(time.RFC3339),
 	}
 	if task.Status.CompletionTime != nil {
 		ct := task.Status.CompletionTime.UTC().Format(time.RFC3339)
 		resp.CompletionTime = &ct
 	}
+	now := time.Now()
+	resp.QueuedSeconds, resp.RunningSeconds, resp.TotalSeconds = taskDurations(task, now)
+	resp.Status.Deadline, resp.Status.RemainingSeconds = taskDeadline(task, now)
+	for _, note := range task.Status.Notes {
+		resp.Notes = append(resp.Notes, noteToResponse(note))
+	}
+	for _, ext := range task.Status.TimeoutExtensions {
+		resp.TimeoutExtensions = append(resp.TimeoutExtensions, TimeoutExtensionResponse{
+			Duration:  ext.Duration.Duration.String(),
+			Reason:    ext.Reason,
+			CreatedAt: ext.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
 	return resp
 }
 
+// taskDurations splits a task's lifetime into time spent waiting for a
+// runner (creation to start) and time spent running (start to completion).
+// Intervals that have not ended yet are measured up to now. A task that
+// finished without starting, e.g. cancelled while queued, was queued for its
+// whole lifetime.
+func taskDurations(task *toolkitv1alpha1.AgentTask, now time.Time) (queued, running, total int64) {
+	created := task.CreationTimestamp.Time
+	end := now
+	if task.Status.CompletionTime != nil {
+		end = task.Status.CompletionTime.Time
+	}
+
+	total = seconds(end.Sub(created))
+	if task.Status.StartTime == nil {
+		return total, 0, total
+	}
+	start := task.Status.StartTime.Time
+	return seconds(start.Sub(created)), seconds(end.Sub(start)), total
+}
+
+// taskDeadline returns when the runner's timeout of a started task expires
+// and, while the task has not finished, the seconds left until then.
+func taskDeadline(task *toolkitv1alpha1.AgentTask, now time.Time) (string, *int64) {
+	if task.Status.StartTime == nil {
+		return "", nil
+	}
+	deadline := task.Status.StartTime.Add(task.RunnerTimeout())
+	if task.IsTerminal() {
+		return deadline.UTC().Format(time.RFC3339), nil
+	}
+	remaining := seconds(deadline.Sub(now))
+	return deadline.UTC().Format(time.RFC3339), &remaining
+}
+
+// seconds converts d to whole seconds, clamping negative values that clock
+// skew between components can produce.
+func seconds(d time.Duration) int64 {
+	return max(0, int64(d/time.Second))
+}
+
 func extractStatus(task *toolkitv1alpha1.AgentTask) TaskStatusSummary {
-	cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
-	phase := "Pending"
+	// Tasks the operator has not reconciled since status.phase was added
+	// have no phase yet; compute it the way the operator would.
+	phase := task.Status.Phase
+	if phase == "" {
+		phase = task.ComputePhase()
+	}
 	message := ""
-	if cond != nil {
-		phase = cond.Reason
+	if cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded); cond != nil {
 		message = cond.Message
 	}
-	return TaskStatusSummary{
-		Phase:            phase,
-		Message:          message,
-		SandboxClaimName: task.Status.SandboxClaimName,
-		PRURL:            task.Status.Result.PRURL,
-		Error:            task.Status.Result.Error,
+	summary := TaskStatusSummary{
+		Phase:               string(phase),
+		Message:             message,
+		SandboxClaimName:    task.Status.SandboxClaimName,
+		SandboxTemplateName: task.Status.SandboxTemplateName,
+		PRURL:               task.Status.Result.PRURL,
+		Error:               task.Status.Result.Error,
+		Summary:             task.Status.Result.Summary,
+	}
+	if task.Status.Result.CostUSD != "" {
+		// Stored by the API itself, so a parse failure is not expected
+		summary.CostUSD, _ = strconv.ParseFloat(task.Status.Result.CostUSD, 64)
 	}
+	return summary
 }
@@ -27,6 +27,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -38,6 +39,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+	"github.com/NissesSenap/shepherd/pkg/validate"
 )
 
 func testScheme() *runtime.Scheme {
@@ -55,9 +58,11 @@ func newTestHandler(objs ...client.Object) *taskHandler {
 	c := builder.Build()
 	return &taskHandler{
 		client:    c,
+		tasks:     newKubeTaskStore(c, nil, "default"),
 		namespace: "default",
 		callback:  newCallbackSender(""),
 		eventHub:  NewEventHub(),
+		feed:      newTaskFeed(),
 	}
 }
 
@@ -66,12 +71,30 @@ func testRouter(h *taskHandler) *chi.Mux {
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Post("/tasks", h.createTask)
 		r.Get("/tasks", h.listTasks)
+		r.Get("/tasks/search", h.searchTasks)
+		r.Get("/tasks/active", h.getActiveTask)
+		r.Get("/tasks/watch", h.watchTasks)
 		r.Get("/tasks/{taskID}", h.getTask)
 		r.Get("/tasks/{taskID}/events", h.streamEvents)
+		r.Post("/tasks/{taskID}/notes", h.addNote)
+		r.Post("/tasks/{taskID}/extend", h.extendTimeout)
+		r.Post("/tasks/{taskID}/cancel", h.cancelTask)
+		r.Post("/tasks/{taskID}/retry", h.retryTask)
+		r.Get("/tasks/{taskID}/callbacks", h.getCallbacks)
+		r.Post("/tasks/{taskID}/callbacks/replay", h.replayCallback)
+		r.Get("/fleets/{fleetID}", h.getFleet)
+		r.Post("/task-templates", h.createTaskTemplate)
+		r.Get("/task-templates", h.listTaskTemplates)
+		r.Get("/task-templates/{templateName}", h.getTaskTemplate)
 		r.Post("/tasks/{taskID}/status", h.updateTaskStatus)
 		r.Post("/tasks/{taskID}/events", h.postEvents)
 		r.Get("/tasks/{taskID}/data", h.getTaskData)
 		r.Get("/tasks/{taskID}/token", h.getTaskToken)
+		r.Get("/event-schemas", h.getEventSchemas)
+		r.Get("/maintenance", h.getMaintenance)
+		r.Get("/callback-signing-key", h.getCallbackSigningKey)
+		r.Get("/openapi.json", getOpenAPISpec)
+		r.Route("/admin", h.adminRoutes)
 	})
 	return r
 }
@@ -158,6 +181,74 @@ func TestCreateTask_ContextIsCompressed(t *testing.T) {
 		"context should be compressed, not stored as plaintext")
 }
 
+func TestCreateTask_CorrelationID(t *testing.T) {
+	h := newTestHandler()
+	router := testRouter(h)
+
+	create := func(header string) *httptest.ResponseRecorder {
+		data, err := json.Marshal(validCreateRequest())
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks", bytes.NewReader(data))
+		if header != "" {
+			req.Header.Set("X-Correlation-ID", header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	stored := func(resp TaskResponse) string {
+		var task toolkitv1alpha1.AgentTask
+		require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+		return task.Annotations["shepherd.io/correlation-id"]
+	}
+
+	// Generated when the adapter sends none
+	w := create("")
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.CorrelationID, 32)
+	assert.Equal(t, resp.CorrelationID, stored(resp))
+
+	// The adapter's own ID is kept
+	w = create("72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "72d3162e-cc78-11e3-81ab-4c9367dc0958", resp.CorrelationID)
+	assert.Equal(t, resp.CorrelationID, stored(resp))
+
+	w = create("not valid!")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateTask_RecordsTrace(t *testing.T) {
+	h := newTestHandler()
+	router := testRouter(h)
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	data, err := json.Marshal(validCreateRequest())
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks", bytes.NewReader(data))
+	req = req.WithContext(tracing.FromTraceParent(req.Context(), traceParent))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+	assert.Equal(t, traceParent, task.Annotations[tracing.TraceParentAnnotation])
+
+	// Without a trace the task gets no traceparent.
+	w = postCreateTask(t, router, validCreateRequest())
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	task = toolkitv1alpha1.AgentTask{}
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+	assert.NotContains(t, task.Annotations, tracing.TraceParentAnnotation)
+}
+
 func TestCreateTask_MissingRepoURL(t *testing.T) {
 	h := newTestHandler()
 	router := testRouter(h)
@@ -239,6 +330,29 @@ func TestCreateTask_MissingCallbackURL(t *testing.T) {
 	assert.Equal(t, "callbackURL is required", errResp.Error)
 }
 
+func TestCreateTask_InternalCallbackAddress(t *testing.T) {
+	targets, err := validate.NewCallbackTargets(validate.CallbackTargetOptions{Internal: []string{"10.96.0.0/12"}})
+	require.NoError(t, err)
+	h := newTestHandler()
+	h.callbackTargets = targets
+	router := testRouter(h)
+
+	req := validCreateRequest()
+	req.Callback = "http://10.0.0.7:8082/callback"
+	w := postCreateTask(t, router, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "invalid callbackURL", errResp.Error)
+	assert.Contains(t, errResp.Details, "blocked address 10.0.0.7")
+
+	// Addresses in an allowed range, such as the service network, pass.
+	req.Callback = "http://10.96.0.12:8082/callback"
+	w = postCreateTask(t, router, req)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
+
 func TestCreateTask_MissingSandboxTemplateName(t *testing.T) {
 	h := newTestHandler()
 	router := testRouter(h)
@@ -319,6 +433,206 @@ func TestCreateTask_InvalidRunnerTimeout(t *testing.T) {
 	assert.Equal(t, "invalid runner.timeout", errResp.Error)
 }
 
+func TestCreateTask_RunnerTimeoutOutOfBounds(t *testing.T) {
+	h := newTestHandler()
+	router := testRouter(h)
+
+	for _, timeout := range []string{"10s", "48h"} {
+		req := validCreateRequest()
+		req.Runner = &RunnerConfig{SandboxTemplateName: "default-template", Timeout: timeout}
+		w := postCreateTask(t, router, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, timeout)
+		var errResp ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+		assert.Equal(t, "invalid runner.timeout", errResp.Error)
+		assert.Contains(t, errResp.Details, "must be between 1m0s and 24h0m0s")
+	}
+}
+
+func TestCreateTask_RunnerModelAndBranchPrefix(t *testing.T) {
+	h := newTestHandler()
+	router := testRouter(h)
+
+	req := validCreateRequest()
+	req.Runner = &RunnerConfig{SandboxTemplateName: "default-template", Model: "claude-opus-4-1", BranchPrefix: "bot/"}
+	w := postCreateTask(t, router, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+	assert.Equal(t, "claude-opus-4-1", task.Spec.Runner.Model)
+	assert.Equal(t, "bot/"+resp.ID, task.BranchName())
+
+	for field, runner := range map[string]RunnerConfig{
+		"invalid runner.model":        {SandboxTemplateName: "default-template", Model: "opus; rm -rf"},
+		"invalid runner.branchPrefix": {SandboxTemplateName: "default-template", BranchPrefix: "../main"},
+	} {
+		req := validCreateRequest()
+		req.Runner = &runner
+		w := postCreateTask(t, router, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, field)
+		var errResp ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+		assert.Equal(t, field, errResp.Error)
+	}
+}
+
+func TestCreateTask_SandboxTemplateAllowlist(t *testing.T) {
+	h := newTestHandler()
+	h.validation = validate.Options{AllowedSandboxTemplates: []string{"default-template"}}
+	router := testRouter(h)
+
+	w := postCreateTask(t, router, validCreateRequest())
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	req := validCreateRequest()
+	req.Runner = &RunnerConfig{SandboxTemplateName: "gpu"}
+	w = postCreateTask(t, router, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "invalid runner.sandboxTemplateName", errResp.Error)
+}
+
+func TestCreateTask_Priority(t *testing.T) {
+	h := newTestHandler()
+	router := testRouter(h)
+
+	req := validCreateRequest()
+	req.Priority = 500
+	w := postCreateTask(t, router, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int32(500), resp.Priority)
+
+	var task toolkitv1alpha1.AgentTask
+	err := h.client.Get(context.Background(), client.ObjectKey{
+		Namespace: "default",
+		Name:      resp.ID,
+	}, &task)
+	require.NoError(t, err)
+	assert.Equal(t, int32(500), task.Spec.Priority)
+}
+
+func TestCreateTask_CallbackFormat(t *testing.T) {
+	h := newTestHandler()
+	router := testRouter(h)
+
+	req := validCreateRequest()
+	req.CallbackFormat = toolkitv1alpha1.CallbackFormatCloudEvents
+	w := postCreateTask(t, router, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+	assert.Equal(t, toolkitv1alpha1.CallbackFormatCloudEvents, task.Spec.Callback.Format)
+
+	req.CallbackFormat = "xml"
+	w = postCreateTask(t, router, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "invalid callbackFormat", errResp.Error)
+}
+
+func TestCreateTask_InvalidPriority(t *testing.T) {
+	for _, priority := range []int32{-1, 1001} {
+		t.Run(fmt.Sprint(priority), func(t *testing.T) {
+			h := newTestHandler()
+			router := testRouter(h)
+
+			req := validCreateRequest()
+			req.Priority = priority
+			w := postCreateTask(t, router, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+			assert.Equal(t, "invalid priority", errResp.Error)
+		})
+	}
+}
+
+func TestCreateTask_InvalidMode(t *testing.T) {
+	router := testRouter(newTestHandler())
+
+	req := validCreateRequest()
+	req.Labels = map[string]string{ModeLabel: "refactor"}
+	w := postCreateTask(t, router, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "invalid shepherd.io/mode label", errResp.Error)
+
+	req.Labels[ModeLabel] = ModeReview
+	assert.Equal(t, http.StatusCreated, postCreateTask(t, router, req).Code)
+}
+
+func TestCreateTask_DependsOn(t *testing.T) {
+	dep := &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "task-first", Namespace: "default"},
+	}
+	h := newTestHandler(dep)
+	router := testRouter(h)
+
+	req := validCreateRequest()
+	req.DependsOn = []string{"task-first", "task-first"}
+	w := postCreateTask(t, router, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"task-first"}, resp.DependsOn)
+
+	var task toolkitv1alpha1.AgentTask
+	err := h.client.Get(context.Background(), client.ObjectKey{
+		Namespace: "default",
+		Name:      resp.ID,
+	}, &task)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-first"}, task.Spec.DependsOn)
+}
+
+func TestCreateTask_InvalidDependsOn(t *testing.T) {
+	tooMany := make([]string, maxTaskDependencies+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("task-%d", i)
+	}
+
+	tests := []struct {
+		name      string
+		dependsOn []string
+	}{
+		{"unknown task", []string{"task-missing"}},
+		{"too many", tooMany},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			router := testRouter(h)
+
+			req := validCreateRequest()
+			req.DependsOn = tt.dependsOn
+			w := postCreateTask(t, router, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+			assert.Equal(t, "invalid dependsOn", errResp.Error)
+		})
+	}
+}
+
 func TestCreateTask_WithLabels(t *testing.T) {
 	h := newTestHandler()
 	router := testRouter(h)
@@ -431,6 +745,7 @@ func TestCreateTask_K8sClientError(t *testing.T) {
 
 	h := &taskHandler{
 		client:    c,
+		tasks:     newKubeTaskStore(c, nil, "default"),
 		namespace: "default",
 		callback:  newCallbackSender(""),
 	}
@@ -533,6 +848,92 @@ func TestTaskToResponse_NoCompletionTime(t *testing.T) {
 	assert.Nil(t, resp.CompletionTime)
 }
 
+func TestTaskDurations(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := created.Add(10 * time.Minute)
+	at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: created.Add(d)} }
+
+	tests := []struct {
+		name                   string
+		start, completion      *metav1.Time
+		queued, running, total int64
+	}{
+		{name: "queued", queued: 600, running: 0, total: 600},
+		{name: "running", start: at(90 * time.Second), queued: 90, running: 510, total: 600},
+		{name: "completed", start: at(90 * time.Second), completion: at(5 * time.Minute), queued: 90, running: 210, total: 300},
+		{name: "cancelled before start", completion: at(2 * time.Minute), queued: 120, running: 0, total: 120},
+		{name: "start before creation", start: at(-5 * time.Second), queued: 0, running: 605, total: 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTask("task-abc", nil, nil)
+			task.CreationTimestamp = metav1.NewTime(created)
+			task.Status.StartTime = tt.start
+			task.Status.CompletionTime = tt.completion
+
+			queued, running, total := taskDurations(task, now)
+			assert.Equal(t, tt.queued, queued, "queued")
+			assert.Equal(t, tt.running, running, "running")
+			assert.Equal(t, tt.total, total, "total")
+		})
+	}
+}
+
+func TestTaskDeadline(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	succeeded := []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonSucceeded,
+	}}
+
+	t.Run("not started", func(t *testing.T) {
+		deadline, remaining := taskDeadline(newTask("task-abc", nil, nil), start)
+		assert.Empty(t, deadline)
+		assert.Nil(t, remaining)
+	})
+
+	t.Run("running", func(t *testing.T) {
+		task := newTask("task-abc", nil, nil)
+		task.Spec.Runner.Timeout = metav1.Duration{Duration: 30 * time.Minute}
+		task.Status.StartTime = &metav1.Time{Time: start}
+
+		deadline, remaining := taskDeadline(task, start.Add(10*time.Minute))
+		assert.Equal(t, "2026-03-01T12:30:00Z", deadline)
+		require.NotNil(t, remaining)
+		assert.Equal(t, int64(1200), *remaining)
+	})
+
+	t.Run("past the deadline", func(t *testing.T) {
+		task := newTask("task-abc", nil, nil)
+		task.Spec.Runner.Timeout = metav1.Duration{Duration: 30 * time.Minute}
+		task.Status.StartTime = &metav1.Time{Time: start}
+
+		_, remaining := taskDeadline(task, start.Add(time.Hour))
+		require.NotNil(t, remaining)
+		assert.Equal(t, int64(0), *remaining)
+	})
+
+	t.Run("default timeout", func(t *testing.T) {
+		task := newTask("task-abc", nil, nil)
+		task.Status.StartTime = &metav1.Time{Time: start}
+
+		deadline, _ := taskDeadline(task, start)
+		assert.Equal(t, start.Add(toolkitv1alpha1.DefaultRunnerTimeout).Format(time.RFC3339), deadline)
+	})
+
+	t.Run("finished", func(t *testing.T) {
+		task := newTask("task-abc", nil, succeeded)
+		task.Spec.Runner.Timeout = metav1.Duration{Duration: 30 * time.Minute}
+		task.Status.StartTime = &metav1.Time{Time: start}
+
+		deadline, remaining := taskDeadline(task, start.Add(10*time.Minute))
+		assert.Equal(t, "2026-03-01T12:30:00Z", deadline)
+		assert.Nil(t, remaining)
+	})
+}
+
 // --- Phase 3: List and Get tests ---
 
 func newTask(name string, labels map[string]string, conditions []metav1.Condition) *toolkitv1alpha1.AgentTask {
@@ -785,7 +1186,7 @@ func TestGetTask_K8sClientError(t *testing.T) {
 		}).
 		Build()
 
-	h := &taskHandler{client: c, namespace: "default", callback: newCallbackSender("")}
+	h := &taskHandler{client: c, tasks: newKubeTaskStore(c, nil, "default"), namespace: "default", callback: newCallbackSender("")}
 	router := testRouter(h)
 
 	w := doGet(t, router, "/api/v1/tasks/task-abc")
@@ -796,6 +1197,231 @@ func TestGetTask_K8sClientError(t *testing.T) {
 	assert.Equal(t, "failed to get task", errResp.Error)
 }
 
+// newCachedTestHandler returns a handler whose informer cache holds cached
+// while the Kubernetes API holds live.
+func newCachedTestHandler(cached []client.Object, live ...client.Object) *taskHandler {
+	h := newTestHandler(live...)
+	cache := fake.NewClientBuilder().WithScheme(testScheme()).WithObjects(cached...).Build()
+	h.tasks = newKubeTaskStore(h.client, cache, "default")
+	return h
+}
+
+func TestGetTask_ReadsFromCache(t *testing.T) {
+	stale := newTask("task-cached", nil, nil)
+	fresh := newTask("task-cached", nil, []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonSucceeded,
+	}})
+	router := testRouter(newCachedTestHandler([]client.Object{stale}, fresh))
+
+	tests := []struct {
+		path  string
+		phase string
+	}{
+		{"/api/v1/tasks/task-cached", "Pending"},
+		{"/api/v1/tasks/task-cached?consistent=false", "Pending"},
+		{"/api/v1/tasks/task-cached?consistent=true", "Succeeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := doGet(t, router, tt.path)
+			require.Equal(t, http.StatusOK, w.Code)
+			validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, tt.path, nil), w)
+
+			var resp TaskResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.phase, resp.Status.Phase)
+		})
+	}
+}
+
+func TestGetTask_CacheMissFallsBackToLiveRead(t *testing.T) {
+	router := testRouter(newCachedTestHandler(nil, newTask("task-new", nil, nil)))
+
+	w := doGet(t, router, "/api/v1/tasks/task-new")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doGet(t, router, "/api/v1/tasks/nonexistent")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListTasks_ReadsFromCache(t *testing.T) {
+	router := testRouter(newCachedTestHandler(
+		[]client.Object{newTask("task-a", nil, nil)},
+		newTask("task-a", nil, nil), newTask("task-b", nil, nil)))
+
+	var cached []TaskResponse
+	w := doGet(t, router, "/api/v1/tasks")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cached))
+	assert.Len(t, cached, 1)
+
+	var live []TaskResponse
+	w = doGet(t, router, "/api/v1/tasks?consistent=true")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/tasks?consistent=true", nil), w)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &live))
+	assert.Len(t, live, 2)
+}
+
+func TestListTasks_PhaseFilter(t *testing.T) {
+	condition := func(status metav1.ConditionStatus, reason string) []metav1.Condition {
+		return []metav1.Condition{{Type: toolkitv1alpha1.ConditionSucceeded, Status: status, Reason: reason}}
+	}
+	running := newTask("task-running", nil, condition(metav1.ConditionUnknown, toolkitv1alpha1.ReasonRunning))
+	succeeded := newTask("task-succeeded", nil, condition(metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded))
+	failed := newTask("task-failed", nil, condition(metav1.ConditionFalse, toolkitv1alpha1.ReasonFailed))
+	timedOut := newTask("task-timedout", nil, condition(metav1.ConditionFalse, toolkitv1alpha1.ReasonTimedOut))
+	pending := newTask("task-pending", nil, nil)
+	queued := newTask("task-queued", nil, condition(metav1.ConditionUnknown, toolkitv1alpha1.ReasonQueued))
+	provisioning := newTask("task-provisioning", nil, condition(metav1.ConditionUnknown, toolkitv1alpha1.ReasonPending))
+	provisioning.Status.SandboxClaimName = "task-provisioning"
+	provisioning.Status.Phase = toolkitv1alpha1.PhaseProvisioning
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"single phase", "phase=Running", []string{"task-running"}},
+		{"provisioning", "phase=Provisioning", []string{"task-provisioning"}},
+		{"queued tasks are pending", "phase=Pending", []string{"task-pending", "task-queued"}},
+		{"comma-separated", "phase=Failed,TimedOut", []string{"task-failed", "task-timedout"}},
+		{"repeated param", "phase=Succeeded&phase=Running", []string{"task-running", "task-succeeded"}},
+		{"case-insensitive", "phase=running", []string{"task-running"}},
+		{"combined with active", "phase=Running,Failed&active=true", []string{"task-running"}},
+		{"empty value is ignored", "phase=", []string{
+			"task-failed", "task-pending", "task-provisioning", "task-queued", "task-running",
+			"task-succeeded", "task-timedout",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(running, succeeded, failed, timedOut, pending, queued, provisioning)
+			router := testRouter(h)
+
+			w := doGet(t, router, "/api/v1/tasks?"+tt.query)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var tasks []TaskResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tasks))
+			ids := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				ids = append(ids, task.ID)
+			}
+			assert.ElementsMatch(t, tt.want, ids)
+		})
+	}
+}
+
+func TestListTasks_Sort(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	sortTask := func(name string, created int, completed int, reason string) *toolkitv1alpha1.AgentTask {
+		status := metav1.ConditionFalse
+		switch reason {
+		case toolkitv1alpha1.ReasonRunning:
+			status = metav1.ConditionUnknown
+		case toolkitv1alpha1.ReasonSucceeded:
+			status = metav1.ConditionTrue
+		}
+		task := newTask(name, nil, []metav1.Condition{
+			{Type: toolkitv1alpha1.ConditionSucceeded, Status: status, Reason: reason},
+		})
+		task.CreationTimestamp = metav1.NewTime(base.Add(time.Duration(created) * time.Minute))
+		if completed > 0 {
+			ct := metav1.NewTime(base.Add(time.Duration(completed) * time.Minute))
+			task.Status.CompletionTime = &ct
+		}
+		return task
+	}
+	// a: created first, completed last; b: created last, still running;
+	// c: created second, completed first.
+	a := sortTask("task-a", 1, 30, toolkitv1alpha1.ReasonSucceeded)
+	b := sortTask("task-b", 3, 0, toolkitv1alpha1.ReasonRunning)
+	c := sortTask("task-c", 2, 10, toolkitv1alpha1.ReasonFailed)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"createdAt ascending by default", "sort=createdAt", []string{"task-a", "task-c", "task-b"}},
+		{"createdAt descending", "sort=createdAt&order=desc", []string{"task-b", "task-c", "task-a"}},
+		{"completionTime keeps incomplete last", "sort=completionTime", []string{"task-c", "task-a", "task-b"}},
+		{"completionTime descending keeps incomplete last", "sort=completionTime&order=desc", []string{"task-a", "task-c", "task-b"}},
+		{"phase", "sort=phase", []string{"task-c", "task-b", "task-a"}},
+		{"combined with phase filter", "phase=Failed,Succeeded&sort=createdAt&order=desc", []string{"task-c", "task-a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(a, b, c)
+			router := testRouter(h)
+
+			w := doGet(t, router, "/api/v1/tasks?"+tt.query)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var tasks []TaskResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tasks))
+			ids := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				ids = append(ids, task.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestListTasks_InvalidSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		details string
+	}{
+		{"unknown field", "sort=name", "name"},
+		{"unknown order", "sort=createdAt&order=up", "up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			router := testRouter(h)
+
+			w := doGet(t, router, "/api/v1/tasks?"+tt.query)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+			assert.Equal(t, "invalid sort", errResp.Error)
+			assert.Contains(t, errResp.Details, tt.details)
+		})
+	}
+}
+
+func TestListTasks_InvalidPhaseFilter(t *testing.T) {
+	h := newTestHandler()
+	router := testRouter(h)
+
+	w := doGet(t, router, "/api/v1/tasks?phase=Failed,Exploded")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	doc := loadSpec(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks?phase=Failed,Exploded", nil)
+	validateResponse(t, doc, req, w)
+
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "invalid phase filter", errResp.Error)
+	assert.Contains(t, errResp.Details, "Exploded")
+
+	// Condition reasons that are not phases are rejected too
+	w = doGet(t, router, "/api/v1/tasks?phase=Queued")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestListTasks_K8sClientError(t *testing.T) {
 	s := testScheme()
 	c := fake.NewClientBuilder().
@@ -807,7 +1433,7 @@ func TestListTasks_K8sClientError(t *testing.T) {
 		}).
 		Build()
 
-	h := &taskHandler{client: c, namespace: "default", callback: newCallbackSender("")}
+	h := &taskHandler{client: c, tasks: newKubeTaskStore(c, nil, "default"), namespace: "default", callback: newCallbackSender("")}
 	router := testRouter(h)
 
 	w := doGet(t, router, "/api/v1/tasks")
@@ -911,6 +1537,31 @@ func TestNormalizeRepoFilter(t *testing.T) {
 			input: "https://github.com/org/sub/repo",
 			want:  "org-sub-repo",
 		},
+		{
+			name:  "link to an issue",
+			input: "https://github.com/org/repo/issues/42",
+			want:  "org-repo",
+		},
+		{
+			name:  "GitLab subgroup link",
+			input: "https://gitlab.com/group/sub/repo/-/merge_requests/7",
+			want:  "group-sub-repo",
+		},
+		{
+			name:  "SSH remote",
+			input: "git@github.com:org/repo.git",
+			want:  "org-repo",
+		},
+		{
+			name:  "surrounding whitespace and trailing slash",
+			input: " org/repo/ ",
+			want:  "org-repo",
+		},
+		{
+			name:    "wildcard",
+			input:   "org/*",
+			wantErr: true,
+		},
 		{
 			name:    "invalid chars after normalization",
 			input:   "$$invalid$$",
@@ -940,6 +1591,76 @@ func TestNormalizeRepoFilter(t *testing.T) {
 	}
 }
 
+func TestNormalizeIssueFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantIssue string
+		wantRepo  string
+		wantErr   bool
+	}{
+		{name: "number", input: "42", wantIssue: "42"},
+		{name: "hash", input: "#42", wantIssue: "42"},
+		{name: "Linear identifier", input: "ENG-123", wantIssue: "ENG-123"},
+		{name: "GitHub issue URL", input: "https://github.com/org/repo/issues/42",
+			wantIssue: "42", wantRepo: "org-repo"},
+		{name: "GitHub pull request URL", input: "https://github.com/org/repo/pull/7#issuecomment-1",
+			wantIssue: "7", wantRepo: "org-repo"},
+		{name: "Gitea pull request URL", input: "https://gitea.example.com/org/repo/pulls/7",
+			wantIssue: "7", wantRepo: "org-repo"},
+		{name: "GitLab issue URL", input: "https://gitlab.com/group/repo/-/issues/3",
+			wantIssue: "3", wantRepo: "group-repo"},
+		{name: "Linear issue URL", input: "https://linear.app/acme/issue/ENG-123/fix-login",
+			wantIssue: "ENG-123"},
+		{name: "URL without issue", input: "https://github.com/org/repo", wantErr: true},
+		{name: "wildcard", input: "4*", wantErr: true},
+		{name: "invalid", input: "not/valid", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue, repo, err := normalizeIssueFilter(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIssue, issue)
+			assert.Equal(t, tt.wantRepo, repo)
+		})
+	}
+}
+
+func TestListTasks_IssueFilterURL(t *testing.T) {
+	h := newTestHandler(
+		newTask("task-aaa", map[string]string{"shepherd.io/repo": "org-repo", "shepherd.io/issue": "42"}, nil),
+		newTask("task-bbb", map[string]string{"shepherd.io/repo": "org-other", "shepherd.io/issue": "42"}, nil),
+	)
+	router := testRouter(h)
+
+	w := doGet(t, router, "/api/v1/tasks?issue="+url.QueryEscape("https://github.com/org/repo/issues/42"))
+	require.Equal(t, http.StatusOK, w.Code)
+	var tasks []TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tasks))
+	require.Len(t, tasks, 1, "the URL also selects the repository")
+	assert.Equal(t, "task-aaa", tasks[0].ID)
+
+	w = doGet(t, router, "/api/v1/tasks?repo=org/other&issue="+url.QueryEscape("https://github.com/org/repo/issues/42"))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "conflicting repository")
+}
+
+func TestListTasks_WildcardFilter(t *testing.T) {
+	h := newTestHandler()
+	router := testRouter(h)
+
+	w := doGet(t, router, "/api/v1/tasks?repo="+url.QueryEscape("org/*"))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "invalid repo filter", errResp.Error)
+	assert.Contains(t, errResp.Details, "/api/v1/tasks/search")
+}
+
 func TestIsTerminal(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -0,0 +1,245 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/validate"
+)
+
+const maxTemplateContextSize = 65536 // Matches the TaskTemplate CRD validation
+
+// templateLabel records which TaskTemplate a task was created from.
+const templateLabel = "shepherd.io/template"
+
+// createTaskTemplate handles POST /api/v1/task-templates.
+func (h *taskHandler) createTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20) // 1 MiB
+	var req CreateTaskTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required", "")
+		return
+	}
+	if req.Runner == nil || req.Runner.SandboxTemplateName == "" {
+		writeError(w, http.StatusBadRequest, "runner.sandboxTemplateName is required", "")
+		return
+	}
+	if err := validate.Model(req.Runner.Model); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid runner.model", err.Error())
+		return
+	}
+	if err := validate.BranchPrefix(req.Runner.BranchPrefix); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid runner.branchPrefix", err.Error())
+		return
+	}
+	runnerSpec := toolkitv1alpha1.RunnerSpec{
+		SandboxTemplateName: req.Runner.SandboxTemplateName,
+		ServiceAccountName:  req.Runner.ServiceAccountName,
+		Model:               req.Runner.Model,
+		BranchPrefix:        req.Runner.BranchPrefix,
+	}
+	if req.Runner.Timeout != "" {
+		d, err := time.ParseDuration(req.Runner.Timeout)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid runner.timeout", err.Error())
+			return
+		}
+		if err := validate.Timeout(d); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid runner.timeout", err.Error())
+			return
+		}
+		runnerSpec.Timeout = metav1.Duration{Duration: d}
+	}
+	if err := validateLabels(req.Labels); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid labels", err.Error())
+		return
+	}
+	if len(req.Context) > maxTemplateContextSize {
+		writeError(w, http.StatusRequestEntityTooLarge, "context exceeds size limit",
+			fmt.Sprintf("context size %d exceeds %d byte limit", len(req.Context), maxTemplateContextSize))
+		return
+	}
+	if req.Priority < 0 || req.Priority > maxTaskPriority {
+		writeError(w, http.StatusBadRequest, "invalid priority",
+			fmt.Sprintf("must be between 0 and %d", maxTaskPriority))
+		return
+	}
+
+	tmpl := &toolkitv1alpha1.TaskTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      req.Name,
+			Namespace: h.namespace,
+		},
+		Spec: toolkitv1alpha1.TaskTemplateSpec{
+			Description: req.Description,
+			Runner:      runnerSpec,
+			Labels:      req.Labels,
+			Context:     req.Context,
+			Priority:    req.Priority,
+		},
+	}
+	if err := h.client.Create(r.Context(), tmpl); err != nil {
+		if errors.IsAlreadyExists(err) {
+			writeError(w, http.StatusConflict, "task template already exists", err.Error())
+			return
+		}
+		if errors.IsInvalid(err) {
+			writeError(w, http.StatusBadRequest, "invalid task template", err.Error())
+			return
+		}
+		log.Error(err, "failed to create task template")
+		writeError(w, http.StatusInternalServerError, "failed to create task template", "")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, taskTemplateToResponse(tmpl))
+}
+
+// listTaskTemplates handles GET /api/v1/task-templates.
+func (h *taskHandler) listTaskTemplates(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+
+	var list toolkitv1alpha1.TaskTemplateList
+	if err := h.client.List(r.Context(), &list, client.InNamespace(h.namespace)); err != nil {
+		log.Error(err, "failed to list task templates")
+		writeError(w, http.StatusInternalServerError, "failed to list task templates", "")
+		return
+	}
+
+	templates := make([]TaskTemplateResponse, 0, len(list.Items))
+	for i := range list.Items {
+		templates = append(templates, taskTemplateToResponse(&list.Items[i]))
+	}
+	slices.SortFunc(templates, func(a, b TaskTemplateResponse) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	writeJSON(w, http.StatusOK, templates)
+}
+
+// getTaskTemplate handles GET /api/v1/task-templates/{templateName}.
+func (h *taskHandler) getTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	name := chi.URLParam(r, "templateName")
+
+	var tmpl toolkitv1alpha1.TaskTemplate
+	if err := h.client.Get(r.Context(), client.ObjectKey{Namespace: h.namespace, Name: name}, &tmpl); err != nil {
+		if errors.IsNotFound(err) {
+			writeError(w, http.StatusNotFound, "task template not found", "")
+			return
+		}
+		log.Error(err, "failed to get task template", "template", name)
+		writeError(w, http.StatusInternalServerError, "failed to get task template", "")
+		return
+	}
+	writeJSON(w, http.StatusOK, taskTemplateToResponse(&tmpl))
+}
+
+func taskTemplateToResponse(tmpl *toolkitv1alpha1.TaskTemplate) TaskTemplateResponse {
+	return TaskTemplateResponse{
+		Name:        tmpl.Name,
+		Namespace:   tmpl.Namespace,
+		Description: tmpl.Spec.Description,
+		Runner:      runnerConfig(tmpl.Spec.Runner),
+		Labels:      tmpl.Spec.Labels,
+		Context:     tmpl.Spec.Context,
+		Priority:    tmpl.Spec.Priority,
+		CreatedAt:   tmpl.CreationTimestamp.UTC().Format(time.RFC3339),
+	}
+}
+
+// applyTaskTemplate fills in the parts of req the template provides. Values
+// set in the request win; the template's context is prepended to the
+// request's.
+func applyTaskTemplate(req *CreateTaskRequest, tmpl *toolkitv1alpha1.TaskTemplate) {
+	runner := runnerConfig(tmpl.Spec.Runner)
+	if req.Runner != nil {
+		runner.SandboxTemplateName = cmp.Or(req.Runner.SandboxTemplateName, runner.SandboxTemplateName)
+		runner.Timeout = cmp.Or(req.Runner.Timeout, runner.Timeout)
+		runner.ServiceAccountName = cmp.Or(req.Runner.ServiceAccountName, runner.ServiceAccountName)
+		runner.Model = cmp.Or(req.Runner.Model, runner.Model)
+		runner.BranchPrefix = cmp.Or(req.Runner.BranchPrefix, runner.BranchPrefix)
+	}
+	req.Runner = &runner
+
+	labels := maps.Clone(tmpl.Spec.Labels)
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	maps.Copy(labels, req.Labels)
+	labels[templateLabel] = tmpl.Name
+	req.Labels = labels
+
+	if req.Priority == 0 {
+		req.Priority = tmpl.Spec.Priority
+	}
+
+	switch {
+	case tmpl.Spec.Context == "":
+	case req.Task.Context == "":
+		req.Task.Context = tmpl.Spec.Context
+	default:
+		req.Task.Context = strings.TrimRight(tmpl.Spec.Context, "\n") + "\n\n" + req.Task.Context
+	}
+}
+
+func runnerConfig(spec toolkitv1alpha1.RunnerSpec) RunnerConfig {
+	cfg := RunnerConfig{
+		SandboxTemplateName: spec.SandboxTemplateName,
+		ServiceAccountName:  spec.ServiceAccountName,
+		Model:               spec.Model,
+		BranchPrefix:        spec.BranchPrefix,
+	}
+	if spec.Timeout.Duration != 0 {
+		cfg.Timeout = spec.Timeout.Duration.String()
+	}
+	return cfg
+}
+
+// validateLabels checks that keys and values are valid Kubernetes labels.
+func validateLabels(labels map[string]string) error {
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("key %q: %s", k, strings.Join(errs, "; "))
+		}
+		if err := validateLabelValue(labels[k]); err != nil {
+			return fmt.Errorf("value of %q: %w", k, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func goServiceTemplate() *toolkitv1alpha1.TaskTemplate {
+	return &toolkitv1alpha1.TaskTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "go-service", Namespace: "default"},
+		Spec: toolkitv1alpha1.TaskTemplateSpec{
+			Runner: toolkitv1alpha1.RunnerSpec{
+				SandboxTemplateName: "go-sandbox",
+				Timeout:             metav1.Duration{Duration: time.Hour},
+				ServiceAccountName:  "runner",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				},
+			},
+			Labels:   map[string]string{"team": "platform", "tier": "backend"},
+			Context:  "Run make lint test before opening a pull request.\n",
+			Priority: 200,
+		},
+	}
+}
+
+func TestCreateTaskTemplate(t *testing.T) {
+	h := newTestHandler()
+	router := testRouter(h)
+
+	w := postJSON(t, router, "/api/v1/task-templates", CreateTaskTemplateRequest{
+		Name:        "go-service",
+		Description: "Go services",
+		Runner:      &RunnerConfig{SandboxTemplateName: "go-sandbox", Timeout: "1h"},
+		Labels:      map[string]string{"team": "platform"},
+		Context:     "Follow the repo conventions.",
+		Priority:    100,
+	})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/task-templates", nil)
+	req.Header.Set("Content-Type", "application/json")
+	validateResponse(t, loadSpec(t), req, w)
+
+	var resp TaskTemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "go-service", resp.Name)
+	assert.Equal(t, "1h0m0s", resp.Runner.Timeout)
+
+	var stored toolkitv1alpha1.TaskTemplate
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "go-service"}, &stored))
+	assert.Equal(t, "go-sandbox", stored.Spec.Runner.SandboxTemplateName)
+	assert.Equal(t, time.Hour, stored.Spec.Runner.Timeout.Duration)
+	assert.Equal(t, "Follow the repo conventions.", stored.Spec.Context)
+	assert.Equal(t, int32(100), stored.Spec.Priority)
+}
+
+func TestCreateTaskTemplate_Invalid(t *testing.T) {
+	valid := func() CreateTaskTemplateRequest {
+		return CreateTaskTemplateRequest{Name: "tmpl", Runner: &RunnerConfig{SandboxTemplateName: "default"}}
+	}
+	tests := []struct {
+		name   string
+		mutate func(*CreateTaskTemplateRequest)
+		code   int
+		errMsg string
+	}{
+		{"missing name", func(r *CreateTaskTemplateRequest) { r.Name = "" }, http.StatusBadRequest, "name is required"},
+		{"missing runner", func(r *CreateTaskTemplateRequest) { r.Runner = nil }, http.StatusBadRequest, "runner.sandboxTemplateName is required"},
+		{"bad timeout", func(r *CreateTaskTemplateRequest) { r.Runner.Timeout = "soon" }, http.StatusBadRequest, "invalid runner.timeout"},
+		{"bad label key", func(r *CreateTaskTemplateRequest) { r.Labels = map[string]string{"not a key": "x"} }, http.StatusBadRequest, "invalid labels"},
+		{"bad label value", func(r *CreateTaskTemplateRequest) { r.Labels = map[string]string{"team": "a b"} }, http.StatusBadRequest, "invalid labels"},
+		{"priority out of range", func(r *CreateTaskTemplateRequest) { r.Priority = 1001 }, http.StatusBadRequest, "invalid priority"},
+		{"context too large", func(r *CreateTaskTemplateRequest) { r.Context = strings.Repeat("x", maxTemplateContextSize+1) }, http.StatusRequestEntityTooLarge, "context exceeds size limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := testRouter(newTestHandler())
+			body := valid()
+			tt.mutate(&body)
+
+			w := postJSON(t, router, "/api/v1/task-templates", body)
+			assert.Equal(t, tt.code, w.Code)
+
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+			assert.Equal(t, tt.errMsg, errResp.Error)
+		})
+	}
+}
+
+func TestCreateTaskTemplate_AlreadyExists(t *testing.T) {
+	router := testRouter(newTestHandler(goServiceTemplate()))
+
+	w := postJSON(t, router, "/api/v1/task-templates", CreateTaskTemplateRequest{
+		Name:   "go-service",
+		Runner: &RunnerConfig{SandboxTemplateName: "default"},
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestListTaskTemplates(t *testing.T) {
+	other := goServiceTemplate()
+	other.Name = "a-python-service"
+	router := testRouter(newTestHandler(goServiceTemplate(), other))
+
+	w := doGet(t, router, "/api/v1/task-templates")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/task-templates", nil), w)
+
+	var resp []TaskTemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 2)
+	assert.Equal(t, "a-python-service", resp[0].Name)
+	assert.Equal(t, "go-service", resp[1].Name)
+}
+
+func TestGetTaskTemplate(t *testing.T) {
+	router := testRouter(newTestHandler(goServiceTemplate()))
+
+	w := doGet(t, router, "/api/v1/task-templates/go-service")
+	require.Equal(t, http.StatusOK, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/task-templates/go-service", nil), w)
+
+	var resp TaskTemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, RunnerConfig{SandboxTemplateName: "go-sandbox", Timeout: "1h0m0s", ServiceAccountName: "runner"}, resp.Runner)
+	assert.Equal(t, int32(200), resp.Priority)
+
+	w = doGet(t, router, "/api/v1/task-templates/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	validateResponse(t, loadSpec(t), httptest.NewRequest(http.MethodGet, "/api/v1/task-templates/missing", nil), w)
+}
+
+func TestCreateTask_TemplateRef(t *testing.T) {
+	h := newTestHandler(goServiceTemplate())
+	router := testRouter(h)
+
+	body := validCreateRequest()
+	body.TemplateRef = "go-service"
+	body.Runner = nil
+	body.Labels = map[string]string{"tier": "frontend"}
+
+	w := postCreateTask(t, router, body)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+
+	assert.Equal(t, "go-sandbox", task.Spec.Runner.SandboxTemplateName)
+	assert.Equal(t, time.Hour, task.Spec.Runner.Timeout.Duration)
+	assert.Equal(t, "runner", task.Spec.Runner.ServiceAccountName)
+	assert.Equal(t, "4Gi", task.Spec.Runner.Resources.Limits.Memory().String())
+	assert.Equal(t, int32(200), task.Spec.Priority)
+	assert.Equal(t, "platform", task.Labels["team"])
+	assert.Equal(t, "frontend", task.Labels["tier"], "request labels win")
+	assert.Equal(t, "go-service", task.Labels[templateLabel])
+
+	ctx, err := decompressContext(task.Spec.Task.Context, task.Spec.Task.ContextEncoding)
+	require.NoError(t, err)
+	assert.Equal(t, "Run make lint test before opening a pull request.\n\nIssue #42: login page throws NPE on empty password", ctx)
+}
+
+func TestCreateTask_TemplateRefOverrides(t *testing.T) {
+	h := newTestHandler(goServiceTemplate())
+	router := testRouter(h)
+
+	body := validCreateRequest()
+	body.TemplateRef = "go-service"
+	body.Runner = &RunnerConfig{Timeout: "10m"}
+	body.Priority = 5
+
+	w := postCreateTask(t, router, body)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+
+	assert.Equal(t, "go-sandbox", task.Spec.Runner.SandboxTemplateName)
+	assert.Equal(t, 10*time.Minute, task.Spec.Runner.Timeout.Duration)
+	assert.Equal(t, int32(5), task.Spec.Priority)
+}
+
+func TestCreateTask_TemplateRefNotFound(t *testing.T) {
+	router := testRouter(newTestHandler())
+
+	body := validCreateRequest()
+	body.TemplateRef = "missing"
+
+	w := postCreateTask(t, router, body)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "invalid templateRef", errResp.Error)
+}
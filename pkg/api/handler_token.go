@@ -22,17 +22,18 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"k8s.io/apimachinery/pkg/api/errors"
-	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
 )
 
 // getTaskToken handles GET /api/v1/tasks/{taskID}/token.
-// Generates a short-lived GitHub installation token scoped to the task's repo.
+// Generates a short-lived GitHub installation token scoped to the task's repo,
+// or returns the configured token for repos on a Gitea or Forgejo instance.
 // Uses TokenIssued flag to prevent replay attacks - each task can only fetch a token once.
 func (h *taskHandler) getTaskToken(w http.ResponseWriter, r *http.Request) {
-	log := ctrl.Log.WithName("api")
+	log := logf.FromContext(r.Context())
 	taskID := chi.URLParam(r, "taskID")
 
 	const maxRetries = 3
@@ -40,17 +41,18 @@ func (h *taskHandler) getTaskToken(w http.ResponseWriter, r *http.Request) {
 		if r.Context().Err() != nil {
 			return
 		}
-		var task toolkitv1alpha1.AgentTask
-		key := client.ObjectKey{Namespace: h.namespace, Name: taskID}
-		if err := h.client.Get(r.Context(), key, &task); err != nil {
+		task, err := h.tasks.Get(r.Context(), taskID, ReadConsistent)
+		if err != nil {
 			if errors.IsNotFound(err) {
 				writeError(w, http.StatusNotFound, "task not found", "")
 				return
 			}
-			log.Error(err, "failed to get task", "taskID", taskID)
+			log.Error(err, "failed to get task", logging.TaskID, taskID)
 			writeError(w, http.StatusInternalServerError, "failed to get task", "")
 			return
 		}
+		log := logging.ForTask(log.WithValues(logging.TaskID, taskID), task.Annotations)
+		log.V(1).Info("token requested", "attempt", attempt, "tokenIssued", task.Status.TokenIssued)
 
 		if task.IsTerminal() {
 			writeError(w, http.StatusGone, "task is terminal", "")
@@ -59,6 +61,7 @@ func (h *taskHandler) getTaskToken(w http.ResponseWriter, r *http.Request) {
 
 		// One-time fetch: block replay within same execution
 		if task.Status.TokenIssued {
+			tokensIssued.WithLabelValues(resultAlreadyIssued).Inc()
 			writeError(w, http.StatusConflict, "token already issued for this execution", "")
 			return
 		}
@@ -73,13 +76,17 @@ func (h *taskHandler) getTaskToken(w http.ResponseWriter, r *http.Request) {
 		// - Security: Prevents token replay if crash occurs after generation but before flag update
 		// - Availability: Transient GitHub API failures permanently block the task
 		// This is a conscious security-first design decision
-		task.Status.TokenIssued = true
-		if err := h.client.Status().Update(r.Context(), &task); err != nil {
+		// The optimistic lock ensures only one concurrent request sets it.
+		err = h.tasks.UpdateStatus(r.Context(), task, func(task *toolkitv1alpha1.AgentTask) error {
+			task.Status.TokenIssued = true
+			return nil
+		})
+		if err != nil {
 			if errors.IsConflict(err) {
-				log.V(1).Info("conflict updating TokenIssued, retrying", "taskID", taskID, "attempt", attempt+1)
+				log.V(1).Info("conflict updating TokenIssued, retrying", "attempt", attempt+1)
 				continue // Retry with fresh task
 			}
-			log.Error(err, "failed to update TokenIssued", "taskID", taskID)
+			log.Error(err, "failed to update TokenIssued")
 			writeError(w, http.StatusInternalServerError, "failed to update task status", "")
 			return
 		}
@@ -87,11 +94,13 @@ func (h *taskHandler) getTaskToken(w http.ResponseWriter, r *http.Request) {
 		// Generate and return token
 		token, expiresAt, err := h.githubClient.GetToken(r.Context(), task.Spec.Repo.URL)
 		if err != nil {
-			log.Error(err, "failed to get GitHub token", "taskID", taskID)
+			log.Error(err, "failed to get GitHub token")
+			tokensIssued.WithLabelValues(resultError).Inc()
 			writeError(w, http.StatusBadGateway, "failed to generate GitHub token", "")
 			return
 		}
 
+		tokensIssued.WithLabelValues(resultIssued).Inc()
 		writeJSON(w, http.StatusOK, TokenResponse{
 			Token:     token,
 			ExpiresAt: expiresAt.Format(time.RFC3339),
@@ -100,6 +109,6 @@ func (h *taskHandler) getTaskToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Exhausted retries
-	log.Error(nil, "exhausted retries updating TokenIssued", "taskID", taskID)
+	log.Error(nil, "exhausted retries updating TokenIssued", logging.TaskID, taskID)
 	writeError(w, http.StatusConflict, "concurrent update conflict", "")
 }
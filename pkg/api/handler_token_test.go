@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 // mockTokenProvider implements TokenProvider for tests.
@@ -67,6 +68,7 @@ func newTokenTestHandler(t *testing.T, objs ...metav1.Object) (*taskHandler, *mo
 
 	return &taskHandler{
 		client:       c,
+		tasks:        newKubeTaskStore(c, nil, "default"),
 		namespace:    "default",
 		callback:     newCallbackSender(""),
 		githubClient: mock,
@@ -90,9 +92,11 @@ func TestGetTaskToken_ReturnsToken(t *testing.T) {
 	r := chi.NewRouter()
 	r.Get("/api/v1/tasks/{taskID}/token", h.getTaskToken)
 
+	issued := testutil.ToFloat64(tokensIssued.WithLabelValues(resultIssued))
 	w := doGet(t, r, "/api/v1/tasks/task-token-1/token")
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, issued+1, testutil.ToFloat64(tokensIssued.WithLabelValues(resultIssued)))
 
 	// Contract validation
 	doc := loadSpec(t)
@@ -195,6 +199,7 @@ func TestGetTaskToken_GitHubAPIError(t *testing.T) {
 
 	h := &taskHandler{
 		client:    c,
+		tasks:     newKubeTaskStore(c, nil, "default"),
 		namespace: "default",
 		callback:  newCallbackSender(""),
 		githubClient: &mockTokenProvider{
@@ -259,9 +264,11 @@ func TestGetTaskToken_RejectsSecondFetch(t *testing.T) {
 	r := chi.NewRouter()
 	r.Get("/api/v1/tasks/{taskID}/token", h.getTaskToken)
 
+	replays := testutil.ToFloat64(tokensIssued.WithLabelValues(resultAlreadyIssued))
 	w := doGet(t, r, "/api/v1/tasks/task-issued-2/token")
 
 	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, replays+1, testutil.ToFloat64(tokensIssued.WithLabelValues(resultAlreadyIssued)))
 	var errResp ErrorResponse
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
 	assert.Equal(t, "token already issued for this execution", errResp.Error)
@@ -315,7 +322,7 @@ func TestGetTaskToken_RetriesOnConflict(t *testing.T) {
 		WithStatusSubresource(&toolkitv1alpha1.AgentTask{}).
 		WithObjects(task).
 		WithInterceptorFuncs(interceptor.Funcs{
-			SubResourceUpdate: func(ctx context.Context, cli client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			SubResourcePatch: func(ctx context.Context, cli client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
 				updateAttempts++
 				// Fail first attempt with conflict error, succeed on second
 				if updateAttempts == 1 {
@@ -326,7 +333,7 @@ func TestGetTaskToken_RetriesOnConflict(t *testing.T) {
 					)
 				}
 				// Succeed on second attempt by calling the real update
-				return cli.Status().Update(ctx, obj, opts...)
+				return cli.Status().Patch(ctx, obj, patch, opts...)
 			},
 		}).
 		Build()
@@ -338,6 +345,7 @@ func TestGetTaskToken_RetriesOnConflict(t *testing.T) {
 
 	h := &taskHandler{
 		client:       c,
+		tasks:        newKubeTaskStore(c, nil, "default"),
 		namespace:    "default",
 		callback:     newCallbackSender(""),
 		githubClient: mock,
@@ -379,7 +387,7 @@ func TestGetTaskToken_ExhaustsRetriesOnPersistentConflict(t *testing.T) {
 		WithStatusSubresource(&toolkitv1alpha1.AgentTask{}).
 		WithObjects(task).
 		WithInterceptorFuncs(interceptor.Funcs{
-			SubResourceUpdate: func(_ context.Context, _ client.Client, _ string, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+			SubResourcePatch: func(_ context.Context, _ client.Client, _ string, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
 				updateAttempts++
 				// Always return conflict error to exhaust retries
 				return errors.NewConflict(
@@ -398,6 +406,7 @@ func TestGetTaskToken_ExhaustsRetriesOnPersistentConflict(t *testing.T) {
 
 	h := &taskHandler{
 		client:       c,
+		tasks:        newKubeTaskStore(c, nil, "default"),
 		namespace:    "default",
 		callback:     newCallbackSender(""),
 		githubClient: mock,
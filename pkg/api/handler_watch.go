@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/coder/websocket"
+	"k8s.io/apimachinery/pkg/labels"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// watchTasks handles GET /api/v1/tasks/watch (WebSocket upgrade, public
+// port 8080). It sends the tasks matching the repo, issue and fleet filters
+// as task_added messages, then tasks_synced, then every change to a
+// matching task until the client disconnects. A client that falls behind
+// is disconnected and gets a fresh list when it reconnects.
+func (h *taskHandler) watchTasks(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+
+	labelSelector, msg, err := taskLabelFilters(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, msg, err.Error())
+		return
+	}
+	selector := labels.SelectorFromSet(labelSelector)
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		log.Error(err, "failed to accept websocket")
+		return
+	}
+	defer conn.CloseNow() //nolint:errcheck
+
+	// CloseRead for write-only mode; the returned context ends when the
+	// client goes away.
+	ctx := conn.CloseRead(r.Context())
+
+	// Subscribe before listing so no change between the two is missed; a
+	// task may then be sent twice, which clients apply idempotently.
+	changes, unsubscribe := h.feed.subscribe()
+	defer unsubscribe()
+
+	tasks, err := h.tasks.List(ctx, selector, ReadCached)
+	if err != nil {
+		log.Error(err, "failed to list tasks for watch")
+		_ = conn.Close(websocket.StatusInternalError, "failed to list tasks")
+		return
+	}
+	for i := range tasks {
+		resp := taskToResponse(&tasks[i])
+		if err := writeWSMessage(ctx, conn, WSMessage{Type: WatchTaskAdded, Data: resp}); err != nil {
+			return
+		}
+	}
+	if err := writeWSMessage(ctx, conn, WSMessage{Type: WatchTasksSynced}); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change, ok := <-changes:
+			if !ok {
+				_ = conn.Close(websocket.StatusPolicyViolation, "slow consumer evicted")
+				return
+			}
+			if change.Task.Namespace != h.namespace || !selector.Matches(labels.Set(change.Task.Labels)) {
+				continue
+			}
+			resp := taskToResponse(change.Task)
+			if err := writeWSMessage(ctx, conn, WSMessage{Type: change.Type, Data: resp}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeWSMessage sends msg as a JSON text message.
+func writeWSMessage(ctx context.Context, conn *websocket.Conn, msg WSMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return conn.Write(ctx, websocket.MessageText, data)
+}
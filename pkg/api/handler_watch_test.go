@@ -0,0 +1,134 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// watchMessage is a WSMessage of a task list watch.
+type watchMessage struct {
+	Type string        `json:"type"`
+	Data *TaskResponse `json:"data"`
+}
+
+func readWatchMessage(ctx context.Context, t *testing.T, conn *websocket.Conn) watchMessage {
+	t.Helper()
+	_, data, err := conn.Read(ctx)
+	require.NoError(t, err)
+	var msg watchMessage
+	require.NoError(t, json.Unmarshal(data, &msg))
+	return msg
+}
+
+func TestWatchTasks(t *testing.T) {
+	repoLabels := map[string]string{"shepherd.io/repo": "org-repo"}
+	h := newTestHandler(newTask("task-a", repoLabels, nil), newTask("task-other", nil, nil))
+	srv := httptest.NewServer(testRouter(h))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, srv.URL+"/api/v1/tasks/watch?repo=org/repo", nil)
+	require.NoError(t, err)
+	defer conn.CloseNow() //nolint:errcheck
+
+	// The matching tasks come first.
+	msg := readWatchMessage(ctx, t, conn)
+	assert.Equal(t, WatchTaskAdded, msg.Type)
+	require.NotNil(t, msg.Data)
+	assert.Equal(t, "task-a", msg.Data.ID)
+	msg = readWatchMessage(ctx, t, conn)
+	assert.Equal(t, WatchTasksSynced, msg.Type)
+	assert.Nil(t, msg.Data)
+
+	// Changes to other repos are filtered out.
+	updated := newTask("task-a", repoLabels, nil)
+	updated.Spec.Task.Description = "updated"
+	h.feed.publish(WatchTaskUpdated, newTask("task-other", nil, nil))
+	h.feed.publish(WatchTaskUpdated, updated)
+	h.feed.publish(WatchTaskDeleted, updated)
+
+	msg = readWatchMessage(ctx, t, conn)
+	assert.Equal(t, WatchTaskUpdated, msg.Type)
+	assert.Equal(t, "task-a", msg.Data.ID)
+	assert.Equal(t, "updated", msg.Data.Task.Description)
+	msg = readWatchMessage(ctx, t, conn)
+	assert.Equal(t, WatchTaskDeleted, msg.Type)
+	assert.Equal(t, "task-a", msg.Data.ID)
+}
+
+func TestWatchTasks_SlowConsumerEvicted(t *testing.T) {
+	h := newTestHandler()
+	srv := httptest.NewServer(testRouter(h))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, srv.URL+"/api/v1/tasks/watch", nil)
+	require.NoError(t, err)
+	defer conn.CloseNow() //nolint:errcheck
+	assert.Equal(t, WatchTasksSynced, readWatchMessage(ctx, t, conn).Type)
+
+	// Overflow the feed faster than the handler can write.
+	task := newTask("task-a", nil, nil)
+	for range taskFeedBuffer * 4 {
+		h.feed.publish(WatchTaskUpdated, task)
+	}
+
+	for {
+		_, _, err := conn.Read(ctx)
+		if err != nil {
+			assert.Equal(t, websocket.StatusPolicyViolation, websocket.CloseStatus(err))
+			return
+		}
+	}
+}
+
+func TestWatchTasks_InvalidFilter(t *testing.T) {
+	h := newTestHandler()
+
+	w := doGet(t, testRouter(h), "/api/v1/tasks/watch?fleet=not%20valid")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTaskFeed_Unsubscribe(t *testing.T) {
+	feed := newTaskFeed()
+	ch, unsubscribe := feed.subscribe()
+	unsubscribe()
+	unsubscribe() // idempotent
+
+	feed.publish(WatchTaskAdded, &toolkitv1alpha1.AgentTask{})
+	_, ok := <-ch
+	assert.False(t, ok)
+
+	var nilFeed *taskFeed
+	nilFeed.publish(WatchTaskAdded, &toolkitv1alpha1.AgentTask{}) // no-op
+}
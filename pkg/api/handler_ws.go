@@ -24,29 +24,33 @@ import (
 	"github.com/coder/websocket"
 	"github.com/go-chi/chi/v5"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
-	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
 )
 
+// StreamEventsSynced is the type of the message GET
+// /api/v1/tasks/{taskID}/events sends after the buffered events, so clients
+// can tell replayed events from live ones.
+const StreamEventsSynced = "events_synced"
+
 // streamEvents handles GET /api/v1/tasks/{taskID}/events (WebSocket upgrade, public port 8080).
 func (h *taskHandler) streamEvents(w http.ResponseWriter, r *http.Request) {
-	log := ctrl.Log.WithName("api")
+	log := logf.FromContext(r.Context())
 	taskID := chi.URLParam(r, "taskID")
 
 	// Validate task exists
-	var task toolkitv1alpha1.AgentTask
-	key := client.ObjectKey{Namespace: h.namespace, Name: taskID}
-	if err := h.client.Get(r.Context(), key, &task); err != nil {
+	task, err := h.tasks.Get(r.Context(), taskID, ReadConsistent)
+	if err != nil {
 		if apierrors.IsNotFound(err) {
 			writeError(w, http.StatusNotFound, "task not found", "")
 			return
 		}
-		log.Error(err, "failed to get task", "taskID", taskID)
+		log.Error(err, "failed to get task", logging.TaskID, taskID)
 		writeError(w, http.StatusInternalServerError, "failed to get task", "")
 		return
 	}
+	log = logging.ForTask(log.WithValues(logging.TaskID, taskID), task.Annotations)
 
 	// Parse ?after parameter
 	var after int64
@@ -62,10 +66,11 @@ func (h *taskHandler) streamEvents(w http.ResponseWriter, r *http.Request) {
 	// Accept WebSocket upgrade
 	conn, err := websocket.Accept(w, r, nil)
 	if err != nil {
-		log.Error(err, "failed to accept websocket", "taskID", taskID)
+		log.Error(err, "failed to accept websocket")
 		return
 	}
 	defer conn.CloseNow() //nolint:errcheck
+	log.V(1).Info("streaming events", "after", after)
 
 	ctx := r.Context()
 
@@ -83,9 +88,15 @@ func (h *taskHandler) streamEvents(w http.ResponseWriter, r *http.Request) {
 		msg := WSMessage{Type: "task_event", Data: e}
 		data, err := json.Marshal(msg)
 		if err != nil {
-			log.Error(err, "failed to marshal event", "taskID", taskID)
+			log.Error(err, "failed to marshal event")
+			return
+		}
+		if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
 			return
 		}
+	}
+
+	if data, err := json.Marshal(WSMessage{Type: StreamEventsSynced}); err == nil {
 		if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
 			return
 		}
@@ -95,7 +106,7 @@ func (h *taskHandler) streamEvents(w http.ResponseWriter, r *http.Request) {
 	if ch == nil {
 		completeData := TaskCompleteData{
 			TaskID: taskID,
-			Status: extractStatus(&task).Phase,
+			Status: extractStatus(task).Phase,
 			PRURL:  task.Status.Result.PRURL,
 			Error:  task.Status.Result.Error,
 		}
@@ -111,7 +122,7 @@ func (h *taskHandler) streamEvents(w http.ResponseWriter, r *http.Request) {
 		msg := WSMessage{Type: "task_event", Data: e}
 		data, err := json.Marshal(msg)
 		if err != nil {
-			log.Error(err, "failed to marshal event", "taskID", taskID)
+			log.Error(err, "failed to marshal event")
 			return
 		}
 		if err := conn.Write(ctx, websocket.MessageText, data); err != nil {
@@ -130,16 +141,16 @@ func (h *taskHandler) streamEvents(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Re-fetch task to get terminal status.
-	var freshTask toolkitv1alpha1.AgentTask
-	if err := h.client.Get(ctx, key, &freshTask); err != nil {
-		log.Error(err, "failed to get task for completion", "taskID", taskID)
+	freshTask, err := h.tasks.Get(ctx, taskID, ReadConsistent)
+	if err != nil {
+		log.Error(err, "failed to get task for completion")
 		_ = conn.Close(websocket.StatusInternalError, "failed to get task status")
 		return
 	}
 
 	completeData := TaskCompleteData{
 		TaskID: taskID,
-		Status: extractStatus(&freshTask).Phase,
+		Status: extractStatus(freshTask).Phase,
 		PRURL:  freshTask.Status.Result.PRURL,
 		Error:  freshTask.Status.Result.Error,
 	}
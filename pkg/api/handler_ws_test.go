@@ -73,6 +73,12 @@ func TestStreamEvents_WebSocketUpgradeAndReplay(t *testing.T) {
 	require.NoError(t, json.Unmarshal(data, &msg2))
 	assert.Equal(t, "task_event", msg2.Type)
 
+	var synced WSMessage
+	_, data, err = conn.Read(ctx)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &synced))
+	assert.Equal(t, StreamEventsSynced, synced.Type, "replayed events are followed by events_synced")
+
 	// Publish a live event
 	h.eventHub.Publish("task-ws", []TaskEvent{
 		{Sequence: 3, Timestamp: "2026-01-01T00:00:02Z", Type: EventTypeToolResult, Summary: "File contents"},
@@ -162,6 +168,12 @@ func TestStreamEvents_CompletedTaskSendsCompleteMessage(t *testing.T) {
 	require.NoError(t, json.Unmarshal(data, &msg1))
 	assert.Equal(t, "task_event", msg1.Type)
 
+	var synced WSMessage
+	_, data, err = conn.Read(ctx)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &synced))
+	assert.Equal(t, StreamEventsSynced, synced.Type)
+
 	// Should receive task_complete message
 	var msg2 WSMessage
 	_, data, err = conn.Read(ctx)
@@ -199,6 +211,13 @@ func TestStreamEvents_LiveCompleteSendsCompleteMessage(t *testing.T) {
 	require.NoError(t, err)
 	defer conn.CloseNow() //nolint:errcheck
 
+	// Nothing to replay
+	var synced WSMessage
+	_, data, err := conn.Read(ctx)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &synced))
+	assert.Equal(t, StreamEventsSynced, synced.Type)
+
 	// Publish an event
 	h.eventHub.Publish("task-live-complete", []TaskEvent{
 		{Sequence: 1, Timestamp: "2026-01-01T00:00:00Z", Type: EventTypeThinking, Summary: "Working"},
@@ -206,7 +225,7 @@ func TestStreamEvents_LiveCompleteSendsCompleteMessage(t *testing.T) {
 
 	// Read the event
 	var msg WSMessage
-	_, data, err := conn.Read(ctx)
+	_, data, err = conn.Read(ctx)
 	require.NoError(t, err)
 	require.NoError(t, json.Unmarshal(data, &msg))
 	assert.Equal(t, "task_event", msg.Type)
@@ -0,0 +1,53 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/url"
+	"strings"
+)
+
+// LogsURLTaskID is the placeholder in LinkOptions.LogsURL that is replaced
+// with the task ID.
+const LogsURLTaskID = "{taskID}"
+
+// LinkOptions configures the links to a task's pages that are added to
+// callbacks, so adapters can point users at them.
+type LinkOptions struct {
+	// DashboardURL is the base URL of the web frontend. A task's page is
+	// DashboardURL/tasks/<id>.
+	DashboardURL string
+	// LogsURL is the URL of a task's logs in an external log viewer, with
+	// LogsURLTaskID where the task ID goes.
+	LogsURL string
+}
+
+// forTask returns the links to the pages of taskID, or nil if none are
+// configured.
+func (o LinkOptions) forTask(taskID string) *TaskLinks {
+	if o.DashboardURL == "" && o.LogsURL == "" {
+		return nil
+	}
+	links := &TaskLinks{}
+	if o.DashboardURL != "" {
+		links.Dashboard = strings.TrimRight(o.DashboardURL, "/") + "/tasks/" + url.PathEscape(taskID)
+	}
+	if o.LogsURL != "" {
+		links.Logs = strings.ReplaceAll(o.LogsURL, LogsURLTaskID, url.QueryEscape(taskID))
+	}
+	return links
+}
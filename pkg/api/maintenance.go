@@ -0,0 +1,172 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// MaintenanceConfigMap is the ConfigMap in the API server's namespace whose
+// presence puts Shepherd into maintenance: new tasks are rejected until it
+// is deleted. Keeping the state in the cluster applies it to every API
+// server replica at once.
+const MaintenanceConfigMap = "shepherd-maintenance"
+
+// MaintenanceHeader is set on requests rejected because of maintenance, so
+// adapters can tell them from other failures.
+const MaintenanceHeader = "X-Shepherd-Maintenance"
+
+// Keys of MaintenanceConfigMap.
+const (
+	maintenanceMessageKey = "message"
+	maintenanceUntilKey   = "until"
+)
+
+// defaultMaintenanceMessage is shown when maintenance was started without
+// a message.
+const defaultMaintenanceMessage = "Shepherd is under maintenance."
+
+// maxMaintenanceMessageLength bounds the message, which adapters post as
+// part of their comments.
+const maxMaintenanceMessageLength = 2000
+
+// maintenance returns the current maintenance state.
+func (h *taskHandler) maintenance(ctx context.Context) (MaintenanceResponse, error) {
+	var cm corev1.ConfigMap
+	err := h.client.Get(ctx, client.ObjectKey{Namespace: h.namespace, Name: MaintenanceConfigMap}, &cm)
+	if apierrors.IsNotFound(err) {
+		return MaintenanceResponse{}, nil
+	}
+	if err != nil {
+		return MaintenanceResponse{}, err
+	}
+	resp := MaintenanceResponse{
+		Enabled: true,
+		Message: cm.Data[maintenanceMessageKey],
+		Until:   cm.Data[maintenanceUntilKey],
+	}
+	if resp.Message == "" {
+		resp.Message = defaultMaintenanceMessage
+	}
+	return resp, nil
+}
+
+// rejectDuringMaintenance writes a 503 response and returns true if Shepherd
+// is under maintenance. If the state cannot be read, requests are let
+// through rather than failing them all.
+func (h *taskHandler) rejectDuringMaintenance(w http.ResponseWriter, r *http.Request) bool {
+	m, err := h.maintenance(r.Context())
+	if err != nil {
+		logf.FromContext(r.Context()).Error(err, "failed to read maintenance state, accepting request")
+		return false
+	}
+	if !m.Enabled {
+		return false
+	}
+	w.Header().Set(MaintenanceHeader, "true")
+	if until, err := time.Parse(time.RFC3339, m.Until); err == nil && until.After(time.Now()) {
+		w.Header().Set("Retry-After", until.UTC().Format(http.TimeFormat))
+	}
+	writeError(w, http.StatusServiceUnavailable, "shepherd is under maintenance", m.Message)
+	return true
+}
+
+// getMaintenance handles GET /api/v1/maintenance, so adapters and the UI
+// can announce maintenance before anyone runs into it.
+func (h *taskHandler) getMaintenance(w http.ResponseWriter, r *http.Request) {
+	m, err := h.maintenance(r.Context())
+	if err != nil {
+		logf.FromContext(r.Context()).Error(err, "failed to read maintenance state")
+		writeError(w, http.StatusInternalServerError, "failed to read maintenance state", "")
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+// setMaintenance handles PUT /api/v1/admin/maintenance.
+func (h *taskHandler) setMaintenance(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+
+	r.Body = http.MaxBytesReader(w, r.Body, 64<<10) // 64 KiB
+	var req SetMaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if n := utf8.RuneCountInString(req.Message); n > maxMaintenanceMessageLength {
+		writeError(w, http.StatusBadRequest, "message is too long",
+			fmt.Sprintf("%d characters exceeds the limit of %d", n, maxMaintenanceMessageLength))
+		return
+	}
+	if req.Until != "" {
+		until, err := time.Parse(time.RFC3339, req.Until)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid until", "must be an RFC 3339 time such as 2026-03-01T18:00:00Z")
+			return
+		}
+		req.Until = until.UTC().Format(time.RFC3339)
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: h.namespace, Name: MaintenanceConfigMap}}
+	if _, err := controllerutil.CreateOrUpdate(r.Context(), h.client, cm, func() error {
+		cm.Data = map[string]string{maintenanceMessageKey: req.Message}
+		if req.Until != "" {
+			cm.Data[maintenanceUntilKey] = req.Until
+		}
+		return nil
+	}); err != nil {
+		log.Error(err, "failed to start maintenance")
+		writeError(w, http.StatusInternalServerError, "failed to start maintenance", "")
+		return
+	}
+	log.Info("maintenance started", "message", req.Message, "until", req.Until)
+
+	m, err := h.maintenance(r.Context())
+	if err != nil {
+		log.Error(err, "failed to read maintenance state")
+		writeError(w, http.StatusInternalServerError, "failed to read maintenance state", "")
+		return
+	}
+	writeJSON(w, http.StatusOK, m)
+}
+
+// endMaintenance handles DELETE /api/v1/admin/maintenance.
+func (h *taskHandler) endMaintenance(w http.ResponseWriter, r *http.Request) {
+	log := logf.FromContext(r.Context())
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: h.namespace, Name: MaintenanceConfigMap}}
+	if err := h.client.Delete(r.Context(), cm); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "failed to end maintenance")
+		writeError(w, http.StatusInternalServerError, "failed to end maintenance", "")
+		return
+	}
+	log.Info("maintenance ended")
+	writeJSON(w, http.StatusOK, MaintenanceResponse{})
+}
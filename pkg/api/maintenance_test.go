@@ -0,0 +1,144 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// newMaintenanceTestHandler returns a test handler whose scheme also knows
+// ConfigMaps, so the maintenance state can be read.
+func newMaintenanceTestHandler(objs ...client.Object) *taskHandler {
+	s := testScheme()
+	_ = corev1.AddToScheme(s)
+	h := newTestHandler()
+	h.client = fake.NewClientBuilder().WithScheme(s).
+		WithStatusSubresource(&toolkitv1alpha1.AgentTask{}).
+		WithObjects(objs...).
+		Build()
+	return h
+}
+
+func doMaintenance(t *testing.T, router http.Handler, method string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+	req := httptest.NewRequest(method, "/api/v1/admin/maintenance", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	validateResponse(t, loadSpec(t), req, w)
+	return w
+}
+
+func TestMaintenance_Lifecycle(t *testing.T) {
+	h := newMaintenanceTestHandler()
+	router := testRouter(h)
+	doc := loadSpec(t)
+
+	w := doGet(t, router, "/api/v1/maintenance")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	validateResponse(t, doc, httptest.NewRequest(http.MethodGet, "/api/v1/maintenance", nil), w)
+	var m MaintenanceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	assert.False(t, m.Enabled)
+
+	until := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	w = doMaintenance(t, router, http.MethodPut, SetMaintenanceRequest{
+		Message: "  Upgrading the cluster  ",
+		Until:   until.Format(time.RFC3339),
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	assert.True(t, m.Enabled)
+	assert.Equal(t, "Upgrading the cluster", m.Message)
+	assert.Equal(t, until.Format(time.RFC3339), m.Until)
+
+	// New tasks are rejected with a response adapters can recognise.
+	w = postJSON(t, router, "/api/v1/tasks", validCreateRequest())
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "true", w.Header().Get(MaintenanceHeader))
+	assert.Equal(t, until.Format(http.TimeFormat), w.Header().Get("Retry-After"))
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "shepherd is under maintenance", errResp.Error)
+	assert.Equal(t, "Upgrading the cluster", errResp.Details)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks", nil)
+	req.Header.Set("Content-Type", "application/json")
+	validateResponse(t, doc, req, w)
+
+	var tasks toolkitv1alpha1.AgentTaskList
+	require.NoError(t, h.client.List(t.Context(), &tasks))
+	assert.Empty(t, tasks.Items)
+
+	w = doMaintenance(t, router, http.MethodDelete, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	// Ending maintenance twice is not an error.
+	w = doMaintenance(t, router, http.MethodDelete, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = postJSON(t, router, "/api/v1/tasks", validCreateRequest())
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+}
+
+func TestMaintenance_ConfigMapCreatedByHand(t *testing.T) {
+	h := newMaintenanceTestHandler(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: MaintenanceConfigMap},
+	})
+	router := testRouter(h)
+
+	w := doGet(t, router, "/api/v1/maintenance")
+	require.Equal(t, http.StatusOK, w.Code)
+	var m MaintenanceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	assert.True(t, m.Enabled)
+	assert.Equal(t, defaultMaintenanceMessage, m.Message)
+
+	w = postJSON(t, router, "/api/v1/tasks", validCreateRequest())
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
+
+func TestSetMaintenance_Validation(t *testing.T) {
+	router := testRouter(newMaintenanceTestHandler())
+
+	w := doMaintenance(t, router, http.MethodPut, SetMaintenanceRequest{Until: "tomorrow"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doMaintenance(t, router, http.MethodPut, SetMaintenanceRequest{
+		Message: string(bytes.Repeat([]byte("x"), maxMaintenanceMessageLength+1)),
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/NissesSenap/shepherd/pkg/forge"
+)
+
+// metricsRegistry holds the API server's metrics, served on the metrics
+// listener.
+var metricsRegistry = prometheus.NewRegistry()
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "shepherd_api_request_duration_seconds",
+		Help:    "Duration of HTTP requests by server, route, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"server", "route", "method", "code"})
+
+	callbacksSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "shepherd_api_callbacks_total",
+		Help: "Callbacks sent to adapters by event and result (success or failure).",
+	}, []string{"event", "result"})
+
+	eventsIngested = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "shepherd_api_events_ingested_total",
+		Help: "Runner events accepted on the events endpoint.",
+	})
+
+	tokensIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "shepherd_api_tokens_issued_total",
+		Help: "GitHub token requests from runners by result: issued, already_issued (rejected replay) or error.",
+	}, []string{"result"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requestDuration,
+		callbacksSent,
+		eventsIngested,
+		tokensIssued,
+	)
+	metricsRegistry.MustRegister(forge.RateLimitCollectors()...)
+}
+
+// Results recorded by shepherd_api_callbacks_total and
+// shepherd_api_tokens_issued_total.
+const (
+	resultSuccess       = "success"
+	resultFailure       = "failure"
+	resultIssued        = "issued"
+	resultAlreadyIssued = "already_issued"
+	resultError         = "error"
+)
+
+// metricsHandler serves the API server's metrics.
+func metricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+}
+
+// metricsMiddleware records the duration of each request of server. Requests
+// are labelled with their route pattern rather than their path, so task IDs
+// do not create a series each; unmatched requests share the route "other".
+func metricsMiddleware(server string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			route := "other"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			requestDuration.WithLabelValues(server, route, r.Method, strconv.Itoa(status)).
+				Observe(time.Since(start).Seconds())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(metricsMiddleware("test"))
+	r.Get("/api/v1/tasks/{taskID}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	doGet(t, r, "/api/v1/tasks/task-a")
+	doGet(t, r, "/api/v1/tasks/task-b")
+	doGet(t, r, "/nope")
+
+	w := doGet(t, metricsHandler(), "/metrics")
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body,
+		`shepherd_api_request_duration_seconds_count{code="404",method="GET",route="/api/v1/tasks/{taskID}",server="test"} 2`,
+		"task IDs share the route's series")
+	assert.Contains(t, body,
+		`shepherd_api_request_duration_seconds_count{code="404",method="GET",route="other",server="test"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestCallbackSender_Metrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	success := testutil.ToFloat64(callbacksSent.WithLabelValues(EventCompleted, resultSuccess))
+	failure := testutil.ToFloat64(callbacksSent.WithLabelValues(EventCompleted, resultFailure))
+
+	sender := newCallbackSender("")
+	require.NoError(t, sender.send(context.Background(), srv.URL, CallbackPayload{TaskID: "task-abc", Event: EventCompleted}))
+	require.Error(t, sender.send(context.Background(), "http://127.0.0.1:1", CallbackPayload{TaskID: "task-abc", Event: EventCompleted}))
+
+	assert.Equal(t, success+1, testutil.ToFloat64(callbacksSent.WithLabelValues(EventCompleted, resultSuccess)))
+	assert.Equal(t, failure+1, testutil.ToFloat64(callbacksSent.WithLabelValues(EventCompleted, resultFailure)))
+}
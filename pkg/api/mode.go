@@ -0,0 +1,42 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+// ModeLabel is the task label that selects what a task produces. Tasks
+// without it change code like ModeFix tasks.
+const ModeLabel = "shepherd.io/mode"
+
+// Task modes (the ModeLabel value).
+const (
+	// ModeFix changes code and opens a PR, or pushes to the branch of a
+	// task's existing PR.
+	ModeFix = "fix"
+	// ModePlan writes an implementation plan without changing code.
+	ModePlan = "plan"
+	// ModeReview reviews the code, or a task's existing PR, and reports
+	// its findings without changing code.
+	ModeReview = "review"
+)
+
+// ValidMode reports whether mode is one of the task modes.
+func ValidMode(mode string) bool {
+	switch mode {
+	case ModeFix, ModePlan, ModeReview:
+		return true
+	}
+	return false
+}
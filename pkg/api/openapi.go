@@ -0,0 +1,43 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+
+	shepherdapi "github.com/NissesSenap/shepherd/api"
+)
+
+// openAPIJSON converts the embedded OpenAPI spec to JSON on first use.
+var openAPIJSON = sync.OnceValues(func() ([]byte, error) {
+	return yaml.YAMLToJSON(shepherdapi.OpenAPISpec)
+})
+
+// getOpenAPISpec handles GET /api/v1/openapi.json.
+func getOpenAPISpec(w http.ResponseWriter, _ *http.Request) {
+	spec, err := openAPIJSON()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to convert OpenAPI spec", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(spec)
+}
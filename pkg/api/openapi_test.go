@@ -0,0 +1,45 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOpenAPISpec(t *testing.T) {
+	router := testRouter(newTestHandler())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	served, err := openapi3.NewLoader().LoadFromData(w.Body.Bytes())
+	require.NoError(t, err, "served spec must parse as JSON OpenAPI")
+	spec := loadSpec(t)
+	assert.Equal(t, spec.Info.Version, served.Info.Version)
+	assert.Equal(t, len(spec.Paths.Map()), len(served.Paths.Map()))
+	assert.NotNil(t, served.Paths.Find("/api/v1/openapi.json"))
+	validateResponse(t, spec, req, w)
+}
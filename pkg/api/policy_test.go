@@ -0,0 +1,109 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/policy"
+)
+
+// failingPolicy is a policy.Evaluator that cannot reach its policy engine.
+type failingPolicy struct{}
+
+func (failingPolicy) Evaluate(context.Context, policy.Input) (policy.Decision, error) {
+	return policy.Decision{}, errors.New("connection refused")
+}
+
+func celPolicy(t *testing.T, policies ...policy.CELPolicy) policy.Evaluator {
+	t.Helper()
+	c, err := policy.NewCEL(policies)
+	require.NoError(t, err)
+	return c
+}
+
+func TestCreateTask_PolicyDenies(t *testing.T) {
+	h := newTestHandler()
+	h.policy = celPolicy(t, policy.CELPolicy{
+		Name:     "acme-only",
+		Validate: `task.repo.startsWith("https://github.com/acme/")`,
+		Message:  "Shepherd only works on acme repositories",
+	})
+
+	w := postCreateTask(t, testRouter(h), validCreateRequest())
+	require.Equal(t, http.StatusForbidden, w.Code)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks", nil)
+	req.Header.Set("Content-Type", "application/json")
+	validateResponse(t, loadSpec(t), req, w)
+
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "denied by policy", resp.Error)
+	assert.Equal(t, "Shepherd only works on acme repositories", resp.Details)
+
+	var tasks toolkitv1alpha1.AgentTaskList
+	require.NoError(t, h.client.List(context.Background(), &tasks))
+	assert.Empty(t, tasks.Items, "denied task is not created")
+}
+
+func TestCreateTask_PolicyMutates(t *testing.T) {
+	h := newTestHandler()
+	h.policy = celPolicy(t, policy.CELPolicy{
+		Name:            "test-org",
+		Match:           `task.repo.startsWith("https://github.com/test-org/")`,
+		Labels:          map[string]string{"team": `"platform"`},
+		Priority:        "500",
+		SandboxTemplate: `"large"`,
+	})
+
+	w := postCreateTask(t, testRouter(h), validCreateRequest())
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+	assert.Equal(t, "platform", task.Labels["team"])
+	assert.Equal(t, int32(500), task.Spec.Priority)
+	assert.Equal(t, "large", task.Spec.Runner.SandboxTemplateName)
+}
+
+func TestCreateTask_PolicyError(t *testing.T) {
+	t.Run("fail closed", func(t *testing.T) {
+		h := newTestHandler()
+		h.policy = failingPolicy{}
+		w := postCreateTask(t, testRouter(h), validCreateRequest())
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+	t.Run("fail open", func(t *testing.T) {
+		h := newTestHandler()
+		h.policy = failingPolicy{}
+		h.policyFailOpen = true
+		w := postCreateTask(t, testRouter(h), validCreateRequest())
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// TaskQuota caps the number of active (non-terminal) tasks. Zero means no
+// limit. Quotas are checked against the API server's view of existing
+// tasks, so requests racing each other may briefly exceed them.
+type TaskQuota struct {
+	// PerRepo limits active tasks for one repository.
+	PerRepo int
+	// PerOrg limits active tasks for all repositories of one owner.
+	PerOrg int
+	// PerNamespace limits active tasks in the API server's namespace.
+	PerNamespace int
+}
+
+func (q TaskQuota) enabled() bool {
+	return q.PerRepo > 0 || q.PerOrg > 0 || q.PerNamespace > 0
+}
+
+// repoScope splits a repository URL into "host/owner/repo" and "host/owner",
+// lowercased so differently cased URLs count against the same quota.
+func repoScope(repoURL string) (repo, org string) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", ""
+	}
+	path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	host := strings.ToLower(u.Host)
+	repo = strings.ToLower(host + "/" + path)
+	owner, _, _ := strings.Cut(path, "/")
+	return repo, strings.ToLower(host + "/" + owner)
+}
+
+// checkQuota returns a message describing the first quota a new task for
+// repoURL would exceed, or "" if it fits.
+func (h *taskHandler) checkQuota(ctx context.Context, repoURL string) (string, error) {
+	if !h.quota.enabled() {
+		return "", nil
+	}
+
+	tasks, err := h.tasks.List(ctx, nil, ReadCached)
+	if err != nil {
+		return "", fmt.Errorf("listing tasks: %w", err)
+	}
+
+	repo, org := repoScope(repoURL)
+	var inRepo, inOrg, inNamespace int
+	for i := range tasks {
+		task := &tasks[i]
+		if task.IsTerminal() {
+			continue
+		}
+		inNamespace++
+		taskRepo, taskOrg := repoScope(task.Spec.Repo.URL)
+		if taskRepo == repo {
+			inRepo++
+		}
+		if taskOrg == org {
+			inOrg++
+		}
+	}
+
+	switch {
+	case h.quota.PerRepo > 0 && inRepo >= h.quota.PerRepo:
+		return fmt.Sprintf("repository %s has %d active tasks (limit %d)", repo, inRepo, h.quota.PerRepo), nil
+	case h.quota.PerOrg > 0 && inOrg >= h.quota.PerOrg:
+		return fmt.Sprintf("organization %s has %d active tasks (limit %d)", org, inOrg, h.quota.PerOrg), nil
+	case h.quota.PerNamespace > 0 && inNamespace >= h.quota.PerNamespace:
+		return fmt.Sprintf("namespace %s has %d active tasks (limit %d)", h.namespace, inNamespace, h.quota.PerNamespace), nil
+	}
+	return "", nil
+}
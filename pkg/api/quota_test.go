@@ -0,0 +1,111 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// repoTask returns a task for repoURL that is active unless terminal is set.
+func repoTask(name, repoURL string, terminal bool) *toolkitv1alpha1.AgentTask {
+	cond := metav1.Condition{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionUnknown,
+		Reason: toolkitv1alpha1.ReasonRunning,
+	}
+	if terminal {
+		cond.Status, cond.Reason = metav1.ConditionTrue, toolkitv1alpha1.ReasonSucceeded
+	}
+	task := newTask(name, nil, []metav1.Condition{cond})
+	task.Spec.Repo.URL = repoURL
+	return task
+}
+
+func TestRepoScope(t *testing.T) {
+	tests := []struct {
+		url, repo, org string
+	}{
+		{"https://github.com/Org/Repo", "github.com/org/repo", "github.com/org"},
+		{"https://github.com/org/repo.git", "github.com/org/repo", "github.com/org"},
+		{"https://github.com/org/repo/", "github.com/org/repo", "github.com/org"},
+		{"https://gitlab.example.com/group/sub/repo", "gitlab.example.com/group/sub/repo", "gitlab.example.com/group"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			repo, org := repoScope(tt.url)
+			assert.Equal(t, tt.repo, repo)
+			assert.Equal(t, tt.org, org)
+		})
+	}
+}
+
+func TestCreateTask_Quota(t *testing.T) {
+	existing := []client.Object{
+		repoTask("task-a", "https://github.com/test-org/test-repo", false),
+		repoTask("task-b", "https://github.com/Test-Org/test-repo.git", false),
+		repoTask("task-c", "https://github.com/test-org/other-repo", false),
+		repoTask("task-d", "https://github.com/test-org/test-repo", true),
+		repoTask("task-e", "https://github.com/another-org/repo", false),
+	}
+
+	tests := []struct {
+		name    string
+		quota   TaskQuota
+		code    int
+		details string
+	}{
+		{"no quota", TaskQuota{}, http.StatusCreated, ""},
+		{"under every quota", TaskQuota{PerRepo: 3, PerOrg: 4, PerNamespace: 5}, http.StatusCreated, ""},
+		{"repo quota reached", TaskQuota{PerRepo: 2}, http.StatusTooManyRequests,
+			"repository github.com/test-org/test-repo has 2 active tasks (limit 2)"},
+		{"org quota reached", TaskQuota{PerRepo: 3, PerOrg: 3}, http.StatusTooManyRequests,
+			"organization github.com/test-org has 3 active tasks (limit 3)"},
+		{"namespace quota reached", TaskQuota{PerNamespace: 4}, http.StatusTooManyRequests,
+			"namespace default has 4 active tasks (limit 4)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(existing...)
+			h.quota = tt.quota
+
+			w := postCreateTask(t, testRouter(h), validCreateRequest())
+			require.Equal(t, tt.code, w.Code, w.Body.String())
+			if tt.code != http.StatusTooManyRequests {
+				return
+			}
+
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+			assert.Equal(t, "task quota exceeded", errResp.Error)
+			assert.Equal(t, tt.details, errResp.Details)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks", nil)
+			req.Header.Set("Content-Type", "application/json")
+			validateResponse(t, loadSpec(t), req, w)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/readiness"
+)
+
+// maxProbedCallbackHosts bounds how many adapters the callback check dials.
+const maxProbedCallbackHosts = 20
+
+// goroutineCheck fails once the background goroutine whose health is
+// tracked by healthy has stopped.
+func goroutineCheck(name string, healthy *atomic.Bool) readiness.Check {
+	return readiness.Check{
+		Name:     name,
+		Critical: true,
+		Run: func(context.Context) error {
+			if !healthy.Load() {
+				return errors.New("stopped")
+			}
+			return nil
+		},
+	}
+}
+
+// kubernetesCheck verifies that the Kubernetes API server answers.
+func kubernetesCheck(dc discovery.ServerVersionInterface) readiness.Check {
+	return readiness.Check{
+		Name:     "kubernetes",
+		Critical: true,
+		Interval: 10 * time.Second,
+		Run: func(context.Context) error {
+			_, err := dc.ServerVersion()
+			return err
+		},
+	}
+}
+
+// crdCheck verifies that the AgentTask CRD is installed, by listing tasks
+// from the API server rather than the informer cache.
+func crdCheck(c client.Reader, namespace string) readiness.Check {
+	return readiness.Check{
+		Name:     "crds",
+		Critical: true,
+		Interval: 30 * time.Second,
+		Run: func(ctx context.Context) error {
+			var tasks toolkitv1alpha1.AgentTaskList
+			err := c.List(ctx, &tasks, client.InNamespace(namespace), client.Limit(1))
+			if apimeta.IsNoMatchError(err) {
+				return errors.New("the AgentTask CRD is not installed")
+			}
+			return err
+		},
+	}
+}
+
+// callbackCheck dials the adapters that active tasks will call back, so a
+// broken adapter Service shows up before the tasks finish. It is only
+// reported: an unreachable adapter is no reason to stop serving the API.
+func callbackCheck(cache client.Reader, namespace string) readiness.Check {
+	return readiness.Check{
+		Name:     "callbacks",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			hosts, err := activeCallbackHosts(ctx, cache, namespace)
+			if err != nil {
+				return err
+			}
+			var d net.Dialer
+			var unreachable []string
+			for _, host := range hosts {
+				conn, err := d.DialContext(ctx, "tcp", host)
+				if err != nil {
+					unreachable = append(unreachable, host)
+					continue
+				}
+				_ = conn.Close()
+			}
+			if len(unreachable) > 0 {
+				return fmt.Errorf("cannot connect to %s", strings.Join(unreachable, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// activeCallbackHosts returns the host:port of the callback URLs of tasks
+// that have not finished, sorted and without duplicates.
+func activeCallbackHosts(ctx context.Context, cache client.Reader, namespace string) ([]string, error) {
+	var tasks toolkitv1alpha1.AgentTaskList
+	if err := cache.List(ctx, &tasks, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("listing tasks: %w", err)
+	}
+	var hosts []string
+	for i := range tasks.Items {
+		task := &tasks.Items[i]
+		if task.IsTerminal() {
+			continue
+		}
+		u, err := url.Parse(task.Spec.Callback.URL)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		port := u.Port()
+		if port == "" {
+			port = "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+		}
+		hosts = append(hosts, net.JoinHostPort(u.Hostname(), port))
+	}
+	slices.Sort(hosts)
+	hosts = slices.Compact(hosts)
+	if len(hosts) > maxProbedCallbackHosts {
+		hosts = hosts[:maxProbedCallbackHosts]
+	}
+	return hosts, nil
+}
+
+// githubCheck verifies the Runner App credentials. A GitHub outage only
+// affects the token endpoint, so it is reported without failing readiness.
+func githubCheck(c *GitHubClient) readiness.Check {
+	return readiness.Check{
+		Name:     "github",
+		Interval: 5 * time.Minute,
+		Run:      c.CheckCredentials,
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestActiveCallbackHosts(t *testing.T) {
+	finished := watcherTask("task-done", "http://old-adapter:8080/callback", []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonSucceeded,
+	}}, toolkitv1alpha1.TaskResult{})
+	h := newTestHandler(
+		watcherTask("task-a", "http://shepherd-github:8082/callback", nil, toolkitv1alpha1.TaskResult{}),
+		watcherTask("task-b", "http://shepherd-github:8082/callback", nil, toolkitv1alpha1.TaskResult{}),
+		watcherTask("task-c", "https://hooks.example.com/shepherd", nil, toolkitv1alpha1.TaskResult{}),
+		finished,
+	)
+
+	hosts, err := activeCallbackHosts(context.Background(), h.client, "default")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hooks.example.com:443", "shepherd-github:8082"}, hosts)
+}
+
+func TestCallbackCheck(t *testing.T) {
+	adapter := httptest.NewServer(http.NotFoundHandler())
+	defer adapter.Close()
+	// A listener closed right away leaves a port nothing accepts on.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	gone := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	h := newTestHandler(watcherTask("task-a", adapter.URL, nil, toolkitv1alpha1.TaskResult{}))
+	check := callbackCheck(h.client, "default")
+	assert.False(t, check.Critical)
+	require.NoError(t, check.Run(context.Background()))
+
+	h = newTestHandler(
+		watcherTask("task-a", adapter.URL, nil, toolkitv1alpha1.TaskResult{}),
+		watcherTask("task-b", "http://"+gone+"/callback", nil, toolkitv1alpha1.TaskResult{}),
+	)
+	err = callbackCheck(h.client, "default").Run(context.Background())
+	assert.EqualError(t, err, "cannot connect to "+gone)
+}
+
+func TestCRDCheck(t *testing.T) {
+	check := crdCheck(newTestHandler().client, "default")
+	assert.True(t, check.Critical)
+	require.NoError(t, check.Run(context.Background()))
+
+	missing := fake.NewClientBuilder().WithScheme(testScheme()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+				return &apimeta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "toolkit.shepherd.io", Kind: "AgentTask"}}
+			},
+		}).Build()
+	err := crdCheck(missing, "default").Run(context.Background())
+	assert.EqualError(t, err, "the AgentTask CRD is not installed")
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// RepoSizer reports the size of a repository.
+// Implemented by GitHubClient; test code can substitute a mock.
+type RepoSizer interface {
+	RepoSize(ctx context.Context, repoURL string) (int64, error)
+}
+
+// RepoSizeLimits rejects tasks for repositories too large to clone into the
+// sandbox they would get, instead of letting them time out mid-clone.
+type RepoSizeLimits struct {
+	// Budgets maps sandbox template names to the largest repository, in
+	// bytes, a sandbox of that template can clone. Tasks using a template
+	// without a budget are not checked.
+	Budgets map[string]int64
+	// Upgrade moves a task whose repository exceeds its template's budget
+	// to the template with the smallest budget that fits, instead of
+	// rejecting it.
+	Upgrade bool
+}
+
+// checkRepoSize returns a message describing why the repository of task is
+// too large for its sandbox template, or "" if it fits. With Upgrade set,
+// a task that fits a larger allowed template is moved to it instead.
+func (h *taskHandler) checkRepoSize(ctx context.Context, task *toolkitv1alpha1.AgentTask) (string, error) {
+	template := task.Spec.Runner.SandboxTemplateName
+	budget, ok := h.repoSize.Budgets[template]
+	if !ok || h.repoSizer == nil {
+		return "", nil
+	}
+	size, err := h.repoSizer.RepoSize(ctx, task.Spec.Repo.URL)
+	if err != nil {
+		return "", err
+	}
+	if size <= budget {
+		return "", nil
+	}
+
+	if h.repoSize.Upgrade {
+		if larger := h.smallestTemplateFor(size); larger != "" {
+			task.Spec.Runner.SandboxTemplateName = larger
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("repository %s is %s, larger than the %s budget of sandbox template %q",
+		task.Spec.Repo.URL, formatBytes(size), formatBytes(budget), template), nil
+}
+
+// smallestTemplateFor returns the allowed template with the smallest budget
+// of at least size bytes, or "" if there is none.
+func (h *taskHandler) smallestTemplateFor(size int64) string {
+	var best string
+	for _, name := range slices.Sorted(maps.Keys(h.repoSize.Budgets)) {
+		budget := h.repoSize.Budgets[name]
+		if budget < size || h.validation.SandboxTemplate(name) != nil {
+			continue
+		}
+		if best == "" || cmp.Less(budget, h.repoSize.Budgets[best]) {
+			best = name
+		}
+	}
+	return best
+}
+
+// formatBytes formats n in binary units, such as "1.5 GiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/validate"
+)
+
+const gib = 1 << 30
+
+// fakeRepoSizer reports the same size for every repository.
+type fakeRepoSizer struct {
+	size int64
+	err  error
+}
+
+func (f fakeRepoSizer) RepoSize(context.Context, string) (int64, error) {
+	return f.size, f.err
+}
+
+func repoSizeHandler(size int64, err error) *taskHandler {
+	h := newTestHandler()
+	h.repoSizer = fakeRepoSizer{size: size, err: err}
+	h.repoSize = RepoSizeLimits{Budgets: map[string]int64{
+		"default-template": 2 * gib,
+		"large":            20 * gib,
+		"huge":             100 * gib,
+	}}
+	return h
+}
+
+func TestCreateTask_RepoTooLarge(t *testing.T) {
+	h := repoSizeHandler(5*gib, nil)
+
+	w := postCreateTask(t, testRouter(h), validCreateRequest())
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks", nil)
+	req.Header.Set("Content-Type", "application/json")
+	validateResponse(t, loadSpec(t), req, w)
+
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "repository too large for sandbox", resp.Error)
+	assert.Equal(t, `repository https://github.com/test-org/test-repo is 5.0 GiB, larger than the 2.0 GiB budget of sandbox template "default-template"`, resp.Details)
+}
+
+func TestCreateTask_RepoSizeFits(t *testing.T) {
+	h := repoSizeHandler(gib, nil)
+	w := postCreateTask(t, testRouter(h), validCreateRequest())
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Templates without a budget are not checked.
+	h = repoSizeHandler(50*gib, nil)
+	body := validCreateRequest()
+	body.Runner.SandboxTemplateName = "unbudgeted"
+	w = postCreateTask(t, testRouter(h), body)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestCreateTask_RepoSizeCheckFailsOpen(t *testing.T) {
+	h := repoSizeHandler(0, errors.New("GitHub unavailable"))
+	w := postCreateTask(t, testRouter(h), validCreateRequest())
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestCreateTask_RepoSizeUpgrade(t *testing.T) {
+	h := repoSizeHandler(5*gib, nil)
+	h.repoSize.Upgrade = true
+
+	w := postCreateTask(t, testRouter(h), validCreateRequest())
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	var task toolkitv1alpha1.AgentTask
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+	assert.Equal(t, "large", task.Spec.Runner.SandboxTemplateName, "smallest template that fits")
+
+	// Templates outside the allowlist are never picked.
+	h.validation = validate.Options{AllowedSandboxTemplates: []string{"default-template", "huge"}}
+	w = postCreateTask(t, testRouter(h), validCreateRequest())
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NoError(t, h.client.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: resp.ID}, &task))
+	assert.Equal(t, "huge", task.Spec.Runner.SandboxTemplateName)
+
+	// Nothing fits: rejected as without Upgrade.
+	h = repoSizeHandler(500*gib, nil)
+	h.repoSize.Upgrade = true
+	w = postCreateTask(t, testRouter(h), validCreateRequest())
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "2.0 GiB", formatBytes(2*gib))
+}
@@ -18,8 +18,11 @@ package api
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"strings"
@@ -29,14 +32,25 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/api/shepherdv1"
+	"github.com/NissesSenap/shepherd/pkg/archive"
+	"github.com/NissesSenap/shepherd/pkg/debug"
+	"github.com/NissesSenap/shepherd/pkg/listen"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/policy"
+	"github.com/NissesSenap/shepherd/pkg/readiness"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+	"github.com/NissesSenap/shepherd/pkg/validate"
 )
 
 var scheme = runtime.NewScheme()
@@ -50,11 +64,67 @@ func init() {
 type Options struct {
 	ListenAddr           string
 	InternalListenAddr   string // Runner-only API port
+	MetricsListenAddr    string // Prometheus metrics; empty disables them
+	DebugListenAddr      string // pprof and expvar; empty disables them
 	CallbackSecret       string
 	Namespace            string
 	GithubAppID          int64
 	GithubInstallationID int64
 	GithubPrivateKeyPath string
+	GithubURL            string // GitHub Enterprise Server URL; empty means github.com
+	Quota                TaskQuota
+	// Archive, when set, receives a record of every finished task.
+	Archive archive.Archiver
+	// Policy, when set, decides whether a new task may be created and may
+	// change its labels, priority and sandbox template first.
+	Policy policy.Evaluator
+	// PolicyFailOpen creates tasks when Policy fails to evaluate, instead
+	// of rejecting them.
+	PolicyFailOpen bool
+	// Validation configures the checks run on new tasks.
+	Validation validate.Options
+	// RepoSize rejects tasks for repositories larger than their sandbox
+	// template can hold. It needs the GitHub App to look up sizes.
+	RepoSize RepoSizeLimits
+	// EventPrivacy limits the detail of the events runners send for
+	// tasks, by repository.
+	EventPrivacy EventPrivacyOptions
+	// TaskIndexSize is the number of sources, such as issue URLs, whose
+	// latest task is remembered to answer active task lookups; zero
+	// disables the index.
+	TaskIndexSize int
+	// MaxTimeoutExtension bounds the total time the timeout of a task may
+	// be extended by through the API; zero disables extensions.
+	MaxTimeoutExtension time.Duration
+	// Links are the URLs of the dashboard and log viewer, linked to from
+	// callbacks.
+	Links LinkOptions
+	// CallbackSecondarySecret also signs callbacks while the callback
+	// secret is rotated.
+	CallbackSecondarySecret string
+	// CallbackSigningKey, when set, also signs callbacks with Ed25519. Its
+	// public key is served at /api/v1/callback-signing-key.
+	CallbackSigningKey ed25519.PrivateKey
+	// CallbackFormat is the format of callbacks for tasks that do not
+	// choose one: json (the default) or cloudevents.
+	CallbackFormat string
+	// CallbackTargets are the hosts and addresses callbacks may reach.
+	CallbackTargets validate.CallbackTargetOptions
+	// GRPC also serves the gRPC API (shepherd.v1.TaskService and
+	// RunnerService) on the public and internal ports.
+	GRPC bool
+	// AdminAPI serves the bulk cancel and delete endpoints under
+	// /api/v1/admin on the public listener.
+	AdminAPI bool
+	// BasePath is a path prefix the public API is served under, such as
+	// "/shepherd", for running behind a shared gateway. Empty serves it at
+	// the root.
+	BasePath string
+	// GiteaURL and GiteaTokenPath issue tokens for repositories on a Gitea
+	// or Forgejo instance: the token in the file at GiteaTokenPath is
+	// handed to runners of tasks whose repository is on GiteaURL's host.
+	GiteaURL       string
+	GiteaTokenPath string
 }
 
 // contentTypeMiddleware validates Content-Type header on mutating requests.
@@ -74,6 +144,22 @@ func contentTypeMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// normalizeBasePath returns p with a leading slash and no trailing slash,
+// or "" for the root.
+func normalizeBasePath(p string) (string, error) {
+	p = strings.TrimRight(p, "/")
+	if p == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	if strings.ContainsAny(p, "?#{}*") || strings.Contains(p, "//") {
+		return "", fmt.Errorf("invalid base path %q", p)
+	}
+	return p, nil
+}
+
 // Run starts the API server.
 func Run(opts Options) error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -81,6 +167,15 @@ func Run(opts Options) error {
 
 	log := ctrl.Log.WithName("api")
 
+	basePath, err := normalizeBasePath(opts.BasePath)
+	if err != nil {
+		return err
+	}
+	callbackTargets, err := validate.NewCallbackTargets(opts.CallbackTargets)
+	if err != nil {
+		return err
+	}
+
 	// Build K8s client
 	cfg, err := ctrl.GetConfig()
 	if err != nil {
@@ -92,26 +187,73 @@ func Run(opts Options) error {
 	}
 
 	cb := newCallbackSender(opts.CallbackSecret)
+	cb.httpClient.Transport = tracing.Transport(callbackTransport(callbackTargets))
+	cb.secondarySecret = opts.CallbackSecondarySecret
+	cb.signingKey = opts.CallbackSigningKey
+	cb.links = opts.Links
+	cb.format = opts.CallbackFormat
+	cb.source = cloudEventSource(opts.Namespace)
 
 	// Create GitHub client if configured
 	var githubClient *GitHubClient
 	if opts.GithubPrivateKeyPath != "" {
 		var err error
-		githubClient, err = NewGitHubClient(opts.GithubAppID, opts.GithubInstallationID, opts.GithubPrivateKeyPath)
+		githubClient, err = NewGitHubClient(opts.GithubAppID, opts.GithubInstallationID, opts.GithubPrivateKeyPath, opts.GithubURL)
 		if err != nil {
 			return fmt.Errorf("creating github client: %w", err)
 		}
 		log.Info("GitHub App configured", "appID", opts.GithubAppID)
 	}
+	var tokens TokenProvider
+	if githubClient != nil {
+		tokens = githubClient
+	}
+	if opts.GiteaTokenPath != "" {
+		giteaTokens, err := NewStaticTokenProvider(opts.GiteaTokenPath)
+		if err != nil {
+			return fmt.Errorf("gitea token: %w", err)
+		}
+		giteaURL, err := url.Parse(opts.GiteaURL)
+		if err != nil {
+			return fmt.Errorf("invalid gitea URL: %w", err)
+		}
+		tokens = &hostTokenProvider{host: giteaURL.Host, provider: giteaTokens, fallback: tokens}
+		log.Info("Gitea token configured", "host", giteaURL.Host)
+	}
 
 	eventHub := NewEventHub()
+	feed := newTaskFeed()
+
+	var archiver *taskArchiver
+	if opts.Archive != nil {
+		archiver = &taskArchiver{store: opts.Archive, eventHub: eventHub, now: time.Now}
+		log.Info("task archive configured")
+	}
+	if opts.AdminAPI {
+		log.Info("admin endpoints enabled")
+	}
 
 	handler := &taskHandler{
-		client:       k8sClient,
-		namespace:    opts.Namespace,
-		callback:     cb,
-		githubClient: githubClient,
-		eventHub:     eventHub,
+		client:         k8sClient,
+		namespace:      opts.Namespace,
+		callback:       cb,
+		githubClient:   tokens,
+		eventHub:       eventHub,
+		feed:           feed,
+		quota:          opts.Quota,
+		archiver:       archiver,
+		policy:         opts.Policy,
+		policyFailOpen: opts.PolicyFailOpen,
+		validation:     opts.Validation,
+		repoSize:       opts.RepoSize,
+		eventPrivacy:   opts.EventPrivacy,
+		taskIndex:      newTaskIndex(opts.TaskIndexSize),
+
+		callbackTargets:     callbackTargets,
+		maxTimeoutExtension: opts.MaxTimeoutExtension,
+	}
+	if githubClient != nil {
+		handler.repoSizer = githubClient
 	}
 
 	// Health tracking for watcher and cache goroutines
@@ -143,12 +285,16 @@ func Run(opts Options) error {
 	if !taskCache.WaitForCacheSync(ctx) {
 		return fmt.Errorf("cache sync failed")
 	}
+	tasks := newKubeTaskStore(k8sClient, taskCache, opts.Namespace)
+	handler.tasks = tasks
 
 	// Start CRD status watcher
 	watcher := &statusWatcher{
-		client:   k8sClient,
+		tasks:    tasks,
 		callback: cb,
 		cache:    taskCache,
+		archiver: archiver,
+		feed:     feed,
 		log:      ctrl.Log.WithName("status-watcher"),
 	}
 
@@ -164,36 +310,71 @@ func Run(opts Options) error {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	}
-	readyzHandler := func(w http.ResponseWriter, _ *http.Request) {
-		if !watcherHealthy.Load() || !cacheHealthy.Load() {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			_, _ = w.Write([]byte("watcher or cache unhealthy"))
-			return
-		}
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("creating discovery client: %w", err)
 	}
+	checks := []readiness.Check{
+		goroutineCheck("cache", &cacheHealthy),
+		goroutineCheck("status-watcher", &watcherHealthy),
+		kubernetesCheck(discoveryClient),
+		crdCheck(k8sClient, opts.Namespace),
+		callbackCheck(taskCache, opts.Namespace),
+	}
+	if githubClient != nil {
+		checks = append(checks, githubCheck(githubClient))
+	}
+	readyzHandler := readiness.New(checks...).ServeHTTP
 
 	// Public router (port 8080) - external API for adapters/UI
 	publicRouter := chi.NewRouter()
 	publicRouter.Use(middleware.RequestID)
+	publicRouter.Use(logging.Middleware(log))
 	publicRouter.Use(middleware.RealIP)
 	publicRouter.Use(middleware.Recoverer)
+	publicRouter.Use(metricsMiddleware("public"))
 	publicRouter.Get("/healthz", healthzHandler)
 	publicRouter.Get("/readyz", readyzHandler)
-	publicRouter.Route("/api/v1", func(r chi.Router) {
+	if basePath != "" {
+		// Let gateways health-check through the same prefix they route.
+		publicRouter.Get(basePath+"/healthz", healthzHandler)
+		publicRouter.Get(basePath+"/readyz", readyzHandler)
+	}
+	publicRouter.Route(basePath+"/api/v1", func(r chi.Router) {
 		r.Use(contentTypeMiddleware)
 		r.Post("/tasks", handler.createTask)
 		r.Get("/tasks", handler.listTasks)
+		r.Get("/tasks/search", handler.searchTasks)
+		r.Get("/tasks/active", handler.getActiveTask)
+		r.Get("/tasks/watch", handler.watchTasks)
 		r.Get("/tasks/{taskID}", handler.getTask)
 		r.Get("/tasks/{taskID}/events", handler.streamEvents)
+		r.Post("/tasks/{taskID}/notes", handler.addNote)
+		r.Post("/tasks/{taskID}/extend", handler.extendTimeout)
+		r.Post("/tasks/{taskID}/cancel", handler.cancelTask)
+		r.Post("/tasks/{taskID}/retry", handler.retryTask)
+		r.Get("/tasks/{taskID}/callbacks", handler.getCallbacks)
+		r.Post("/tasks/{taskID}/callbacks/replay", handler.replayCallback)
+		r.Get("/fleets/{fleetID}", handler.getFleet)
+		r.Get("/archive/tasks/{taskID}", handler.getArchivedTask)
+		r.Post("/task-templates", handler.createTaskTemplate)
+		r.Get("/task-templates", handler.listTaskTemplates)
+		r.Get("/task-templates/{templateName}", handler.getTaskTemplate)
+		r.Get("/maintenance", handler.getMaintenance)
+		r.Get("/callback-signing-key", handler.getCallbackSigningKey)
+		r.Get("/openapi.json", getOpenAPISpec)
+		if opts.AdminAPI {
+			r.Route("/admin", handler.adminRoutes)
+		}
 	})
 
 	// Internal router (port 8081) - runner-only API (NetworkPolicy protected)
 	internalRouter := chi.NewRouter()
 	internalRouter.Use(middleware.RequestID)
+	internalRouter.Use(logging.Middleware(log))
 	internalRouter.Use(middleware.RealIP)
 	internalRouter.Use(middleware.Recoverer)
+	internalRouter.Use(metricsMiddleware("internal"))
 	internalRouter.Get("/healthz", healthzHandler)
 	internalRouter.Get("/readyz", readyzHandler)
 	internalRouter.Route("/api/v1", func(r chi.Router) {
@@ -202,12 +383,69 @@ func Run(opts Options) error {
 		r.Post("/tasks/{taskID}/events", handler.postEvents)
 		r.Get("/tasks/{taskID}/data", handler.getTaskData)
 		r.Get("/tasks/{taskID}/token", handler.getTaskToken)
+		r.Get("/event-schemas", handler.getEventSchemas)
 	})
 
+	// gRPC shares the ports of the REST API, over unencrypted HTTP/2.
+	publicHandler := http.Handler(publicRouter)
+	internalHandler := http.Handler(internalRouter)
+	var protocols *http.Protocols
+	var publicGRPC, internalGRPC *grpc.Server
+	if opts.GRPC {
+		publicGRPC = newGRPCServer()
+		shepherdv1.RegisterTaskServiceServer(publicGRPC, &taskService{
+			rest:    restBridge{router: publicRouter, prefix: basePath + "/api/v1"},
+			handler: handler,
+			log:     log.WithName("grpc"),
+		})
+		internalGRPC = newGRPCServer()
+		shepherdv1.RegisterRunnerServiceServer(internalGRPC, &runnerService{
+			rest: restBridge{router: internalRouter, prefix: "/api/v1"},
+		})
+		publicHandler = grpcHandler(publicGRPC, publicRouter)
+		internalHandler = grpcHandler(internalGRPC, internalRouter)
+
+		protocols = new(http.Protocols)
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+		log.Info("gRPC API enabled")
+	}
+
+	publicLn, err := listen.Listen(opts.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("public listener: %w", err)
+	}
+	internalLn, err := listen.Listen(opts.InternalListenAddr)
+	if err != nil {
+		_ = publicLn.Close()
+		return fmt.Errorf("internal listener: %w", err)
+	}
+	var metricsLn net.Listener
+	if opts.MetricsListenAddr != "" {
+		metricsLn, err = listen.Listen(opts.MetricsListenAddr)
+		if err != nil {
+			_ = publicLn.Close()
+			_ = internalLn.Close()
+			return fmt.Errorf("metrics listener: %w", err)
+		}
+	}
+	var debugLn net.Listener
+	if opts.DebugListenAddr != "" {
+		debugLn, err = listen.Listen(opts.DebugListenAddr)
+		if err != nil {
+			_ = publicLn.Close()
+			_ = internalLn.Close()
+			if metricsLn != nil {
+				_ = metricsLn.Close()
+			}
+			return fmt.Errorf("debug listener: %w", err)
+		}
+	}
+
 	// Start public server
 	publicSrv := &http.Server{
-		Addr:         opts.ListenAddr,
-		Handler:      publicRouter,
+		Handler:      tracing.Handler(publicHandler, "shepherd-api"),
+		Protocols:    protocols,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 60 * time.Second,
 		IdleTimeout:  120 * time.Second,
@@ -215,26 +453,55 @@ func Run(opts Options) error {
 
 	// Start internal server
 	internalSrv := &http.Server{
-		Addr:         opts.InternalListenAddr,
-		Handler:      internalRouter,
+		Handler:      tracing.Handler(internalHandler, "shepherd-api-internal"),
+		Protocols:    protocols,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 60 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}
 
-	errCh := make(chan error, 2)
+	// Metrics server, on its own listener so scrapes need neither the
+	// public nor the runner port
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metricsHandler())
+	metricsSrv := &http.Server{
+		Handler:      metricsMux,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 60 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	debugSrv := debug.NewServer()
+
+	errCh := make(chan error, 4)
 	go func() {
-		log.Info("starting public API server", "addr", opts.ListenAddr)
-		if err := publicSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Info("starting public API server", "addr", opts.ListenAddr, "basePath", basePath)
+		if err := publicSrv.Serve(publicLn); err != nil && err != http.ErrServerClosed {
 			errCh <- fmt.Errorf("public server: %w", err)
 		}
 	}()
 	go func() {
 		log.Info("starting internal API server", "addr", opts.InternalListenAddr)
-		if err := internalSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := internalSrv.Serve(internalLn); err != nil && err != http.ErrServerClosed {
 			errCh <- fmt.Errorf("internal server: %w", err)
 		}
 	}()
+	if metricsLn != nil {
+		go func() {
+			log.Info("starting metrics server", "addr", opts.MetricsListenAddr)
+			if err := metricsSrv.Serve(metricsLn); err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("metrics server: %w", err)
+			}
+		}()
+	}
+	if debugLn != nil {
+		go func() {
+			log.Info("starting debug server", "addr", opts.DebugListenAddr)
+			if err := debugSrv.Serve(debugLn); err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("debug server: %w", err)
+			}
+		}()
+	}
 
 	// Wait for shutdown signal or error
 	select {
@@ -242,7 +509,13 @@ func Run(opts Options) error {
 		log.Info("shutting down API servers")
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer shutdownCancel()
-		// Shutdown both servers
+		// gRPC streams, such as event watches, would hold up the HTTP
+		// shutdown until it times out.
+		if opts.GRPC {
+			publicGRPC.Stop()
+			internalGRPC.Stop()
+		}
+		// Shutdown all servers
 		var errs []error
 		if err := publicSrv.Shutdown(shutdownCtx); err != nil {
 			errs = append(errs, fmt.Errorf("public shutdown: %w", err))
@@ -250,6 +523,12 @@ func Run(opts Options) error {
 		if err := internalSrv.Shutdown(shutdownCtx); err != nil {
 			errs = append(errs, fmt.Errorf("internal shutdown: %w", err))
 		}
+		if err := metricsSrv.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("metrics shutdown: %w", err))
+		}
+		if err := debugSrv.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("debug shutdown: %w", err))
+		}
 		if len(errs) > 0 {
 			return fmt.Errorf("shutdown errors: %v", errs)
 		}
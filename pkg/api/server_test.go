@@ -69,6 +69,7 @@ func buildTestRouters(h *taskHandler) (publicRouter, internalRouter *chi.Mux) {
 		r.Post("/tasks/{taskID}/events", h.postEvents)
 		r.Get("/tasks/{taskID}/data", h.getTaskData)
 		r.Get("/tasks/{taskID}/token", h.getTaskToken)
+		r.Get("/event-schemas", h.getEventSchemas)
 	})
 
 	return publicRouter, internalRouter
@@ -101,11 +102,13 @@ func TestDualPortRouting(t *testing.T) {
 		{"public: POST /tasks/{id}/events not available", publicRouter, http.MethodPost, "/api/v1/tasks/test-task/events", 0, true},
 		{"public: GET /tasks/{id}/data not available", publicRouter, http.MethodGet, "/api/v1/tasks/test-task/data", 0, true},
 		{"public: GET /tasks/{id}/token not available", publicRouter, http.MethodGet, "/api/v1/tasks/test-task/token", 0, true},
+		{"public: GET /event-schemas not available", publicRouter, http.MethodGet, "/api/v1/event-schemas", 0, true},
 		// Internal router - routes that SHOULD be available
 		{"internal: POST /tasks/{id}/status available", internalRouter, http.MethodPost, "/api/v1/tasks/test-task/status", http.StatusBadRequest, false},
 		{"internal: POST /tasks/{id}/events available", internalRouter, http.MethodPost, "/api/v1/tasks/test-task/events", http.StatusNotFound, false},
 		{"internal: GET /tasks/{id}/data available", internalRouter, http.MethodGet, "/api/v1/tasks/test-task/data", http.StatusNotFound, false},
 		{"internal: GET /tasks/{id}/token available", internalRouter, http.MethodGet, "/api/v1/tasks/test-task/token", http.StatusNotFound, false},
+		{"internal: GET /event-schemas available", internalRouter, http.MethodGet, "/api/v1/event-schemas", http.StatusOK, false},
 		{"internal: GET /healthz available", internalRouter, http.MethodGet, "/healthz", http.StatusOK, false},
 		{"internal: GET /readyz available", internalRouter, http.MethodGet, "/readyz", http.StatusOK, false},
 		// Internal router - routes that should NOT be available (public-only)
@@ -133,3 +136,31 @@ func TestDualPortRouting(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeBasePath(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"/", "", false},
+		{"/shepherd", "/shepherd", false},
+		{"shepherd/", "/shepherd", false},
+		{"/tools/shepherd//", "/tools/shepherd", false},
+		{"/a//b", "", true},
+		{"/shepherd?x=1", "", true},
+		{"/{tenant}", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := normalizeBasePath(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
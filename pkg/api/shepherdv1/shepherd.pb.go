@@ -0,0 +1,1877 @@
+// Copyright 2026.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The gRPC API of the Shepherd API server. Every method behaves like the
+// REST endpoint named in its comment, which remains the reference; see
+// api/openapi.yaml for the details of fields and errors.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: shepherd/v1/shepherd.proto
+
+package shepherdv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Repo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Ref           string                 `protobuf:"bytes,2,opt,name=ref,proto3" json:"ref,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Repo) Reset() {
+	*x = Repo{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Repo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Repo) ProtoMessage() {}
+
+func (x *Repo) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Repo.ProtoReflect.Descriptor instead.
+func (*Repo) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Repo) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Repo) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+type TaskDetails struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Context       string                 `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	SourceUrl     string                 `protobuf:"bytes,3,opt,name=source_url,json=sourceUrl,proto3" json:"source_url,omitempty"`
+	SourceType    string                 `protobuf:"bytes,4,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`
+	SourceId      string                 `protobuf:"bytes,5,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskDetails) Reset() {
+	*x = TaskDetails{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskDetails) ProtoMessage() {}
+
+func (x *TaskDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskDetails.ProtoReflect.Descriptor instead.
+func (*TaskDetails) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TaskDetails) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TaskDetails) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *TaskDetails) GetSourceUrl() string {
+	if x != nil {
+		return x.SourceUrl
+	}
+	return ""
+}
+
+func (x *TaskDetails) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *TaskDetails) GetSourceId() string {
+	if x != nil {
+		return x.SourceId
+	}
+	return ""
+}
+
+type RunnerConfig struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	SandboxTemplateName string                 `protobuf:"bytes,1,opt,name=sandbox_template_name,json=sandboxTemplateName,proto3" json:"sandbox_template_name,omitempty"`
+	Timeout             *durationpb.Duration   `protobuf:"bytes,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	ServiceAccountName  string                 `protobuf:"bytes,3,opt,name=service_account_name,json=serviceAccountName,proto3" json:"service_account_name,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RunnerConfig) Reset() {
+	*x = RunnerConfig{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunnerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunnerConfig) ProtoMessage() {}
+
+func (x *RunnerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunnerConfig.ProtoReflect.Descriptor instead.
+func (*RunnerConfig) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RunnerConfig) GetSandboxTemplateName() string {
+	if x != nil {
+		return x.SandboxTemplateName
+	}
+	return ""
+}
+
+func (x *RunnerConfig) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *RunnerConfig) GetServiceAccountName() string {
+	if x != nil {
+		return x.ServiceAccountName
+	}
+	return ""
+}
+
+type CreateTaskRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Repo        *Repo                  `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Task        *TaskDetails           `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	CallbackUrl string                 `protobuf:"bytes,3,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+	Runner      *RunnerConfig          `protobuf:"bytes,4,opt,name=runner,proto3" json:"runner,omitempty"`
+	Labels      map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Priority    int32                  `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`
+	DependsOn   []string               `protobuf:"bytes,7,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// json or cloudevents; empty uses the API server's default.
+	CallbackFormat string `protobuf:"bytes,8,opt,name=callback_format,json=callbackFormat,proto3" json:"callback_format,omitempty"`
+	// TaskTemplate supplying defaults for the task.
+	TemplateRef string `protobuf:"bytes,9,opt,name=template_ref,json=templateRef,proto3" json:"template_ref,omitempty"`
+	// Correlation ID of the task; generated when empty.
+	CorrelationId string `protobuf:"bytes,10,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskRequest) Reset() {
+	*x = CreateTaskRequest{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskRequest) ProtoMessage() {}
+
+func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTaskRequest) GetRepo() *Repo {
+	if x != nil {
+		return x.Repo
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetTask() *TaskDetails {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetRunner() *RunnerConfig {
+	if x != nil {
+		return x.Runner
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *CreateTaskRequest) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *CreateTaskRequest) GetCallbackFormat() string {
+	if x != nil {
+		return x.CallbackFormat
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetTemplateRef() string {
+	if x != nil {
+		return x.TemplateRef
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+type GetTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Repository URL or owner/name.
+	Repo  string `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Issue string `protobuf:"bytes,2,opt,name=issue,proto3" json:"issue,omitempty"`
+	Fleet string `protobuf:"bytes,3,opt,name=fleet,proto3" json:"fleet,omitempty"`
+	// Only tasks that have not finished.
+	Active        bool     `protobuf:"varint,4,opt,name=active,proto3" json:"active,omitempty"`
+	Phases        []string `protobuf:"bytes,5,rep,name=phases,proto3" json:"phases,omitempty"`
+	Sort          string   `protobuf:"bytes,6,opt,name=sort,proto3" json:"sort,omitempty"`
+	Order         string   `protobuf:"bytes,7,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTasksRequest) GetRepo() string {
+	if x != nil {
+		return x.Repo
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetIssue() string {
+	if x != nil {
+		return x.Issue
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetFleet() string {
+	if x != nil {
+		return x.Fleet
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetPhases() []string {
+	if x != nil {
+		return x.Phases
+	}
+	return nil
+}
+
+func (x *ListTasksRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+type ListTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type Task struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Namespace      string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Repo           *Repo                  `protobuf:"bytes,3,opt,name=repo,proto3" json:"repo,omitempty"`
+	Task           *TaskDetails           `protobuf:"bytes,4,opt,name=task,proto3" json:"task,omitempty"`
+	CallbackUrl    string                 `protobuf:"bytes,5,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+	RequestedBy    string                 `protobuf:"bytes,6,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	CorrelationId  string                 `protobuf:"bytes,7,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	Priority       int32                  `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	DependsOn      []string               `protobuf:"bytes,9,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	Status         *TaskStatus            `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CompletionTime *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=completion_time,json=completionTime,proto3" json:"completion_time,omitempty"`
+	QueuedSeconds  int64                  `protobuf:"varint,13,opt,name=queued_seconds,json=queuedSeconds,proto3" json:"queued_seconds,omitempty"`
+	RunningSeconds int64                  `protobuf:"varint,14,opt,name=running_seconds,json=runningSeconds,proto3" json:"running_seconds,omitempty"`
+	TotalSeconds   int64                  `protobuf:"varint,15,opt,name=total_seconds,json=totalSeconds,proto3" json:"total_seconds,omitempty"`
+	Notes          []*Note                `protobuf:"bytes,16,rep,name=notes,proto3" json:"notes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Task) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Task) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *Task) GetRepo() *Repo {
+	if x != nil {
+		return x.Repo
+	}
+	return nil
+}
+
+func (x *Task) GetTask() *TaskDetails {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *Task) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+func (x *Task) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+func (x *Task) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *Task) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Task) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *Task) GetStatus() *TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetCompletionTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletionTime
+	}
+	return nil
+}
+
+func (x *Task) GetQueuedSeconds() int64 {
+	if x != nil {
+		return x.QueuedSeconds
+	}
+	return 0
+}
+
+func (x *Task) GetRunningSeconds() int64 {
+	if x != nil {
+		return x.RunningSeconds
+	}
+	return 0
+}
+
+func (x *Task) GetTotalSeconds() int64 {
+	if x != nil {
+		return x.TotalSeconds
+	}
+	return 0
+}
+
+func (x *Task) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+type TaskStatus struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Phase               string                 `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	Message             string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	SandboxClaimName    string                 `protobuf:"bytes,3,opt,name=sandbox_claim_name,json=sandboxClaimName,proto3" json:"sandbox_claim_name,omitempty"`
+	SandboxTemplateName string                 `protobuf:"bytes,4,opt,name=sandbox_template_name,json=sandboxTemplateName,proto3" json:"sandbox_template_name,omitempty"`
+	PrUrl               string                 `protobuf:"bytes,5,opt,name=pr_url,json=prUrl,proto3" json:"pr_url,omitempty"`
+	Error               string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	CostUsd             float64                `protobuf:"fixed64,7,opt,name=cost_usd,json=costUsd,proto3" json:"cost_usd,omitempty"`
+	// When the runner's timeout expires; set once the task has started.
+	Deadline *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// Seconds until the deadline; set while a started task has not finished.
+	RemainingSeconds *int64 `protobuf:"varint,9,opt,name=remaining_seconds,json=remainingSeconds,proto3,oneof" json:"remaining_seconds,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *TaskStatus) Reset() {
+	*x = TaskStatus{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStatus) ProtoMessage() {}
+
+func (x *TaskStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStatus.ProtoReflect.Descriptor instead.
+func (*TaskStatus) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TaskStatus) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetSandboxClaimName() string {
+	if x != nil {
+		return x.SandboxClaimName
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetSandboxTemplateName() string {
+	if x != nil {
+		return x.SandboxTemplateName
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetPrUrl() string {
+	if x != nil {
+		return x.PrUrl
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetCostUsd() float64 {
+	if x != nil {
+		return x.CostUsd
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+func (x *TaskStatus) GetRemainingSeconds() int64 {
+	if x != nil && x.RemainingSeconds != nil {
+		return *x.RemainingSeconds
+	}
+	return 0
+}
+
+type Note struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Author        string                 `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Note) Reset() {
+	*x = Note{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Note) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Note) ProtoMessage() {}
+
+func (x *Note) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Note.ProtoReflect.Descriptor instead.
+func (*Note) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Note) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Note) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Note) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type WatchTaskEventsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Only events with a greater sequence are replayed.
+	After         int64 `protobuf:"varint,2,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTaskEventsRequest) Reset() {
+	*x = WatchTaskEventsRequest{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTaskEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTaskEventsRequest) ProtoMessage() {}
+
+func (x *WatchTaskEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTaskEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchTaskEventsRequest) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchTaskEventsRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *WatchTaskEventsRequest) GetAfter() int64 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+type WatchTaskEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*WatchTaskEventsResponse_Event
+	//	*WatchTaskEventsResponse_Complete
+	Kind          isWatchTaskEventsResponse_Kind `protobuf_oneof:"kind"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTaskEventsResponse) Reset() {
+	*x = WatchTaskEventsResponse{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTaskEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTaskEventsResponse) ProtoMessage() {}
+
+func (x *WatchTaskEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTaskEventsResponse.ProtoReflect.Descriptor instead.
+func (*WatchTaskEventsResponse) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchTaskEventsResponse) GetKind() isWatchTaskEventsResponse_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *WatchTaskEventsResponse) GetEvent() *TaskEvent {
+	if x != nil {
+		if x, ok := x.Kind.(*WatchTaskEventsResponse_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *WatchTaskEventsResponse) GetComplete() *TaskComplete {
+	if x != nil {
+		if x, ok := x.Kind.(*WatchTaskEventsResponse_Complete); ok {
+			return x.Complete
+		}
+	}
+	return nil
+}
+
+type isWatchTaskEventsResponse_Kind interface {
+	isWatchTaskEventsResponse_Kind()
+}
+
+type WatchTaskEventsResponse_Event struct {
+	Event *TaskEvent `protobuf:"bytes,1,opt,name=event,proto3,oneof"`
+}
+
+type WatchTaskEventsResponse_Complete struct {
+	Complete *TaskComplete `protobuf:"bytes,2,opt,name=complete,proto3,oneof"`
+}
+
+func (*WatchTaskEventsResponse_Event) isWatchTaskEventsResponse_Kind() {}
+
+func (*WatchTaskEventsResponse_Complete) isWatchTaskEventsResponse_Kind() {}
+
+type TaskEvent struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Sequence  int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// thinking, tool_call, tool_result or error.
+	Type          string           `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Summary       string           `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	Tool          string           `protobuf:"bytes,5,opt,name=tool,proto3" json:"tool,omitempty"`
+	Input         *structpb.Struct `protobuf:"bytes,6,opt,name=input,proto3" json:"input,omitempty"`
+	Output        *TaskEventOutput `protobuf:"bytes,7,opt,name=output,proto3" json:"output,omitempty"`
+	Metadata      *structpb.Struct `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEvent) Reset() {
+	*x = TaskEvent{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEvent) ProtoMessage() {}
+
+func (x *TaskEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
+func (*TaskEvent) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TaskEvent) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *TaskEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetTool() string {
+	if x != nil {
+		return x.Tool
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetInput() *structpb.Struct {
+	if x != nil {
+		return x.Input
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetOutput() *TaskEventOutput {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type TaskEventOutput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Summary       string                 `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEventOutput) Reset() {
+	*x = TaskEventOutput{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEventOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEventOutput) ProtoMessage() {}
+
+func (x *TaskEventOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEventOutput.ProtoReflect.Descriptor instead.
+func (*TaskEventOutput) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TaskEventOutput) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TaskEventOutput) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+type TaskComplete struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Phase the task finished in.
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	PrUrl         string `protobuf:"bytes,3,opt,name=pr_url,json=prUrl,proto3" json:"pr_url,omitempty"`
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskComplete) Reset() {
+	*x = TaskComplete{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskComplete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskComplete) ProtoMessage() {}
+
+func (x *TaskComplete) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskComplete.ProtoReflect.Descriptor instead.
+func (*TaskComplete) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TaskComplete) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskComplete) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TaskComplete) GetPrUrl() string {
+	if x != nil {
+		return x.PrUrl
+	}
+	return ""
+}
+
+func (x *TaskComplete) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetTaskDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskDataRequest) Reset() {
+	*x = GetTaskDataRequest{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskDataRequest) ProtoMessage() {}
+
+func (x *GetTaskDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskDataRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskDataRequest) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetTaskDataRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+type TaskData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Context       string                 `protobuf:"bytes,4,opt,name=context,proto3" json:"context,omitempty"`
+	SourceUrl     string                 `protobuf:"bytes,5,opt,name=source_url,json=sourceUrl,proto3" json:"source_url,omitempty"`
+	SourceType    string                 `protobuf:"bytes,6,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`
+	Repo          *Repo                  `protobuf:"bytes,7,opt,name=repo,proto3" json:"repo,omitempty"`
+	Timeout       *durationpb.Duration   `protobuf:"bytes,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	Deadline      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	EventPrivacy  string                 `protobuf:"bytes,11,opt,name=event_privacy,json=eventPrivacy,proto3" json:"event_privacy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskData) Reset() {
+	*x = TaskData{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskData) ProtoMessage() {}
+
+func (x *TaskData) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskData.ProtoReflect.Descriptor instead.
+func (*TaskData) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TaskData) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *TaskData) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskData) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TaskData) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *TaskData) GetSourceUrl() string {
+	if x != nil {
+		return x.SourceUrl
+	}
+	return ""
+}
+
+func (x *TaskData) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *TaskData) GetRepo() *Repo {
+	if x != nil {
+		return x.Repo
+	}
+	return nil
+}
+
+func (x *TaskData) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *TaskData) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *TaskData) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+func (x *TaskData) GetEventPrivacy() string {
+	if x != nil {
+		return x.EventPrivacy
+	}
+	return ""
+}
+
+type UpdateTaskStatusRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// started, progress, completed or failed.
+	Event         string           `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	Message       string           `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Details       *structpb.Struct `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskStatusRequest) Reset() {
+	*x = UpdateTaskStatusRequest{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskStatusRequest) ProtoMessage() {}
+
+func (x *UpdateTaskStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskStatusRequest) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateTaskStatusRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *UpdateTaskStatusRequest) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *UpdateTaskStatusRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UpdateTaskStatusRequest) GetDetails() *structpb.Struct {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type UpdateTaskStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Note          string                 `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskStatusResponse) Reset() {
+	*x = UpdateTaskStatusResponse{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskStatusResponse) ProtoMessage() {}
+
+func (x *UpdateTaskStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTaskStatusResponse) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpdateTaskStatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateTaskStatusResponse) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type ReportEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Events        []*TaskEvent           `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportEventsRequest) Reset() {
+	*x = ReportEventsRequest{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportEventsRequest) ProtoMessage() {}
+
+func (x *ReportEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportEventsRequest.ProtoReflect.Descriptor instead.
+func (*ReportEventsRequest) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReportEventsRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ReportEventsRequest) GetEvents() []*TaskEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type ReportEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of events accepted over the stream.
+	Accepted      int64 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportEventsResponse) Reset() {
+	*x = ReportEventsResponse{}
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportEventsResponse) ProtoMessage() {}
+
+func (x *ReportEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_shepherd_v1_shepherd_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportEventsResponse.ProtoReflect.Descriptor instead.
+func (*ReportEventsResponse) Descriptor() ([]byte, []int) {
+	return file_shepherd_v1_shepherd_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReportEventsResponse) GetAccepted() int64 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+var File_shepherd_v1_shepherd_proto protoreflect.FileDescriptor
+
+const file_shepherd_v1_shepherd_proto_rawDesc = "" +
+	"\n" +
+	"\x1ashepherd/v1/shepherd.proto\x12\vshepherd.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"*\n" +
+	"\x04Repo\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x10\n" +
+	"\x03ref\x18\x02 \x01(\tR\x03ref\"\xa6\x01\n" +
+	"\vTaskDetails\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x18\n" +
+	"\acontext\x18\x02 \x01(\tR\acontext\x12\x1d\n" +
+	"\n" +
+	"source_url\x18\x03 \x01(\tR\tsourceUrl\x12\x1f\n" +
+	"\vsource_type\x18\x04 \x01(\tR\n" +
+	"sourceType\x12\x1b\n" +
+	"\tsource_id\x18\x05 \x01(\tR\bsourceId\"\xa9\x01\n" +
+	"\fRunnerConfig\x122\n" +
+	"\x15sandbox_template_name\x18\x01 \x01(\tR\x13sandboxTemplateName\x123\n" +
+	"\atimeout\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x120\n" +
+	"\x14service_account_name\x18\x03 \x01(\tR\x12serviceAccountName\"\xeb\x03\n" +
+	"\x11CreateTaskRequest\x12%\n" +
+	"\x04repo\x18\x01 \x01(\v2\x11.shepherd.v1.RepoR\x04repo\x12,\n" +
+	"\x04task\x18\x02 \x01(\v2\x18.shepherd.v1.TaskDetailsR\x04task\x12!\n" +
+	"\fcallback_url\x18\x03 \x01(\tR\vcallbackUrl\x121\n" +
+	"\x06runner\x18\x04 \x01(\v2\x19.shepherd.v1.RunnerConfigR\x06runner\x12B\n" +
+	"\x06labels\x18\x05 \x03(\v2*.shepherd.v1.CreateTaskRequest.LabelsEntryR\x06labels\x12\x1a\n" +
+	"\bpriority\x18\x06 \x01(\x05R\bpriority\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\a \x03(\tR\tdependsOn\x12'\n" +
+	"\x0fcallback_format\x18\b \x01(\tR\x0ecallbackFormat\x12!\n" +
+	"\ftemplate_ref\x18\t \x01(\tR\vtemplateRef\x12%\n" +
+	"\x0ecorrelation_id\x18\n" +
+	" \x01(\tR\rcorrelationId\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\" \n" +
+	"\x0eGetTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xac\x01\n" +
+	"\x10ListTasksRequest\x12\x12\n" +
+	"\x04repo\x18\x01 \x01(\tR\x04repo\x12\x14\n" +
+	"\x05issue\x18\x02 \x01(\tR\x05issue\x12\x14\n" +
+	"\x05fleet\x18\x03 \x01(\tR\x05fleet\x12\x16\n" +
+	"\x06active\x18\x04 \x01(\bR\x06active\x12\x16\n" +
+	"\x06phases\x18\x05 \x03(\tR\x06phases\x12\x12\n" +
+	"\x04sort\x18\x06 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\a \x01(\tR\x05order\"<\n" +
+	"\x11ListTasksResponse\x12'\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x11.shepherd.v1.TaskR\x05tasks\"\x80\x05\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12%\n" +
+	"\x04repo\x18\x03 \x01(\v2\x11.shepherd.v1.RepoR\x04repo\x12,\n" +
+	"\x04task\x18\x04 \x01(\v2\x18.shepherd.v1.TaskDetailsR\x04task\x12!\n" +
+	"\fcallback_url\x18\x05 \x01(\tR\vcallbackUrl\x12!\n" +
+	"\frequested_by\x18\x06 \x01(\tR\vrequestedBy\x12%\n" +
+	"\x0ecorrelation_id\x18\a \x01(\tR\rcorrelationId\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\x05R\bpriority\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\t \x03(\tR\tdependsOn\x12/\n" +
+	"\x06status\x18\n" +
+	" \x01(\v2\x17.shepherd.v1.TaskStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12C\n" +
+	"\x0fcompletion_time\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\x0ecompletionTime\x12%\n" +
+	"\x0equeued_seconds\x18\r \x01(\x03R\rqueuedSeconds\x12'\n" +
+	"\x0frunning_seconds\x18\x0e \x01(\x03R\x0erunningSeconds\x12#\n" +
+	"\rtotal_seconds\x18\x0f \x01(\x03R\ftotalSeconds\x12'\n" +
+	"\x05notes\x18\x10 \x03(\v2\x11.shepherd.v1.NoteR\x05notes\"\xe6\x02\n" +
+	"\n" +
+	"TaskStatus\x12\x14\n" +
+	"\x05phase\x18\x01 \x01(\tR\x05phase\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
+	"\x12sandbox_claim_name\x18\x03 \x01(\tR\x10sandboxClaimName\x122\n" +
+	"\x15sandbox_template_name\x18\x04 \x01(\tR\x13sandboxTemplateName\x12\x15\n" +
+	"\x06pr_url\x18\x05 \x01(\tR\x05prUrl\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x19\n" +
+	"\bcost_usd\x18\a \x01(\x01R\acostUsd\x126\n" +
+	"\bdeadline\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\x120\n" +
+	"\x11remaining_seconds\x18\t \x01(\x03H\x00R\x10remainingSeconds\x88\x01\x01B\x14\n" +
+	"\x12_remaining_seconds\"m\n" +
+	"\x04Note\x12\x16\n" +
+	"\x06author\x18\x01 \x01(\tR\x06author\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"G\n" +
+	"\x16WatchTaskEventsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x14\n" +
+	"\x05after\x18\x02 \x01(\x03R\x05after\"\x8a\x01\n" +
+	"\x17WatchTaskEventsResponse\x12.\n" +
+	"\x05event\x18\x01 \x01(\v2\x16.shepherd.v1.TaskEventH\x00R\x05event\x127\n" +
+	"\bcomplete\x18\x02 \x01(\v2\x19.shepherd.v1.TaskCompleteH\x00R\bcompleteB\x06\n" +
+	"\x04kind\"\xbd\x02\n" +
+	"\tTaskEvent\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x18\n" +
+	"\asummary\x18\x04 \x01(\tR\asummary\x12\x12\n" +
+	"\x04tool\x18\x05 \x01(\tR\x04tool\x12-\n" +
+	"\x05input\x18\x06 \x01(\v2\x17.google.protobuf.StructR\x05input\x124\n" +
+	"\x06output\x18\a \x01(\v2\x1c.shepherd.v1.TaskEventOutputR\x06output\x123\n" +
+	"\bmetadata\x18\b \x01(\v2\x17.google.protobuf.StructR\bmetadata\"E\n" +
+	"\x0fTaskEventOutput\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\asummary\x18\x02 \x01(\tR\asummary\"l\n" +
+	"\fTaskComplete\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x15\n" +
+	"\x06pr_url\x18\x03 \x01(\tR\x05prUrl\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"-\n" +
+	"\x12GetTaskDataRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\xad\x03\n" +
+	"\bTaskData\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x05R\aversion\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x18\n" +
+	"\acontext\x18\x04 \x01(\tR\acontext\x12\x1d\n" +
+	"\n" +
+	"source_url\x18\x05 \x01(\tR\tsourceUrl\x12\x1f\n" +
+	"\vsource_type\x18\x06 \x01(\tR\n" +
+	"sourceType\x12%\n" +
+	"\x04repo\x18\a \x01(\v2\x11.shepherd.v1.RepoR\x04repo\x123\n" +
+	"\atimeout\x18\b \x01(\v2\x19.google.protobuf.DurationR\atimeout\x129\n" +
+	"\n" +
+	"started_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x126\n" +
+	"\bdeadline\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\x12#\n" +
+	"\revent_privacy\x18\v \x01(\tR\feventPrivacy\"\x95\x01\n" +
+	"\x17UpdateTaskStatusRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x14\n" +
+	"\x05event\x18\x02 \x01(\tR\x05event\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x121\n" +
+	"\adetails\x18\x04 \x01(\v2\x17.google.protobuf.StructR\adetails\"F\n" +
+	"\x18UpdateTaskStatusResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x12\n" +
+	"\x04note\x18\x02 \x01(\tR\x04note\"^\n" +
+	"\x13ReportEventsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12.\n" +
+	"\x06events\x18\x02 \x03(\v2\x16.shepherd.v1.TaskEventR\x06events\"2\n" +
+	"\x14ReportEventsResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x03R\baccepted2\xb5\x02\n" +
+	"\vTaskService\x12?\n" +
+	"\n" +
+	"CreateTask\x12\x1e.shepherd.v1.CreateTaskRequest\x1a\x11.shepherd.v1.Task\x129\n" +
+	"\aGetTask\x12\x1b.shepherd.v1.GetTaskRequest\x1a\x11.shepherd.v1.Task\x12J\n" +
+	"\tListTasks\x12\x1d.shepherd.v1.ListTasksRequest\x1a\x1e.shepherd.v1.ListTasksResponse\x12^\n" +
+	"\x0fWatchTaskEvents\x12#.shepherd.v1.WatchTaskEventsRequest\x1a$.shepherd.v1.WatchTaskEventsResponse0\x012\x8e\x02\n" +
+	"\rRunnerService\x12E\n" +
+	"\vGetTaskData\x12\x1f.shepherd.v1.GetTaskDataRequest\x1a\x15.shepherd.v1.TaskData\x12_\n" +
+	"\x10UpdateTaskStatus\x12$.shepherd.v1.UpdateTaskStatusRequest\x1a%.shepherd.v1.UpdateTaskStatusResponse\x12U\n" +
+	"\fReportEvents\x12 .shepherd.v1.ReportEventsRequest\x1a!.shepherd.v1.ReportEventsResponse(\x01B?Z=github.com/NissesSenap/shepherd/pkg/api/shepherdv1;shepherdv1b\x06proto3"
+
+var (
+	file_shepherd_v1_shepherd_proto_rawDescOnce sync.Once
+	file_shepherd_v1_shepherd_proto_rawDescData []byte
+)
+
+func file_shepherd_v1_shepherd_proto_rawDescGZIP() []byte {
+	file_shepherd_v1_shepherd_proto_rawDescOnce.Do(func() {
+		file_shepherd_v1_shepherd_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_shepherd_v1_shepherd_proto_rawDesc), len(file_shepherd_v1_shepherd_proto_rawDesc)))
+	})
+	return file_shepherd_v1_shepherd_proto_rawDescData
+}
+
+var file_shepherd_v1_shepherd_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_shepherd_v1_shepherd_proto_goTypes = []any{
+	(*Repo)(nil),                     // 0: shepherd.v1.Repo
+	(*TaskDetails)(nil),              // 1: shepherd.v1.TaskDetails
+	(*RunnerConfig)(nil),             // 2: shepherd.v1.RunnerConfig
+	(*CreateTaskRequest)(nil),        // 3: shepherd.v1.CreateTaskRequest
+	(*GetTaskRequest)(nil),           // 4: shepherd.v1.GetTaskRequest
+	(*ListTasksRequest)(nil),         // 5: shepherd.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),        // 6: shepherd.v1.ListTasksResponse
+	(*Task)(nil),                     // 7: shepherd.v1.Task
+	(*TaskStatus)(nil),               // 8: shepherd.v1.TaskStatus
+	(*Note)(nil),                     // 9: shepherd.v1.Note
+	(*WatchTaskEventsRequest)(nil),   // 10: shepherd.v1.WatchTaskEventsRequest
+	(*WatchTaskEventsResponse)(nil),  // 11: shepherd.v1.WatchTaskEventsResponse
+	(*TaskEvent)(nil),                // 12: shepherd.v1.TaskEvent
+	(*TaskEventOutput)(nil),          // 13: shepherd.v1.TaskEventOutput
+	(*TaskComplete)(nil),             // 14: shepherd.v1.TaskComplete
+	(*GetTaskDataRequest)(nil),       // 15: shepherd.v1.GetTaskDataRequest
+	(*TaskData)(nil),                 // 16: shepherd.v1.TaskData
+	(*UpdateTaskStatusRequest)(nil),  // 17: shepherd.v1.UpdateTaskStatusRequest
+	(*UpdateTaskStatusResponse)(nil), // 18: shepherd.v1.UpdateTaskStatusResponse
+	(*ReportEventsRequest)(nil),      // 19: shepherd.v1.ReportEventsRequest
+	(*ReportEventsResponse)(nil),     // 20: shepherd.v1.ReportEventsResponse
+	nil,                              // 21: shepherd.v1.CreateTaskRequest.LabelsEntry
+	(*durationpb.Duration)(nil),      // 22: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),    // 23: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),          // 24: google.protobuf.Struct
+}
+var file_shepherd_v1_shepherd_proto_depIdxs = []int32{
+	22, // 0: shepherd.v1.RunnerConfig.timeout:type_name -> google.protobuf.Duration
+	0,  // 1: shepherd.v1.CreateTaskRequest.repo:type_name -> shepherd.v1.Repo
+	1,  // 2: shepherd.v1.CreateTaskRequest.task:type_name -> shepherd.v1.TaskDetails
+	2,  // 3: shepherd.v1.CreateTaskRequest.runner:type_name -> shepherd.v1.RunnerConfig
+	21, // 4: shepherd.v1.CreateTaskRequest.labels:type_name -> shepherd.v1.CreateTaskRequest.LabelsEntry
+	7,  // 5: shepherd.v1.ListTasksResponse.tasks:type_name -> shepherd.v1.Task
+	0,  // 6: shepherd.v1.Task.repo:type_name -> shepherd.v1.Repo
+	1,  // 7: shepherd.v1.Task.task:type_name -> shepherd.v1.TaskDetails
+	8,  // 8: shepherd.v1.Task.status:type_name -> shepherd.v1.TaskStatus
+	23, // 9: shepherd.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	23, // 10: shepherd.v1.Task.completion_time:type_name -> google.protobuf.Timestamp
+	9,  // 11: shepherd.v1.Task.notes:type_name -> shepherd.v1.Note
+	23, // 12: shepherd.v1.TaskStatus.deadline:type_name -> google.protobuf.Timestamp
+	23, // 13: shepherd.v1.Note.created_at:type_name -> google.protobuf.Timestamp
+	12, // 14: shepherd.v1.WatchTaskEventsResponse.event:type_name -> shepherd.v1.TaskEvent
+	14, // 15: shepherd.v1.WatchTaskEventsResponse.complete:type_name -> shepherd.v1.TaskComplete
+	23, // 16: shepherd.v1.TaskEvent.timestamp:type_name -> google.protobuf.Timestamp
+	24, // 17: shepherd.v1.TaskEvent.input:type_name -> google.protobuf.Struct
+	13, // 18: shepherd.v1.TaskEvent.output:type_name -> shepherd.v1.TaskEventOutput
+	24, // 19: shepherd.v1.TaskEvent.metadata:type_name -> google.protobuf.Struct
+	0,  // 20: shepherd.v1.TaskData.repo:type_name -> shepherd.v1.Repo
+	22, // 21: shepherd.v1.TaskData.timeout:type_name -> google.protobuf.Duration
+	23, // 22: shepherd.v1.TaskData.started_at:type_name -> google.protobuf.Timestamp
+	23, // 23: shepherd.v1.TaskData.deadline:type_name -> google.protobuf.Timestamp
+	24, // 24: shepherd.v1.UpdateTaskStatusRequest.details:type_name -> google.protobuf.Struct
+	12, // 25: shepherd.v1.ReportEventsRequest.events:type_name -> shepherd.v1.TaskEvent
+	3,  // 26: shepherd.v1.TaskService.CreateTask:input_type -> shepherd.v1.CreateTaskRequest
+	4,  // 27: shepherd.v1.TaskService.GetTask:input_type -> shepherd.v1.GetTaskRequest
+	5,  // 28: shepherd.v1.TaskService.ListTasks:input_type -> shepherd.v1.ListTasksRequest
+	10, // 29: shepherd.v1.TaskService.WatchTaskEvents:input_type -> shepherd.v1.WatchTaskEventsRequest
+	15, // 30: shepherd.v1.RunnerService.GetTaskData:input_type -> shepherd.v1.GetTaskDataRequest
+	17, // 31: shepherd.v1.RunnerService.UpdateTaskStatus:input_type -> shepherd.v1.UpdateTaskStatusRequest
+	19, // 32: shepherd.v1.RunnerService.ReportEvents:input_type -> shepherd.v1.ReportEventsRequest
+	7,  // 33: shepherd.v1.TaskService.CreateTask:output_type -> shepherd.v1.Task
+	7,  // 34: shepherd.v1.TaskService.GetTask:output_type -> shepherd.v1.Task
+	6,  // 35: shepherd.v1.TaskService.ListTasks:output_type -> shepherd.v1.ListTasksResponse
+	11, // 36: shepherd.v1.TaskService.WatchTaskEvents:output_type -> shepherd.v1.WatchTaskEventsResponse
+	16, // 37: shepherd.v1.RunnerService.GetTaskData:output_type -> shepherd.v1.TaskData
+	18, // 38: shepherd.v1.RunnerService.UpdateTaskStatus:output_type -> shepherd.v1.UpdateTaskStatusResponse
+	20, // 39: shepherd.v1.RunnerService.ReportEvents:output_type -> shepherd.v1.ReportEventsResponse
+	33, // [33:40] is the sub-list for method output_type
+	26, // [26:33] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_shepherd_v1_shepherd_proto_init() }
+func file_shepherd_v1_shepherd_proto_init() {
+	if File_shepherd_v1_shepherd_proto != nil {
+		return
+	}
+	file_shepherd_v1_shepherd_proto_msgTypes[8].OneofWrappers = []any{}
+	file_shepherd_v1_shepherd_proto_msgTypes[11].OneofWrappers = []any{
+		(*WatchTaskEventsResponse_Event)(nil),
+		(*WatchTaskEventsResponse_Complete)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_shepherd_v1_shepherd_proto_rawDesc), len(file_shepherd_v1_shepherd_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_shepherd_v1_shepherd_proto_goTypes,
+		DependencyIndexes: file_shepherd_v1_shepherd_proto_depIdxs,
+		MessageInfos:      file_shepherd_v1_shepherd_proto_msgTypes,
+	}.Build()
+	File_shepherd_v1_shepherd_proto = out.File
+	file_shepherd_v1_shepherd_proto_goTypes = nil
+	file_shepherd_v1_shepherd_proto_depIdxs = nil
+}
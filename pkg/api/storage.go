@@ -0,0 +1,179 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// Consistency says how current a read must be.
+type Consistency int
+
+const (
+	// ReadCached may return a copy that lags a moment behind the latest
+	// writes, such as from an informer cache.
+	ReadCached Consistency = iota
+	// ReadConsistent sees every write that has completed.
+	ReadConsistent
+)
+
+// TaskStore persists the tasks of the API server's namespace. The handlers
+// and the status watcher reach tasks only through it.
+//
+// A missing task is reported with an error for which apierrors.IsNotFound
+// is true, and a write that lost against a concurrent one with an error for
+// which apierrors.IsConflict is true.
+type TaskStore interface {
+	// Get returns the task with the given name.
+	Get(ctx context.Context, name string, consistency Consistency) (*toolkitv1alpha1.AgentTask, error)
+	// List returns the tasks whose labels match selector; a nil selector
+	// matches every task.
+	List(ctx context.Context, selector labels.Selector, consistency Consistency) ([]toolkitv1alpha1.AgentTask, error)
+	// Create stores a new task and updates it with the stored copy.
+	Create(ctx context.Context, task *toolkitv1alpha1.AgentTask) error
+	// UpdateStatus calls mutate on task, which must have been read from
+	// the store, and writes the changed status. It fails with a conflict if
+	// the task changed since it was read. An error from mutate aborts the
+	// update and is returned as is.
+	UpdateStatus(ctx context.Context, task *toolkitv1alpha1.AgentTask, mutate func(*toolkitv1alpha1.AgentTask) error) error
+	// SetAnnotation sets an annotation of task, regardless of concurrent
+	// changes to its other fields.
+	SetAnnotation(ctx context.Context, task *toolkitv1alpha1.AgentTask, key, value string) error
+	// Delete removes a task.
+	Delete(ctx context.Context, task *toolkitv1alpha1.AgentTask) error
+}
+
+// EventStore buffers the events runners report for each task and fans them
+// out to the streams following the task. EventHub keeps them in memory.
+type EventStore interface {
+	// Publish appends events to a task's stream.
+	Publish(taskID string, events []TaskEvent)
+	// Subscribe returns the buffered events after the given sequence number
+	// and a channel of the events published from now on.
+	Subscribe(taskID string, after int64) (history []TaskEvent, ch <-chan TaskEvent, unsubscribe func())
+	// Events returns the buffered events of a task.
+	Events(taskID string) []TaskEvent
+	// Complete ends a task's stream; its subscribers' channels are closed.
+	Complete(taskID string)
+	// IsStreamDone reports whether a task's stream has ended.
+	IsStreamDone(taskID string) bool
+	// Cleanup drops the buffered events of a task.
+	Cleanup(taskID string)
+}
+
+var _ EventStore = (*EventHub)(nil)
+
+// retryStatusUpdate reads the task with the given name and applies mutate
+// to its status, starting over when the write conflicts with a concurrent
+// one. Use it for changes that are still wanted on the newer copy, such as
+// appending to a list. An error from mutate aborts the update and is
+// returned as is.
+func retryStatusUpdate(ctx context.Context, tasks TaskStore, name string,
+	mutate func(*toolkitv1alpha1.AgentTask) error) (*toolkitv1alpha1.AgentTask, error) {
+	var task *toolkitv1alpha1.AgentTask
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		if task, err = tasks.Get(ctx, name, ReadConsistent); err != nil {
+			return err
+		}
+		return tasks.UpdateStatus(ctx, task, mutate)
+	})
+	return task, err
+}
+
+// kubeTaskStore keeps tasks as AgentTask resources.
+type kubeTaskStore struct {
+	client    client.Client
+	cache     client.Reader // Informer cache for cached reads; nil reads from client
+	namespace string
+}
+
+// newKubeTaskStore returns a TaskStore for the AgentTasks in namespace.
+// Cached reads are served from cache, if not nil.
+func newKubeTaskStore(c client.Client, cache client.Reader, namespace string) TaskStore {
+	return &kubeTaskStore{client: c, cache: cache, namespace: namespace}
+}
+
+func (s *kubeTaskStore) reader(consistency Consistency) client.Reader {
+	if s.cache == nil || consistency == ReadConsistent {
+		return s.client
+	}
+	return s.cache
+}
+
+func (s *kubeTaskStore) Get(ctx context.Context, name string, consistency Consistency) (*toolkitv1alpha1.AgentTask, error) {
+	var task toolkitv1alpha1.AgentTask
+	key := client.ObjectKey{Namespace: s.namespace, Name: name}
+	reader := s.reader(consistency)
+	err := reader.Get(ctx, key, &task)
+	if apierrors.IsNotFound(err) && reader == s.cache {
+		// A task created a moment ago may not have reached the cache yet.
+		err = s.client.Get(ctx, key, &task)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (s *kubeTaskStore) List(ctx context.Context, selector labels.Selector, consistency Consistency) ([]toolkitv1alpha1.AgentTask, error) {
+	opts := []client.ListOption{client.InNamespace(s.namespace)}
+	if selector != nil {
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+	var list toolkitv1alpha1.AgentTaskList
+	if err := s.reader(consistency).List(ctx, &list, opts...); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (s *kubeTaskStore) Create(ctx context.Context, task *toolkitv1alpha1.AgentTask) error {
+	return s.client.Create(ctx, task)
+}
+
+func (s *kubeTaskStore) UpdateStatus(ctx context.Context, task *toolkitv1alpha1.AgentTask,
+	mutate func(*toolkitv1alpha1.AgentTask) error) error {
+	base := task.DeepCopy()
+	if err := mutate(task); err != nil {
+		return err
+	}
+	// Lists in the status are replaced as a whole, so guard against a
+	// concurrent write.
+	patch := client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})
+	return s.client.Status().Patch(ctx, task, patch)
+}
+
+func (s *kubeTaskStore) SetAnnotation(ctx context.Context, task *toolkitv1alpha1.AgentTask, key, value string) error {
+	base := task.DeepCopy()
+	if task.Annotations == nil {
+		task.Annotations = map[string]string{}
+	}
+	task.Annotations[key] = value
+	return s.client.Patch(ctx, task, client.MergeFrom(base))
+}
+
+func (s *kubeTaskStore) Delete(ctx context.Context, task *toolkitv1alpha1.AgentTask) error {
+	return s.client.Delete(ctx, task)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+var (
+	// agentTaskResource names AgentTasks in the errors of MemoryTaskStore.
+	agentTaskResource = schema.GroupResource{Group: toolkitv1alpha1.GroupVersion.Group, Resource: "agenttasks"}
+
+	errTaskChanged = errors.New("the task was changed since it was read")
+)
+
+// MemoryTaskStore is a TaskStore that keeps tasks in memory, for tests.
+// Every read is consistent.
+type MemoryTaskStore struct {
+	mu        sync.Mutex
+	namespace string
+	tasks     map[string]*toolkitv1alpha1.AgentTask
+	version   int
+}
+
+// NewMemoryTaskStore returns a MemoryTaskStore for namespace holding
+// copies of tasks.
+func NewMemoryTaskStore(namespace string, tasks ...*toolkitv1alpha1.AgentTask) *MemoryTaskStore {
+	s := &MemoryTaskStore{namespace: namespace, tasks: map[string]*toolkitv1alpha1.AgentTask{}}
+	for _, task := range tasks {
+		if err := s.Create(context.Background(), task.DeepCopy()); err != nil {
+			panic(err)
+		}
+	}
+	return s
+}
+
+// Get returns the task with the given name.
+func (s *MemoryTaskStore) Get(_ context.Context, name string, _ Consistency) (*toolkitv1alpha1.AgentTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, ok := s.tasks[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(agentTaskResource, name)
+	}
+	return task.DeepCopy(), nil
+}
+
+// List returns the tasks whose labels match selector, ordered by name.
+func (s *MemoryTaskStore) List(_ context.Context, selector labels.Selector, _ Consistency) ([]toolkitv1alpha1.AgentTask, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := []toolkitv1alpha1.AgentTask{}
+	for _, task := range s.tasks {
+		if selector == nil || selector.Matches(labels.Set(task.Labels)) {
+			tasks = append(tasks, *task.DeepCopy())
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name < tasks[j].Name })
+	return tasks, nil
+}
+
+// Create stores a new task, naming it from its GenerateName if it has no
+// name.
+func (s *MemoryTaskStore) Create(_ context.Context, task *toolkitv1alpha1.AgentTask) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if task.Name == "" && task.GenerateName != "" {
+		task.Name = task.GenerateName + rand.String(5)
+	}
+	if _, ok := s.tasks[task.Name]; ok {
+		return apierrors.NewAlreadyExists(agentTaskResource, task.Name)
+	}
+	task.Namespace = s.namespace
+	if task.CreationTimestamp.IsZero() {
+		task.CreationTimestamp = metav1.NewTime(time.Now().Truncate(time.Second))
+	}
+	s.store(task)
+	return nil
+}
+
+// UpdateStatus calls mutate on task and stores its status, unless the
+// stored task changed since task was read. mutate runs without s.mu held,
+// so it may read the store; a write it races with surfaces as a conflict.
+func (s *MemoryTaskStore) UpdateStatus(_ context.Context, task *toolkitv1alpha1.AgentTask,
+	mutate func(*toolkitv1alpha1.AgentTask) error) error {
+	readVersion := task.ResourceVersion
+	if err := mutate(task); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.tasks[task.Name]
+	if !ok {
+		return apierrors.NewNotFound(agentTaskResource, task.Name)
+	}
+	if stored.ResourceVersion != readVersion {
+		return apierrors.NewConflict(agentTaskResource, task.Name, errTaskChanged)
+	}
+	updated := stored.DeepCopy()
+	task.Status.DeepCopyInto(&updated.Status)
+	s.store(updated)
+	task.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// SetAnnotation sets an annotation of task.
+func (s *MemoryTaskStore) SetAnnotation(_ context.Context, task *toolkitv1alpha1.AgentTask, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.tasks[task.Name]
+	if !ok {
+		return apierrors.NewNotFound(agentTaskResource, task.Name)
+	}
+	updated := stored.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[key] = value
+	s.store(updated)
+	updated.DeepCopyInto(task)
+	return nil
+}
+
+// Delete removes a task.
+func (s *MemoryTaskStore) Delete(_ context.Context, task *toolkitv1alpha1.AgentTask) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[task.Name]; !ok {
+		return apierrors.NewNotFound(agentTaskResource, task.Name)
+	}
+	delete(s.tasks, task.Name)
+	return nil
+}
+
+// store saves a copy of task under a new resource version, which it also
+// sets on task. The caller holds s.mu.
+func (s *MemoryTaskStore) store(task *toolkitv1alpha1.AgentTask) {
+	s.version++
+	task.ResourceVersion = strconv.Itoa(s.version)
+	s.tasks[task.Name] = task.DeepCopy()
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// taskStores returns the TaskStore implementations, each holding tasks.
+func taskStores(tasks ...*toolkitv1alpha1.AgentTask) map[string]TaskStore {
+	builder := fake.NewClientBuilder().WithScheme(testScheme()).WithStatusSubresource(&toolkitv1alpha1.AgentTask{})
+	for _, task := range tasks {
+		builder = builder.WithObjects(task.DeepCopy())
+	}
+	return map[string]TaskStore{
+		"kube":   newKubeTaskStore(builder.Build(), nil, "default"),
+		"memory": NewMemoryTaskStore("default", tasks...),
+	}
+}
+
+func TestTaskStore(t *testing.T) {
+	ctx := context.Background()
+	repoLabel := map[string]string{"shepherd.io/repo": "acme-app"}
+
+	for name, store := range taskStores(newTask("task-a", repoLabel, nil), newTask("task-b", nil, nil)) {
+		t.Run(name, func(t *testing.T) {
+			task, err := store.Get(ctx, "task-a", ReadConsistent)
+			require.NoError(t, err)
+			assert.Equal(t, "default", task.Namespace)
+
+			_, err = store.Get(ctx, "missing", ReadCached)
+			assert.True(t, apierrors.IsNotFound(err), "got %v", err)
+
+			all, err := store.List(ctx, nil, ReadCached)
+			require.NoError(t, err)
+			assert.Len(t, all, 2)
+			matching, err := store.List(ctx, labels.SelectorFromSet(repoLabel), ReadCached)
+			require.NoError(t, err)
+			require.Len(t, matching, 1)
+			assert.Equal(t, "task-a", matching[0].Name)
+
+			created := newTask("task-c", nil, nil)
+			require.NoError(t, store.Create(ctx, created))
+			assert.True(t, apierrors.IsAlreadyExists(store.Create(ctx, newTask("task-c", nil, nil))))
+
+			// A stale copy cannot overwrite a newer status.
+			stale := task.DeepCopy()
+			require.NoError(t, store.UpdateStatus(ctx, task, func(task *toolkitv1alpha1.AgentTask) error {
+				task.Status.TokenIssued = true
+				return nil
+			}))
+			err = store.UpdateStatus(ctx, stale, func(task *toolkitv1alpha1.AgentTask) error {
+				task.Status.Result.PRURL = "https://github.com/acme/app/pull/1"
+				return nil
+			})
+			assert.True(t, apierrors.IsConflict(err), "got %v", err)
+
+			// An error from mutate aborts the update.
+			errAbort := errors.New("abort")
+			err = store.UpdateStatus(ctx, task, func(task *toolkitv1alpha1.AgentTask) error {
+				task.Status.TokenIssued = false
+				return errAbort
+			})
+			assert.ErrorIs(t, err, errAbort)
+
+			got, err := store.Get(ctx, "task-a", ReadConsistent)
+			require.NoError(t, err)
+			assert.True(t, got.Status.TokenIssued)
+			assert.Empty(t, got.Status.Result.PRURL)
+
+			// Annotations are set regardless of concurrent status changes.
+			require.NoError(t, store.SetAnnotation(ctx, stale, CallbackAttemptsAnnotation, "1"))
+			got, err = store.Get(ctx, "task-a", ReadConsistent)
+			require.NoError(t, err)
+			assert.Equal(t, "1", got.Annotations[CallbackAttemptsAnnotation])
+			assert.True(t, got.Status.TokenIssued)
+
+			require.NoError(t, store.Delete(ctx, got))
+			_, err = store.Get(ctx, "task-a", ReadConsistent)
+			assert.True(t, apierrors.IsNotFound(err), "got %v", err)
+		})
+	}
+}
+
+func TestRetryStatusUpdate(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTaskStore("default", newTask("task-a", nil, nil))
+
+	// A write that sneaks in between the read and the update is kept.
+	var raced bool
+	task, err := retryStatusUpdate(ctx, store, "task-a", func(task *toolkitv1alpha1.AgentTask) error {
+		if !raced {
+			raced = true
+			other, err := store.Get(ctx, "task-a", ReadConsistent)
+			require.NoError(t, err)
+			require.NoError(t, store.UpdateStatus(ctx, other, func(other *toolkitv1alpha1.AgentTask) error {
+				other.Status.Notes = append(other.Status.Notes, toolkitv1alpha1.TaskNote{Text: "first"})
+				return nil
+			}))
+		}
+		task.Status.Notes = append(task.Status.Notes, toolkitv1alpha1.TaskNote{Text: "second"})
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, task.Status.Notes, 2)
+	assert.Equal(t, "first", task.Status.Notes[0].Text)
+	assert.Equal(t, "second", task.Status.Notes[1].Text)
+}
+
+func TestMemoryTaskStore_ServesHandlers(t *testing.T) {
+	h := newTestHandler()
+	h.tasks = NewMemoryTaskStore("default", newTask("task-a", nil, nil))
+	router := testRouter(h)
+
+	w := postJSON(t, router, "/api/v1/tasks/task-a/notes", CreateNoteRequest{Text: "retried after infra outage"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	task, err := h.tasks.Get(context.Background(), "task-a", ReadConsistent)
+	require.NoError(t, err)
+	require.Len(t, task.Status.Notes, 1)
+	assert.Equal(t, "retried after infra outage", task.Status.Notes[0].Text)
+
+	w = doGet(t, router, "/api/v1/tasks/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// Message types of GET /api/v1/tasks/watch.
+const (
+	WatchTaskAdded   = "task_added"
+	WatchTaskUpdated = "task_updated"
+	WatchTaskDeleted = "task_deleted"
+	// WatchTasksSynced follows the tasks that existed when the watch began.
+	WatchTasksSynced = "tasks_synced"
+)
+
+// taskFeedBuffer is how many changes a watcher may fall behind before it is
+// dropped.
+const taskFeedBuffer = 256
+
+// taskChange is a change to an AgentTask seen by the status watcher's
+// informer.
+type taskChange struct {
+	Type string // WatchTaskAdded, WatchTaskUpdated or WatchTaskDeleted
+	Task *toolkitv1alpha1.AgentTask
+}
+
+// taskFeed fans out task changes to the task list watchers. A nil
+// *taskFeed drops changes.
+type taskFeed struct {
+	mu          sync.Mutex
+	subscribers map[string]chan taskChange
+}
+
+func newTaskFeed() *taskFeed {
+	return &taskFeed{subscribers: make(map[string]chan taskChange)}
+}
+
+// subscribe returns a channel of changes from now on. The channel is closed
+// if the subscriber falls too far behind.
+func (f *taskFeed) subscribe() (<-chan taskChange, func()) {
+	ch := make(chan taskChange, taskFeedBuffer)
+	id := rand.String(8)
+
+	f.mu.Lock()
+	f.subscribers[id] = ch
+	f.mu.Unlock()
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.subscribers[id]; ok {
+			delete(f.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// publish sends a change to every subscriber, dropping the ones that are
+// not keeping up.
+func (f *taskFeed) publish(typ string, task *toolkitv1alpha1.AgentTask) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, ch := range f.subscribers {
+		select {
+		case ch <- taskChange{Type: typ, Task: task}:
+		default:
+			close(ch)
+			delete(f.subscribers, id)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// taskIndex remembers the latest task of recently used sources, such as
+// an issue URL, so that the active task of a source can be found without
+// listing tasks. It is a least-recently-used cache: entries may be stale
+// or missing, and callers check the task itself.
+type taskIndex struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	order   *list.List // of *taskIndexEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type taskIndexEntry struct {
+	source string
+	task   string
+}
+
+// newTaskIndex returns an index of up to maxEntries sources, or nil if
+// maxEntries is not positive. A nil index remembers nothing.
+func newTaskIndex(maxEntries int) *taskIndex {
+	if maxEntries <= 0 {
+		return nil
+	}
+	return &taskIndex{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+// sourceKey normalizes a source URL, so that URLs differing only in the
+// case of the host or a trailing slash share an entry.
+func sourceKey(sourceURL string) string {
+	u, err := url.Parse(strings.TrimSpace(sourceURL))
+	if err != nil || u.Host == "" {
+		return sourceURL
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.Fragment = ""
+	return u.String()
+}
+
+// get returns the task last recorded for source.
+func (x *taskIndex) get(source string) (string, bool) {
+	if x == nil {
+		return "", false
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	el, ok := x.entries[source]
+	if !ok {
+		return "", false
+	}
+	x.order.MoveToFront(el)
+	return el.Value.(*taskIndexEntry).task, true
+}
+
+// put records task as the latest task of source, evicting the least
+// recently used source when the index is full.
+func (x *taskIndex) put(source, task string) {
+	if x == nil || source == "" {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if el, ok := x.entries[source]; ok {
+		el.Value.(*taskIndexEntry).task = task
+		x.order.MoveToFront(el)
+		return
+	}
+	if x.order.Len() >= x.maxEntries {
+		oldest := x.order.Back()
+		x.order.Remove(oldest)
+		delete(x.entries, oldest.Value.(*taskIndexEntry).source)
+	}
+	x.entries[source] = x.order.PushFront(&taskIndexEntry{source: source, task: task})
+}
+
+// remove forgets source if task is still its latest task.
+func (x *taskIndex) remove(source, task string) {
+	if x == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if el, ok := x.entries[source]; ok && el.Value.(*taskIndexEntry).task == task {
+		x.order.Remove(el)
+		delete(x.entries, source)
+	}
+}
+
+// activeTaskFor returns the newest non-terminal task created for
+// sourceURL, or nil if there is none. Indexed sources cost a single get;
+// others, such as tasks created through another API replica, are looked
+// up in the informer cache and indexed.
+func (h *taskHandler) activeTaskFor(ctx context.Context, sourceURL string) (*toolkitv1alpha1.AgentTask, error) {
+	source := sourceKey(sourceURL)
+	if name, ok := h.taskIndex.get(source); ok {
+		// Read through to the API server: a task just created here may not
+		// be in the informer cache yet.
+		task, err := h.tasks.Get(ctx, name, ReadConsistent)
+		switch {
+		case err == nil && !task.IsTerminal():
+			return task, nil
+		case err != nil && !errors.IsNotFound(err):
+			return nil, fmt.Errorf("getting task %s: %w", name, err)
+		}
+		h.taskIndex.remove(source, name)
+	}
+
+	tasks, err := h.tasks.List(ctx, nil, ReadCached)
+	if err != nil {
+		return nil, fmt.Errorf("listing tasks: %w", err)
+	}
+	var newest *toolkitv1alpha1.AgentTask
+	for i := range tasks {
+		task := &tasks[i]
+		if task.IsTerminal() || sourceKey(task.Spec.Task.SourceURL) != source {
+			continue
+		}
+		if newest == nil || newest.CreationTimestamp.Before(&task.CreationTimestamp) {
+			newest = task
+		}
+	}
+	if newest != nil {
+		h.taskIndex.put(source, newest.Name)
+	}
+	return newest, nil
+}
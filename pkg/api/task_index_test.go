@@ -0,0 +1,168 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func TestTaskIndex(t *testing.T) {
+	x := newTaskIndex(2)
+	x.put("a", "task-a")
+	x.put("b", "task-b")
+
+	got, ok := x.get("a")
+	require.True(t, ok)
+	assert.Equal(t, "task-a", got)
+
+	// b is now the least recently used source.
+	x.put("c", "task-c")
+	_, ok = x.get("b")
+	assert.False(t, ok, "evicted")
+	_, ok = x.get("a")
+	assert.True(t, ok)
+
+	x.put("a", "task-a2")
+	got, _ = x.get("a")
+	assert.Equal(t, "task-a2", got, "a newer task replaces the entry")
+
+	x.remove("a", "task-a")
+	_, ok = x.get("a")
+	assert.True(t, ok, "only removed for the task it holds")
+	x.remove("a", "task-a2")
+	_, ok = x.get("a")
+	assert.False(t, ok)
+
+	var disabled *taskIndex
+	disabled.put("a", "task-a")
+	_, ok = disabled.get("a")
+	assert.False(t, ok)
+	assert.Nil(t, newTaskIndex(0))
+}
+
+func TestSourceKey(t *testing.T) {
+	assert.Equal(t, "https://github.com/Org/Repo/issues/1", sourceKey("https://GitHub.com/Org/Repo/issues/1/"))
+	assert.Equal(t, sourceKey("https://github.com/org/repo/issues/1"),
+		sourceKey("https://github.com/org/repo/issues/1#issuecomment-5"))
+}
+
+func activeTaskPath(sourceURL string) string {
+	return "/api/v1/tasks/active?" + url.Values{"sourceURL": {sourceURL}}.Encode()
+}
+
+func TestGetActiveTask(t *testing.T) {
+	const issueURL = "https://github.com/test-org/test-repo/issues/42"
+
+	t.Run("task created through the API", func(t *testing.T) {
+		h := newTestHandler()
+		h.taskIndex = newTaskIndex(10)
+		router := testRouter(h)
+
+		body := validCreateRequest()
+		body.Task.SourceURL = issueURL
+		created := postCreateTask(t, router, body)
+		require.Equal(t, http.StatusCreated, created.Code)
+		var task TaskResponse
+		require.NoError(t, json.Unmarshal(created.Body.Bytes(), &task))
+
+		name, ok := h.taskIndex.get(sourceKey(issueURL))
+		require.True(t, ok, "indexed on creation")
+		assert.Equal(t, task.ID, name)
+
+		w := doGet(t, router, activeTaskPath(issueURL))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		doc := loadSpec(t)
+		req := httptest.NewRequest(http.MethodGet, activeTaskPath(issueURL), nil)
+		validateResponse(t, doc, req, w)
+
+		var resp TaskResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, task.ID, resp.ID)
+	})
+
+	t.Run("task not in the index", func(t *testing.T) {
+		older := newTask("task-older", nil, nil)
+		older.Spec.Task.SourceURL = issueURL
+		older.CreationTimestamp = metav1.NewTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+		newer := newTask("task-newer", nil, nil)
+		newer.Spec.Task.SourceURL = issueURL + "/"
+		newer.CreationTimestamp = metav1.NewTime(time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC))
+		other := newTask("task-other", nil, nil)
+		other.Spec.Task.SourceURL = "https://github.com/test-org/test-repo/issues/7"
+
+		h := newTestHandler(older, newer, other)
+		h.taskIndex = newTaskIndex(10)
+		router := testRouter(h)
+
+		w := doGet(t, router, activeTaskPath(issueURL))
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp TaskResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "task-newer", resp.ID)
+
+		name, ok := h.taskIndex.get(sourceKey(issueURL))
+		require.True(t, ok, "indexed on lookup")
+		assert.Equal(t, "task-newer", name)
+	})
+
+	t.Run("finished task", func(t *testing.T) {
+		done := newTask("task-done", nil, []metav1.Condition{{
+			Type:   toolkitv1alpha1.ConditionSucceeded,
+			Status: metav1.ConditionTrue,
+			Reason: toolkitv1alpha1.ReasonSucceeded,
+		}})
+		done.Spec.Task.SourceURL = issueURL
+
+		h := newTestHandler(done)
+		h.taskIndex = newTaskIndex(10)
+		h.taskIndex.put(sourceKey(issueURL), "task-done")
+		router := testRouter(h)
+
+		w := doGet(t, router, activeTaskPath(issueURL))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		_, ok := h.taskIndex.get(sourceKey(issueURL))
+		assert.False(t, ok, "stale entry removed")
+	})
+
+	t.Run("deleted task", func(t *testing.T) {
+		h := newTestHandler()
+		h.taskIndex = newTaskIndex(10)
+		h.taskIndex.put(sourceKey(issueURL), "task-gone")
+
+		task, err := h.activeTaskFor(context.Background(), issueURL)
+		require.NoError(t, err)
+		assert.Nil(t, task)
+	})
+
+	t.Run("sourceURL is required", func(t *testing.T) {
+		w := doGet(t, testRouter(newTestHandler()), "/api/v1/tasks/active")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+)
+
+// TimeoutWarningDetail is the callback detail that marks a progress callback
+// as a timeout warning. The details also carry "deadline" (RFC 3339) and
+// "remaining_seconds".
+const TimeoutWarningDetail = "timeout_warning"
+
+// timeoutWarningDue reports whether the operator flagged task as about to
+// time out and the adapter has not been warned yet.
+func timeoutWarningDue(task *toolkitv1alpha1.AgentTask) bool {
+	cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionTimeoutWarning)
+	return cond != nil && cond.Reason == toolkitv1alpha1.ReasonDeadlineApproaching && !task.IsTerminal()
+}
+
+// handleTimeoutWarning sends the progress callback warning the adapter that
+// task is about to time out. The warning is claimed before it is sent, so
+// it goes out at most once across API server replicas; a lost warning only
+// costs the user a heads-up.
+func (w *statusWatcher) handleTimeoutWarning(ctx context.Context, task *toolkitv1alpha1.AgentTask) {
+	if !timeoutWarningDue(task) {
+		return
+	}
+
+	fresh, err := w.tasks.Get(ctx, task.Name, ReadConsistent)
+	if err != nil {
+		w.log.Error(err, "failed to re-fetch task for timeout warning", logging.TaskID, task.Name)
+		return
+	}
+	if !timeoutWarningDue(fresh) {
+		return
+	}
+	cond := apimeta.FindStatusCondition(fresh.Status.Conditions, toolkitv1alpha1.ConditionTimeoutWarning)
+	message := cond.Message
+
+	err = w.tasks.UpdateStatus(ctx, fresh, func(fresh *toolkitv1alpha1.AgentTask) error {
+		apimeta.SetStatusCondition(&fresh.Status.Conditions, metav1.Condition{
+			Type:               toolkitv1alpha1.ConditionTimeoutWarning,
+			Status:             metav1.ConditionTrue,
+			Reason:             toolkitv1alpha1.ReasonWarningSent,
+			Message:            message,
+			ObservedGeneration: fresh.Generation,
+		})
+		return nil
+	})
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			w.log.V(1).Info("conflict claiming timeout warning, someone else handling it", logging.TaskID, task.Name)
+			return
+		}
+		w.log.Error(err, "failed to claim timeout warning", logging.TaskID, task.Name)
+		return
+	}
+
+	payload := timeoutWarningPayload(fresh, message, time.Now())
+	if _, err := w.callback.deliver(ctx, fresh.Spec.Callback, payload); err != nil {
+		w.log.Error(err, "failed to send timeout warning",
+			logging.TaskID, fresh.Name, "callbackURL", fresh.Spec.Callback.URL)
+		return
+	}
+	w.log.Info("sent timeout warning to adapter", logging.TaskID, fresh.Name)
+}
+
+// timeoutWarningPayload returns the progress callback warning that task is
+// about to time out.
+func timeoutWarningPayload(task *toolkitv1alpha1.AgentTask, message string, now time.Time) CallbackPayload {
+	details := map[string]any{TimeoutWarningDetail: true}
+	if deadline, remaining := taskDeadline(task, now); remaining != nil {
+		details["deadline"] = deadline
+		details["remaining_seconds"] = *remaining
+	}
+	return CallbackPayload{
+		TaskID:        task.Name,
+		Event:         EventProgress,
+		Message:       message,
+		Details:       details,
+		CorrelationID: task.Annotations[logging.CorrelationIDAnnotation],
+	}
+}
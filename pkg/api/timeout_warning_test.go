@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+func timeoutWarningTask(callbackURL, reason string) *toolkitv1alpha1.AgentTask {
+	task := watcherTask("task-warn", callbackURL, []metav1.Condition{
+		{
+			Type:   toolkitv1alpha1.ConditionSucceeded,
+			Status: metav1.ConditionUnknown,
+			Reason: toolkitv1alpha1.ReasonRunning,
+		},
+		{
+			Type:    toolkitv1alpha1.ConditionTimeoutWarning,
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: "Task has used 80% of its 30m0s timeout",
+		},
+	}, toolkitv1alpha1.TaskResult{})
+	start := metav1.NewTime(time.Now().Add(-24 * time.Minute))
+	task.Status.StartTime = &start
+	return task
+}
+
+func TestWatcher_TimeoutWarningSendsProgressCallback(t *testing.T) {
+	var calls atomic.Int32
+	var payload CallbackPayload
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	task := timeoutWarningTask(adapter.URL, toolkitv1alpha1.ReasonDeadlineApproaching)
+	w, c := newTestWatcher(task)
+	w.handleTimeoutWarning(context.Background(), task)
+
+	require.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, EventProgress, payload.Event)
+	assert.Equal(t, "Task has used 80% of its 30m0s timeout", payload.Message)
+	assert.Equal(t, true, payload.Details[TimeoutWarningDetail])
+	assert.NotEmpty(t, payload.Details["deadline"])
+	remaining, ok := payload.Details["remaining_seconds"].(float64)
+	require.True(t, ok)
+	assert.InDelta(t, 360, remaining, 5)
+
+	var updated toolkitv1alpha1.AgentTask
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(task), &updated))
+	cond := apimeta.FindStatusCondition(updated.Status.Conditions, toolkitv1alpha1.ConditionTimeoutWarning)
+	require.NotNil(t, cond)
+	assert.Equal(t, toolkitv1alpha1.ReasonWarningSent, cond.Reason)
+
+	// The warning is only sent once.
+	w.handleTimeoutWarning(context.Background(), &updated)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWatcher_TimeoutWarningAlreadySent(t *testing.T) {
+	var calls atomic.Int32
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	task := timeoutWarningTask(adapter.URL, toolkitv1alpha1.ReasonWarningSent)
+	w, _ := newTestWatcher(task)
+	w.handleTimeoutWarning(context.Background(), task)
+
+	assert.Zero(t, calls.Load())
+}
@@ -16,21 +16,50 @@ limitations under the License.
 
 package api
 
+import "encoding/json"
+
 // Callback event types used by runners and adapters.
 const (
 	EventStarted   = "started"
 	EventProgress  = "progress"
 	EventCompleted = "completed"
 	EventFailed    = "failed"
+	// EventCancelled is only sent to adapters, for tasks deleted or
+	// cancelled before they finished.
+	EventCancelled = "cancelled"
+)
+
+// ProgressActionDetail is the detail of a progress event describing the
+// runner's last action, such as the tool it ran. The event's message is
+// the step the runner is at.
+const ProgressActionDetail = "last_action"
+
+// Task source types (TaskRequest.SourceType).
+const (
+	SourceTypeIssue = "issue"
+	// SourceTypeVerification marks a post-merge task that checks a merged PR
+	// instead of producing a new one.
+	SourceTypeVerification = "verification"
+	// SourceTypePullRequest marks a task that pushes to the branch of an
+	// existing PR, named by repo.ref, instead of opening a new one.
+	SourceTypePullRequest = "pr"
 )
 
 // CreateTaskRequest is the JSON body for POST /api/v1/tasks.
 type CreateTaskRequest struct {
-	Repo     RepoRequest       `json:"repo"`
-	Task     TaskRequest       `json:"task"`
-	Callback string            `json:"callbackURL"`
-	Runner   *RunnerConfig     `json:"runner"`
-	Labels   map[string]string `json:"labels,omitempty"`
+	Repo      RepoRequest       `json:"repo"`
+	Task      TaskRequest       `json:"task"`
+	Callback  string            `json:"callbackURL"`
+	Runner    *RunnerConfig     `json:"runner"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Priority  int32             `json:"priority,omitempty"`
+	DependsOn []string          `json:"dependsOn,omitempty"`
+	// CallbackFormat is the format of the task's callbacks: json or
+	// cloudevents. Empty uses the API server's default.
+	CallbackFormat string `json:"callbackFormat,omitempty"`
+	// TemplateRef names a TaskTemplate supplying defaults for runner,
+	// labels, priority and context.
+	TemplateRef string `json:"templateRef,omitempty"`
 }
 
 // RepoRequest specifies the repository for the task.
@@ -53,6 +82,12 @@ type RunnerConfig struct {
 	SandboxTemplateName string `json:"sandboxTemplateName,omitempty"`
 	Timeout             string `json:"timeout,omitempty"`
 	ServiceAccountName  string `json:"serviceAccountName,omitempty"`
+	// Model selects the model the runner's agent works with; empty uses
+	// the runner image's default.
+	Model string `json:"model,omitempty"`
+	// BranchPrefix is prepended to the task's ID to name the branch the
+	// runner pushes to; empty uses "shepherd/".
+	BranchPrefix string `json:"branchPrefix,omitempty"`
 }
 
 // TaskResponse is the JSON response for task endpoints.
@@ -62,9 +97,22 @@ type TaskResponse struct {
 	Repo           RepoRequest       `json:"repo"`
 	Task           TaskRequest       `json:"task"`
 	CallbackURL    string            `json:"callbackURL"`
+	RequestedBy    string            `json:"requestedBy,omitempty"`
+	CorrelationID  string            `json:"correlationID,omitempty"`
+	Priority       int32             `json:"priority,omitempty"`
+	DependsOn      []string          `json:"dependsOn,omitempty"`
 	Status         TaskStatusSummary `json:"status"`
 	CreatedAt      string            `json:"createdAt"`
 	CompletionTime *string           `json:"completionTime,omitempty"`
+	// Durations in whole seconds. Unfinished intervals are measured up to
+	// the time of the response.
+	QueuedSeconds  int64 `json:"queuedSeconds"`
+	RunningSeconds int64 `json:"runningSeconds"`
+	TotalSeconds   int64 `json:"totalSeconds"`
+	// Notes people attached to the task, oldest first.
+	Notes []NoteResponse `json:"notes,omitempty"`
+	// TimeoutExtensions granted to the task, oldest first.
+	TimeoutExtensions []TimeoutExtensionResponse `json:"timeoutExtensions,omitempty"`
 }
 
 // TaskStatusSummary summarizes the task's current status.
@@ -72,8 +120,174 @@ type TaskStatusSummary struct {
 	Phase            string `json:"phase"`
 	Message          string `json:"message"`
 	SandboxClaimName string `json:"sandboxClaimName,omitempty"`
-	PRURL            string `json:"prURL,omitempty"`
-	Error            string `json:"error,omitempty"`
+	// SandboxTemplateName is the template the sandbox was claimed from,
+	// which operator template routes may have changed.
+	SandboxTemplateName string  `json:"sandboxTemplateName,omitempty"`
+	PRURL               string  `json:"prURL,omitempty"`
+	Error               string  `json:"error,omitempty"`
+	CostUSD             float64 `json:"costUSD,omitempty"`
+	// Summary is the runner's description of what it changed and why.
+	Summary string `json:"summary,omitempty"`
+	// Deadline is when the runner's timeout expires, set once the task has
+	// started. RemainingSeconds counts down to it while the task runs.
+	Deadline         string `json:"deadline,omitempty"`
+	RemainingSeconds *int64 `json:"remainingSeconds,omitempty"`
+}
+
+// FleetResponse is the JSON response for GET /api/v1/fleets/{fleetID}.
+type FleetResponse struct {
+	ID          string              `json:"id"`
+	Namespace   string              `json:"namespace"`
+	Description string              `json:"description"`
+	RepoQuery   string              `json:"repoQuery,omitempty"`
+	Status      FleetStatusSummary  `json:"status"`
+	Tasks       []FleetTaskResponse `json:"tasks"`
+	CreatedAt   string              `json:"createdAt"`
+}
+
+// FleetStatusSummary summarizes a fleet's progress across its tasks.
+type FleetStatusSummary struct {
+	Phase     string `json:"phase"`
+	Message   string `json:"message"`
+	Total     int32  `json:"total"`
+	Pending   int32  `json:"pending"`
+	Running   int32  `json:"running"`
+	Succeeded int32  `json:"succeeded"`
+	Failed    int32  `json:"failed"`
+}
+
+// FleetTaskResponse is the result of one task in a fleet.
+type FleetTaskResponse struct {
+	ID      string `json:"id"`
+	RepoURL string `json:"repoURL"`
+	Phase   string `json:"phase"`
+	PRURL   string `json:"prURL,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ArchivedTask is the record of a finished task kept in the archive and
+// returned by GET /api/v1/archive/tasks/{taskID}.
+type ArchivedTask struct {
+	ArchivedAt string          `json:"archivedAt"`
+	Task       TaskResponse    `json:"task"`
+	Events     []TaskEvent     `json:"events"`
+	Object     json.RawMessage `json:"object"` // The AgentTask resource, spec and status included
+}
+
+// ExtendTimeoutRequest is the JSON body for
+// POST /api/v1/tasks/{taskID}/extend.
+type ExtendTimeoutRequest struct {
+	// Duration is how much to extend the timeout by, as a Go duration
+	// string such as "30m".
+	Duration string `json:"duration"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// TimeoutExtensionResponse is a timeout extension granted to a task.
+type TimeoutExtensionResponse struct {
+	Duration  string `json:"duration"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// CreateNoteRequest is the JSON body for POST /api/v1/tasks/{taskID}/notes.
+type CreateNoteRequest struct {
+	Author string `json:"author,omitempty"`
+	Text   string `json:"text"`
+}
+
+// NoteResponse is a note attached to a task.
+type NoteResponse struct {
+	Author    string `json:"author,omitempty"`
+	Text      string `json:"text"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// CreateTaskTemplateRequest is the JSON body for POST /api/v1/task-templates.
+type CreateTaskTemplateRequest struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Runner      *RunnerConfig     `json:"runner"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Context     string            `json:"context,omitempty"`
+	Priority    int32             `json:"priority,omitempty"`
+}
+
+// TaskTemplateResponse is the JSON response for task template endpoints.
+type TaskTemplateResponse struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Description string            `json:"description,omitempty"`
+	Runner      RunnerConfig      `json:"runner"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Context     string            `json:"context,omitempty"`
+	Priority    int32             `json:"priority,omitempty"`
+	CreatedAt   string            `json:"createdAt"`
+}
+
+// CallbackHistoryResponse is the JSON response for GET /api/v1/tasks/{taskID}/callbacks.
+type CallbackHistoryResponse struct {
+	// Status is the reason of the task's Notified condition, such as
+	// CallbackSent or CallbackFailed; empty before the first attempt.
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	// FailedAttempts counts the failed attempts the automatic retries are
+	// based on.
+	FailedAttempts int                       `json:"failedAttempts"`
+	Attempts       []CallbackAttemptResponse `json:"attempts"`
+}
+
+// CallbackAttemptResponse is one attempt to send a task's terminal callback.
+type CallbackAttemptResponse struct {
+	Time       string `json:"time"`
+	Event      string `json:"event"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Replay     bool   `json:"replay,omitempty"`
+}
+
+// MaintenanceResponse is the JSON response of the maintenance endpoints.
+type MaintenanceResponse struct {
+	Enabled bool `json:"enabled"`
+	// Message tells users why Shepherd is under maintenance.
+	Message string `json:"message,omitempty"`
+	// Until is when maintenance is expected to end, in RFC 3339 format.
+	Until string `json:"until,omitempty"`
+}
+
+// SetMaintenanceRequest is the JSON body for PUT /api/v1/admin/maintenance.
+type SetMaintenanceRequest struct {
+	Message string `json:"message,omitempty"`
+	Until   string `json:"until,omitempty"`
+}
+
+// CancelTaskRequest is the optional JSON body for
+// POST /api/v1/tasks/{taskID}/cancel.
+type CancelTaskRequest struct {
+	// Reason is appended to the task's failure message.
+	Reason string `json:"reason,omitempty"`
+}
+
+// CancelTasksRequest is the optional JSON body for POST /api/v1/admin/tasks/cancel.
+type CancelTasksRequest struct {
+	// Reason is appended to the failure message of the cancelled tasks.
+	Reason string `json:"reason,omitempty"`
+}
+
+// BulkTaskResponse is the JSON response of the admin bulk endpoints.
+type BulkTaskResponse struct {
+	DryRun bool `json:"dryRun,omitempty"`
+	// Matched is the number of tasks the request applied to.
+	Matched int `json:"matched"`
+	// Tasks are the IDs of the tasks acted on, or with dryRun that would be.
+	Tasks  []string        `json:"tasks"`
+	Errors []BulkTaskError `json:"errors,omitempty"`
+}
+
+// BulkTaskError is a task a bulk request failed for.
+type BulkTaskError struct {
+	TaskID string `json:"taskID"`
+	Error  string `json:"error"`
 }
 
 // StatusUpdateRequest is the JSON body from the runner for POST /api/v1/tasks/{taskID}/status.
@@ -83,20 +297,81 @@ type StatusUpdateRequest struct {
 	Details map[string]any `json:"details,omitempty"`
 }
 
+// StatusAcceptedResponse is the JSON response for the runner's status and
+// event updates.
+type StatusAcceptedResponse struct {
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
+
 // CallbackPayload is the JSON body sent to adapters.
 type CallbackPayload struct {
 	TaskID  string         `json:"taskID"`
 	Event   string         `json:"event"` // started, progress, completed, failed
 	Message string         `json:"message"`
 	Details map[string]any `json:"details,omitempty"`
+	// CorrelationID is the task's correlation ID, also sent in the
+	// X-Correlation-ID header.
+	CorrelationID string `json:"correlationID,omitempty"`
+	// Links is set when the API server is configured with the URLs of the
+	// dashboard or a log viewer.
+	Links *TaskLinks `json:"links,omitempty"`
 }
 
+// TaskLinks are links to pages about a task outside the API.
+type TaskLinks struct {
+	Dashboard string `json:"dashboard,omitempty"`
+	Logs      string `json:"logs,omitempty"`
+}
+
+// TaskDataVersion is the version of TaskDataResponse served by this API.
+// It is only incremented for changes that older runners cannot handle;
+// new optional fields keep the version.
+//
+// Version 2 added EventPrivacy. A runner that ignored it would send the
+// events it is meant to withhold, so only responses that set it are
+// served as version 2; the rest stay at version 1.
+//
+// Version 3 added Mode. A runner that ignored it would change code when
+// asked for a plan or a review, so only responses that set it are served
+// as version 3.
+const TaskDataVersion = 3
+
 // TaskDataResponse is the JSON response for GET /api/v1/tasks/{taskID}/data.
 type TaskDataResponse struct {
+	Version     int         `json:"version"`
+	TaskID      string      `json:"taskID"`
 	Description string      `json:"description"`
 	Context     string      `json:"context"`
 	SourceURL   string      `json:"sourceURL,omitempty"`
+	SourceType  string      `json:"sourceType,omitempty"`
 	Repo        RepoRequest `json:"repo"`
+	// Timeout is the time budget of the run, as a Go duration string.
+	Timeout string `json:"timeout"`
+	// StartedAt and Deadline are set once the operator has recorded the
+	// start of the run (RFC3339).
+	StartedAt string `json:"startedAt,omitempty"`
+	Deadline  string `json:"deadline,omitempty"`
+	// EventPrivacy limits the detail of the events the runner sends; it
+	// is omitted for EventPrivacyFull.
+	EventPrivacy string `json:"eventPrivacy,omitempty"`
+	// Mode is the task's mode, one of ModePlan or ModeReview; it is
+	// omitted for tasks that change code.
+	Mode string `json:"mode,omitempty"`
+	// Model is the model the agent works with, omitted for the runner's
+	// default.
+	Model string `json:"model,omitempty"`
+	// Branch is the branch a task that opens a PR pushes to.
+	Branch string `json:"branch,omitempty"`
+}
+
+// CallbackSigningKeyResponse is the JSON response for
+// GET /api/v1/callback-signing-key.
+type CallbackSigningKeyResponse struct {
+	Algorithm string `json:"algorithm"`
+	// PublicKey is the key callback signatures verify with, in PKIX PEM
+	// form.
+	PublicKey string `json:"publicKey"`
 }
 
 // TokenResponse is the JSON response for GET /api/v1/tasks/{taskID}/token.
@@ -133,6 +408,11 @@ type TaskEvent struct {
 	Metadata  map[string]any   `json:"metadata,omitempty"`
 }
 
+// EventMetadataCorrelationID is the TaskEvent metadata key holding the
+// correlation ID of the task, which the API server sets on every event it
+// accepts.
+const EventMetadataCorrelationID = "correlationID"
+
 // TaskEventOutput contains the result of a tool execution.
 type TaskEventOutput struct {
 	Success bool   `json:"success"`
@@ -141,7 +421,7 @@ type TaskEventOutput struct {
 
 // WSMessage is a WebSocket message envelope (server → client).
 type WSMessage struct {
-	Type string `json:"type"` // "task_event" or "task_complete"
+	Type string `json:"type"` // "task_event", "events_synced" or "task_complete"; task list watches send Watch* types
 	Data any    `json:"data"`
 }
 
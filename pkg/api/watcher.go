@@ -27,24 +27,33 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	toolscache "k8s.io/client-go/tools/cache"
 	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
 )
 
 const (
 	// callbackPendingTTL is the maximum time a CallbackPending condition can remain
 	// before being considered stale and eligible for retry.
 	callbackPendingTTL = 5 * time.Minute
+
+	// callbackSweepInterval is how often the watcher looks for expired
+	// CallbackPending claims and failed callbacks that are due for a retry.
+	callbackSweepInterval = time.Minute
 )
 
-// statusWatcher watches AgentTask resources for terminal states
-// and sends adapter callbacks. Uses a standalone controller-runtime
-// cache for typed informers without the full manager overhead.
+// statusWatcher watches AgentTask resources for terminal states and
+// timeout warnings and sends adapter callbacks, and passes every change on
+// to the task list watchers. Uses a standalone
+// controller-runtime cache for typed informers without the full manager
+// overhead.
 type statusWatcher struct {
-	client   client.Client
+	tasks    TaskStore
 	callback *callbackSender
 	cache    ctrlcache.Cache
+	archiver *taskArchiver // nil if archiving is not configured
+	feed     *taskFeed
 	log      logr.Logger
 }
 
@@ -56,33 +65,121 @@ func (w *statusWatcher) run(ctx context.Context) error {
 		return fmt.Errorf("getting AgentTask informer: %w", err)
 	}
 
-	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+	registration, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj any) {
 			task, ok := obj.(*toolkitv1alpha1.AgentTask)
 			if !ok {
 				w.log.Error(nil, "unexpected object type in add", "type", fmt.Sprintf("%T", obj))
 				return
 			}
+			w.feed.publish(WatchTaskAdded, task)
 			// Handle cold-start: process terminal tasks that already exist
 			w.handleTerminalTransition(ctx, task)
+			w.handleTimeoutWarning(ctx, task)
 		},
-		UpdateFunc: func(_, newObj any) {
+		UpdateFunc: func(oldObj, newObj any) {
 			newTask, ok := newObj.(*toolkitv1alpha1.AgentTask)
 			if !ok {
 				w.log.Error(nil, "unexpected object type in update", "type", fmt.Sprintf("%T", newObj))
 				return
 			}
+			if oldTask, ok := oldObj.(*toolkitv1alpha1.AgentTask); !ok || oldTask.ResourceVersion != newTask.ResourceVersion {
+				w.feed.publish(WatchTaskUpdated, newTask)
+			}
 			w.handleTerminalTransition(ctx, newTask)
+			w.handleTimeoutWarning(ctx, newTask)
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			task, ok := obj.(*toolkitv1alpha1.AgentTask)
+			if !ok {
+				w.log.Error(nil, "unexpected object type in delete", "type", fmt.Sprintf("%T", obj))
+				return
+			}
+			w.feed.publish(WatchTaskDeleted, task)
 		},
 	})
 	if err != nil {
 		return fmt.Errorf("adding event handler: %w", err)
 	}
 
+	// The initial add events have sent the callbacks missed while the API
+	// was down; catch the ones that failed for a transient reason.
+	if !toolscache.WaitForCacheSync(ctx.Done(), registration.HasSynced) {
+		return nil
+	}
+	w.recoverMissedCallbacks(ctx)
+
 	w.log.Info("status watcher ready")
-	// Block until context is cancelled (cache.Start is called separately in server.go)
-	<-ctx.Done()
-	return nil
+	// Sweep until context is cancelled (cache.Start is called separately in server.go)
+	ticker := time.NewTicker(callbackSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.resyncCallbacks(ctx)
+		}
+	}
+}
+
+// resyncCallbacks re-drives the callbacks of terminal tasks whose
+// CallbackPending claim has expired or whose failed callback is due for a
+// retry. An expired claim is left behind when the API server that made it
+// stopped before finishing the callback. A terminal task gets no further
+// status updates, so nothing else would retry either.
+func (w *statusWatcher) resyncCallbacks(ctx context.Context) {
+	tasks, err := w.tasks.List(ctx, nil, ReadCached)
+	if err != nil {
+		w.log.Error(err, "failed to list tasks for callback retries")
+		return
+	}
+	now := time.Now()
+	for i := range tasks {
+		task := &tasks[i]
+		cond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionNotified)
+		if cond == nil || !task.IsTerminal() || !callbackDue(task, now) {
+			continue
+		}
+		switch cond.Reason {
+		case toolkitv1alpha1.ReasonCallbackPending:
+			w.log.Info("retrying callback with expired claim",
+				logging.TaskID, task.Name, "claimedAt", cond.LastTransitionTime.Time)
+		case toolkitv1alpha1.ReasonCallbackFailed:
+			w.log.Info("retrying failed callback",
+				logging.TaskID, task.Name, "attempts", callbackAttempts(task))
+		}
+		w.handleTerminalTransition(ctx, task)
+	}
+}
+
+// recoverMissedCallbacks re-drives the callbacks of terminal tasks that
+// have no Notified condition, because they finished while no API server was
+// watching. Run at startup; the periodic resync only retries callbacks that
+// were already claimed.
+func (w *statusWatcher) recoverMissedCallbacks(ctx context.Context) {
+	tasks, err := w.tasks.List(ctx, nil, ReadConsistent)
+	if err != nil {
+		w.log.Error(err, "failed to list tasks for missed callbacks")
+		return
+	}
+	var missed int
+	for i := range tasks {
+		task := &tasks[i]
+		if !task.IsTerminal() ||
+			apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionNotified) != nil {
+			continue
+		}
+		w.log.Info("sending missed callback", logging.TaskID, task.Name)
+		w.handleTerminalTransition(ctx, task)
+		missed++
+	}
+	if missed > 0 {
+		w.log.Info("re-drove missed callbacks", "count", missed)
+	}
 }
 
 // handleTerminalTransition checks if a task has reached a terminal state
@@ -94,126 +191,145 @@ func (w *statusWatcher) handleTerminalTransition(ctx context.Context, task *tool
 		return
 	}
 
-	notifiedCond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionNotified)
-	if notifiedCond != nil {
-		// Only skip if callback is definitively complete (CallbackSent or CallbackFailed)
-		if notifiedCond.Reason == toolkitv1alpha1.ReasonCallbackSent ||
-			notifiedCond.Reason == toolkitv1alpha1.ReasonCallbackFailed {
-			return
-		}
-		// If CallbackPending, check if it's stale (older than TTL)
-		if notifiedCond.Reason == toolkitv1alpha1.ReasonCallbackPending {
-			if time.Since(notifiedCond.LastTransitionTime.Time) < callbackPendingTTL {
-				return // Still fresh, don't retry yet
-			}
-			// Stale CallbackPending — fall through to retry
-		}
+	// Skip callbacks that were sent, are being sent, or are not due for a
+	// retry yet
+	if !callbackDue(task, time.Now()) {
+		return
 	}
 
 	// Phase 1: Re-fetch and atomically claim with CallbackPending
-	var fresh toolkitv1alpha1.AgentTask
-	if err := w.client.Get(ctx, client.ObjectKeyFromObject(task), &fresh); err != nil {
-		w.log.Error(err, "failed to re-fetch task for claim", "task", task.Name)
+	fresh, err := w.tasks.Get(ctx, task.Name, ReadConsistent)
+	if err != nil {
+		w.log.Error(err, "failed to re-fetch task for claim", logging.TaskID, task.Name)
 		return
 	}
 
 	// Re-check on fresh copy
-	notifiedCond = apimeta.FindStatusCondition(fresh.Status.Conditions, toolkitv1alpha1.ConditionNotified)
-	if notifiedCond != nil {
-		// Only skip if callback is definitively complete (CallbackSent or CallbackFailed)
-		if notifiedCond.Reason == toolkitv1alpha1.ReasonCallbackSent ||
-			notifiedCond.Reason == toolkitv1alpha1.ReasonCallbackFailed {
-			return
-		}
-		// If CallbackPending, check if it's stale (older than TTL)
-		if notifiedCond.Reason == toolkitv1alpha1.ReasonCallbackPending {
-			if time.Since(notifiedCond.LastTransitionTime.Time) < callbackPendingTTL {
-				return // Still fresh, don't retry yet
-			}
-			// Stale CallbackPending — fall through to retry
-		}
+	if !callbackDue(fresh, time.Now()) {
+		return
 	}
 
-	// Determine event type from Succeeded condition
-	succeededCond := apimeta.FindStatusCondition(fresh.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
-	if succeededCond == nil {
-		w.log.Error(nil, "Succeeded condition not found on terminal task", "task", fresh.Name)
+	payload, ok := terminalCallbackPayload(fresh)
+	if !ok {
+		w.log.Error(nil, "Succeeded condition not found on terminal task", logging.TaskID, fresh.Name)
 		return
 	}
-	event := EventFailed
-	if succeededCond.Status == metav1.ConditionTrue {
-		event = EventCompleted
-	}
+	event := payload.Event
 
-	// Atomically claim by setting Notified=Unknown, Reason=CallbackPending
-	apimeta.SetStatusCondition(&fresh.Status.Conditions, metav1.Condition{
-		Type:               toolkitv1alpha1.ConditionNotified,
-		Status:             metav1.ConditionUnknown,
-		Reason:             toolkitv1alpha1.ReasonCallbackPending,
-		Message:            "Sending callback to adapter",
-		ObservedGeneration: fresh.Generation,
+	// Atomically claim by setting Notified=Unknown, Reason=CallbackPending.
+	// An expired claim or a failed attempt is dropped first so the new
+	// claim starts a new TTL.
+	err = w.tasks.UpdateStatus(ctx, fresh, func(fresh *toolkitv1alpha1.AgentTask) error {
+		apimeta.RemoveStatusCondition(&fresh.Status.Conditions, toolkitv1alpha1.ConditionNotified)
+		apimeta.SetStatusCondition(&fresh.Status.Conditions, metav1.Condition{
+			Type:               toolkitv1alpha1.ConditionNotified,
+			Status:             metav1.ConditionUnknown,
+			Reason:             toolkitv1alpha1.ReasonCallbackPending,
+			Message:            "Sending callback to adapter",
+			ObservedGeneration: fresh.Generation,
+		})
+		return nil
 	})
-
-	if err := w.client.Status().Update(ctx, &fresh); err != nil {
+	if err != nil {
 		if apierrors.IsConflict(err) {
 			// Someone else (handler or another watcher) claimed it first
-			w.log.V(1).Info("conflict claiming task, someone else handling it", "task", task.Name)
+			w.log.V(1).Info("conflict claiming task, someone else handling it", logging.TaskID, task.Name)
 			return
 		}
-		w.log.Error(err, "failed to claim task with CallbackPending", "task", task.Name)
+		w.log.Error(err, "failed to claim task with CallbackPending", logging.TaskID, task.Name)
 		return
 	}
 
 	// Phase 2: Send callback (we now own this notification)
-	payload := CallbackPayload{
-		TaskID:  fresh.Name,
-		Event:   event,
-		Message: succeededCond.Message,
-		Details: map[string]any{},
-	}
-	if fresh.Status.Result.PRURL != "" {
-		payload.Details["pr_url"] = fresh.Status.Result.PRURL
-	}
-	if fresh.Status.Result.Error != "" {
-		payload.Details["error"] = fresh.Status.Result.Error
-	}
+	// The callback joins the trace of the request that created the task.
+	ctx, span := tracing.Tracer().Start(tracing.FromAnnotations(ctx, fresh.Annotations), "send terminal callback")
+	defer span.End()
 
 	callbackURL := fresh.Spec.Callback.URL
-	if err := w.callback.send(ctx, callbackURL, payload); err != nil {
+	code, err := w.callback.deliver(ctx, fresh.Spec.Callback, payload)
+	attempt := newCallbackAttempt(event, time.Now(), code, err, false)
+	if err != nil {
 		w.log.Error(err, "failed to send terminal callback",
-			"task", fresh.Name, "event", event, "callbackURL", callbackURL)
+			logging.TaskID, fresh.Name, "event", event, "callbackURL", callbackURL)
 
-		// Set Notified condition as failed
-		w.setNotifiedCondition(ctx, &fresh, toolkitv1alpha1.ReasonCallbackFailed,
-			fmt.Sprintf("Callback failed: %v", err))
+		// Set Notified condition as failed; the resync retries it once the
+		// backoff for the recorded attempts has passed.
+		message, recordErr := recordCallbackFailure(ctx, w.tasks, fresh, err)
+		if recordErr != nil {
+			w.log.Error(recordErr, "failed to record callback attempt", logging.TaskID, fresh.Name)
+		}
+		w.setNotifiedCondition(ctx, fresh, toolkitv1alpha1.ReasonCallbackFailed, message, attempt)
 		return
 	}
 
 	w.log.Info("sent terminal callback to adapter",
-		"task", fresh.Name, "event", event, "callbackURL", callbackURL)
+		logging.TaskID, fresh.Name, "event", event, "callbackURL", callbackURL)
 
 	// Set Notified condition as sent
-	w.setNotifiedCondition(ctx, &fresh, toolkitv1alpha1.ReasonCallbackSent,
-		fmt.Sprintf("Adapter notified: %s", event))
+	w.setNotifiedCondition(ctx, fresh, toolkitv1alpha1.ReasonCallbackSent,
+		fmt.Sprintf("Adapter notified: %s", event), attempt)
 }
 
-func (w *statusWatcher) setNotifiedCondition(ctx context.Context, task *toolkitv1alpha1.AgentTask, reason, message string) {
+// terminalCallbackPayload returns the callback announcing how task
+// finished, or false if the task has no Succeeded condition.
+func terminalCallbackPayload(task *toolkitv1alpha1.AgentTask) (CallbackPayload, bool) {
+	succeededCond := apimeta.FindStatusCondition(task.Status.Conditions, toolkitv1alpha1.ConditionSucceeded)
+	if succeededCond == nil {
+		return CallbackPayload{}, false
+	}
+	event := EventFailed
+	switch {
+	case succeededCond.Status == metav1.ConditionTrue:
+		event = EventCompleted
+	case succeededCond.Reason == toolkitv1alpha1.ReasonCancelled:
+		event = EventCancelled
+	}
+
+	payload := CallbackPayload{
+		TaskID:        task.Name,
+		Event:         event,
+		Message:       succeededCond.Message,
+		Details:       map[string]any{},
+		CorrelationID: task.Annotations[logging.CorrelationIDAnnotation],
+	}
+	if task.Status.Result.PRURL != "" {
+		payload.Details["pr_url"] = task.Status.Result.PRURL
+	}
+	if task.Status.Result.Error != "" {
+		payload.Details["error"] = task.Status.Result.Error
+	}
+	if task.Status.Result.Summary != "" {
+		payload.Details["summary"] = task.Status.Result.Summary
+	}
+	return payload, true
+}
+
+// setNotifiedCondition sets the Notified condition of task and records the
+// callback attempt that decided it.
+func (w *statusWatcher) setNotifiedCondition(ctx context.Context, task *toolkitv1alpha1.AgentTask, reason, message string,
+	attempt toolkitv1alpha1.CallbackAttempt) {
 	// Re-fetch to avoid conflicts
-	var fresh toolkitv1alpha1.AgentTask
-	if err := w.client.Get(ctx, client.ObjectKeyFromObject(task), &fresh); err != nil {
-		w.log.Error(err, "failed to re-fetch task for Notified condition", "task", task.Name)
+	fresh, err := w.tasks.Get(ctx, task.Name, ReadConsistent)
+	if err != nil {
+		w.log.Error(err, "failed to re-fetch task for Notified condition", logging.TaskID, task.Name)
 		return
 	}
+	if err := w.archiver.archive(ctx, fresh); err != nil {
+		w.log.Error(err, "failed to archive task", logging.TaskID, task.Name)
+	}
 
-	apimeta.SetStatusCondition(&fresh.Status.Conditions, metav1.Condition{
-		Type:               toolkitv1alpha1.ConditionNotified,
-		Status:             metav1.ConditionTrue,
-		Reason:             reason,
-		Message:            message,
-		ObservedGeneration: fresh.Generation,
+	err = w.tasks.UpdateStatus(ctx, fresh, func(fresh *toolkitv1alpha1.AgentTask) error {
+		apimeta.SetStatusCondition(&fresh.Status.Conditions, metav1.Condition{
+			Type:               toolkitv1alpha1.ConditionNotified,
+			Status:             metav1.ConditionTrue,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: fresh.Generation,
+		})
+		recordCallbackAttempt(fresh, attempt)
+		return nil
 	})
-
-	if err := w.client.Status().Update(ctx, &fresh); err != nil {
-		w.log.Error(err, "failed to set Notified condition", "task", task.Name)
+	if err != nil {
+		w.log.Error(err, "failed to set Notified condition", logging.TaskID, task.Name)
 	}
 }
@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,7 +48,7 @@ func newTestWatcher(objs ...client.Object) (*statusWatcher, client.Client) {
 	c := builder.Build()
 
 	w := &statusWatcher{
-		client:   c,
+		tasks:    newKubeTaskStore(c, nil, "default"),
 		callback: newCallbackSender("test-secret"),
 		log:      ctrl.Log.WithName("status-watcher-test"),
 		// cache not needed for direct handleTerminalTransition tests
@@ -146,6 +147,30 @@ func TestWatcher_TerminalFailedTriggersCallback(t *testing.T) {
 	assert.Equal(t, toolkitv1alpha1.ReasonCallbackSent, notified.Reason)
 }
 
+func TestWatcher_CancelledTriggersCancelledCallback(t *testing.T) {
+	var receivedPayload CallbackPayload
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&receivedPayload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	task := watcherTask("task-deleted", adapter.URL, []metav1.Condition{
+		{
+			Type:    toolkitv1alpha1.ConditionSucceeded,
+			Status:  metav1.ConditionFalse,
+			Reason:  toolkitv1alpha1.ReasonCancelled,
+			Message: "Task was deleted before it finished",
+		},
+	}, toolkitv1alpha1.TaskResult{})
+
+	w, _ := newTestWatcher(task)
+	w.handleTerminalTransition(context.Background(), task)
+
+	assert.Equal(t, "cancelled", receivedPayload.Event)
+	assert.Equal(t, "Task was deleted before it finished", receivedPayload.Message)
+}
+
 func TestWatcher_NonTerminalDoesNotTriggerCallback(t *testing.T) {
 	var callbackCount atomic.Int32
 	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -241,6 +266,97 @@ func TestWatcher_CallbackPendingSkipsCallback(t *testing.T) {
 	assert.Equal(t, int32(0), callbackCount.Load(), "no callback for task with CallbackPending")
 }
 
+func TestWatcher_ResyncRetriesExpiredCallbackPending(t *testing.T) {
+	var callbacks []CallbackPayload
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload CallbackPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		callbacks = append(callbacks, payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	pending := func(name string, claimedAt time.Time) *toolkitv1alpha1.AgentTask {
+		return watcherTask(name, adapter.URL, []metav1.Condition{
+			{
+				Type:   toolkitv1alpha1.ConditionSucceeded,
+				Status: metav1.ConditionTrue,
+				Reason: toolkitv1alpha1.ReasonSucceeded,
+			},
+			{
+				Type:               toolkitv1alpha1.ConditionNotified,
+				Status:             metav1.ConditionUnknown,
+				Reason:             toolkitv1alpha1.ReasonCallbackPending,
+				LastTransitionTime: metav1.NewTime(claimedAt),
+			},
+		}, toolkitv1alpha1.TaskResult{})
+	}
+	expired := pending("task-expired", time.Now().Add(-2*callbackPendingTTL))
+	fresh := pending("task-fresh", time.Now())
+
+	w, c := newTestWatcher(expired, fresh)
+	w.resyncCallbacks(context.Background())
+
+	require.Len(t, callbacks, 1, "only the expired claim is retried")
+	assert.Equal(t, "task-expired", callbacks[0].TaskID)
+	assert.Equal(t, EventCompleted, callbacks[0].Event)
+
+	var updated toolkitv1alpha1.AgentTask
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(expired), &updated))
+	cond := apimeta.FindStatusCondition(updated.Status.Conditions, toolkitv1alpha1.ConditionNotified)
+	require.NotNil(t, cond)
+	assert.Equal(t, toolkitv1alpha1.ReasonCallbackSent, cond.Reason)
+
+	// A second resync finds nothing left to retry.
+	w.resyncCallbacks(context.Background())
+	assert.Len(t, callbacks, 1)
+}
+
+func TestWatcher_RecoverMissedCallbacks(t *testing.T) {
+	var callbacks []CallbackPayload
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload CallbackPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		callbacks = append(callbacks, payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	failed := metav1.Condition{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionFalse,
+		Reason: toolkitv1alpha1.ReasonFailed,
+	}
+	missed := watcherTask("task-missed", adapter.URL, []metav1.Condition{failed}, toolkitv1alpha1.TaskResult{})
+	notified := watcherTask("task-notified", adapter.URL, []metav1.Condition{failed, {
+		Type:   toolkitv1alpha1.ConditionNotified,
+		Status: metav1.ConditionTrue,
+		Reason: toolkitv1alpha1.ReasonCallbackSent,
+	}}, toolkitv1alpha1.TaskResult{})
+	running := watcherTask("task-running", adapter.URL, []metav1.Condition{{
+		Type:   toolkitv1alpha1.ConditionSucceeded,
+		Status: metav1.ConditionUnknown,
+		Reason: toolkitv1alpha1.ReasonRunning,
+	}}, toolkitv1alpha1.TaskResult{})
+
+	w, c := newTestWatcher(missed, notified, running)
+	w.recoverMissedCallbacks(context.Background())
+
+	require.Len(t, callbacks, 1, "only the terminal task without a Notified condition is recovered")
+	assert.Equal(t, "task-missed", callbacks[0].TaskID)
+	assert.Equal(t, EventFailed, callbacks[0].Event)
+
+	var updated toolkitv1alpha1.AgentTask
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(missed), &updated))
+	cond := apimeta.FindStatusCondition(updated.Status.Conditions, toolkitv1alpha1.ConditionNotified)
+	require.NotNil(t, cond)
+	assert.Equal(t, toolkitv1alpha1.ReasonCallbackSent, cond.Reason)
+
+	// Recovering again finds nothing left to send.
+	w.recoverMissedCallbacks(context.Background())
+	assert.Len(t, callbacks, 1)
+}
+
 func TestWatcher_CallbackFailureSetsCallbackFailedCondition(t *testing.T) {
 	// Adapter that always returns 500
 	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -269,7 +385,86 @@ func TestWatcher_CallbackFailureSetsCallbackFailedCondition(t *testing.T) {
 	require.NotNil(t, notified)
 	assert.Equal(t, metav1.ConditionTrue, notified.Status)
 	assert.Equal(t, toolkitv1alpha1.ReasonCallbackFailed, notified.Reason)
-	assert.Contains(t, notified.Message, "Callback failed")
+	assert.Contains(t, notified.Message, "Callback failed (attempt 1 of 8), retrying in 30s")
+	assert.Equal(t, "1", updated.Annotations[CallbackAttemptsAnnotation])
+	require.Len(t, updated.Status.CallbackHistory, 1)
+	assert.Equal(t, int32(http.StatusInternalServerError), updated.Status.CallbackHistory[0].StatusCode)
+	assert.Equal(t, EventCompleted, updated.Status.CallbackHistory[0].Event)
+}
+
+func TestWatcher_ResyncRetriesFailedCallbacks(t *testing.T) {
+	var callbacks []string
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload CallbackPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		callbacks = append(callbacks, payload.TaskID)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer adapter.Close()
+
+	failed := func(name string, attempts int, failedAt time.Time) *toolkitv1alpha1.AgentTask {
+		task := watcherTask(name, adapter.URL, []metav1.Condition{
+			{
+				Type:   toolkitv1alpha1.ConditionSucceeded,
+				Status: metav1.ConditionFalse,
+				Reason: toolkitv1alpha1.ReasonFailed,
+			},
+			{
+				Type:               toolkitv1alpha1.ConditionNotified,
+				Status:             metav1.ConditionTrue,
+				Reason:             toolkitv1alpha1.ReasonCallbackFailed,
+				LastTransitionTime: metav1.NewTime(failedAt),
+			},
+		}, toolkitv1alpha1.TaskResult{})
+		task.Annotations = map[string]string{CallbackAttemptsAnnotation: fmt.Sprint(attempts)}
+		return task
+	}
+	due := failed("task-due", 2, time.Now().Add(-2*time.Minute))
+	backingOff := failed("task-backing-off", 2, time.Now().Add(-10*time.Second))
+	exhausted := failed("task-exhausted", maxCallbackAttempts, time.Now().Add(-24*time.Hour))
+
+	w, c := newTestWatcher(due, backingOff, exhausted)
+	w.resyncCallbacks(context.Background())
+
+	assert.Equal(t, []string{"task-due"}, callbacks)
+	var updated toolkitv1alpha1.AgentTask
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(due), &updated))
+	cond := apimeta.FindStatusCondition(updated.Status.Conditions, toolkitv1alpha1.ConditionNotified)
+	require.NotNil(t, cond)
+	assert.Equal(t, toolkitv1alpha1.ReasonCallbackSent, cond.Reason)
+}
+
+func TestWatcher_FinalCallbackFailure(t *testing.T) {
+	adapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer adapter.Close()
+
+	task := watcherTask("task-last-try", adapter.URL, []metav1.Condition{
+		{
+			Type:   toolkitv1alpha1.ConditionSucceeded,
+			Status: metav1.ConditionTrue,
+			Reason: toolkitv1alpha1.ReasonSucceeded,
+		},
+		{
+			Type:               toolkitv1alpha1.ConditionNotified,
+			Status:             metav1.ConditionTrue,
+			Reason:             toolkitv1alpha1.ReasonCallbackFailed,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+	}, toolkitv1alpha1.TaskResult{})
+	task.Annotations = map[string]string{CallbackAttemptsAnnotation: fmt.Sprint(maxCallbackAttempts - 1)}
+
+	w, c := newTestWatcher(task)
+	w.handleTerminalTransition(context.Background(), task)
+
+	var updated toolkitv1alpha1.AgentTask
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(task), &updated))
+	cond := apimeta.FindStatusCondition(updated.Status.Conditions, toolkitv1alpha1.ConditionNotified)
+	require.NotNil(t, cond)
+	assert.Equal(t, toolkitv1alpha1.ReasonCallbackFailed, cond.Reason)
+	assert.Contains(t, cond.Message, "Callback failed after 8 attempts")
+	assert.False(t, callbackDue(&updated, time.Now().Add(24*time.Hour)), "no retries after the last attempt")
 }
 
 func TestWatcher_PRUrlIncludedInCallbackDetails(t *testing.T) {
@@ -353,7 +548,7 @@ func TestWatcher_SetNotifiedConditionRefetchFailure(t *testing.T) {
 	task.Spec.Callback.URL = adapter.URL
 
 	w := &statusWatcher{
-		client:   c,
+		tasks:    newKubeTaskStore(c, nil, "default"),
 		callback: newCallbackSender("test-secret"),
 		log:      ctrl.Log.WithName("status-watcher-test"),
 	}
@@ -400,7 +595,7 @@ func TestWatcher_ConflictDuringClaimSkipsCallback(t *testing.T) {
 		WithStatusSubresource(&toolkitv1alpha1.AgentTask{}).
 		WithObjects(task).
 		WithInterceptorFuncs(interceptor.Funcs{
-			SubResourceUpdate: func(ctx context.Context, cl client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			SubResourcePatch: func(ctx context.Context, cl client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
 				if updateCount.Add(1) == 1 {
 					// First update (CallbackPending claim) fails with conflict
 					return apierrors.NewConflict(
@@ -410,13 +605,13 @@ func TestWatcher_ConflictDuringClaimSkipsCallback(t *testing.T) {
 					)
 				}
 				// Subsequent updates succeed (shouldn't happen in this test)
-				return cl.SubResource(subResourceName).Update(ctx, obj, opts...)
+				return cl.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
 			},
 		}).
 		Build()
 
 	w := &statusWatcher{
-		client:   c,
+		tasks:    newKubeTaskStore(c, nil, "default"),
 		callback: newCallbackSender("test-secret"),
 		log:      ctrl.Log.WithName("status-watcher-test"),
 	}
@@ -0,0 +1,35 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package archive stores records of finished tasks in object storage, so
+// they outlive the AgentTask resource.
+package archive
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Archiver.Get for a key that was never written.
+var ErrNotFound = errors.New("archived object not found")
+
+// Archiver writes and reads archived objects by key.
+type Archiver interface {
+	// Put stores data under key, replacing an existing object.
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns the object stored under key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client is a Go client for the Shepherd REST API. Its operations
+// are generated from api/openapi.yaml, one method per operation, and send
+// and return the request and response types of pkg/api.
+package client
+
+//go:generate go run ../../hack/clientgen -spec ../../api/openapi.yaml -out zz_generated.client.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
+)
+
+// maxResponseSize bounds responses. Listing every task can be much larger
+// than a single task.
+const maxResponseSize = 32 << 20
+
+// maxErrorMessage bounds the response body quoted by Error when the API did
+// not answer with an api.ErrorResponse.
+const maxErrorMessage = 1024
+
+// Client calls the Shepherd API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	header     http.Header
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for API requests.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) { cl.httpClient = c }
+}
+
+// WithHeader sends a header with every request, such as a correlation ID.
+func WithHeader(key, value string) Option {
+	return func(cl *Client) { cl.header.Set(key, value) }
+}
+
+// New returns a client for the API served at baseURL, such as
+// http://shepherd-api:8080.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Transport: tracing.Transport(nil),
+			Timeout:   30 * time.Second,
+		},
+		header: http.Header{"User-Agent": {"shepherd-client/" + SpecVersion}},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// BaseURL returns the URL of the API the client calls.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Error is returned when the API answers with a status other than the
+// operation's success status.
+type Error struct {
+	StatusCode int
+	Header     http.Header
+	// Response is the decoded error body; empty if the API did not send an
+	// api.ErrorResponse.
+	Response api.ErrorResponse
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *Error) Error() string {
+	msg := e.Response.Error
+	if msg == "" {
+		msg = string(bytes.TrimSpace(e.Body))
+		if len(msg) > maxErrorMessage {
+			msg = msg[:maxErrorMessage]
+		}
+	}
+	if msg == "" {
+		msg = "unknown error"
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, msg)
+}
+
+// StatusCode returns the status code of an *Error in err's chain, or 0 if
+// the request failed before the API answered.
+func StatusCode(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// request is a single API call, built by the generated operations.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   any
+	// status is the success status; any other is returned as *Error.
+	status int
+}
+
+// do sends req and decodes the JSON response into out, if not nil.
+func (c *Client) do(ctx context.Context, req request, out any) error {
+	u := c.baseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+
+	var body io.Reader
+	if req.body != nil {
+		data, err := json.Marshal(req.body)
+		if err != nil {
+			return fmt.Errorf("marshaling request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	for key, values := range c.header {
+		httpReq.Header[key] = values
+	}
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	if req.body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if out != nil {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != req.status {
+		apiErr := &Error{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody}
+		_ = json.Unmarshal(respBody, &apiErr.Response)
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("parsing response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func TestCreateTask(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/tasks", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "corr-1", r.Header.Get("X-Correlation-ID"))
+		assert.Equal(t, "shepherd-client/"+SpecVersion, r.Header.Get("User-Agent"))
+
+		var req api.CreateTaskRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "https://github.com/org/repo", req.Repo.URL)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"task-1","status":{"phase":"Pending"}}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL + "/")
+	task, err := c.CreateTask(context.Background(), &CreateTaskParams{CorrelationID: "corr-1"}, api.CreateTaskRequest{
+		Repo: api.RepoRequest{URL: "https://github.com/org/repo"},
+		Task: api.TaskRequest{Description: "Fix it"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", task.ID)
+	assert.Equal(t, "Pending", task.Status.Phase)
+}
+
+func TestQueryAndPathParameters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/tasks":
+			assert.Equal(t, "sort=createdAt", r.URL.RawQuery, "unset parameters are omitted")
+			_, _ = w.Write([]byte(`[{"id":"a"},{"id":"b"}]`))
+		case "/api/v1/tasks/search":
+			assert.Equal(t, "10", r.URL.Query().Get("limit"))
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			assert.Equal(t, "/api/v1/tasks/a%2Fb/notes", r.URL.RawPath, "path parameters are escaped")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"note-1"}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	tasks, err := c.ListTasks(context.Background(), &ListTasksParams{Sort: "createdAt"})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	_, err = c.SearchTasks(context.Background(), &SearchTasksParams{Limit: 10})
+	require.NoError(t, err)
+
+	_, err = c.AddTaskNote(context.Background(), "a/b", api.CreateNoteRequest{Text: "note"})
+	require.NoError(t, err)
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"error response", `{"error":"task not found","details":"gone"}`, "API error 404: task not found"},
+		{"plain body", "not here\n", "API error 404: not here"},
+		{"empty body", "", "API error 404: unknown error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-Test", "yes")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := New(srv.URL).GetTask(context.Background(), "missing", nil)
+			require.Error(t, err)
+			assert.EqualError(t, err, tt.want)
+			assert.Equal(t, http.StatusNotFound, StatusCode(fmt.Errorf("wrapped: %w", err)))
+
+			var apiErr *Error
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, "yes", apiErr.Header.Get("X-Test"))
+		})
+	}
+}
+
+func TestStatusCode_TransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	err := New(srv.URL).Healthz(context.Background())
+	require.Error(t, err)
+	assert.Zero(t, StatusCode(err))
+}
+
+func TestWithHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "corr-2", r.Header.Get("X-Correlation-ID"))
+		_, _ = w.Write([]byte(`{"status":"accepted"}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithHeader("X-Correlation-ID", "corr-2"))
+	resp, err := c.UpdateTaskStatus(context.Background(), "task-1", api.StatusUpdateRequest{Event: "started"})
+	require.NoError(t, err)
+	assert.Equal(t, "accepted", resp.Status)
+}
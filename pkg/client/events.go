@@ -0,0 +1,130 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/coder/websocket"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+// maxStreamMessageSize bounds a single message of an event stream.
+const maxStreamMessageSize = 1 << 20
+
+// StreamMessage is a message of a task's event stream. Event is set for
+// "task_event" messages and Complete for "task_complete" messages.
+type StreamMessage struct {
+	Type     string
+	Event    *api.TaskEvent
+	Complete *api.TaskCompleteData
+}
+
+// EventStream reads the agent events of a task as the API sends them over
+// the WebSocket of GET /api/v1/tasks/{taskID}/events.
+type EventStream struct {
+	conn *websocket.Conn
+}
+
+// StreamEvents opens the event stream of a task, starting after the event
+// with sequence number after (0 for all buffered events).
+func (c *Client) StreamEvents(ctx context.Context, taskID string, after int64) (*EventStream, error) {
+	u, err := url.Parse(c.baseURL + "/api/v1/tasks/" + url.PathEscape(taskID) + "/events")
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	if after > 0 {
+		u.RawQuery = url.Values{"after": {strconv.FormatInt(after, 10)}}.Encode()
+	}
+
+	conn, resp, err := websocket.Dial(ctx, u.String(), &websocket.DialOptions{
+		HTTPClient: c.httpClient,
+		HTTPHeader: c.header.Clone(),
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+			apiErr := &Error{StatusCode: resp.StatusCode, Header: resp.Header}
+			if resp.Body != nil {
+				apiErr.Body, _ = io.ReadAll(io.LimitReader(resp.Body, maxErrorMessage))
+				_ = json.Unmarshal(apiErr.Body, &apiErr.Response)
+			}
+			return nil, apiErr
+		}
+		return nil, fmt.Errorf("opening event stream: %w", err)
+	}
+	conn.SetReadLimit(maxStreamMessageSize)
+	return &EventStream{conn: conn}, nil
+}
+
+// Next returns the next message. It returns io.EOF once the API closed the
+// stream normally, which it does after the "task_complete" message. Any
+// other error means the stream broke off, for example because the client
+// fell behind, and may be resumed with StreamEvents after the last event
+// received.
+func (s *EventStream) Next(ctx context.Context) (StreamMessage, error) {
+	_, data, err := s.conn.Read(ctx)
+	if err != nil {
+		if websocket.CloseStatus(err) == websocket.StatusNormalClosure {
+			return StreamMessage{}, io.EOF
+		}
+		return StreamMessage{}, err
+	}
+	var raw struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return StreamMessage{}, fmt.Errorf("parsing stream message: %w", err)
+	}
+	msg := StreamMessage{Type: raw.Type}
+	switch raw.Type {
+	case "task_event":
+		msg.Event = new(api.TaskEvent)
+		err = json.Unmarshal(raw.Data, msg.Event)
+	case "task_complete":
+		msg.Complete = new(api.TaskCompleteData)
+		err = json.Unmarshal(raw.Data, msg.Complete)
+	}
+	if err != nil {
+		return StreamMessage{}, fmt.Errorf("parsing %s message: %w", raw.Type, err)
+	}
+	return msg, nil
+}
+
+// Close closes the stream. Closing a stream the API already closed is not
+// an error.
+func (s *EventStream) Close() error {
+	if err := s.conn.CloseNow(); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
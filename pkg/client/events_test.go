@@ -0,0 +1,88 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+)
+
+func TestStreamEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/tasks/task-1/events" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"task not found"}`))
+			return
+		}
+		assert.Equal(t, "2", r.URL.Query().Get("after"))
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		conn, err := websocket.Accept(w, r, nil)
+		require.NoError(t, err)
+		for _, msg := range []api.WSMessage{
+			{Type: "task_event", Data: api.TaskEvent{Sequence: 3, Type: api.EventTypeThinking, Summary: "Reading"}},
+			{Type: api.StreamEventsSynced},
+			{Type: "task_complete", Data: api.TaskCompleteData{TaskID: "task-1", Status: "Succeeded"}},
+		} {
+			data, _ := json.Marshal(msg)
+			require.NoError(t, conn.Write(r.Context(), websocket.MessageText, data))
+		}
+		_ = conn.Close(websocket.StatusNormalClosure, "task complete")
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c := New(srv.URL, WithHeader("Authorization", "Bearer key"))
+	stream, err := c.StreamEvents(ctx, "task-1", 2)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, stream.Close()) }()
+
+	msg, err := stream.Next(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, msg.Event)
+	assert.Equal(t, int64(3), msg.Event.Sequence)
+	assert.Equal(t, "Reading", msg.Event.Summary)
+
+	msg, err = stream.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, StreamMessage{Type: api.StreamEventsSynced}, msg)
+
+	msg, err = stream.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &api.TaskCompleteData{TaskID: "task-1", Status: "Succeeded"}, msg.Complete)
+
+	_, err = stream.Next(ctx)
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = c.StreamEvents(ctx, "missing", 0)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, StatusCode(err))
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "task not found", apiErr.Response.Error)
+}
@@ -0,0 +1,505 @@
+// Code generated by hack/clientgen from api/openapi.yaml. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	"github.com/NissesSenap/shepherd/pkg/readiness"
+)
+
+// SpecVersion is the version of the API spec the client was generated from.
+const SpecVersion = "0.1.0"
+
+// Operations without a JSON or empty success response have no method: streamEvents, watchTasks.
+
+// AddTaskNote calls POST /api/v1/tasks/{taskID}/notes: Attach a note to a task.
+func (c *Client) AddTaskNote(ctx context.Context, taskID string, body api.CreateNoteRequest) (*api.NoteResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/notes", status: http.StatusCreated}
+	req.body = body
+	var out api.NoteResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelTask calls POST /api/v1/tasks/{taskID}/cancel: Cancel an unfinished task.
+func (c *Client) CancelTask(ctx context.Context, taskID string, body *api.CancelTaskRequest) (*api.TaskResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/cancel", status: http.StatusOK}
+	if body != nil {
+		req.body = body
+	}
+	var out api.TaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelTasksParams are the query and header parameters of CancelTasks.
+type CancelTasksParams struct {
+	// Kubernetes label selector of the tasks, e.g. shepherd.io/repo=acme-app.
+	// Use shepherd.io/repo to match every task.
+	Selector string
+	// Only report the tasks the request applies to
+	DryRun bool
+}
+
+// CancelTasks calls POST /api/v1/admin/tasks/cancel: Cancel all unfinished tasks matching a label selector.
+func (c *Client) CancelTasks(ctx context.Context, params *CancelTasksParams, body *api.CancelTasksRequest) (*api.BulkTaskResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/admin/tasks/cancel", status: http.StatusOK}
+	if params != nil {
+		req.query = url.Values{}
+		if params.Selector != "" {
+			req.query.Set("selector", params.Selector)
+		}
+		if params.DryRun {
+			req.query.Set("dryRun", strconv.FormatBool(params.DryRun))
+		}
+	}
+	if body != nil {
+		req.body = body
+	}
+	var out api.BulkTaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateTaskParams are the query and header parameters of CreateTask.
+type CreateTaskParams struct {
+	// Correlation ID to log the task's lines with in every component, e.g. the
+	// ID of the webhook delivery that triggered it. 1-64 letters, digits, '.',
+	// '_' or '-'; generated if omitted.
+	CorrelationID string
+}
+
+// CreateTask calls POST /api/v1/tasks: Create a new agent task.
+func (c *Client) CreateTask(ctx context.Context, params *CreateTaskParams, body api.CreateTaskRequest) (*api.TaskResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/tasks", status: http.StatusCreated}
+	if params != nil {
+		req.header = http.Header{}
+		if params.CorrelationID != "" {
+			req.header.Set("X-Correlation-ID", params.CorrelationID)
+		}
+	}
+	req.body = body
+	var out api.TaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateTaskTemplate calls POST /api/v1/task-templates: Create a reusable task template.
+func (c *Client) CreateTaskTemplate(ctx context.Context, body api.CreateTaskTemplateRequest) (*api.TaskTemplateResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/task-templates", status: http.StatusCreated}
+	req.body = body
+	var out api.TaskTemplateResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTasksParams are the query and header parameters of DeleteTasks.
+type DeleteTasksParams struct {
+	// Kubernetes label selector of the tasks, e.g. shepherd.io/repo=acme-app.
+	// Use shepherd.io/repo to match every task.
+	Selector string
+	// Only delete tasks that finished at least this long ago, e.g. 24h
+	OlderThan string
+	// Only report the tasks the request applies to
+	DryRun bool
+}
+
+// DeleteTasks calls DELETE /api/v1/admin/tasks: Delete finished tasks matching a label selector.
+func (c *Client) DeleteTasks(ctx context.Context, params *DeleteTasksParams) (*api.BulkTaskResponse, error) {
+	req := request{method: http.MethodDelete, path: "/api/v1/admin/tasks", status: http.StatusOK}
+	if params != nil {
+		req.query = url.Values{}
+		if params.Selector != "" {
+			req.query.Set("selector", params.Selector)
+		}
+		if params.OlderThan != "" {
+			req.query.Set("olderThan", params.OlderThan)
+		}
+		if params.DryRun {
+			req.query.Set("dryRun", strconv.FormatBool(params.DryRun))
+		}
+	}
+	var out api.BulkTaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EndMaintenance calls DELETE /api/v1/admin/maintenance: End maintenance.
+func (c *Client) EndMaintenance(ctx context.Context) (*api.MaintenanceResponse, error) {
+	req := request{method: http.MethodDelete, path: "/api/v1/admin/maintenance", status: http.StatusOK}
+	var out api.MaintenanceResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExtendTaskTimeout calls POST /api/v1/tasks/{taskID}/extend: Extend the timeout of a running task.
+func (c *Client) ExtendTaskTimeout(ctx context.Context, taskID string, body api.ExtendTimeoutRequest) (*api.TaskResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/extend", status: http.StatusOK}
+	req.body = body
+	var out api.TaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetActiveTaskParams are the query and header parameters of GetActiveTask.
+type GetActiveTaskParams struct {
+	// Source URL the task was created for
+	SourceURL string
+}
+
+// GetActiveTask calls GET /api/v1/tasks/active: Get the active task of a source.
+func (c *Client) GetActiveTask(ctx context.Context, params *GetActiveTaskParams) (*api.TaskResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/tasks/active", status: http.StatusOK}
+	if params != nil {
+		req.query = url.Values{}
+		if params.SourceURL != "" {
+			req.query.Set("sourceURL", params.SourceURL)
+		}
+	}
+	var out api.TaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetArchivedTask calls GET /api/v1/archive/tasks/{taskID}: Get the archived record of a finished task.
+func (c *Client) GetArchivedTask(ctx context.Context, taskID string) (*api.ArchivedTask, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/archive/tasks/" + url.PathEscape(taskID), status: http.StatusOK}
+	var out api.ArchivedTask
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCallbackSigningKey calls GET /api/v1/callback-signing-key: Get the public key callbacks are signed with.
+func (c *Client) GetCallbackSigningKey(ctx context.Context) (*api.CallbackSigningKeyResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/callback-signing-key", status: http.StatusOK}
+	var out api.CallbackSigningKeyResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetEventSchemas calls GET /api/v1/event-schemas: List the TaskEvent schema versions the API accepts.
+func (c *Client) GetEventSchemas(ctx context.Context) (*api.EventSchemaResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/event-schemas", status: http.StatusOK}
+	var out api.EventSchemaResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFleet calls GET /api/v1/fleets/{fleetID}: Get a task fleet and the results of its tasks.
+func (c *Client) GetFleet(ctx context.Context, fleetID string) (*api.FleetResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/fleets/" + url.PathEscape(fleetID), status: http.StatusOK}
+	var out api.FleetResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMaintenance calls GET /api/v1/maintenance: Get the maintenance state.
+func (c *Client) GetMaintenance(ctx context.Context) (*api.MaintenanceResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/maintenance", status: http.StatusOK}
+	var out api.MaintenanceResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOpenAPISpec calls GET /api/v1/openapi.json: Get the OpenAPI spec of the REST API.
+func (c *Client) GetOpenAPISpec(ctx context.Context) (map[string]any, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/openapi.json", status: http.StatusOK}
+	var out map[string]any
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTaskParams are the query and header parameters of GetTask.
+type GetTaskParams struct {
+	// If "true", read from the Kubernetes API instead of the API server's
+	// informer cache, which may lag a moment behind.
+	Consistent string
+}
+
+// GetTask calls GET /api/v1/tasks/{taskID}: Get a single task.
+func (c *Client) GetTask(ctx context.Context, taskID string, params *GetTaskParams) (*api.TaskResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/tasks/" + url.PathEscape(taskID), status: http.StatusOK}
+	if params != nil {
+		req.query = url.Values{}
+		if params.Consistent != "" {
+			req.query.Set("consistent", params.Consistent)
+		}
+	}
+	var out api.TaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaskCallbacks calls GET /api/v1/tasks/{taskID}/callbacks: Get the delivery history of a task's terminal callback.
+func (c *Client) GetTaskCallbacks(ctx context.Context, taskID string) (*api.CallbackHistoryResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/callbacks", status: http.StatusOK}
+	var out api.CallbackHistoryResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaskData calls GET /api/v1/tasks/{taskID}/data: Get task data for runner.
+func (c *Client) GetTaskData(ctx context.Context, taskID string) (*api.TaskDataResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/data", status: http.StatusOK}
+	var out api.TaskDataResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaskTemplate calls GET /api/v1/task-templates/{templateName}: Get a task template.
+func (c *Client) GetTaskTemplate(ctx context.Context, templateName string) (*api.TaskTemplateResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/task-templates/" + url.PathEscape(templateName), status: http.StatusOK}
+	var out api.TaskTemplateResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTaskToken calls GET /api/v1/tasks/{taskID}/token: Get GitHub installation token scoped to task repo.
+func (c *Client) GetTaskToken(ctx context.Context, taskID string) (*api.TokenResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/token", status: http.StatusOK}
+	var out api.TokenResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Healthz calls GET /healthz: Liveness probe.
+func (c *Client) Healthz(ctx context.Context) error {
+	req := request{method: http.MethodGet, path: "/healthz", status: http.StatusOK}
+	return c.do(ctx, req, nil)
+}
+
+// ListTaskTemplates calls GET /api/v1/task-templates: List task templates.
+func (c *Client) ListTaskTemplates(ctx context.Context) ([]api.TaskTemplateResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/task-templates", status: http.StatusOK}
+	var out []api.TaskTemplateResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListTasksParams are the query and header parameters of ListTasks.
+type ListTasksParams struct {
+	// Filter by shepherd.io/repo label. Accepts the label value (org-repo),
+	// org/repo, the repository URL or SSH remote, or a link to a page of the
+	// repository.
+	Repo string
+	// Filter by shepherd.io/issue label. Accepts the issue number, #number, a
+	// Linear identifier, or a link to the issue or pull request, which also
+	// filters by its repository.
+	Issue string
+	// Filter by shepherd.io/fleet label
+	Fleet string
+	// If "true", only return non-terminal tasks
+	Active string
+	// Only return tasks in the given phases. Accepts a comma-separated list
+	// (e.g. "Failed,TimedOut") and may be repeated. Valid phases: Pending,
+	// Provisioning, Running, Succeeded, Failed, TimedOut, Cancelled.
+	Phase string
+	// If "true", read from the Kubernetes API instead of the API server's
+	// informer cache, which may lag a moment behind.
+	Consistent string
+	// Sort tasks by the given field. Ties are broken by task ID. When sorting by
+	// completionTime, tasks that have not completed are always listed last.
+	// Without this parameter the order is unspecified.
+	Sort string
+	// Sort direction, only used together with sort.
+	Order string
+}
+
+// ListTasks calls GET /api/v1/tasks: List agent tasks.
+func (c *Client) ListTasks(ctx context.Context, params *ListTasksParams) ([]api.TaskResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/tasks", status: http.StatusOK}
+	if params != nil {
+		req.query = url.Values{}
+		if params.Repo != "" {
+			req.query.Set("repo", params.Repo)
+		}
+		if params.Issue != "" {
+			req.query.Set("issue", params.Issue)
+		}
+		if params.Fleet != "" {
+			req.query.Set("fleet", params.Fleet)
+		}
+		if params.Active != "" {
+			req.query.Set("active", params.Active)
+		}
+		if params.Phase != "" {
+			req.query.Set("phase", params.Phase)
+		}
+		if params.Consistent != "" {
+			req.query.Set("consistent", params.Consistent)
+		}
+		if params.Sort != "" {
+			req.query.Set("sort", params.Sort)
+		}
+		if params.Order != "" {
+			req.query.Set("order", params.Order)
+		}
+	}
+	var out []api.TaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PostEventsParams are the query and header parameters of PostEvents.
+type PostEventsParams struct {
+	// TaskEvent schema version of the events; 1 if omitted.
+	ShepherdEventSchema int
+}
+
+// PostEvents calls POST /api/v1/tasks/{taskID}/events: Post agent events for a task (runner → API).
+func (c *Client) PostEvents(ctx context.Context, taskID string, params *PostEventsParams, body api.PostEventRequest) (*api.StatusAcceptedResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/events", status: http.StatusOK}
+	if params != nil {
+		req.header = http.Header{}
+		if params.ShepherdEventSchema != 0 {
+			req.header.Set("X-Shepherd-Event-Schema", strconv.Itoa(params.ShepherdEventSchema))
+		}
+	}
+	req.body = body
+	var out api.StatusAcceptedResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Readyz calls GET /readyz: Readiness probe.
+func (c *Client) Readyz(ctx context.Context) (*readiness.Result, error) {
+	req := request{method: http.MethodGet, path: "/readyz", status: http.StatusOK}
+	var out readiness.Result
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReplayTaskCallback calls POST /api/v1/tasks/{taskID}/callbacks/replay: Send a finished task's terminal callback again.
+func (c *Client) ReplayTaskCallback(ctx context.Context, taskID string) (*api.CallbackAttemptResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/callbacks/replay", status: http.StatusOK}
+	var out api.CallbackAttemptResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RetryTaskParams are the query and header parameters of RetryTask.
+type RetryTaskParams struct {
+	// Correlation ID to log the retry's lines with in every component. 1-64
+	// letters, digits, '.', '_' or '-'; generated if omitted.
+	CorrelationID string
+}
+
+// RetryTask calls POST /api/v1/tasks/{taskID}/retry: Retry a failed, timed out or cancelled task.
+func (c *Client) RetryTask(ctx context.Context, taskID string, params *RetryTaskParams) (*api.TaskResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/retry", status: http.StatusCreated}
+	if params != nil {
+		req.header = http.Header{}
+		if params.CorrelationID != "" {
+			req.header.Set("X-Correlation-ID", params.CorrelationID)
+		}
+	}
+	var out api.TaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchTasksParams are the query and header parameters of SearchTasks.
+type SearchTasksParams struct {
+	// Search terms
+	Q string
+	// Maximum number of results
+	Limit int
+}
+
+// SearchTasks calls GET /api/v1/tasks/search: Search tasks by text.
+func (c *Client) SearchTasks(ctx context.Context, params *SearchTasksParams) ([]api.TaskResponse, error) {
+	req := request{method: http.MethodGet, path: "/api/v1/tasks/search", status: http.StatusOK}
+	if params != nil {
+		req.query = url.Values{}
+		if params.Q != "" {
+			req.query.Set("q", params.Q)
+		}
+		if params.Limit != 0 {
+			req.query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var out []api.TaskResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SetMaintenance calls PUT /api/v1/admin/maintenance: Start maintenance or update its message.
+func (c *Client) SetMaintenance(ctx context.Context, body api.SetMaintenanceRequest) (*api.MaintenanceResponse, error) {
+	req := request{method: http.MethodPut, path: "/api/v1/admin/maintenance", status: http.StatusOK}
+	req.body = body
+	var out api.MaintenanceResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateTaskStatus calls POST /api/v1/tasks/{taskID}/status: Update task status (runner callback).
+func (c *Client) UpdateTaskStatus(ctx context.Context, taskID string, body api.StatusUpdateRequest) (*api.StatusAcceptedResponse, error) {
+	req := request{method: http.MethodPost, path: "/api/v1/tasks/" + url.PathEscape(taskID) + "/status", status: http.StatusOK}
+	req.body = body
+	var out api.StatusAcceptedResponse
+	if err := c.do(ctx, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
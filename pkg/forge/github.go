@@ -0,0 +1,107 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package forge holds what the components that talk to the git forge
+// share: building API clients for github.com or a GitHub Enterprise Server
+// instance, finding the installations of a GitHub App, retrying requests
+// that hit its rate limits, and splitting forge URLs into their path
+// segments.
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	gh "github.com/google/go-github/v75/github"
+)
+
+// enterpriseAPIPath is where GitHub Enterprise Server serves its REST API.
+const enterpriseAPIPath = "/api/v3"
+
+// NewGitHubClient returns a go-github client for the GitHub Enterprise
+// Server instance at baseURL, or for github.com when baseURL is empty.
+// baseURL may be the web URL (https://ghe.example.com) or the API URL
+// (https://ghe.example.com/api/v3). uploadURL defaults to baseURL.
+func NewGitHubClient(httpClient *http.Client, baseURL, uploadURL string) (*gh.Client, error) {
+	client := gh.NewClient(httpClient)
+	if baseURL == "" {
+		return client, nil
+	}
+	if err := ValidateURL(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid GitHub URL: %w", err)
+	}
+	if uploadURL == "" {
+		uploadURL = baseURL
+	} else if err := ValidateURL(uploadURL); err != nil {
+		return nil, fmt.Errorf("invalid GitHub upload URL: %w", err)
+	}
+	return client.WithEnterpriseURLs(baseURL, uploadURL)
+}
+
+// APIURL returns the REST API root of client without a trailing slash, the
+// form ghinstallation expects as its BaseURL.
+func APIURL(client *gh.Client) string {
+	return strings.TrimSuffix(client.BaseURL.String(), "/")
+}
+
+// BasePath returns the path prefix repositories are served under on the
+// GitHub instance at baseURL. It is empty for github.com and for instances
+// served from the root of their host, and "/github" for one reached
+// through https://proxy.example.com/github.
+func BasePath(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	p := strings.TrimSuffix(u.Path, "/")
+	return strings.TrimSuffix(p, enterpriseAPIPath)
+}
+
+// SplitPath returns the path segments of rawURL below basePath, e.g.
+// [org repo issues 42] for https://ghe.example.com/org/repo/issues/42.
+func SplitPath(basePath, rawURL string) ([]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	p := u.Path
+	if basePath != "" {
+		rest, ok := strings.CutPrefix(p, basePath+"/")
+		if !ok {
+			return nil, fmt.Errorf("path is not below %s", basePath)
+		}
+		p = rest
+	}
+	return strings.Split(strings.Trim(p, "/"), "/"), nil
+}
+
+// ValidateURL checks that raw is an absolute http(s) URL, as the GitHub
+// URL flags must be.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("host is empty")
+	}
+	return nil
+}
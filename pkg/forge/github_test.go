@@ -0,0 +1,85 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGitHubClient(t *testing.T) {
+	t.Run("github.com by default", func(t *testing.T) {
+		c, err := NewGitHubClient(nil, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.github.com", APIURL(c))
+		assert.Equal(t, "https://uploads.github.com/", c.UploadURL.String())
+	})
+
+	t.Run("enterprise web URL", func(t *testing.T) {
+		c, err := NewGitHubClient(nil, "https://ghe.example.com", "")
+		require.NoError(t, err)
+		assert.Equal(t, "https://ghe.example.com/api/v3", APIURL(c))
+		assert.Equal(t, "https://ghe.example.com/api/uploads/", c.UploadURL.String())
+	})
+
+	t.Run("enterprise API URL and separate upload host", func(t *testing.T) {
+		c, err := NewGitHubClient(nil, "https://ghe.example.com/api/v3/", "https://uploads.ghe.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "https://ghe.example.com/api/v3", APIURL(c))
+		assert.Equal(t, "https://uploads.ghe.example.com/api/uploads/", c.UploadURL.String())
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		_, err := NewGitHubClient(nil, "ghe.example.com", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid GitHub URL")
+	})
+}
+
+func TestBasePath(t *testing.T) {
+	for baseURL, want := range map[string]string{
+		"":                                 "",
+		"https://ghe.example.com":          "",
+		"https://ghe.example.com/":         "",
+		"https://ghe.example.com/api/v3":   "",
+		"https://proxy.example.com/github": "/github",
+		"https://proxy.example.com/github/api/v3/": "/github",
+	} {
+		assert.Equal(t, want, BasePath(baseURL), baseURL)
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	parts, err := SplitPath("", "https://ghe.example.com/org/repo/issues/42")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org", "repo", "issues", "42"}, parts)
+
+	parts, err = SplitPath("/github", "https://proxy.example.com/github/org/repo.git")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org", "repo.git"}, parts)
+
+	_, err = SplitPath("/github", "https://proxy.example.com/org/repo")
+	require.Error(t, err)
+}
+
+func TestValidateURL(t *testing.T) {
+	require.NoError(t, ValidateURL("https://ghe.example.com"))
+	require.Error(t, ValidateURL("ftp://ghe.example.com"))
+	require.Error(t, ValidateURL("https://"))
+}
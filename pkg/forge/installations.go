@@ -0,0 +1,159 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	gh "github.com/google/go-github/v75/github"
+)
+
+// installationCacheTTL is how long the installation found for a repository
+// is reused. An App is rarely moved between installations, and requests to
+// a repository it no longer has access to fail until the entry expires.
+const installationCacheTTL = time.Hour
+
+// GitHubInstallations finds the installation of a GitHub App on each
+// repository, so one deployment can serve every organization or user the
+// App is installed on instead of a single configured installation.
+type GitHubInstallations struct {
+	apps   *ghinstallation.AppsTransport
+	client *gh.Client // authenticated as the App itself
+	now    func() time.Time
+
+	mu         sync.Mutex
+	ids        map[string]cachedInstallation // by lowercase owner/repo
+	transports map[int64]*ghinstallation.Transport
+}
+
+type cachedInstallation struct {
+	id      int64
+	fetched time.Time
+}
+
+// NewGitHubInstallations looks up installations with apps, whose BaseURL
+// must already point at the GitHub API.
+func NewGitHubInstallations(apps *ghinstallation.AppsTransport) (*GitHubInstallations, error) {
+	client := gh.NewClient(&http.Client{Transport: apps})
+	u, err := url.Parse(strings.TrimSuffix(apps.BaseURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub API URL: %w", err)
+	}
+	client.BaseURL = u
+	return &GitHubInstallations{
+		apps:       apps,
+		client:     client,
+		now:        time.Now,
+		ids:        make(map[string]cachedInstallation),
+		transports: make(map[int64]*ghinstallation.Transport),
+	}, nil
+}
+
+// ID returns the ID of the App's installation on owner/repo.
+func (i *GitHubInstallations) ID(ctx context.Context, owner, repo string) (int64, error) {
+	key := strings.ToLower(owner + "/" + repo)
+	i.mu.Lock()
+	entry, ok := i.ids[key]
+	i.mu.Unlock()
+	if ok && i.now().Sub(entry.fetched) < installationCacheTTL {
+		return entry.id, nil
+	}
+
+	installation, _, err := i.client.Apps.FindRepositoryInstallation(ctx, owner, repo)
+	if err != nil {
+		return 0, fmt.Errorf("finding the GitHub App installation on %s/%s: %w", owner, repo, err)
+	}
+	i.Remember(owner, repo, installation.GetID())
+	return installation.GetID(), nil
+}
+
+// Remember records that the App's installation on owner/repo is id, as
+// named by a webhook the installation sent, saving a lookup.
+func (i *GitHubInstallations) Remember(owner, repo string, id int64) {
+	if owner == "" || repo == "" || id == 0 {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.ids[strings.ToLower(owner+"/"+repo)] = cachedInstallation{id: id, fetched: i.now()}
+}
+
+// Transport returns the transport authenticated as installation id. It is
+// shared, so its token is reused until it expires.
+func (i *GitHubInstallations) Transport(id int64) *ghinstallation.Transport {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	tr, ok := i.transports[id]
+	if !ok {
+		tr = ghinstallation.NewFromAppsTransport(i.apps, id)
+		i.transports[id] = tr
+	}
+	return tr
+}
+
+// CheckCredentials verifies that the App can authenticate, without
+// needing an installation.
+func (i *GitHubInstallations) CheckCredentials(ctx context.Context) error {
+	if _, _, err := i.client.Apps.Get(ctx, ""); err != nil {
+		return fmt.Errorf("authenticating as the GitHub App: %w", err)
+	}
+	return nil
+}
+
+type repositoryKey struct{}
+
+// WithRepository names the repository a request made with ctx is about, for
+// requests such as GraphQL queries whose path names none.
+func WithRepository(ctx context.Context, owner, repo string) context.Context {
+	return context.WithValue(ctx, repositoryKey{}, [2]string{owner, repo})
+}
+
+// RoundTrip authenticates req as the installation on the repository its
+// path names, such as /repos/owner/repo/issues, or else the one its context
+// names with WithRepository.
+func (i *GitHubInstallations) RoundTrip(req *http.Request) (*http.Response, error) {
+	owner, repo, ok := requestRepository(req)
+	if !ok {
+		return nil, fmt.Errorf("%s %s names no repository to find the GitHub App installation of", req.Method, req.URL.Path)
+	}
+	id, err := i.ID(req.Context(), owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return i.Transport(id).RoundTrip(req)
+}
+
+// requestRepository returns the repository req is about.
+func requestRepository(req *http.Request) (owner, repo string, ok bool) {
+	if _, rest, found := strings.Cut(req.URL.Path, "/repos/"); found {
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) >= 2 && parts[0] != "" && parts[1] != "" {
+			return parts[0], parts[1], true
+		}
+	}
+	if r, found := req.Context().Value(repositoryKey{}).([2]string); found {
+		return r[0], r[1], true
+	}
+	return "", "", false
+}
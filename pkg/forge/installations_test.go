@@ -0,0 +1,142 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forge
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInstallations serves the GitHub App endpoints that find and
+// authenticate as installations: org-a is installation 1, org-b is 2.
+type fakeInstallations struct {
+	mu      sync.Mutex
+	lookups map[string]int
+	// auth records the Authorization header of each repository request.
+	auth map[string]string
+}
+
+func (f *fakeInstallations) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := map[string]int{"org-a": 1, "org-b": 2}
+	w.Header().Set("Content-Type", "application/json")
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.URL.Path == "/app":
+		_, _ = w.Write([]byte(`{"id":12345}`))
+	case len(parts) == 4 && parts[0] == "repos" && parts[3] == "installation":
+		f.lookups[parts[1]]++
+		if ids[parts[1]] == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]int{"id": ids[parts[1]]})
+	case len(parts) == 4 && parts[1] == "installations" && parts[3] == "access_tokens":
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"token":      "token-" + parts[2],
+			"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+		})
+	default:
+		f.auth[r.URL.Path] = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func newTestInstallations(t *testing.T) (*GitHubInstallations, *fakeInstallations, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	f := &fakeInstallations{lookups: map[string]int{}, auth: map[string]string{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	apps, err := ghinstallation.NewAppsTransport(http.DefaultTransport, 12345, keyPEM)
+	require.NoError(t, err)
+	apps.BaseURL = srv.URL
+	installations, err := NewGitHubInstallations(apps)
+	require.NoError(t, err)
+	return installations, f, srv.URL
+}
+
+func TestGitHubInstallations_RoundTrip(t *testing.T) {
+	installations, f, url := newTestInstallations(t)
+	client := &http.Client{Transport: installations}
+
+	get := func(ctx context.Context, path string) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+path, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+	ctx := context.Background()
+
+	require.NoError(t, get(ctx, "/repos/org-a/api/issues/1/comments"))
+	require.NoError(t, get(ctx, "/repos/Org-A/api/pulls/2"))
+	require.NoError(t, get(ctx, "/repos/org-b/web/issues/3"))
+	assert.Equal(t, "token token-1", f.auth["/repos/org-a/api/issues/1/comments"])
+	assert.Equal(t, "token token-1", f.auth["/repos/Org-A/api/pulls/2"])
+	assert.Equal(t, "token token-2", f.auth["/repos/org-b/web/issues/3"])
+	assert.Equal(t, map[string]int{"org-a": 1, "org-b": 1}, f.lookups, "installations are cached per repository")
+
+	assert.ErrorContains(t, get(ctx, "/graphql"), "names no repository")
+	require.NoError(t, get(WithRepository(ctx, "org-b", "web"), "/graphql"))
+	assert.Equal(t, "token token-2", f.auth["/graphql"])
+
+	assert.ErrorContains(t, get(ctx, "/repos/org-c/api/issues"), "finding the GitHub App installation on org-c/api")
+}
+
+func TestGitHubInstallations_RememberAndExpire(t *testing.T) {
+	installations, f, _ := newTestInstallations(t)
+	now := time.Now()
+	installations.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	installations.Remember("org-a", "api", 7)
+	id, err := installations.ID(ctx, "org-a", "api")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id, "a remembered installation is not looked up")
+	assert.Empty(t, f.lookups)
+
+	now = now.Add(installationCacheTTL)
+	id, err = installations.ID(ctx, "org-a", "api")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id, "an expired installation is looked up again")
+
+	require.NoError(t, installations.CheckCredentials(ctx))
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forge
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Rate limits recorded by shepherd_github_rate_limited_total.
+const (
+	// LimitPrimary is the hourly request quota of a token.
+	LimitPrimary = "primary"
+	// LimitSecondary is GitHub's limit on bursts of requests, concurrent
+	// requests and content creation such as comments.
+	LimitSecondary = "secondary"
+)
+
+const (
+	// maxRateLimitRetries is how often a rate limited request is retried.
+	maxRateLimitRetries = 3
+	// maxRateLimitWait is the longest wait before a retry. A primary quota
+	// that resets later is not waited for, so the request fails at once.
+	maxRateLimitWait = 5 * time.Minute
+	// secondaryRateLimitWait is the first wait after a secondary rate
+	// limit that does not say how long to wait, as GitHub recommends; it
+	// doubles with every retry.
+	secondaryRateLimitWait = time.Minute
+	// maxRateLimitBody is how much of a 403 response is read to tell a
+	// secondary rate limit from a permission error.
+	maxRateLimitBody = 64 << 10
+)
+
+var (
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "shepherd_github_rate_limited_total",
+		Help: "GitHub API responses rejected by a rate limit, by component and limit (primary or secondary).",
+	}, []string{"component", "limit"})
+
+	rateLimitRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "shepherd_github_rate_limit_retries_total",
+		Help: "GitHub API requests retried after a rate limit, by component.",
+	}, []string{"component"})
+
+	rateLimitWait = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "shepherd_github_rate_limit_wait_seconds_total",
+		Help: "Time spent waiting for GitHub rate limits before retrying, by component.",
+	}, []string{"component"})
+)
+
+// RateLimitCollectors returns the metrics of RateLimitTransport, for the
+// registry of the component that uses it.
+func RateLimitCollectors() []prometheus.Collector {
+	return []prometheus.Collector{rateLimited, rateLimitRetries, rateLimitWait}
+}
+
+// RateLimitTransport retries GitHub API requests rejected by a rate limit.
+// It waits as long as the response's Retry-After header asks, or until a
+// second after its X-RateLimit-Reset, or for a secondary limit without either, a minute doubling
+// with each retry. Waits get up to a fifth of jitter, so replicas throttled
+// together do not retry together. A request is not retried if the wait
+// would exceed maxRateLimitWait or its context's deadline, or if its body
+// cannot be sent again; the rate limited response is then returned.
+type RateLimitTransport struct {
+	next      http.RoundTripper
+	component string
+	log       logr.Logger
+	now       func() time.Time
+	// sleep waits for d unless done is closed first, and reports whether
+	// it waited.
+	sleep func(d time.Duration, done <-chan struct{}) bool
+}
+
+// NewRateLimitTransport returns a RateLimitTransport sending requests
+// through next, or http.DefaultTransport if nil. Its metrics carry
+// component.
+func NewRateLimitTransport(next http.RoundTripper, component string, log logr.Logger) *RateLimitTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RateLimitTransport{next: next, component: component, log: log, now: time.Now, sleep: sleep}
+}
+
+func sleep(d time.Duration, done <-chan struct{}) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		limit, wait := t.rateLimit(resp)
+		if limit == "" {
+			return resp, nil
+		}
+		rateLimited.WithLabelValues(t.component, limit).Inc()
+		if wait == 0 {
+			wait = secondaryRateLimitWait << attempt
+		}
+		wait += rand.N(wait/5 + 1)
+		if attempt == maxRateLimitRetries || wait > maxRateLimitWait || !t.fits(req, wait) ||
+			(req.Body != nil && req.GetBody == nil) {
+			t.log.Info("GitHub rate limit hit, not retrying", "limit", limit, "method", req.Method,
+				"path", req.URL.Path, "retryAfter", wait.Round(time.Second).String(), "attempt", attempt+1)
+			return resp, nil
+		}
+		t.log.Info("GitHub rate limit hit, retrying", "limit", limit, "method", req.Method,
+			"path", req.URL.Path, "wait", wait.Round(time.Second).String(), "attempt", attempt+1)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		if !t.sleep(wait, req.Context().Done()) {
+			return nil, req.Context().Err()
+		}
+		rateLimitRetries.WithLabelValues(t.component).Inc()
+		rateLimitWait.WithLabelValues(t.component).Add(wait.Seconds())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// fits reports whether waiting for d leaves time before the deadline of
+// req's context.
+func (t *RateLimitTransport) fits(req *http.Request, d time.Duration) bool {
+	deadline, ok := req.Context().Deadline()
+	return !ok || t.now().Add(d).Before(deadline)
+}
+
+// rateLimit returns the limit that rejected resp and how long GitHub asks
+// to wait before retrying, or 0 if it does not say. The limit is "" if
+// resp was not rate limited.
+func (t *RateLimitTransport) rateLimit(resp *http.Response) (string, time.Duration) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return "", 0
+	}
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if seconds, err := strconv.Atoi(s); err == nil && seconds >= 0 {
+			return LimitSecondary, max(time.Duration(seconds)*time.Second, time.Second)
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			// The reset is in whole seconds; a second more makes sure
+			// the retry does not arrive just before it.
+			return LimitPrimary, max(time.Unix(reset+1, 0).Sub(t.now()), time.Second)
+		}
+		return LimitPrimary, 0
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return LimitSecondary, 0
+	}
+	// A 403 is a secondary rate limit only if its message says so;
+	// otherwise the token lacks a permission.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRateLimitBody))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err == nil && strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		return LimitSecondary, 0
+	}
+	return "", 0
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forge
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// throttlingServer answers with the responses in order, then 200, and
+// records the bodies of the requests it got.
+type throttlingServer struct {
+	mu        sync.Mutex
+	responses []func(w http.ResponseWriter)
+	bodies    []string
+}
+
+func (s *throttlingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	s.bodies = append(s.bodies, string(body))
+	if len(s.responses) == 0 {
+		_, _ = w.Write([]byte(`{"id":1}`))
+		return
+	}
+	respond := s.responses[0]
+	s.responses = s.responses[1:]
+	respond(w)
+}
+
+func retryAfter(seconds int) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+	}
+}
+
+func primaryLimit(reset time.Time) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"API rate limit exceeded"}`))
+	}
+}
+
+func forbidden(message string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"message":"` + message + `"}`))
+	}
+}
+
+// newTestRateLimitTransport returns a transport whose waits are recorded
+// instead of slept.
+func newTestRateLimitTransport(component string, now time.Time) (*RateLimitTransport, *[]time.Duration) {
+	var waits []time.Duration
+	t := NewRateLimitTransport(nil, component, logr.Discard())
+	t.now = func() time.Time { return now }
+	t.sleep = func(d time.Duration, _ <-chan struct{}) bool {
+		waits = append(waits, d)
+		return true
+	}
+	return t, &waits
+}
+
+func post(t *testing.T, rt http.RoundTripper, ctx context.Context, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(`{"body":"Done"}`))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	// X-RateLimit-Reset has whole seconds.
+	now := time.Now().Truncate(time.Second)
+
+	t.Run("waits for Retry-After and resends the body", func(t *testing.T) {
+		s := &throttlingServer{responses: []func(http.ResponseWriter){retryAfter(30)}}
+		srv := httptest.NewServer(s)
+		defer srv.Close()
+		rt, waits := newTestRateLimitTransport("retry-after", now)
+		limited := testutil.ToFloat64(rateLimited.WithLabelValues("retry-after", LimitSecondary))
+		retries := testutil.ToFloat64(rateLimitRetries.WithLabelValues("retry-after"))
+		waited := testutil.ToFloat64(rateLimitWait.WithLabelValues("retry-after"))
+
+		resp := post(t, rt, context.Background(), srv.URL)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{`{"body":"Done"}`, `{"body":"Done"}`}, s.bodies)
+		require.Len(t, *waits, 1)
+		assert.GreaterOrEqual(t, (*waits)[0], 30*time.Second)
+		assert.LessOrEqual(t, (*waits)[0], 36*time.Second, "at most a fifth of jitter")
+		assert.Equal(t, limited+1, testutil.ToFloat64(rateLimited.WithLabelValues("retry-after", LimitSecondary)))
+		assert.Equal(t, retries+1, testutil.ToFloat64(rateLimitRetries.WithLabelValues("retry-after")))
+		assert.InDelta(t, waited+(*waits)[0].Seconds(), testutil.ToFloat64(rateLimitWait.WithLabelValues("retry-after")), 1e-9)
+	})
+
+	t.Run("backs off on a secondary limit without a wait", func(t *testing.T) {
+		s := &throttlingServer{responses: []func(http.ResponseWriter){
+			forbidden("You have exceeded a secondary rate limit"),
+			func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) },
+		}}
+		srv := httptest.NewServer(s)
+		defer srv.Close()
+		rt, waits := newTestRateLimitTransport("backoff", now)
+
+		resp := post(t, rt, context.Background(), srv.URL)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Len(t, *waits, 2)
+		assert.GreaterOrEqual(t, (*waits)[0], time.Minute)
+		assert.GreaterOrEqual(t, (*waits)[1], 2*time.Minute)
+	})
+
+	t.Run("waits for a primary quota that resets soon", func(t *testing.T) {
+		s := &throttlingServer{responses: []func(http.ResponseWriter){primaryLimit(now.Add(10 * time.Second))}}
+		srv := httptest.NewServer(s)
+		defer srv.Close()
+		rt, waits := newTestRateLimitTransport("primary", now)
+		limited := testutil.ToFloat64(rateLimited.WithLabelValues("primary", LimitPrimary))
+
+		resp := post(t, rt, context.Background(), srv.URL)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Len(t, *waits, 1)
+		assert.GreaterOrEqual(t, (*waits)[0], 11*time.Second, "until a second after the reset")
+		assert.Equal(t, limited+1, testutil.ToFloat64(rateLimited.WithLabelValues("primary", LimitPrimary)))
+	})
+
+	tests := []struct {
+		name      string
+		responses []func(http.ResponseWriter)
+		ctx       func() (context.Context, context.CancelFunc)
+		wantCode  int
+		wantBody  string
+		wantTries int
+	}{
+		{
+			name:      "a permission error",
+			responses: []func(http.ResponseWriter){forbidden("Resource not accessible by integration")},
+			wantCode:  http.StatusForbidden,
+			wantBody:  "Resource not accessible by integration",
+			wantTries: 1,
+		},
+		{
+			name:      "a quota that resets in an hour",
+			responses: []func(http.ResponseWriter){primaryLimit(now.Add(time.Hour))},
+			wantCode:  http.StatusForbidden,
+			wantBody:  "API rate limit exceeded",
+			wantTries: 1,
+		},
+		{
+			name:      "a wait past the deadline",
+			responses: []func(http.ResponseWriter){retryAfter(60)},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), now.Add(30*time.Second))
+			},
+			wantCode:  http.StatusForbidden,
+			wantBody:  "secondary rate limit",
+			wantTries: 1,
+		},
+		{
+			name: "a limit that persists",
+			responses: []func(http.ResponseWriter){
+				retryAfter(1), retryAfter(1), retryAfter(1), retryAfter(1), retryAfter(1),
+			},
+			wantCode:  http.StatusForbidden,
+			wantBody:  "secondary rate limit",
+			wantTries: maxRateLimitRetries + 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run("returns "+tt.name, func(t *testing.T) {
+			s := &throttlingServer{responses: tt.responses}
+			srv := httptest.NewServer(s)
+			defer srv.Close()
+			rt, _ := newTestRateLimitTransport("give-up", now)
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if tt.ctx != nil {
+				ctx, cancel = tt.ctx()
+			}
+			defer cancel()
+
+			resp := post(t, rt, ctx, srv.URL)
+
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Contains(t, string(body), tt.wantBody, "the caller gets GitHub's error")
+			assert.Len(t, s.bodies, tt.wantTries)
+		})
+	}
+}
+
+func TestRateLimitTransport_StopsWaitingWhenCancelled(t *testing.T) {
+	s := &throttlingServer{responses: []func(http.ResponseWriter){retryAfter(30)}}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	rt := NewRateLimitTransport(nil, "cancelled", logr.Discard())
+	ctx, cancel := context.WithCancel(context.Background())
+	rt.sleep = func(time.Duration, <-chan struct{}) bool {
+		cancel()
+		return false
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+}
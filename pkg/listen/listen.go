@@ -0,0 +1,112 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package listen opens the listeners for Shepherd's HTTP servers from a
+// listen address flag. Besides a TCP "host:port", the address may be
+//
+//	unix:/run/shepherd/api.sock  a Unix domain socket at the given path
+//	systemd:                     the only socket passed by systemd
+//	systemd:<name>               the socket with FileDescriptorName=<name>
+package listen
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	unixPrefix    = "unix:"
+	systemdPrefix = "systemd:"
+
+	// systemdFirstFD is the first file descriptor systemd passes (SD_LISTEN_FDS_START).
+	systemdFirstFD = 3
+)
+
+// Listen returns a listener for addr.
+func Listen(addr string) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(addr, unixPrefix):
+		return listenUnix(strings.TrimPrefix(addr, unixPrefix))
+	case strings.HasPrefix(addr, systemdPrefix):
+		return listenSystemd(strings.TrimPrefix(addr, systemdPrefix))
+	default:
+		return net.Listen("tcp", addr)
+	}
+}
+
+// listenUnix listens on a Unix socket at path, replacing a socket left
+// behind by a previous process. The socket file is removed again when the
+// listener is closed.
+func listenUnix(path string) (net.Listener, error) {
+	if path == "" {
+		return nil, errors.New("unix socket path is empty")
+	}
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+	return net.Listen("unix", path)
+}
+
+// listenSystemd returns a socket inherited through systemd socket activation.
+func listenSystemd(name string) (net.Listener, error) {
+	fd, err := systemdFD(name, os.Getpid(), os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), "systemd:"+name)
+	defer func() { _ = f.Close() }()
+	ln, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("using systemd socket %d: %w", fd, err)
+	}
+	return ln, nil
+}
+
+// systemdFD picks the file descriptor for name from the sd_listen_fds(3)
+// environment. An empty name requires exactly one passed socket.
+func systemdFD(name string, pid int, getenv func(string) string) (int, error) {
+	if getenv("LISTEN_PID") != strconv.Itoa(pid) {
+		return 0, errors.New("no sockets passed by systemd (LISTEN_PID not set for this process)")
+	}
+	count, err := strconv.Atoi(getenv("LISTEN_FDS"))
+	if err != nil || count < 1 {
+		return 0, errors.New("no sockets passed by systemd (LISTEN_FDS not set)")
+	}
+
+	if name == "" {
+		if count != 1 {
+			return 0, fmt.Errorf("systemd passed %d sockets, select one with systemd:<name>", count)
+		}
+		return systemdFirstFD, nil
+	}
+	names := strings.Split(getenv("LISTEN_FDNAMES"), ":")
+	for i := 0; i < count && i < len(names); i++ {
+		if names[i] == name {
+			return systemdFirstFD + i, nil
+		}
+	}
+	return 0, fmt.Errorf("no systemd socket named %q", name)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package listen
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen_TCP(t *testing.T) {
+	ln, err := Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+	assert.Equal(t, "tcp", ln.Addr().Network())
+}
+
+func TestListen_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.sock")
+
+	ln, err := Listen("unix:" + path)
+	require.NoError(t, err)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})}
+	go func() { _ = srv.Serve(ln) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		Dial: func(_, _ string) (net.Conn, error) { return net.Dial("unix", path) },
+	}}
+	resp, err := client.Get("http://shepherd/healthz")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+
+	require.NoError(t, srv.Close())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "socket is removed on close")
+}
+
+func TestListen_UnixReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.sock")
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	ln, err := Listen("unix:" + path)
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+}
+
+func TestListen_UnixRefusesRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("keep me"), 0o600))
+
+	_, err := Listen("unix:" + path)
+	require.ErrorContains(t, err, "not a socket")
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, "keep me", string(content))
+}
+
+func TestSystemdFD(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	tests := []struct {
+		name    string
+		sock    string
+		env     map[string]string
+		want    int
+		wantErr string
+	}{
+		{"single socket", "", map[string]string{"LISTEN_PID": "42", "LISTEN_FDS": "1"}, 3, ""},
+		{"named socket", "internal", map[string]string{"LISTEN_PID": "42", "LISTEN_FDS": "2", "LISTEN_FDNAMES": "public:internal"}, 4, ""},
+		{"other process", "", map[string]string{"LISTEN_PID": "7", "LISTEN_FDS": "1"}, 0, "LISTEN_PID"},
+		{"no sockets", "", map[string]string{"LISTEN_PID": "42"}, 0, "LISTEN_FDS"},
+		{"ambiguous", "", map[string]string{"LISTEN_PID": "42", "LISTEN_FDS": "2"}, 0, "select one"},
+		{"unknown name", "metrics", map[string]string{"LISTEN_PID": "42", "LISTEN_FDS": "2", "LISTEN_FDNAMES": "public:internal"}, 0, `no systemd socket named "metrics"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fd, err := systemdFD(tt.sock, 42, env(tt.env))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fd)
+		})
+	}
+}
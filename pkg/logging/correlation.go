@@ -0,0 +1,48 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+// CorrelationIDAnnotation holds the correlation ID of an AgentTask. Every
+// component logs it with the task's lines, so the adapter, API server,
+// operator and runner logs of one task can be joined on it.
+const CorrelationIDAnnotation = "shepherd.io/correlation-id"
+
+// CorrelationIDHeader carries a task's correlation ID on requests between
+// components. Adapters may set it when creating a task to use their own ID,
+// such as the ID of the webhook delivery that triggered it.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+var correlationIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// NewCorrelationID returns a random correlation ID.
+func NewCorrelationID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidCorrelationID reports whether id can be used as a correlation ID:
+// 1 to 64 letters, digits, '.', '_' or '-'.
+func ValidCorrelationID(id string) bool {
+	return correlationIDPattern.MatchString(id)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationID(t *testing.T) {
+	id := NewCorrelationID()
+	assert.Len(t, id, 32)
+	assert.True(t, ValidCorrelationID(id))
+	assert.NotEqual(t, id, NewCorrelationID())
+
+	assert.True(t, ValidCorrelationID("72d3162e-cc78-11e3-81ab-4c9367dc0958"), "GitHub delivery GUID")
+	assert.False(t, ValidCorrelationID(""))
+	assert.False(t, ValidCorrelationID("has space"))
+	assert.False(t, ValidCorrelationID(strings.Repeat("a", 65)))
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import "github.com/go-logr/logr"
+
+// DebugAnnotation turns on debug logging for a single AgentTask when set
+// to "true", regardless of the configured log level.
+const DebugAnnotation = "shepherd.io/debug"
+
+// ForTask returns logger for lines about the task with annotations. The
+// task's correlation ID is added to every line. If annotations enable
+// DebugAnnotation, every V level is enabled and written at info level, so
+// the task's debug lines pass the logger's level filter.
+func ForTask(logger logr.Logger, annotations map[string]string) logr.Logger {
+	if id := annotations[CorrelationIDAnnotation]; id != "" {
+		logger = logger.WithValues(CorrelationID, id)
+	}
+	if annotations[DebugAnnotation] != "true" {
+		return logger
+	}
+	sink := logger.GetSink()
+	if sink == nil {
+		return logger
+	}
+	// Account for the extra frame debugSink adds between logr and the sink.
+	if cd, ok := sink.(logr.CallDepthLogSink); ok {
+		sink = cd.WithCallDepth(1)
+	}
+	return logger.WithSink(debugSink{sink}).WithValues("debug", true)
+}
+
+// debugSink forwards to a LogSink with every verbosity level enabled.
+type debugSink struct {
+	logr.LogSink
+}
+
+func (s debugSink) Enabled(int) bool { return true }
+
+func (s debugSink) Info(_ int, msg string, keysAndValues ...any) {
+	s.LogSink.Info(0, msg, keysAndValues...)
+}
+
+func (s debugSink) Error(err error, msg string, keysAndValues ...any) {
+	s.LogSink.Error(err, msg, keysAndValues...)
+}
+
+func (s debugSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return debugSink{s.LogSink.WithValues(keysAndValues...)}
+}
+
+func (s debugSink) WithName(name string) logr.LogSink {
+	return debugSink{s.LogSink.WithName(name)}
+}
+
+func (s debugSink) WithCallDepth(depth int) logr.LogSink {
+	if cd, ok := s.LogSink.(logr.CallDepthLogSink); ok {
+		return debugSink{cd.WithCallDepth(depth)}
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForTask(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		logged      bool
+	}{
+		{"no annotations", nil, false},
+		{"debug disabled", map[string]string{DebugAnnotation: "false"}, false},
+		{"debug enabled", map[string]string{DebugAnnotation: "true"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New(Options{Format: FormatJSON, Writer: &buf})
+			require.NoError(t, err)
+
+			ForTask(logger.WithValues(TaskID, "task-abc"), tt.annotations).V(2).Info("claim details")
+			if !tt.logged {
+				assert.Empty(t, buf.String())
+				return
+			}
+			line := decodeLine(t, &buf)
+			assert.Equal(t, "claim details", line["msg"])
+			assert.Equal(t, "info", line["level"])
+			assert.Equal(t, true, line["debug"])
+			assert.Equal(t, "task-abc", line[TaskID])
+			assert.Contains(t, line["caller"], "debug_test.go", "caller skips the debug sink")
+		})
+	}
+}
+
+func TestForTask_CorrelationID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(Options{Format: FormatJSON, Writer: &buf})
+	require.NoError(t, err)
+
+	ForTask(logger, map[string]string{CorrelationIDAnnotation: "abc123"}).Info("assigned")
+	line := decodeLine(t, &buf)
+	assert.Equal(t, "abc123", line[CorrelationID])
+}
+
+func TestForTask_Discard(t *testing.T) {
+	logger := ForTask(logr.Discard(), map[string]string{DebugAnnotation: "true"})
+	assert.False(t, logger.V(1).Enabled())
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging builds the logger shared by every Shepherd binary and
+// defines the field names of its log schema. In JSON format each line is an
+// object with the fixed keys ts, level, logger, msg and caller, plus the
+// fields below when they apply, so log pipelines can rely on one schema
+// across the API server, operator, adapters and runner.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-logr/logr"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// Field names shared by all components.
+const (
+	TaskID    = "task_id"    // AgentTask name
+	Repo      = "repo"       // Repository URL of the task
+	Phase     = "phase"      // Task phase (Succeeded condition reason)
+	RequestID = "request_id" // ID of the HTTP request being served
+
+	CorrelationID = "correlation_id" // Correlation ID of the task, see CorrelationIDAnnotation
+)
+
+// Log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options configures New.
+type Options struct {
+	Format string // FormatText or FormatJSON; empty means FormatText
+	Level  int    // 0 = info, 1 = debug, higher is more verbose
+	// Development adds stack traces to warnings and logs text with colors.
+	Development bool
+	// Writer receives the log lines; defaults to os.Stderr.
+	Writer io.Writer
+}
+
+// New returns a logger for opts.
+func New(opts Options) (logr.Logger, error) {
+	var encoder zapcore.Encoder
+	cfg := encoderConfig()
+	switch opts.Format {
+	case FormatJSON:
+		encoder = zapcore.NewJSONEncoder(cfg)
+	case FormatText, "":
+		if opts.Development {
+			cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(cfg)
+	default:
+		return logr.Logger{}, fmt.Errorf("unknown log format %q, must be %s or %s", opts.Format, FormatText, FormatJSON)
+	}
+
+	w := opts.Writer
+	if w == nil {
+		w = os.Stderr
+	}
+	return zap.New(
+		zap.UseDevMode(opts.Development),
+		zap.Encoder(encoder),
+		zap.Level(zapcore.Level(-opts.Level)),
+		zap.WriteTo(w),
+		zap.RawZapOpts(uberzap.AddCaller()),
+	), nil
+}
+
+// encoderConfig fixes the key names and time format of every log line.
+func encoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "ts",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+	}
+}
+
+// Middleware stores base, tagged with the chi request ID, in each request's
+// context. Handlers log through log.FromContext(r.Context()). It must run
+// after middleware.RequestID.
+func Middleware(base logr.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := base
+			if id := middleware.GetReqID(r.Context()); id != "" {
+				logger = logger.WithValues(RequestID, id)
+			}
+			next.ServeHTTP(w, r.WithContext(log.IntoContext(r.Context(), logger)))
+		})
+	}
+}
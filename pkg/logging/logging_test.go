@@ -0,0 +1,97 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line), buf.String())
+	return line
+}
+
+func TestNew_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(Options{Format: FormatJSON, Writer: &buf})
+	require.NoError(t, err)
+
+	logger.WithName("api").Info("task created", TaskID, "task-abc", Repo, "https://github.com/org/repo")
+
+	line := decodeLine(t, &buf)
+	assert.Equal(t, "info", line["level"])
+	assert.Equal(t, "api", line["logger"])
+	assert.Equal(t, "task created", line["msg"])
+	assert.Equal(t, "task-abc", line[TaskID])
+	assert.Equal(t, "https://github.com/org/repo", line[Repo])
+	assert.Contains(t, line, "ts")
+	assert.Contains(t, line, "caller")
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(Options{Format: FormatJSON, Writer: &buf})
+	require.NoError(t, err)
+	logger.V(1).Info("debug line")
+	assert.Empty(t, buf.String())
+
+	logger, err = New(Options{Format: FormatJSON, Level: 1, Writer: &buf})
+	require.NoError(t, err)
+	logger.V(1).Info("debug line")
+	assert.Equal(t, "debug", decodeLine(t, &buf)["level"])
+}
+
+func TestNew_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(Options{Writer: &buf})
+	require.NoError(t, err)
+
+	logger.Info("hello", TaskID, "task-abc")
+	assert.Contains(t, buf.String(), "hello")
+	assert.Contains(t, buf.String(), `"task_id": "task-abc"`)
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	_, err := New(Options{Format: "xml"})
+	assert.ErrorContains(t, err, `unknown log format "xml"`)
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(Options{Format: FormatJSON, Writer: &buf})
+	require.NoError(t, err)
+
+	handler := middleware.RequestID(Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.FromContext(r.Context()).Info("handling")
+	})))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "req-123", decodeLine(t, &buf)[RequestID])
+}
@@ -29,11 +29,15 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
 	"github.com/NissesSenap/shepherd/internal/controller"
+	webhookv1alpha1 "github.com/NissesSenap/shepherd/internal/webhook/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/validate"
 	sandboxv1alpha1 "sigs.k8s.io/agent-sandbox/api/v1alpha1"
 	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
 )
@@ -53,6 +57,58 @@ type Options struct {
 	HealthAddr     string
 	LeaderElection bool
 	APIURL         string // Internal API URL (e.g., http://shepherd-api.shepherd.svc.cluster.local:8081)
+
+	MaxConcurrentTasks            int            // Tasks allowed to hold a SandboxClaim at once; 0 means unlimited
+	MaxConcurrentTasksPerTemplate map[string]int // The same, per sandbox template name
+	QueueOrder                    string         // "priority" or "fifo"
+
+	// TemplateRoutes move tasks to a sandbox template by their labels.
+	TemplateRoutes []controller.TemplateRoute
+
+	TTLAfterFinished time.Duration // Default retention of finished tasks; 0 keeps them
+
+	// TimeoutWarningPercent of a running task's timeout, once used, makes
+	// the adapter get a timeout warning; 0 disables the warning.
+	TimeoutWarningPercent int
+
+	MaxConcurrentReconciles int // Objects of each kind reconciled in parallel
+
+	// Backoff and overall rate limit for retrying failed task reconciles.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	RetryQPS       float64
+	RetryBurst     int
+
+	// Reconciles allowed per second for a single task; 0 means unlimited.
+	TaskReconcileQPS   float64
+	TaskReconcileBurst int
+
+	// Namespace is the namespace the operator runs in.
+	Namespace string
+
+	// Runner images kept pulled on every node by a DaemonSet; none
+	// disables it. See controller.ImagePrePuller.
+	PrePullImages           []string
+	PrePullPauseImage       string
+	PrePullImagePullSecrets []string
+	PrePullNodeSelector     map[string]string
+
+	// Webhooks serves the AgentTask admission webhooks. They need
+	// a serving certificate in WebhookCertDir.
+	Webhooks       bool
+	WebhookPort    int
+	WebhookCertDir string
+	// Validation configures the checks run by the webhook.
+	Validation validate.Options
+
+	// GitHub App credentials used to resolve TaskFleet repo queries.
+	// Optional; without them only fleets with an explicit repo list work.
+	GithubAppID          int64
+	GithubInstallationID int64
+	GithubPrivateKeyPath string
+	// GithubURL points at a GitHub Enterprise Server instance; empty
+	// means github.com.
+	GithubURL string
 }
 
 // Run starts the operator with the given options.
@@ -70,6 +126,13 @@ func Run(opts Options) error {
 		HealthProbeBindAddress: opts.HealthAddr,
 		LeaderElection:         opts.LeaderElection,
 		LeaderElectionID:       "shepherd-operator",
+		Controller: config.Controller{
+			MaxConcurrentReconciles: opts.MaxConcurrentReconciles,
+		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    opts.WebhookPort,
+			CertDir: opts.WebhookCertDir,
+		}),
 	})
 	if err != nil {
 		return fmt.Errorf("creating manager: %w", err)
@@ -81,10 +144,71 @@ func Run(opts Options) error {
 		Recorder:   mgr.GetEventRecorder("shepherd-operator"),
 		APIURL:     opts.APIURL,
 		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+
+		MaxConcurrentTasks:            opts.MaxConcurrentTasks,
+		MaxConcurrentTasksPerTemplate: opts.MaxConcurrentTasksPerTemplate,
+		QueueOrder:                    opts.QueueOrder,
+		TemplateRoutes:                opts.TemplateRoutes,
+		TTLAfterFinished:              opts.TTLAfterFinished,
+		TimeoutWarningPercent:         opts.TimeoutWarningPercent,
+		RateLimit: controller.RateLimitOptions{
+			BaseDelay: opts.RetryBaseDelay,
+			MaxDelay:  opts.RetryMaxDelay,
+			QPS:       opts.RetryQPS,
+			Burst:     opts.RetryBurst,
+			TaskQPS:   opts.TaskReconcileQPS,
+			TaskBurst: opts.TaskReconcileBurst,
+		},
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("setting up controller: %w", err)
 	}
 
+	if err := (&controller.AgentTaskScheduleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorder("shepherd-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("setting up schedule controller: %w", err)
+	}
+
+	fleetReconciler := &controller.TaskFleetReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorder("shepherd-operator"),
+	}
+	if opts.GithubAppID != 0 {
+		searcher, err := newGitHubRepoSearcher(opts.GithubAppID, opts.GithubInstallationID, opts.GithubPrivateKeyPath, opts.GithubURL)
+		if err != nil {
+			return fmt.Errorf("creating GitHub client: %w", err)
+		}
+		fleetReconciler.Repos = searcher
+	} else {
+		log.Info("GitHub App not configured, TaskFleet repo queries are disabled")
+	}
+	if err := fleetReconciler.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("setting up fleet controller: %w", err)
+	}
+
+	if err := mgr.Add(&controller.ImagePrePuller{
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		Namespace:        opts.Namespace,
+		Images:           opts.PrePullImages,
+		PauseImage:       opts.PrePullPauseImage,
+		ImagePullSecrets: opts.PrePullImagePullSecrets,
+		NodeSelector:     opts.PrePullNodeSelector,
+	}); err != nil {
+		return fmt.Errorf("setting up image pre-pull: %w", err)
+	}
+
+	if opts.Webhooks {
+		if err := webhookv1alpha1.SetupAgentTaskWebhookWithManager(mgr, opts.Validation); err != nil {
+			return fmt.Errorf("setting up AgentTask webhook: %w", err)
+		}
+	} else {
+		log.Info("admission webhook disabled, AgentTasks created outside the API are not validated")
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		return fmt.Errorf("setting up healthz: %w", err)
 	}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	gh "github.com/google/go-github/v75/github"
+
+	"github.com/NissesSenap/shepherd/pkg/forge"
+)
+
+// githubRepoSearcher resolves TaskFleet repo queries with the GitHub
+// repository search API.
+type githubRepoSearcher struct {
+	gh *gh.Client
+}
+
+// newGitHubRepoSearcher creates a searcher authenticated as a GitHub App
+// installation, so private repositories the app is installed on are found.
+// baseURL points it at a GitHub Enterprise Server instance.
+func newGitHubRepoSearcher(appID, installationID int64, privateKeyPath, baseURL string) (*githubRepoSearcher, error) {
+	keyData, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading private key: %w", err)
+	}
+
+	transport, err := ghinstallation.New(http.DefaultTransport, appID, installationID, keyData)
+	if err != nil {
+		return nil, fmt.Errorf("creating installation transport: %w", err)
+	}
+	client, err := forge.NewGitHubClient(&http.Client{Transport: transport}, baseURL, "")
+	if err != nil {
+		return nil, err
+	}
+	transport.BaseURL = forge.APIURL(client)
+	return &githubRepoSearcher{gh: client}, nil
+}
+
+// SearchRepos returns the HTML URLs of up to limit repositories matching query.
+func (s *githubRepoSearcher) SearchRepos(ctx context.Context, query string, limit int) ([]string, error) {
+	var urls []string
+	opts := &gh.SearchOptions{ListOptions: gh.ListOptions{PerPage: 100}}
+	for len(urls) < limit {
+		result, resp, err := s.gh.Search.Repositories(ctx, query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("searching repositories: %w", err)
+		}
+		for _, repo := range result.Repositories {
+			if len(urls) == limit {
+				break
+			}
+			urls = append(urls, repo.GetHTMLURL())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return urls, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gh "github.com/google/go-github/v75/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSearcher(t *testing.T, handler http.HandlerFunc) *githubRepoSearcher {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/search/repositories", handler)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := gh.NewClient(nil).WithEnterpriseURLs(srv.URL, srv.URL)
+	require.NoError(t, err)
+	return &githubRepoSearcher{gh: client}
+}
+
+func TestSearchRepos(t *testing.T) {
+	var queries []string
+	s := newTestSearcher(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v3/search/repositories?q=x&page=2>; rel="next"`, r.Host))
+			_, _ = w.Write([]byte(`{"total_count":3,"items":[{"html_url":"https://github.com/acme/a"},{"html_url":"https://github.com/acme/b"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"total_count":3,"items":[{"html_url":"https://github.com/acme/c"}]}`))
+		}
+	})
+
+	urls, err := s.SearchRepos(context.Background(), "org:acme topic:go", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://github.com/acme/a", "https://github.com/acme/b", "https://github.com/acme/c"}, urls)
+	assert.Equal(t, []string{"org:acme topic:go", "org:acme topic:go"}, queries)
+}
+
+func TestSearchRepos_Limit(t *testing.T) {
+	requests := 0
+	s := newTestSearcher(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v3/search/repositories?q=x&page=2>; rel="next"`, r.Host))
+		_, _ = w.Write([]byte(`{"total_count":300,"items":[{"html_url":"https://github.com/acme/a"},{"html_url":"https://github.com/acme/b"}]}`))
+	})
+
+	urls, err := s.SearchRepos(context.Background(), "org:acme", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://github.com/acme/a"}, urls)
+	assert.Equal(t, 1, requests)
+}
+
+func TestSearchRepos_Error(t *testing.T) {
+	s := newTestSearcher(t, func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"message":"Validation Failed"}`, http.StatusUnprocessableEntity)
+	})
+
+	_, err := s.SearchRepos(context.Background(), "org:", 10)
+	assert.ErrorContains(t, err, "searching repositories")
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+	"sigs.k8s.io/yaml"
+)
+
+// celCostLimit bounds the work a single expression may do, so a policy
+// cannot stall task creation.
+const celCostLimit = 1_000_000
+
+// CELPolicy is one policy of a CEL policy file. Expressions see the task as
+// `task` (with the fields of Input, in lower camel case) and the current
+// time as `now`.
+type CELPolicy struct {
+	Name string `json:"name"`
+	// Match limits the policy to tasks it is true for. Empty matches all.
+	Match string `json:"match,omitempty"`
+	// Validate denies the task when it is false.
+	Validate string `json:"validate,omitempty"`
+	// Message is returned when Validate denies the task.
+	Message string `json:"message,omitempty"`
+	// Labels maps label keys to string expressions giving their values.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Priority is an int expression giving the task's priority.
+	Priority string `json:"priority,omitempty"`
+	// SandboxTemplate is a string expression giving the task's sandbox
+	// template.
+	SandboxTemplate string `json:"sandboxTemplate,omitempty"`
+}
+
+// CELConfig is the content of a CEL policy file.
+type CELConfig struct {
+	Policies []CELPolicy `json:"policies"`
+}
+
+type celPolicy struct {
+	name            string
+	message         string
+	match           cel.Program
+	validate        cel.Program
+	labels          map[string]cel.Program
+	priority        cel.Program
+	sandboxTemplate cel.Program
+}
+
+// CEL evaluates compiled CEL policies in the order they were given.
+type CEL struct {
+	policies []celPolicy
+}
+
+var _ Evaluator = (*CEL)(nil)
+
+// LoadCEL reads and compiles a CEL policy file.
+func LoadCEL(path string) (*CEL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg CELConfig
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	c, err := NewCEL(cfg.Policies)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// NewCEL compiles policies. Every expression is type checked, so a policy
+// that could never evaluate is rejected here rather than at task creation.
+func NewCEL(policies []CELPolicy) (*CEL, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("task", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("now", cel.TimestampType),
+		ext.Strings(),
+		ext.Lists(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating CEL environment: %w", err)
+	}
+
+	c := &CEL{}
+	for i, p := range policies {
+		if p.Name == "" {
+			return nil, fmt.Errorf("policy %d: name is required", i+1)
+		}
+		compile := func(field, expr string, want *cel.Type) (cel.Program, error) {
+			if expr == "" {
+				return nil, nil
+			}
+			ast, iss := env.Compile(expr)
+			if iss.Err() != nil {
+				return nil, fmt.Errorf("policy %q: %s: %w", p.Name, field, iss.Err())
+			}
+			if !ast.OutputType().IsExactType(want) && ast.OutputType() != cel.DynType {
+				return nil, fmt.Errorf("policy %q: %s must evaluate to %s, got %s", p.Name, field, want, ast.OutputType())
+			}
+			return env.Program(ast, cel.CostLimit(celCostLimit), cel.InterruptCheckFrequency(100))
+		}
+
+		cp := celPolicy{name: p.Name, message: p.Message}
+		if cp.message == "" {
+			cp.message = fmt.Sprintf("denied by policy %q", p.Name)
+		}
+		if cp.match, err = compile("match", p.Match, cel.BoolType); err != nil {
+			return nil, err
+		}
+		if cp.validate, err = compile("validate", p.Validate, cel.BoolType); err != nil {
+			return nil, err
+		}
+		if cp.priority, err = compile("priority", p.Priority, cel.IntType); err != nil {
+			return nil, err
+		}
+		if cp.sandboxTemplate, err = compile("sandboxTemplate", p.SandboxTemplate, cel.StringType); err != nil {
+			return nil, err
+		}
+		for _, key := range slices.Sorted(maps.Keys(p.Labels)) {
+			prg, err := compile("labels."+key, p.Labels[key], cel.StringType)
+			if err != nil {
+				return nil, err
+			}
+			if cp.labels == nil {
+				cp.labels = map[string]cel.Program{}
+			}
+			cp.labels[key] = prg
+		}
+		c.policies = append(c.policies, cp)
+	}
+	return c, nil
+}
+
+// Evaluate implements Evaluator.
+func (c *CEL) Evaluate(ctx context.Context, in Input) (Decision, error) {
+	in.Labels = maps.Clone(in.Labels)
+	if in.Labels == nil {
+		in.Labels = map[string]string{}
+	}
+	result := Decision{Allowed: true}
+	for _, p := range c.policies {
+		vars := map[string]any{
+			"task": map[string]any{
+				"name":            in.Name,
+				"repo":            in.Repo,
+				"ref":             in.Ref,
+				"description":     in.Description,
+				"requestedBy":     in.RequestedBy,
+				"sourceType":      in.SourceType,
+				"labels":          in.Labels,
+				"sandboxTemplate": in.SandboxTemplate,
+				"priority":        int64(in.Priority),
+			},
+			"now": in.Time,
+		}
+
+		if p.match != nil {
+			matched, err := evalBool(ctx, p.match, vars)
+			if err != nil {
+				return Decision{}, fmt.Errorf("policy %q: match: %w", p.name, err)
+			}
+			if !matched {
+				continue
+			}
+		}
+		if p.validate != nil {
+			ok, err := evalBool(ctx, p.validate, vars)
+			if err != nil {
+				return Decision{}, fmt.Errorf("policy %q: validate: %w", p.name, err)
+			}
+			if !ok {
+				return Decision{Policy: p.name, Message: p.message}, nil
+			}
+		}
+
+		var d Decision
+		for key, prg := range p.labels {
+			v, err := evalString(ctx, prg, vars)
+			if err != nil {
+				return Decision{}, fmt.Errorf("policy %q: labels.%s: %w", p.name, key, err)
+			}
+			if d.Labels == nil {
+				d.Labels = map[string]string{}
+			}
+			d.Labels[key] = v
+		}
+		if p.priority != nil {
+			out, _, err := p.priority.ContextEval(ctx, vars)
+			if err != nil {
+				return Decision{}, fmt.Errorf("policy %q: priority: %w", p.name, err)
+			}
+			n, ok := out.Value().(int64)
+			if !ok || n < 0 || n > math.MaxInt32 {
+				return Decision{}, fmt.Errorf("policy %q: priority: got %v, want a non-negative int", p.name, out)
+			}
+			priority := int32(n)
+			d.Priority = &priority
+		}
+		if p.sandboxTemplate != nil {
+			v, err := evalString(ctx, p.sandboxTemplate, vars)
+			if err != nil {
+				return Decision{}, fmt.Errorf("policy %q: sandboxTemplate: %w", p.name, err)
+			}
+			d.SandboxTemplate = v
+		}
+		result.merge(d, &in)
+	}
+	return result, nil
+}
+
+func evalBool(ctx context.Context, prg cel.Program, vars map[string]any) (bool, error) {
+	out, _, err := prg.ContextEval(ctx, vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := out.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("got %v, want a bool", out)
+	}
+	return bool(b), nil
+}
+
+func evalString(ctx context.Context, prg cel.Program, vars map[string]any) (string, error) {
+	out, _, err := prg.ContextEval(ctx, vars)
+	if err != nil {
+		return "", err
+	}
+	s, ok := out.(types.String)
+	if !ok {
+		return "", fmt.Errorf("got %v, want a string", out)
+	}
+	return string(s), nil
+}
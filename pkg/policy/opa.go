@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// opaResult is the decision document an OPA policy returns for a task.
+type opaResult struct {
+	Allow           bool              `json:"allow"`
+	Message         string            `json:"message,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Priority        *int32            `json:"priority,omitempty"`
+	SandboxTemplate string            `json:"sandboxTemplate,omitempty"`
+}
+
+// OPA evaluates a decision document on an Open Policy Agent server through
+// its Data API. The task is sent as the input document; the decision must
+// be an object with an "allow" boolean and optionally "message",
+// "labels", "priority" and "sandboxTemplate".
+type OPA struct {
+	// URL is the Data API URL of the decision, e.g.
+	// http://opa.opa:8181/v1/data/shepherd/admission.
+	URL        string
+	HTTPClient *http.Client
+}
+
+var _ Evaluator = (*OPA)(nil)
+
+// NewOPA returns an OPA evaluator for the decision at url.
+func NewOPA(url string) *OPA {
+	return &OPA{URL: url, HTTPClient: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Evaluate implements Evaluator.
+func (o *OPA) Evaluate(ctx context.Context, in Input) (Decision, error) {
+	body, err := json.Marshal(map[string]any{"input": in})
+	if err != nil {
+		return Decision{}, fmt.Errorf("encoding OPA input: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.URL, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return Decision{}, fmt.Errorf("querying OPA: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return Decision{}, fmt.Errorf("querying OPA: unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var out struct {
+		Result *opaResult `json:"result"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&out); err != nil {
+		return Decision{}, fmt.Errorf("decoding OPA response: %w", err)
+	}
+	if out.Result == nil {
+		return Decision{}, fmt.Errorf("OPA decision at %s is undefined", o.URL)
+	}
+	if !out.Result.Allow {
+		message := out.Result.Message
+		if message == "" {
+			message = "denied by OPA policy"
+		}
+		return Decision{Policy: "opa", Message: message}, nil
+	}
+	if out.Result.Priority != nil && *out.Result.Priority < 0 {
+		return Decision{}, fmt.Errorf("OPA decision has negative priority %d", *out.Result.Priority)
+	}
+	return Decision{
+		Allowed:         true,
+		Labels:          out.Result.Labels,
+		Priority:        out.Result.Priority,
+		SandboxTemplate: out.Result.SandboxTemplate,
+	}, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy decides whether a new AgentTask may be created, and how it
+// is changed first, from policies written by platform teams in CEL or
+// evaluated by an Open Policy Agent server.
+package policy
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// requestedByLabel holds the login of the user who asked for a task.
+const requestedByLabel = "shepherd.io/requested-by"
+
+// Input is the view of a task that policies are evaluated against.
+type Input struct {
+	Name            string            `json:"name"`
+	Repo            string            `json:"repo"`
+	Ref             string            `json:"ref,omitempty"`
+	Description     string            `json:"description"`
+	RequestedBy     string            `json:"requestedBy,omitempty"`
+	SourceType      string            `json:"sourceType,omitempty"`
+	Labels          map[string]string `json:"labels"`
+	SandboxTemplate string            `json:"sandboxTemplate"`
+	Priority        int32             `json:"priority"`
+	// Time is when the task is being created.
+	Time time.Time `json:"time"`
+}
+
+// NewInput returns the Input for task created at now.
+func NewInput(task *toolkitv1alpha1.AgentTask, now time.Time) Input {
+	labels := maps.Clone(task.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	return Input{
+		Name:            task.Name,
+		Repo:            task.Spec.Repo.URL,
+		Ref:             task.Spec.Repo.Ref,
+		Description:     task.Spec.Task.Description,
+		RequestedBy:     labels[requestedByLabel],
+		SourceType:      task.Spec.Task.SourceType,
+		Labels:          labels,
+		SandboxTemplate: task.Spec.Runner.SandboxTemplateName,
+		Priority:        task.Spec.Priority,
+		Time:            now,
+	}
+}
+
+// Decision is the outcome of evaluating policies for a task. An allowed
+// task may carry changes to apply before it is created.
+type Decision struct {
+	Allowed bool
+	// Policy names the policy that denied the task.
+	Policy string
+	// Message says why the task was denied.
+	Message string
+
+	// Labels are added to the task, replacing existing values.
+	Labels map[string]string
+	// Priority, when set, replaces the task's priority.
+	Priority *int32
+	// SandboxTemplate, when set, replaces the task's sandbox template.
+	SandboxTemplate string
+}
+
+// Evaluator evaluates policies for a task about to be created.
+type Evaluator interface {
+	Evaluate(ctx context.Context, in Input) (Decision, error)
+}
+
+// Apply writes the changes of an allowed decision to task.
+func (d Decision) Apply(task *toolkitv1alpha1.AgentTask) {
+	if len(d.Labels) > 0 {
+		if task.Labels == nil {
+			task.Labels = map[string]string{}
+		}
+		maps.Copy(task.Labels, d.Labels)
+	}
+	if d.Priority != nil {
+		task.Spec.Priority = *d.Priority
+	}
+	if d.SandboxTemplate != "" {
+		task.Spec.Runner.SandboxTemplateName = d.SandboxTemplate
+	}
+}
+
+// merge adds the changes of next to d and to in, so that later policies
+// see the task as earlier ones left it.
+func (d *Decision) merge(next Decision, in *Input) {
+	if len(next.Labels) > 0 {
+		if d.Labels == nil {
+			d.Labels = map[string]string{}
+		}
+		maps.Copy(d.Labels, next.Labels)
+		maps.Copy(in.Labels, next.Labels)
+		in.RequestedBy = in.Labels[requestedByLabel]
+	}
+	if next.Priority != nil {
+		d.Priority = next.Priority
+		in.Priority = *next.Priority
+	}
+	if next.SandboxTemplate != "" {
+		d.SandboxTemplate = next.SandboxTemplate
+		in.SandboxTemplate = next.SandboxTemplate
+	}
+}
+
+// Chain evaluates its evaluators in order. Each one sees the changes made
+// by those before it, and the first denial stops the chain.
+type Chain []Evaluator
+
+var _ Evaluator = Chain(nil)
+
+// Evaluate implements Evaluator.
+func (c Chain) Evaluate(ctx context.Context, in Input) (Decision, error) {
+	in.Labels = maps.Clone(in.Labels)
+	if in.Labels == nil {
+		in.Labels = map[string]string{}
+	}
+	result := Decision{Allowed: true}
+	for _, e := range c {
+		d, err := e.Evaluate(ctx, in)
+		if err != nil {
+			return Decision{}, err
+		}
+		if !d.Allowed {
+			return d, nil
+		}
+		result.merge(d, &in)
+	}
+	return result, nil
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// monday10am is a Monday, 10:00 UTC.
+var monday10am = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+func testInput() Input {
+	task := &toolkitv1alpha1.AgentTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "task-abc",
+			Labels: map[string]string{requestedByLabel: "octocat", "shepherd.io/source-type": "issue"},
+		},
+		Spec: toolkitv1alpha1.AgentTaskSpec{
+			Repo:   toolkitv1alpha1.RepoSpec{URL: "https://github.com/acme/payments"},
+			Task:   toolkitv1alpha1.TaskSpec{Description: "Fix the login bug", SourceType: "issue"},
+			Runner: toolkitv1alpha1.RunnerSpec{SandboxTemplateName: "default"},
+		},
+	}
+	return NewInput(task, monday10am)
+}
+
+func TestCEL_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   CELPolicy
+		allowed  bool
+		message  string
+		evalTime time.Time
+	}{
+		{
+			name:    "allowed repo",
+			policy:  CELPolicy{Name: "org", Validate: `task.repo.startsWith("https://github.com/acme/")`},
+			allowed: true,
+		},
+		{
+			name:    "denied repo",
+			policy:  CELPolicy{Name: "org", Validate: `task.repo.startsWith("https://github.com/other/")`, Message: "only acme repos"},
+			message: "only acme repos",
+		},
+		{
+			name:    "default message",
+			policy:  CELPolicy{Name: "requester", Validate: `task.requestedBy in ["alice", "bob"]`},
+			message: `denied by policy "requester"`,
+		},
+		{
+			name:     "time of day",
+			policy:   CELPolicy{Name: "office-hours", Validate: `now.getHours("Europe/Stockholm") >= 8 && now.getHours("Europe/Stockholm") < 18`},
+			evalTime: monday10am.Add(12 * time.Hour),
+			message:  `denied by policy "office-hours"`,
+		},
+		{
+			name:    "match skips other tasks",
+			policy:  CELPolicy{Name: "fleet", Match: `"shepherd.io/fleet" in task.labels`, Validate: "false"},
+			allowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCEL([]CELPolicy{tt.policy})
+			require.NoError(t, err)
+			in := testInput()
+			if !tt.evalTime.IsZero() {
+				in.Time = tt.evalTime
+			}
+			d, err := c.Evaluate(context.Background(), in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.allowed, d.Allowed)
+			assert.Equal(t, tt.message, d.Message)
+			if !tt.allowed {
+				assert.Equal(t, tt.policy.Name, d.Policy)
+			}
+		})
+	}
+}
+
+func TestCEL_Mutate(t *testing.T) {
+	c, err := NewCEL([]CELPolicy{
+		{
+			Name:            "payments",
+			Match:           `task.repo == "https://github.com/acme/payments"`,
+			Labels:          map[string]string{"team": `"payments"`},
+			Priority:        "task.priority + 100",
+			SandboxTemplate: `"large"`,
+		},
+		{
+			// Sees the changes of the policy before it.
+			Name:     "large-sandboxes",
+			Validate: `task.sandboxTemplate != "large" || task.labels["team"] == "payments"`,
+			Labels:   map[string]string{"cost-center": `task.labels["team"] + "-" + string(task.priority)`},
+		},
+	})
+	require.NoError(t, err)
+
+	d, err := c.Evaluate(context.Background(), testInput())
+	require.NoError(t, err)
+	require.True(t, d.Allowed)
+	assert.Equal(t, map[string]string{"team": "payments", "cost-center": "payments-100"}, d.Labels)
+	require.NotNil(t, d.Priority)
+	assert.Equal(t, int32(100), *d.Priority)
+	assert.Equal(t, "large", d.SandboxTemplate)
+
+	task := &toolkitv1alpha1.AgentTask{}
+	d.Apply(task)
+	assert.Equal(t, "payments", task.Labels["team"])
+	assert.Equal(t, int32(100), task.Spec.Priority)
+	assert.Equal(t, "large", task.Spec.Runner.SandboxTemplateName)
+}
+
+func TestNewCEL_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy CELPolicy
+		errMsg string
+	}{
+		{"missing name", CELPolicy{Validate: "true"}, "name is required"},
+		{"syntax error", CELPolicy{Name: "p", Validate: "task.repo =="}, `policy "p": validate`},
+		{"wrong type", CELPolicy{Name: "p", Validate: `"yes"`}, "validate must evaluate to bool"},
+		{"unknown variable", CELPolicy{Name: "p", Match: "request.user == 'x'"}, `policy "p": match`},
+		{"label not a string", CELPolicy{Name: "p", Labels: map[string]string{"team": "1"}}, "labels.team must evaluate to string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCEL([]CELPolicy{tt.policy})
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestCEL_EvaluationError(t *testing.T) {
+	c, err := NewCEL([]CELPolicy{{Name: "p", Validate: `task.labels["missing"] == "x"`}})
+	require.NoError(t, err)
+	_, err = c.Evaluate(context.Background(), testInput())
+	assert.ErrorContains(t, err, `policy "p": validate`)
+}
+
+func TestLoadCEL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+policies:
+  - name: acme-only
+    validate: task.repo.startsWith("https://github.com/acme/")
+    message: Only acme repositories
+`), 0o600))
+	c, err := LoadCEL(path)
+	require.NoError(t, err)
+	in := testInput()
+	in.Repo = "https://github.com/evil/repo"
+	d, err := c.Evaluate(context.Background(), in)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, "Only acme repositories", d.Message)
+
+	require.NoError(t, os.WriteFile(path, []byte("policies:\n  - name: x\n    unknown: true\n"), 0o600))
+	_, err = LoadCEL(path)
+	assert.ErrorContains(t, err, "unknown")
+}
+
+func opaServer(t *testing.T, result string) *OPA {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input Input `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "https://github.com/acme/payments", body.Input.Repo)
+		assert.Equal(t, "octocat", body.Input.RequestedBy)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(result))
+	}))
+	t.Cleanup(srv.Close)
+	return NewOPA(srv.URL + "/v1/data/shepherd/admission")
+}
+
+func TestOPA(t *testing.T) {
+	t.Run("allow with changes", func(t *testing.T) {
+		o := opaServer(t, `{"result": {"allow": true, "labels": {"team": "payments"}, "priority": 50}}`)
+		d, err := o.Evaluate(context.Background(), testInput())
+		require.NoError(t, err)
+		assert.True(t, d.Allowed)
+		assert.Equal(t, map[string]string{"team": "payments"}, d.Labels)
+		require.NotNil(t, d.Priority)
+		assert.Equal(t, int32(50), *d.Priority)
+	})
+	t.Run("deny", func(t *testing.T) {
+		o := opaServer(t, `{"result": {"allow": false, "message": "repo is frozen"}}`)
+		d, err := o.Evaluate(context.Background(), testInput())
+		require.NoError(t, err)
+		assert.False(t, d.Allowed)
+		assert.Equal(t, "opa", d.Policy)
+		assert.Equal(t, "repo is frozen", d.Message)
+	})
+	t.Run("undefined decision", func(t *testing.T) {
+		o := opaServer(t, `{}`)
+		_, err := o.Evaluate(context.Background(), testInput())
+		assert.ErrorContains(t, err, "is undefined")
+	})
+}
+
+func TestChain(t *testing.T) {
+	labeler, err := NewCEL([]CELPolicy{{Name: "team", Labels: map[string]string{"team": `"payments"`}}})
+	require.NoError(t, err)
+	checker, err := NewCEL([]CELPolicy{{Name: "needs-team", Validate: `"team" in task.labels`}})
+	require.NoError(t, err)
+
+	d, err := Chain{labeler, checker}.Evaluate(context.Background(), testInput())
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+	assert.Equal(t, map[string]string{"team": "payments"}, d.Labels)
+
+	d, err = Chain{checker, labeler}.Evaluate(context.Background(), testInput())
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, "needs-team", d.Policy)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readiness serves /readyz from a set of dependency checks, and
+// reports the status of each one as JSON so an operator can tell which
+// dependency a component is waiting for.
+package readiness
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// checkTimeout bounds a single run of a check.
+const checkTimeout = 5 * time.Second
+
+// Statuses of a check and of the component as a whole.
+const (
+	StatusOK          = "ok"
+	StatusFailing     = "failing"
+	StatusDegraded    = "degraded"    // only non-critical checks fail
+	StatusUnavailable = "unavailable" // a critical check fails
+)
+
+// Check probes one dependency.
+type Check struct {
+	Name string
+	// Critical checks make the component unready when they fail. Others
+	// are only reported, for dependencies whose outage should not take
+	// the component out of its Service.
+	Critical bool
+	// Interval is how long a result is reused, for dependencies that are
+	// slow or rate limited. Zero runs the check on every request.
+	Interval time.Duration
+	// Run returns nil when the dependency is usable.
+	Run func(ctx context.Context) error
+}
+
+// CheckResult is the last outcome of a check.
+type CheckResult struct {
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Result is the readiness of a component, keyed by check name.
+type Result struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Ready reports whether every critical check passed.
+func (r Result) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+type checkState struct {
+	Check
+
+	mu     sync.Mutex
+	result CheckResult
+}
+
+// Checker runs checks and serves their results.
+type Checker struct {
+	checks []*checkState
+	now    func() time.Time
+}
+
+// New returns a Checker for checks.
+func New(checks ...Check) *Checker {
+	c := &Checker{now: time.Now}
+	for _, check := range checks {
+		c.checks = append(c.checks, &checkState{Check: check})
+	}
+	return c
+}
+
+// Check runs the checks whose cached result has expired, in parallel.
+func (c *Checker) Check(ctx context.Context) Result {
+	results := make([]CheckResult, len(c.checks))
+	var wg sync.WaitGroup
+	for i, s := range c.checks {
+		wg.Go(func() { results[i] = c.run(ctx, s) })
+	}
+	wg.Wait()
+
+	out := Result{Status: StatusOK, Checks: make(map[string]CheckResult, len(c.checks))}
+	for i, s := range c.checks {
+		r := results[i]
+		out.Checks[s.Name] = r
+		if r.Status == StatusOK {
+			continue
+		}
+		if s.Critical {
+			out.Status = StatusUnavailable
+		} else if out.Status == StatusOK {
+			out.Status = StatusDegraded
+		}
+	}
+	return out
+}
+
+// run returns the result of s, running it unless its last result is
+// younger than its interval. Concurrent requests wait for one run.
+func (c *Checker) run(ctx context.Context, s *checkState) CheckResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := c.now()
+	if !s.result.CheckedAt.IsZero() && now.Sub(s.result.CheckedAt) < s.Interval {
+		return s.result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	s.result = CheckResult{Status: StatusOK, Critical: s.Critical, CheckedAt: now}
+	if err := s.Run(ctx); err != nil {
+		s.result.Status = StatusFailing
+		s.result.Error = err.Error()
+	}
+	return s.result
+}
+
+// ServeHTTP serves the Result as JSON, with 503 Service Unavailable when a
+// critical check fails.
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result := c.Check(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	if !result.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func failing(context.Context) error { return errors.New("connection refused") }
+func passing(context.Context) error { return nil }
+
+func TestChecker_Status(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   string
+	}{
+		{"all pass", []Check{{Name: "a", Critical: true, Run: passing}, {Name: "b", Run: passing}}, StatusOK},
+		{"non-critical fails", []Check{{Name: "a", Critical: true, Run: passing}, {Name: "b", Run: failing}}, StatusDegraded},
+		{"critical fails", []Check{{Name: "a", Critical: true, Run: failing}, {Name: "b", Run: failing}}, StatusUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.checks...).Check(context.Background())
+			assert.Equal(t, tt.want, got.Status)
+			assert.Equal(t, tt.want != StatusUnavailable, got.Ready())
+		})
+	}
+}
+
+func TestChecker_CachesResultsForInterval(t *testing.T) {
+	runs := 0
+	c := New(Check{Name: "github", Interval: time.Minute, Run: func(context.Context) error {
+		runs++
+		return nil
+	}})
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.Check(context.Background())
+	c.Check(context.Background())
+	assert.Equal(t, 1, runs)
+
+	now = now.Add(time.Minute)
+	c.Check(context.Background())
+	assert.Equal(t, 2, runs)
+}
+
+func TestChecker_ServeHTTP(t *testing.T) {
+	c := New(
+		Check{Name: "kubernetes", Critical: true, Run: failing},
+		Check{Name: "github", Run: passing},
+	)
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var result Result
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+	assert.Equal(t, StatusUnavailable, result.Status)
+	assert.Equal(t, StatusFailing, result.Checks["kubernetes"].Status)
+	assert.Equal(t, "connection refused", result.Checks["kubernetes"].Error)
+	assert.True(t, result.Checks["kubernetes"].Critical)
+	assert.Equal(t, StatusOK, result.Checks["github"].Status)
+}
@@ -1,23 +1,20 @@
 package runner
 
 import (
-	"bytes"
+	"cmp"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 
 	"github.com/NissesSenap/shepherd/pkg/api"
-)
-
-const (
-	maxTaskDataResponseSize = 10 << 20 // 10MB — task context can be large
-	maxTokenResponseSize    = 4 << 10  // 4KB — token response is small
-	maxStatusResponseSize   = 64 << 10 // 64KB — status response with error details
+	"github.com/NissesSenap/shepherd/pkg/client"
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
 )
 
 // HTTPStatusError is returned when the API responds with a non-OK status code.
@@ -46,6 +43,17 @@ func WithHTTPClient(c *http.Client) ClientOption {
 	return func(cl *Client) { cl.httpClient = c }
 }
 
+// WithCorrelationID sends the task's correlation ID with every request.
+func WithCorrelationID(id string) ClientOption {
+	return func(cl *Client) { cl.correlationID = id }
+}
+
+// WithEventPrivacy filters the events sent by PostEvents to the given
+// privacy level, as served in the task's data.
+func WithEventPrivacy(level string) ClientOption {
+	return func(cl *Client) { cl.eventPrivacy = level }
+}
+
 // WithClientLogger sets the logger for the client.
 func WithClientLogger(l logr.Logger) ClientOption {
 	return func(cl *Client) { cl.logger = l }
@@ -53,131 +61,154 @@ func WithClientLogger(l logr.Logger) ClientOption {
 
 // Client implements APIClient for the shepherd API server.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	logger     logr.Logger
+	baseURL       string
+	httpClient    *http.Client
+	logger        logr.Logger
+	correlationID string
+	eventPrivacy  string
+	api           *client.Client
 }
 
 // NewClient creates an API client for the given base URL.
 func NewClient(baseURL string, opts ...ClientOption) *Client {
 	c := &Client{
-		baseURL:    baseURL,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Transport: tracing.Transport(nil), Timeout: 30 * time.Second},
 		logger:     logr.Discard(),
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	apiOpts := []client.Option{client.WithHTTPClient(c.httpClient)}
+	if c.correlationID != "" {
+		apiOpts = append(apiOpts, client.WithHeader(logging.CorrelationIDHeader, c.correlationID))
+	}
+	c.api = client.New(c.baseURL, apiOpts...)
 	return c
 }
 
-// taskDataResponse mirrors pkg/api.TaskDataResponse for JSON decoding.
-type taskDataResponse struct {
-	Description string `json:"description"`
-	Context     string `json:"context"`
-	SourceURL   string `json:"sourceURL,omitempty"`
-	Repo        struct {
-		URL string `json:"url"`
-		Ref string `json:"ref,omitempty"`
-	} `json:"repo"`
-}
-
-// tokenResponse mirrors pkg/api.TokenResponse for JSON decoding.
-type tokenResponse struct {
-	Token     string `json:"token"`
-	ExpiresAt string `json:"expiresAt"`
-}
-
-// statusUpdateRequest mirrors pkg/api.StatusUpdateRequest for JSON encoding.
-type statusUpdateRequest struct {
-	Event   string         `json:"event"`
-	Message string         `json:"message"`
-	Details map[string]any `json:"details,omitempty"`
-}
-
-// postEventRequest mirrors pkg/api.PostEventRequest for JSON encoding.
-type postEventRequest struct {
-	Events []api.TaskEvent `json:"events"`
+// statusError converts the *client.Error in err, if any, to an
+// *HTTPStatusError.
+func statusError(err error) error {
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		return &HTTPStatusError{StatusCode: apiErr.StatusCode, Body: string(apiErr.Body)}
+	}
+	return err
 }
 
 // FetchTaskData retrieves task details from the API.
 func (c *Client) FetchTaskData(ctx context.Context, taskID string) (*TaskData, error) {
-	url := c.baseURL + "/api/v1/tasks/" + taskID + "/data"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("fetching task data: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTaskDataResponseSize))
-	if err != nil {
-		return nil, fmt.Errorf("reading response body: %w", err)
-	}
-
-	switch resp.StatusCode {
-	case http.StatusOK:
-		// success, parse below
+	data, err := c.api.GetTaskData(ctx, taskID)
+	switch client.StatusCode(err) {
+	case 0:
+		if err != nil {
+			return nil, fmt.Errorf("fetching task data: %w", err)
+		}
 	case http.StatusNotFound:
 		return nil, fmt.Errorf("task %s not found", taskID)
 	case http.StatusGone:
 		return nil, fmt.Errorf("task %s is terminal", taskID)
 	default:
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+		return nil, statusError(err)
 	}
 
-	var data taskDataResponse
-	if err := json.Unmarshal(body, &data); err != nil {
-		return nil, fmt.Errorf("decoding task data: %w", err)
+	// API servers older than the versioned response send no version.
+	if data.Version > api.TaskDataVersion {
+		return nil, fmt.Errorf("unsupported task data version %d, this runner supports up to %d",
+			data.Version, api.TaskDataVersion)
 	}
 
-	return &TaskData{
+	td := &TaskData{
 		TaskID:      taskID,
 		APIURL:      c.baseURL,
 		Description: data.Description,
 		Context:     data.Context,
 		SourceURL:   data.SourceURL,
+		SourceType:  data.SourceType,
 		RepoURL:     data.Repo.URL,
 		RepoRef:     data.Repo.Ref,
-	}, nil
+		Model:       data.Model,
+		Branch:      data.Branch,
+	}
+	// Refuse levels this runner does not know rather than send events
+	// the API meant to withhold.
+	td.EventPrivacy = api.EventPrivacyFull
+	if data.EventPrivacy != "" {
+		if !api.ValidEventPrivacy(data.EventPrivacy) {
+			return nil, fmt.Errorf("decoding task data: unsupported event privacy %q", data.EventPrivacy)
+		}
+		td.EventPrivacy = data.EventPrivacy
+	}
+	// Likewise, a mode this runner does not know must not change code.
+	if data.Mode != "" {
+		if !api.ValidMode(data.Mode) {
+			return nil, fmt.Errorf("decoding task data: unsupported mode %q", data.Mode)
+		}
+		td.Mode = data.Mode
+	}
+	if data.Timeout != "" {
+		if td.Timeout, err = time.ParseDuration(data.Timeout); err != nil {
+			return nil, fmt.Errorf("decoding task data: invalid timeout %q: %w", data.Timeout, err)
+		}
+	}
+	if data.Deadline != "" {
+		if td.Deadline, err = time.Parse(time.RFC3339, data.Deadline); err != nil {
+			return nil, fmt.Errorf("decoding task data: invalid deadline %q: %w", data.Deadline, err)
+		}
+	}
+	return td, nil
+}
+
+// FilterEvents reduces events to what may leave the sandbox at the given
+// event privacy level. Empty means api.EventPrivacyFull; unknown levels
+// send nothing.
+func FilterEvents(level string, events []api.TaskEvent) []api.TaskEvent {
+	switch level {
+	case "", api.EventPrivacyFull:
+		return events
+	case api.EventPrivacySummariesOnly:
+		filtered := make([]api.TaskEvent, 0, len(events))
+		for _, e := range events {
+			e.Input = nil
+			e.Metadata = nil
+			switch e.Type {
+			case api.EventTypeToolCall:
+				// Tool call summaries quote their input, such as a command.
+				e.Summary = cmp.Or(e.Tool, "tool call")
+			case api.EventTypeToolResult:
+				// Tool result summaries are the tool's output.
+				success := e.Output == nil || e.Output.Success
+				outcome := " succeeded"
+				if !success {
+					outcome = " failed"
+				}
+				e.Summary = cmp.Or(e.Tool, "tool") + outcome
+				e.Output = &api.TaskEventOutput{Success: success}
+			default:
+				e.Output = nil
+			}
+			filtered = append(filtered, e)
+		}
+		return filtered
+	default:
+		return events[:0]
+	}
 }
 
 // FetchToken retrieves a GitHub installation token.
 // Returns a fatal error on 409 Conflict (token already issued, non-retriable).
 func (c *Client) FetchToken(ctx context.Context, taskID string) (string, time.Time, error) {
-	url := c.baseURL + "/api/v1/tasks/" + taskID + "/token"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return "", time.Time{}, fmt.Errorf("creating request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", time.Time{}, fmt.Errorf("fetching token: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
-	if err != nil {
-		return "", time.Time{}, fmt.Errorf("reading response body: %w", err)
-	}
-
-	switch resp.StatusCode {
-	case http.StatusOK:
-		// success, parse below
+	tok, err := c.api.GetTaskToken(ctx, taskID)
+	switch client.StatusCode(err) {
+	case 0:
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("fetching token: %w", err)
+		}
 	case http.StatusConflict:
 		return "", time.Time{}, fmt.Errorf("token already issued for task %s (non-retriable)", taskID)
 	default:
-		return "", time.Time{}, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var tok tokenResponse
-	if err := json.Unmarshal(body, &tok); err != nil {
-		return "", time.Time{}, fmt.Errorf("decoding token response: %w", err)
+		return "", time.Time{}, statusError(err)
 	}
 
 	expiresAt, err := time.Parse(time.RFC3339, tok.ExpiresAt)
@@ -189,70 +220,34 @@ func (c *Client) FetchToken(ctx context.Context, taskID string) (string, time.Ti
 }
 
 // PostEvents sends agent events to the API. This is best-effort: callers should
-// log errors but not fail the task if event posting fails.
+// log errors but not fail the task if event posting fails. Events are first
+// reduced to the client's event privacy level, and nothing is sent if the
+// level withholds all of them.
 func (c *Client) PostEvents(ctx context.Context, taskID string, events []api.TaskEvent) error {
-	url := c.baseURL + "/api/v1/tasks/" + taskID + "/events"
-
-	payload := postEventRequest{Events: events}
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshaling events: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+	filtered := FilterEvents(c.eventPrivacy, events)
+	if len(filtered) == 0 && len(events) > 0 {
+		return nil
 	}
-	req.Header.Set("Content-Type", "application/json")
+	events = filtered
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+	_, err := c.api.PostEvents(ctx, taskID,
+		&client.PostEventsParams{ShepherdEventSchema: api.EventSchemaVersion},
+		api.PostEventRequest{Events: events})
+	if err != nil && client.StatusCode(err) == 0 {
 		return fmt.Errorf("posting events: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxStatusResponseSize))
-		return &HTTPStatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
-	}
-
-	return nil
+	return statusError(err)
 }
 
 // ReportStatus sends a status update to the API.
 func (c *Client) ReportStatus(ctx context.Context, taskID string, event, message string, details map[string]any) error {
-	url := c.baseURL + "/api/v1/tasks/" + taskID + "/status"
-
-	payload := statusUpdateRequest{
+	_, err := c.api.UpdateTaskStatus(ctx, taskID, api.StatusUpdateRequest{
 		Event:   event,
 		Message: message,
 		Details: details,
-	}
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshaling status update: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+	})
+	if err != nil && client.StatusCode(err) == 0 {
 		return fmt.Errorf("reporting status: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxStatusResponseSize))
-	if err != nil {
-		return fmt.Errorf("reading response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return &HTTPStatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
-	}
-
-	return nil
+	return statusError(err)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/NissesSenap/shepherd/pkg/api"
+	"time"
 )
 
 func TestFetchTaskData(t *testing.T) {
@@ -21,17 +22,18 @@ func TestFetchTaskData(t *testing.T) {
 			assert.Equal(t, http.MethodGet, r.Method)
 
 			w.Header().Set("Content-Type", "application/json")
-			_ = json.NewEncoder(w).Encode(taskDataResponse{
+			_ = json.NewEncoder(w).Encode(api.TaskDataResponse{
+				Version:     api.TaskDataVersion,
 				Description: "fix the bug",
 				Context:     "some context",
 				SourceURL:   "https://github.com/org/repo/issues/1",
-				Repo: struct {
-					URL string `json:"url"`
-					Ref string `json:"ref,omitempty"`
-				}{
+				SourceType:  "issue",
+				Repo: api.RepoRequest{
 					URL: "https://github.com/org/repo",
 					Ref: "main",
 				},
+				Timeout:  "30m0s",
+				Deadline: "2026-03-01T12:30:00Z",
 			})
 		}))
 		defer srv.Close()
@@ -45,8 +47,98 @@ func TestFetchTaskData(t *testing.T) {
 		assert.Equal(t, "fix the bug", data.Description)
 		assert.Equal(t, "some context", data.Context)
 		assert.Equal(t, "https://github.com/org/repo/issues/1", data.SourceURL)
+		assert.Equal(t, "issue", data.SourceType)
 		assert.Equal(t, "https://github.com/org/repo", data.RepoURL)
 		assert.Equal(t, "main", data.RepoRef)
+		assert.Equal(t, 30*time.Minute, data.Timeout)
+		assert.Equal(t, time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC), data.Deadline)
+		assert.Equal(t, api.EventPrivacyFull, data.EventPrivacy)
+	})
+
+	t.Run("event privacy", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.TaskDataResponse{Version: 2, Description: "fix the bug", EventPrivacy: "minimal"})
+		}))
+		defer srv.Close()
+
+		data, err := NewClient(srv.URL).FetchTaskData(context.Background(), "task-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.EventPrivacyMinimal, data.EventPrivacy)
+	})
+
+	t.Run("unknown event privacy", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.TaskDataResponse{Version: 2, Description: "fix the bug", EventPrivacy: "redacted"})
+		}))
+		defer srv.Close()
+
+		_, err := NewClient(srv.URL).FetchTaskData(context.Background(), "task-1")
+		assert.ErrorContains(t, err, `unsupported event privacy "redacted"`)
+	})
+
+	t.Run("mode", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.TaskDataResponse{Version: 3, Description: "plan the fix", Mode: "plan"})
+		}))
+		defer srv.Close()
+
+		data, err := NewClient(srv.URL).FetchTaskData(context.Background(), "task-1")
+		require.NoError(t, err)
+		assert.Equal(t, api.ModePlan, data.Mode)
+	})
+
+	t.Run("model and branch", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.TaskDataResponse{Version: 1, Description: "fix the bug",
+				Model: "claude-opus-4-1", Branch: "bot/task-1"})
+		}))
+		defer srv.Close()
+
+		data, err := NewClient(srv.URL).FetchTaskData(context.Background(), "task-1")
+		require.NoError(t, err)
+		assert.Equal(t, "claude-opus-4-1", data.Model)
+		assert.Equal(t, "bot/task-1", data.Branch)
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.TaskDataResponse{Version: 3, Description: "fix the bug", Mode: "refactor"})
+		}))
+		defer srv.Close()
+
+		_, err := NewClient(srv.URL).FetchTaskData(context.Background(), "task-1")
+		assert.ErrorContains(t, err, `unsupported mode "refactor"`)
+	})
+
+	t.Run("newer version", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.TaskDataResponse{Version: api.TaskDataVersion + 1, Description: "fix the bug"})
+		}))
+		defer srv.Close()
+
+		c := NewClient(srv.URL)
+		_, err := c.FetchTaskData(context.Background(), "task-1")
+		assert.ErrorContains(t, err, "unsupported task data version")
+	})
+
+	t.Run("base URL with path prefix", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/shepherd/api/v1/tasks/task-1/data", r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.TaskDataResponse{Description: "fix the bug"})
+		}))
+		defer srv.Close()
+
+		c := NewClient(srv.URL + "/shepherd/")
+		data, err := c.FetchTaskData(context.Background(), "task-1")
+		require.NoError(t, err)
+		assert.Equal(t, srv.URL+"/shepherd", data.APIURL)
 	})
 
 	t.Run("not found", func(t *testing.T) {
@@ -83,7 +175,7 @@ func TestFetchToken(t *testing.T) {
 			assert.Equal(t, http.MethodGet, r.Method)
 
 			w.Header().Set("Content-Type", "application/json")
-			_ = json.NewEncoder(w).Encode(tokenResponse{
+			_ = json.NewEncoder(w).Encode(api.TokenResponse{
 				Token:     "ghs_test_token",
 				ExpiresAt: "2026-02-10T12:00:00Z",
 			})
@@ -119,15 +211,17 @@ func TestPostEvents(t *testing.T) {
 			assert.Equal(t, "/api/v1/tasks/task-1/events", r.URL.Path)
 			assert.Equal(t, http.MethodPost, r.Method)
 			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "1", r.Header.Get(api.EventSchemaVersionHeader))
 
 			body, err := io.ReadAll(r.Body)
 			require.NoError(t, err)
 
-			var req postEventRequest
+			var req api.PostEventRequest
 			require.NoError(t, json.Unmarshal(body, &req))
 			assert.NotNil(t, req.Events)
 
 			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"status":"accepted"}`))
 		}))
 		defer srv.Close()
 
@@ -155,6 +249,7 @@ func TestPostEvents(t *testing.T) {
 	t.Run("empty events", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"status":"accepted"}`))
 		}))
 		defer srv.Close()
 
@@ -162,6 +257,63 @@ func TestPostEvents(t *testing.T) {
 		err := c.PostEvents(context.Background(), "task-1", []api.TaskEvent{})
 		require.NoError(t, err)
 	})
+
+	t.Run("event privacy", func(t *testing.T) {
+		var got []api.TaskEvent
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req api.PostEventRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			got = req.Events
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"status":"accepted"}`))
+		}))
+		defer srv.Close()
+
+		events := []api.TaskEvent{
+			{Sequence: 1, Type: api.EventTypeToolCall, Summary: "cat secrets.go", Tool: "Bash",
+				Input: map[string]any{"command": "cat secrets.go"}},
+		}
+
+		c := NewClient(srv.URL, WithEventPrivacy(api.EventPrivacySummariesOnly))
+		require.NoError(t, c.PostEvents(context.Background(), "task-1", events))
+		require.Len(t, got, 1)
+		assert.Equal(t, "Bash", got[0].Summary)
+		assert.Nil(t, got[0].Input)
+
+		got = nil
+		c = NewClient(srv.URL, WithEventPrivacy(api.EventPrivacyMinimal))
+		require.NoError(t, c.PostEvents(context.Background(), "task-1", events))
+		assert.Nil(t, got, "minimal sends no request")
+	})
+}
+
+func TestFilterEvents(t *testing.T) {
+	events := []api.TaskEvent{
+		{Sequence: 1, Type: api.EventTypeThinking, Summary: "Looking at the handler"},
+		{Sequence: 2, Type: api.EventTypeToolCall, Summary: "Reading auth.go", Tool: "Read",
+			Input: map[string]any{"file_path": "auth.go"}},
+		{Sequence: 3, Type: api.EventTypeToolResult, Summary: "package auth", Tool: "Read",
+			Output: &api.TaskEventOutput{Success: true, Summary: "package auth"}},
+		{Sequence: 4, Type: api.EventTypeToolResult, Summary: "exit status 1", Tool: "Bash",
+			Output: &api.TaskEventOutput{Success: false, Summary: "exit status 1"}},
+		{Sequence: 5, Type: api.EventTypeError, Summary: "failed to parse line"},
+	}
+
+	assert.Equal(t, events, FilterEvents("", events))
+	assert.Equal(t, events, FilterEvents(api.EventPrivacyFull, events))
+	assert.Empty(t, FilterEvents(api.EventPrivacyMinimal, events))
+	assert.Empty(t, FilterEvents("unknown", events), "unknown levels send nothing")
+
+	assert.Equal(t, []api.TaskEvent{
+		{Sequence: 1, Type: api.EventTypeThinking, Summary: "Looking at the handler"},
+		{Sequence: 2, Type: api.EventTypeToolCall, Summary: "Read", Tool: "Read"},
+		{Sequence: 3, Type: api.EventTypeToolResult, Summary: "Read succeeded", Tool: "Read",
+			Output: &api.TaskEventOutput{Success: true}},
+		{Sequence: 4, Type: api.EventTypeToolResult, Summary: "Bash failed", Tool: "Bash",
+			Output: &api.TaskEventOutput{Success: false}},
+		{Sequence: 5, Type: api.EventTypeError, Summary: "failed to parse line"},
+	}, FilterEvents(api.EventPrivacySummariesOnly, events))
+	assert.Equal(t, map[string]any{"file_path": "auth.go"}, events[1].Input, "input events are not modified")
 }
 
 func TestReportStatus(t *testing.T) {
@@ -174,7 +326,7 @@ func TestReportStatus(t *testing.T) {
 			body, err := io.ReadAll(r.Body)
 			require.NoError(t, err)
 
-			var req statusUpdateRequest
+			var req api.StatusUpdateRequest
 			require.NoError(t, json.Unmarshal(body, &req))
 			assert.Equal(t, "completed", req.Event)
 			assert.Equal(t, "task done", req.Message)
@@ -192,6 +344,20 @@ func TestReportStatus(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("correlation ID", func(t *testing.T) {
+		var header string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header.Get("X-Correlation-ID")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"status":"accepted"}`))
+		}))
+		defer srv.Close()
+
+		c := NewClient(srv.URL, WithCorrelationID("corr-1"))
+		require.NoError(t, c.ReportStatus(context.Background(), "task-1", "started", "starting", nil))
+		assert.Equal(t, "corr-1", header)
+	})
+
 	t.Run("server error", func(t *testing.T) {
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
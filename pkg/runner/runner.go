@@ -1,22 +1,57 @@
 package runner
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// ProtocolVersion is the version of the task assignment protocol this
+// runner speaks. It is bumped when a change to the assignment or to the
+// runner's use of the API breaks compatibility with the operator, and must
+// match the operator's RunnerProtocolVersion.
+const ProtocolVersion = 1
 
 // TaskAssignment is the payload sent by the operator when assigning a task.
 type TaskAssignment struct {
 	TaskID string `json:"taskID"`
 	APIURL string `json:"apiURL"`
+	// ProtocolVersion is the operator's protocol version. Operators that
+	// predate versioning do not send it; they speak version 1.
+	ProtocolVersion int `json:"protocolVersion,omitempty"`
+	// CorrelationID identifies the task in the logs of all components. The
+	// runner logs it and sends it with its requests to the API.
+	CorrelationID string `json:"correlationID,omitempty"`
+	// TraceParent is the W3C traceparent of the operator's reconcile;
+	// the runner's requests to the API continue that trace.
+	TraceParent string `json:"traceparent,omitempty"`
 }
 
 // TaskData holds the fetched task information for the runner.
 type TaskData struct {
-	TaskID      string
-	APIURL      string
-	Description string
-	Context     string
-	SourceURL   string
-	RepoURL     string
-	RepoRef     string
+	TaskID        string
+	CorrelationID string
+	APIURL        string
+	Description   string
+	Context       string
+	SourceURL     string
+	SourceType    string
+	RepoURL       string
+	RepoRef       string
+	// Timeout is the time budget of the run. Deadline is when the run times
+	// out; it is zero if the API did not know the start of the run yet.
+	Timeout  time.Duration
+	Deadline time.Time
+	// EventPrivacy is the event privacy level of the task, one of the
+	// api.EventPrivacy levels. Runners pass it to WithEventPrivacy.
+	EventPrivacy string
+	// Mode is api.ModePlan or api.ModeReview for tasks that must not
+	// change code, and empty for tasks that do.
+	Mode string
+	// Model is the model the agent works with, empty for its default.
+	Model string
+	// Branch is the branch a task that opens a PR pushes to; empty for
+	// shepherd/{TaskID}, as API servers that predate branch prefixes send.
+	Branch string
 }
 
 // Result holds the outcome of a task execution.
@@ -24,6 +59,9 @@ type Result struct {
 	Success bool
 	PRURL   string
 	Message string
+	CostUSD float64 // Model cost of the run, if the runner can measure it
+	// Summary describes what the run changed and why, in a few sentences.
+	Summary string
 }
 
 // TaskRunner is implemented by language-specific runners.
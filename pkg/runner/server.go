@@ -9,6 +9,11 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/NissesSenap/shepherd/pkg/logging"
+	"github.com/NissesSenap/shepherd/pkg/tracing"
 )
 
 // Server handles task assignment and delegates to a TaskRunner.
@@ -70,18 +75,40 @@ func (s *Server) newMux() *http.ServeMux {
 			http.Error(w, "taskID and apiURL are required", http.StatusBadRequest)
 			return
 		}
-		s.logger.Info("received task assignment", "taskID", ta.TaskID, "apiURL", ta.APIURL)
+		s.logger.Info("received task assignment", logging.TaskID, ta.TaskID, "apiURL", ta.APIURL,
+			"protocolVersion", ta.ProtocolVersion)
+		if ta.ProtocolVersion != 0 && ta.ProtocolVersion != ProtocolVersion {
+			writeAssignmentResponse(w, http.StatusBadRequest, assignmentResponse{
+				Error: fmt.Sprintf("unsupported protocol version %d", ta.ProtocolVersion),
+			})
+			return
+		}
 		select {
 		case s.assigned <- ta:
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"status":"accepted"}`))
+			writeAssignmentResponse(w, http.StatusOK, assignmentResponse{Status: "accepted"})
 		default:
-			http.Error(w, "task already assigned", http.StatusConflict)
+			writeAssignmentResponse(w, http.StatusConflict, assignmentResponse{Error: "task already assigned"})
 		}
 	})
 	return mux
 }
 
+// assignmentResponse is the reply to a task assignment. It always carries
+// the runner's protocol version, so the operator can tell an incompatible
+// runner from a broken one.
+type assignmentResponse struct {
+	Status          string `json:"status,omitempty"`
+	Error           string `json:"error,omitempty"`
+	ProtocolVersion int    `json:"protocolVersion"`
+}
+
+func writeAssignmentResponse(w http.ResponseWriter, code int, resp assignmentResponse) {
+	resp.ProtocolVersion = ProtocolVersion
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
 // Serve starts the HTTP server and blocks until the task is complete or context is cancelled.
 func (s *Server) Serve(ctx context.Context) error {
 	srv := &http.Server{Addr: s.addr, Handler: s.newMux()}
@@ -98,7 +125,7 @@ func (s *Server) Serve(ctx context.Context) error {
 	var ta TaskAssignment
 	select {
 	case ta = <-s.assigned:
-		s.logger.Info("task assigned", "taskID", ta.TaskID, "apiURL", ta.APIURL)
+		s.logger.Info("task assigned", logging.TaskID, ta.TaskID, "apiURL", ta.APIURL)
 	case err := <-listenErr:
 		return fmt.Errorf("server failed to start: %w", err)
 	case <-ctx.Done():
@@ -120,12 +147,19 @@ func (s *Server) Serve(ctx context.Context) error {
 
 // executeTask runs the full task lifecycle: report started, fetch data, fetch token, run, report result.
 func (s *Server) executeTask(ctx context.Context, ta TaskAssignment) error {
-	log := s.logger.WithValues("taskID", ta.TaskID)
+	log := s.logger.WithValues(logging.TaskID, ta.TaskID)
+	if ta.CorrelationID != "" {
+		log = log.WithValues(logging.CorrelationID, ta.CorrelationID)
+	}
+
+	ctx, span := tracing.Tracer().Start(tracing.FromTraceParent(ctx, ta.TraceParent), "execute task",
+		trace.WithAttributes(attribute.String(logging.TaskID, ta.TaskID)))
+	defer span.End()
 
 	// Use injected client (testing) or create a new one
 	client := s.client
 	if client == nil {
-		client = NewClient(ta.APIURL, WithClientLogger(log))
+		client = NewClient(ta.APIURL, WithClientLogger(log), WithCorrelationID(ta.CorrelationID))
 	}
 
 	// Guard against nil runner
@@ -150,6 +184,7 @@ func (s *Server) executeTask(ctx context.Context, ta TaskAssignment) error {
 		return fmt.Errorf("fetching task data: %w", err)
 	}
 	taskData.APIURL = ta.APIURL
+	taskData.CorrelationID = ta.CorrelationID
 
 	// Fetch GitHub token (409 = fatal, non-retriable)
 	token, expiresAt, err := client.FetchToken(ctx, ta.TaskID)
@@ -182,9 +217,20 @@ func (s *Server) executeTask(ctx context.Context, ta TaskAssignment) error {
 	if fallbackMsg == "" {
 		fallbackMsg = "task " + status
 	}
-	var details map[string]any
+	details := map[string]any{}
 	if result.PRURL != "" {
-		details = map[string]any{"pr_url": result.PRURL}
+		details["pr_url"] = result.PRURL
+	}
+	if result.CostUSD > 0 {
+		// The Stop hook reports before the agent exits and cannot know the
+		// final cost, so this fallback is the only place it is reported.
+		details["cost_usd"] = result.CostUSD
+	}
+	if result.Summary != "" {
+		details["summary"] = result.Summary
+	}
+	if len(details) == 0 {
+		details = nil
 	}
 	if err := client.ReportStatus(ctx, ta.TaskID, status, fallbackMsg, details); err != nil {
 		log.Error(err, "failed to report fallback terminal status", "status", status)
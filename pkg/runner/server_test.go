@@ -2,6 +2,7 @@ package runner
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestHealthEndpoint(t *testing.T) {
@@ -39,12 +41,48 @@ func TestTaskAccepted(t *testing.T) {
 	defer func() { _ = resp.Body.Close() }()
 
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var reply assignmentResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	assert.Equal(t, "accepted", reply.Status)
+	assert.Equal(t, ProtocolVersion, reply.ProtocolVersion)
 
 	ta := <-s.assigned
 	assert.Equal(t, "task-1", ta.TaskID)
 	assert.Equal(t, "http://api:8081", ta.APIURL)
 }
 
+func TestTaskProtocolVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{"current version", `{"taskID":"task-1","apiURL":"http://api:8081","protocolVersion":1}`, http.StatusOK},
+		{"unversioned operator", `{"taskID":"task-1","apiURL":"http://api:8081"}`, http.StatusOK},
+		{"newer operator", `{"taskID":"task-1","apiURL":"http://api:8081","protocolVersion":2}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(nil)
+			srv := httptest.NewServer(s.newMux())
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/task", "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			var reply assignmentResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+			assert.Equal(t, ProtocolVersion, reply.ProtocolVersion, "the runner reports its version on every reply")
+			if tt.wantCode != http.StatusOK {
+				assert.Equal(t, "unsupported protocol version 2", reply.Error)
+				assert.Empty(t, s.assigned, "an incompatible assignment is not run")
+			}
+		})
+	}
+}
+
 func TestTaskRejectsSecond(t *testing.T) {
 	s := NewServer(nil)
 	srv := httptest.NewServer(s.newMux())
@@ -129,9 +167,11 @@ func TestTaskMissingFields(t *testing.T) {
 type mockRunner struct {
 	result *Result
 	err    error
+	ctx    context.Context
 }
 
 func (m *mockRunner) Run(ctx context.Context, task TaskData, token string) (*Result, error) {
+	m.ctx = ctx
 	return m.result, m.err
 }
 
@@ -149,6 +189,7 @@ type statusCall struct {
 	taskID  string
 	event   string
 	message string
+	details map[string]any
 }
 
 func (m *mockAPIClient) FetchTaskData(ctx context.Context, taskID string) (*TaskData, error) {
@@ -162,7 +203,7 @@ func (m *mockAPIClient) FetchToken(ctx context.Context, taskID string) (string,
 func (m *mockAPIClient) ReportStatus(
 	ctx context.Context, taskID string, event, message string, details map[string]any,
 ) error {
-	m.statusCalls = append(m.statusCalls, statusCall{taskID: taskID, event: event, message: message})
+	m.statusCalls = append(m.statusCalls, statusCall{taskID: taskID, event: event, message: message, details: details})
 	return m.statusErr
 }
 
@@ -184,6 +225,8 @@ func TestExecuteTaskHappyPath(t *testing.T) {
 			Success: true,
 			PRURL:   "https://github.com/org/repo/pull/1",
 			Message: "PR created",
+			CostUSD: 1.25,
+			Summary: "Fixed the login redirect.",
 		},
 		err: nil,
 	}
@@ -203,6 +246,31 @@ func TestExecuteTaskHappyPath(t *testing.T) {
 	assert.Equal(t, "started", mockClient.statusCalls[0].event)
 	assert.Equal(t, "completed", mockClient.statusCalls[1].event)
 	assert.Equal(t, "PR created", mockClient.statusCalls[1].message)
+	assert.Equal(t, map[string]any{
+		"pr_url":   "https://github.com/org/repo/pull/1",
+		"cost_usd": 1.25,
+		"summary":  "Fixed the login redirect.",
+	}, mockClient.statusCalls[1].details)
+}
+
+func TestExecuteTaskContinuesTrace(t *testing.T) {
+	mockClient := &mockAPIClient{
+		taskData:     &TaskData{TaskID: "task-1"},
+		token:        "ghs_test_token",
+		tokenExpires: time.Now().Add(time.Hour),
+	}
+	mockRun := &mockRunner{result: &Result{Success: true}}
+
+	s := NewServer(mockRun, WithClient(mockClient))
+	ta := TaskAssignment{
+		TaskID:      "task-1",
+		APIURL:      "http://api:8081",
+		TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+
+	require.NoError(t, s.executeTask(context.Background(), ta))
+	require.NotNil(t, mockRun.ctx)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(mockRun.ctx).TraceID().String())
 }
 
 func TestExecuteTaskFetchDataFails(t *testing.T) {
@@ -0,0 +1,258 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package seed gives a new installation a working reference: it creates a
+// demo SandboxTemplate and a sample task through the API, then follows the
+// task through its lifecycle, printing each step.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	sandboxv1alpha1 "sigs.k8s.io/agent-sandbox/api/v1alpha1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+	"github.com/NissesSenap/shepherd/pkg/api"
+	shepherdclient "github.com/NissesSenap/shepherd/pkg/client"
+)
+
+// runnerPort is the port the runner image serves its task assignment API on.
+const runnerPort = 8888
+
+// Options configures Run.
+type Options struct {
+	// Namespace is where the API server creates tasks; the template is
+	// created there too.
+	Namespace string
+	// TemplateName is the SandboxTemplate the demo task runs in. An
+	// existing template of that name is used as is.
+	TemplateName string
+	// RunnerImage is the container image of the demo template.
+	RunnerImage string
+	// CredentialsSecret holds the runner's Anthropic API key under api-key.
+	CredentialsSecret string
+	RepoURL           string
+	Description       string
+	CallbackURL       string
+	// Wait is how long to follow the task; 0 returns once it is created.
+	Wait time.Duration
+	// PollInterval is how often the task is read while following it.
+	PollInterval time.Duration
+	Out          io.Writer
+}
+
+// Scheme returns the scheme of the Kubernetes client Run is given.
+func Scheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	utilruntime.Must(sandboxextv1alpha1.AddToScheme(s))
+	return s
+}
+
+// Run creates the demo template with kube and the demo task with shepherd,
+// then follows the task until it finishes or opts.Wait passes. It returns
+// an error if the task does not succeed.
+func Run(ctx context.Context, kube client.Client, shepherd *shepherdclient.Client, opts Options) error {
+	out := opts.Out
+
+	_, _ = fmt.Fprintf(out, "[1/4] Checking the API at %s\n", shepherd.BaseURL())
+	if err := shepherd.Healthz(ctx); err != nil {
+		return fmt.Errorf("API is not reachable: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "[2/4] Creating SandboxTemplate %s/%s\n", opts.Namespace, opts.TemplateName)
+	created, err := ensureTemplate(ctx, kube, opts)
+	if err != nil {
+		return err
+	}
+	if created {
+		_, _ = fmt.Fprintf(out, "      created with image %s; the runner reads its API key from secret %s\n",
+			opts.RunnerImage, opts.CredentialsSecret)
+	} else {
+		_, _ = fmt.Fprintln(out, "      already exists, using it as is")
+	}
+
+	_, _ = fmt.Fprintf(out, "[3/4] Creating a task for %s\n", opts.RepoURL)
+	task, err := shepherd.CreateTask(ctx, nil, api.CreateTaskRequest{
+		Repo:     api.RepoRequest{URL: opts.RepoURL},
+		Task:     api.TaskRequest{Description: opts.Description},
+		Callback: opts.CallbackURL,
+		Runner:   &api.RunnerConfig{SandboxTemplateName: opts.TemplateName},
+	})
+	if err != nil {
+		return fmt.Errorf("creating task: %w", err)
+	}
+	_, _ = fmt.Fprintf(out, "      created task %s\n", task.ID)
+	if task.Namespace != opts.Namespace {
+		return fmt.Errorf("the API creates tasks in namespace %q but the template is in %q; rerun with --namespace %s",
+			task.Namespace, opts.Namespace, task.Namespace)
+	}
+
+	if opts.Wait == 0 {
+		_, _ = fmt.Fprintf(out, "[4/4] Not waiting; follow the task at %s/api/v1/tasks/%s\n", shepherd.BaseURL(), task.ID)
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "[4/4] Following task %s for up to %s\n", task.ID, opts.Wait)
+	return follow(ctx, shepherd, task, opts)
+}
+
+// ensureTemplate creates the demo template unless one of that name exists,
+// and reports whether it did.
+func ensureTemplate(ctx context.Context, kube client.Client, opts Options) (bool, error) {
+	var existing sandboxextv1alpha1.SandboxTemplate
+	err := kube.Get(ctx, client.ObjectKey{Namespace: opts.Namespace, Name: opts.TemplateName}, &existing)
+	if err == nil {
+		return false, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("getting SandboxTemplate: %w", err)
+	}
+	if err := kube.Create(ctx, demoTemplate(opts)); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("creating SandboxTemplate: %w", err)
+	}
+	return true, nil
+}
+
+// demoTemplate is the runner template of the quickstart guide.
+func demoTemplate(opts Options) *sandboxextv1alpha1.SandboxTemplate {
+	return &sandboxextv1alpha1.SandboxTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.TemplateName,
+			Namespace: opts.Namespace,
+			Labels:    map[string]string{"app.kubernetes.io/created-by": "shepherd-seed"},
+		},
+		Spec: sandboxextv1alpha1.SandboxTemplateSpec{
+			PodTemplate: sandboxv1alpha1.PodTemplate{
+				Spec: corev1.PodSpec{
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsUser:    ptr.To[int64](1000),
+						RunAsGroup:   ptr.To[int64](1000),
+						FSGroup:      ptr.To[int64](1000),
+						RunAsNonRoot: ptr.To(true),
+					},
+					Containers: []corev1.Container{{
+						Name:  "runner",
+						Image: opts.RunnerImage,
+						Ports: []corev1.ContainerPort{{ContainerPort: runnerPort, Protocol: corev1.ProtocolTCP}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{
+								HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(runnerPort)},
+							},
+							InitialDelaySeconds: 2,
+							PeriodSeconds:       5,
+						},
+						Env: []corev1.EnvVar{{
+							Name: "ANTHROPIC_API_KEY",
+							ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: opts.CredentialsSecret},
+								Key:                  "api-key",
+							}},
+						}},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("2Gi"),
+								corev1.ResourceCPU:    resource.MustParse("500m"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("4Gi"),
+								corev1.ResourceCPU:    resource.MustParse("2000m"),
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "home", MountPath: "/home/shepherd"},
+							{Name: "workspace", MountPath: "/workspace"},
+						},
+					}},
+					Volumes: []corev1.Volume{
+						{Name: "home", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+						{Name: "workspace", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					},
+					RestartPolicy: corev1.RestartPolicyNever,
+				},
+			},
+		},
+	}
+}
+
+// follow polls the task and prints every change of its phase or message
+// until it finishes.
+func follow(ctx context.Context, shepherd *shepherdclient.Client, task *api.TaskResponse, opts Options) error {
+	ctx, cancel := context.WithTimeout(ctx, opts.Wait)
+	defer cancel()
+	start := time.Now()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	var last api.TaskStatusSummary
+	for {
+		if task.Status.Phase != last.Phase || task.Status.Message != last.Message {
+			_, _ = fmt.Fprintf(opts.Out, "      %8s  %-20s %s\n",
+				time.Since(start).Round(time.Second), task.Status.Phase, task.Status.Message)
+			last = task.Status
+		}
+		if done, err := finished(task); done {
+			if err == nil && task.Status.PRURL != "" {
+				_, _ = fmt.Fprintf(opts.Out, "      opened %s\n", task.Status.PRURL)
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("task %s did not finish within %s; it is %s", task.ID, opts.Wait, task.Status.Phase)
+		case <-ticker.C:
+		}
+		next, err := shepherd.GetTask(ctx, task.ID, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return fmt.Errorf("getting task %s: %w", task.ID, err)
+		}
+		task = next
+	}
+}
+
+// finished reports whether the task has finished, and returns an error
+// unless it succeeded.
+func finished(task *api.TaskResponse) (bool, error) {
+	switch task.Status.Phase {
+	case string(toolkitv1alpha1.PhaseSucceeded):
+		return true, nil
+	case string(toolkitv1alpha1.PhaseFailed), string(toolkitv1alpha1.PhaseTimedOut), string(toolkitv1alpha1.PhaseCancelled):
+		msg := task.Status.Error
+		if msg == "" {
+			msg = task.Status.Message
+		}
+		return true, fmt.Errorf("task %s %s: %s", task.ID, task.Status.Phase, msg)
+	}
+	return false, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	sandboxextv1alpha1 "sigs.k8s.io/agent-sandbox/extensions/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/NissesSenap/shepherd/pkg/api"
+	shepherdclient "github.com/NissesSenap/shepherd/pkg/client"
+)
+
+// fakeAPI serves the demo task, moving it through phases on every read.
+type fakeAPI struct {
+	mu        sync.Mutex
+	namespace string
+	phases    []api.TaskStatusSummary
+	created   *api.CreateTaskRequest
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	task := api.TaskResponse{ID: "task-demo", Namespace: f.namespace, Status: api.TaskStatusSummary{Phase: "Pending"}}
+	switch {
+	case r.URL.Path == "/healthz":
+		_, _ = w.Write([]byte("ok"))
+		return
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/tasks":
+		f.created = &api.CreateTaskRequest{}
+		_ = json.NewDecoder(r.Body).Decode(f.created)
+		w.WriteHeader(http.StatusCreated)
+	case r.URL.Path == "/api/v1/tasks/task-demo":
+		task.Status = f.phases[0]
+		if len(f.phases) > 1 {
+			f.phases = f.phases[1:]
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(task)
+}
+
+func testOptions(out *bytes.Buffer) Options {
+	return Options{
+		Namespace:         "shepherd-system",
+		TemplateName:      "shepherd-demo",
+		RunnerImage:       "runner:test",
+		CredentialsSecret: "anthropic-credentials",
+		RepoURL:           "https://github.com/org/repo",
+		Description:       "Say hello world",
+		CallbackURL:       "https://example.com/callback",
+		Wait:              5 * time.Second,
+		PollInterval:      time.Millisecond,
+		Out:               out,
+	}
+}
+
+func TestRun(t *testing.T) {
+	fakeSrv := &fakeAPI{namespace: "shepherd-system", phases: []api.TaskStatusSummary{
+		{Phase: "Running", Message: "Runner started"},
+		{Phase: "Running", Message: "Runner started"},
+		{Phase: "Succeeded", Message: "Task completed", PRURL: "https://github.com/org/repo/pull/1"},
+	}}
+	srv := httptest.NewServer(fakeSrv)
+	defer srv.Close()
+	kube := fake.NewClientBuilder().WithScheme(Scheme()).Build()
+
+	var out bytes.Buffer
+	err := Run(context.Background(), kube, shepherdclient.New(srv.URL), testOptions(&out))
+	require.NoError(t, err)
+
+	var tmpl sandboxextv1alpha1.SandboxTemplate
+	require.NoError(t, kube.Get(context.Background(), client.ObjectKey{Namespace: "shepherd-system", Name: "shepherd-demo"}, &tmpl))
+	require.Len(t, tmpl.Spec.PodTemplate.Spec.Containers, 1)
+	assert.Equal(t, "runner:test", tmpl.Spec.PodTemplate.Spec.Containers[0].Image)
+
+	require.NotNil(t, fakeSrv.created)
+	assert.Equal(t, "https://github.com/org/repo", fakeSrv.created.Repo.URL)
+	assert.Equal(t, "shepherd-demo", fakeSrv.created.Runner.SandboxTemplateName)
+
+	assert.Contains(t, out.String(), "created with image runner:test")
+	assert.Contains(t, out.String(), "created task task-demo")
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("Runner started")), "unchanged status printed once")
+	assert.Contains(t, out.String(), "opened https://github.com/org/repo/pull/1")
+}
+
+func TestRun_ExistingTemplate(t *testing.T) {
+	srv := httptest.NewServer(&fakeAPI{namespace: "shepherd-system", phases: []api.TaskStatusSummary{{Phase: "Succeeded"}}})
+	defer srv.Close()
+	existing := &sandboxextv1alpha1.SandboxTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "shepherd-demo", Namespace: "shepherd-system"},
+	}
+	kube := fake.NewClientBuilder().WithScheme(Scheme()).WithObjects(existing).Build()
+
+	var out bytes.Buffer
+	require.NoError(t, Run(context.Background(), kube, shepherdclient.New(srv.URL), testOptions(&out)))
+
+	var tmpl sandboxextv1alpha1.SandboxTemplate
+	require.NoError(t, kube.Get(context.Background(), client.ObjectKeyFromObject(existing), &tmpl))
+	assert.Empty(t, tmpl.Spec.PodTemplate.Spec.Containers, "existing template left alone")
+	assert.Contains(t, out.String(), "already exists")
+}
+
+func TestRun_TaskFailed(t *testing.T) {
+	srv := httptest.NewServer(&fakeAPI{namespace: "shepherd-system", phases: []api.TaskStatusSummary{
+		{Phase: "Failed", Message: "Runner failed", Error: "clone failed"},
+	}})
+	defer srv.Close()
+	kube := fake.NewClientBuilder().WithScheme(Scheme()).Build()
+
+	var out bytes.Buffer
+	err := Run(context.Background(), kube, shepherdclient.New(srv.URL), testOptions(&out))
+	require.EqualError(t, err, "task task-demo Failed: clone failed")
+}
+
+func TestRun_NamespaceMismatch(t *testing.T) {
+	srv := httptest.NewServer(&fakeAPI{namespace: "shepherd", phases: []api.TaskStatusSummary{{Phase: "Pending"}}})
+	defer srv.Close()
+	kube := fake.NewClientBuilder().WithScheme(Scheme()).Build()
+
+	var out bytes.Buffer
+	err := Run(context.Background(), kube, shepherdclient.New(srv.URL), testOptions(&out))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rerun with --namespace shepherd")
+}
+
+func TestRun_NoWait(t *testing.T) {
+	srv := httptest.NewServer(&fakeAPI{namespace: "shepherd-system", phases: []api.TaskStatusSummary{{Phase: "Pending"}}})
+	defer srv.Close()
+	kube := fake.NewClientBuilder().WithScheme(Scheme()).Build()
+
+	var out bytes.Buffer
+	opts := testOptions(&out)
+	opts.Wait = 0
+	require.NoError(t, Run(context.Background(), kube, shepherdclient.New(srv.URL), opts))
+	assert.Contains(t, out.String(), "/api/v1/tasks/task-demo")
+}
+
+func TestRun_WaitExceeded(t *testing.T) {
+	srv := httptest.NewServer(&fakeAPI{namespace: "shepherd-system", phases: []api.TaskStatusSummary{{Phase: "Running"}}})
+	defer srv.Close()
+	kube := fake.NewClientBuilder().WithScheme(Scheme()).Build()
+
+	var out bytes.Buffer
+	opts := testOptions(&out)
+	opts.Wait = 50 * time.Millisecond
+	err := Run(context.Background(), kube, shepherdclient.New(srv.URL), opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did not finish within 50ms; it is Running")
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing sets up OpenTelemetry tracing for the Shepherd binaries
+// and carries trace context across the hops that are not HTTP requests.
+// A task's trace starts when the adapter receives the webhook, follows the
+// task creation request to the API server, is stored on the AgentTask so
+// the operator's reconciles and the TaskAssignment join it, and comes back
+// through the runner's status updates and the callbacks to the adapter.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceParentAnnotation holds the W3C traceparent of the request that
+// created an AgentTask. A tracestate, if any, is kept in
+// "shepherd.io/tracestate".
+const TraceParentAnnotation = annotationPrefix + "traceparent"
+
+const annotationPrefix = "shepherd.io/"
+
+const instrumentationName = "github.com/NissesSenap/shepherd"
+
+// Options configures Setup.
+type Options struct {
+	// ServiceName identifies the component in its spans.
+	ServiceName string
+	// Endpoint is the OTLP/HTTP endpoint spans are exported to, e.g.
+	// http://otel-collector:4318. Empty disables export; trace context is
+	// still propagated so the other components' spans stay connected.
+	Endpoint string
+	// SampleRatio is the fraction of new traces that are sampled. Traces
+	// started elsewhere follow the caller's sampling decision.
+	SampleRatio float64
+}
+
+// Setup installs the global propagator and, when opts.Endpoint is set, a
+// tracer provider exporting to it. The returned function flushes and stops
+// the exporter.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, fmt.Errorf("tracing sample ratio must be between 0 and 1, got %g", opts.SampleRatio)
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("creating trace exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", opts.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("creating trace resource: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer Shepherd's own spans are created with.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Handler wraps h so each request continues the caller's trace, or starts
+// one, in a server span named after operation.
+func Handler(h http.Handler, operation string) http.Handler {
+	return otelhttp.NewHandler(h, operation)
+}
+
+// Transport wraps base so each request is a client span and carries the
+// trace context of its request context. A nil base means
+// http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
+
+// annotationCarrier stores the trace context headers as annotations with
+// annotationPrefix.
+type annotationCarrier map[string]string
+
+func (c annotationCarrier) Get(key string) string {
+	return c[annotationPrefix+key]
+}
+
+func (c annotationCarrier) Set(key, value string) {
+	c[annotationPrefix+key] = value
+}
+
+func (c annotationCarrier) Keys() []string {
+	var keys []string
+	for k := range c {
+		if key, ok := strings.CutPrefix(k, annotationPrefix); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// InjectAnnotations records the trace context of ctx in annotations. Nothing
+// is recorded when ctx carries no trace.
+func InjectAnnotations(ctx context.Context, annotations map[string]string) {
+	propagation.TraceContext{}.Inject(ctx, annotationCarrier(annotations))
+}
+
+// FromAnnotations returns ctx with the remote trace context recorded in
+// annotations, so spans started from it join the trace of the request that
+// created the object.
+func FromAnnotations(ctx context.Context, annotations map[string]string) context.Context {
+	if annotations[TraceParentAnnotation] == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, annotationCarrier(annotations))
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx, or "" when
+// ctx carries no trace.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier["traceparent"]
+}
+
+// FromTraceParent returns ctx with the remote span described by the W3C
+// traceparent, or ctx unchanged if traceparent is empty or invalid.
+func FromTraceParent(ctx context.Context, traceparent string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceparent})
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestAnnotationsRoundTrip(t *testing.T) {
+	ctx := FromTraceParent(context.Background(), testTraceParent)
+	require.True(t, trace.SpanContextFromContext(ctx).IsValid())
+
+	annotations := map[string]string{"shepherd.io/debug": "true"}
+	InjectAnnotations(ctx, annotations)
+	assert.Equal(t, testTraceParent, annotations[TraceParentAnnotation])
+	assert.Equal(t, "true", annotations["shepherd.io/debug"])
+
+	restored := trace.SpanContextFromContext(FromAnnotations(context.Background(), annotations))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", restored.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", restored.SpanID().String())
+	assert.True(t, restored.IsRemote())
+	assert.Equal(t, testTraceParent, TraceParent(FromAnnotations(context.Background(), annotations)))
+}
+
+func TestNoTrace(t *testing.T) {
+	annotations := map[string]string{}
+	InjectAnnotations(context.Background(), annotations)
+	assert.Empty(t, annotations)
+
+	assert.Empty(t, TraceParent(context.Background()))
+	assert.False(t, trace.SpanContextFromContext(FromAnnotations(context.Background(), nil)).IsValid())
+	assert.False(t, trace.SpanContextFromContext(FromTraceParent(context.Background(), "garbage")).IsValid())
+}
+
+func TestSetup_InvalidSampleRatio(t *testing.T) {
+	_, err := Setup(context.Background(), Options{ServiceName: "test", Endpoint: "http://localhost:4318", SampleRatio: 2})
+	assert.ErrorContains(t, err, "sample ratio")
+}
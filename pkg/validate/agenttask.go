@@ -0,0 +1,172 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validate holds the AgentTask checks that go beyond the CRD
+// schema. The API server runs them on create requests and the operator's
+// admission webhook runs them on tasks applied directly to the cluster, so
+// both paths give the same guarantees.
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	toolkitv1alpha1 "github.com/NissesSenap/shepherd/api/v1alpha1"
+)
+
+// Bounds of spec.runner.timeout. A zero timeout is allowed and means the
+// operator's default.
+const (
+	MinTimeout = time.Minute
+	MaxTimeout = 24 * time.Hour
+)
+
+// maxRunnerNameLength bounds spec.runner.model and spec.runner.branchPrefix,
+// matching the CRD validation.
+const maxRunnerNameLength = 100
+
+var (
+	// modelRegex matches model names such as "claude-sonnet-4-5" or
+	// "us.anthropic.claude-opus-4-1-v1:0", as the CRD pattern does.
+	modelRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:@/-]*$`)
+	// branchPrefixRegex is the CRD pattern of spec.runner.branchPrefix.
+	branchPrefixRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_./-]*$`)
+)
+
+// Options configures the checks that depend on the installation.
+type Options struct {
+	// AllowedSandboxTemplates limits the sandbox templates tasks may use.
+	// Empty allows any template.
+	AllowedSandboxTemplates []string
+}
+
+// RepoURL checks that a repository URL uses HTTPS.
+func RepoURL(raw string) error {
+	if !strings.HasPrefix(raw, "https://") {
+		return fmt.Errorf("must start with https://")
+	}
+	return nil
+}
+
+// CallbackURL checks that a callback URL is an http(s) URL whose host is
+// not one of DefaultDeniedCallbackHosts.
+func CallbackURL(raw string) error {
+	host, err := callbackHost(raw)
+	if err != nil {
+		return err
+	}
+	if defaultDeniedCallbackHosts.Match(host) {
+		return fmt.Errorf("host %q is blocked", host)
+	}
+	return nil
+}
+
+// callbackHost returns the host name of an http(s) callback URL.
+func callbackHost(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("scheme must be http or https")
+	}
+	host := u.Hostname()
+	if host == "" {
+		return "", fmt.Errorf("hostname is empty")
+	}
+	return host, nil
+}
+
+// Timeout checks that a runner timeout is zero or within MinTimeout and
+// MaxTimeout.
+func Timeout(d time.Duration) error {
+	if d == 0 {
+		return nil
+	}
+	if d < MinTimeout || d > MaxTimeout {
+		return fmt.Errorf("must be between %s and %s, got %s", MinTimeout, MaxTimeout, d)
+	}
+	return nil
+}
+
+// Model checks a runner model name; empty means the runner's default.
+func Model(name string) error {
+	if name == "" {
+		return nil
+	}
+	if len(name) > maxRunnerNameLength || !modelRegex.MatchString(name) {
+		return fmt.Errorf("must be at most %d letters, digits, '.', '_', ':', '@', '/' or '-', starting with a letter or digit",
+			maxRunnerNameLength)
+	}
+	return nil
+}
+
+// BranchPrefix checks that a task name appended to prefix makes a valid
+// git branch name; empty means the default prefix.
+func BranchPrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	if len(prefix) > maxRunnerNameLength || !branchPrefixRegex.MatchString(prefix) ||
+		strings.Contains(prefix, "..") || strings.Contains(prefix, "//") ||
+		strings.Contains(prefix, "/.") || strings.Contains(prefix, ".lock/") {
+		return fmt.Errorf("%q cannot start a git branch name", prefix)
+	}
+	return nil
+}
+
+// SandboxTemplate checks that name is one of the allowed templates.
+func (o Options) SandboxTemplate(name string) error {
+	if len(o.AllowedSandboxTemplates) == 0 || slices.Contains(o.AllowedSandboxTemplates, name) {
+		return nil
+	}
+	return fmt.Errorf("sandbox template %q is not allowed, must be one of %s",
+		name, strings.Join(o.AllowedSandboxTemplates, ", "))
+}
+
+// AgentTask runs every check on the spec of task.
+func (o Options) AgentTask(task *toolkitv1alpha1.AgentTask) field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	if err := RepoURL(task.Spec.Repo.URL); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("repo", "url"), task.Spec.Repo.URL, err.Error()))
+	}
+	if err := CallbackURL(task.Spec.Callback.URL); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("callback", "url"), task.Spec.Callback.URL, err.Error()))
+	}
+	runner := spec.Child("runner")
+	if err := Timeout(task.Spec.Runner.Timeout.Duration); err != nil {
+		errs = append(errs, field.Invalid(runner.Child("timeout"), task.Spec.Runner.Timeout.String(), err.Error()))
+	}
+	if err := Model(task.Spec.Runner.Model); err != nil {
+		errs = append(errs, field.Invalid(runner.Child("model"), task.Spec.Runner.Model, err.Error()))
+	}
+	if err := BranchPrefix(task.Spec.Runner.BranchPrefix); err != nil {
+		errs = append(errs, field.Invalid(runner.Child("branchPrefix"), task.Spec.Runner.BranchPrefix, err.Error()))
+	}
+	if err := o.SandboxTemplate(task.Spec.Runner.SandboxTemplateName); err != nil {
+		errs = append(errs, field.NotSupported(runner.Child("sandboxTemplateName"),
+			task.Spec.Runner.SandboxTemplateName, o.AllowedSandboxTemplates))
+	}
+	return errs
+}
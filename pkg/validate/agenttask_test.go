@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallbackURL(t *testing.T) {
+	tests := []struct {
+		url    string
+		errMsg string
+	}{
+		{"https://adapter.example.com/callback", ""},
+		{"http://shepherd-github.shepherd:8080/callback", ""},
+		{"ftp://example.com/callback", "scheme must be http or https"},
+		{"http:///callback", "hostname is empty"},
+		{"http://169.254.169.254/latest", `host "169.254.169.254" is blocked`},
+		{"http://localhost:8080/callback", `host "localhost" is blocked`},
+		{"http://[::1]:8080/callback", `host "::1" is blocked`},
+		{"http://exa mple.com", "invalid character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := CallbackURL(tt.url)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		valid   bool
+	}{
+		{0, true},
+		{time.Minute, true},
+		{30 * time.Minute, true},
+		{24 * time.Hour, true},
+		{30 * time.Second, false},
+		{25 * time.Hour, false},
+		{-time.Minute, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timeout.String(), func(t *testing.T) {
+			assert.Equal(t, tt.valid, Timeout(tt.timeout) == nil)
+		})
+	}
+}
+
+func TestModel(t *testing.T) {
+	for _, name := range []string{"", "claude-sonnet-4-5", "us.anthropic.claude-opus-4-1-v1:0", "opus"} {
+		assert.NoError(t, Model(name), name)
+	}
+	for _, name := range []string{"-opus", "claude sonnet", "opus;rm", strings.Repeat("a", 101)} {
+		assert.Error(t, Model(name), name)
+	}
+}
+
+func TestBranchPrefix(t *testing.T) {
+	for _, prefix := range []string{"", "shepherd/", "bot/ai-", "team_x/shepherd-"} {
+		assert.NoError(t, BranchPrefix(prefix), prefix)
+	}
+	for _, prefix := range []string{"/bot", "-bot/", "bot//", "bot/../x/", "bot/.hidden/", "bot.lock/", "bot~1/", "bot /"} {
+		assert.Error(t, BranchPrefix(prefix), prefix)
+	}
+}
+
+func TestRepoURL(t *testing.T) {
+	assert.NoError(t, RepoURL("https://github.com/acme/app"))
+	assert.Error(t, RepoURL("http://github.com/acme/app"))
+	assert.Error(t, RepoURL("git@github.com:acme/app.git"))
+}
+
+func TestOptions_SandboxTemplate(t *testing.T) {
+	assert.NoError(t, Options{}.SandboxTemplate("anything"))
+
+	o := Options{AllowedSandboxTemplates: []string{"default", "large"}}
+	assert.NoError(t, o.SandboxTemplate("large"))
+	assert.ErrorContains(t, o.SandboxTemplate("gpu"), `sandbox template "gpu" is not allowed, must be one of default, large`)
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// DefaultDeniedCallbackHosts are the hosts a callback is never sent to
+// unless configured otherwise: the cloud metadata endpoint and the loopback
+// addresses of the API pod.
+var DefaultDeniedCallbackHosts = []string{"169.254.169.254", "localhost", "127.0.0.1", "::1", "0.0.0.0"}
+
+var defaultDeniedCallbackHosts = mustHostPatterns(DefaultDeniedCallbackHosts)
+
+// sharedAddressSpace is the carrier-grade NAT range, which some clusters
+// use for pods and services.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// HostPatterns matches host names against a list of patterns. A pattern is
+// a host name (adapter.example.com) or a wildcard for all of a domain's
+// subdomains (*.svc.cluster.local). Matching ignores case and a trailing
+// dot.
+type HostPatterns struct {
+	hosts    map[string]bool
+	suffixes []string
+}
+
+// NewHostPatterns parses patterns. Empty patterns are ignored.
+func NewHostPatterns(patterns []string) (HostPatterns, error) {
+	p := HostPatterns{hosts: make(map[string]bool)}
+	for _, pattern := range patterns {
+		pattern = normalizeHost(strings.TrimSpace(pattern))
+		switch {
+		case pattern == "":
+		case strings.HasPrefix(pattern, "*."):
+			if strings.Contains(pattern[2:], "*") || len(pattern) == 2 {
+				return HostPatterns{}, fmt.Errorf("invalid host pattern %q", pattern)
+			}
+			p.suffixes = append(p.suffixes, pattern[1:])
+		case strings.Contains(pattern, "*"):
+			return HostPatterns{}, fmt.Errorf("invalid host pattern %q: * is only allowed as the first label", pattern)
+		default:
+			p.hosts[strings.Trim(pattern, "[]")] = true
+		}
+	}
+	return p, nil
+}
+
+func mustHostPatterns(patterns []string) HostPatterns {
+	p, err := NewHostPatterns(patterns)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Empty reports whether p has no patterns.
+func (p HostPatterns) Empty() bool {
+	return len(p.hosts) == 0 && len(p.suffixes) == 0
+}
+
+// Match reports whether host matches one of the patterns.
+func (p HostPatterns) Match(host string) bool {
+	host = normalizeHost(host)
+	if p.hosts[host] {
+		return true
+	}
+	for _, suffix := range p.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// CallbackTargetOptions configures CallbackTargets.
+type CallbackTargetOptions struct {
+	// Allowed limits callbacks to hosts matching these patterns. Empty
+	// allows any host.
+	Allowed []string
+	// Denied are host patterns callbacks are never sent to. Nil means
+	// DefaultDeniedCallbackHosts.
+	Denied []string
+	// Internal are host patterns and CIDR ranges callbacks may reach
+	// although they resolve to internal addresses, such as in-cluster
+	// adapters.
+	Internal []string
+}
+
+// CallbackTargets decides which hosts and addresses callbacks may reach.
+// Besides the allow and deny lists, callback hosts are resolved and
+// rejected when any of their addresses is loopback, private, link-local,
+// multicast or unspecified, unless the host or address is listed as
+// internal. A nil CallbackTargets only runs the CallbackURL check.
+type CallbackTargets struct {
+	allowed          HostPatterns
+	denied           HostPatterns
+	internal         HostPatterns
+	internalPrefixes []netip.Prefix
+	dialer           net.Dialer
+	// lookup resolves a host name; tests replace it.
+	lookup func(ctx context.Context, host string) ([]netip.Addr, error)
+}
+
+// NewCallbackTargets returns the CallbackTargets configured by opts.
+func NewCallbackTargets(opts CallbackTargetOptions) (*CallbackTargets, error) {
+	t := &CallbackTargets{
+		lookup: func(ctx context.Context, host string) ([]netip.Addr, error) {
+			return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		},
+	}
+	var err error
+	if t.allowed, err = NewHostPatterns(opts.Allowed); err != nil {
+		return nil, fmt.Errorf("allowed callback hosts: %w", err)
+	}
+	denied := opts.Denied
+	if denied == nil {
+		denied = DefaultDeniedCallbackHosts
+	}
+	if t.denied, err = NewHostPatterns(denied); err != nil {
+		return nil, fmt.Errorf("denied callback hosts: %w", err)
+	}
+	var internalHosts []string
+	for _, entry := range opts.Internal {
+		if !strings.Contains(entry, "/") {
+			internalHosts = append(internalHosts, entry)
+			continue
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, fmt.Errorf("internal callback hosts: invalid range %q: %w", entry, err)
+		}
+		t.internalPrefixes = append(t.internalPrefixes, prefix.Masked())
+	}
+	if t.internal, err = NewHostPatterns(internalHosts); err != nil {
+		return nil, fmt.Errorf("internal callback hosts: %w", err)
+	}
+	return t, nil
+}
+
+// Check checks the host of the callback URL raw against the allow and deny
+// lists and every address it resolves to.
+func (t *CallbackTargets) Check(ctx context.Context, raw string) error {
+	if t == nil {
+		return CallbackURL(raw)
+	}
+	host, err := callbackHost(raw)
+	if err != nil {
+		return err
+	}
+	_, err = t.resolve(ctx, host)
+	return err
+}
+
+// DialContext dials address after checking its host and the addresses it
+// resolves to, and connects to one of those checked addresses. Checking at
+// dial time rather than only when the task is created means a host that
+// later starts resolving to an internal address (DNS rebinding) is still
+// refused.
+func (t *CallbackTargets) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := t.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if addrs == nil {
+		return t.dialer.DialContext(ctx, network, address)
+	}
+	var errs []error
+	for _, addr := range addrs {
+		conn, err := t.dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// resolve checks host and returns its addresses once all of them are
+// checked. It returns nil addresses for an internal host name, which is
+// dialed as is.
+func (t *CallbackTargets) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	if t.denied.Match(host) {
+		return nil, fmt.Errorf("host %q is blocked", host)
+	}
+	if !t.allowed.Empty() && !t.allowed.Match(host) {
+		return nil, fmt.Errorf("host %q is not an allowed callback host", host)
+	}
+	if t.internal.Match(host) {
+		return nil, nil
+	}
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		addrs, err = t.lookup(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("resolving host %q: %w", host, err)
+		}
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("host %q has no addresses", host)
+		}
+	}
+	for _, addr := range addrs {
+		if !t.addrAllowed(addr) {
+			return nil, fmt.Errorf("host %q resolves to blocked address %s", host, addr)
+		}
+	}
+	return addrs, nil
+}
+
+func (t *CallbackTargets) addrAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range t.internalPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return !blockedAddr(addr)
+}
+
+// blockedAddr reports whether addr is internal to the cluster, the node or
+// the cloud provider, and so must not be reached by a callback.
+func blockedAddr(addr netip.Addr) bool {
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() ||
+		sharedAddressSpace.Contains(addr)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTargets returns CallbackTargets that resolve names from records.
+func newTestTargets(t *testing.T, opts CallbackTargetOptions, records map[string][]string) *CallbackTargets {
+	t.Helper()
+	targets, err := NewCallbackTargets(opts)
+	require.NoError(t, err)
+	targets.lookup = func(_ context.Context, host string) ([]netip.Addr, error) {
+		ips, ok := records[host]
+		if !ok {
+			return nil, fmt.Errorf("no such host")
+		}
+		var addrs []netip.Addr
+		for _, ip := range ips {
+			addrs = append(addrs, netip.MustParseAddr(ip))
+		}
+		return addrs, nil
+	}
+	return targets
+}
+
+func TestCallbackTargets_Check(t *testing.T) {
+	targets := newTestTargets(t, CallbackTargetOptions{
+		Internal: []string{"shepherd-github.shepherd", "*.svc.cluster.local", "203.0.113.0/24"},
+	},
+		map[string][]string{
+			"adapter.example.com":      {"93.184.216.34"},
+			"internal.example.com":     {"93.184.216.34", "10.0.0.7"},
+			"metadata.example.com":     {"169.254.169.254"},
+			"mapped.example.com":       {"::ffff:192.168.1.1"},
+			"multicast.example.com":    {"224.0.0.1"},
+			"cgnat.example.com":        {"100.64.1.1"},
+			"shepherd-github.shepherd": {"10.96.0.12"},
+		})
+
+	tests := []struct {
+		url    string
+		errMsg string
+	}{
+		{"https://adapter.example.com/callback", ""},
+		{"http://shepherd-github.shepherd:8082/callback", ""},
+		{"http://SHEPHERD-GITHUB.shepherd.:8082/callback", ""},
+		{"http://adapter.shepherd.svc.cluster.local:8082/callback", ""},
+		{"http://203.0.113.9/callback", ""},
+		{"http://internal.example.com/callback", `host "internal.example.com" resolves to blocked address 10.0.0.7`},
+		{"http://metadata.example.com/latest", "blocked address 169.254.169.254"},
+		{"http://mapped.example.com/callback", "blocked address ::ffff:192.168.1.1"},
+		{"http://multicast.example.com/callback", "blocked address 224.0.0.1"},
+		{"http://cgnat.example.com/callback", "blocked address 100.64.1.1"},
+		{"http://10.1.2.3/callback", "blocked address 10.1.2.3"},
+		{"http://[fd00::1]/callback", "blocked address fd00::1"},
+		{"http://unknown.example.com/callback", `resolving host "unknown.example.com"`},
+		{"http://localhost/callback", `host "localhost" is blocked`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := targets.Check(context.Background(), tt.url)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestCallbackTargets_NilOnlyChecksURL(t *testing.T) {
+	var targets *CallbackTargets
+	assert.NoError(t, targets.Check(context.Background(), "http://10.0.0.7/callback"))
+	assert.ErrorContains(t, targets.Check(context.Background(), "http://localhost/callback"), "is blocked")
+}
+
+func TestCallbackTargets_AllowAndDenyLists(t *testing.T) {
+	records := map[string][]string{
+		"adapter.mycorp.com":                 {"93.184.216.34"},
+		"hooks.example.com":                  {"93.184.216.34"},
+		"old-adapter.mycorp.com":             {"93.184.216.34"},
+		"adapter.shepherd.svc.cluster.local": {"10.96.0.12"},
+	}
+	targets := newTestTargets(t, CallbackTargetOptions{
+		Allowed:  []string{"*.svc.cluster.local", "*.mycorp.com"},
+		Denied:   []string{"old-adapter.mycorp.com"},
+		Internal: []string{"*.svc.cluster.local"},
+	}, records)
+
+	tests := []struct {
+		url    string
+		errMsg string
+	}{
+		{"https://adapter.mycorp.com/callback", ""},
+		{"http://adapter.shepherd.svc.cluster.local:8082/callback", ""},
+		{"https://hooks.example.com/callback", `host "hooks.example.com" is not an allowed callback host`},
+		{"https://mycorp.com/callback", `host "mycorp.com" is not an allowed callback host`},
+		{"https://OLD-ADAPTER.mycorp.com/callback", `host "OLD-ADAPTER.mycorp.com" is blocked`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := targets.Check(context.Background(), tt.url)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestHostPatterns(t *testing.T) {
+	p, err := NewHostPatterns([]string{"Adapter.Example.com", "*.svc.cluster.local", "[::1]", " "})
+	require.NoError(t, err)
+	assert.True(t, p.Match("adapter.example.com."))
+	assert.True(t, p.Match("github.shepherd.svc.cluster.local"))
+	assert.True(t, p.Match("::1"))
+	assert.False(t, p.Match("svc.cluster.local"))
+	assert.False(t, p.Match("other.example.com"))
+	assert.False(t, p.Empty())
+
+	p, err = NewHostPatterns(nil)
+	require.NoError(t, err)
+	assert.True(t, p.Empty())
+
+	for _, bad := range []string{"*", "*.", "adapter.*.com", "*.*.com"} {
+		_, err := NewHostPatterns([]string{bad})
+		assert.ErrorContains(t, err, "invalid host pattern", bad)
+	}
+}
+
+func TestNewCallbackTargets_Invalid(t *testing.T) {
+	_, err := NewCallbackTargets(CallbackTargetOptions{Internal: []string{"10.0.0.0/33"}})
+	assert.ErrorContains(t, err, `internal callback hosts: invalid range "10.0.0.0/33"`)
+
+	_, err = NewCallbackTargets(CallbackTargetOptions{Allowed: []string{"hooks.*"}})
+	assert.ErrorContains(t, err, "allowed callback hosts: invalid host pattern")
+}
+
+func TestCallbackTargets_DialContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(u.Host)
+	require.NoError(t, err)
+
+	// rebind.example.com passed the check when the task was created and now
+	// resolves to the loopback address the test server listens on.
+	targets := newTestTargets(t, CallbackTargetOptions{}, map[string][]string{"rebind.example.com": {"127.0.0.1"}})
+	client := &http.Client{Transport: &http.Transport{DialContext: targets.DialContext}}
+
+	_, err = client.Get("http://rebind.example.com:" + port + "/")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "blocked address 127.0.0.1")
+
+	// Allowing the range lets the connection through to the checked address.
+	targets = newTestTargets(t, CallbackTargetOptions{Internal: []string{"127.0.0.0/8"}},
+		map[string][]string{"rebind.example.com": {"127.0.0.1"}})
+	client = &http.Client{Transport: &http.Transport{DialContext: targets.DialContext}}
+	resp, err := client.Get("http://rebind.example.com:" + port + "/")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
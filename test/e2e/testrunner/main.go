@@ -203,7 +203,7 @@ func newMux(assigned chan<- TaskAssignment) *http.ServeMux {
 		select {
 		case assigned <- ta:
 			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(`{"status":"accepted"}`))
+			_, _ = w.Write([]byte(`{"status":"accepted","protocolVersion":1}`))
 		default:
 			http.Error(w, "task already assigned", http.StatusConflict)
 		}
@@ -28,7 +28,12 @@ export interface paths {
 			path?: never;
 			cookie?: never;
 		};
-		/** Readiness probe */
+		/**
+		 * Readiness probe
+		 * @description Reports the status of each dependency the API server needs. Failing
+		 *     non-critical dependencies mark the server degraded but keep it ready.
+		 *
+		 */
 		get: operations["readyz"];
 		put?: never;
 		post?: never;
@@ -56,6 +61,79 @@ export interface paths {
 		patch?: never;
 		trace?: never;
 	};
+	"/api/v1/tasks/search": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		/**
+		 * Search tasks by text
+		 * @description Case-insensitive substring search over task ID, description, repository
+		 *     URL, requesting user and PR URL. Every whitespace-separated term in q
+		 *     must match one of these fields. Results are ordered newest first.
+		 *
+		 */
+		get: operations["searchTasks"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/tasks/active": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		/**
+		 * Get the active task of a source
+		 * @description Returns the newest task created for sourceURL, such as an issue URL,
+		 *     that has not finished. Adapters use this to skip duplicate triggers.
+		 *
+		 */
+		get: operations["getActiveTask"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/tasks/watch": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		/**
+		 * Watch the task list via WebSocket
+		 * @description Upgrades to a WebSocket connection that keeps a task list up to date
+		 *     without polling. The server sends WSMessage JSON messages whose data
+		 *     is a TaskResponse: first a "task_added" message for every matching
+		 *     task, then a "tasks_synced" message without data, then a
+		 *     "task_added", "task_updated" or "task_deleted" message for every
+		 *     change to a matching task. A task may be sent more than once. A
+		 *     client that falls behind is disconnected with status 1008 and
+		 *     should reconnect to get a fresh list.
+		 *
+		 */
+		get: operations["watchTasks"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
 	"/api/v1/tasks/{taskID}": {
 		parameters: {
 			query?: never;
@@ -73,31 +151,47 @@ export interface paths {
 		patch?: never;
 		trace?: never;
 	};
-	"/api/v1/tasks/{taskID}/events": {
+	"/api/v1/tasks/{taskID}/notes": {
 		parameters: {
 			query?: never;
 			header?: never;
 			path?: never;
 			cookie?: never;
 		};
+		get?: never;
+		put?: never;
 		/**
-		 * Stream task events via WebSocket
-		 * @description Upgrades to a WebSocket connection for real-time event streaming.
-		 *     The server sends JSON messages of type WSMessage with "task_event"
-		 *     or "task_complete" types. Use the ?after query parameter to resume
-		 *     from a specific sequence number after reconnection.
+		 * Attach a note to a task
+		 * @description Stores a comment from a person with the task, such as why it was retried or which PR superseded it. Notes are returned with the task, oldest first.
 		 */
-		get: operations["streamEvents"];
+		post: operations["addTaskNote"];
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/tasks/{taskID}/extend": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		get?: never;
 		put?: never;
-		/** Post agent events for a task (runner → API) */
-		post: operations["postEvents"];
+		/**
+		 * Extend the timeout of a running task
+		 * @description Adds time to an unfinished task's timeout and moves its sandbox's shutdown time out with it. The extensions of a task may add up to the API server's --max-timeout-extension. The timeout warning is cleared, so the adapter is warned again before the new deadline.
+		 */
+		post: operations["extendTaskTimeout"];
 		delete?: never;
 		options?: never;
 		head?: never;
 		patch?: never;
 		trace?: never;
 	};
-	"/api/v1/tasks/{taskID}/status": {
+	"/api/v1/tasks/{taskID}/cancel": {
 		parameters: {
 			query?: never;
 			header?: never;
@@ -106,40 +200,49 @@ export interface paths {
 		};
 		get?: never;
 		put?: never;
-		/** Update task status (runner callback) */
-		post: operations["updateTaskStatus"];
+		/**
+		 * Cancel an unfinished task
+		 * @description Marks the task Cancelled. The operator releases its sandbox and the adapter that created it receives a "cancelled" callback. The task's message is "Cancelled through the API", followed by the reason if one is given.
+		 */
+		post: operations["cancelTask"];
 		delete?: never;
 		options?: never;
 		head?: never;
 		patch?: never;
 		trace?: never;
 	};
-	"/api/v1/tasks/{taskID}/data": {
+	"/api/v1/tasks/{taskID}/retry": {
 		parameters: {
 			query?: never;
 			header?: never;
 			path?: never;
 			cookie?: never;
 		};
-		/** Get task data for runner */
-		get: operations["getTaskData"];
+		get?: never;
 		put?: never;
-		post?: never;
+		/**
+		 * Retry a failed, timed out or cancelled task
+		 * @description Creates a new task with the spec and labels of the finished task, labeled shepherd.io/retry-of with its ID. The retry goes through the same maintenance, quota and policy checks as a created task, and gets its own correlation ID.
+		 */
+		post: operations["retryTask"];
 		delete?: never;
 		options?: never;
 		head?: never;
 		patch?: never;
 		trace?: never;
 	};
-	"/api/v1/tasks/{taskID}/token": {
+	"/api/v1/tasks/{taskID}/callbacks": {
 		parameters: {
 			query?: never;
 			header?: never;
 			path?: never;
 			cookie?: never;
 		};
-		/** Get GitHub installation token scoped to task repo */
-		get: operations["getTaskToken"];
+		/**
+		 * Get the delivery history of a task's terminal callback
+		 * @description Lists the latest 20 attempts to send the task's terminal callback to its adapter, with the status code or error of each, oldest first.
+		 */
+		get: operations["getTaskCallbacks"];
 		put?: never;
 		post?: never;
 		delete?: never;
@@ -148,76 +251,509 @@ export interface paths {
 		patch?: never;
 		trace?: never;
 	};
-}
-export type webhooks = Record<string, never>;
-export interface components {
-	schemas: {
-		CreateTaskRequest: {
-			repo: components["schemas"]["RepoRequest"];
-			task: components["schemas"]["TaskRequest"];
-			/** Format: uri */
-			callbackURL: string;
-			runner: components["schemas"]["RunnerConfig"];
-			labels?: {
-				[key: string]: string;
-			};
-		};
-		RepoRequest: {
-			/** Format: uri */
-			url: string;
-			ref?: string;
+	"/api/v1/tasks/{taskID}/callbacks/replay": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
 		};
-		TaskRequest: {
-			description: string;
-			context?: string;
-			sourceURL?: string;
-			sourceType?: string;
-			sourceID?: string;
+		get?: never;
+		put?: never;
+		/**
+		 * Send a finished task's terminal callback again
+		 * @description Sends the callback announcing how the task finished, also if it was delivered before, and records the attempt. A delivered replay marks the task notified and ends the automatic retries.
+		 */
+		post: operations["replayTaskCallback"];
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/fleets/{fleetID}": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
 		};
-		RunnerConfig: {
-			sandboxTemplateName: string;
-			timeout?: string;
-			serviceAccountName?: string;
+		/**
+		 * Get a task fleet and the results of its tasks
+		 * @description A fleet runs one task in many repositories. The response aggregates the fleet's child tasks; list them in full with GET /api/v1/tasks?fleet={fleetID}.
+		 */
+		get: operations["getFleet"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/archive/tasks/{taskID}": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
 		};
-		TaskResponse: {
-			id: string;
-			namespace: string;
-			repo: components["schemas"]["RepoRequest"];
-			task: components["schemas"]["TaskRequest"];
-			callbackURL: string;
-			status: components["schemas"]["TaskStatusSummary"];
-			/** Format: date-time */
-			createdAt: string;
-			/** Format: date-time */
-			completionTime?: string | null;
+		/**
+		 * Get the archived record of a finished task
+		 * @description Reads the record written to the archive when the task finished, which is kept after the AgentTask itself was deleted. Only available when the API server is configured with an archive bucket.
+		 */
+		get: operations["getArchivedTask"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/task-templates": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
 		};
-		TaskStatusSummary: {
-			phase: string;
-			message: string;
-			sandboxClaimName?: string;
-			prURL?: string;
-			error?: string;
+		/** List task templates */
+		get: operations["listTaskTemplates"];
+		put?: never;
+		/** Create a reusable task template */
+		post: operations["createTaskTemplate"];
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/task-templates/{templateName}": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
 		};
-		StatusUpdateRequest: {
-			/** @enum {string} */
-			event: "started" | "progress" | "completed" | "failed";
-			message?: string;
-			details?: {
-				[key: string]: unknown;
-			};
+		/** Get a task template */
+		get: operations["getTaskTemplate"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/admin/tasks/cancel": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
 		};
-		StatusAcceptedResponse: {
-			status: string;
-			note?: string;
+		get?: never;
+		put?: never;
+		/**
+		 * Cancel all unfinished tasks matching a label selector
+		 * @description Marks the matching tasks Cancelled. The operator releases their sandboxes and adapters receive a "cancelled" callback. Finished tasks are left alone. Only served when the API server runs with --admin-api.
+		 */
+		post: operations["cancelTasks"];
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/admin/tasks": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
 		};
-		TaskDataResponse: {
-			description: string;
-			context: string;
-			sourceURL?: string;
-			repo: components["schemas"]["RepoRequest"];
+		get?: never;
+		put?: never;
+		post?: never;
+		/**
+		 * Delete finished tasks matching a label selector
+		 * @description Deletes the matching tasks that have finished, optionally only those that finished at least olderThan ago. Unfinished tasks are left alone; cancel them first. Only served when the API server runs with --admin-api.
+		 */
+		delete: operations["deleteTasks"];
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/maintenance": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
 		};
-		TokenResponse: {
-			token: string;
+		/**
+		 * Get the maintenance state
+		 * @description Reports whether Shepherd is under maintenance, so adapters and the UI can announce it before new tasks are rejected.
+		 */
+		get: operations["getMaintenance"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/callback-signing-key": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		/**
+		 * Get the public key callbacks are signed with
+		 * @description Publishes the Ed25519 public key of the callback signatures, so adapters can verify callbacks without sharing the HMAC secret. Only available when the API server runs with --callback-signing-key.
+		 */
+		get: operations["getCallbackSigningKey"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/admin/maintenance": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		get?: never;
+		/**
+		 * Start maintenance or update its message
+		 * @description While under maintenance the API rejects new tasks with 503. Tasks that are already running are not affected. Only served when the API server runs with --admin-api.
+		 */
+		put: operations["setMaintenance"];
+		post?: never;
+		/** End maintenance */
+		delete: operations["endMaintenance"];
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/tasks/{taskID}/events": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		/**
+		 * Stream task events via WebSocket
+		 * @description Upgrades to a WebSocket connection for real-time event streaming.
+		 *     The server sends JSON messages of type WSMessage: a "task_event"
+		 *     message for every buffered event, then an "events_synced" message
+		 *     without data, then a "task_event" message for every new event and
+		 *     finally a "task_complete" message. Use the ?after query parameter to
+		 *     resume from a specific sequence number after reconnection.
+		 */
+		get: operations["streamEvents"];
+		put?: never;
+		/**
+		 * Post agent events for a task (runner → API)
+		 * @description Events are validated against the TaskEvent schema version named in
+		 *     X-Shepherd-Event-Schema and translated to the current version, so
+		 *     runners built against an older schema keep working.
+		 *
+		 */
+		post: operations["postEvents"];
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/tasks/{taskID}/status": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		get?: never;
+		put?: never;
+		/** Update task status (runner callback) */
+		post: operations["updateTaskStatus"];
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/tasks/{taskID}/data": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		/** Get task data for runner */
+		get: operations["getTaskData"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/tasks/{taskID}/token": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		/** Get GitHub installation token scoped to task repo */
+		get: operations["getTaskToken"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+	"/api/v1/event-schemas": {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		/** List the TaskEvent schema versions the API accepts */
+		get: operations["getEventSchemas"];
+		put?: never;
+		post?: never;
+		delete?: never;
+		options?: never;
+		head?: never;
+		patch?: never;
+		trace?: never;
+	};
+}
+export type webhooks = Record<string, never>;
+export interface components {
+	schemas: {
+		CreateTaskRequest: {
+			repo: components["schemas"]["RepoRequest"];
+			task: components["schemas"]["TaskRequest"];
+			/** Format: uri */
+			callbackURL: string;
+			/** @description Required unless templateRef names a task template. */
+			runner?: components["schemas"]["RunnerConfig"];
+			labels?: {
+				[key: string]: string;
+			};
+			/**
+			 * Format: int32
+			 * @description Admission order when the operator's concurrency limit is reached.
+			 *     Higher values are admitted first.
+			 */
+			priority?: number;
+			/**
+			 * @description IDs of tasks that must succeed before this task starts. If one of
+			 *     them fails, times out or is cancelled, this task fails too.
+			 */
+			dependsOn?: string[];
+			/**
+			 * @description Format of the task's callbacks: json for CallbackPayload, or
+			 *     cloudevents for a CloudEvents 1.0 event in structured JSON with
+			 *     the CallbackPayload as data. Defaults to the API server's
+			 *     --callback-format.
+			 * @enum {string}
+			 */
+			callbackFormat?: "json" | "cloudevents";
+			/**
+			 * @description Name of a task template supplying defaults. Runner fields, labels
+			 *     and priority set in the request take precedence; the template's
+			 *     context is prepended to task.context.
+			 */
+			templateRef?: string;
+		};
+		RepoRequest: {
+			/** Format: uri */
+			url: string;
+			ref?: string;
+		};
+		TaskRequest: {
+			description: string;
+			context?: string;
+			sourceURL?: string;
+			sourceType?: string;
+			sourceID?: string;
+		};
+		RunnerConfig: {
+			sandboxTemplateName: string;
+			timeout?: string;
+			serviceAccountName?: string;
+			/** @description Model the runner's agent works with; the runner image's default if omitted */
+			model?: string;
+			/** @description Prefix of the branch the runner pushes to, followed by the task's ID; "shepherd/" if omitted */
+			branchPrefix?: string;
+		};
+		TaskResponse: {
+			id: string;
+			namespace: string;
+			repo: components["schemas"]["RepoRequest"];
+			task: components["schemas"]["TaskRequest"];
+			callbackURL: string;
+			/** @description GitHub login of the user who requested the task, if known */
+			requestedBy?: string;
+			/** @description ID the task's log lines carry in every component, as correlation_id */
+			correlationID?: string;
+			/** Format: int32 */
+			priority?: number;
+			dependsOn?: string[];
+			status: components["schemas"]["TaskStatusSummary"];
+			/** Format: date-time */
+			createdAt: string;
+			/** Format: date-time */
+			completionTime?: string | null;
+			/**
+			 * Format: int64
+			 * @description Seconds from creation until a runner started the task. For a task that has not started, counts up to now or to completion.
+			 */
+			queuedSeconds: number;
+			/**
+			 * Format: int64
+			 * @description Seconds from start until completion, or until now while running. Zero if the task never started.
+			 */
+			runningSeconds: number;
+			/**
+			 * Format: int64
+			 * @description Seconds from creation until completion, or until now.
+			 */
+			totalSeconds: number;
+			/** @description Notes people attached to the task, oldest first. */
+			notes?: components["schemas"]["NoteResponse"][];
+			/** @description Timeout extensions granted to the task, oldest first. */
+			timeoutExtensions?: components["schemas"]["TimeoutExtensionResponse"][];
+		};
+		CreateNoteRequest: {
+			/** @description Who wrote the note. */
+			author?: string;
+			text: string;
+		};
+		NoteResponse: {
+			author?: string;
+			text: string;
+			/** Format: date-time */
+			createdAt: string;
+		};
+		ExtendTimeoutRequest: {
+			/** @description Time to add to the timeout, as a Go duration such as 30m. */
+			duration: string;
+			reason?: string;
+		};
+		TimeoutExtensionResponse: {
+			duration: string;
+			reason?: string;
+			/** Format: date-time */
+			createdAt: string;
+		};
+		TaskStatusSummary: {
+			/**
+			 * @description The task's status.phase, as shown by kubectl get agenttasks.
+			 * @enum {string}
+			 */
+			phase: "Pending" | "Provisioning" | "Running" | "Succeeded" | "Failed" | "TimedOut" | "Cancelled";
+			message: string;
+			sandboxClaimName?: string;
+			/** @description Template the task's sandbox was claimed from, after operator template routes. */
+			sandboxTemplateName?: string;
+			prURL?: string;
+			error?: string;
+			/**
+			 * Format: double
+			 * @description Model cost of the run in USD, as reported by the runner.
+			 */
+			costUSD?: number;
+			/** @description The runner's short description of what it changed and why, also used as the PR body. */
+			summary?: string;
+			/**
+			 * Format: date-time
+			 * @description When the runner's timeout expires; set once the task has started.
+			 */
+			deadline?: string;
+			/**
+			 * Format: int64
+			 * @description Seconds until the deadline; set while a started task has not finished.
+			 */
+			remainingSeconds?: number;
+		};
+		StatusUpdateRequest: {
+			/** @enum {string} */
+			event: "started" | "progress" | "completed" | "failed";
+			message?: string;
+			details?: {
+				[key: string]: unknown;
+			};
+		};
+		StatusAcceptedResponse: {
+			status: string;
+			note?: string;
+		};
+		TaskDataResponse: {
+			/** @description Version of this schema. It is incremented only for changes that break older runners; runners should refuse versions newer than they support. */
+			version: number;
+			taskID: string;
+			description: string;
+			context: string;
+			sourceURL?: string;
+			sourceType?: string;
+			repo: components["schemas"]["RepoRequest"];
+			/** @description Time budget of the run, as a Go duration (e.g. "30m0s"). */
+			timeout: string;
+			/**
+			 * Format: date-time
+			 * @description When the run started. Absent until the operator has recorded the start.
+			 */
+			startedAt?: string;
+			/**
+			 * Format: date-time
+			 * @description When the run times out (startedAt plus timeout).
+			 */
+			deadline?: string;
+			/**
+			 * @description How much of the agent's activity the runner may send as events:
+			 *     summaries-only drops tool inputs and outputs, minimal sends no
+			 *     events. Absent means all events are sent. Responses that set it
+			 *     are version 2.
+			 * @enum {string}
+			 */
+			eventPrivacy?: "summaries-only" | "minimal";
+			/**
+			 * @description What the task produces, from the task's shepherd.io/mode label:
+			 *     plan writes an implementation plan and review reports findings
+			 *     on the code, both without changing code. Absent means the task
+			 *     changes code. Responses that set it are version 3.
+			 * @enum {string}
+			 */
+			mode?: "plan" | "review";
+			/** @description Model the agent works with, from the task's runner.model. Absent means the runner's default. */
+			model?: string;
+			/** @description Branch a task that opens a PR pushes to: the task's runner.branchPrefix, or "shepherd/", followed by its ID. Absent for tasks of an existing PR, which push to repo.ref. */
+			branch?: string;
+		};
+		TokenResponse: {
+			token: string;
 			/** Format: date-time */
 			expiresAt: string;
 		};
@@ -237,130 +773,1134 @@ export interface components {
 				[key: string]: unknown;
 			};
 			output?: components["schemas"]["TaskEventOutput"];
+			/** @description Free-form event details. The API server sets `correlationID` to the task's correlation ID on every event it accepts. */
 			metadata?: {
 				[key: string]: unknown;
 			};
 		};
-		TaskEventOutput: {
-			success: boolean;
-			summary?: string;
-		};
-		ErrorResponse: {
-			error: string;
-			details?: string;
-		};
-	};
-	responses: never;
-	parameters: {
-		taskID: string;
+		TaskEventOutput: {
+			success: boolean;
+			summary?: string;
+		};
+		EventSchemaResponse: {
+			/** @description Version the API translates events to */
+			current: number;
+			schemas: components["schemas"]["EventSchema"][];
+		};
+		EventSchema: {
+			version: number;
+			/** @description Accepted values of TaskEvent.type */
+			types: string[];
+			/** @description Fields every event must set */
+			required: string[];
+		};
+		CreateTaskTemplateRequest: {
+			/** @description Kubernetes object name of the template */
+			name: string;
+			description?: string;
+			runner: components["schemas"]["RunnerConfig"];
+			/** @description Labels added to every task created from the template */
+			labels?: {
+				[key: string]: string;
+			};
+			/** @description Prepended to the context of every task created from the template */
+			context?: string;
+			/** Format: int32 */
+			priority?: number;
+		};
+		TaskTemplateResponse: {
+			name: string;
+			namespace: string;
+			description?: string;
+			runner: components["schemas"]["RunnerConfig"];
+			labels?: {
+				[key: string]: string;
+			};
+			context?: string;
+			/** Format: int32 */
+			priority?: number;
+			/** Format: date-time */
+			createdAt: string;
+		};
+		FleetResponse: {
+			id: string;
+			namespace: string;
+			description: string;
+			/** @description GitHub search query the repositories were resolved from, if any */
+			repoQuery?: string;
+			status: components["schemas"]["FleetStatusSummary"];
+			tasks: components["schemas"]["FleetTaskResponse"][];
+			/** Format: date-time */
+			createdAt: string;
+		};
+		FleetStatusSummary: {
+			/** @description Running until every task finished, then Succeeded or Failed */
+			phase: string;
+			message: string;
+			/** Format: int32 */
+			total: number;
+			/** Format: int32 */
+			pending: number;
+			/** Format: int32 */
+			running: number;
+			/** Format: int32 */
+			succeeded: number;
+			/**
+			 * Format: int32
+			 * @description Tasks that failed, timed out or were cancelled
+			 */
+			failed: number;
+		};
+		FleetTaskResponse: {
+			id: string;
+			repoURL: string;
+			phase: string;
+			prURL?: string;
+			error?: string;
+		};
+		ArchivedTask: {
+			/** Format: date-time */
+			archivedAt: string;
+			task: components["schemas"]["TaskResponse"];
+			/** @description Agent activity events still buffered when the task finished */
+			events: components["schemas"]["TaskEvent"][];
+			/** @description The AgentTask resource with its spec and status */
+			object: {
+				[key: string]: unknown;
+			};
+		};
+		ReadinessResponse: {
+			/** @enum {string} */
+			status: "ok" | "degraded" | "unavailable";
+			checks: {
+				[key: string]: components["schemas"]["ReadinessCheck"];
+			};
+		};
+		ReadinessCheck: {
+			/** @enum {string} */
+			status: "ok" | "failing";
+			/** @description Whether a failure makes the server unready */
+			critical: boolean;
+			error?: string;
+			/** Format: date-time */
+			checkedAt: string;
+		};
+		CallbackHistoryResponse: {
+			/**
+			 * @description Reason of the task's Notified condition; absent before the first attempt.
+			 * @enum {string}
+			 */
+			status?: "CallbackPending" | "CallbackSent" | "CallbackFailed";
+			message?: string;
+			/** @description Failed attempts counted for the automatic retries. */
+			failedAttempts: number;
+			attempts: components["schemas"]["CallbackAttemptResponse"][];
+		};
+		CallbackAttemptResponse: {
+			/** Format: date-time */
+			time: string;
+			event: string;
+			/** @description HTTP status the adapter answered with; absent when it did not answer. */
+			statusCode?: number;
+			/** @description Why the attempt failed; absent when it succeeded. */
+			error?: string;
+			/** @description Whether the attempt was requested through the replay endpoint. */
+			replay?: boolean;
+		};
+		MaintenanceResponse: {
+			enabled: boolean;
+			/** @description Why Shepherd is under maintenance. */
+			message?: string;
+			/**
+			 * Format: date-time
+			 * @description When maintenance is expected to end.
+			 */
+			until?: string;
+		};
+		CallbackSigningKeyResponse: {
+			/** @enum {string} */
+			algorithm: "ed25519";
+			/** @description Public key in PKIX PEM form. Callbacks carry an X-Shepherd-Signature header of "ed25519=" followed by the base64-encoded signature of the request body. */
+			publicKey: string;
+		};
+		SetMaintenanceRequest: {
+			/** @description Shown to users whose tasks are rejected; a default is used if empty. */
+			message?: string;
+			/**
+			 * Format: date-time
+			 * @description When maintenance is expected to end. Informational only; maintenance lasts until it is ended.
+			 */
+			until?: string;
+		};
+		CancelTaskRequest: {
+			/** @description Appended to the task's failure message. */
+			reason?: string;
+		};
+		CancelTasksRequest: {
+			/** @description Appended to the failure message of the cancelled tasks. */
+			reason?: string;
+		};
+		BulkTaskResponse: {
+			dryRun?: boolean;
+			/** @description Number of tasks the request applied to. */
+			matched: number;
+			/** @description IDs of the tasks acted on, or with dryRun that would be. */
+			tasks: string[];
+			errors?: components["schemas"]["BulkTaskError"][];
+		};
+		BulkTaskError: {
+			taskID: string;
+			error: string;
+		};
+		ErrorResponse: {
+			error: string;
+			details?: string;
+		};
+	};
+	responses: never;
+	parameters: {
+		taskID: string;
+		/** @description If "true", read from the Kubernetes API instead of the API server's
+		 *     informer cache, which may lag a moment behind.
+		 *      */
+		consistent: "true" | "false";
+		fleetID: string;
+		templateName: string;
+		/** @description Kubernetes label selector of the tasks, e.g. shepherd.io/repo=acme-app. Use shepherd.io/repo to match every task. */
+		selector: string;
+		/** @description Only report the tasks the request applies to */
+		dryRun: boolean;
+	};
+	requestBodies: never;
+	headers: never;
+	pathItems: never;
+}
+export type $defs = Record<string, never>;
+export interface operations {
+	healthz: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Healthy */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"text/plain": string;
+				};
+			};
+		};
+	};
+	readyz: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Ready */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ReadinessResponse"];
+				};
+			};
+			/** @description A critical dependency is failing */
+			503: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ReadinessResponse"];
+				};
+			};
+		};
+	};
+	listTasks: {
+		parameters: {
+			query?: {
+				/** @description Filter by shepherd.io/repo label. Accepts the label value
+				 *     (org-repo), org/repo, the repository URL or SSH remote, or a link
+				 *     to a page of the repository.
+				 *      */
+				repo?: string;
+				/** @description Filter by shepherd.io/issue label. Accepts the issue number,
+				 *     #number, a Linear identifier, or a link to the issue or pull
+				 *     request, which also filters by its repository.
+				 *      */
+				issue?: string;
+				/** @description Filter by shepherd.io/fleet label */
+				fleet?: string;
+				/** @description If "true", only return non-terminal tasks */
+				active?: "true" | "false";
+				/** @description Only return tasks in the given phases. Accepts a comma-separated
+				 *     list (e.g. "Failed,TimedOut") and may be repeated. Valid phases:
+				 *     Pending, Provisioning, Running, Succeeded, Failed, TimedOut,
+				 *     Cancelled.
+				 *      */
+				phase?: string;
+				/** @description If "true", read from the Kubernetes API instead of the API server's
+				 *     informer cache, which may lag a moment behind.
+				 *      */
+				consistent?: components["parameters"]["consistent"];
+				/** @description Sort tasks by the given field. Ties are broken by task ID. When
+				 *     sorting by completionTime, tasks that have not completed are
+				 *     always listed last. Without this parameter the order is
+				 *     unspecified.
+				 *      */
+				sort?: "createdAt" | "completionTime" | "phase";
+				/** @description Sort direction, only used together with sort. */
+				order?: "asc" | "desc";
+			};
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description List of tasks */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskResponse"][];
+				};
+			};
+			/** @description Invalid filter or sort parameters */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	createTask: {
+		parameters: {
+			query?: never;
+			header?: {
+				/** @description Correlation ID to log the task's lines with in every component, e.g. the ID of the webhook delivery that triggered it. 1-64 letters, digits, '.', '_' or '-'; generated if omitted. */
+				"X-Correlation-ID"?: string;
+			};
+			path?: never;
+			cookie?: never;
+		};
+		requestBody: {
+			content: {
+				"application/json": components["schemas"]["CreateTaskRequest"];
+			};
+		};
+		responses: {
+			/** @description Task created */
+			201: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskResponse"];
+				};
+			};
+			/** @description Invalid request */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Denied by a task admission policy */
+			403: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Compressed context exceeds size limit */
+			413: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Content-Type must be application/json */
+			415: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Repository is larger than the sandbox template can hold */
+			422: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Active task quota for the repository, organization or namespace exceeded */
+			429: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Shepherd is under maintenance and not accepting new tasks. The error details carry the maintenance message. */
+			503: {
+				headers: {
+					/** @description Set to true when the task was rejected because of maintenance */
+					"X-Shepherd-Maintenance"?: string;
+					/** @description When maintenance is expected to end, if announced */
+					"Retry-After"?: string;
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	searchTasks: {
+		parameters: {
+			query: {
+				/** @description Search terms */
+				q: string;
+				/** @description Maximum number of results */
+				limit?: number;
+			};
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Matching tasks */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskResponse"][];
+				};
+			};
+			/** @description Missing query or invalid limit */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	getActiveTask: {
+		parameters: {
+			query: {
+				/** @description Source URL the task was created for */
+				sourceURL: string;
+			};
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description The active task */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskResponse"];
+				};
+			};
+			/** @description Missing sourceURL */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description No active task for the source */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	watchTasks: {
+		parameters: {
+			query?: {
+				/** @description Filter by shepherd.io/repo label. Accepts the label value
+				 *     (org-repo), org/repo, the repository URL or SSH remote, or a link
+				 *     to a page of the repository.
+				 *      */
+				repo?: string;
+				/** @description Filter by shepherd.io/issue label. Accepts the issue number,
+				 *     #number, a Linear identifier, or a link to the issue or pull
+				 *     request, which also filters by its repository.
+				 *      */
+				issue?: string;
+				/** @description Filter by shepherd.io/fleet label */
+				fleet?: string;
+			};
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description WebSocket upgrade */
+			101: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content?: never;
+			};
+			/** @description Invalid filter */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	getTask: {
+		parameters: {
+			query?: {
+				/** @description If "true", read from the Kubernetes API instead of the API server's
+				 *     informer cache, which may lag a moment behind.
+				 *      */
+				consistent?: components["parameters"]["consistent"];
+			};
+			header?: never;
+			path: {
+				taskID: components["parameters"]["taskID"];
+			};
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Task details */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskResponse"];
+				};
+			};
+			/** @description Task not found */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	addTaskNote: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path: {
+				taskID: components["parameters"]["taskID"];
+			};
+			cookie?: never;
+		};
+		requestBody: {
+			content: {
+				"application/json": components["schemas"]["CreateNoteRequest"];
+			};
+		};
+		responses: {
+			/** @description Note added */
+			201: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["NoteResponse"];
+				};
+			};
+			/** @description Missing or too long text or author */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Task not found */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description The task already has the maximum of 100 notes */
+			409: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	extendTaskTimeout: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path: {
+				taskID: components["parameters"]["taskID"];
+			};
+			cookie?: never;
+		};
+		requestBody: {
+			content: {
+				"application/json": components["schemas"]["ExtendTimeoutRequest"];
+			};
+		};
+		responses: {
+			/** @description Timeout extended */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskResponse"];
+				};
+			};
+			/** @description Invalid duration or too long reason */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Timeout extensions are disabled */
+			403: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Task not found */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description The task already has the maximum of 20 extensions */
+			409: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Task has already finished */
+			410: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description The extensions would exceed the operator's limit */
+			422: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	cancelTask: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path: {
+				taskID: components["parameters"]["taskID"];
+			};
+			cookie?: never;
+		};
+		requestBody?: {
+			content: {
+				"application/json": components["schemas"]["CancelTaskRequest"];
+			};
+		};
+		responses: {
+			/** @description Task cancelled */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskResponse"];
+				};
+			};
+			/** @description Invalid request body or too long reason */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Task not found */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Task has already finished */
+			410: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	retryTask: {
+		parameters: {
+			query?: never;
+			header?: {
+				/** @description Correlation ID to log the retry's lines with in every component. 1-64 letters, digits, '.', '_' or '-'; generated if omitted. */
+				"X-Correlation-ID"?: string;
+			};
+			path: {
+				taskID: components["parameters"]["taskID"];
+			};
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Retry created */
+			201: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskResponse"];
+				};
+			};
+			/** @description Invalid correlation ID */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Denied by a task admission policy */
+			403: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Task not found */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description The task has not finished, or it succeeded */
+			409: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Repository is larger than the sandbox template can hold */
+			422: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Active task quota for the repository, organization or namespace exceeded */
+			429: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Shepherd is under maintenance and not accepting new tasks. The error details carry the maintenance message. */
+			503: {
+				headers: {
+					/** @description Set to true when the task was rejected because of maintenance */
+					"X-Shepherd-Maintenance"?: string;
+					/** @description When maintenance is expected to end, if announced */
+					"Retry-After"?: string;
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	getTaskCallbacks: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path: {
+				taskID: components["parameters"]["taskID"];
+			};
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Callback history */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["CallbackHistoryResponse"];
+				};
+			};
+			/** @description Task not found */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	replayTaskCallback: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path: {
+				taskID: components["parameters"]["taskID"];
+			};
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Callback delivered */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["CallbackAttemptResponse"];
+				};
+			};
+			/** @description Task not found */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description The task has not finished */
+			409: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description The adapter could not be reached or answered with an error */
+			502: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
 	};
-	requestBodies: never;
-	headers: never;
-	pathItems: never;
-}
-export type $defs = Record<string, never>;
-export interface operations {
-	healthz: {
+	getFleet: {
 		parameters: {
 			query?: never;
 			header?: never;
-			path?: never;
+			path: {
+				fleetID: components["parameters"]["fleetID"];
+			};
 			cookie?: never;
 		};
 		requestBody?: never;
 		responses: {
-			/** @description Healthy */
+			/** @description Fleet details */
 			200: {
 				headers: {
 					[name: string]: unknown;
 				};
 				content: {
-					"text/plain": string;
+					"application/json": components["schemas"]["FleetResponse"];
+				};
+			};
+			/** @description Fleet not found */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
 				};
 			};
 		};
 	};
-	readyz: {
+	getArchivedTask: {
 		parameters: {
 			query?: never;
 			header?: never;
-			path?: never;
+			path: {
+				taskID: components["parameters"]["taskID"];
+			};
 			cookie?: never;
 		};
 		requestBody?: never;
 		responses: {
-			/** @description Ready */
+			/** @description Archived task */
 			200: {
 				headers: {
 					[name: string]: unknown;
 				};
 				content: {
-					"text/plain": string;
+					"application/json": components["schemas"]["ArchivedTask"];
+				};
+			};
+			/** @description Task not archived */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Archive could not be read */
+			502: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
 				};
 			};
-			/** @description Not ready */
+			/** @description Archive not configured */
 			503: {
 				headers: {
 					[name: string]: unknown;
 				};
 				content: {
-					"text/plain": string;
+					"application/json": components["schemas"]["ErrorResponse"];
 				};
 			};
 		};
 	};
-	listTasks: {
+	createTaskTemplate: {
 		parameters: {
-			query?: {
-				/** @description Filter by shepherd.io/repo label */
-				repo?: string;
-				/** @description Filter by shepherd.io/issue label */
-				issue?: string;
-				/** @description Filter by shepherd.io/fleet label */
-				fleet?: string;
-				/** @description If "true", only return non-terminal tasks */
-				active?: "true" | "false";
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody: {
+			content: {
+				"application/json": components["schemas"]["CreateTaskTemplateRequest"];
+			};
+		};
+		responses: {
+			/** @description Task template created */
+			201: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskTemplateResponse"];
+				};
+			};
+			/** @description Invalid request */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
 			};
+			/** @description A task template with this name already exists */
+			409: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+			/** @description Context exceeds size limit */
+			413: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	listTaskTemplates: {
+		parameters: {
+			query?: never;
 			header?: never;
 			path?: never;
 			cookie?: never;
 		};
 		requestBody?: never;
 		responses: {
-			/** @description List of tasks */
+			/** @description Task templates, ordered by name */
 			200: {
 				headers: {
 					[name: string]: unknown;
 				};
 				content: {
-					"application/json": components["schemas"]["TaskResponse"][];
+					"application/json": components["schemas"]["TaskTemplateResponse"][];
 				};
 			};
 		};
 	};
-	createTask: {
+	getTaskTemplate: {
 		parameters: {
 			query?: never;
 			header?: never;
+			path: {
+				templateName: components["parameters"]["templateName"];
+			};
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Task template details */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["TaskTemplateResponse"];
+				};
+			};
+			/** @description Task template not found */
+			404: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	cancelTasks: {
+		parameters: {
+			query: {
+				/** @description Kubernetes label selector of the tasks, e.g. shepherd.io/repo=acme-app. Use shepherd.io/repo to match every task. */
+				selector: components["parameters"]["selector"];
+				/** @description Only report the tasks the request applies to */
+				dryRun?: components["parameters"]["dryRun"];
+			};
+			header?: never;
 			path?: never;
 			cookie?: never;
 		};
-		requestBody: {
+		requestBody?: {
 			content: {
-				"application/json": components["schemas"]["CreateTaskRequest"];
+				"application/json": components["schemas"]["CancelTasksRequest"];
 			};
 		};
 		responses: {
-			/** @description Task created */
-			201: {
+			/** @description Tasks cancelled, or with dryRun the tasks that would be */
+			200: {
 				headers: {
 					[name: string]: unknown;
 				};
 				content: {
-					"application/json": components["schemas"]["TaskResponse"];
+					"application/json": components["schemas"]["BulkTaskResponse"];
 				};
 			};
-			/** @description Invalid request */
+			/** @description Missing or invalid selector, or invalid request body */
 			400: {
 				headers: {
 					[name: string]: unknown;
@@ -369,17 +1909,35 @@ export interface operations {
 					"application/json": components["schemas"]["ErrorResponse"];
 				};
 			};
-			/** @description Compressed context exceeds size limit */
-			413: {
+		};
+	};
+	deleteTasks: {
+		parameters: {
+			query: {
+				/** @description Kubernetes label selector of the tasks, e.g. shepherd.io/repo=acme-app. Use shepherd.io/repo to match every task. */
+				selector: components["parameters"]["selector"];
+				/** @description Only delete tasks that finished at least this long ago, e.g. 24h */
+				olderThan?: string;
+				/** @description Only report the tasks the request applies to */
+				dryRun?: components["parameters"]["dryRun"];
+			};
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Tasks deleted, or with dryRun the tasks that would be */
+			200: {
 				headers: {
 					[name: string]: unknown;
 				};
 				content: {
-					"application/json": components["schemas"]["ErrorResponse"];
+					"application/json": components["schemas"]["BulkTaskResponse"];
 				};
 			};
-			/** @description Content-Type must be application/json */
-			415: {
+			/** @description Missing or invalid selector, or invalid olderThan */
+			400: {
 				headers: {
 					[name: string]: unknown;
 				};
@@ -389,27 +1947,45 @@ export interface operations {
 			};
 		};
 	};
-	getTask: {
+	getMaintenance: {
 		parameters: {
 			query?: never;
 			header?: never;
-			path: {
-				taskID: components["parameters"]["taskID"];
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Current maintenance state */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["MaintenanceResponse"];
+				};
 			};
+		};
+	};
+	getCallbackSigningKey: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
 			cookie?: never;
 		};
 		requestBody?: never;
 		responses: {
-			/** @description Task details */
+			/** @description Public key of callback signatures */
 			200: {
 				headers: {
 					[name: string]: unknown;
 				};
 				content: {
-					"application/json": components["schemas"]["TaskResponse"];
+					"application/json": components["schemas"]["CallbackSigningKeyResponse"];
 				};
 			};
-			/** @description Task not found */
+			/** @description Callbacks are not signed with a key */
 			404: {
 				headers: {
 					[name: string]: unknown;
@@ -420,6 +1996,59 @@ export interface operations {
 			};
 		};
 	};
+	setMaintenance: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody: {
+			content: {
+				"application/json": components["schemas"]["SetMaintenanceRequest"];
+			};
+		};
+		responses: {
+			/** @description Maintenance started */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["MaintenanceResponse"];
+				};
+			};
+			/** @description Invalid message or until */
+			400: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["ErrorResponse"];
+				};
+			};
+		};
+	};
+	endMaintenance: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Maintenance ended */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["MaintenanceResponse"];
+				};
+			};
+		};
+	};
 	streamEvents: {
 		parameters: {
 			query?: {
@@ -464,7 +2093,10 @@ export interface operations {
 	postEvents: {
 		parameters: {
 			query?: never;
-			header?: never;
+			header?: {
+				/** @description TaskEvent schema version of the events; 1 if omitted. */
+				"X-Shepherd-Event-Schema"?: number;
+			};
 			path: {
 				taskID: components["parameters"]["taskID"];
 			};
@@ -479,6 +2111,8 @@ export interface operations {
 			/** @description Events accepted */
 			200: {
 				headers: {
+					/** @description TaskEvent schema version the events were translated to */
+					"X-Shepherd-Event-Schema"?: number;
 					[name: string]: unknown;
 				};
 				content: {
@@ -683,4 +2317,24 @@ export interface operations {
 			};
 		};
 	};
+	getEventSchemas: {
+		parameters: {
+			query?: never;
+			header?: never;
+			path?: never;
+			cookie?: never;
+		};
+		requestBody?: never;
+		responses: {
+			/** @description Event schema versions, oldest first */
+			200: {
+				headers: {
+					[name: string]: unknown;
+				};
+				content: {
+					"application/json": components["schemas"]["EventSchemaResponse"];
+				};
+			};
+		};
+	};
 }
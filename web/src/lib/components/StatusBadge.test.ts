@@ -5,6 +5,7 @@ import StatusBadge from "./StatusBadge.svelte";
 describe("StatusBadge", () => {
 	it.each([
 		["Pending", "Pending", "text-attention-fg"],
+		["Provisioning", "Provisioning", "text-attention-fg"],
 		["Running", "Running", "text-info-fg"],
 		["Succeeded", "Succeeded", "text-success-fg"],
 		["Failed", "Failed", "text-danger-fg"],
@@ -20,6 +20,9 @@ function makeTask(overrides: Partial<TaskResponse> = {}): TaskResponse {
 		callbackURL: "https://example.com/callback",
 		status: { phase: "Pending", message: "" },
 		createdAt: "2026-01-15T10:00:00Z",
+		queuedSeconds: 0,
+		runningSeconds: 0,
+		totalSeconds: 0,
 		...overrides,
 	};
 }
@@ -278,7 +281,7 @@ describe("computeStats", () => {
 	it("includes unknown phases in total but not in any category", () => {
 		const tasks: TaskResponse[] = [
 			makeTask({ status: { phase: "Running", message: "" } }),
-			makeTask({ status: { phase: "SomethingElse", message: "" } }),
+			makeTask({ status: { phase: "SomethingElse" as TaskResponse["status"]["phase"], message: "" } }),
 		];
 
 		const stats = computeStats(tasks);
@@ -72,6 +72,11 @@ export function getStatusConfig(status: string): StatusConfig {
 				color: "text-attention-fg bg-attention-fg/10",
 				label: "Pending",
 			};
+		case "Provisioning":
+			return {
+				color: "text-attention-fg bg-attention-fg/10",
+				label: "Provisioning",
+			};
 		case "Running":
 			return { color: "text-info-fg bg-info-fg/10", label: "Running" };
 		case "Succeeded":
@@ -110,7 +115,7 @@ export function computeStats(tasks: TaskResponse[]): TaskStats {
 	for (const task of tasks) {
 		const phase = task.status.phase;
 		if (phase === "Running") active++;
-		else if (phase === "Pending") pending++;
+		else if (phase === "Pending" || phase === "Provisioning") pending++;
 		else if (phase === "Succeeded") succeeded++;
 		else if (phase === "Failed" || phase === "TimedOut") failed++;
 	}
@@ -4,6 +4,7 @@ type TaskEvent = components["schemas"]["TaskEvent"];
 
 export type WSMessage =
 	| { type: "task_event"; data: TaskEvent }
+	| { type: "events_synced" }
 	| { type: "task_complete"; data: TaskCompleteData };
 
 export interface TaskCompleteData {
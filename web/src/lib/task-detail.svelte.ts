@@ -7,6 +7,7 @@ export class TaskDetailStore {
 	task: TaskResponse | null = $state(null);
 	loading = $state(false);
 	error: string | null = $state(null);
+	noteError: string | null = $state(null);
 	private controller: AbortController | null = null;
 
 	async load(taskId: string): Promise<void> {
@@ -34,4 +35,29 @@ export class TaskDetailStore {
 			}
 		}
 	}
+
+	/** Attach a note to the task and show it without reloading the task. */
+	async addNote(taskId: string, author: string, text: string): Promise<boolean> {
+		this.noteError = null;
+		try {
+			const { data, error, response } = await api.POST(
+				"/api/v1/tasks/{taskID}/notes",
+				{
+					params: { path: { taskID: taskId } },
+					body: { author: author || undefined, text },
+				},
+			);
+			if (!response.ok || !data) {
+				this.noteError = error?.error ?? `Failed to add note (${response.status})`;
+				return false;
+			}
+			if (this.task?.id === taskId) {
+				this.task.notes = [...(this.task.notes ?? []), data];
+			}
+			return true;
+		} catch (e) {
+			this.noteError = e instanceof Error ? e.message : "Network error";
+			return false;
+		}
+	}
 }
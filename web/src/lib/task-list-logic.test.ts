@@ -0,0 +1,64 @@
+import { describe, expect, it } from "vitest";
+import type { components } from "./api.js";
+import { applyTaskChange } from "./task-list-logic.js";
+
+type TaskResponse = components["schemas"]["TaskResponse"];
+type TaskPhase = TaskResponse["status"]["phase"];
+
+function makeTask(id: string, phase: TaskPhase = "Running"): TaskResponse {
+	return {
+		id,
+		namespace: "default",
+		repo: { url: "https://github.com/org/repo" },
+		task: { description: "Fix the widget" },
+		callbackURL: "https://example.com/callback",
+		status: { phase, message: "" },
+		createdAt: "2026-01-15T10:00:00Z",
+		queuedSeconds: 0,
+		runningSeconds: 0,
+		totalSeconds: 0,
+	};
+}
+
+describe("applyTaskChange", () => {
+	it("appends added tasks", () => {
+		const tasks = applyTaskChange([makeTask("a")], { type: "task_added", data: makeTask("b") }, false);
+		expect(tasks.map((t) => t.id)).toEqual(["a", "b"]);
+	});
+
+	it("replaces updated tasks in place", () => {
+		const tasks = applyTaskChange(
+			[makeTask("a"), makeTask("b")],
+			{ type: "task_updated", data: makeTask("a", "Succeeded") },
+			false,
+		);
+		expect(tasks.map((t) => t.id)).toEqual(["a", "b"]);
+		expect(tasks[0].status.phase).toBe("Succeeded");
+	});
+
+	it("treats a repeated add as an update", () => {
+		const tasks = applyTaskChange([makeTask("a")], { type: "task_added", data: makeTask("a", "Failed") }, false);
+		expect(tasks).toHaveLength(1);
+		expect(tasks[0].status.phase).toBe("Failed");
+	});
+
+	it("removes deleted tasks", () => {
+		const tasks = applyTaskChange(
+			[makeTask("a"), makeTask("b")],
+			{ type: "task_deleted", data: makeTask("a") },
+			false,
+		);
+		expect(tasks.map((t) => t.id)).toEqual(["b"]);
+	});
+
+	it("removes finished tasks when only active tasks are shown", () => {
+		const tasks = applyTaskChange([makeTask("a")], { type: "task_updated", data: makeTask("a", "TimedOut") }, true);
+		expect(tasks).toEqual([]);
+	});
+
+	it("ignores finished tasks it does not show", () => {
+		const before = [makeTask("a")];
+		const tasks = applyTaskChange(before, { type: "task_added", data: makeTask("b", "Succeeded") }, true);
+		expect(tasks).toBe(before);
+	});
+});
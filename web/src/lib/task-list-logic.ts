@@ -0,0 +1,40 @@
+import type { components } from "./api.js";
+
+type TaskResponse = components["schemas"]["TaskResponse"];
+
+export type TaskListMessage =
+	| {
+			type: "task_added" | "task_updated" | "task_deleted";
+			data: TaskResponse;
+	  }
+	| { type: "tasks_synced" };
+
+const TERMINAL_PHASES = new Set(["Succeeded", "Failed", "TimedOut", "Cancelled"]);
+
+/**
+ * Apply a change from the task list watch and return the new list.
+ *
+ * Updated tasks keep their position and new tasks are appended. The watch
+ * does not filter on phase, so with `activeOnly` finished tasks are removed
+ * here, as the list endpoint's `active=true` filter would.
+ */
+export function applyTaskChange(
+	tasks: TaskResponse[],
+	msg: Exclude<TaskListMessage, { type: "tasks_synced" }>,
+	activeOnly: boolean,
+): TaskResponse[] {
+	const task = msg.data;
+	const remove =
+		msg.type === "task_deleted" ||
+		(activeOnly && TERMINAL_PHASES.has(task.status.phase));
+	const index = tasks.findIndex((t) => t.id === task.id);
+	if (remove) {
+		return index === -1 ? tasks : tasks.filter((t) => t.id !== task.id);
+	}
+	if (index === -1) {
+		return [...tasks, task];
+	}
+	const next = tasks.slice();
+	next[index] = task;
+	return next;
+}
@@ -4,7 +4,7 @@ import {
 	type TaskCompleteData,
 	type WSMessage,
 } from "./stream-logic.js";
-import { type ConnectionState, WSClient } from "./ws.js";
+import { apiWSUrl, type ConnectionState, WSClient } from "./ws.js";
 
 type TaskEvent = components["schemas"]["TaskEvent"];
 
@@ -41,11 +41,7 @@ export class TaskStream {
 		this.completionData = null;
 		this.gapReconnectCount = 0;
 
-		const wsProtocol = window.location.protocol === "https:" ? "wss:" : "ws:";
-		const wsHost = import.meta.env.VITE_API_URL
-			? new URL(import.meta.env.VITE_API_URL as string).host
-			: window.location.host;
-		const url = `${wsProtocol}//${wsHost}/api/v1/tasks/${taskId}/events`;
+		const url = apiWSUrl(`/api/v1/tasks/${taskId}/events`);
 
 		this.client = new WSClient<WSMessage>({
 			url,
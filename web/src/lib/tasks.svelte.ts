@@ -1,5 +1,7 @@
 import type { components } from "./api.js";
 import { api } from "./client.js";
+import { applyTaskChange, type TaskListMessage } from "./task-list-logic.js";
+import { apiWSUrl, type ConnectionState, WSClient } from "./ws.js";
 
 type TaskResponse = components["schemas"]["TaskResponse"];
 
@@ -13,7 +15,12 @@ export class TasksStore {
 	data: TaskResponse[] = $state([]);
 	loading = $state(false);
 	error: string | null = $state(null);
+	/** State of the live task list watch started by watch(). */
+	connectionState: ConnectionState = $state("disconnected");
 	private controller: AbortController | null = null;
+	private watchClient: WSClient<TaskListMessage> | null = null;
+	/** Tasks received before the watch's tasks_synced message, or null once synced. */
+	private syncing: TaskResponse[] | null = null;
 
 	async load(params?: TaskFilters): Promise<void> {
 		this.controller?.abort();
@@ -40,4 +47,51 @@ export class TasksStore {
 			}
 		}
 	}
+
+	/**
+	 * Keep data up to date over the task list watch. Each (re)connect
+	 * replaces the list once the server has sent the current tasks.
+	 */
+	watch(params?: TaskFilters): void {
+		this.unwatch();
+		const activeOnly = params?.active === "true";
+		const query = new URLSearchParams();
+		if (params?.repo) query.set("repo", params.repo);
+		if (params?.fleet) query.set("fleet", params.fleet);
+		const qs = query.toString();
+
+		this.watchClient = new WSClient<TaskListMessage>({
+			url: apiWSUrl(`/api/v1/tasks/watch${qs ? `?${qs}` : ""}`),
+			onMessage: (msg) => this.handleWatchMessage(msg, activeOnly),
+			onStateChange: (state) => {
+				this.connectionState = state;
+				if (state === "connecting" || state === "reconnecting") {
+					this.syncing = [];
+				}
+			},
+		});
+		this.watchClient.connect();
+	}
+
+	unwatch(): void {
+		this.watchClient?.disconnect();
+		this.watchClient = null;
+		this.syncing = null;
+	}
+
+	private handleWatchMessage(msg: TaskListMessage, activeOnly: boolean): void {
+		if (msg.type === "tasks_synced") {
+			if (this.syncing !== null) {
+				this.data = this.syncing;
+				this.syncing = null;
+				this.error = null;
+			}
+			return;
+		}
+		if (this.syncing !== null) {
+			this.syncing = applyTaskChange(this.syncing, msg, activeOnly);
+		} else {
+			this.data = applyTaskChange(this.data, msg, activeOnly);
+		}
+	}
 }
@@ -27,6 +27,17 @@ export function backoffDelay(attempt: number): number {
 	return Math.max(0, delay + jitter);
 }
 
+/**
+ * Build the WebSocket URL of an API path such as `/api/v1/tasks/watch`.
+ * Keeps the path of VITE_API_URL so an API served under a prefix works.
+ */
+export function apiWSUrl(path: string): string {
+	const wsProtocol = window.location.protocol === "https:" ? "wss:" : "ws:";
+	const apiURL = new URL((import.meta.env.VITE_API_URL as string) || "/", window.location.href);
+	const basePath = apiURL.pathname.replace(/\/+$/, "");
+	return `${wsProtocol}//${apiURL.host}${basePath}${path}`;
+}
+
 /**
  * Build a WebSocket URL with an optional `?after=N` parameter for reconnection.
  */